@@ -19,12 +19,14 @@ var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrInvalidToken       = errors.New("invalid token")
 	ErrTokenExpired       = errors.New("token expired")
+	ErrEmailNotVerified   = fmt.Errorf("email not verified: %w", ErrForbidden)
 
 	// Resource-specific errors
 	ErrUserNotFound    = fmt.Errorf("user %w", ErrNotFound)
 	ErrRequestNotFound = fmt.Errorf("request %w", ErrNotFound)
 	ErrFileNotFound    = fmt.Errorf("file %w", ErrNotFound)
 	ErrJobNotFound     = fmt.Errorf("job %w", ErrNotFound)
+	ErrBatchNotFound   = fmt.Errorf("batch %w", ErrNotFound)
 
 	// Validation errors
 	ErrValidation = errors.New("validation error")
@@ -32,7 +32,9 @@ type TokenPair struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
-func NewToken(secret, issuer, subject string, roles []string, ttl time.Duration, audiences ...string) (string, error) {
+// NewToken signs an access token for subject with the algorithm keys use.
+// Each token gets a random jti so it can be revoked on its own.
+func NewToken(keys *Keys, issuer, subject string, roles []string, ttl time.Duration, audiences ...string) (string, error) {
 	now := time.Now()
 	aud := audiences
 	if len(aud) == 0 {
@@ -47,10 +49,19 @@ func NewToken(secret, issuer, subject string, roles []string, ttl time.Duration,
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 			Audience:  aud,
+			ID:        uuid.NewString(),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, cl)
-	return token.SignedString([]byte(secret))
+	return keys.sign(cl)
+}
+
+// RevocationID returns the key under which an access token is blacklisted:
+// its jti, or the hash of the raw token for tokens issued without one.
+func RevocationID(token string, claims *Claims) string {
+	if claims != nil && claims.ID != "" {
+		return "jti:" + claims.ID
+	}
+	return HashToken(token)
 }
 
 func GenerateRefreshToken() (string, error) {
@@ -61,8 +72,8 @@ func GenerateRefreshToken() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-func NewTokenPair(secret, issuer string, userID uuid.UUID, roles []string, accessTTL, _ time.Duration) (*TokenPair, error) {
-	accessToken, err := NewToken(secret, issuer, userID.String(), roles, accessTTL)
+func NewTokenPair(keys *Keys, issuer string, userID uuid.UUID, roles []string, accessTTL, _ time.Duration) (*TokenPair, error) {
+	accessToken, err := NewToken(keys, issuer, userID.String(), roles, accessTTL)
 	if err != nil {
 		return nil, err
 	}
@@ -1,13 +1,24 @@
 package auth
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
 	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+
+	"github.com/fedutinova/smartheart/back-api/config"
 )
 
 func TestGenerateRefreshToken_IsHexAndLength(t *testing.T) {
@@ -36,7 +47,7 @@ func TestNewToken_ContainsClaims(t *testing.T) {
 	roles := []string{"user", "tester"}
 
 	ttl := 2 * time.Minute
-	tokenStr, err := NewToken(secret, issuer, subject, roles, ttl)
+	tokenStr, err := NewToken(NewHS256Keys(secret), issuer, subject, roles, ttl)
 	if err != nil {
 		t.Fatalf("NewToken error: %v", err)
 	}
@@ -81,7 +92,7 @@ func TestNewTokenPair_ReturnsAccessAndRefresh(t *testing.T) {
 	userID := uuid.New()
 	roles := []string{"user"}
 
-	pair, err := NewTokenPair(secret, issuer, userID, roles, 1*time.Minute, 7*24*time.Hour)
+	pair, err := NewTokenPair(NewHS256Keys(secret), issuer, userID, roles, 1*time.Minute, 7*24*time.Hour)
 	if err != nil {
 		t.Fatalf("NewTokenPair error: %v", err)
 	}
@@ -92,3 +103,172 @@ func TestNewTokenPair_ReturnsAccessAndRefresh(t *testing.T) {
 		t.Fatalf("expected refresh token length 64, got %d", len(pair.RefreshToken))
 	}
 }
+
+func writePEM(t *testing.T, name, typ string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadKeys_RS256(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+
+	keys, err := LoadKeys(config.JWTConfig{
+		Algorithm:      "RS256",
+		PrivateKeyFile: writePEM(t, "jwt.key", "PRIVATE KEY", privDER),
+		PublicKeyFile:  writePEM(t, "jwt.pub", "PUBLIC KEY", pubDER),
+	})
+	if err != nil {
+		t.Fatalf("LoadKeys error: %v", err)
+	}
+	if keys.Algorithm() != AlgRS256 {
+		t.Fatalf("expected RS256, got %s", keys.Algorithm())
+	}
+
+	tokenStr, err := NewToken(keys, "iss", uuid.New().String(), []string{"user"}, time.Minute)
+	if err != nil {
+		t.Fatalf("NewToken error: %v", err)
+	}
+	token, err := keys.parser().ParseWithClaims(tokenStr, &Claims{}, keys.verifyKey)
+	if err != nil {
+		t.Fatalf("ParseWithClaims error: %v", err)
+	}
+
+	jwks := keys.JWKS()
+	if len(jwks.Keys) != 1 {
+		t.Fatalf("expected one JWK, got %d", len(jwks.Keys))
+	}
+	if jwks.Keys[0].Kid != token.Header["kid"] {
+		t.Fatalf("expected kid %q in token header, got %v", jwks.Keys[0].Kid, token.Header["kid"])
+	}
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	otherDER, _ := x509.MarshalPKIXPublicKey(&other.PublicKey)
+	_, err = LoadKeys(config.JWTConfig{
+		Algorithm:      "RS256",
+		PrivateKeyFile: writePEM(t, "jwt.key", "PRIVATE KEY", privDER),
+		PublicKeyFile:  writePEM(t, "other.pub", "PUBLIC KEY", otherDER),
+	})
+	if err == nil {
+		t.Fatal("expected error for mismatched public key")
+	}
+}
+
+func TestJWTMiddleware_RejectsOtherAlgorithm(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	rsKeys := NewRS256Keys(private)
+	hsKeys := NewHS256Keys("test-secret")
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	mw := JWTMiddleware(rsKeys, "iss")(ok)
+
+	for _, tc := range []struct {
+		name string
+		keys *Keys
+		want int
+	}{
+		{"RS256 token", rsKeys, http.StatusOK},
+		{"HS256 token", hsKeys, http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenStr, err := NewToken(tc.keys, "iss", uuid.New().String(), []string{"user"}, time.Minute)
+			if err != nil {
+				t.Fatalf("NewToken error: %v", err)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			req.Header.Set("Authorization", "Bearer "+tokenStr)
+			w := httptest.NewRecorder()
+			mw.ServeHTTP(w, req)
+			if w.Code != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, w.Code)
+			}
+		})
+	}
+}
+
+func TestJWKS_EmptyForHS256(t *testing.T) {
+	if n := len(NewHS256Keys("test-secret").JWKS().Keys); n != 0 {
+		t.Fatalf("expected no published keys for HS256, got %d", n)
+	}
+}
+
+type blacklistSet map[string]bool
+
+func (b blacklistSet) IsTokenBlacklisted(_ context.Context, tokenID string) (bool, error) {
+	return b[tokenID], nil
+}
+
+func TestNewToken_SetsUniqueJTI(t *testing.T) {
+	keys := NewHS256Keys("test-secret")
+	seen := make(map[string]bool)
+	for range 3 {
+		tokenStr, err := NewToken(keys, "iss", uuid.New().String(), nil, time.Minute)
+		if err != nil {
+			t.Fatalf("NewToken error: %v", err)
+		}
+		claims := &Claims{}
+		if _, err := keys.parser().ParseWithClaims(tokenStr, claims, keys.verifyKey); err != nil {
+			t.Fatalf("ParseWithClaims error: %v", err)
+		}
+		if claims.ID == "" || seen[claims.ID] {
+			t.Fatalf("expected a fresh jti, got %q", claims.ID)
+		}
+		seen[claims.ID] = true
+	}
+}
+
+func TestJWTMiddleware_RejectsBlacklistedJTI(t *testing.T) {
+	keys := NewHS256Keys("test-secret")
+	revoked, err := NewToken(keys, "iss", uuid.New().String(), []string{"user"}, time.Minute)
+	if err != nil {
+		t.Fatalf("NewToken error: %v", err)
+	}
+	active, err := NewToken(keys, "iss", uuid.New().String(), []string{"user"}, time.Minute)
+	if err != nil {
+		t.Fatalf("NewToken error: %v", err)
+	}
+	claims := &Claims{}
+	if _, err := keys.parser().ParseWithClaims(revoked, claims, keys.verifyKey); err != nil {
+		t.Fatalf("ParseWithClaims error: %v", err)
+	}
+	bl := blacklistSet{RevocationID(revoked, claims): true}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	mw := JWTMiddleware(keys, "iss", WithBlacklist(bl))(ok)
+
+	for _, tc := range []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"revoked jti", revoked, http.StatusUnauthorized},
+		{"other token of same signer", active, http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			w := httptest.NewRecorder()
+			mw.ServeHTTP(w, req)
+			if w.Code != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, w.Code)
+			}
+		})
+	}
+}
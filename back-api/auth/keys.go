@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/fedutinova/smartheart/back-api/config"
+)
+
+// Signing algorithms accepted in JWT_ALGORITHM.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// Keys signs and verifies access tokens, either with an HS256 shared secret
+// or with an RS256 key pair whose public half is published as a JWKS so other
+// services can verify tokens without holding the secret.
+type Keys struct {
+	method  jwt.SigningMethod
+	secret  []byte
+	private *rsa.PrivateKey
+	public  *rsa.PublicKey
+	kid     string
+}
+
+// NewHS256Keys returns keys that sign and verify with a shared secret.
+func NewHS256Keys(secret string) *Keys {
+	return &Keys{method: jwt.SigningMethodHS256, secret: []byte(secret)}
+}
+
+// NewRS256Keys returns keys that sign with private and verify with its public
+// key. The key ID is the public key's RFC 7638 thumbprint.
+func NewRS256Keys(private *rsa.PrivateKey) *Keys {
+	return &Keys{
+		method:  jwt.SigningMethodRS256,
+		private: private,
+		public:  &private.PublicKey,
+		kid:     thumbprint(&private.PublicKey),
+	}
+}
+
+// LoadKeys builds the keys selected by cfg.Algorithm: the shared secret for
+// HS256 (the default), or the PEM key files for RS256. When a public key file
+// is also given it must match the private key.
+func LoadKeys(cfg config.JWTConfig) (*Keys, error) {
+	switch strings.ToUpper(cfg.Algorithm) {
+	case "", AlgHS256:
+		return NewHS256Keys(cfg.Secret), nil
+	case AlgRS256:
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", cfg.Algorithm)
+	}
+
+	data, err := os.ReadFile(cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read jwt private key: %w", err)
+	}
+	private, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse jwt private key: %w", err)
+	}
+	keys := NewRS256Keys(private)
+
+	if cfg.PublicKeyFile != "" {
+		data, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read jwt public key: %w", err)
+		}
+		public, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse jwt public key: %w", err)
+		}
+		if !public.Equal(&private.PublicKey) {
+			return nil, errors.New("jwt public key does not match private key")
+		}
+	}
+	return keys, nil
+}
+
+// Algorithm returns the JWT "alg" these keys sign with.
+func (k *Keys) Algorithm() string {
+	return k.method.Alg()
+}
+
+// sign returns the signed compact JWT for claims.
+func (k *Keys) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.private != nil {
+		token.Header["kid"] = k.kid
+		return token.SignedString(k.private)
+	}
+	return token.SignedString(k.secret)
+}
+
+// parser accepts only the algorithm these keys sign with, so an RS256
+// deployment never verifies an HS256 token against its public key.
+func (k *Keys) parser() *jwt.Parser {
+	return jwt.NewParser(jwt.WithValidMethods([]string{k.method.Alg()}))
+}
+
+func (k *Keys) verifyKey(_ *jwt.Token) (any, error) {
+	if k.public != nil {
+		return k.public, nil
+	}
+	return k.secret, nil
+}
+
+// JWK is a public key in JSON Web Key form (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public verification keys. It is empty for HS256, whose
+// secret must never be published.
+func (k *Keys) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	if k.public == nil {
+		return set
+	}
+	n, e := rsaComponents(k.public)
+	set.Keys = append(set.Keys, JWK{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: k.method.Alg(),
+		Kid: k.kid,
+		N:   n,
+		E:   e,
+	})
+	return set
+}
+
+// rsaComponents returns the base64url modulus and exponent of pub.
+func rsaComponents(pub *rsa.PublicKey) (n, e string) {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(pub.N.Bytes()), enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint of pub.
+func thumbprint(pub *rsa.PublicKey) string {
+	n, e := rsaComponents(pub)
+	// Members in lexicographic order, no whitespace, as RFC 7638 requires.
+	canonical, _ := json.Marshal(struct {
+		E   string `json:"e"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+	}{e, "RSA", n})
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
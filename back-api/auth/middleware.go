@@ -7,20 +7,26 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
-
-	"github.com/golang-jwt/jwt/v5"
 )
 
 // writeJSONError writes a JSON error response from middleware.
-// Duplicates the {"error":"..."} shape from handler.APIError because
-// the auth package cannot import handler (circular dependency).
-func writeJSONError(w http.ResponseWriter, code int, msg string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
+// Duplicates the {"error":{"code":"...","message":"..."}} shape from
+// handler.APIError because the auth package cannot import handler
+// (circular dependency).
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	code := "unauthorized"
+	if status == http.StatusForbidden {
+		code = "forbidden"
+	}
 	type errBody struct {
-		Error string `json:"error"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
 	}
-	json.NewEncoder(w).Encode(errBody{Error: msg}) //nolint:errcheck // response write error is unrecoverable
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct { //nolint:errcheck // response write error is unrecoverable
+		Error errBody `json:"error"`
+	}{errBody{Code: code, Message: msg}})
 }
 
 type ctxKey string
@@ -39,59 +45,83 @@ func NewContext(ctx context.Context, claims *Claims) context.Context {
 	return context.WithValue(ctx, ctxKeyClaims, claims)
 }
 
-// TokenBlacklistChecker checks whether a token hash has been blacklisted.
+// TokenBlacklistChecker checks whether a token has been blacklisted, by the
+// key RevocationID returns for it.
 type TokenBlacklistChecker interface {
-	IsTokenBlacklisted(ctx context.Context, tokenHash string) (bool, error)
+	IsTokenBlacklisted(ctx context.Context, tokenID string) (bool, error)
 }
 
-func JWTMiddleware(secret, issuer string, opts ...func(*jwtMWConfig)) func(http.Handler) http.Handler {
+// Authentication failures returned by Authenticate. Their messages are sent
+// to clients as-is.
+var (
+	ErrTokenInvalid  = errors.New("invalid token")
+	ErrTokenIssuer   = errors.New("invalid issuer")
+	ErrTokenNoUserID = errors.New("invalid token: missing user_id")
+	ErrTokenRevoked  = errors.New("token has been revoked")
+)
+
+// Authenticate verifies a bearer token signed by keys and issued by issuer
+// and returns its claims. The HTTP middleware and the gRPC interceptor share
+// it so both transports accept exactly the same tokens.
+func Authenticate(ctx context.Context, keys *Keys, issuer, tokenStr string, opts ...func(*jwtMWConfig)) (*Claims, error) {
 	cfg := jwtMWConfig{}
 	for _, o := range opts {
 		o(&cfg)
 	}
+
+	cl := &Claims{}
+	_, err := keys.parser().ParseWithClaims(tokenStr, cl, keys.verifyKey)
+	if err != nil {
+		slog.Warn("Jwt parse failed", "error", err)
+		return nil, ErrTokenInvalid
+	}
+	if cl.Issuer != issuer {
+		return nil, ErrTokenIssuer
+	}
+	if cl.UserID == "" {
+		return nil, ErrTokenNoUserID
+	}
+
+	// Check token blacklist (for logged-out tokens).
+	// Fail-open: if the blacklist store is unreachable we log the
+	// error but allow the request so that a Redis outage does not
+	// cause a full authentication outage.
+	if cfg.blacklist != nil {
+		blacklisted, err := cfg.blacklist.IsTokenBlacklisted(ctx, RevocationID(tokenStr, cl))
+		if err != nil {
+			slog.Error("Failed to check token blacklist, allowing request", "error", err)
+		} else if blacklisted {
+			return nil, ErrTokenRevoked
+		}
+	}
+	return cl, nil
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>" value.
+func BearerToken(header string) (string, bool) {
+	if !strings.HasPrefix(header, "Bearer ") {
+		return "", false
+	}
+	return strings.TrimPrefix(header, "Bearer "), true
+}
+
+// JWTMiddleware authenticates bearer tokens signed by keys and issued by
+// issuer, storing their claims in the request context.
+func JWTMiddleware(keys *Keys, issuer string, opts ...func(*jwtMWConfig)) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			raw := r.Header.Get("Authorization")
-			if raw == "" || !strings.HasPrefix(raw, "Bearer ") {
+			tokenStr, ok := BearerToken(r.Header.Get("Authorization"))
+			if !ok {
 				writeJSONError(w, http.StatusUnauthorized, "missing bearer token")
 				return
 			}
-			tokenStr := strings.TrimPrefix(raw, "Bearer ")
 
-			parser := jwt.NewParser(jwt.WithValidMethods([]string{"HS256"}))
-			cl := &Claims{}
-			_, err := parser.ParseWithClaims(tokenStr, cl, func(_ *jwt.Token) (any, error) {
-				return []byte(secret), nil
-			})
+			cl, err := Authenticate(r.Context(), keys, issuer, tokenStr, opts...)
 			if err != nil {
-				slog.Warn("Jwt parse failed", "error", err)
-				writeJSONError(w, http.StatusUnauthorized, "invalid token")
-				return
-			}
-			if cl.Issuer != issuer {
-				writeJSONError(w, http.StatusUnauthorized, "invalid issuer")
-				return
-			}
-			if cl.UserID == "" {
-				writeJSONError(w, http.StatusUnauthorized, "invalid token: missing user_id")
+				writeJSONError(w, http.StatusUnauthorized, err.Error())
 				return
 			}
 
-			// Check token blacklist (for logged-out tokens).
-			// Fail-open: if the blacklist store is unreachable we log the
-			// error but allow the request so that a Redis outage does not
-			// cause a full authentication outage.
-			if cfg.blacklist != nil {
-				tokenHash := HashToken(tokenStr)
-				blacklisted, err := cfg.blacklist.IsTokenBlacklisted(r.Context(), tokenHash)
-				if err != nil {
-					slog.Error("Failed to check token blacklist, allowing request", "error", err)
-				} else if blacklisted {
-					writeJSONError(w, http.StatusUnauthorized, "token has been revoked")
-					return
-				}
-			}
-
 			ctx := context.WithValue(r.Context(), ctxKeyClaims, cl)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -115,12 +145,7 @@ func RequirePerm(required string) func(http.Handler) http.Handler {
 				writeJSONError(w, http.StatusUnauthorized, "no auth context")
 				return
 			}
-			perms := PermsForRoles(cl.Roles)
-			if _, hasAdmin := perms[PermAdminAll]; hasAdmin {
-				next.ServeHTTP(w, r)
-				return
-			}
-			if _, hasPerm := perms[required]; !hasPerm {
+			if !HasPerm(cl, required) {
 				writeJSONError(w, http.StatusForbidden, "forbidden")
 				return
 			}
@@ -129,4 +154,14 @@ func RequirePerm(required string) func(http.Handler) http.Handler {
 	}
 }
 
+// HasPerm reports whether the claims' roles grant required, or admin:all.
+func HasPerm(cl *Claims, required string) bool {
+	perms := PermsForRoles(cl.Roles)
+	if _, hasAdmin := perms[PermAdminAll]; hasAdmin {
+		return true
+	}
+	_, hasPerm := perms[required]
+	return hasPerm
+}
+
 var ErrNoClaims = errors.New("no claims in context")
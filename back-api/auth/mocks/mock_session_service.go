@@ -22,6 +22,53 @@ func (_m *MockSessionService) EXPECT() *MockSessionService_Expecter {
 	return &MockSessionService_Expecter{mock: &_m.Mock}
 }
 
+// DeleteTwoFactorChallenge provides a mock function with given fields: ctx, tokenHash
+func (_m *MockSessionService) DeleteTwoFactorChallenge(ctx context.Context, tokenHash string) error {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTwoFactorChallenge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSessionService_DeleteTwoFactorChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTwoFactorChallenge'
+type MockSessionService_DeleteTwoFactorChallenge_Call struct {
+	*mock.Call
+}
+
+// DeleteTwoFactorChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+func (_e *MockSessionService_Expecter) DeleteTwoFactorChallenge(ctx interface{}, tokenHash interface{}) *MockSessionService_DeleteTwoFactorChallenge_Call {
+	return &MockSessionService_DeleteTwoFactorChallenge_Call{Call: _e.mock.On("DeleteTwoFactorChallenge", ctx, tokenHash)}
+}
+
+func (_c *MockSessionService_DeleteTwoFactorChallenge_Call) Run(run func(ctx context.Context, tokenHash string)) *MockSessionService_DeleteTwoFactorChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSessionService_DeleteTwoFactorChallenge_Call) Return(_a0 error) *MockSessionService_DeleteTwoFactorChallenge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSessionService_DeleteTwoFactorChallenge_Call) RunAndReturn(run func(context.Context, string) error) *MockSessionService_DeleteTwoFactorChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetLoginAttempts provides a mock function with given fields: ctx, email
 func (_m *MockSessionService) GetLoginAttempts(ctx context.Context, email string) (int64, error) {
 	ret := _m.Called(ctx, email)
@@ -136,6 +183,63 @@ func (_c *MockSessionService_GetRefreshTokenUserID_Call) RunAndReturn(run func(c
 	return _c
 }
 
+// GetTwoFactorChallengeUserID provides a mock function with given fields: ctx, tokenHash
+func (_m *MockSessionService) GetTwoFactorChallengeUserID(ctx context.Context, tokenHash string) (string, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTwoFactorChallengeUserID")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSessionService_GetTwoFactorChallengeUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTwoFactorChallengeUserID'
+type MockSessionService_GetTwoFactorChallengeUserID_Call struct {
+	*mock.Call
+}
+
+// GetTwoFactorChallengeUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+func (_e *MockSessionService_Expecter) GetTwoFactorChallengeUserID(ctx interface{}, tokenHash interface{}) *MockSessionService_GetTwoFactorChallengeUserID_Call {
+	return &MockSessionService_GetTwoFactorChallengeUserID_Call{Call: _e.mock.On("GetTwoFactorChallengeUserID", ctx, tokenHash)}
+}
+
+func (_c *MockSessionService_GetTwoFactorChallengeUserID_Call) Run(run func(ctx context.Context, tokenHash string)) *MockSessionService_GetTwoFactorChallengeUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSessionService_GetTwoFactorChallengeUserID_Call) Return(_a0 string, _a1 error) *MockSessionService_GetTwoFactorChallengeUserID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSessionService_GetTwoFactorChallengeUserID_Call) RunAndReturn(run func(context.Context, string) (string, error)) *MockSessionService_GetTwoFactorChallengeUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IncrLoginAttempts provides a mock function with given fields: ctx, email, window
 func (_m *MockSessionService) IncrLoginAttempts(ctx context.Context, email string, window time.Duration) (int64, error) {
 	ret := _m.Called(ctx, email, window)
@@ -194,9 +298,9 @@ func (_c *MockSessionService_IncrLoginAttempts_Call) RunAndReturn(run func(conte
 	return _c
 }
 
-// IsTokenBlacklisted provides a mock function with given fields: ctx, tokenHash
-func (_m *MockSessionService) IsTokenBlacklisted(ctx context.Context, tokenHash string) (bool, error) {
-	ret := _m.Called(ctx, tokenHash)
+// IsTokenBlacklisted provides a mock function with given fields: ctx, tokenID
+func (_m *MockSessionService) IsTokenBlacklisted(ctx context.Context, tokenID string) (bool, error) {
+	ret := _m.Called(ctx, tokenID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for IsTokenBlacklisted")
@@ -205,16 +309,16 @@ func (_m *MockSessionService) IsTokenBlacklisted(ctx context.Context, tokenHash
 	var r0 bool
 	var r1 error
 	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
-		return rf(ctx, tokenHash)
+		return rf(ctx, tokenID)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
-		r0 = rf(ctx, tokenHash)
+		r0 = rf(ctx, tokenID)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, tokenHash)
+		r1 = rf(ctx, tokenID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -229,12 +333,12 @@ type MockSessionService_IsTokenBlacklisted_Call struct {
 
 // IsTokenBlacklisted is a helper method to define mock.On call
 //   - ctx context.Context
-//   - tokenHash string
-func (_e *MockSessionService_Expecter) IsTokenBlacklisted(ctx interface{}, tokenHash interface{}) *MockSessionService_IsTokenBlacklisted_Call {
-	return &MockSessionService_IsTokenBlacklisted_Call{Call: _e.mock.On("IsTokenBlacklisted", ctx, tokenHash)}
+//   - tokenID string
+func (_e *MockSessionService_Expecter) IsTokenBlacklisted(ctx interface{}, tokenID interface{}) *MockSessionService_IsTokenBlacklisted_Call {
+	return &MockSessionService_IsTokenBlacklisted_Call{Call: _e.mock.On("IsTokenBlacklisted", ctx, tokenID)}
 }
 
-func (_c *MockSessionService_IsTokenBlacklisted_Call) Run(run func(ctx context.Context, tokenHash string)) *MockSessionService_IsTokenBlacklisted_Call {
+func (_c *MockSessionService_IsTokenBlacklisted_Call) Run(run func(ctx context.Context, tokenID string)) *MockSessionService_IsTokenBlacklisted_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(string))
 	})
@@ -438,9 +542,9 @@ func (_c *MockSessionService_RevokeRefreshToken_Call) RunAndReturn(run func(cont
 	return _c
 }
 
-// StoreBlacklistedToken provides a mock function with given fields: ctx, tokenHash, ttl
-func (_m *MockSessionService) StoreBlacklistedToken(ctx context.Context, tokenHash string, ttl time.Duration) error {
-	ret := _m.Called(ctx, tokenHash, ttl)
+// StoreBlacklistedToken provides a mock function with given fields: ctx, tokenID, ttl
+func (_m *MockSessionService) StoreBlacklistedToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	ret := _m.Called(ctx, tokenID, ttl)
 
 	if len(ret) == 0 {
 		panic("no return value specified for StoreBlacklistedToken")
@@ -448,7 +552,7 @@ func (_m *MockSessionService) StoreBlacklistedToken(ctx context.Context, tokenHa
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) error); ok {
-		r0 = rf(ctx, tokenHash, ttl)
+		r0 = rf(ctx, tokenID, ttl)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -463,13 +567,13 @@ type MockSessionService_StoreBlacklistedToken_Call struct {
 
 // StoreBlacklistedToken is a helper method to define mock.On call
 //   - ctx context.Context
-//   - tokenHash string
+//   - tokenID string
 //   - ttl time.Duration
-func (_e *MockSessionService_Expecter) StoreBlacklistedToken(ctx interface{}, tokenHash interface{}, ttl interface{}) *MockSessionService_StoreBlacklistedToken_Call {
-	return &MockSessionService_StoreBlacklistedToken_Call{Call: _e.mock.On("StoreBlacklistedToken", ctx, tokenHash, ttl)}
+func (_e *MockSessionService_Expecter) StoreBlacklistedToken(ctx interface{}, tokenID interface{}, ttl interface{}) *MockSessionService_StoreBlacklistedToken_Call {
+	return &MockSessionService_StoreBlacklistedToken_Call{Call: _e.mock.On("StoreBlacklistedToken", ctx, tokenID, ttl)}
 }
 
-func (_c *MockSessionService_StoreBlacklistedToken_Call) Run(run func(ctx context.Context, tokenHash string, ttl time.Duration)) *MockSessionService_StoreBlacklistedToken_Call {
+func (_c *MockSessionService_StoreBlacklistedToken_Call) Run(run func(ctx context.Context, tokenID string, ttl time.Duration)) *MockSessionService_StoreBlacklistedToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration))
 	})
@@ -535,6 +639,55 @@ func (_c *MockSessionService_StoreRefreshToken_Call) RunAndReturn(run func(conte
 	return _c
 }
 
+// StoreTwoFactorChallenge provides a mock function with given fields: ctx, tokenHash, userID, ttl
+func (_m *MockSessionService) StoreTwoFactorChallenge(ctx context.Context, tokenHash string, userID string, ttl time.Duration) error {
+	ret := _m.Called(ctx, tokenHash, userID, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreTwoFactorChallenge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) error); ok {
+		r0 = rf(ctx, tokenHash, userID, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSessionService_StoreTwoFactorChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreTwoFactorChallenge'
+type MockSessionService_StoreTwoFactorChallenge_Call struct {
+	*mock.Call
+}
+
+// StoreTwoFactorChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+//   - userID string
+//   - ttl time.Duration
+func (_e *MockSessionService_Expecter) StoreTwoFactorChallenge(ctx interface{}, tokenHash interface{}, userID interface{}, ttl interface{}) *MockSessionService_StoreTwoFactorChallenge_Call {
+	return &MockSessionService_StoreTwoFactorChallenge_Call{Call: _e.mock.On("StoreTwoFactorChallenge", ctx, tokenHash, userID, ttl)}
+}
+
+func (_c *MockSessionService_StoreTwoFactorChallenge_Call) Run(run func(ctx context.Context, tokenHash string, userID string, ttl time.Duration)) *MockSessionService_StoreTwoFactorChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockSessionService_StoreTwoFactorChallenge_Call) Return(_a0 error) *MockSessionService_StoreTwoFactorChallenge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSessionService_StoreTwoFactorChallenge_Call) RunAndReturn(run func(context.Context, string, string, time.Duration) error) *MockSessionService_StoreTwoFactorChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockSessionService creates a new instance of MockSessionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockSessionService(t interface {
@@ -24,6 +24,12 @@ type SessionService interface {
 	RevokeRefreshToken(ctx context.Context, tokenHash string) error
 	RevokeAllUserTokens(ctx context.Context, userID string) error
 
-	// Access token blacklisting
-	StoreBlacklistedToken(ctx context.Context, tokenHash string, ttl time.Duration) error
+	// Two-factor login challenges: an opaque token, issued after the password
+	// check, that maps to the user until a TOTP code completes the login.
+	StoreTwoFactorChallenge(ctx context.Context, tokenHash, userID string, ttl time.Duration) error
+	GetTwoFactorChallengeUserID(ctx context.Context, tokenHash string) (string, error)
+	DeleteTwoFactorChallenge(ctx context.Context, tokenHash string) error
+
+	// Access token blacklisting, keyed by RevocationID
+	StoreBlacklistedToken(ctx context.Context, tokenID string, ttl time.Duration) error
 }
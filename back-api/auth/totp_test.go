@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+)
+
+func TestSecretBox_RoundTrip(t *testing.T) {
+	box := NewSecretBox("test-secret")
+
+	sealed, err := box.Seal("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("Seal error: %v", err)
+	}
+	if sealed == "JBSWY3DPEHPK3PXP" {
+		t.Fatal("expected sealed value to differ from plaintext")
+	}
+	plain, err := box.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open error: %v", err)
+	}
+	if plain != "JBSWY3DPEHPK3PXP" {
+		t.Fatalf("expected round trip, got %q", plain)
+	}
+
+	if _, err := NewSecretBox("other-secret").Open(sealed); err == nil {
+		t.Fatal("expected Open with a different key to fail")
+	}
+}
+
+func TestValidateTOTP_AllowsOnePeriodSkew(t *testing.T) {
+	enrollment, err := GenerateTOTP("alice@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTOTP error: %v", err)
+	}
+	now := time.Now()
+
+	for _, tc := range []struct {
+		name   string
+		offset time.Duration
+		want   bool
+	}{
+		{"current period", 0, true},
+		{"previous period", -30 * time.Second, true},
+		{"two periods ago", -90 * time.Second, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			code, err := totp.GenerateCode(enrollment.Secret, now.Add(tc.offset))
+			if err != nil {
+				t.Fatalf("GenerateCode error: %v", err)
+			}
+			if got := ValidateTOTP(code, enrollment.Secret); got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
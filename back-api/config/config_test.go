@@ -0,0 +1,103 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func validConfig() Config {
+	return Config{
+		Env:     EnvProduction,
+		JWT:     JWTConfig{Secret: "a-real-secret-that-is-long-enough"},
+		DB:      DBConfig{URL: "postgres://app@db/smartheart", MaxConns: 10, MinConns: 1},
+		Queue:   QueueConfig{Workers: 4, Mode: QueueModeMemory},
+		Storage: StorageConfig{Mode: StorageModeLocal, MaxImageBytes: 10 << 20, MultipartMemoryBytes: 1 << 20, PresignTTL: 10 * time.Minute},
+		GPT:     GPTConfig{APIKey: "sk-test"},
+	}
+}
+
+func TestValidate_InsecureDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*Config)
+		wantErr string
+	}{
+		{"valid", func(*Config) {}, ""},
+		{"default JWT secret", func(c *Config) { c.JWT.Secret = defaultJWTSecret }, "JWT_SECRET"},
+		{"default database URL", func(c *Config) { c.DB.URL = defaultDatabaseURL }, "DATABASE_URL"},
+		{"missing OpenAI key", func(c *Config) { c.GPT.APIKey = "" }, "OPENAI_API_KEY"},
+		{"missing OpenAI key with mock", func(c *Config) { c.GPT.APIKey = ""; c.GPT.Mock = true }, ""},
+		{"unknown AI provider", func(c *Config) { c.GPT.Provider = "gemini" }, "AI_PROVIDER"},
+		{"GPT max timeout above client limit", func(c *Config) { c.GPT.MaxTimeout = time.Hour }, "GPT_MAX_TIMEOUT"},
+		{"GPT max timeout above job duration", func(c *Config) {
+			c.Queue.MaxDuration = 30 * time.Second
+			c.GPT.MaxTimeout = time.Minute
+		}, "JOB_MAX_DURATION"},
+		{"GPT max timeout within job duration", func(c *Config) {
+			c.Queue.MaxDuration = 2 * time.Minute
+			c.GPT.MaxTimeout = time.Minute
+		}, ""},
+		{"anthropic without key", func(c *Config) { c.GPT.Provider = AIProviderAnthropic }, "ANTHROPIC_API_KEY"},
+		{"anthropic without OpenAI key", func(c *Config) {
+			c.GPT = GPTConfig{Provider: AIProviderAnthropic, AnthropicAPIKey: "sk-ant-test"}
+		}, ""},
+		{"s3 without bucket", func(c *Config) { c.Storage.Mode = StorageModeS3 }, "S3_BUCKET"},
+		{"gcs without bucket", func(c *Config) { c.Storage.Mode = StorageModeGCS }, "GCS_BUCKET"},
+		{"image confidence out of range", func(c *Config) { c.ECG.MinImageConfidence = 1.5 }, "ECG_MIN_IMAGE_CONFIDENCE"},
+		{"unknown JWT algorithm", func(c *Config) { c.JWT.Algorithm = "ES256" }, "JWT_ALGORITHM"},
+		{"RS256 without private key", func(c *Config) { c.JWT.Algorithm = "RS256" }, "JWT_PRIVATE_KEY_FILE"},
+		{"max workers below workers", func(c *Config) { c.Queue.MaxWorkers = 2 }, "QUEUE_WORKERS_MAX"},
+		{"max workers above workers", func(c *Config) { c.Queue.MaxWorkers = 16 }, ""},
+		{"unknown queue mode", func(c *Config) { c.Queue.Mode = "memq" }, "QUEUE_MODE"},
+		{"redis queue without URL", func(c *Config) { c.Queue.Mode = QueueModeRedis }, "REDIS_URL"},
+		{"unknown mail mode", func(c *Config) { c.SMTP.Mode = "sendgrid" }, "MAIL_MODE"},
+		{"unknown log level", func(c *Config) { c.Log.Level = "verbose" }, "LOG_LEVEL"},
+		{"unknown log format", func(c *Config) { c.Log.Format = "xml" }, "LOG_FORMAT"},
+		{"encryption active version without key", func(c *Config) { c.Encryption.ActiveVersion = 1 }, "DATA_ENCRYPTION_ACTIVE_VERSION"},
+		{"short encryption secret", func(c *Config) { c.Encryption.Keys = map[int]string{1: "short"} }, "DATA_ENCRYPTION_KEYS"},
+		{"encryption enabled", func(c *Config) {
+			c.Encryption.Keys = map[int]string{1: strings.Repeat("k", minEncryptionSecretLen)}
+			c.Encryption.ActiveVersion = 1
+		}, ""},
+		{"localstack test credentials", func(c *Config) {
+			c.Storage.Mode = StorageModeAWS
+			c.S3 = S3Config{Bucket: "b", AWSAccessKey: "test", AWSSecretKey: "test"}
+		}, "AWS credentials"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.mutate(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error mentioning %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidate_DevelopmentOnlyWarns(t *testing.T) {
+	cfg := validConfig()
+	cfg.Env = EnvDevelopment
+	cfg.JWT.Secret = defaultJWTSecret
+	cfg.DB.URL = defaultDatabaseURL
+	cfg.GPT.APIKey = ""
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected insecure defaults to be allowed in development, got %v", err)
+	}
+}
+
+func TestDefaults_GPTMaxTimeoutFitsJobDuration(t *testing.T) {
+	c := defaults()
+	if c.GPT.MaxTimeout > c.Queue.MaxDuration {
+		t.Fatalf("default GPT_MAX_TIMEOUT %s exceeds default JOB_MAX_DURATION %s", c.GPT.MaxTimeout, c.Queue.MaxDuration)
+	}
+}
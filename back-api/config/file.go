@@ -0,0 +1,43 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadFile merges a YAML or JSON config file (JSON is valid YAML) into cfg.
+// Keys use the yaml tags of Config; keys absent from the file keep their
+// current value. Unknown keys are logged and ignored.
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	// A strict pass only to report unknown keys; the real decode is lenient.
+	var probe Config
+	strict := yaml.NewDecoder(bytes.NewReader(data))
+	strict.KnownFields(true)
+	if err := strict.Decode(&probe); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return fmt.Errorf("parse config file: %w", err)
+		}
+		for _, msg := range typeErr.Errors {
+			slog.Warn("Ignoring config file entry", "path", path, "problem", msg)
+		}
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("parse config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFile_YAML(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+http_addr: ":9090"
+queue:
+  workers: 8
+  max_duration: 45s
+cors:
+  origins: [https://app.example.com]
+quota:
+  role_tokens:
+    pro: { daily: 100, monthly: 1000 }
+unknown_section:
+  foo: bar
+`)
+	cfg := defaults()
+	if err := loadFile(path, &cfg); err != nil {
+		t.Fatalf("loadFile: %v", err)
+	}
+
+	if cfg.HTTPAddr != ":9090" {
+		t.Errorf("HTTPAddr = %q", cfg.HTTPAddr)
+	}
+	if cfg.Queue.Workers != 8 || cfg.Queue.MaxDuration != 45*time.Second {
+		t.Errorf("Queue = %+v", cfg.Queue)
+	}
+	if cfg.Queue.Buffer != 1024 {
+		t.Errorf("keys absent from the file should keep defaults, Buffer = %d", cfg.Queue.Buffer)
+	}
+	if len(cfg.CORS.Origins) != 1 || cfg.CORS.Origins[0] != "https://app.example.com" {
+		t.Errorf("CORS.Origins = %v", cfg.CORS.Origins)
+	}
+	if got := cfg.Quota.RoleTokens["pro"]; got != (TokenBudget{Daily: 100, Monthly: 1000}) {
+		t.Errorf("RoleTokens[pro] = %+v", got)
+	}
+}
+
+func TestLoadFile_JSON(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{"gpt": {"model": "gpt-4o-mini", "max_tokens": 500}}`)
+	cfg := defaults()
+	if err := loadFile(path, &cfg); err != nil {
+		t.Fatalf("loadFile: %v", err)
+	}
+	if cfg.GPT.Model != "gpt-4o-mini" || cfg.GPT.MaxTokens != 500 {
+		t.Errorf("GPT = %+v", cfg.GPT)
+	}
+}
+
+func TestLoadFile_Malformed(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "queue: [unterminated")
+	cfg := defaults()
+	if err := loadFile(path, &cfg); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
+
+func TestApplyEnv_OverridesFile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "http_addr: \":9090\"\nqueue:\n  workers: 8\n")
+	t.Setenv("HTTP_ADDR", ":7070")
+
+	cfg := defaults()
+	if err := loadFile(path, &cfg); err != nil {
+		t.Fatalf("loadFile: %v", err)
+	}
+	applyEnv(&cfg)
+
+	if cfg.HTTPAddr != ":7070" {
+		t.Errorf("env should take precedence, HTTPAddr = %q", cfg.HTTPAddr)
+	}
+	if cfg.Queue.Workers != 8 {
+		t.Errorf("file value should survive when env is unset, Workers = %d", cfg.Queue.Workers)
+	}
+}
+
+func TestApplyEnv_HTTPTimeouts(t *testing.T) {
+	t.Setenv("HTTP_WRITE_TIMEOUT", "2m")
+	t.Setenv("HTTP_IDLE_TIMEOUT", "bogus")
+
+	cfg := defaults()
+	applyEnv(&cfg)
+
+	if cfg.HTTP.ReadTimeout != 30*time.Second {
+		t.Errorf("ReadTimeout should keep its default, got %v", cfg.HTTP.ReadTimeout)
+	}
+	if cfg.HTTP.WriteTimeout != 2*time.Minute {
+		t.Errorf("WriteTimeout = %v", cfg.HTTP.WriteTimeout)
+	}
+	if cfg.HTTP.IdleTimeout != 90*time.Second {
+		t.Errorf("malformed duration should fall back to default, IdleTimeout = %v", cfg.HTTP.IdleTimeout)
+	}
+}
+
+func TestApplyEnv_DBPool(t *testing.T) {
+	t.Setenv("DB_MAX_CONN_LIFETIME", "15m")
+	t.Setenv("DB_HEALTH_CHECK_PERIOD", "10s")
+
+	cfg := defaults()
+	applyEnv(&cfg)
+
+	if cfg.DB.MaxConnLifetime != 15*time.Minute {
+		t.Errorf("MaxConnLifetime = %v", cfg.DB.MaxConnLifetime)
+	}
+	if cfg.DB.MaxConnIdleTime != 30*time.Minute {
+		t.Errorf("MaxConnIdleTime should keep its default, got %v", cfg.DB.MaxConnIdleTime)
+	}
+	if cfg.DB.HealthCheckPeriod != 10*time.Second {
+		t.Errorf("HealthCheckPeriod = %v", cfg.DB.HealthCheckPeriod)
+	}
+}
+
+func TestApplyEnv_EncryptionKeys(t *testing.T) {
+	t.Setenv("DATA_ENCRYPTION_KEYS", "1:old:secret, 2:new-secret,bogus")
+	t.Setenv("DATA_ENCRYPTION_ACTIVE_VERSION", "2")
+
+	cfg := defaults()
+	applyEnv(&cfg)
+
+	if len(cfg.Encryption.Keys) != 2 {
+		t.Fatalf("expected the malformed entry to be skipped, got %d keys", len(cfg.Encryption.Keys))
+	}
+	if cfg.Encryption.Keys[1] != "old:secret" {
+		t.Errorf("only the first ':' should separate the version, Keys[1] = %q", cfg.Encryption.Keys[1])
+	}
+	if cfg.Encryption.Keys[2] != "new-secret" {
+		t.Errorf("Keys[2] = %q", cfg.Encryption.Keys[2])
+	}
+	if cfg.Encryption.ActiveVersion != 2 {
+		t.Errorf("ActiveVersion = %d", cfg.Encryption.ActiveVersion)
+	}
+}
@@ -30,12 +30,14 @@ type DB struct {
 	pool *pgxpool.Pool
 }
 
-// PoolConfig holds optional connection pool tuning parameters.
+// PoolConfig holds optional connection pool tuning parameters. Zero values
+// keep the pgxpool defaults.
 type PoolConfig struct {
-	MaxConns        int32
-	MinConns        int32
-	MaxConnLifetime time.Duration
-	MaxConnIdleTime time.Duration
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
 }
 
 func NewDB(ctx context.Context, databaseURL string, opts ...func(*PoolConfig)) (*DB, error) {
@@ -60,6 +62,9 @@ func NewDB(ctx context.Context, databaseURL string, opts ...func(*PoolConfig)) (
 	if pc.MaxConnIdleTime > 0 {
 		cfg.MaxConnIdleTime = pc.MaxConnIdleTime
 	}
+	if pc.HealthCheckPeriod > 0 {
+		cfg.HealthCheckPeriod = pc.HealthCheckPeriod
+	}
 
 	pool, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
@@ -72,7 +77,10 @@ func NewDB(ctx context.Context, databaseURL string, opts ...func(*PoolConfig)) (
 
 	slog.InfoContext(ctx, "Database connection established",
 		"max_conns", cfg.MaxConns,
-		"min_conns", cfg.MinConns)
+		"min_conns", cfg.MinConns,
+		"max_conn_lifetime", cfg.MaxConnLifetime,
+		"max_conn_idle_time", cfg.MaxConnIdleTime,
+		"health_check_period", cfg.HealthCheckPeriod)
 	return &DB{pool: pool}, nil
 }
 
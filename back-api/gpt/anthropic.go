@@ -0,0 +1,262 @@
+package gpt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	anthropicBaseURL = "https://api.anthropic.com/v1"
+	anthropicVersion = "2023-06-01"
+	// anthropicDefaultMaxTokens is used when a request sets no limit; the
+	// Messages API requires one.
+	anthropicDefaultMaxTokens = 4096
+	// anthropicJSONInstruction replaces OpenAI's json_object response
+	// format, which the Messages API has no equivalent of.
+	anthropicJSONInstruction = "Respond with a single JSON object and nothing else."
+)
+
+// AnthropicProvider is a Provider calling the Anthropic Messages API with
+// Claude models. Images are sent as base64 or URL image blocks, so vision
+// requests work as with OpenAI; image detail levels are ignored.
+type AnthropicProvider struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewAnthropicProvider returns a provider authenticating with apiKey.
+// Request deadlines come from the Client's context.
+func NewAnthropicProvider(apiKey string) *AnthropicProvider {
+	return &AnthropicProvider{apiKey: apiKey, baseURL: anthropicBaseURL, httpClient: &http.Client{}}
+}
+
+func (*AnthropicProvider) Name() string { return "Anthropic" }
+
+// AnthropicError is an error response of the Messages API.
+type AnthropicError struct {
+	StatusCode int
+	Type       string // e.g. "rate_limit_error", "overloaded_error"
+	Message    string
+}
+
+func (e *AnthropicError) Error() string {
+	return fmt.Sprintf("anthropic %s (HTTP %d): %s", e.Type, e.StatusCode, e.Message)
+}
+
+// Retryable reports whether err is transient: HTTP 429, 5xx (including 529
+// overloaded) or a network timeout.
+func (*AnthropicProvider) Retryable(err error) bool {
+	var apiErr *AnthropicError
+	if errors.As(err, &apiErr) {
+		return isRetryableStatus(apiErr.StatusCode)
+	}
+	return isTimeout(err)
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	Temperature *float32           `json:"temperature,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string           `json:"role"`
+	Content []anthropicBlock `json:"content"`
+}
+
+type anthropicBlock struct {
+	Type   string                `json:"type"`
+	Text   string                `json:"text,omitempty"`
+	Source *anthropicImageSource `json:"source,omitempty"`
+}
+
+type anthropicImageSource struct {
+	Type      string `json:"type"` // "base64" or "url"
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+type anthropicResponse struct {
+	ID         string           `json:"id"`
+	Model      string           `json:"model"`
+	Content    []anthropicBlock `json:"content"`
+	StopReason string           `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// CreateChatCompletion translates req to a Messages API call and the reply
+// back to the OpenAI shape, summing input and output tokens into
+// TotalTokens.
+func (p *AnthropicProvider) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	body, err := json.Marshal(toAnthropicRequest(req))
+	if err != nil {
+		return openai.ChatCompletionResponse{}, fmt.Errorf("marshal anthropic request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/messages", bytes.NewReader(body))
+	if err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Api-Key", p.apiKey)
+	httpReq.Header.Set("Anthropic-Version", anthropicVersion)
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return openai.ChatCompletionResponse{}, fmt.Errorf("read anthropic response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &AnthropicError{StatusCode: resp.StatusCode, Type: "api_error", Message: http.StatusText(resp.StatusCode)}
+		var envelope struct {
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(data, &envelope) == nil && envelope.Error.Type != "" {
+			apiErr.Type, apiErr.Message = envelope.Error.Type, envelope.Error.Message
+		}
+		return openai.ChatCompletionResponse{}, apiErr
+	}
+
+	var out anthropicResponse
+	if err := json.Unmarshal(data, &out); err != nil {
+		return openai.ChatCompletionResponse{}, fmt.Errorf("decode anthropic response: %w", err)
+	}
+	return fromAnthropicResponse(out, jsonMode(req)), nil
+}
+
+func jsonMode(req openai.ChatCompletionRequest) bool {
+	return req.ResponseFormat != nil && req.ResponseFormat.Type == openai.ChatCompletionResponseFormatTypeJSONObject
+}
+
+func toAnthropicRequest(req openai.ChatCompletionRequest) anthropicRequest {
+	out := anthropicRequest{Model: req.Model, MaxTokens: req.MaxTokens}
+	if out.MaxTokens <= 0 {
+		out.MaxTokens = anthropicDefaultMaxTokens
+	}
+	if req.Temperature > 0 {
+		out.Temperature = &req.Temperature
+	}
+
+	var system []string
+	for _, m := range req.Messages {
+		if m.Role == openai.ChatMessageRoleSystem {
+			system = append(system, m.Content)
+			continue
+		}
+		msg := anthropicMessage{Role: m.Role}
+		if m.Content != "" {
+			msg.Content = append(msg.Content, anthropicBlock{Type: "text", Text: m.Content})
+		}
+		for _, part := range m.MultiContent {
+			if block, ok := toAnthropicBlock(part); ok {
+				msg.Content = append(msg.Content, block)
+			}
+		}
+		out.Messages = append(out.Messages, msg)
+	}
+	if jsonMode(req) {
+		system = append(system, anthropicJSONInstruction)
+	}
+	out.System = strings.Join(system, "\n\n")
+	return out
+}
+
+// toAnthropicBlock converts a text or image message part. Data URLs become
+// base64 image sources, other URLs are fetched by Anthropic.
+func toAnthropicBlock(part openai.ChatMessagePart) (anthropicBlock, bool) {
+	switch part.Type {
+	case openai.ChatMessagePartTypeText:
+		return anthropicBlock{Type: "text", Text: part.Text}, true
+	case openai.ChatMessagePartTypeImageURL:
+		if part.ImageURL == nil {
+			return anthropicBlock{}, false
+		}
+		url := part.ImageURL.URL
+		if rest, ok := strings.CutPrefix(url, "data:"); ok {
+			mediaType, data, ok := strings.Cut(rest, ";base64,")
+			if !ok {
+				return anthropicBlock{}, false
+			}
+			return anthropicBlock{Type: "image", Source: &anthropicImageSource{Type: "base64", MediaType: mediaType, Data: data}}, true
+		}
+		return anthropicBlock{Type: "image", Source: &anthropicImageSource{Type: "url", URL: url}}, true
+	}
+	return anthropicBlock{}, false
+}
+
+func fromAnthropicResponse(resp anthropicResponse, jsonOnly bool) openai.ChatCompletionResponse {
+	out := openai.ChatCompletionResponse{
+		ID:    resp.ID,
+		Model: resp.Model,
+		Usage: openai.Usage{
+			PromptTokens:     resp.Usage.InputTokens,
+			CompletionTokens: resp.Usage.OutputTokens,
+			TotalTokens:      resp.Usage.InputTokens + resp.Usage.OutputTokens,
+		},
+	}
+
+	var text []string
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text = append(text, block.Text)
+		}
+	}
+	if len(text) == 0 {
+		return out
+	}
+	content := strings.Join(text, "")
+	if jsonOnly {
+		content = stripCodeFence(content)
+	}
+
+	finish := openai.FinishReason(resp.StopReason)
+	switch resp.StopReason {
+	case "end_turn", "stop_sequence":
+		finish = openai.FinishReasonStop
+	case "max_tokens":
+		finish = openai.FinishReasonLength
+	}
+	out.Choices = []openai.ChatCompletionChoice{{
+		Message:      openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: content},
+		FinishReason: finish,
+	}}
+	return out
+}
+
+// stripCodeFence removes a Markdown code fence Claude sometimes wraps JSON
+// in, leaving other content unchanged.
+func stripCodeFence(s string) string {
+	trimmed := strings.TrimSpace(s)
+	rest, ok := strings.CutPrefix(trimmed, "```")
+	if !ok {
+		return s
+	}
+	rest, ok = strings.CutSuffix(rest, "```")
+	if !ok {
+		return s
+	}
+	rest = strings.TrimPrefix(rest, "json")
+	return strings.TrimSpace(rest)
+}
@@ -0,0 +1,172 @@
+package gpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// anthropicCapture records the last Messages API request and answers with
+// a canned response.
+type anthropicCapture struct {
+	header http.Header
+	req    anthropicRequest
+	status int
+	body   string
+}
+
+func (a *anthropicCapture) RoundTrip(r *http.Request) (*http.Response, error) {
+	a.header = r.Header.Clone()
+	if err := json.NewDecoder(r.Body).Decode(&a.req); err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: a.status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(a.body)),
+	}, nil
+}
+
+func newAnthropicStub(status int, body string) (*AnthropicProvider, *anthropicCapture) {
+	capture := &anthropicCapture{status: status, body: body}
+	p := NewAnthropicProvider("test-key")
+	p.httpClient = &http.Client{Transport: capture}
+	return p, capture
+}
+
+const anthropicSuccessBody = `{"id":"msg_1","type":"message","role":"assistant","model":"claude-sonnet-4-5",` +
+	`"content":[{"type":"text","text":"ok"}],"stop_reason":"end_turn","usage":{"input_tokens":12,"output_tokens":5}}`
+
+func TestAnthropicProvider_TranslatesRequestAndResponse(t *testing.T) {
+	p, capture := newAnthropicStub(http.StatusOK, anthropicSuccessBody)
+
+	resp, err := p.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:     "claude-sonnet-4-5",
+		MaxTokens: 300,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: "be precise"},
+			{Role: openai.ChatMessageRoleUser, MultiContent: []openai.ChatMessagePart{
+				{Type: openai.ChatMessagePartTypeImageURL, ImageURL: &openai.ChatMessageImageURL{URL: "data:image/png;base64,AAAA"}},
+				{Type: openai.ChatMessagePartTypeImageURL, ImageURL: &openai.ChatMessageImageURL{URL: "https://bucket.example/ekg.jpg"}},
+				{Type: openai.ChatMessagePartTypeText, Text: "what rhythm?"},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+
+	if capture.header.Get("X-Api-Key") != "test-key" || capture.header.Get("Anthropic-Version") == "" {
+		t.Errorf("missing auth headers: %v", capture.header)
+	}
+	if capture.req.System != "be precise" || capture.req.MaxTokens != 300 {
+		t.Errorf("unexpected system or max_tokens: %+v", capture.req)
+	}
+	if len(capture.req.Messages) != 1 || len(capture.req.Messages[0].Content) != 3 {
+		t.Fatalf("expected one user message with 3 blocks, got %+v", capture.req.Messages)
+	}
+	blocks := capture.req.Messages[0].Content
+	if src := blocks[0].Source; blocks[0].Type != "image" || src.Type != "base64" || src.MediaType != "image/png" || src.Data != "AAAA" {
+		t.Errorf("data URL not converted to a base64 image block: %+v %+v", blocks[0], src)
+	}
+	if src := blocks[1].Source; src.Type != "url" || src.URL != "https://bucket.example/ekg.jpg" {
+		t.Errorf("URL not converted to a url image block: %+v", src)
+	}
+	if blocks[2].Type != "text" || blocks[2].Text != "what rhythm?" {
+		t.Errorf("unexpected text block: %+v", blocks[2])
+	}
+
+	if len(resp.Choices) != 1 || resp.Choices[0].Message.Content != "ok" || resp.Choices[0].FinishReason != openai.FinishReasonStop {
+		t.Errorf("unexpected choices: %+v", resp.Choices)
+	}
+	if resp.Usage.TotalTokens != 17 || resp.Model != "claude-sonnet-4-5" {
+		t.Errorf("expected 17 total tokens from model claude-sonnet-4-5, got %+v", resp)
+	}
+}
+
+func TestAnthropicProvider_JSONModeAddsInstructionAndStripsFence(t *testing.T) {
+	body := `{"id":"msg_2","model":"claude-sonnet-4-5","content":[{"type":"text","text":"` +
+		"```json\\n{\\\"conclusion\\\":\\\"normal\\\"}\\n```" +
+		`"}],"stop_reason":"end_turn","usage":{"input_tokens":1,"output_tokens":1}}`
+	p, capture := newAnthropicStub(http.StatusOK, body)
+
+	resp, err := p.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Messages:       []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}},
+		ResponseFormat: &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if !strings.Contains(capture.req.System, anthropicJSONInstruction) {
+		t.Errorf("expected the JSON instruction in the system prompt, got %q", capture.req.System)
+	}
+	if capture.req.MaxTokens != anthropicDefaultMaxTokens {
+		t.Errorf("expected default max_tokens, got %d", capture.req.MaxTokens)
+	}
+	if got := resp.Choices[0].Message.Content; got != `{"conclusion":"normal"}` {
+		t.Errorf("expected the code fence stripped, got %q", got)
+	}
+}
+
+func TestAnthropicProvider_ErrorsAndRetryability(t *testing.T) {
+	tests := []struct {
+		status    int
+		body      string
+		errType   string
+		retryable bool
+	}{
+		{http.StatusTooManyRequests, `{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`, "rate_limit_error", true},
+		{529, `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`, "overloaded_error", true},
+		{http.StatusUnauthorized, `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`, "authentication_error", false},
+		{http.StatusBadGateway, `<html>bad gateway</html>`, "api_error", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.errType, func(t *testing.T) {
+			p, _ := newAnthropicStub(tt.status, tt.body)
+			_, err := p.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+				Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}},
+			})
+			var apiErr *AnthropicError
+			if !errors.As(err, &apiErr) || apiErr.Type != tt.errType || apiErr.StatusCode != tt.status {
+				t.Fatalf("expected %s error with HTTP %d, got %v", tt.errType, tt.status, err)
+			}
+			if got := p.Retryable(err); got != tt.retryable {
+				t.Errorf("Retryable = %v, want %v", got, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestClient_WithAnthropicProvider(t *testing.T) {
+	p, capture := newAnthropicStub(http.StatusOK, anthropicSuccessBody)
+	c := NewClient("", nil, WithProvider(p), WithModel("claude-sonnet-4-5"), WithMaxTokens(800))
+
+	result, err := c.ProcessRequest(context.Background(), "hello", nil)
+	if err != nil {
+		t.Fatalf("ProcessRequest: %v", err)
+	}
+	if result.Content != "ok" || result.TokensUsed != 17 || result.Model != "claude-sonnet-4-5" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if capture.req.Model != "claude-sonnet-4-5" || capture.req.MaxTokens != 800 {
+		t.Errorf("client settings not passed to the provider: %+v", capture.req)
+	}
+}
+
+func TestClassifyError_NamesProvider(t *testing.T) {
+	err := classifyError(context.Background(), "Anthropic",
+		&AnthropicError{StatusCode: http.StatusUnauthorized, Type: "authentication_error", Message: "invalid x-api-key"}, 0)
+	if !strings.HasPrefix(err.Error(), "Anthropic API authentication failed") {
+		t.Errorf("unexpected classification: %v", err)
+	}
+	var apiErr *AnthropicError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("expected the provider error to stay wrapped")
+	}
+}
@@ -0,0 +1,111 @@
+package gpt
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache stores ProcessResults under a content-derived key, so identical
+// requests reuse an earlier OpenAI answer instead of paying for a new one.
+type Cache interface {
+	// Get returns the cached result for key; ok is false on a miss.
+	Get(ctx context.Context, key string) (result *ProcessResult, ok bool, err error)
+	// Set stores result under key for ttl.
+	Set(ctx context.Context, key string, result *ProcessResult, ttl time.Duration) error
+}
+
+// NoopCache never stores anything. It is the client default.
+type NoopCache struct{}
+
+func (NoopCache) Get(context.Context, string) (*ProcessResult, bool, error) { return nil, false, nil }
+
+func (NoopCache) Set(context.Context, string, *ProcessResult, time.Duration) error { return nil }
+
+const redisCachePrefix = "smartheart:gpt_cache:"
+
+// RedisCache keeps results as JSON values with a TTL.
+type RedisCache struct {
+	client *redis.Client
+}
+
+// NewRedisCache returns a cache backed by client.
+func NewRedisCache(client *redis.Client) *RedisCache {
+	return &RedisCache{client: client}
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) (*ProcessResult, bool, error) {
+	data, err := c.client.Get(ctx, redisCachePrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("get cached GPT result: %w", err)
+	}
+	var result ProcessResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, false, fmt.Errorf("decode cached GPT result: %w", err)
+	}
+	return &result, true, nil
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, result *ProcessResult, ttl time.Duration) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("encode GPT result: %w", err)
+	}
+	if err := c.client.Set(ctx, redisCachePrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("cache GPT result: %w", err)
+	}
+	return nil
+}
+
+// WithCache enables result caching: identical requests within ttl are
+// answered from cache. A nil cache or non-positive ttl leaves caching off.
+func WithCache(cache Cache, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if cache != nil && ttl > 0 {
+			c.cache = cache
+			c.cacheTTL = ttl
+		}
+	}
+}
+
+// cacheKey derives the cache key for a request from everything that shapes
+// the answer: model, system prompt, query, options and file contents. File
+// storage keys are left out so duplicate uploads share an entry; file order
+// is ignored because each part is sent as an independent attachment.
+func cacheKey(model, systemPrompt, textQuery string, opts RequestOptions, files []loadedFile) string {
+	fileHashes := make([]string, len(files))
+	for i, f := range files {
+		sum := sha256.Sum256(f.data)
+		fileHashes[i] = f.contentType + ":" + hex.EncodeToString(sum[:])
+	}
+	sort.Strings(fileHashes)
+
+	h := sha256.New()
+	for _, part := range []string{
+		model,
+		systemPrompt,
+		textQuery,
+		string(opts.ImageDetail),
+		strconv.Itoa(opts.MaxTokens),
+		strconv.FormatBool(opts.Structured),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	for _, fh := range fileHashes {
+		h.Write([]byte(fh))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
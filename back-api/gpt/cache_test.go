@@ -0,0 +1,140 @@
+package gpt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	storagemocks "github.com/fedutinova/smartheart/back-api/storage/mocks"
+)
+
+// mapCache is an in-memory Cache for tests.
+type mapCache struct {
+	mu      sync.Mutex
+	entries map[string]ProcessResult
+}
+
+func newMapCache() *mapCache { return &mapCache{entries: make(map[string]ProcessResult)} }
+
+func (c *mapCache) Get(_ context.Context, key string) (*ProcessResult, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	return &r, true, nil
+}
+
+func (c *mapCache) Set(_ context.Context, key string, result *ProcessResult, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = *result
+	return nil
+}
+
+func TestProcessRequest_ServesRepeatFromCache(t *testing.T) {
+	transport := &stubTransport{responses: []stubResponse{{http.StatusOK, successBody}}}
+	c := newStubClient(transport, WithCache(newMapCache(), time.Hour))
+
+	first, err := c.ProcessRequest(context.Background(), "hello", nil)
+	if err != nil {
+		t.Fatalf("first ProcessRequest: %v", err)
+	}
+	if first.Cached {
+		t.Fatal("first result should not be cached")
+	}
+
+	second, err := c.ProcessRequest(context.Background(), "hello", nil)
+	if err != nil {
+		t.Fatalf("second ProcessRequest: %v", err)
+	}
+	if !second.Cached || second.Content != "ok" || second.TokensUsed != 0 {
+		t.Fatalf("expected cached result with no tokens, got %+v", second)
+	}
+	if got := transport.calls.Load(); got != 1 {
+		t.Fatalf("expected 1 OpenAI call, got %d", got)
+	}
+}
+
+func TestProcessRequest_NoCacheBypassesCache(t *testing.T) {
+	transport := &stubTransport{responses: []stubResponse{{http.StatusOK, successBody}}}
+	c := newStubClient(transport, WithCache(newMapCache(), time.Hour))
+
+	for range 2 {
+		res, err := c.ProcessRequest(context.Background(), "hello", nil, RequestOptions{NoCache: true})
+		if err != nil {
+			t.Fatalf("ProcessRequest: %v", err)
+		}
+		if res.Cached {
+			t.Fatal("NoCache result should not come from the cache")
+		}
+	}
+	if got := transport.calls.Load(); got != 2 {
+		t.Fatalf("expected 2 OpenAI calls, got %d", got)
+	}
+}
+
+func TestProcessRequest_DoesNotCacheRefusal(t *testing.T) {
+	transport := &stubTransport{responses: []stubResponse{{http.StatusOK, refusalBody}}}
+	c := newStubClient(transport, WithCache(newMapCache(), time.Hour))
+
+	for range 2 {
+		if _, err := c.ProcessRequest(context.Background(), "hello", nil); err != nil {
+			t.Fatalf("ProcessRequest: %v", err)
+		}
+	}
+	// Each call makes the original attempt plus the rephrased retry.
+	if got := transport.calls.Load(); got != 4 {
+		t.Fatalf("expected 4 OpenAI calls, got %d", got)
+	}
+}
+
+func TestProcessRequest_CacheKeyIgnoresStorageKey(t *testing.T) {
+	store := storagemocks.NewMockStorage(t)
+	store.EXPECT().GetFile(mock.Anything, mock.Anything).
+		RunAndReturn(func(context.Context, string) (io.ReadCloser, string, error) {
+			return io.NopCloser(strings.NewReader("same report")), "text/plain", nil
+		})
+
+	transport := &stubTransport{responses: []stubResponse{{http.StatusOK, successBody}}}
+	c := newStubClient(transport, WithCache(newMapCache(), time.Hour))
+	c.storage = store
+
+	if _, err := c.ProcessRequest(context.Background(), "", []string{"uploads/a.txt"}); err != nil {
+		t.Fatalf("first ProcessRequest: %v", err)
+	}
+	res, err := c.ProcessRequest(context.Background(), "", []string{"uploads/b.txt"})
+	if err != nil {
+		t.Fatalf("second ProcessRequest: %v", err)
+	}
+	if !res.Cached {
+		t.Fatal("expected identical content under a new key to hit the cache")
+	}
+	if got := transport.calls.Load(); got != 1 {
+		t.Fatalf("expected 1 OpenAI call, got %d", got)
+	}
+}
+
+func TestCacheKey_DependsOnQueryAndOptions(t *testing.T) {
+	files := []loadedFile{{key: "a", data: []byte("x"), contentType: "text/plain"}}
+	base := cacheKey("gpt-4o", "sys", "q", RequestOptions{MaxTokens: 100}, files)
+
+	variants := map[string]string{
+		"model":      cacheKey("gpt-4o-mini", "sys", "q", RequestOptions{MaxTokens: 100}, files),
+		"query":      cacheKey("gpt-4o", "sys", "q2", RequestOptions{MaxTokens: 100}, files),
+		"structured": cacheKey("gpt-4o", "sys", "q", RequestOptions{MaxTokens: 100, Structured: true}, files),
+		"content":    cacheKey("gpt-4o", "sys", "q", RequestOptions{MaxTokens: 100}, []loadedFile{{key: "a", data: []byte("y"), contentType: "text/plain"}}),
+	}
+	for name, key := range variants {
+		if key == base {
+			t.Errorf("changing %s should change the cache key", name)
+		}
+	}
+}
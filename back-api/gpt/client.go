@@ -12,25 +12,45 @@ import (
 	"time"
 
 	"github.com/sashabaranov/go-openai"
+	"golang.org/x/sync/errgroup"
 
+	"github.com/fedutinova/smartheart/back-api/models"
 	"github.com/fedutinova/smartheart/back-api/storage"
 	"github.com/fedutinova/smartheart/back-api/validation"
 )
 
 // Processor is the interface for GPT processing, enabling testability.
+// Client implements it on top of any Provider.
 type Processor interface {
-	ProcessRequest(ctx context.Context, textQuery string, fileKeys []string) (*ProcessResult, error)
+	ProcessRequest(ctx context.Context, textQuery string, fileKeys []string, opts ...RequestOptions) (*ProcessResult, error)
 	ProcessStructuredECG(ctx context.Context, fileKeys []string, systemPrompt, userPrompt string) (*ProcessResult, error)
 }
 
 type Client struct {
-	openAI      *openai.Client
+	provider    Provider // Model vendor (OpenAI by default)
+	baseURL     string   // OpenAI endpoint override for the default provider
 	storage     storage.Storage
 	model       string                // GPT model name
 	imageDetail openai.ImageURLDetail // Detail level for images (Auto, Low, High)
 	timeout     time.Duration         // Request timeout
+	maxTokens   int                   // Completion token limit for ProcessRequest
+	prompts     *PromptSet            // Prompt templates
+
+	maxImageDimension int           // Longest image side in px before downscaling (0 = never)
+	maxFileBytes      int64         // Largest stored file read into a request
+	presignTTL        time.Duration // Lifetime of presigned image URLs sent to the provider
+
+	maxAttempts    int           // Total attempts per provider call, including the first
+	retryBaseDelay time.Duration // Initial backoff delay between attempts
+
+	cache    Cache         // ProcessRequest result cache
+	cacheTTL time.Duration // How long cached results live (0 = caching off)
 }
 
+// maxFileConcurrency bounds how many files of one request are read and
+// converted to message parts at the same time.
+const maxFileConcurrency = 4
+
 // ClientOption configures GPT client.
 type ClientOption func(*Client)
 
@@ -48,6 +68,35 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithPrompts sets the prompt templates. Nil keeps the embedded defaults.
+func WithPrompts(p *PromptSet) ClientOption {
+	return func(c *Client) {
+		if p != nil {
+			c.prompts = p
+		}
+	}
+}
+
+// WithMaxFileBytes sets the largest stored file the client reads into a
+// request. Non-positive values are ignored.
+func WithMaxFileBytes(n int64) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxFileBytes = n
+		}
+	}
+}
+
+// WithPresignTTL sets how long presigned image URLs sent to the provider stay
+// valid. Non-positive values are ignored.
+func WithPresignTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if ttl > 0 {
+			c.presignTTL = ttl
+		}
+	}
+}
+
 // WithModel sets the GPT model name.
 func WithModel(model string) ClientOption {
 	return func(c *Client) {
@@ -62,60 +111,81 @@ type ProcessResult struct {
 	Model            string
 	TokensUsed       int
 	ProcessingTimeMs int
+	// Refused is set when the model refused the request, for ProcessRequest
+	// both the original prompt and the rephrased retry; Content then holds
+	// the refusal text.
+	Refused bool
+	// FinishReason is the provider's reason the model stopped generating
+	// the returned content, e.g. stop or length.
+	FinishReason string
+	// Cached is set when the result was served from the cache. TokensUsed is
+	// then 0, since no provider call was made.
+	Cached bool
 }
 
 func NewClient(apiKey string, storageService storage.Storage, opts ...ClientOption) *Client {
 	client := &Client{
-		openAI:      openai.NewClient(apiKey),
 		storage:     storageService,
 		model:       openai.GPT4o,
 		imageDetail: openai.ImageURLDetailAuto,
 		timeout:     60 * time.Second,
+		maxTokens:   defaultMaxTokens,
+		prompts:     DefaultPrompts(),
+
+		maxFileBytes: validation.DefaultMaxFileSize,
+		presignTTL:   10 * time.Minute,
+
+		maxAttempts:    defaultMaxAttempts,
+		retryBaseDelay: defaultRetryBaseDelay,
+
+		cache: NoopCache{},
 	}
 	for _, opt := range opts {
 		opt(client)
 	}
+	if client.provider == nil {
+		client.provider = newOpenAIProvider(apiKey, client.baseURL)
+	}
 	return client
 }
 
-func (c *Client) ProcessRequest(ctx context.Context, textQuery string, fileKeys []string) (*ProcessResult, error) {
+// ProcessRequest sends the query and files to the model provider. opts
+// override the client's image detail and max tokens for this call only.
+func (c *Client) ProcessRequest(ctx context.Context, textQuery string, fileKeys []string, opts ...RequestOptions) (*ProcessResult, error) {
+	ctx, span := c.startSpan(ctx, "gpt.ProcessRequest", len(fileKeys))
+	result, err := c.processRequest(ctx, textQuery, fileKeys, c.resolve(opts))
+	endSpan(span, result, err)
+	return result, err
+}
+
+func (c *Client) processRequest(ctx context.Context, textQuery string, fileKeys []string, opts RequestOptions) (*ProcessResult, error) {
 	start := time.Now()
 
-	reqCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	reqCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
 	defer cancel()
 
+	systemPrompt, err := c.systemPrompt(PromptGPTSystem, opts)
+	if err != nil {
+		return nil, err
+	}
 	messages := []openai.ChatCompletionMessage{
-		{
-			Role: openai.ChatMessageRoleSystem,
-			Content: "You are an expert assistant for analyzing ECG/EKG (electrocardiogram) images. " +
-				"You will receive an image of an ECG recording. " +
-				"Your task is to describe what you observe in Russian language.\n\n" +
-				"Provide a structured analysis in Russian:\n" +
-				"1. Качество изображения: четкость, наличие артефактов, видимость отведений и калибровки\n" +
-				"2. Ритм: регулярный/нерегулярный, приблизительная ЧСС если видна разметка\n" +
-				"3. Зубцы и интервалы: P, QRS, T — форма, амплитуда, длительность\n" +
-				"4. Сегменты: ST-сегмент, PR-интервал, QT-интервал\n" +
-				"5. Особенности: отклонения от нормального синусового ритма\n\n" +
-				"This is a technical image analysis task for educational purposes. " +
-				"Describe what you observe without making diagnostic conclusions. " +
-				"If you cannot see certain details or measurements, state that clearly.",
-		},
+		{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
 	}
 
-	var content []openai.ChatMessagePart
+	// Files are read up front because their contents are part of the cache key.
+	files := c.loadFiles(reqCtx, fileKeys)
 
-	// Add images FIRST, then text query (OpenAI recommends this order)
-	for _, key := range fileKeys {
-		filePart, err := c.createMessagePartFromFile(reqCtx, key)
-		if err != nil {
-			slog.ErrorContext(ctx, "Failed to process file", "key", key, "error", err)
-			continue
-		}
-		if filePart != nil {
-			content = append(content, *filePart)
+	var cacheKeyStr string
+	if c.cacheTTL > 0 && !opts.NoCache {
+		cacheKeyStr = cacheKey(opts.Model, systemPrompt, textQuery, opts, files)
+		if cached := c.cachedResult(ctx, cacheKeyStr, start); cached != nil {
+			return cached, nil
 		}
 	}
 
+	// Add images FIRST, then text query (OpenAI recommends this order)
+	content := c.messageParts(reqCtx, files, opts.ImageDetail)
+
 	if textQuery != "" {
 		content = append(content, openai.ChatMessagePart{
 			Type: openai.ChatMessagePartTypeText,
@@ -132,66 +202,234 @@ func (c *Client) ProcessRequest(ctx context.Context, textQuery string, fileKeys
 		MultiContent: content,
 	})
 
-	slog.InfoContext(ctx, "Sending request to OpenAI",
-		"model", c.model,
+	slog.InfoContext(ctx, "Sending request to model provider",
+		"provider", c.provider.Name(),
+		"model", opts.Model,
 		"files", len(fileKeys),
-		"content_parts", len(content))
+		"content_parts", len(content),
+		"max_tokens", opts.MaxTokens)
 
-	resp, err := c.openAI.CreateChatCompletion(reqCtx, openai.ChatCompletionRequest{
-		Model:     c.model,
+	chatReq := openai.ChatCompletionRequest{
+		Model:     opts.Model,
 		Messages:  messages,
-		MaxTokens: 2000,
-	})
+		MaxTokens: opts.MaxTokens,
+	}
+	if opts.Structured {
+		chatReq.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		}
+	}
+	resp, err := c.createChatCompletion(reqCtx, chatReq)
 	if err != nil {
-		return nil, classifyOpenAIError(reqCtx, err, c.timeout)
+		return nil, classifyError(reqCtx, c.provider.Name(), err, opts.Timeout)
 	}
 
 	if len(resp.Choices) == 0 {
-		slog.ErrorContext(ctx, "OpenAI API returned empty choices",
+		slog.ErrorContext(ctx, "Model provider returned empty choices",
+			"provider", c.provider.Name(),
 			"model", resp.Model,
 			"tokens_used", resp.Usage.TotalTokens,
 			"response_id", resp.ID)
-		return nil, errors.New("no response from OpenAI")
+		return nil, fmt.Errorf("no response from %s", c.provider.Name())
 	}
 
 	responseContent := resp.Choices[0].Message.Content
+	tokensUsed := resp.Usage.TotalTokens
+
+	// The medical framing of the default prompt sometimes trips the content
+	// filter. Retry once with the more neutral rephrase prompt.
+	filtered := resp.Choices[0].FinishReason == openai.FinishReasonContentFilter
+	if IsRefusal(responseContent) || filtered {
+		slog.WarnContext(ctx, "Model returned refusal, retrying with rephrased prompt",
+			"tokens", resp.Usage.TotalTokens, "finish_reason", resp.Choices[0].FinishReason)
+
+		retryPrompt, err := c.systemPrompt(PromptGPTRetry, opts)
+		if err != nil {
+			return nil, err
+		}
+		chatReq.Messages[0].Content = retryPrompt
+		retry, err := c.createChatCompletion(reqCtx, chatReq)
+		if err != nil {
+			return nil, classifyError(reqCtx, c.provider.Name(), err, opts.Timeout)
+		}
+		if len(retry.Choices) == 0 {
+			return nil, fmt.Errorf("no response from %s", c.provider.Name())
+		}
+		resp = retry
+		responseContent = retry.Choices[0].Message.Content
+		tokensUsed += retry.Usage.TotalTokens
+		if IsRefusal(responseContent) {
+			slog.WarnContext(ctx, "Model refused rephrased prompt", "tokens", retry.Usage.TotalTokens)
+		}
+	}
 
-	if IsRefusal(responseContent) {
-		slog.WarnContext(ctx, "OpenAI returned refusal", "tokens", resp.Usage.TotalTokens, "finish_reason", resp.Choices[0].FinishReason)
+	// A refusal is an answer callers handle; anything else must be complete
+	// and non-blank to be stored.
+	refused := IsRefusal(responseContent)
+	if !refused {
+		if err := completionError(c.provider.Name(), resp.Choices[0], opts.MaxTokens); err != nil {
+			slog.WarnContext(ctx, "Model response unusable",
+				"provider", c.provider.Name(),
+				"model", resp.Model,
+				"finish_reason", resp.Choices[0].FinishReason,
+				"response_len", len(responseContent),
+				"error", err)
+			return nil, err
+		}
 	}
 
-	slog.InfoContext(ctx, "OpenAI response received",
+	slog.InfoContext(ctx, "Model response received",
+		"provider", c.provider.Name(),
 		"model", resp.Model,
 		"tokens", resp.Usage.TotalTokens,
 		"response_len", len(responseContent))
 
+	if opts.Structured {
+		if _, ok := models.ParseGPTStructuredResult(responseContent); !ok {
+			// Keep the raw text; readers fall back to ExtractConclusion.
+			slog.WarnContext(ctx, "Model returned invalid structured output, keeping raw response",
+				"response_len", len(responseContent))
+		}
+	}
+
 	processingTime := time.Since(start)
 
-	return &ProcessResult{
-		Content:          resp.Choices[0].Message.Content,
+	result := &ProcessResult{
+		Content:          responseContent,
 		Model:            resp.Model,
-		TokensUsed:       resp.Usage.TotalTokens,
+		TokensUsed:       tokensUsed,
 		ProcessingTimeMs: int(processingTime.Milliseconds()),
-	}, nil
+		Refused:          refused,
+		FinishReason:     string(resp.Choices[0].FinishReason),
+	}
+	// Refusals are not cached so a repeat gets a fresh attempt.
+	if cacheKeyStr != "" && !refused {
+		if err := c.cache.Set(ctx, cacheKeyStr, result, c.cacheTTL); err != nil {
+			slog.WarnContext(ctx, "Failed to cache GPT result", "error", err)
+		}
+	}
+	return result, nil
+}
+
+// cachedResult returns the cached result for key marked as Cached, or nil
+// on a miss. Cache errors are logged and treated as misses.
+func (c *Client) cachedResult(ctx context.Context, key string, start time.Time) *ProcessResult {
+	result, ok, err := c.cache.Get(ctx, key)
+	if err != nil {
+		slog.WarnContext(ctx, "GPT cache lookup failed, calling the provider", "error", err)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	slog.InfoContext(ctx, "GPT result served from cache", "model", result.Model)
+	result.Cached = true
+	result.TokensUsed = 0
+	result.ProcessingTimeMs = int(time.Since(start).Milliseconds())
+	return result
+}
+
+// systemPrompt renders the named system prompt, appending the JSON output
+// instructions in structured mode.
+func (c *Client) systemPrompt(name string, opts RequestOptions) (string, error) {
+	prompt, err := c.prompts.Render(name, nil)
+	if err != nil {
+		return "", err
+	}
+	if opts.Structured {
+		jsonPrompt, err := c.prompts.Render(PromptGPTJSON, nil)
+		if err != nil {
+			return "", err
+		}
+		prompt += "\n\n" + jsonPrompt
+	}
+	return prompt, nil
+}
+
+// loadedFile is a stored file read into memory for a request.
+type loadedFile struct {
+	key         string
+	data        []byte
+	contentType string
 }
 
-func (c *Client) createMessagePartFromFile(ctx context.Context, key string) (*openai.ChatMessagePart, error) {
+// loadFiles reads fileKeys concurrently, at most maxFileConcurrency at a
+// time. Files that fail to load are logged and skipped; the rest keep the
+// order of fileKeys.
+func (c *Client) loadFiles(ctx context.Context, fileKeys []string) []loadedFile {
+	loaded := make([]*loadedFile, len(fileKeys))
+	var g errgroup.Group
+	g.SetLimit(maxFileConcurrency)
+	for i, key := range fileKeys {
+		g.Go(func() error {
+			f, err := c.loadFile(ctx, key)
+			if err != nil {
+				slog.ErrorContext(ctx, "Failed to process file", "key", key, "error", err)
+				return nil
+			}
+			loaded[i] = &f
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	files := make([]loadedFile, 0, len(fileKeys))
+	for _, f := range loaded {
+		if f != nil {
+			files = append(files, *f)
+		}
+	}
+	return files
+}
+
+// messageParts converts files into chat message parts concurrently, at most
+// maxFileConcurrency at a time, keeping the order of files. Failures are
+// logged and skipped.
+func (c *Client) messageParts(ctx context.Context, files []loadedFile, detail openai.ImageURLDetail) []openai.ChatMessagePart {
+	parts := make([]*openai.ChatMessagePart, len(files))
+	var g errgroup.Group
+	g.SetLimit(maxFileConcurrency)
+	for i, f := range files {
+		g.Go(func() error {
+			part, err := c.messagePart(ctx, f, detail)
+			if err != nil {
+				slog.ErrorContext(ctx, "Failed to process file", "key", f.key, "error", err)
+				return nil
+			}
+			parts[i] = part
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	content := make([]openai.ChatMessagePart, 0, len(files)+1)
+	for _, p := range parts {
+		if p != nil {
+			content = append(content, *p)
+		}
+	}
+	return content
+}
+
+// loadFile reads a stored file, enforcing maxFileBytes and sniffing a
+// missing or generic content type.
+func (c *Client) loadFile(ctx context.Context, key string) (loadedFile, error) {
 	reader, contentType, err := c.storage.GetFile(ctx, key)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get file from storage: %w", err)
+		return loadedFile{}, fmt.Errorf("failed to get file from storage: %w", err)
 	}
 	defer func() { _ = reader.Close() }()
 
-	const maxFileSize = 20 * 1024 * 1024 // 20 MB
-	data, err := io.ReadAll(io.LimitReader(reader, maxFileSize+1))
+	data, err := io.ReadAll(io.LimitReader(reader, c.maxFileBytes+1))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file data: %w", err)
+		return loadedFile{}, fmt.Errorf("failed to read file data: %w", err)
 	}
 	if len(data) == 0 {
-		return nil, fmt.Errorf("file is empty: %s", key)
+		return loadedFile{}, fmt.Errorf("file is empty: %s", key)
 	}
-	if len(data) > maxFileSize {
-		return nil, fmt.Errorf("file too large: %s (%d bytes, max %d)", key, len(data), maxFileSize)
+	if int64(len(data)) > c.maxFileBytes {
+		slog.WarnContext(ctx, "Stored file exceeds size limit", "key", key, "max_bytes", c.maxFileBytes)
+		return loadedFile{}, fmt.Errorf("file too large: %s (over %d bytes)", key, c.maxFileBytes)
 	}
 
 	// Detect content type from file header if not provided or generic
@@ -203,9 +441,15 @@ func (c *Client) createMessagePartFromFile(ctx context.Context, key string) (*op
 			slog.DebugContext(ctx, "Detected content type", "key", key, "detected_type", contentType)
 		}
 	}
+	return loadedFile{key: key, data: data, contentType: contentType}, nil
+}
+
+// messagePart converts a loaded file into a chat message part.
+func (c *Client) messagePart(ctx context.Context, f loadedFile, detail openai.ImageURLDetail) (*openai.ChatMessagePart, error) {
+	key, data, contentType := f.key, f.data, f.contentType
 
 	if isImageType(contentType) {
-		return c.buildImagePart(ctx, key, data, contentType)
+		return c.buildImagePart(ctx, key, data, contentType, detail)
 	}
 
 	if isTextType(contentType) {
@@ -215,6 +459,21 @@ func (c *Client) createMessagePartFromFile(ctx context.Context, key string) (*op
 		}, nil
 	}
 
+	if contentType == contentTypePDF {
+		text, truncated, err := extractPDFText(data)
+		if err == nil {
+			slog.InfoContext(ctx, "Extracted text from PDF", "key", key, "text_len", len(text), "truncated", truncated)
+			if truncated {
+				text += "\n[...truncated]"
+			}
+			return &openai.ChatMessagePart{
+				Type: openai.ChatMessagePartTypeText,
+				Text: fmt.Sprintf("PDF content (%s):\n%s", key, text),
+			}, nil
+		}
+		slog.WarnContext(ctx, "Failed to extract PDF text, sending placeholder", "key", key, "error", err)
+	}
+
 	return &openai.ChatMessagePart{
 		Type: openai.ChatMessagePartTypeText,
 		Text: fmt.Sprintf("File: %s (type: %s, size: %d bytes) - Content not directly readable", key, contentType, len(data)),
@@ -222,21 +481,41 @@ func (c *Client) createMessagePartFromFile(ctx context.Context, key string) (*op
 }
 
 // buildImagePart creates an image message part, preferring presigned URL over base64.
-func (c *Client) buildImagePart(ctx context.Context, key string, data []byte, contentType string) (*openai.ChatMessagePart, error) {
+// Images larger than maxImageDimension are downscaled and always sent as base64,
+// since the presigned URL would point at the original.
+func (c *Client) buildImagePart(ctx context.Context, key string, data []byte, contentType string, detail openai.ImageURLDetail) (*openai.ChatMessagePart, error) {
+	resized, ok, err := downscaleImage(data, c.maxImageDimension)
+	switch {
+	case err != nil:
+		slog.WarnContext(ctx, "Failed to downscale image, sending original", "key", key, "error", err)
+	case ok:
+		slog.InfoContext(ctx, "Downscaled image",
+			"key", key,
+			"original_size", len(data),
+			"resized_size", len(resized),
+			"max_dimension", c.maxImageDimension)
+		return base64ImagePart(ctx, key, resized, "image/jpeg", detail)
+	}
+
 	// Try presigned URL first — avoids base64 overhead
-	presignedURL, err := c.storage.GetPresignedURL(ctx, key, 10*time.Minute)
+	presignedURL, err := c.storage.GetPresignedURL(ctx, key, c.presignTTL)
 	if err == nil && !isLocalhostURL(presignedURL) {
-		slog.InfoContext(ctx, "Using presigned URL for image", "key", key, "content_type", contentType, "detail", c.imageDetail)
+		slog.InfoContext(ctx, "Using presigned URL for image", "key", key, "content_type", contentType, "detail", detail)
 		return &openai.ChatMessagePart{
 			Type: openai.ChatMessagePartTypeImageURL,
 			ImageURL: &openai.ChatMessageImageURL{
 				URL:    presignedURL,
-				Detail: c.imageDetail,
+				Detail: detail,
 			},
 		}, nil
 	}
 
-	// Fall back to base64 encoding
+	return base64ImagePart(ctx, key, data, contentType, detail)
+}
+
+// base64ImagePart inlines image data into the message as a data URL.
+func base64ImagePart(ctx context.Context, key string, data []byte, contentType string, detail openai.ImageURLDetail) (*openai.ChatMessagePart, error) {
+	// OpenAI rejects inline images above 20MB regardless of MAX_IMAGE_BYTES.
 	const maxBase64Size = 20 * 1024 * 1024
 	estimatedBase64Size := (len(data) * 4) / 3
 	if estimatedBase64Size > maxBase64Size {
@@ -250,19 +529,26 @@ func (c *Client) buildImagePart(ctx context.Context, key string, data []byte, co
 		"key", key,
 		"content_type", contentType,
 		"original_size", len(data),
-		"detail", c.imageDetail)
+		"detail", detail)
 
 	return &openai.ChatMessagePart{
 		Type: openai.ChatMessagePartTypeImageURL,
 		ImageURL: &openai.ChatMessageImageURL{
 			URL:    imageURL,
-			Detail: c.imageDetail,
+			Detail: detail,
 		},
 	}, nil
 }
 
 // ProcessStructuredECG calls GPT with temperature=0 and custom prompts for structured ECG measurement.
 func (c *Client) ProcessStructuredECG(ctx context.Context, fileKeys []string, systemPrompt, userPrompt string) (*ProcessResult, error) {
+	ctx, span := c.startSpan(ctx, "gpt.ProcessStructuredECG", len(fileKeys))
+	result, err := c.processStructuredECG(ctx, fileKeys, systemPrompt, userPrompt)
+	endSpan(span, result, err)
+	return result, err
+}
+
+func (c *Client) processStructuredECG(ctx context.Context, fileKeys []string, systemPrompt, userPrompt string) (*ProcessResult, error) {
 	start := time.Now()
 
 	reqCtx, cancel := context.WithTimeout(ctx, c.timeout)
@@ -272,17 +558,7 @@ func (c *Client) ProcessStructuredECG(ctx context.Context, fileKeys []string, sy
 		{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
 	}
 
-	var content []openai.ChatMessagePart
-	for _, key := range fileKeys {
-		filePart, err := c.createMessagePartFromFile(reqCtx, key)
-		if err != nil {
-			slog.ErrorContext(ctx, "Failed to process file for structured ECG", "key", key, "error", err)
-			continue
-		}
-		if filePart != nil {
-			content = append(content, *filePart)
-		}
-	}
+	content := c.messageParts(reqCtx, c.loadFiles(reqCtx, fileKeys), c.imageDetail)
 
 	content = append(content, openai.ChatMessagePart{
 		Type: openai.ChatMessagePartTypeText,
@@ -294,11 +570,11 @@ func (c *Client) ProcessStructuredECG(ctx context.Context, fileKeys []string, sy
 		MultiContent: content,
 	})
 
-	slog.InfoContext(ctx, "Sending structured ECG request to OpenAI",
-		"model", c.model, "files", len(fileKeys))
+	slog.InfoContext(ctx, "Sending structured ECG request to model provider",
+		"provider", c.provider.Name(), "model", c.model, "files", len(fileKeys))
 
 	temp := float32(0.0)
-	resp, err := c.openAI.CreateChatCompletion(reqCtx, openai.ChatCompletionRequest{
+	resp, err := c.createChatCompletion(reqCtx, openai.ChatCompletionRequest{
 		Model:       c.model,
 		Messages:    messages,
 		MaxTokens:   4000,
@@ -308,16 +584,17 @@ func (c *Client) ProcessStructuredECG(ctx context.Context, fileKeys []string, sy
 		},
 	})
 	if err != nil {
-		return nil, classifyOpenAIError(reqCtx, err, c.timeout)
+		return nil, classifyError(reqCtx, c.provider.Name(), err, c.timeout)
 	}
 
 	if len(resp.Choices) == 0 {
-		return nil, errors.New("no response from OpenAI")
+		return nil, fmt.Errorf("no response from %s", c.provider.Name())
 	}
 
 	responseContent := resp.Choices[0].Message.Content
-	if IsRefusal(responseContent) {
-		slog.WarnContext(ctx, "OpenAI returned refusal for structured ECG", "tokens", resp.Usage.TotalTokens)
+	refused := IsRefusal(responseContent)
+	if refused {
+		slog.WarnContext(ctx, "Model returned refusal for structured ECG", "tokens", resp.Usage.TotalTokens)
 	}
 
 	slog.InfoContext(ctx, "Structured ECG response received",
@@ -328,47 +605,83 @@ func (c *Client) ProcessStructuredECG(ctx context.Context, fileKeys []string, sy
 		Model:            resp.Model,
 		TokensUsed:       resp.Usage.TotalTokens,
 		ProcessingTimeMs: int(time.Since(start).Milliseconds()),
+		Refused:          refused,
+		FinishReason:     string(resp.Choices[0].FinishReason),
 	}, nil
 }
 
-// isLocalhostURL checks whether a URL points to a local address that OpenAI cannot reach.
+// isLocalhostURL checks whether a URL points to a local address that the provider cannot reach.
 func isLocalhostURL(u string) bool {
 	return strings.Contains(u, "localhost") || strings.Contains(u, "127.0.0.1") || strings.Contains(u, "::1")
 }
 
-// classifyOpenAIError wraps an OpenAI API error with a descriptive message based on its type.
-func classifyOpenAIError(reqCtx context.Context, err error, timeout time.Duration) error {
-	errStr := err.Error()
+// errorClass groups provider API errors by the keywords in their text. The
+// keywords cover the error types of every Provider.
+type errorClass struct {
+	keywords []string
+	message  string
+	hint     string
+	code     models.ErrorCode
+}
 
-	type errClass struct {
-		keywords []string
-		message  string
-		hint     string
-	}
+var errorClasses = []errorClass{
+	{[]string{"insufficient_quota", "quota", "credit balance"}, "API quota exceeded", "Check the account balance and usage limits", models.ErrorCodeRateLimited},
+	{[]string{"invalid_api_key", "authentication"}, "API authentication failed", "Check OPENAI_API_KEY or ANTHROPIC_API_KEY for the configured AI_PROVIDER", models.ErrorCodeAuth},
+	{[]string{"rate_limit"}, "API rate limit exceeded", "Too many requests, please retry later", models.ErrorCodeRateLimited},
+	{[]string{"content_filter", "safety"}, "API content filtered", "Request was filtered by content moderation", models.ErrorCodeContentFiltered},
+	{[]string{"invalid_image", "image_parse_error", "Could not process image"}, "API rejected the image", "Check the image format and size", models.ErrorCodeInvalidImage},
+}
 
-	classes := []errClass{
-		{[]string{"insufficient_quota", "quota"}, "OpenAI API quota exceeded", "Check your OpenAI account balance and usage limits"},
-		{[]string{"invalid_api_key", "authentication"}, "OpenAI API authentication failed", "Check OPENAI_API_KEY environment variable"},
-		{[]string{"rate_limit"}, "OpenAI API rate limit exceeded", "Too many requests, please retry later"},
-		{[]string{"content_filter", "safety"}, "OpenAI API content filtered", "Request was filtered by content moderation"},
-	}
+// classifyError wraps a provider API error with a descriptive message based
+// on its type.
+func classifyError(reqCtx context.Context, provider string, err error, timeout time.Duration) error {
+	errStr := err.Error()
 
-	for _, c := range classes {
+	for _, c := range errorClasses {
 		for _, kw := range c.keywords {
 			if strings.Contains(errStr, kw) {
-				slog.Error(c.message, "error", err, "hint", c.hint) //nolint:sloglint // message varies by error class
-				return fmt.Errorf("%s: %w", c.message, err)
+				message := provider + " " + c.message
+				slog.Error(message, "error", err, "hint", c.hint) //nolint:sloglint // message varies by error class
+				return fmt.Errorf("%s: %w", message, err)
 			}
 		}
 	}
 
 	if errors.Is(reqCtx.Err(), context.DeadlineExceeded) {
-		slog.Error("OpenAI API request timeout", "error", err, "timeout", timeout)
-		return fmt.Errorf("openai API request timeout: %w", err)
+		slog.Error("Model provider request timeout", "provider", provider, "error", err, "timeout", timeout)
+		return fmt.Errorf("%s API request timeout: %w", strings.ToLower(provider), err)
 	}
 
-	slog.Error("OpenAI API error", "error", err)
-	return fmt.Errorf("openai API error: %w", err)
+	slog.Error("Model provider API error", "provider", provider, "error", err)
+	return fmt.Errorf("%s API error: %w", strings.ToLower(provider), err)
+}
+
+// ErrorCode maps an error from ProcessRequest or ProcessStructuredECG to the
+// category reported to clients. Unrecognized errors are
+// models.ErrorCodeInternal.
+func ErrorCode(err error) models.ErrorCode {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrContentFiltered):
+		return models.ErrorCodeContentFiltered
+	case errors.Is(err, ErrTruncated), errors.Is(err, ErrEmptyResponse):
+		return models.ErrorCodeInternal
+	case errors.Is(err, context.DeadlineExceeded):
+		return models.ErrorCodeTimeout
+	}
+	errStr := err.Error()
+	for _, c := range errorClasses {
+		for _, kw := range c.keywords {
+			if strings.Contains(errStr, kw) {
+				return c.code
+			}
+		}
+	}
+	if strings.Contains(errStr, "request timeout") {
+		return models.ErrorCodeTimeout
+	}
+	return models.ErrorCodeInternal
 }
 
 func isImageType(contentType string) bool {
@@ -0,0 +1,45 @@
+package gpt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+func TestErrorCode_MapsProviderErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want models.ErrorCode
+	}{
+		{"quota", errors.New("error, status code: 429, message: You exceeded your current quota (insufficient_quota)"), models.ErrorCodeRateLimited},
+		{"rate limit", errors.New("error, status code: 429, type: requests, code: rate_limit_exceeded"), models.ErrorCodeRateLimited},
+		{"anthropic credit", errors.New("anthropic: Your credit balance is too low"), models.ErrorCodeRateLimited},
+		{"invalid key", errors.New("error, status code: 401, code: invalid_api_key"), models.ErrorCodeAuth},
+		{"anthropic auth", errors.New("anthropic: authentication_error: invalid x-api-key"), models.ErrorCodeAuth},
+		{"content filter", errors.New("error, status code: 400, code: content_filter"), models.ErrorCodeContentFiltered},
+		{"invalid image", errors.New("error, status code: 400, code: invalid_image_format"), models.ErrorCodeInvalidImage},
+		{"image parse", errors.New("error, status code: 400, code: image_parse_error"), models.ErrorCodeInvalidImage},
+		{"deadline", fmt.Errorf("openai API request timeout: %w", context.DeadlineExceeded), models.ErrorCodeTimeout},
+		{"filtered completion", fmt.Errorf("%w (OpenAI)", ErrContentFiltered), models.ErrorCodeContentFiltered},
+		{"truncated", fmt.Errorf("%w: OpenAI stopped after max_tokens=10", ErrTruncated), models.ErrorCodeInternal},
+		{"unknown", errors.New("openai API error: unexpected EOF"), models.ErrorCodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCode(fmt.Errorf("gpt processing failed: %w", tt.err)); got != tt.want {
+				t.Errorf("ErrorCode(%q) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorCode_MatchesClassifiedErrors(t *testing.T) {
+	err := classifyError(context.Background(), "OpenAI", errors.New("code: rate_limit_exceeded"), 0)
+	if got := ErrorCode(err); got != models.ErrorCodeRateLimited {
+		t.Errorf("expected %q for a classified rate limit error, got %q", models.ErrorCodeRateLimited, got)
+	}
+}
@@ -0,0 +1,39 @@
+package gpt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Errors for completions that arrived but carry no usable answer. They are
+// returned instead of a ProcessResult so the request is not stored as
+// completed with an empty or partial body.
+var (
+	// ErrEmptyResponse means the model answered with blank content.
+	ErrEmptyResponse = errors.New("model returned an empty response")
+	// ErrTruncated means the answer was cut off at the completion token
+	// limit; raising max tokens usually helps.
+	ErrTruncated = errors.New("model response truncated at the token limit")
+	// ErrContentFiltered means the provider's content filter withheld the
+	// answer. Unlike a refusal, the model produced no explanation.
+	ErrContentFiltered = errors.New("model response blocked by the content filter")
+)
+
+// completionError checks the choice that ends a ProcessRequest call and
+// returns nil when its content can be stored.
+func completionError(provider string, choice openai.ChatCompletionChoice, maxTokens int) error {
+	switch choice.FinishReason {
+	case openai.FinishReasonLength:
+		return fmt.Errorf("%w: %s stopped after max_tokens=%d; raise GPT_MAX_TOKENS or the request's max_tokens",
+			ErrTruncated, provider, maxTokens)
+	case openai.FinishReasonContentFilter:
+		return fmt.Errorf("%w (%s)", ErrContentFiltered, provider)
+	}
+	if strings.TrimSpace(choice.Message.Content) == "" {
+		return fmt.Errorf("%w (%s, finish_reason %q)", ErrEmptyResponse, provider, choice.FinishReason)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package gpt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	truncatedBody = `{"id":"chatcmpl-2","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"  \n"},"finish_reason":"length"}],"usage":{"total_tokens":9}}`
+	blankBody     = `{"id":"chatcmpl-3","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":" \t\n"},"finish_reason":"stop"}],"usage":{"total_tokens":3}}`
+	filteredBody  = `{"id":"chatcmpl-4","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":""},"finish_reason":"content_filter"}],"usage":{"total_tokens":4}}`
+)
+
+func TestProcessRequest_FailsOnTruncatedEmptyResponse(t *testing.T) {
+	transport := &stubTransport{responses: []stubResponse{
+		{http.StatusOK, truncatedBody},
+	}}
+	c := newStubClient(transport, WithRetry(1, time.Millisecond), WithMaxTokens(500))
+
+	res, err := c.ProcessRequest(context.Background(), "hello", nil)
+	if !errors.Is(err, ErrTruncated) {
+		t.Fatalf("expected ErrTruncated, got result %+v, err %v", res, err)
+	}
+	if !strings.Contains(err.Error(), "max_tokens=500") {
+		t.Fatalf("expected the error to name the token limit, got %q", err)
+	}
+	if got := transport.calls.Load(); got != 1 {
+		t.Fatalf("expected no rephrase retry for truncation, got %d calls", got)
+	}
+}
+
+func TestProcessRequest_FailsOnBlankResponse(t *testing.T) {
+	transport := &stubTransport{responses: []stubResponse{
+		{http.StatusOK, blankBody},
+	}}
+	c := newStubClient(transport, WithRetry(1, time.Millisecond))
+
+	_, err := c.ProcessRequest(context.Background(), "hello", nil)
+	if !errors.Is(err, ErrEmptyResponse) {
+		t.Fatalf("expected ErrEmptyResponse, got %v", err)
+	}
+}
+
+func TestProcessRequest_RephrasesAfterContentFilter(t *testing.T) {
+	transport := &stubTransport{responses: []stubResponse{
+		{http.StatusOK, filteredBody},
+		{http.StatusOK, successBody},
+	}}
+	c := newStubClient(transport, WithRetry(1, time.Millisecond))
+
+	res, err := c.ProcessRequest(context.Background(), "hello", nil)
+	if err != nil {
+		t.Fatalf("ProcessRequest error: %v", err)
+	}
+	if res.Content != "ok" {
+		t.Fatalf("expected rephrased answer, got %+v", res)
+	}
+}
+
+func TestProcessRequest_FailsOnPersistentContentFilter(t *testing.T) {
+	transport := &stubTransport{responses: []stubResponse{
+		{http.StatusOK, filteredBody},
+	}}
+	c := newStubClient(transport, WithRetry(1, time.Millisecond))
+
+	_, err := c.ProcessRequest(context.Background(), "hello", nil)
+	if !errors.Is(err, ErrContentFiltered) {
+		t.Fatalf("expected ErrContentFiltered, got %v", err)
+	}
+	if errors.Is(err, ErrTruncated) {
+		t.Fatalf("content filter must not be reported as truncation: %v", err)
+	}
+	if got := transport.calls.Load(); got != 2 {
+		t.Fatalf("expected one rephrase retry (2 calls), got %d", got)
+	}
+}
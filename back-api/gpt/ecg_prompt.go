@@ -7,57 +7,26 @@ import (
 	"strings"
 )
 
-// BuildECGMeasurementPrompt returns system and user messages for structured ECG measurement.
-func BuildECGMeasurementPrompt(paperSpeedMMS float64) (system, user string) {
-	system = `Ты эксперт по измерению ЭКГ на бумажных плёнках. Твоя задача: точно посчитать количество МАЛЫХ клеток (1мм) для амплитуд зубцов и интервалов. Возвращай только JSON.`
-
-	schema := ecgSchemaTemplate()
-	schemaJSON, _ := json.MarshalIndent(schema, "", "  ")
-
-	user = fmt.Sprintf(`ЗАДАЧА: Измерь ЭКГ по сетке. Верни ТОЛЬКО JSON строго по схеме.
-
-СЕТКА ЭКГ:
-- Малая клетка = 1мм (тонкие линии)
-- Большая клетка = 5 малых = 5мм (толстые линии)
-- Калибровочный импульс (обычно слева): 10мм = 1мВ
-
-КАК ИЗМЕРЯТЬ:
-1. Найди изоэлектрическую линию (baseline) — горизонтальный участок между зубцами T и P
-2. R_up_sq: количество МАЛЫХ клеток от baseline ВВЕРХ до вершины зубца R (всегда положительное число)
-3. S_down_sq: количество МАЛЫХ клеток от baseline ВНИЗ до дна зубца S (всегда отрицательное число)
-4. Измеряй 3-5 последовательных комплексов в каждом видимом отведении
-5. Разрешены половинки клетки (0.5)
-6. Если отведение не видно или не удается измерить — верни null (НЕ 0)
-
-ТИПИЧНЫЕ ЗНАЧЕНИЯ (для самопроверки):
-- R в V1: обычно 1-6 малых клеток (маленький зубец)
-- S в V1: обычно 8-20 малых клеток (глубокий зубец, отрицательный)
-- R в V5-V6: обычно 10-25 малых клеток (высокий зубец)
-- S в V5-V6: обычно 0-5 малых клеток (маленький или отсутствует)
-- R нарастает от V1 к V4-V5, затем уменьшается к V6
-- S уменьшается от V1 к V6
-- Если все отведения показывают одинаковую амплитуду — скорее всего ошибка измерения
-
-ИНТЕРВАЛЫ:
-- QRS: ширина комплекса QRS в малых клетках (обычно 2-4 клетки)
-- RR: расстояние между двумя соседними R-зубцами в малых клетках
-
-EXTRAS:
-- SV1_sq: глубина S в V1 (отрицательное число)
-- RV5_sq, RV6_sq: высота R в V5 и V6
-- RaVL_sq: высота R в aVL
-- SV3_sq, SV4_sq: глубина S в V3 и V4
-- S_deepest_sq: самый глубокий S среди всех грудных отведений
-
-Скорость плёнки: %.0f мм/с (если видишь другую калибровку — укажи в calibration).
-HR_bpm: частота сердечных сокращений, если можно определить.
-
-СХЕМА:
-%s
-
-Верни один JSON.`, paperSpeedMMS, string(schemaJSON))
+// ECGMeasurementPrompt renders the system and user messages for structured
+// ECG measurement.
+func (p *PromptSet) ECGMeasurementPrompt(paperSpeedMMS float64) (system, user string, err error) {
+	system, err = p.Render(PromptECGSystem, nil)
+	if err != nil {
+		return "", "", err
+	}
 
-	return system, user
+	schemaJSON, err := json.MarshalIndent(ecgSchemaTemplate(), "", "  ")
+	if err != nil {
+		return "", "", fmt.Errorf("marshal ECG schema: %w", err)
+	}
+	user, err = p.Render(PromptECGUser, ECGPromptData{
+		PaperSpeedMMS: paperSpeedMMS,
+		Schema:        string(schemaJSON),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return system, user, nil
 }
 
 func ecgSchemaTemplate() map[string]any {
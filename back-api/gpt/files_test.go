@@ -0,0 +1,66 @@
+package gpt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/mock"
+
+	storagemocks "github.com/fedutinova/smartheart/back-api/storage/mocks"
+)
+
+// delayedStorage returns a mock storage serving "content of <key>" as text
+// after delay, and failing for keys listed in failing.
+func delayedStorage(tb testing.TB, delay time.Duration, failing ...string) *storagemocks.MockStorage {
+	store := storagemocks.NewMockStorage(tb)
+	store.EXPECT().GetFile(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, key string) (io.ReadCloser, string, error) {
+			time.Sleep(delay)
+			for _, f := range failing {
+				if key == f {
+					return nil, "", errors.New("not found")
+				}
+			}
+			return io.NopCloser(strings.NewReader("content of " + key)), "text/plain", nil
+		})
+	return store
+}
+
+func TestMessageParts_KeepsOrderAndSkipsFailures(t *testing.T) {
+	c := NewClient("test-key", delayedStorage(t, time.Millisecond, "uploads/2.txt"))
+
+	keys := []string{"uploads/0.txt", "uploads/1.txt", "uploads/2.txt", "uploads/3.txt", "uploads/4.txt", "uploads/5.txt"}
+	parts := c.messageParts(context.Background(), c.loadFiles(context.Background(), keys), openai.ImageURLDetailAuto)
+
+	want := []string{"uploads/0.txt", "uploads/1.txt", "uploads/3.txt", "uploads/4.txt", "uploads/5.txt"}
+	if len(parts) != len(want) {
+		t.Fatalf("expected %d parts, got %d", len(want), len(parts))
+	}
+	for i, key := range want {
+		if !strings.Contains(parts[i].Text, "content of "+key) {
+			t.Errorf("part %d: expected content of %s, got %q", i, key, parts[i].Text)
+		}
+	}
+}
+
+func BenchmarkProcessRequest_MultipleFiles(b *testing.B) {
+	keys := make([]string, 5)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("uploads/%d.txt", i)
+	}
+	c := newStubClient(&stubTransport{responses: []stubResponse{{200, successBody}}})
+	c.storage = delayedStorage(b, 5*time.Millisecond)
+
+	b.ResetTimer()
+	for range b.N {
+		if _, err := c.ProcessRequest(context.Background(), "summarize", keys); err != nil {
+			b.Fatalf("ProcessRequest: %v", err)
+		}
+	}
+}
@@ -90,7 +90,7 @@ func simulateWork(ctx context.Context, d time.Duration) error {
 	return nil
 }
 
-func (m *MockProcessor) ProcessRequest(ctx context.Context, _ string, _ []string) (*ProcessResult, error) {
+func (m *MockProcessor) ProcessRequest(ctx context.Context, _ string, _ []string, _ ...RequestOptions) (*ProcessResult, error) {
 	done := m.trackConcurrency()
 	defer done()
 	if err := simulateWork(ctx, m.Delay); err != nil {
@@ -101,6 +101,7 @@ func (m *MockProcessor) ProcessRequest(ctx context.Context, _ string, _ []string
 		Model:            "mock",
 		TokensUsed:       100,
 		ProcessingTimeMs: int(m.Delay.Milliseconds()),
+		FinishReason:     "stop",
 	}, nil
 }
 
@@ -115,5 +116,6 @@ func (m *MockProcessor) ProcessStructuredECG(ctx context.Context, _ []string, _,
 		Model:            "mock",
 		TokensUsed:       200,
 		ProcessingTimeMs: int(m.Delay.Milliseconds()),
+		FinishReason:     "stop",
 	}, nil
 }
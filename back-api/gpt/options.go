@@ -0,0 +1,87 @@
+package gpt
+
+import (
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	defaultMaxTokens = 2000
+	// MaxCompletionTokens is the completion limit of the largest supported
+	// model, and the largest max_tokens a request may ask for.
+	MaxCompletionTokens = 16384
+	// MaxRequestTimeout caps a per-request timeout override; callers apply
+	// their own, usually lower, limit.
+	MaxRequestTimeout = 10 * time.Minute
+)
+
+// WithMaxTokens sets the default completion token limit for ProcessRequest.
+// Values outside 1..16384 are ignored.
+func WithMaxTokens(n int) ClientOption {
+	return func(c *Client) {
+		if ValidMaxTokens(n) {
+			c.maxTokens = n
+		}
+	}
+}
+
+// RequestOptions overrides client defaults for a single ProcessRequest call.
+// Zero values fall back to the client defaults.
+type RequestOptions struct {
+	ImageDetail openai.ImageURLDetail
+	MaxTokens   int
+	// Structured asks the model for a JSON object matching
+	// models.GPTStructuredResult instead of free-form text.
+	Structured bool
+	// NoCache bypasses the result cache for this call: no lookup, no store.
+	NoCache bool
+	// Model replaces the client's model for this call. Callers must restrict
+	// it to an allowlist; the client sends whatever name it is given.
+	Model string
+	// Timeout replaces the client's request timeout for this call. Values
+	// above MaxRequestTimeout are ignored.
+	Timeout time.Duration
+}
+
+// ValidImageDetail reports whether d is a detail level accepted by OpenAI.
+func ValidImageDetail(d openai.ImageURLDetail) bool {
+	switch d {
+	case openai.ImageURLDetailAuto, openai.ImageURLDetailLow, openai.ImageURLDetailHigh:
+		return true
+	}
+	return false
+}
+
+// ValidMaxTokens reports whether n is a completion token limit in
+// 1..MaxCompletionTokens.
+func ValidMaxTokens(n int) bool {
+	return n > 0 && n <= MaxCompletionTokens
+}
+
+func validTimeout(d time.Duration) bool {
+	return d > 0 && d <= MaxRequestTimeout
+}
+
+// resolve merges per-request overrides with the client defaults, dropping
+// out-of-range values.
+func (c *Client) resolve(opts []RequestOptions) RequestOptions {
+	resolved := RequestOptions{ImageDetail: c.imageDetail, MaxTokens: c.maxTokens, Model: c.model, Timeout: c.timeout}
+	for _, o := range opts {
+		if ValidImageDetail(o.ImageDetail) {
+			resolved.ImageDetail = o.ImageDetail
+		}
+		if ValidMaxTokens(o.MaxTokens) {
+			resolved.MaxTokens = o.MaxTokens
+		}
+		resolved.Structured = resolved.Structured || o.Structured
+		resolved.NoCache = resolved.NoCache || o.NoCache
+		if o.Model != "" {
+			resolved.Model = o.Model
+		}
+		if validTimeout(o.Timeout) {
+			resolved.Timeout = o.Timeout
+		}
+	}
+	return resolved
+}
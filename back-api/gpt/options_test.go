@@ -0,0 +1,138 @@
+package gpt
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestResolve_FallsBackToDefaults(t *testing.T) {
+	c := NewClient("test-key", nil, WithImageDetail(openai.ImageURLDetailHigh), WithMaxTokens(1500))
+
+	tests := []struct {
+		name string
+		opts []RequestOptions
+		want RequestOptions
+	}{
+		{"no overrides", nil, RequestOptions{ImageDetail: openai.ImageURLDetailHigh, MaxTokens: 1500, Model: openai.GPT4o, Timeout: time.Minute}},
+		{"zero values", []RequestOptions{{}}, RequestOptions{ImageDetail: openai.ImageURLDetailHigh, MaxTokens: 1500, Model: openai.GPT4o, Timeout: time.Minute}},
+		{"valid overrides", []RequestOptions{{ImageDetail: openai.ImageURLDetailLow, MaxTokens: 500}}, RequestOptions{ImageDetail: openai.ImageURLDetailLow, MaxTokens: 500, Model: openai.GPT4o, Timeout: time.Minute}},
+		{"invalid detail", []RequestOptions{{ImageDetail: "ultra", MaxTokens: 500}}, RequestOptions{ImageDetail: openai.ImageURLDetailHigh, MaxTokens: 500, Model: openai.GPT4o, Timeout: time.Minute}},
+		{"max tokens out of range", []RequestOptions{{ImageDetail: openai.ImageURLDetailLow, MaxTokens: MaxCompletionTokens + 1}}, RequestOptions{ImageDetail: openai.ImageURLDetailLow, MaxTokens: 1500, Model: openai.GPT4o, Timeout: time.Minute}},
+		{"negative max tokens", []RequestOptions{{MaxTokens: -1}}, RequestOptions{ImageDetail: openai.ImageURLDetailHigh, MaxTokens: 1500, Model: openai.GPT4o, Timeout: time.Minute}},
+		{"model override", []RequestOptions{{Model: openai.GPT4Dot1}}, RequestOptions{ImageDetail: openai.ImageURLDetailHigh, MaxTokens: 1500, Model: openai.GPT4Dot1, Timeout: time.Minute}},
+		{"timeout override", []RequestOptions{{Timeout: 3 * time.Minute}}, RequestOptions{ImageDetail: openai.ImageURLDetailHigh, MaxTokens: 1500, Model: openai.GPT4o, Timeout: 3 * time.Minute}},
+		{"timeout above max", []RequestOptions{{Timeout: MaxRequestTimeout + time.Second}}, RequestOptions{ImageDetail: openai.ImageURLDetailHigh, MaxTokens: 1500, Model: openai.GPT4o, Timeout: time.Minute}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.resolve(tt.opts); got != tt.want {
+				t.Fatalf("resolve = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithMaxTokens_IgnoresInvalid(t *testing.T) {
+	c := NewClient("test-key", nil, WithMaxTokens(0))
+	if c.maxTokens != defaultMaxTokens {
+		t.Fatalf("expected default %d, got %d", defaultMaxTokens, c.maxTokens)
+	}
+}
+
+// bodyCapture records the last chat completion request body.
+type bodyCapture struct {
+	req openai.ChatCompletionRequest
+}
+
+func (b *bodyCapture) RoundTrip(r *http.Request) (*http.Response, error) {
+	if err := json.NewDecoder(r.Body).Decode(&b.req); err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(successBody)),
+	}, nil
+}
+
+func TestProcessRequest_UsesMaxTokensOverride(t *testing.T) {
+	transport := &bodyCapture{}
+	c := newStubClient(transport, WithMaxTokens(1000))
+
+	if _, err := c.ProcessRequest(context.Background(), "hello", nil); err != nil {
+		t.Fatalf("ProcessRequest error: %v", err)
+	}
+	if transport.req.MaxTokens != 1000 {
+		t.Fatalf("expected client default 1000, got %d", transport.req.MaxTokens)
+	}
+
+	if _, err := c.ProcessRequest(context.Background(), "hello", nil, RequestOptions{MaxTokens: 300}); err != nil {
+		t.Fatalf("ProcessRequest error: %v", err)
+	}
+	if transport.req.MaxTokens != 300 {
+		t.Fatalf("expected override 300, got %d", transport.req.MaxTokens)
+	}
+}
+
+func TestProcessRequest_UsesModelOverride(t *testing.T) {
+	transport := &bodyCapture{}
+	c := newStubClient(transport, WithModel(openai.GPT4oMini))
+
+	if _, err := c.ProcessRequest(context.Background(), "hello", nil, RequestOptions{Model: openai.GPT4o}); err != nil {
+		t.Fatalf("ProcessRequest error: %v", err)
+	}
+	if transport.req.Model != openai.GPT4o {
+		t.Fatalf("expected override %q, got %q", openai.GPT4o, transport.req.Model)
+	}
+}
+
+func TestProcessRequest_StructuredRequestsJSONObject(t *testing.T) {
+	transport := &bodyCapture{}
+	c := newStubClient(transport)
+
+	if _, err := c.ProcessRequest(context.Background(), "hello", nil, RequestOptions{Structured: true}); err != nil {
+		t.Fatalf("ProcessRequest error: %v", err)
+	}
+	if transport.req.ResponseFormat == nil || transport.req.ResponseFormat.Type != openai.ChatCompletionResponseFormatTypeJSONObject {
+		t.Fatalf("expected json_object response format, got %+v", transport.req.ResponseFormat)
+	}
+	if !strings.Contains(transport.req.Messages[0].Content, `"conclusion"`) {
+		t.Fatal("system prompt should describe the JSON fields")
+	}
+}
+
+// deadlineCapture records how long the request context had left.
+type deadlineCapture struct {
+	remaining time.Duration
+}
+
+func (d *deadlineCapture) RoundTrip(r *http.Request) (*http.Response, error) {
+	if deadline, ok := r.Context().Deadline(); ok {
+		d.remaining = time.Until(deadline)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(successBody)),
+	}, nil
+}
+
+func TestProcessRequest_UsesTimeoutOverride(t *testing.T) {
+	transport := &deadlineCapture{}
+	c := newStubClient(transport)
+
+	payload := JobPayload{TimeoutMs: 180000}
+	if _, err := c.ProcessRequest(context.Background(), "hello", nil, payload.RequestOptions()); err != nil {
+		t.Fatalf("ProcessRequest error: %v", err)
+	}
+	if transport.remaining <= 2*time.Minute || transport.remaining > 3*time.Minute {
+		t.Fatalf("expected a 3m deadline, got %v left", transport.remaining)
+	}
+}
@@ -2,15 +2,50 @@ package gpt
 
 import (
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/fedutinova/smartheart/back-api/job"
 )
 
+func init() {
+	job.RegisterPayload(job.TypeGPTProcess, func() job.Payload { return new(JobPayload) })
+}
+
+// JobPayload represents the payload for GPT processing jobs.
 type JobPayload struct {
 	RequestID uuid.UUID `json:"request_id"`
 	TextQuery string    `json:"text_query,omitempty"`
 	FileKeys  []string  `json:"file_keys"`
 	UserID    uuid.UUID `json:"user_id"`
+
+	// Optional per-job overrides of the client defaults.
+	ImageDetail openai.ImageURLDetail `json:"image_detail,omitempty"`
+	MaxTokens   int                   `json:"max_tokens,omitempty"`
+	Structured  bool                  `json:"structured,omitempty"`
+	NoCache     bool                  `json:"no_cache,omitempty"`
+	Model       string                `json:"model,omitempty"`
+	TimeoutMs   int                   `json:"timeout_ms,omitempty"`
+
+	// CallbackURL receives a signed POST when the request finishes.
+	CallbackURL string `json:"callback_url,omitempty"`
+}
+
+// JobType implements job.Payload.
+func (JobPayload) JobType() job.Type { return job.TypeGPTProcess }
+
+// RequestOptions returns the payload's per-job overrides for ProcessRequest.
+func (p JobPayload) RequestOptions() RequestOptions {
+	return RequestOptions{
+		ImageDetail: p.ImageDetail,
+		MaxTokens:   p.MaxTokens,
+		Structured:  p.Structured,
+		NoCache:     p.NoCache,
+		Model:       p.Model,
+		Timeout:     time.Duration(p.TimeoutMs) * time.Millisecond,
+	}
 }
 
 // refusalPatterns are phrases that indicate GPT refused to process the request.
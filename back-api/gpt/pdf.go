@@ -0,0 +1,60 @@
+package gpt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// maxPDFTextBytes caps how much extracted PDF text is sent to the model so a
+// long report cannot blow the token budget (~5k tokens of Cyrillic text).
+const maxPDFTextBytes = 20 * 1024
+
+const contentTypePDF = "application/pdf"
+
+// extractPDFText returns the plain text of a PDF, truncated to maxPDFTextBytes.
+// truncated reports whether text was cut. The PDF parser panics on some
+// malformed inputs, so panics are turned into errors.
+func extractPDFText(data []byte) (text string, truncated bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("parse pdf: %v", r)
+		}
+	}()
+
+	r, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", false, fmt.Errorf("open pdf: %w", err)
+	}
+	plain, err := r.GetPlainText()
+	if err != nil {
+		return "", false, fmt.Errorf("extract pdf text: %w", err)
+	}
+	raw, err := io.ReadAll(plain)
+	if err != nil {
+		return "", false, fmt.Errorf("read pdf text: %w", err)
+	}
+
+	text = strings.TrimSpace(string(raw))
+	if text == "" {
+		return "", false, errors.New("pdf contains no extractable text")
+	}
+	text, truncated = truncateUTF8(text, maxPDFTextBytes)
+	return text, truncated, nil
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a multi-byte rune.
+func truncateUTF8(s string, n int) (string, bool) {
+	if len(s) <= n {
+		return s, false
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n], true
+}
@@ -0,0 +1,76 @@
+package gpt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildPDF assembles a minimal single-page PDF that draws text with Helvetica.
+func buildPDF(text string) []byte {
+	stream := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func TestExtractPDFText(t *testing.T) {
+	text, truncated, err := extractPDFText(buildPDF("Sinus rhythm 72 bpm"))
+	if err != nil {
+		t.Fatalf("extractPDFText error: %v", err)
+	}
+	if truncated {
+		t.Fatalf("expected short text not to be truncated")
+	}
+	if !strings.Contains(text, "Sinus rhythm 72 bpm") {
+		t.Fatalf("expected extracted text, got %q", text)
+	}
+}
+
+func TestExtractPDFText_Truncates(t *testing.T) {
+	long := strings.Repeat("A", maxPDFTextBytes+100)
+	text, truncated, err := extractPDFText(buildPDF(long))
+	if err != nil {
+		t.Fatalf("extractPDFText error: %v", err)
+	}
+	if !truncated {
+		t.Fatalf("expected text to be truncated")
+	}
+	if len(text) > maxPDFTextBytes {
+		t.Fatalf("expected at most %d bytes, got %d", maxPDFTextBytes, len(text))
+	}
+}
+
+func TestExtractPDFText_InvalidPDF(t *testing.T) {
+	if _, _, err := extractPDFText([]byte("%PDF-1.4 garbage")); err == nil {
+		t.Fatalf("expected error for malformed PDF")
+	}
+}
+
+func TestTruncateUTF8_KeepsRuneBoundary(t *testing.T) {
+	s := "ЭКГ" // 2 bytes per rune
+	got, truncated := truncateUTF8(s, 3)
+	if !truncated || got != "Э" {
+		t.Fatalf("expected %q, got %q (truncated=%v)", "Э", got, truncated)
+	}
+}
@@ -0,0 +1,131 @@
+package gpt
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// Prompt template names.
+const (
+	PromptGPTSystem = "gpt_system"     // system prompt for free-form ProcessRequest calls
+	PromptGPTJSON   = "gpt_structured" // appended to gpt_system in structured mode
+	PromptGPTRetry  = "gpt_rephrase"   // replaces gpt_system on the one retry after a refusal
+	PromptECGSystem = "ekg_system"     // system prompt for structured ECG measurement
+	PromptECGUser   = "ekg_user"       // user prompt for structured ECG measurement; data: ECGPromptData
+)
+
+var requiredPrompts = []string{PromptGPTSystem, PromptGPTJSON, PromptGPTRetry, PromptECGSystem, PromptECGUser}
+
+const promptExt = ".tmpl"
+
+//go:embed prompts/*.tmpl
+var defaultPromptFS embed.FS
+
+// ECGPromptData is the template data for PromptECGUser.
+type ECGPromptData struct {
+	PaperSpeedMMS float64
+	Schema        string // JSON schema of RawECGMeasurement
+}
+
+// PromptSet holds the named text/template prompts used to talk to the model.
+type PromptSet struct {
+	tmpl *template.Template
+}
+
+// DefaultPrompts returns the prompts embedded in the binary.
+func DefaultPrompts() *PromptSet {
+	p, err := LoadPromptSet("")
+	if err != nil {
+		panic(fmt.Sprintf("embedded prompts are invalid: %v", err))
+	}
+	return p
+}
+
+// LoadPromptSet parses the embedded default prompts and then any *.tmpl files
+// in dir, which override defaults with the same name (file name without the
+// extension). An empty dir yields the defaults. All templates must parse and
+// every required prompt must be present.
+func LoadPromptSet(dir string) (*PromptSet, error) {
+	sub, err := fs.Sub(defaultPromptFS, "prompts")
+	if err != nil {
+		return nil, err
+	}
+	tmpl := template.New("prompts").Option("missingkey=error")
+	if err := parsePromptFS(tmpl, sub); err != nil {
+		return nil, fmt.Errorf("parse default prompts: %w", err)
+	}
+
+	if dir != "" {
+		if err := parsePromptFS(tmpl, os.DirFS(dir)); err != nil {
+			return nil, fmt.Errorf("parse prompts from %s: %w", dir, err)
+		}
+	}
+
+	var missing []string
+	for _, name := range requiredPrompts {
+		if tmpl.Lookup(name) == nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing prompt templates: %s", strings.Join(missing, ", "))
+	}
+
+	// Render once with sample data so field typos fail at startup rather
+	// than on the first job.
+	p := &PromptSet{tmpl: tmpl}
+	for name, data := range promptSampleData {
+		if _, err := p.Render(name, data); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+var promptSampleData = map[string]any{
+	PromptGPTSystem: nil,
+	PromptGPTJSON:   nil,
+	PromptGPTRetry:  nil,
+	PromptECGSystem: nil,
+	PromptECGUser:   ECGPromptData{PaperSpeedMMS: 25, Schema: "{}"},
+}
+
+func parsePromptFS(tmpl *template.Template, fsys fs.FS) error {
+	files, err := fs.Glob(fsys, "*"+promptExt)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(path.Base(file), promptExt)
+		if _, err := tmpl.New(name).Parse(string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render executes the named prompt with data and trims surrounding whitespace.
+func (p *PromptSet) Render(name string, data any) (string, error) {
+	t := p.tmpl.Lookup(name)
+	if t == nil {
+		return "", fmt.Errorf("prompt %q not found", name)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("render prompt %q: %w", name, err)
+	}
+	out := strings.TrimSpace(sb.String())
+	if out == "" {
+		return "", fmt.Errorf("prompt %q rendered empty", name)
+	}
+	return out, nil
+}
@@ -0,0 +1,71 @@
+package gpt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePrompt(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name+promptExt), []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDefaultPrompts_ECGMeasurementPrompt(t *testing.T) {
+	system, user, err := DefaultPrompts().ECGMeasurementPrompt(50)
+	if err != nil {
+		t.Fatalf("ECGMeasurementPrompt error: %v", err)
+	}
+	if !strings.HasPrefix(system, "Ты эксперт по измерению ЭКГ") {
+		t.Fatalf("unexpected system prompt: %q", system)
+	}
+	if !strings.Contains(user, "Скорость плёнки: 50 мм/с") {
+		t.Fatalf("paper speed not rendered: %q", user)
+	}
+	if !strings.Contains(user, `"intervals_sq"`) {
+		t.Fatal("schema not rendered into user prompt")
+	}
+}
+
+func TestLoadPromptSet_OverridesFromDir(t *testing.T) {
+	dir := t.TempDir()
+	writePrompt(t, dir, PromptECGUser, "speed={{.PaperSpeedMMS}}")
+
+	p, err := LoadPromptSet(dir)
+	if err != nil {
+		t.Fatalf("LoadPromptSet error: %v", err)
+	}
+	_, user, err := p.ECGMeasurementPrompt(25)
+	if err != nil {
+		t.Fatalf("ECGMeasurementPrompt error: %v", err)
+	}
+	if user != "speed=25" {
+		t.Fatalf("expected override, got %q", user)
+	}
+	if _, err := p.Render(PromptGPTSystem, nil); err != nil {
+		t.Fatalf("default gpt_system should still be available: %v", err)
+	}
+}
+
+func TestLoadPromptSet_RejectsInvalidTemplates(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"syntax error", "{{.PaperSpeedMMS"},
+		{"unknown field", "{{.PaperSpeed}}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writePrompt(t, dir, PromptECGUser, tt.body)
+
+			if _, err := LoadPromptSet(dir); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package gpt
+
+import (
+	"context"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Provider sends chat completions to a model vendor. Requests and responses
+// use the OpenAI wire types, which Client builds; other vendors translate
+// them to and from their own API. Client adds caching, retries, refusal
+// handling and error classification on top, so they behave the same for
+// every provider.
+type Provider interface {
+	// Name identifies the vendor in logs and errors, e.g. "OpenAI".
+	Name() string
+	CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error)
+	// Retryable reports whether err is transient, so the call may be repeated.
+	Retryable(err error) bool
+}
+
+// WithProvider replaces the default OpenAI provider. Nil is ignored.
+func WithProvider(p Provider) ClientOption {
+	return func(c *Client) {
+		if p != nil {
+			c.provider = p
+		}
+	}
+}
+
+// WithBaseURL points the default OpenAI provider at an OpenAI-compatible
+// endpoint, such as a corporate proxy or a local model server. Empty keeps
+// the official API. It has no effect together with WithProvider.
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// openAIProvider is the default Provider, calling OpenAI directly.
+type openAIProvider struct {
+	client *openai.Client
+	config openai.ClientConfig
+}
+
+// newOpenAIProvider returns a provider for the official API, or for baseURL
+// when it is set.
+func newOpenAIProvider(apiKey, baseURL string) openAIProvider {
+	cfg := openai.DefaultConfig(apiKey)
+	if baseURL != "" {
+		cfg.BaseURL = baseURL
+	}
+	return openAIProvider{client: openai.NewClientWithConfig(cfg), config: cfg}
+}
+
+func (openAIProvider) Name() string { return "OpenAI" }
+
+func (p openAIProvider) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	return p.client.CreateChatCompletion(ctx, req)
+}
+
+func (openAIProvider) Retryable(err error) bool { return isRetryableOpenAIError(err) }
@@ -0,0 +1,60 @@
+package gpt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestWithBaseURL_SetsOpenAIEndpoint(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ClientOption
+		want string
+	}{
+		{"default", nil, openai.DefaultConfig("").BaseURL},
+		{"empty keeps default", []ClientOption{WithBaseURL("")}, openai.DefaultConfig("").BaseURL},
+		{"override", []ClientOption{WithBaseURL("http://localhost:11434/v1")}, "http://localhost:11434/v1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient("test-key", nil, tt.opts...)
+			p, ok := c.provider.(openAIProvider)
+			if !ok {
+				t.Fatalf("expected the OpenAI provider, got %T", c.provider)
+			}
+			if p.config.BaseURL != tt.want {
+				t.Errorf("BaseURL = %q, want %q", p.config.BaseURL, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithBaseURL_IgnoredWithCustomProvider(t *testing.T) {
+	p := NewAnthropicProvider("test-key")
+	c := NewClient("", nil, WithBaseURL("http://localhost:11434/v1"), WithProvider(p))
+	if c.provider != Provider(p) {
+		t.Errorf("expected the custom provider to win, got %T", c.provider)
+	}
+}
+
+func TestWithBaseURL_SendsRequestsToEndpoint(t *testing.T) {
+	var gotPath, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(successBody))
+	}))
+	defer srv.Close()
+
+	c := NewClient("test-key", nil, WithBaseURL(srv.URL+"/v1"))
+	if _, err := c.ProcessRequest(context.Background(), "hello", nil); err != nil {
+		t.Fatalf("ProcessRequest: %v", err)
+	}
+	if gotPath != "/v1/chat/completions" || gotAuth != "Bearer test-key" {
+		t.Errorf("unexpected request: path %q, auth %q", gotPath, gotAuth)
+	}
+}
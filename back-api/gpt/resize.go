@@ -0,0 +1,70 @@
+package gpt
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif" // register GIF decoder
+	"image/jpeg"
+	_ "image/png" // register PNG decoder
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register WebP decoder
+
+	"github.com/fedutinova/smartheart/back-api/validation"
+)
+
+const resizedJPEGQuality = 90
+
+// WithMaxImageDimension enables downscaling of images whose longest side
+// exceeds maxDim pixels before they are sent to OpenAI. Zero disables it.
+func WithMaxImageDimension(maxDim int) ClientOption {
+	return func(c *Client) {
+		c.maxImageDimension = max(maxDim, 0)
+	}
+}
+
+// downscaleImage shrinks data so that its longest side is at most maxDim,
+// preserving aspect ratio, and re-encodes it as JPEG. ok is false when the
+// image is already small enough (or maxDim is disabled) and data should be
+// used unchanged. Images over validation.MaxImagePixels are rejected from
+// their header, before any pixels are allocated.
+func downscaleImage(data []byte, maxDim int) (resized []byte, ok bool, err error) {
+	if maxDim <= 0 {
+		return nil, false, nil
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, false, fmt.Errorf("decode image config: %w", err)
+	}
+	if cfg.Width <= maxDim && cfg.Height <= maxDim {
+		return nil, false, nil
+	}
+	if cfg.Width*cfg.Height > validation.MaxImagePixels {
+		return nil, false, fmt.Errorf("image is %dx%d, over the %d pixel limit", cfg.Width, cfg.Height, validation.MaxImagePixels)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, false, fmt.Errorf("decode image: %w", err)
+	}
+
+	w, h := scaledSize(cfg.Width, cfg.Height, maxDim)
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: resizedJPEGQuality}); err != nil {
+		return nil, false, fmt.Errorf("encode jpeg: %w", err)
+	}
+	return buf.Bytes(), true, nil
+}
+
+// scaledSize fits width×height into a maxDim square, keeping aspect ratio.
+func scaledSize(width, height, maxDim int) (int, int) {
+	if width >= height {
+		return maxDim, max(1, height*maxDim/width)
+	}
+	return max(1, width*maxDim/height), maxDim
+}
@@ -0,0 +1,94 @@
+package gpt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := range w {
+		img.Set(x, h/2, color.Black)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDownscaleImage_ResizesLargeImage(t *testing.T) {
+	data := encodePNG(t, 4000, 1000)
+
+	resized, ok, err := downscaleImage(data, 2048)
+	if err != nil {
+		t.Fatalf("downscaleImage error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected image to be resized")
+	}
+
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(resized))
+	if err != nil {
+		t.Fatalf("resized image is not a JPEG: %v", err)
+	}
+	if cfg.Width != 2048 || cfg.Height != 512 {
+		t.Fatalf("expected 2048x512, got %dx%d", cfg.Width, cfg.Height)
+	}
+}
+
+func TestDownscaleImage_PortraitKeepsAspectRatio(t *testing.T) {
+	data := encodePNG(t, 600, 1200)
+
+	resized, ok, err := downscaleImage(data, 300)
+	if err != nil || !ok {
+		t.Fatalf("expected resize, got ok=%v err=%v", ok, err)
+	}
+	cfg, _ := jpeg.DecodeConfig(bytes.NewReader(resized))
+	if cfg.Width != 150 || cfg.Height != 300 {
+		t.Fatalf("expected 150x300, got %dx%d", cfg.Width, cfg.Height)
+	}
+}
+
+func TestDownscaleImage_SkipsSmallImage(t *testing.T) {
+	data := encodePNG(t, 800, 600)
+
+	_, ok, err := downscaleImage(data, 2048)
+	if err != nil {
+		t.Fatalf("downscaleImage error: %v", err)
+	}
+	if ok {
+		t.Fatalf("expected small image to be left unchanged")
+	}
+}
+
+func TestDownscaleImage_RejectsOversizedImage(t *testing.T) {
+	// A 1x1 PNG whose IHDR is rewritten to declare 30000x30000 pixels.
+	data := encodePNG(t, 1, 1)
+	binary.BigEndian.PutUint32(data[16:], 30_000)
+	binary.BigEndian.PutUint32(data[20:], 30_000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	_, ok, err := downscaleImage(data, 2048)
+	if err == nil || !strings.Contains(err.Error(), "pixel limit") {
+		t.Fatalf("expected pixel limit error, got %v", err)
+	}
+	if ok {
+		t.Fatalf("expected oversized image not to be resized")
+	}
+}
+
+func TestDownscaleImage_Disabled(t *testing.T) {
+	_, ok, err := downscaleImage([]byte("not an image"), 0)
+	if err != nil || ok {
+		t.Fatalf("expected no-op when disabled, got ok=%v err=%v", ok, err)
+	}
+}
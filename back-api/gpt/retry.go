@@ -0,0 +1,99 @@
+package gpt
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	defaultMaxAttempts    = 3
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	maxRetryDelay         = 30 * time.Second
+)
+
+// WithRetry configures retries for transient provider failures (rate limits,
+// timeouts, 5xx). maxAttempts counts the first call; values below 1 disable
+// retrying. Delays grow exponentially from baseDelay with random jitter.
+func WithRetry(maxAttempts int, baseDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxAttempts = max(maxAttempts, 1)
+		if baseDelay > 0 {
+			c.retryBaseDelay = baseDelay
+		}
+	}
+}
+
+// createChatCompletion calls the provider, retrying errors it reports as
+// retryable with backoff. It never sleeps past ctx's deadline: if the next
+// delay would not fit, the last error is returned immediately.
+func (c *Client) createChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := c.provider.CreateChatCompletion(ctx, req)
+		if err == nil || attempt >= c.maxAttempts || ctx.Err() != nil || !c.provider.Retryable(err) {
+			return resp, err
+		}
+
+		delay := backoffDelay(c.retryBaseDelay, attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+
+		slog.WarnContext(ctx, "Model provider call failed, retrying",
+			"provider", c.provider.Name(), "attempt", attempt, "max_attempts", c.maxAttempts, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, err
+		case <-timer.C:
+		}
+	}
+}
+
+// isRetryableOpenAIError reports whether err is transient: HTTP 429 (except
+// quota exhaustion), 5xx, or a network timeout.
+func isRetryableOpenAIError(err error) bool {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.Type == "insufficient_quota" || apiErr.Code == "insufficient_quota" {
+			return false
+		}
+		return isRetryableStatus(apiErr.HTTPStatusCode)
+	}
+
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		return isRetryableStatus(reqErr.HTTPStatusCode)
+	}
+
+	return isTimeout(err)
+}
+
+// isTimeout reports whether err is a network timeout.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// backoffDelay returns base·2^(attempt-1) capped at maxRetryDelay, with
+// "equal jitter": half of the delay is fixed, the other half random.
+func backoffDelay(base time.Duration, attempt int) time.Duration {
+	d := base << (attempt - 1)
+	if d <= 0 || d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
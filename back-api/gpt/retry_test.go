@@ -0,0 +1,186 @@
+package gpt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// stubTransport replays canned responses in order, repeating the last one.
+type stubTransport struct {
+	calls     atomic.Int32
+	responses []stubResponse
+}
+
+type stubResponse struct {
+	status int
+	body   string
+}
+
+func (s *stubTransport) RoundTrip(_ *http.Request) (*http.Response, error) {
+	n := int(s.calls.Add(1)) - 1
+	r := s.responses[min(n, len(s.responses)-1)]
+	return &http.Response{
+		StatusCode: r.status,
+		Status:     http.StatusText(r.status),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(r.body)),
+	}, nil
+}
+
+func newStubClient(transport http.RoundTripper, opts ...ClientOption) *Client {
+	cfg := openai.DefaultConfig("test-key")
+	cfg.HTTPClient = &http.Client{Transport: transport}
+	c := NewClient("test-key", nil, opts...)
+	c.provider = openAIProvider{client: openai.NewClientWithConfig(cfg)}
+	return c
+}
+
+const (
+	rateLimitBody = `{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`
+	refusalBody   = `{"id":"chatcmpl-0","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"I'm sorry, I can't assist with that."},"finish_reason":"stop"}],"usage":{"total_tokens":5}}`
+	successBody   = `{"id":"chatcmpl-1","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"total_tokens":7}}`
+)
+
+func TestProcessRequest_RetriesRateLimit(t *testing.T) {
+	transport := &stubTransport{responses: []stubResponse{
+		{http.StatusTooManyRequests, rateLimitBody},
+		{http.StatusTooManyRequests, rateLimitBody},
+		{http.StatusOK, successBody},
+	}}
+	c := newStubClient(transport, WithRetry(3, time.Millisecond))
+
+	res, err := c.ProcessRequest(context.Background(), "hello", nil)
+	if err != nil {
+		t.Fatalf("ProcessRequest error: %v", err)
+	}
+	if res.Content != "ok" {
+		t.Fatalf("expected content %q, got %q", "ok", res.Content)
+	}
+	if got := transport.calls.Load(); got != 3 {
+		t.Fatalf("expected 3 calls, got %d", got)
+	}
+}
+
+func TestProcessRequest_GivesUpAfterMaxAttempts(t *testing.T) {
+	transport := &stubTransport{responses: []stubResponse{
+		{http.StatusTooManyRequests, rateLimitBody},
+	}}
+	c := newStubClient(transport, WithRetry(2, time.Millisecond))
+
+	if _, err := c.ProcessRequest(context.Background(), "hello", nil); err == nil {
+		t.Fatalf("expected error after exhausting retries")
+	}
+	if got := transport.calls.Load(); got != 2 {
+		t.Fatalf("expected 2 calls, got %d", got)
+	}
+}
+
+func TestProcessRequest_DoesNotRetryAuthError(t *testing.T) {
+	transport := &stubTransport{responses: []stubResponse{
+		{http.StatusUnauthorized, `{"error":{"message":"Incorrect API key","type":"invalid_request_error","code":"invalid_api_key"}}`},
+	}}
+	c := newStubClient(transport, WithRetry(3, time.Millisecond))
+
+	if _, err := c.ProcessRequest(context.Background(), "hello", nil); err == nil {
+		t.Fatalf("expected error")
+	}
+	if got := transport.calls.Load(); got != 1 {
+		t.Fatalf("expected 1 call, got %d", got)
+	}
+}
+
+func TestProcessRequest_DoesNotRetryQuotaExhaustion(t *testing.T) {
+	transport := &stubTransport{responses: []stubResponse{
+		{http.StatusTooManyRequests, `{"error":{"message":"You exceeded your current quota","type":"insufficient_quota","code":"insufficient_quota"}}`},
+	}}
+	c := newStubClient(transport, WithRetry(3, time.Millisecond))
+
+	if _, err := c.ProcessRequest(context.Background(), "hello", nil); err == nil {
+		t.Fatalf("expected error")
+	}
+	if got := transport.calls.Load(); got != 1 {
+		t.Fatalf("expected 1 call, got %d", got)
+	}
+}
+
+func TestProcessRequest_StopsRetryingAtDeadline(t *testing.T) {
+	transport := &stubTransport{responses: []stubResponse{
+		{http.StatusServiceUnavailable, `{"error":{"message":"overloaded","type":"server_error"}}`},
+	}}
+	c := newStubClient(transport, WithRetry(5, time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := c.ProcessRequest(ctx, "hello", nil); err == nil {
+		t.Fatalf("expected error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("retry loop ignored context deadline, took %s", elapsed)
+	}
+	if got := transport.calls.Load(); got != 1 {
+		t.Fatalf("expected 1 call, got %d", got)
+	}
+}
+
+func TestBackoffDelay_GrowsAndCaps(t *testing.T) {
+	base := 100 * time.Millisecond
+	for attempt := 1; attempt <= 3; attempt++ {
+		full := base << (attempt - 1)
+		d := backoffDelay(base, attempt)
+		if d < full/2 || d > full {
+			t.Fatalf("attempt %d: delay %s outside [%s, %s]", attempt, d, full/2, full)
+		}
+	}
+	if d := backoffDelay(base, 40); d > maxRetryDelay {
+		t.Fatalf("expected delay capped at %s, got %s", maxRetryDelay, d)
+	}
+}
+
+func TestProcessRequest_RephrasesOnceAfterRefusal(t *testing.T) {
+	transport := &stubTransport{responses: []stubResponse{
+		{http.StatusOK, refusalBody},
+		{http.StatusOK, successBody},
+	}}
+	c := newStubClient(transport, WithRetry(1, time.Millisecond))
+
+	res, err := c.ProcessRequest(context.Background(), "hello", nil)
+	if err != nil {
+		t.Fatalf("ProcessRequest error: %v", err)
+	}
+	if res.Content != "ok" || res.Refused || res.FinishReason != "stop" {
+		t.Fatalf("expected rephrased answer, got %+v", res)
+	}
+	if res.TokensUsed != 12 {
+		t.Fatalf("expected tokens from both calls (12), got %d", res.TokensUsed)
+	}
+	if got := transport.calls.Load(); got != 2 {
+		t.Fatalf("expected 2 calls, got %d", got)
+	}
+}
+
+func TestProcessRequest_ReportsPersistentRefusal(t *testing.T) {
+	transport := &stubTransport{responses: []stubResponse{
+		{http.StatusOK, refusalBody},
+	}}
+	c := newStubClient(transport, WithRetry(1, time.Millisecond))
+
+	res, err := c.ProcessRequest(context.Background(), "hello", nil)
+	if err != nil {
+		t.Fatalf("ProcessRequest error: %v", err)
+	}
+	if !res.Refused {
+		t.Fatalf("expected Refused after second refusal, got %+v", res)
+	}
+	if got := transport.calls.Load(); got != 2 {
+		t.Fatalf("expected exactly one rephrase retry (2 calls), got %d", got)
+	}
+}
@@ -0,0 +1,37 @@
+package gpt
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/fedutinova/smartheart/back-api/gpt"
+
+// startSpan opens a client span around an OpenAI call.
+func (c *Client) startSpan(ctx context.Context, name string, files int) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("gpt.model", c.model),
+			attribute.Int("gpt.files", files),
+		),
+	)
+}
+
+// endSpan records the outcome of an OpenAI call and ends the span.
+func endSpan(span trace.Span, result *ProcessResult, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else if result != nil {
+		span.SetAttributes(
+			attribute.String("gpt.response_model", result.Model),
+			attribute.Int("gpt.tokens_used", result.TokensUsed),
+		)
+	}
+	span.End()
+}
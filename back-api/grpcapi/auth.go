@@ -0,0 +1,90 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/grpcapi/smartheartv1"
+	"github.com/fedutinova/smartheart/back-api/job"
+	"github.com/fedutinova/smartheart/back-api/service"
+)
+
+// methodPerms is the permission each RPC requires, mirroring the RequirePerm
+// middleware on the matching HTTP routes. Methods missing here are denied.
+var methodPerms = map[string]string{
+	smartheartv1.SmartHeartService_SubmitEKG_FullMethodName:    auth.PermECGSubmit,
+	smartheartv1.SmartHeartService_SubmitGPT_FullMethodName:    auth.PermECGSubmit,
+	smartheartv1.SmartHeartService_GetRequest_FullMethodName:   auth.PermJobReadOwn,
+	smartheartv1.SmartHeartService_GetJobStatus_FullMethodName: auth.PermJobReadOwn,
+}
+
+// authInterceptor authenticates every call with the bearer token in the
+// "authorization" metadata, the same way JWTMiddleware does for HTTP, and
+// stores the claims in the context for auth.FromContext. A nil blacklist
+// skips the revocation check.
+func authInterceptor(keys *auth.Keys, issuer string, blacklist auth.TokenBlacklistChecker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		var header string
+		if v := md.Get("authorization"); len(v) > 0 {
+			header = v[0]
+		}
+		tokenStr, ok := auth.BearerToken(header)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+		}
+
+		var (
+			claims *auth.Claims
+			err    error
+		)
+		if blacklist != nil {
+			claims, err = auth.Authenticate(ctx, keys, issuer, tokenStr, auth.WithBlacklist(blacklist))
+		} else {
+			claims, err = auth.Authenticate(ctx, keys, issuer, tokenStr)
+		}
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		perm, known := methodPerms[info.FullMethod]
+		if !known || !auth.HasPerm(claims, perm) {
+			return nil, status.Error(codes.PermissionDenied, "forbidden")
+		}
+
+		return handler(auth.NewContext(ctx, claims), req)
+	}
+}
+
+// toStatus converts a service-layer error to a gRPC status, following the
+// HTTP mapping in handler.serviceErrorBody. Unrecognized errors are logged
+// and reported as internal.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, job.ErrQueueFull):
+		return status.Error(codes.Unavailable, "service busy, try again later")
+	case errors.Is(err, apperr.ErrQuotaExceeded), errors.Is(err, service.ErrTooManyAttempts):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, apperr.ErrPaymentRequired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case apperr.IsValidation(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case apperr.IsConflict(err):
+		return status.Error(codes.AlreadyExists, "already exists")
+	case apperr.IsNotFound(err):
+		return status.Error(codes.NotFound, "not found")
+	case apperr.IsForbidden(err):
+		return status.Error(codes.PermissionDenied, "forbidden")
+	default:
+		slog.Error("Unhandled service error", "error", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,294 @@
+// Package grpcapi serves the SmartHeart gRPC API. It exposes EKG and GPT
+// submission and status polling on top of the same services as the HTTP
+// handlers, so both transports share validation, quotas and ownership checks.
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/grpcapi/smartheartv1"
+	"github.com/fedutinova/smartheart/back-api/job"
+	"github.com/fedutinova/smartheart/back-api/models"
+	"github.com/fedutinova/smartheart/back-api/service"
+	"github.com/fedutinova/smartheart/back-api/validation"
+)
+
+// recvMsgOverhead is the message allowance for the text fields and protobuf
+// framing on top of the file content of a request.
+const recvMsgOverhead = 1 << 20
+
+// maxRecvMsgSize bounds a single request message so that it fits
+// validation.MaxFiles files of maxImageBytes each; larger files are then
+// rejected by uploadedFile with a clear message rather than by the transport.
+func maxRecvMsgSize(maxImageBytes int64) int {
+	return int(validation.MaxFiles*maxImageBytes + recvMsgOverhead)
+}
+
+// Server implements smartheartv1.SmartHeartServiceServer.
+type Server struct {
+	smartheartv1.UnimplementedSmartHeartServiceServer
+
+	Submission service.SubmissionService
+	Requests   service.RequestService
+	// MaxImageBytes is the size limit of each inline file; zero uses
+	// validation.DefaultMaxFileSize.
+	MaxImageBytes int64
+}
+
+// NewGRPCServer returns a gRPC server with srv registered. Every call must
+// carry a bearer access token in the "authorization" metadata; blacklist,
+// when non-nil, rejects revoked tokens.
+func NewGRPCServer(srv *Server, keys *auth.Keys, issuer string, blacklist auth.TokenBlacklistChecker) *grpc.Server {
+	if srv.MaxImageBytes <= 0 {
+		srv.MaxImageBytes = validation.DefaultMaxFileSize
+	}
+	gs := grpc.NewServer(
+		grpc.MaxRecvMsgSize(maxRecvMsgSize(srv.MaxImageBytes)),
+		grpc.UnaryInterceptor(authInterceptor(keys, issuer, blacklist)),
+	)
+	smartheartv1.RegisterSmartHeartServiceServer(gs, srv)
+	return gs
+}
+
+// SubmitEKG queues an EKG image given by URL or as uploaded bytes.
+func (s *Server) SubmitEKG(ctx context.Context, req *smartheartv1.SubmitEKGRequest) (*smartheartv1.SubmitEKGResponse, error) {
+	userID, _, err := userFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := service.DefaultECGParams()
+	if age := int(req.GetAge()); age != 0 {
+		params.Age = &age
+	}
+	params.Sex = req.GetSex()
+	if v := req.GetPaperSpeedMms(); v != 0 {
+		params.PaperSpeedMMS = v
+	}
+	if v := req.GetMmPerMvLimb(); v != 0 {
+		params.MmPerMvLimb = v
+	}
+	if v := req.GetMmPerMvChest(); v != 0 {
+		params.MmPerMvChest = v
+	}
+	if err := params.Validate(); err != nil {
+		return nil, toStatus(err)
+	}
+	if v := req.GetCallbackUrl(); v != "" {
+		if err := validation.SSRFSafeURL(v); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid callback_url")
+		}
+		params.CallbackURL = v
+	}
+
+	var result *service.SubmittedJob
+	switch img := req.GetImage().(type) {
+	case *smartheartv1.SubmitEKGRequest_ImageUrl:
+		if err := validation.SSRFSafeURL(img.ImageUrl); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid image_url: "+err.Error())
+		}
+		result, err = s.Submission.SubmitECG(ctx, userID, img.ImageUrl, params)
+	case *smartheartv1.SubmitEKGRequest_ImageFile:
+		file, ferr := s.uploadedFile(img.ImageFile)
+		if ferr != nil {
+			return nil, ferr
+		}
+		result, err = s.Submission.SubmitECGFile(ctx, userID, file, params)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "image_url or image_file is required")
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &smartheartv1.SubmitEKGResponse{
+		JobId:     result.JobID.String(),
+		RequestId: result.RequestID.String(),
+		Status:    result.Status,
+	}, nil
+}
+
+// SubmitGPT queues files and an optional question for GPT analysis.
+func (s *Server) SubmitGPT(ctx context.Context, req *smartheartv1.SubmitGPTRequest) (*smartheartv1.SubmitGPTResponse, error) {
+	userID, _, err := userFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.GetFiles()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one file must be provided")
+	}
+	if len(req.GetFiles()) > validation.MaxFiles {
+		return nil, status.Errorf(codes.InvalidArgument, "maximum %d files allowed, got %d", validation.MaxFiles, len(req.GetFiles()))
+	}
+	if len(req.GetTextQuery()) > validation.MaxTextLength {
+		return nil, status.Errorf(codes.InvalidArgument, "text query exceeds maximum length of %d characters", validation.MaxTextLength)
+	}
+
+	files := make([]service.UploadedFile, 0, len(req.GetFiles()))
+	for _, f := range req.GetFiles() {
+		file, err := s.uploadedFile(f)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	opts := service.GPTOptions{Structured: req.GetStructured()}
+	if v := req.GetCallbackUrl(); v != "" {
+		if err := validation.SSRFSafeURL(v); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid callback_url")
+		}
+		opts.CallbackURL = v
+	}
+
+	result, err := s.Submission.SubmitGPT(ctx, userID, req.GetTextQuery(), files, opts)
+	if err != nil {
+		if result != nil && len(result.UploadErrors) > 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "no files successfully processed: %v", result.UploadErrors)
+		}
+		return nil, toStatus(err)
+	}
+
+	return &smartheartv1.SubmitGPTResponse{
+		JobId:          result.JobID.String(),
+		RequestId:      result.RequestID.String(),
+		Status:         result.Status,
+		FilesProcessed: int32(result.FilesProcessed),
+		UploadErrors:   result.UploadErrors,
+	}, nil
+}
+
+// GetRequest returns a request the caller owns, with its files and response.
+func (s *Server) GetRequest(ctx context.Context, req *smartheartv1.GetRequestRequest) (*smartheartv1.Request, error) {
+	_, claims, err := userFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request ID")
+	}
+
+	r, err := s.Requests.GetRequest(ctx, id, claims)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return requestToProto(r), nil
+}
+
+// GetJobStatus returns the queue status of a job the caller owns.
+func (s *Server) GetJobStatus(ctx context.Context, req *smartheartv1.GetJobStatusRequest) (*smartheartv1.Job, error) {
+	_, claims, err := userFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid job ID")
+	}
+
+	j, err := s.Requests.GetJobStatus(ctx, id, claims)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return jobToProto(j), nil
+}
+
+// userFromContext returns the caller's user ID and claims stored by
+// authInterceptor.
+func userFromContext(ctx context.Context) (uuid.UUID, *auth.Claims, error) {
+	claims, ok := auth.FromContext(ctx)
+	if !ok {
+		return uuid.Nil, nil, status.Error(codes.Unauthenticated, "no auth context")
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return uuid.Nil, nil, status.Error(codes.InvalidArgument, "invalid user ID")
+	}
+	return userID, claims, nil
+}
+
+// uploadedFile wraps an inline file for the submission service, enforcing
+// the same size limits as multipart uploads.
+func (s *Server) uploadedFile(f *smartheartv1.File) (service.UploadedFile, error) {
+	size := int64(len(f.GetData()))
+	switch {
+	case size == 0:
+		return service.UploadedFile{}, status.Errorf(codes.InvalidArgument, "file %s is empty", f.GetFilename())
+	case size > s.MaxImageBytes:
+		return service.UploadedFile{}, status.Errorf(codes.InvalidArgument,
+			"file %s exceeds maximum size of %d bytes", f.GetFilename(), s.MaxImageBytes)
+	}
+	return service.UploadedFile{
+		Reader:      bytes.NewReader(f.GetData()),
+		Filename:    f.GetFilename(),
+		ContentType: f.GetContentType(),
+		Size:        size,
+	}, nil
+}
+
+func requestToProto(r *models.Request) *smartheartv1.Request {
+	out := &smartheartv1.Request{
+		Id:        r.ID.String(),
+		UserId:    r.UserID.String(),
+		Status:    string(r.Status),
+		CreatedAt: timestamppb.New(r.CreatedAt),
+		UpdatedAt: timestamppb.New(r.UpdatedAt),
+	}
+	if r.TextQuery != nil {
+		out.TextQuery = *r.TextQuery
+	}
+	for _, f := range r.Files {
+		out.Files = append(out.Files, &smartheartv1.StoredFile{
+			Id:               f.ID.String(),
+			OriginalFilename: f.OriginalFilename,
+			FileType:         f.FileType,
+			FileSize:         f.FileSize,
+			S3Key:            f.S3Key,
+			S3Url:            f.S3URL,
+		})
+	}
+	if resp := r.Response; resp != nil {
+		out.Response = &smartheartv1.Response{
+			Id:               resp.ID.String(),
+			Content:          resp.Content,
+			Model:            resp.Model,
+			TokensUsed:       int32(resp.TokensUsed),
+			ProcessingTimeMs: int32(resp.ProcessingTimeMs),
+			CreatedAt:        timestamppb.New(resp.CreatedAt),
+		}
+	}
+	return out
+}
+
+func jobToProto(j *job.Job) *smartheartv1.Job {
+	return &smartheartv1.Job{
+		Id:         j.ID.String(),
+		Type:       string(j.Type),
+		Status:     string(j.Status),
+		Error:      j.Error,
+		EnqueuedAt: timestamppb.New(j.Enqueued),
+		StartedAt:  optionalTimestamp(j.Started),
+		FinishedAt: optionalTimestamp(j.Finished),
+	}
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// Compile-time check that Server implements the generated interface.
+var _ smartheartv1.SmartHeartServiceServer = (*Server)(nil)
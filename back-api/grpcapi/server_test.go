@@ -0,0 +1,305 @@
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/grpcapi/smartheartv1"
+	"github.com/fedutinova/smartheart/back-api/job"
+	"github.com/fedutinova/smartheart/back-api/service"
+	"github.com/fedutinova/smartheart/back-api/service/mocks"
+	"github.com/fedutinova/smartheart/back-api/validation"
+)
+
+const testIssuer = "smartheart-test"
+
+type testEnv struct {
+	client     smartheartv1.SmartHeartServiceClient
+	keys       *auth.Keys
+	submission *mocks.MockSubmissionService
+	requests   *mocks.MockRequestService
+}
+
+// newTestEnv serves a Server backed by mocks; configure adjusts the Server
+// before it is registered.
+func newTestEnv(t *testing.T, configure ...func(*Server)) *testEnv {
+	t.Helper()
+	env := &testEnv{
+		keys:       auth.NewHS256Keys("test-secret"),
+		submission: mocks.NewMockSubmissionService(t),
+		requests:   mocks.NewMockRequestService(t),
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	server := &Server{Submission: env.submission, Requests: env.requests}
+	for _, c := range configure {
+		c(server)
+	}
+	srv := NewGRPCServer(server, env.keys, testIssuer, nil)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	env.client = smartheartv1.NewSmartHeartServiceClient(conn)
+	return env
+}
+
+// authed returns a context carrying a bearer token for userID with roles.
+func (e *testEnv) authed(t *testing.T, userID uuid.UUID, roles ...string) context.Context {
+	t.Helper()
+	token, err := auth.NewToken(e.keys, testIssuer, userID.String(), roles, time.Minute)
+	if err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func assertCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("expected code %v, got %v (err: %v)", want, got, err)
+	}
+}
+
+// --- Auth tests ---
+
+func TestInterceptor_MissingToken(t *testing.T) {
+	env := newTestEnv(t)
+
+	_, err := env.client.GetJobStatus(context.Background(), &smartheartv1.GetJobStatusRequest{Id: uuid.NewString()})
+	assertCode(t, err, codes.Unauthenticated)
+}
+
+func TestInterceptor_InvalidToken(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer not-a-jwt")
+
+	_, err := env.client.GetJobStatus(ctx, &smartheartv1.GetJobStatusRequest{Id: uuid.NewString()})
+	assertCode(t, err, codes.Unauthenticated)
+}
+
+func TestInterceptor_MissingPermission(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := env.authed(t, uuid.New(), "nobody")
+
+	_, err := env.client.SubmitEKG(ctx, &smartheartv1.SubmitEKGRequest{
+		Image: &smartheartv1.SubmitEKGRequest_ImageUrl{ImageUrl: "https://example.com/ecg.png"},
+	})
+	assertCode(t, err, codes.PermissionDenied)
+}
+
+// --- SubmitEKG tests ---
+
+func TestSubmitEKG_FileUsesDefaultCalibration(t *testing.T) {
+	env := newTestEnv(t)
+	userID := uuid.New()
+	jobID, requestID := uuid.New(), uuid.New()
+
+	env.submission.EXPECT().
+		SubmitECGFile(mock.Anything, userID, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ uuid.UUID, f service.UploadedFile, p service.ECGParams) (*service.SubmittedJob, error) {
+			data, _ := io.ReadAll(f.Reader)
+			if string(data) != "png-bytes" || f.Filename != "ecg.png" || f.Size != 9 {
+				t.Errorf("unexpected file: %q %q %d", data, f.Filename, f.Size)
+			}
+			if p.PaperSpeedMMS != 25 || p.MmPerMvLimb != 10 || p.MmPerMvChest != 20 {
+				t.Errorf("unexpected calibration: %+v", p)
+			}
+			if p.Age == nil || *p.Age != 60 {
+				t.Errorf("expected age 60, got %v", p.Age)
+			}
+			return &service.SubmittedJob{JobID: jobID, RequestID: requestID, Status: "queued"}, nil
+		})
+
+	resp, err := env.client.SubmitEKG(env.authed(t, userID, auth.RoleUser), &smartheartv1.SubmitEKGRequest{
+		Image:        &smartheartv1.SubmitEKGRequest_ImageFile{ImageFile: &smartheartv1.File{Filename: "ecg.png", Data: []byte("png-bytes")}},
+		Age:          60,
+		MmPerMvChest: 20,
+	})
+	if err != nil {
+		t.Fatalf("SubmitEKG: %v", err)
+	}
+	if resp.GetJobId() != jobID.String() || resp.GetRequestId() != requestID.String() || resp.GetStatus() != "queued" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestSubmitEKG_MissingImage(t *testing.T) {
+	env := newTestEnv(t)
+
+	_, err := env.client.SubmitEKG(env.authed(t, uuid.New(), auth.RoleUser), &smartheartv1.SubmitEKGRequest{})
+	assertCode(t, err, codes.InvalidArgument)
+}
+
+func TestSubmitEKG_PrivateImageURLRejected(t *testing.T) {
+	env := newTestEnv(t)
+
+	_, err := env.client.SubmitEKG(env.authed(t, uuid.New(), auth.RoleUser), &smartheartv1.SubmitEKGRequest{
+		Image: &smartheartv1.SubmitEKGRequest_ImageUrl{ImageUrl: "http://127.0.0.1/ecg.png"},
+	})
+	assertCode(t, err, codes.InvalidArgument)
+}
+
+func TestSubmitEKG_InvalidParamsRejected(t *testing.T) {
+	env := newTestEnv(t)
+	image := &smartheartv1.SubmitEKGRequest_ImageUrl{ImageUrl: "https://example.com/ecg.png"}
+
+	for name, req := range map[string]*smartheartv1.SubmitEKGRequest{
+		"age":         {Image: image, Age: 200},
+		"sex":         {Image: image, Sex: "unknown"},
+		"paper speed": {Image: image, PaperSpeedMms: 500},
+		"limb gain":   {Image: image, MmPerMvLimb: 100},
+		"chest gain":  {Image: image, MmPerMvChest: -1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := env.client.SubmitEKG(env.authed(t, uuid.New(), auth.RoleUser), req)
+			assertCode(t, err, codes.InvalidArgument)
+		})
+	}
+}
+
+func TestSubmitEKG_QuotaExceeded(t *testing.T) {
+	env := newTestEnv(t)
+	env.submission.EXPECT().
+		SubmitECGFile(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, apperr.ErrQuotaExceeded)
+
+	_, err := env.client.SubmitEKG(env.authed(t, uuid.New(), auth.RoleUser), &smartheartv1.SubmitEKGRequest{
+		Image: &smartheartv1.SubmitEKGRequest_ImageFile{ImageFile: &smartheartv1.File{Filename: "ecg.png", Data: []byte("x")}},
+	})
+	assertCode(t, err, codes.ResourceExhausted)
+}
+
+// --- SubmitGPT tests ---
+
+func TestSubmitGPT_NoFiles(t *testing.T) {
+	env := newTestEnv(t)
+
+	_, err := env.client.SubmitGPT(env.authed(t, uuid.New(), auth.RoleUser), &smartheartv1.SubmitGPTRequest{TextQuery: "hi"})
+	assertCode(t, err, codes.InvalidArgument)
+}
+
+func TestSubmitGPT_FileOverConfiguredLimit(t *testing.T) {
+	env := newTestEnv(t, func(s *Server) { s.MaxImageBytes = 4 })
+
+	_, err := env.client.SubmitGPT(env.authed(t, uuid.New(), auth.RoleUser), &smartheartv1.SubmitGPTRequest{
+		Files: []*smartheartv1.File{{Filename: "ecg.png", Data: []byte("png-bytes")}},
+	})
+	assertCode(t, err, codes.InvalidArgument)
+}
+
+func TestMaxRecvMsgSize_FollowsMaxImageBytes(t *testing.T) {
+	for _, maxImageBytes := range []int64{10 << 20, 100 << 20} {
+		if got, want := maxRecvMsgSize(maxImageBytes), int(validation.MaxFiles*maxImageBytes); got <= want {
+			t.Fatalf("maxRecvMsgSize(%d) = %d, want more than %d", maxImageBytes, got, want)
+		}
+	}
+}
+
+func TestSubmitGPT_AcceptsMaxFilesAtConfiguredLimit(t *testing.T) {
+	const maxImageBytes = 2 << 20
+	env := newTestEnv(t, func(s *Server) { s.MaxImageBytes = maxImageBytes })
+	userID := uuid.New()
+
+	env.submission.EXPECT().
+		SubmitGPT(mock.Anything, userID, "", mock.Anything, service.GPTOptions{}).
+		Return(&service.GPTSubmitResult{
+			SubmittedJob:   service.SubmittedJob{JobID: uuid.New(), RequestID: uuid.New(), Status: "queued"},
+			FilesProcessed: validation.MaxFiles,
+		}, nil)
+
+	// The message is larger than gRPC's 4 MiB default, so it only arrives if
+	// the receive limit was derived from MaxImageBytes.
+	files := make([]*smartheartv1.File, validation.MaxFiles)
+	for i := range files {
+		files[i] = &smartheartv1.File{Filename: "a.pdf", ContentType: "application/pdf", Data: make([]byte, maxImageBytes)}
+	}
+	if _, err := env.client.SubmitGPT(env.authed(t, userID, auth.RoleUser), &smartheartv1.SubmitGPTRequest{Files: files}); err != nil {
+		t.Fatalf("SubmitGPT: %v", err)
+	}
+}
+
+func TestSubmitGPT_Success(t *testing.T) {
+	env := newTestEnv(t)
+	userID := uuid.New()
+	jobID, requestID := uuid.New(), uuid.New()
+
+	env.submission.EXPECT().
+		SubmitGPT(mock.Anything, userID, "what is this?", mock.Anything, service.GPTOptions{Structured: true}).
+		Return(&service.GPTSubmitResult{
+			SubmittedJob:   service.SubmittedJob{JobID: jobID, RequestID: requestID, Status: "queued"},
+			FilesProcessed: 1,
+		}, nil)
+
+	resp, err := env.client.SubmitGPT(env.authed(t, userID, auth.RoleUser), &smartheartv1.SubmitGPTRequest{
+		TextQuery:  "what is this?",
+		Files:      []*smartheartv1.File{{Filename: "a.pdf", ContentType: "application/pdf", Data: []byte("%PDF")}},
+		Structured: true,
+	})
+	if err != nil {
+		t.Fatalf("SubmitGPT: %v", err)
+	}
+	if resp.GetJobId() != jobID.String() || resp.GetFilesProcessed() != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+// --- Status tests ---
+
+func TestGetJobStatus_Success(t *testing.T) {
+	env := newTestEnv(t)
+	jobID := uuid.New()
+	started := time.Now().Add(-time.Second)
+
+	env.requests.EXPECT().
+		GetJobStatus(mock.Anything, jobID, mock.Anything).
+		Return(&job.Job{ID: jobID, Type: job.TypeECGAnalyze, Status: job.StatusRunning, Enqueued: started.Add(-time.Second), Started: &started}, nil)
+
+	resp, err := env.client.GetJobStatus(env.authed(t, uuid.New(), auth.RoleUser), &smartheartv1.GetJobStatusRequest{Id: jobID.String()})
+	if err != nil {
+		t.Fatalf("GetJobStatus: %v", err)
+	}
+	if resp.GetStatus() != string(job.StatusRunning) || resp.GetStartedAt() == nil || resp.GetFinishedAt() != nil {
+		t.Fatalf("unexpected job: %+v", resp)
+	}
+}
+
+func TestGetJobStatus_InvalidID(t *testing.T) {
+	env := newTestEnv(t)
+
+	_, err := env.client.GetJobStatus(env.authed(t, uuid.New(), auth.RoleUser), &smartheartv1.GetJobStatusRequest{Id: "nope"})
+	assertCode(t, err, codes.InvalidArgument)
+}
+
+func TestGetRequest_NotFound(t *testing.T) {
+	env := newTestEnv(t)
+	requestID := uuid.New()
+
+	env.requests.EXPECT().
+		GetRequest(mock.Anything, requestID, mock.Anything).
+		Return(nil, apperr.ErrRequestNotFound)
+
+	_, err := env.client.GetRequest(env.authed(t, uuid.New(), auth.RoleUser), &smartheartv1.GetRequestRequest{Id: requestID.String()})
+	assertCode(t, err, codes.NotFound)
+}
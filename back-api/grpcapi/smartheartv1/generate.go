@@ -0,0 +1,5 @@
+// Package smartheartv1 holds the generated protobuf and gRPC code for the
+// SmartHeart gRPC API.
+package smartheartv1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative smartheart.proto
@@ -1,15 +1,41 @@
 package handler
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/fedutinova/smartheart/back-api/job"
+	"github.com/fedutinova/smartheart/back-api/models"
+	"github.com/fedutinova/smartheart/back-api/queue"
 	"github.com/fedutinova/smartheart/back-api/repository"
 )
 
 // AdminHandler handles admin dashboard endpoints.
 type AdminHandler struct {
 	Repo repository.Store
+	// DeadLetters replays failed jobs; nil when the queue has no dead
+	// letter stream (QUEUE_MODE=memory).
+	DeadLetters DeadLetterReplayer
+}
+
+// DeadLetterReplayer requeues dead-lettered jobs in bulk. *queue.RedisQueue
+// implements it.
+type DeadLetterReplayer interface {
+	RetryAllDeadLetter(ctx context.Context, filter queue.DeadLetterFilter) (int, error)
+}
+
+func newAdminHandler(repo repository.Store, q job.Queue) *AdminHandler {
+	h := &AdminHandler{Repo: repo}
+	if dl, ok := q.(DeadLetterReplayer); ok {
+		h.DeadLetters = dl
+	}
+	return h
 }
 
 func adminPagination(r *http.Request) (limit, offset int) {
@@ -92,3 +118,162 @@ func (h *AdminHandler) ListFeedback(w http.ResponseWriter, r *http.Request) {
 		Offset: offset,
 	})
 }
+
+// ListRequests returns a paginated, filtered list of requests across all users.
+// Supported query params: user_id, status, from, to (RFC 3339 or YYYY-MM-DD;
+// a date-only "to" includes that whole day), tag.<key>, limit, offset.
+func (h *AdminHandler) ListRequests(w http.ResponseWriter, r *http.Request) {
+	limit, offset := adminPagination(r)
+	filter := repository.RequestFilter{Limit: limit, Offset: offset}
+	q := r.URL.Query()
+
+	if v := q.Get("user_id"); v != "" {
+		userID, err := uuid.Parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid user_id")
+			return
+		}
+		filter.UserID = &userID
+	}
+	if v := q.Get("status"); v != "" {
+		if !models.ValidRequestStatus(v) {
+			writeError(w, http.StatusBadRequest, "invalid status")
+			return
+		}
+		filter.Status = v
+	}
+	if !parseDateRange(w, q, &filter.From, &filter.To) {
+		return
+	}
+	var ok bool
+	if filter.Tags, ok = tagFilterFromQuery(w, q); !ok {
+		return
+	}
+
+	requests, total, err := h.Repo.ListRequests(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load requests")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PaginatedResponse{
+		Data:   requests,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// ListAudit returns a paginated, filtered view of the audit log.
+// Supported query params: actor_id, action, from, to (as for ListRequests),
+// limit, offset.
+func (h *AdminHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	limit, offset := adminPagination(r)
+	filter := repository.AuditFilter{Limit: limit, Offset: offset, Action: r.URL.Query().Get("action")}
+	q := r.URL.Query()
+
+	if v := q.Get("actor_id"); v != "" {
+		actorID, err := uuid.Parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid actor_id")
+			return
+		}
+		filter.ActorID = &actorID
+	}
+	if !parseDateRange(w, q, &filter.From, &filter.To) {
+		return
+	}
+
+	entries, total, err := h.Repo.ListAudit(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load audit log")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PaginatedResponse{
+		Data:   entries,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// RetryDeadLetters requeues dead-lettered jobs, oldest first.
+// Supported query params: type (job type), reason (substring of the dead
+// letter reason), limit (at most 1000). Jobs moved before a failure stay
+// requeued; the error response then reports how many.
+func (h *AdminHandler) RetryDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if h.DeadLetters == nil {
+		writeError(w, http.StatusConflict, "dead letter replay requires QUEUE_MODE=redis")
+		return
+	}
+	q := r.URL.Query()
+	filter := queue.DeadLetterFilter{Reason: q.Get("reason")}
+	if v := q.Get("type"); v != "" {
+		t := job.Type(v)
+		if t != job.TypeECGAnalyze && t != job.TypeGPTProcess {
+			writeError(w, http.StatusBadRequest, "invalid type")
+			return
+		}
+		filter.Type = t
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		filter.Limit = n
+	}
+
+	n, err := h.DeadLetters.RetryAllDeadLetter(r.Context(), filter)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Dead letter replay failed", "retried", n, "error", err)
+		writeError(w, http.StatusInternalServerError, "dead letter replay failed after "+strconv.Itoa(n)+" jobs")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"retried": n})
+}
+
+// parseDateRange reads the "from" and "to" query parameters into from and
+// to. A date-only "to" includes that whole day. On a malformed value it
+// writes a 400 response and returns false.
+func parseDateRange(w http.ResponseWriter, q url.Values, from, to **time.Time) bool {
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+		end  bool
+	}{
+		{"from", from, false},
+		{"to", to, true},
+	} {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := parseDateParam(v, p.end)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid "+p.name+": expected RFC 3339 or YYYY-MM-DD")
+			return false
+		}
+		*p.dst = &t
+	}
+	return true
+}
+
+// parseDateParam parses an RFC 3339 timestamp or a YYYY-MM-DD date (UTC).
+// For date-only values with endOfDay set, the start of the next day is
+// returned so the bound can be used exclusively.
+func parseDateParam(v string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+type createRoleRequest struct {
+	Name        string `json:"name"        validate:"required,max=50"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+// Permission names follow the seeded "resource:action" convention, so the
+// name is derived rather than sent.
+type createPermissionRequest struct {
+	Resource    string `json:"resource"    validate:"required,max=50,excludes=:"`
+	Action      string `json:"action"      validate:"required,max=49,excludes=:"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+type attachPermissionRequest struct {
+	Permission string `json:"permission" validate:"required"`
+}
+
+type assignRoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// ListRoles returns all roles with their permissions.
+func (h *AdminHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.Repo.ListRoles(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load roles")
+		return
+	}
+	writeJSON(w, http.StatusOK, roles)
+}
+
+// CreateRole creates a role without permissions.
+func (h *AdminHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
+	var req createRoleRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	role := &models.Role{Name: req.Name, Description: req.Description}
+	if err := h.Repo.CreateRole(r.Context(), role); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, role)
+}
+
+// ListPermissions returns all permissions.
+func (h *AdminHandler) ListPermissions(w http.ResponseWriter, r *http.Request) {
+	perms, err := h.Repo.ListPermissions(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load permissions")
+		return
+	}
+	writeJSON(w, http.StatusOK, perms)
+}
+
+// CreatePermission creates a permission named "resource:action".
+func (h *AdminHandler) CreatePermission(w http.ResponseWriter, r *http.Request) {
+	var req createPermissionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	perm := &models.Permission{
+		Name:        req.Resource + ":" + req.Action,
+		Resource:    req.Resource,
+		Action:      req.Action,
+		Description: req.Description,
+	}
+	if err := h.Repo.CreatePermission(r.Context(), perm); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, perm)
+}
+
+// AttachPermission grants a permission to the role in the path.
+func (h *AdminHandler) AttachPermission(w http.ResponseWriter, r *http.Request) {
+	var req attachPermissionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := h.Repo.AttachPermission(r.Context(), chi.URLParam(r, "name"), req.Permission); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.reloadPermissions(r.Context())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DetachPermission revokes a permission from the role in the path.
+func (h *AdminHandler) DetachPermission(w http.ResponseWriter, r *http.Request) {
+	err := h.Repo.DetachPermission(r.Context(), chi.URLParam(r, "name"), chi.URLParam(r, "permission"))
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.reloadPermissions(r.Context())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AssignRole gives the user in the path a role. It takes effect when the
+// user's next access token is issued, since roles are carried in the token.
+func (h *AdminHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+	var req assignRoleRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := h.Repo.AssignRoleToUser(r.Context(), userID, req.Role); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnassignRole removes a role from the user in the path.
+func (h *AdminHandler) UnassignRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	if err := h.Repo.UnassignRoleFromUser(r.Context(), userID, chi.URLParam(r, "role")); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reloadPermissions refreshes the role→permissions cache after a change.
+// Only this instance is refreshed; others pick the change up on restart.
+// The change itself is already committed, so a failed reload is only logged.
+func (h *AdminHandler) reloadPermissions(ctx context.Context) {
+	mapping, err := h.Repo.LoadRolePermissions(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to reload role permissions", "error", err)
+		return
+	}
+	auth.InitPermsFromDB(mapping)
+}
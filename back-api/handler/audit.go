@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fedutinova/smartheart/back-api/models"
+	"github.com/fedutinova/smartheart/back-api/repository"
+)
+
+// auditTimeout bounds a single audit write.
+const auditTimeout = 2 * time.Second
+
+// auditQueueSize is how many entries may wait for the writer before new ones
+// are dropped.
+const auditQueueSize = 256
+
+// Auditor records security-relevant actions in the audit log. Entries are
+// queued and written by a background goroutine, so auditing never blocks the
+// request; when the queue is full the entry is dropped and logged. A nil
+// Auditor records nothing.
+type Auditor struct {
+	Repo repository.Store
+
+	start   sync.Once
+	mu      sync.RWMutex // guards closed against sends on a closed queue
+	closed  bool
+	entries chan pendingAudit
+	done    chan struct{}
+}
+
+type pendingAudit struct {
+	ctx   context.Context
+	entry *models.AuditEntry
+}
+
+// Record queues an audit entry for r. actorID may be nil when the actor is
+// not known, e.g. for a failed login.
+func (a *Auditor) Record(r *http.Request, actorID *uuid.UUID, action, target string) {
+	if a == nil || a.Repo == nil {
+		return
+	}
+	entry := &models.AuditEntry{
+		ActorID: actorID,
+		Action:  action,
+		Target:  target,
+		IP:      clientIP(r),
+	}
+	// The write must outlive a client that disconnects right after the
+	// response.
+	ctx := context.WithoutCancel(r.Context())
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		slog.WarnContext(ctx, "Audit log closed, dropping entry", "action", action, "target", target)
+		return
+	}
+	a.start.Do(a.startWriter)
+	select {
+	case a.entries <- pendingAudit{ctx: ctx, entry: entry}:
+	default:
+		slog.WarnContext(ctx, "Audit queue full, dropping entry", "action", action, "target", target)
+	}
+}
+
+// Close stops accepting entries and waits until the queued ones are written.
+func (a *Auditor) Close() {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+	a.closed = true
+	a.start.Do(func() {}) // never started: nothing to drain
+	started := a.entries != nil
+	if started {
+		close(a.entries)
+	}
+	a.mu.Unlock()
+	if started {
+		<-a.done
+	}
+}
+
+func (a *Auditor) startWriter() {
+	a.entries = make(chan pendingAudit, auditQueueSize)
+	a.done = make(chan struct{})
+	go a.write()
+}
+
+func (a *Auditor) write() {
+	defer close(a.done)
+	for p := range a.entries {
+		ctx, cancel := context.WithTimeout(p.ctx, auditTimeout)
+		if err := a.Repo.WriteAudit(ctx, p.entry); err != nil {
+			slog.WarnContext(ctx, "Failed to write audit entry", "action", p.entry.Action, "target", p.entry.Target, "error", err)
+		}
+		cancel()
+	}
+}
+
+// Middleware records every request passing through it as models.AuditAdminAccess
+// by the authenticated user, with the method and path as the target. Mount it
+// after the permission checks so denied requests are not logged as access.
+func (a *Auditor) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var actorID *uuid.UUID
+		if id, _, ok := extractUserID(r); ok {
+			actorID = &id
+		}
+		a.Record(r, actorID, models.AuditAdminAccess, r.Method+" "+r.URL.Path)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the client address of r without the port. Behind a
+// proxy, chi's RealIP middleware has already replaced RemoteAddr with the
+// forwarded address.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/fedutinova/smartheart/back-api/apperr"
 	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/models"
 )
 
 const maxBodySize = 1 << 20 // 1 MB
@@ -20,6 +23,10 @@ type loginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+type resendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
 // accessTokenResponse is the JSON body returned by login/refresh.
 // The refresh token is no longer included — it travels as an httpOnly cookie.
 type accessTokenResponse struct {
@@ -28,8 +35,6 @@ type accessTokenResponse struct {
 
 // Register handles user registration.
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
-	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
-
 	var req registerRequest
 	if !decodeAndValidate(w, r, &req) {
 		return
@@ -49,23 +54,65 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 // Login handles user authentication.
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
-
 	var req loginRequest
 	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	tokens, err := h.Service.Login(r.Context(), req.Email, req.Password)
+	result, err := h.Service.Login(r.Context(), req.Email, req.Password)
 	if err != nil {
+		if errors.Is(err, apperr.ErrInvalidCredentials) {
+			h.Audit.Record(r, nil, models.AuditLoginFailed, "email:"+req.Email)
+		}
 		handleServiceError(w, err)
 		return
 	}
+	if result.ChallengeToken != "" {
+		h.Audit.Record(r, &result.UserID, models.AuditLoginChallenge, "")
+		writeJSON(w, http.StatusOK, twoFactorChallengeResponse{TwoFactorRequired: true, ChallengeToken: result.ChallengeToken})
+		return
+	}
 
+	h.Audit.Record(r, &result.UserID, models.AuditLogin, "")
+	tokens := result.Tokens
 	auth.SetRefreshTokenCookie(w, tokens.RefreshToken, h.Config.JWT.TTLRefresh, h.Config.Cookie)
 	writeJSON(w, http.StatusOK, accessTokenResponse{AccessToken: tokens.AccessToken})
 }
 
+// VerifyEmail confirms the address a verification token was mailed to.
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	if err := h.Service.VerifyEmail(r.Context(), token); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "email verified"})
+}
+
+// ResendVerification mails a new verification link. The response is the same
+// whether or not the address belongs to an unverified account.
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var req resendVerificationRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := h.Service.ResendVerification(r.Context(), req.Email); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"message": "if the email awaits verification, a new link has been sent",
+	})
+}
+
 // Refresh handles token refresh.
 // The refresh token is read from the httpOnly cookie.
 func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
@@ -98,7 +145,17 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	claims, _ := auth.FromContext(r.Context())
 
 	_ = h.Service.Logout(r.Context(), refreshToken, accessToken, claims)
+	if userID, _, ok := extractUserID(r); ok {
+		h.Audit.Record(r, &userID, models.AuditLogout, "")
+	}
 
 	auth.ClearRefreshTokenCookie(w, h.Config.Cookie)
 	writeJSON(w, http.StatusOK, map[string]string{"message": "logged out successfully"})
 }
+
+// JWKS publishes the public keys that verify access tokens. With HS256 the
+// set is empty.
+func (h *AuthHandler) JWKS(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	writeJSON(w, http.StatusOK, h.Keys.JWKS())
+}
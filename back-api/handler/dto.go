@@ -2,9 +2,16 @@ package handler
 
 import "github.com/google/uuid"
 
-// APIError is a structured error response returned by API handlers.
+// APIError is the body of every error response returned by API handlers:
+// {"error": {"code": "not_found", "message": "..."}}.
 type APIError struct {
-	Error        string   `json:"error"`
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody carries a stable machine-readable code and a human-readable message.
+type ErrorBody struct {
+	Code         string   `json:"code"`
+	Message      string   `json:"message"`
 	Details      any      `json:"details,omitempty"`
 	UploadErrors []string `json:"upload_errors,omitempty"`
 }
@@ -24,6 +31,12 @@ type SubmitGPTResponse struct {
 	UploadErrors   []string  `json:"upload_errors,omitempty"`
 }
 
+// ValidateGPTResponse is returned when a GPT submission passes validation.
+type ValidateGPTResponse struct {
+	Valid bool `json:"valid"`
+	Files int  `json:"files"`
+}
+
 // SubmitECGResponse is returned when an EKG analysis job is enqueued.
 type SubmitECGResponse struct {
 	JobID     uuid.UUID `json:"job_id"`
@@ -32,6 +45,25 @@ type SubmitECGResponse struct {
 	Message   string    `json:"message"`
 }
 
+// batchItemRejected is the status of a batch item that was not submitted.
+const batchItemRejected = "rejected"
+
+// SubmitECGBatchResponse is returned when an EKG batch is submitted.
+type SubmitECGBatchResponse struct {
+	BatchID uuid.UUID              `json:"batch_id"`
+	Items   []ECGBatchItemResponse `json:"items"`
+}
+
+// ECGBatchItemResponse reports one image of a batch, in input order. Rejected
+// items carry an error and no job.
+type ECGBatchItemResponse struct {
+	Index     int        `json:"index"`
+	JobID     *uuid.UUID `json:"job_id,omitempty"`
+	RequestID *uuid.UUID `json:"request_id,omitempty"`
+	Status    string     `json:"status"`
+	Error     *ErrorBody `json:"error,omitempty"`
+}
+
 // PaginatedResponse wraps a list result with pagination metadata.
 type PaginatedResponse struct {
 	Data   any `json:"data"`
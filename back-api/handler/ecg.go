@@ -3,109 +3,38 @@ package handler
 import (
 	"encoding/json"
 	"fmt"
-	"net"
 	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
-	"sync"
-	"time"
 
-	"github.com/fedutinova/smartheart/back-api/apperr"
 	"github.com/fedutinova/smartheart/back-api/models"
 	"github.com/fedutinova/smartheart/back-api/service"
+	"github.com/fedutinova/smartheart/back-api/validation"
 )
 
-type dnsEntry struct {
-	ips       []net.IP
-	expiresAt time.Time
+type ekgAnalyzeRequest struct {
+	ImageTempURL string `json:"image_temp_url" validate:"required,url"`
+	ekgParamsRequest
 }
 
-var (
-	dnsCache    = make(map[string]*dnsEntry)
-	dnsCacheMu  sync.RWMutex
-	dnsCacheTTL = 5 * time.Minute
-)
+// ekgBatchRequest is the JSON body of POST /v1/ecg/batch. URLs are validated
+// per item, so one bad URL only rejects that image.
+type ekgBatchRequest struct {
+	ImageTempURLs []string `json:"image_temp_urls" validate:"required,min=1"`
+	ekgParamsRequest
+}
 
-type ekgAnalyzeRequest struct {
-	ImageTempURL  string                    `json:"image_temp_url"            validate:"required,url"`
+// ekgParamsRequest holds the patient and calibration fields shared by the
+// single-image and batch EKG endpoints.
+type ekgParamsRequest struct {
 	Age           *int                      `json:"age,omitempty"             validate:"omitempty,min=1,max=150"`
 	Sex           string                    `json:"sex,omitempty"             validate:"omitempty,oneof=male female"`
 	PaperSpeedMMS *float64                  `json:"paper_speed_mms,omitempty" validate:"omitempty,min=10,max=100"`
 	MmPerMvLimb   *float64                  `json:"mm_per_mv_limb,omitempty"  validate:"omitempty,min=1,max=40"`
 	MmPerMvChest  *float64                  `json:"mm_per_mv_chest,omitempty" validate:"omitempty,min=1,max=40"`
 	ClientMeta    *models.RequestClientMeta `json:"client_meta,omitempty"`
-}
-
-// resolveHostWithCache performs DNS lookup with caching to avoid blocking on every request.
-// TTL is 5 minutes per hostname.
-func resolveHostWithCache(host string) ([]net.IP, error) {
-	dnsCacheMu.RLock()
-	if entry, ok := dnsCache[host]; ok && time.Now().Before(entry.expiresAt) {
-		dnsCacheMu.RUnlock()
-		return entry.ips, nil
-	}
-	dnsCacheMu.RUnlock()
-
-	// Not in cache or expired; perform DNS lookup
-	ips, err := net.LookupIP(host)
-	if err != nil {
-		return nil, err
-	}
-
-	// Update cache
-	dnsCacheMu.Lock()
-	dnsCache[host] = &dnsEntry{
-		ips:       ips,
-		expiresAt: time.Now().Add(dnsCacheTTL),
-	}
-	dnsCacheMu.Unlock()
-
-	return ips, nil
-}
-
-// isSSRFSafeURL validates that a URL is safe to fetch (prevents SSRF attacks).
-// This is handler-level validation for user-submitted URLs (requires HTTPS).
-// Note: workers/ecg_handler.go has separate SSRF validation for internal URLs using
-// a custom transport dialer. Both approaches are complementary and serve different purposes.
-// Rejects localhost, private networks, and link-local addresses.
-// Uses cached DNS results to avoid blocking lookups on every request.
-func isSSRFSafeURL(urlStr string) error {
-	u, err := url.Parse(urlStr)
-	if err != nil {
-		return fmt.Errorf("invalid URL: %w", apperr.ErrValidation)
-	}
-
-	// Require https for security
-	if u.Scheme != "https" {
-		return fmt.Errorf("only HTTPS URLs are allowed: %w", apperr.ErrValidation)
-	}
-
-	// Parse the host (removes port)
-	host := u.Hostname()
-	if host == "" {
-		return fmt.Errorf("URL has no host: %w", apperr.ErrValidation)
-	}
-
-	// Reject localhost and loop back
-	if host == "localhost" || host == "127.0.0.1" || host == "::1" {
-		return fmt.Errorf("localhost not allowed: %w", apperr.ErrValidation)
-	}
-
-	// Resolve and check IP (uses cache to avoid blocking)
-	ips, err := resolveHostWithCache(host)
-	if err != nil {
-		return fmt.Errorf("cannot resolve hostname: %w", apperr.ErrValidation)
-	}
-
-	for _, ip := range ips {
-		// Reject private/internal network ranges
-		if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() {
-			return fmt.Errorf("URL resolves to private/internal network: %w", apperr.ErrValidation)
-		}
-	}
-
-	return nil
+	CallbackURL   string                    `json:"callback_url,omitempty"    validate:"omitempty,url"`
+	Tags          map[string]string         `json:"tags,omitempty"`
 }
 
 // SubmitECGAnalyze handles EKG image analysis submission.
@@ -121,14 +50,10 @@ func (h *ECGHandler) SubmitECGAnalyze(w http.ResponseWriter, r *http.Request) {
 	h.submitECGURL(w, r)
 }
 
-func ecgParamsFromRequest(req *ekgAnalyzeRequest) service.ECGParams {
-	p := service.ECGParams{
-		Age:           req.Age,
-		Sex:           req.Sex,
-		PaperSpeedMMS: 25,
-		MmPerMvLimb:   10,
-		MmPerMvChest:  10,
-	}
+func ecgParamsFromRequest(req *ekgParamsRequest) service.ECGParams {
+	p := service.DefaultECGParams()
+	p.Age = req.Age
+	p.Sex = req.Sex
 	if req.PaperSpeedMMS != nil {
 		p.PaperSpeedMMS = *req.PaperSpeedMMS
 	}
@@ -139,6 +64,8 @@ func ecgParamsFromRequest(req *ekgAnalyzeRequest) service.ECGParams {
 		p.MmPerMvChest = *req.MmPerMvChest
 	}
 	p.ClientMeta = req.ClientMeta
+	p.CallbackURL = req.CallbackURL
+	p.Tags = req.Tags
 	return p
 }
 
@@ -156,12 +83,21 @@ func (h *ECGHandler) submitECGURL(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if !validTags(w, req.Tags) {
+		return
+	}
 
 	// SSRF protection: validate that URL is not to internal networks
-	if err := isSSRFSafeURL(req.ImageTempURL); err != nil {
+	if err := validation.SSRFSafeURL(req.ImageTempURL); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid image URL")
 		return
 	}
+	if req.CallbackURL != "" {
+		if err := validation.SSRFSafeURL(req.CallbackURL); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid callback_url")
+			return
+		}
+	}
 
 	userID, _, ok := extractUserID(r)
 	if !ok {
@@ -169,7 +105,7 @@ func (h *ECGHandler) submitECGURL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	params := ecgParamsFromRequest(&req)
+	params := ecgParamsFromRequest(&req.ekgParamsRequest)
 	result, err := h.Service.SubmitECG(r.Context(), userID, req.ImageTempURL, params)
 	if err != nil {
 		handleServiceError(w, err)
@@ -186,8 +122,7 @@ func (h *ECGHandler) submitECGURL(w http.ResponseWriter, r *http.Request) {
 
 // submitECGFile handles file-based EKG submission (multipart upload).
 func (h *ECGHandler) submitECGFile(w http.ResponseWriter, r *http.Request) {
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
-		writeError(w, http.StatusBadRequest, "failed to parse form")
+	if !parseMultipart(w, r, 1, h.MaxImageBytes, h.MultipartMemory) {
 		return
 	}
 	defer func() {
@@ -203,21 +138,53 @@ func (h *ECGHandler) submitECGFile(w http.ResponseWriter, r *http.Request) {
 	}
 	defer func() { _ = file.Close() }()
 
-	params := service.ECGParams{
-		Sex:           r.FormValue("sex"),
-		PaperSpeedMMS: 25,
-		MmPerMvLimb:   10,
-		MmPerMvChest:  10,
+	params, ok := ecgParamsFromForm(w, r)
+	if !ok {
+		return
 	}
+
+	userID, _, ok := extractUserID(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	uploaded := service.UploadedFile{
+		Reader:      file,
+		Filename:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Size:        header.Size,
+	}
+
+	result, err := h.Service.SubmitECGFile(r.Context(), userID, uploaded, params)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, SubmitECGResponse{
+		JobID:     result.JobID,
+		RequestID: result.RequestID,
+		Status:    result.Status,
+		Message:   fmt.Sprintf("EKG analysis job submitted successfully (file: %s)", header.Filename),
+	})
+}
+
+// ecgParamsFromForm reads EKG parameters from a parsed multipart form. Out of
+// range numbers fall back to defaults; an invalid client_meta, callback_url
+// or tags writes a 400 and returns false.
+func ecgParamsFromForm(w http.ResponseWriter, r *http.Request) (params service.ECGParams, ok bool) {
+	params = service.DefaultECGParams()
+	params.Sex = r.FormValue("sex")
 	if rawClientMeta := r.FormValue("client_meta"); rawClientMeta != "" {
 		var clientMeta models.RequestClientMeta
 		if err := json.Unmarshal([]byte(rawClientMeta), &clientMeta); err != nil {
 			writeError(w, http.StatusBadRequest, "invalid client_meta")
-			return
+			return service.ECGParams{}, false
 		}
 		if err := clientMeta.Validate(); err != nil {
 			writeError(w, http.StatusBadRequest, "invalid client_meta")
-			return
+			return service.ECGParams{}, false
 		}
 		params.ClientMeta = &clientMeta
 	}
@@ -241,30 +208,15 @@ func (h *ECGHandler) submitECGFile(w http.ResponseWriter, r *http.Request) {
 			params.MmPerMvChest = f
 		}
 	}
-
-	userID, _, ok := extractUserID(r)
-	if !ok {
-		writeError(w, http.StatusBadRequest, "invalid user ID")
-		return
-	}
-
-	uploaded := service.UploadedFile{
-		Reader:      file,
-		Filename:    header.Filename,
-		ContentType: header.Header.Get("Content-Type"),
-		Size:        header.Size,
+	if v := r.FormValue("callback_url"); v != "" {
+		if err := validation.SSRFSafeURL(v); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid callback_url")
+			return service.ECGParams{}, false
+		}
+		params.CallbackURL = v
 	}
-
-	result, err := h.Service.SubmitECGFile(r.Context(), userID, uploaded, params)
-	if err != nil {
-		handleServiceError(w, err)
-		return
+	if params.Tags, ok = tagsFromForm(w, r); !ok {
+		return service.ECGParams{}, false
 	}
-
-	writeJSON(w, http.StatusOK, SubmitECGResponse{
-		JobID:     result.JobID,
-		RequestID: result.RequestID,
-		Status:    result.Status,
-		Message:   fmt.Sprintf("EKG analysis job submitted successfully (file: %s)", header.Filename),
-	})
+	return params, true
 }
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/service"
+	"github.com/fedutinova/smartheart/back-api/validation"
+)
+
+// SubmitECGBatch handles batch EKG submission: a JSON body with
+// image_temp_urls, or multipart/form-data with one or more "images" files.
+// Each image becomes a child request and job of a new batch; invalid images
+// are rejected individually.
+func (h *ECGHandler) SubmitECGBatch(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		h.submitECGBatchFiles(w, r)
+		return
+	}
+	h.submitECGBatchURLs(w, r)
+}
+
+func (h *ECGHandler) submitECGBatchURLs(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+
+	var req ekgBatchRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	if len(req.ImageTempURLs) > service.MaxECGBatchSize {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("at most %d images per batch", service.MaxECGBatchSize))
+		return
+	}
+	if req.ClientMeta != nil {
+		if err := req.ClientMeta.Validate(); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid client_meta")
+			return
+		}
+	}
+	if !validTags(w, req.Tags) {
+		return
+	}
+	if req.CallbackURL != "" {
+		if err := validation.SSRFSafeURL(req.CallbackURL); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid callback_url")
+			return
+		}
+	}
+
+	userID, _, ok := extractUserID(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	// SSRF-check each URL; rejected ones are reported without being submitted.
+	rejected := make(map[int]error)
+	var items []service.ECGBatchItem
+	var indexes []int
+	for i, u := range req.ImageTempURLs {
+		if err := validation.SSRFSafeURL(u); err != nil {
+			rejected[i] = fmt.Errorf("invalid image URL: %w", apperr.ErrValidation)
+			continue
+		}
+		items = append(items, service.ECGBatchItem{ImageURL: u})
+		indexes = append(indexes, i)
+	}
+
+	h.submitBatch(w, r, userID, items, indexes, rejected, len(req.ImageTempURLs), ecgParamsFromRequest(&req.ekgParamsRequest))
+}
+
+func (h *ECGHandler) submitECGBatchFiles(w http.ResponseWriter, r *http.Request) {
+	if !parseMultipart(w, r, service.MaxECGBatchSize, h.MaxImageBytes, h.MultipartMemory) {
+		return
+	}
+	defer func() {
+		if r.MultipartForm != nil {
+			_ = r.MultipartForm.RemoveAll()
+		}
+	}()
+
+	headers := r.MultipartForm.File["images"]
+	if len(headers) == 0 {
+		writeError(w, http.StatusBadRequest, "at least one image file is required")
+		return
+	}
+	if len(headers) > service.MaxECGBatchSize {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("at most %d images per batch", service.MaxECGBatchSize))
+		return
+	}
+
+	params, ok := ecgParamsFromForm(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _, ok := extractUserID(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	rejected := make(map[int]error)
+	var items []service.ECGBatchItem
+	var indexes []int
+	for i, header := range headers {
+		file, err := header.Open()
+		if err != nil {
+			rejected[i] = fmt.Errorf("failed to read file: %w", apperr.ErrValidation)
+			continue
+		}
+		defer func(f multipart.File) { _ = f.Close() }(file)
+
+		items = append(items, service.ECGBatchItem{File: &service.UploadedFile{
+			Reader:      file,
+			Filename:    header.Filename,
+			ContentType: header.Header.Get("Content-Type"),
+			Size:        header.Size,
+		}})
+		indexes = append(indexes, i)
+	}
+
+	h.submitBatch(w, r, userID, items, indexes, rejected, len(headers), params)
+}
+
+// submitBatch submits the items that passed handler checks and merges the
+// results with the rejected ones back into input order. indexes[i] is the
+// input position of items[i].
+func (h *ECGHandler) submitBatch(
+	w http.ResponseWriter, r *http.Request,
+	userID uuid.UUID, items []service.ECGBatchItem, indexes []int,
+	rejected map[int]error, total int, params service.ECGParams,
+) {
+	if len(items) == 0 {
+		writeError(w, http.StatusBadRequest, "no valid images in batch")
+		return
+	}
+
+	result, err := h.Service.SubmitECGBatch(r.Context(), userID, items, params)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	resp := SubmitECGBatchResponse{
+		BatchID: result.BatchID,
+		Items:   make([]ECGBatchItemResponse, total),
+	}
+	for i, err := range rejected {
+		_, body := serviceErrorBody(err)
+		resp.Items[i] = ECGBatchItemResponse{Index: i, Status: batchItemRejected, Error: &body}
+	}
+	for k, res := range result.Items {
+		i := indexes[k]
+		if res.Err != nil {
+			_, body := serviceErrorBody(res.Err)
+			resp.Items[i] = ECGBatchItemResponse{Index: i, Status: batchItemRejected, Error: &body}
+			continue
+		}
+		resp.Items[i] = ECGBatchItemResponse{
+			Index:     i,
+			JobID:     &res.Job.JobID,
+			RequestID: &res.Job.RequestID,
+			Status:    res.Job.Status,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GetECGBatch returns a batch's child requests with aggregated status.
+func (h *RequestHandler) GetECGBatch(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid batch ID")
+		return
+	}
+
+	_, claims, ok := extractUserID(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "no auth context")
+		return
+	}
+
+	batch, err := h.Service.GetECGBatch(r.Context(), id, claims)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, batch)
+}
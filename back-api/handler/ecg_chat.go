@@ -46,8 +46,6 @@ func (h *ECGChatHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
 // PostMessage handles POST /v1/ecg/{id}/chat/messages — sends a user question
 // and returns the assistant's reply (with citations).
 func (h *ECGChatHandler) PostMessage(w http.ResponseWriter, r *http.Request) {
-	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
-
 	requestID, err := parseUUID(chi.URLParam(r, "id"))
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "invalid request ID")
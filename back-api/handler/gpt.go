@@ -1,18 +1,26 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
+	"slices"
+	"strconv"
+	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/fedutinova/smartheart/back-api/gpt"
 	"github.com/fedutinova/smartheart/back-api/service"
 	"github.com/fedutinova/smartheart/back-api/validation"
 )
 
 // SubmitGPTRequest handles GPT processing request with file uploads.
 func (h *GPTHandler) SubmitGPTRequest(w http.ResponseWriter, r *http.Request) {
-	if err := r.ParseMultipartForm(32 << 20); err != nil {
-		writeError(w, http.StatusBadRequest, "failed to parse form")
+	if !parseMultipart(w, r, validation.MaxFiles, h.MaxImageBytes, h.MultipartMemory) {
 		return
 	}
 	defer func() {
@@ -21,14 +29,12 @@ func (h *GPTHandler) SubmitGPTRequest(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	textQuery := r.FormValue("text_query")
-	files := r.MultipartForm.File["files"]
-
-	if validationErrs := validation.ValidateGPTRequest(textQuery, files); len(validationErrs) > 0 {
-		writeJSON(w, http.StatusBadRequest, APIError{
-			Error:   "validation failed",
-			Details: validationErrs,
-		})
+	textQuery, files, ok := h.validGPTForm(w, r)
+	if !ok {
+		return
+	}
+	opts, ok := h.gptOptionsFromForm(w, r)
+	if !ok {
 		return
 	}
 
@@ -62,19 +68,23 @@ func (h *GPTHandler) SubmitGPTRequest(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	result, err := h.Service.SubmitGPT(r.Context(), userID, textQuery, uploaded)
+	result, err := h.Service.SubmitGPT(r.Context(), userID, textQuery, uploaded, opts)
 	if err != nil {
 		if result != nil && len(result.UploadErrors) > 0 {
-			writeJSON(w, http.StatusBadRequest, APIError{
-				Error:        "no files successfully processed",
+			writeJSON(w, http.StatusBadRequest, APIError{Error: ErrorBody{
+				Code:         codeValidation,
+				Message:      "no files successfully processed",
 				UploadErrors: result.UploadErrors,
-			})
+			}})
 			return
 		}
 		handleServiceError(w, err)
 		return
 	}
 
+	if result.TokensRemaining != nil {
+		w.Header().Set("X-Token-Budget-Remaining", strconv.Itoa(*result.TokensRemaining))
+	}
 	writeJSON(w, http.StatusOK, SubmitGPTResponse{
 		RequestID:      result.RequestID,
 		JobID:          result.JobID,
@@ -83,3 +93,181 @@ func (h *GPTHandler) SubmitGPTRequest(w http.ResponseWriter, r *http.Request) {
 		UploadErrors:   result.UploadErrors,
 	})
 }
+
+// ValidateGPTRequest runs the checks of SubmitGPTRequest, options included, on
+// a multipart form without creating a request, uploading files or enqueueing
+// a job, so clients can report problems before submitting.
+func (h *GPTHandler) ValidateGPTRequest(w http.ResponseWriter, r *http.Request) {
+	if !parseMultipart(w, r, validation.MaxFiles, h.MaxImageBytes, h.MultipartMemory) {
+		return
+	}
+	defer func() {
+		if r.MultipartForm != nil {
+			_ = r.MultipartForm.RemoveAll()
+		}
+	}()
+
+	_, files, ok := h.validGPTForm(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.gptOptionsFromForm(w, r); !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, ValidateGPTResponse{Valid: true, Files: len(files)})
+}
+
+// validGPTForm validates the text query and files of a parsed GPT multipart
+// form, writing a 400 with the validation errors and returning false if they
+// are invalid.
+func (h *GPTHandler) validGPTForm(w http.ResponseWriter, r *http.Request) (string, []*multipart.FileHeader, bool) {
+	textQuery := r.FormValue("text_query")
+	files := r.MultipartForm.File["files"]
+
+	if validationErrs := validation.ValidateGPTRequest(textQuery, files, h.MaxImageBytes); len(validationErrs) > 0 {
+		writeJSON(w, http.StatusBadRequest, APIError{Error: ErrorBody{
+			Code:    codeValidation,
+			Message: "validation failed",
+			Details: validationErrs,
+		}})
+		return "", nil, false
+	}
+	return textQuery, files, true
+}
+
+// gptOptionsFromForm reads the optional submission settings of a parsed GPT
+// multipart form, writing a 400 and returning false if any is invalid.
+func (h *GPTHandler) gptOptionsFromForm(w http.ResponseWriter, r *http.Request) (service.GPTOptions, bool) {
+	opts := service.GPTOptions{
+		Structured: r.FormValue("structured") == "true",
+		NoCache:    r.FormValue("no_cache") == "true",
+	}
+	if v := r.FormValue("callback_url"); v != "" {
+		if err := validation.SSRFSafeURL(v); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid callback_url")
+			return opts, false
+		}
+		opts.CallbackURL = v
+	}
+	if v := r.FormValue("timeout_ms"); v != "" {
+		ms, err := strconv.Atoi(v)
+		timeout := time.Duration(ms) * time.Millisecond
+		if err != nil || ms <= 0 || timeout > h.MaxTimeout {
+			writeJSONError(w, http.StatusBadRequest, codeValidation,
+				fmt.Sprintf("timeout_ms must be between 1 and %d", h.MaxTimeout.Milliseconds()))
+			return opts, false
+		}
+		opts.Timeout = timeout
+	}
+	if v := r.FormValue("image_detail"); v != "" {
+		detail := openai.ImageURLDetail(v)
+		if !gpt.ValidImageDetail(detail) {
+			writeJSONError(w, http.StatusBadRequest, codeValidation, "image_detail must be one of auto, low, high")
+			return opts, false
+		}
+		opts.ImageDetail = detail
+	}
+	if v := r.FormValue("max_tokens"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || !gpt.ValidMaxTokens(n) {
+			writeJSONError(w, http.StatusBadRequest, codeValidation,
+				fmt.Sprintf("max_tokens must be between 1 and %d", gpt.MaxCompletionTokens))
+			return opts, false
+		}
+		opts.MaxTokens = n
+	}
+	tags, ok := tagsFromForm(w, r)
+	if !ok {
+		return opts, false
+	}
+	opts.Tags = tags
+	return opts, true
+}
+
+// RetryRequest re-enqueues a failed GPT request using its stored files.
+func (h *GPTHandler) RetryRequest(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request ID")
+		return
+	}
+
+	_, claims, ok := extractUserID(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "no auth context")
+		return
+	}
+
+	result, err := h.Service.RetryGPT(r.Context(), id, claims)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	if result.TokensRemaining != nil {
+		w.Header().Set("X-Token-Budget-Remaining", strconv.Itoa(*result.TokensRemaining))
+	}
+	writeJSON(w, http.StatusOK, SubmitGPTResponse{
+		RequestID:      result.RequestID,
+		JobID:          result.JobID,
+		Status:         result.Status,
+		FilesProcessed: result.FilesProcessed,
+	})
+}
+
+// reanalyzeRequest is the optional body of ReanalyzeRequest.
+type reanalyzeRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+// ReanalyzeRequest runs a finished GPT request again on its stored files,
+// optionally with another allowed model or text query. The new response is
+// added alongside the earlier ones.
+func (h *GPTHandler) ReanalyzeRequest(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request ID")
+		return
+	}
+
+	// A chunked body has no declared length; an empty one means no overrides.
+	var req reanalyzeRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	if req.Model != "" && !slices.Contains(h.ReanalyzeModels, req.Model) {
+		writeJSONError(w, http.StatusBadRequest, codeValidation, fmt.Sprintf("model %q is not allowed", req.Model))
+		return
+	}
+	if len(req.Prompt) > validation.MaxTextLength {
+		writeJSONError(w, http.StatusBadRequest, codeValidation,
+			fmt.Sprintf("prompt exceeds maximum length of %d characters", validation.MaxTextLength))
+		return
+	}
+
+	_, claims, ok := extractUserID(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "no auth context")
+		return
+	}
+
+	result, err := h.Service.ReanalyzeGPT(r.Context(), id, claims, service.ReanalyzeOptions{Model: req.Model, TextQuery: req.Prompt})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	if result.TokensRemaining != nil {
+		w.Header().Set("X-Token-Budget-Remaining", strconv.Itoa(*result.TokensRemaining))
+	}
+	writeJSON(w, http.StatusOK, SubmitGPTResponse{
+		RequestID:      result.RequestID,
+		JobID:          result.JobID,
+		Status:         result.Status,
+		FilesProcessed: result.FilesProcessed,
+	})
+}
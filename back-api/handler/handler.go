@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
@@ -12,25 +13,46 @@ import (
 	"github.com/fedutinova/smartheart/back-api/repository"
 	"github.com/fedutinova/smartheart/back-api/service"
 	"github.com/fedutinova/smartheart/back-api/storage"
+	"github.com/fedutinova/smartheart/back-api/validation"
 )
 
 type AuthHandler struct {
 	Service service.AuthService
+	Keys    *auth.Keys
 	Config  config.Config
+	Audit   *Auditor
 }
 
 type ECGHandler struct {
 	Service service.SubmissionService
+	// MaxImageBytes is the size limit of each uploaded file.
+	MaxImageBytes int64
+	// MultipartMemory is how much of an upload is buffered in memory before
+	// spilling to a temporary file.
+	MultipartMemory int64
 }
 
 type GPTHandler struct {
 	Service service.SubmissionService
+	// MaxImageBytes is the size limit of each uploaded file.
+	MaxImageBytes int64
+	// MultipartMemory is how much of an upload is buffered in memory before
+	// spilling to a temporary file.
+	MultipartMemory int64
+	// ReanalyzeModels are the models a re-analysis may ask for.
+	ReanalyzeModels []string
+	// MaxTimeout is the largest timeout_ms a submission may ask for
+	// (0 = overrides rejected).
+	MaxTimeout time.Duration
 }
 
 type RequestHandler struct {
 	Service service.RequestService
 	Config  config.Config
 	Storage storage.Storage
+	// FileSigner verifies signed /files/* links issued by local storage.
+	FileSigner *storage.URLSigner
+	Audit      *Auditor
 }
 
 type HealthHandler struct {
@@ -38,6 +60,21 @@ type HealthHandler struct {
 	Repo     repository.Store
 	Sessions auth.SessionService
 	Storage  storage.Storage
+	// StorageCritical reports a failed storage probe as unhealthy rather
+	// than degraded.
+	StorageCritical bool
+	// CheckOpenAI enables the OpenAI probe; OpenAI is nil when no API key
+	// is configured or OpenAI is not the AI provider.
+	CheckOpenAI bool
+	OpenAI      ModelLister
+	// AIProvider is the configured model vendor; the OpenAI probe is
+	// skipped unless it is OpenAI.
+	AIProvider string
+	// Build identifies the running binary in health responses.
+	Build BuildInfo
+	// DBPool, when set, adds connection pool statistics to the database
+	// check.
+	DBPool PoolStatter
 }
 
 type Middleware = func(http.Handler) http.Handler
@@ -47,6 +84,7 @@ type Middlewares struct {
 	AnalyzeRateLimit       Middleware
 	SubscriptionRateLimit  Middleware
 	PasswordResetRateLimit Middleware
+	VerifyResendRateLimit  Middleware
 }
 
 type Handler struct {
@@ -62,6 +100,7 @@ type Handler struct {
 	Payment  *PaymentHandler
 	Profile  *ProfileHandler
 	Admin    *AdminHandler
+	Audit    *Auditor
 	Config   config.Config
 	MW       Middlewares
 }
@@ -76,24 +115,35 @@ func NewHandler(
 	queue job.Queue,
 	repo repository.Store,
 	sessions auth.SessionService,
+	keys *auth.Keys,
 	storageService storage.Storage,
 	hub *notify.Hub,
 	cfg config.Config,
 	mw Middlewares,
 ) *Handler {
+	audit := &Auditor{Repo: repo}
+	maxImageBytes := cfg.Storage.MaxImageBytes
+	if maxImageBytes <= 0 {
+		maxImageBytes = validation.DefaultMaxFileSize
+	}
+	multipartMemory := cfg.Storage.MultipartMemoryBytes
+	if multipartMemory <= 0 {
+		multipartMemory = validation.DefaultMultipartMemory
+	}
 	return &Handler{
-		Auth:     &AuthHandler{Service: authSvc, Config: cfg},
+		Auth:     &AuthHandler{Service: authSvc, Keys: keys, Config: cfg, Audit: audit},
 		Password: &PasswordHandler{Service: passwordSvc},
-		EKG:      &ECGHandler{Service: submissionSvc},
-		GPT:      &GPTHandler{Service: submissionSvc},
-		Request:  &RequestHandler{Service: requestSvc, Config: cfg, Storage: storageService},
-		Healthz:  &HealthHandler{Queue: queue, Repo: repo, Sessions: sessions, Storage: storageService},
+		EKG:      &ECGHandler{Service: submissionSvc, MaxImageBytes: maxImageBytes, MultipartMemory: multipartMemory},
+		GPT:      &GPTHandler{Service: submissionSvc, MaxImageBytes: maxImageBytes, MultipartMemory: multipartMemory, ReanalyzeModels: cfg.GPT.ReanalyzeModels, MaxTimeout: cfg.GPT.MaxTimeout},
+		Request:  &RequestHandler{Service: requestSvc, Config: cfg, Storage: storageService, FileSigner: storage.NewURLSigner(cfg.JWT.Secret), Audit: audit},
+		Healthz:  newHealthHandler(queue, repo, sessions, storageService, cfg),
 		Events:   &EventsHandler{Hub: hub},
-		RAG:      NewRAGHandler(cfg.RAG.URL, repo, cfg.GPT.APIKey),
+		RAG:      NewRAGHandler(cfg.RAG.URL, repo, cfg.GPT.APIKey, cfg.GPT.BaseURL),
 		ECGChat:  &ECGChatHandler{Service: ecgChatSvc},
 		Payment:  &PaymentHandler{Service: paymentSvc},
-		Profile:  &ProfileHandler{Repo: repo},
-		Admin:    &AdminHandler{Repo: repo},
+		Profile:  &ProfileHandler{Repo: repo, Service: authSvc},
+		Admin:    newAdminHandler(repo, queue),
+		Audit:    audit,
 		Config:   cfg,
 		MW:       mw,
 	}
@@ -101,13 +151,25 @@ func NewHandler(
 
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Get("/health", h.Healthz.Health)
+	r.Get("/version", h.Healthz.Version)
 
 	r.Get("/openapi.yaml", OpenAPISpec)
+	r.Get("/openapi.json", OpenAPISpecJSON)
+	r.Get("/docs", SwaggerUI)
+	r.Get("/.well-known/jwks.json", h.Auth.JWKS)
 
 	r.Group(func(r chi.Router) {
+		r.Use(requireJSON)
 		r.Post("/v1/auth/register", h.Auth.Register)
 		r.Post("/v1/auth/login", h.Auth.Login)
 		r.Post("/v1/auth/refresh", h.Auth.Refresh)
+		r.Post("/v1/auth/2fa/login", h.Auth.LoginTwoFactor)
+		r.Get("/v1/auth/verify", h.Auth.VerifyEmail)
+		if h.MW.VerifyResendRateLimit != nil {
+			r.With(h.MW.VerifyResendRateLimit).Post("/v1/auth/verify/resend", h.Auth.ResendVerification)
+		} else {
+			r.Post("/v1/auth/verify/resend", h.Auth.ResendVerification)
+		}
 		if h.MW.PasswordResetRateLimit != nil {
 			r.With(h.MW.PasswordResetRateLimit).Post("/v1/auth/password-reset", h.Password.RequestReset)
 		} else {
@@ -116,6 +178,11 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 		r.Post("/v1/auth/password-reset/confirm", h.Password.ConfirmReset)
 	})
 
+	if h.Config.Storage.Mode == config.StorageModeLocal || h.Config.Storage.Mode == config.StorageModeFilesystem {
+		jwt := auth.JWTMiddleware(h.Auth.Keys, h.Config.JWT.Issuer, auth.WithBlacklist(h.Healthz.Sessions))
+		r.With(h.Request.FileAccess(jwt)).Get("/files/*", h.Request.ServeFiles)
+	}
+
 	if h.MW.WebhookIP != nil {
 		r.With(h.MW.WebhookIP).Post("/v1/payments/webhook", h.Payment.Webhook)
 	} else {
@@ -123,54 +190,84 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 	}
 
 	r.Group(func(r chi.Router) {
-		r.Use(auth.JWTMiddleware(h.Config.JWT.Secret, h.Config.JWT.Issuer, auth.WithBlacklist(h.Healthz.Sessions)))
-
-		if h.Config.Storage.Mode == config.StorageModeLocal || h.Config.Storage.Mode == config.StorageModeFilesystem {
-			r.Get("/files/*", h.Request.ServeFiles)
-		}
+		r.Use(auth.JWTMiddleware(h.Auth.Keys, h.Config.JWT.Issuer, auth.WithBlacklist(h.Healthz.Sessions)))
 
-		r.Post("/v1/auth/logout", h.Auth.Logout)
-		r.Post("/v1/auth/password-change", h.Password.ChangePassword)
+		// JSON routes go through requireJSON; the multipart submit routes
+		// (analyze, batch, h2-compare, gpt/*) accept either encoding and
+		// bound their own bodies.
+		r.With(requireJSON).Post("/v1/auth/logout", h.Auth.Logout)
+		r.With(requireJSON).Post("/v1/auth/password-change", h.Password.ChangePassword)
+		r.With(requireJSON).Post("/v1/auth/2fa/enroll", h.Auth.EnrollTwoFactor)
+		r.With(requireJSON).Post("/v1/auth/2fa/verify", h.Auth.VerifyTwoFactor)
 
 		ekgMiddleware := []func(http.Handler) http.Handler{auth.RequirePerm(auth.PermECGSubmit)}
 		if h.MW.AnalyzeRateLimit != nil {
 			ekgMiddleware = append(ekgMiddleware, h.MW.AnalyzeRateLimit)
 		}
 		r.With(ekgMiddleware...).Post("/v1/ecg/analyze", h.EKG.SubmitECGAnalyze)
+		r.With(ekgMiddleware...).Post("/v1/ecg/batch", h.EKG.SubmitECGBatch)
 		r.With(ekgMiddleware...).Post("/v1/ecg/analyze-h2-compare", h.EKG.CompareH2Redaction)
 		r.With(ekgMiddleware...).Post("/v1/gpt/process", h.GPT.SubmitGPTRequest)
+		r.With(auth.RequirePerm(auth.PermECGSubmit)).Post("/v1/gpt/validate", h.GPT.ValidateGPTRequest)
+		r.With(ekgMiddleware...).With(requireJSON).Post("/v1/requests/{id}/retry", h.GPT.RetryRequest)
+		r.With(ekgMiddleware...).With(requireJSON).Post("/v1/requests/{id}/reanalyze", h.GPT.ReanalyzeRequest)
 
 		r.With(auth.RequirePerm(auth.PermJobReadOwn)).Get("/v1/jobs/{id}", h.Request.GetJob)
+		r.With(auth.RequirePerm(auth.PermJobReadOwn)).Get("/v1/requests/stats", h.Request.GetRequestStats)
 		r.With(auth.RequirePerm(auth.PermJobReadOwn)).Get("/v1/requests/{id}", h.Request.GetRequest)
+		r.With(auth.RequirePerm(auth.PermJobReadOwn)).Get("/v1/ecg/batch/{id}", h.Request.GetECGBatch)
+		r.With(auth.RequirePerm(auth.PermJobReadOwn)).Delete("/v1/requests/{id}", h.Request.DeleteRequest)
 		r.With(auth.RequirePerm(auth.PermJobReadOwn)).Get("/v1/requests/{id}/files/{fileId}/url", h.Request.GetRequestFileURL)
 		r.With(auth.RequirePerm(auth.PermJobReadOwn)).Get("/v1/requests/{id}/files/{fileId}", h.Request.GetRequestFile)
 		r.With(auth.RequirePerm(auth.PermJobReadOwn)).Get("/v1/requests", h.Request.GetUserRequests)
+		r.With(auth.RequirePerm(auth.PermJobReadOwn)).Get("/v1/files/{id}", h.Request.DownloadFile)
+		r.With(auth.RequirePerm(auth.PermJobReadOwn)).Get("/v1/files/{id}/thumbnail", h.Request.DownloadThumbnail)
 
 		r.Get("/v1/events", h.Events.StreamEvents)
 
-		r.Post("/v1/rag/query", h.RAG.Query)
-		r.Post("/v1/rag/feedback", h.RAG.Feedback)
+		r.With(requireJSON).Post("/v1/rag/query", h.RAG.Query)
+		r.With(requireJSON).Post("/v1/rag/feedback", h.RAG.Feedback)
 
 		r.With(auth.RequirePerm(auth.PermJobReadOwn)).Get("/v1/ecg/{id}/chat", h.ECGChat.GetMessages)
-		r.With(auth.RequirePerm(auth.PermJobReadOwn)).Post("/v1/ecg/{id}/chat/messages", h.ECGChat.PostMessage)
+		r.With(auth.RequirePerm(auth.PermJobReadOwn), requireJSON).Post("/v1/ecg/{id}/chat/messages", h.ECGChat.PostMessage)
 
 		r.Get("/v1/me", h.Profile.GetMe)
+		r.Get("/v1/users/me", h.Profile.GetMe)
+		r.With(requireJSON).Patch("/v1/users/me", h.Profile.UpdateMe)
+		r.With(requireJSON).Post("/v1/users/me/password", h.Password.ChangePassword)
 
 		r.Get("/v1/quota", h.Payment.GetQuota)
-		r.Post("/v1/promo/validate", h.Payment.ApplyPromoCode)
+		r.With(requireJSON).Post("/v1/promo/validate", h.Payment.ApplyPromoCode)
 		if h.MW.SubscriptionRateLimit != nil {
-			r.With(h.MW.SubscriptionRateLimit).Post("/v1/subscriptions", h.Payment.CreateSubscription)
+			r.With(h.MW.SubscriptionRateLimit, requireJSON).Post("/v1/subscriptions", h.Payment.CreateSubscription)
 		} else {
-			r.Post("/v1/subscriptions", h.Payment.CreateSubscription)
+			r.With(requireJSON).Post("/v1/subscriptions", h.Payment.CreateSubscription)
 		}
 
 		r.With(auth.RequirePerm(auth.PermAdminAll)).Get("/ready", h.Healthz.Ready)
 		r.Route("/v1/admin", func(r chi.Router) {
-			r.Use(auth.RequirePerm(auth.PermAdminAll))
-			r.Get("/stats", h.Admin.GetStats)
-			r.Get("/users", h.Admin.ListUsers)
-			r.Get("/payments", h.Admin.ListPayments)
-			r.Get("/feedback", h.Admin.ListFeedback)
+			r.With(auth.RequirePerm(auth.PermJobReadAll), h.Audit.Middleware).Get("/requests", h.Admin.ListRequests)
+
+			r.Group(func(r chi.Router) {
+				r.Use(auth.RequirePerm(auth.PermAdminAll))
+				r.Use(h.Audit.Middleware)
+				r.Use(requireJSON)
+				r.Get("/stats", h.Admin.GetStats)
+				r.Get("/users", h.Admin.ListUsers)
+				r.Get("/payments", h.Admin.ListPayments)
+				r.Get("/feedback", h.Admin.ListFeedback)
+				r.Get("/audit", h.Admin.ListAudit)
+				r.Post("/deadletter/retry", h.Admin.RetryDeadLetters)
+
+				r.Get("/roles", h.Admin.ListRoles)
+				r.Post("/roles", h.Admin.CreateRole)
+				r.Post("/roles/{name}/permissions", h.Admin.AttachPermission)
+				r.Delete("/roles/{name}/permissions/{permission}", h.Admin.DetachPermission)
+				r.Get("/permissions", h.Admin.ListPermissions)
+				r.Post("/permissions", h.Admin.CreatePermission)
+				r.Post("/users/{id}/roles", h.Admin.AssignRole)
+				r.Delete("/users/{id}/roles/{role}", h.Admin.UnassignRole)
+			})
 		})
 	})
 }
@@ -4,8 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -13,6 +19,8 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sashabaranov/go-openai"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/fedutinova/smartheart/back-api/apperr"
@@ -21,16 +29,21 @@ import (
 	"github.com/fedutinova/smartheart/back-api/config"
 	"github.com/fedutinova/smartheart/back-api/job"
 	jobmocks "github.com/fedutinova/smartheart/back-api/job/mocks"
+	"github.com/fedutinova/smartheart/back-api/models"
 	"github.com/fedutinova/smartheart/back-api/notify"
+	"github.com/fedutinova/smartheart/back-api/queue"
+	"github.com/fedutinova/smartheart/back-api/repository"
 	repomocks "github.com/fedutinova/smartheart/back-api/repository/mocks"
 	"github.com/fedutinova/smartheart/back-api/service"
 	svcmocks "github.com/fedutinova/smartheart/back-api/service/mocks"
 	storagemocks "github.com/fedutinova/smartheart/back-api/storage/mocks"
+	"github.com/fedutinova/smartheart/back-api/validation"
 )
 
 // --- Helpers ---
 
 type testDeps struct {
+	t             testing.TB
 	authSvc       *svcmocks.MockAuthService
 	passwordSvc   *svcmocks.MockPasswordService
 	submissionSvc *svcmocks.MockSubmissionService
@@ -42,10 +55,14 @@ type testDeps struct {
 	sessions      *authmocks.MockSessionService
 	storage       *storagemocks.MockStorage
 	config        config.Config
+
+	// audits collects the entries handlers write to the audit log.
+	audits []models.AuditEntry
 }
 
 func newTestDeps(t testing.TB) *testDeps {
-	return &testDeps{
+	d := &testDeps{
+		t:             t,
 		authSvc:       svcmocks.NewMockAuthService(t),
 		passwordSvc:   svcmocks.NewMockPasswordService(t),
 		submissionSvc: svcmocks.NewMockSubmissionService(t),
@@ -58,10 +75,23 @@ func newTestDeps(t testing.TB) *testDeps {
 		storage:       storagemocks.NewMockStorage(t),
 		config:        config.Config{JWT: config.JWTConfig{Secret: "test-secret", Issuer: "test"}},
 	}
+	d.repo.EXPECT().WriteAudit(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, e *models.AuditEntry) error {
+			d.audits = append(d.audits, *e)
+			return nil
+		}).Maybe()
+	return d
 }
 
 func (d *testDeps) handler() *Handler {
-	return NewHandler(d.authSvc, d.passwordSvc, d.submissionSvc, d.requestSvc, d.paymentSvc, d.ecgChatSvc, d.queue, d.repo, d.sessions, d.storage, notify.NewHub(), d.config, Middlewares{})
+	h := NewHandler(d.authSvc, d.passwordSvc, d.submissionSvc, d.requestSvc, d.paymentSvc, d.ecgChatSvc, d.queue, d.repo, d.sessions, auth.NewHS256Keys(d.config.JWT.Secret), d.storage, notify.NewHub(), d.config, Middlewares{})
+	d.t.Cleanup(h.Audit.Close)
+	return h
+}
+
+// flushAudit waits for the entries h has queued so far to land in d.audits.
+func (d *testDeps) flushAudit(h *Handler) {
+	h.Audit.Close()
 }
 
 func withAuthContext(r *http.Request, userID uuid.UUID, roles []string) *http.Request {
@@ -104,6 +134,227 @@ func TestHealth_ReturnsOK(t *testing.T) {
 	}
 }
 
+func TestVersion_ReturnsBuildInfo(t *testing.T) {
+	d := newTestDeps(t)
+	h := d.handler()
+	h.Healthz.Build = BuildInfo{Version: "v1.2.3", Commit: "abc123", BuildTime: "2026-01-01T00:00:00Z"}
+
+	w := httptest.NewRecorder()
+	h.Healthz.Version(w, httptest.NewRequest("GET", "/version", http.NoBody))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var info BuildInfo
+	if err := json.NewDecoder(w.Body).Decode(&info); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if info.Version != "v1.2.3" || info.Commit != "abc123" || info.BuildTime != "2026-01-01T00:00:00Z" {
+		t.Errorf("unexpected build info: %+v", info)
+	}
+	if info.GoVersion == "" {
+		t.Error("expected go_version to be set")
+	}
+}
+
+func TestReady_StorageFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		critical   bool
+		wantStatus string
+		wantCode   int
+	}{
+		{"degraded by default", false, StatusDegraded, http.StatusOK},
+		{"unhealthy when critical", true, StatusUnhealthy, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			d.config.Storage.HealthCritical = tt.critical
+			d.repo.EXPECT().Ping(mock.Anything).Return(nil)
+			d.sessions.EXPECT().Ping(mock.Anything).Return(nil)
+			d.queue.EXPECT().Stats(mock.Anything).Return(job.QueueStats{}, nil)
+			d.storage.EXPECT().HealthCheck(mock.Anything).Return(errors.New("bucket not found"))
+			h := d.handler()
+
+			w := httptest.NewRecorder()
+			h.Healthz.Ready(w, httptest.NewRequest("GET", "/ready", http.NoBody))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d", tt.wantCode, w.Code)
+			}
+			var status HealthStatus
+			if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if status.Status != tt.wantStatus {
+				t.Errorf("expected %s, got %s", tt.wantStatus, status.Status)
+			}
+			if status.Checks["storage"].Status != tt.wantStatus {
+				t.Errorf("expected storage check %s, got %s", tt.wantStatus, status.Checks["storage"].Status)
+			}
+		})
+	}
+}
+
+type stubModelLister struct{ err error }
+
+func (s stubModelLister) ListModels(context.Context) (openai.ModelsList, error) {
+	return openai.ModelsList{}, s.err
+}
+
+func TestReady_OpenAICheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		lister      ModelLister
+		wantCheck   string
+		wantOverall string
+	}{
+		{"not configured", nil, StatusSkipped, StatusHealthy},
+		{"reachable", stubModelLister{}, StatusHealthy, StatusHealthy},
+		{"invalid key", stubModelLister{err: errors.New("401 unauthorized")}, StatusDegraded, StatusDegraded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			d.repo.EXPECT().Ping(mock.Anything).Return(nil)
+			d.sessions.EXPECT().Ping(mock.Anything).Return(nil)
+			d.queue.EXPECT().Stats(mock.Anything).Return(job.QueueStats{}, nil)
+			d.storage.EXPECT().HealthCheck(mock.Anything).Return(nil)
+			h := d.handler()
+			h.Healthz.CheckOpenAI = true
+			h.Healthz.OpenAI = tt.lister
+
+			w := httptest.NewRecorder()
+			h.Healthz.Ready(w, httptest.NewRequest("GET", "/ready", http.NoBody))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", w.Code)
+			}
+			var status HealthStatus
+			if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got := status.Checks["openai"].Status; got != tt.wantCheck {
+				t.Errorf("expected openai check %s, got %s", tt.wantCheck, got)
+			}
+			if status.Status != tt.wantOverall {
+				t.Errorf("expected overall %s, got %s", tt.wantOverall, status.Status)
+			}
+		})
+	}
+}
+
+func TestReady_OpenAICheckSkippedForAnthropic(t *testing.T) {
+	cfg := config.Config{GPT: config.GPTConfig{
+		Provider:    config.AIProviderAnthropic,
+		HealthCheck: true,
+		APIKey:      "sk-openai",
+	}}
+
+	d := newTestDeps(t)
+	d.repo.EXPECT().Ping(mock.Anything).Return(nil)
+	d.sessions.EXPECT().Ping(mock.Anything).Return(nil)
+	d.queue.EXPECT().Stats(mock.Anything).Return(job.QueueStats{}, nil)
+	d.storage.EXPECT().HealthCheck(mock.Anything).Return(nil)
+	hh := newHealthHandler(d.queue, d.repo, d.sessions, d.storage, cfg)
+	if hh.OpenAI != nil {
+		t.Fatal("expected no OpenAI client when the provider is anthropic")
+	}
+
+	w := httptest.NewRecorder()
+	hh.Ready(w, httptest.NewRequest("GET", "/ready", http.NoBody))
+
+	var status HealthStatus
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got := status.Checks["openai"].Status; got != StatusSkipped {
+		t.Errorf("expected openai check %s, got %s", StatusSkipped, got)
+	}
+}
+
+func TestReady_DatabasePoolStats(t *testing.T) {
+	// The pool connects lazily, so Stat works without a server.
+	pool, err := pgxpool.New(context.Background(), "postgres://app@127.0.0.1:1/smartheart?pool_max_conns=7")
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	defer pool.Close()
+
+	d := newTestDeps(t)
+	d.repo.EXPECT().Ping(mock.Anything).Return(nil)
+	d.sessions.EXPECT().Ping(mock.Anything).Return(nil)
+	d.storage.EXPECT().HealthCheck(mock.Anything).Return(nil)
+	d.queue.EXPECT().Stats(mock.Anything).Return(job.QueueStats{}, nil)
+	h := d.handler()
+	h.Healthz.DBPool = pool
+
+	w := httptest.NewRecorder()
+	h.Healthz.Ready(w, httptest.NewRequest("GET", "/ready", http.NoBody))
+
+	var status HealthStatus
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	check := status.Checks["database"]
+	if check.Pool == nil || check.Pool.MaxConns != 7 {
+		t.Fatalf("expected pool stats with max_conns 7, got %+v", check.Pool)
+	}
+	if want := "connection successful (pool: total 0/7, acquired 0, idle 0, waited acquires 0)"; check.Message != want {
+		t.Errorf("message = %q, want %q", check.Message, want)
+	}
+	if check.Status != StatusHealthy {
+		t.Errorf("pool details must not change the status, got %s", check.Status)
+	}
+}
+
+func TestReady_QueueUnavailable(t *testing.T) {
+	d := newTestDeps(t)
+	d.repo.EXPECT().Ping(mock.Anything).Return(nil)
+	d.sessions.EXPECT().Ping(mock.Anything).Return(nil)
+	d.storage.EXPECT().HealthCheck(mock.Anything).Return(nil)
+	d.queue.EXPECT().Stats(mock.Anything).
+		Return(job.QueueStats{}, fmt.Errorf("%w: connection refused", job.ErrQueueUnavailable))
+	h := d.handler()
+
+	w := httptest.NewRecorder()
+	h.Healthz.Ready(w, httptest.NewRequest("GET", "/ready", http.NoBody))
+
+	var status HealthStatus
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got := status.Checks["queue"].Status; got != StatusUnhealthy {
+		t.Errorf("expected queue check %s, got %s", StatusUnhealthy, got)
+	}
+	if status.Status != StatusDegraded {
+		t.Errorf("expected overall %s, got %s", StatusDegraded, status.Status)
+	}
+}
+
+func TestReady_QueueStreamLength(t *testing.T) {
+	d := newTestDeps(t)
+	d.repo.EXPECT().Ping(mock.Anything).Return(nil)
+	d.sessions.EXPECT().Ping(mock.Anything).Return(nil)
+	d.storage.EXPECT().HealthCheck(mock.Anything).Return(nil)
+	d.queue.EXPECT().Stats(mock.Anything).
+		Return(job.QueueStats{Queued: 2, Running: 1, Pending: 3, Length: 840}, nil)
+	h := d.handler()
+
+	w := httptest.NewRecorder()
+	h.Healthz.Ready(w, httptest.NewRequest("GET", "/ready", http.NoBody))
+
+	var status HealthStatus
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	want := "queue operational (queued: 2, running: 1, scheduled: 0, stream length: 840)"
+	if got := status.Checks["queue"].Message; got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+}
+
 // --- EKG handler tests ---
 
 func TestSubmitECGAnalyze_Success(t *testing.T) {
@@ -144,6 +395,94 @@ func TestSubmitECGAnalyze_Success(t *testing.T) {
 	}
 }
 
+func TestSubmitECGAnalyze_RejectsPrivateCallbackURL(t *testing.T) {
+	d := newTestDeps(t)
+	h := d.handler()
+
+	body, _ := json.Marshal(map[string]string{
+		"image_temp_url": "https://8.8.8.8/ekg.jpg",
+		"callback_url":   "https://10.0.0.1/hook",
+	})
+	req := httptest.NewRequest("POST", "/v1/ecg/analyze", bytes.NewReader(body))
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	w := httptest.NewRecorder()
+
+	h.EKG.SubmitECGAnalyze(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSubmitECGAnalyze_PassesCallbackURL(t *testing.T) {
+	d := newTestDeps(t)
+
+	d.submissionSvc.EXPECT().
+		SubmitECG(mock.Anything, mock.Anything, "https://8.8.8.8/ekg.jpg",
+			mock.MatchedBy(func(p service.ECGParams) bool { return p.CallbackURL == "https://8.8.4.4/hook" })).
+		Return(&service.SubmittedJob{JobID: uuid.New(), RequestID: uuid.New(), Status: "queued"}, nil)
+
+	h := d.handler()
+
+	body, _ := json.Marshal(map[string]string{
+		"image_temp_url": "https://8.8.8.8/ekg.jpg",
+		"callback_url":   "https://8.8.4.4/hook",
+	})
+	req := httptest.NewRequest("POST", "/v1/ecg/analyze", bytes.NewReader(body))
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	w := httptest.NewRecorder()
+
+	h.EKG.SubmitECGAnalyze(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSubmitECGAnalyze_PassesTags(t *testing.T) {
+	d := newTestDeps(t)
+
+	d.submissionSvc.EXPECT().
+		SubmitECG(mock.Anything, mock.Anything, "https://8.8.8.8/ekg.jpg",
+			mock.MatchedBy(func(p service.ECGParams) bool { return p.Tags["study"] == "af-2026" })).
+		Return(&service.SubmittedJob{JobID: uuid.New(), RequestID: uuid.New(), Status: "queued"}, nil)
+
+	h := d.handler()
+
+	body, _ := json.Marshal(map[string]any{
+		"image_temp_url": "https://8.8.8.8/ekg.jpg",
+		"tags":           map[string]string{"study": "af-2026"},
+	})
+	req := httptest.NewRequest("POST", "/v1/ecg/analyze", bytes.NewReader(body))
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	w := httptest.NewRecorder()
+
+	h.EKG.SubmitECGAnalyze(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSubmitECGAnalyze_RejectsInvalidTags(t *testing.T) {
+	d := newTestDeps(t)
+	h := d.handler()
+
+	body, _ := json.Marshal(map[string]any{
+		"image_temp_url": "https://8.8.8.8/ekg.jpg",
+		"tags":           map[string]string{"no spaces": "x"},
+	})
+	req := httptest.NewRequest("POST", "/v1/ecg/analyze", bytes.NewReader(body))
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	w := httptest.NewRecorder()
+
+	h.EKG.SubmitECGAnalyze(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
 func TestSubmitECGAnalyze_EmptyBody(t *testing.T) {
 	d := newTestDeps(t)
 	h := d.handler()
@@ -194,6 +533,36 @@ func TestSubmitECGAnalyze_MissingImageURL(t *testing.T) {
 	}
 }
 
+func TestSubmitECGAnalyze_RejectsInvalidBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not a URL", `{"image_temp_url": "ekg.jpg"}`},
+		{"ftp scheme", `{"image_temp_url": "ftp://8.8.8.8/ekg.jpg"}`},
+		{"plain http", `{"image_temp_url": "http://8.8.8.8/ekg.jpg"}`},
+		{"javascript scheme", `{"image_temp_url": "javascript:alert(1)"}`},
+		{"loopback host", `{"image_temp_url": "https://127.0.0.1/ekg.jpg"}`},
+		{"unknown notes field", `{"image_temp_url": "https://8.8.8.8/ekg.jpg", "notes": "` + strings.Repeat("x", 5000) + `"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// No service expectations: the request must not get that far.
+			h := newTestDeps(t).handler()
+
+			req := httptest.NewRequest("POST", "/v1/ecg/analyze", strings.NewReader(tt.body))
+			req = withAuthContext(req, uuid.New(), []string{"user"})
+			w := httptest.NewRecorder()
+
+			h.EKG.SubmitECGAnalyze(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
 func TestSubmitECGAnalyze_NoAuthContext(t *testing.T) {
 	d := newTestDeps(t)
 	h := d.handler()
@@ -231,110 +600,1194 @@ func TestSubmitECGAnalyze_ServiceError(t *testing.T) {
 	}
 }
 
-// --- GetJob tests ---
-
-func TestGetJob_NotFound(t *testing.T) {
+func TestSubmitECGAnalyze_QueueFull(t *testing.T) {
 	d := newTestDeps(t)
-	jobID := uuid.New()
 
-	d.requestSvc.EXPECT().
-		GetJobStatus(mock.Anything, jobID, mock.Anything).
-		Return(nil, apperr.ErrJobNotFound)
+	d.submissionSvc.EXPECT().
+		SubmitECG(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, apperr.WrapInternal("enqueue ECG job", job.ErrQueueFull))
 
 	h := d.handler()
 	userID := uuid.New()
 
-	req := httptest.NewRequest("GET", "/v1/jobs/"+jobID.String(), http.NoBody)
+	body, _ := json.Marshal(map[string]string{"image_temp_url": "https://8.8.8.8/ekg.jpg"})
+	req := httptest.NewRequest("POST", "/v1/ecg/analyze", bytes.NewReader(body))
 	req = withAuthContext(req, userID, []string{"user"})
-	req = addChiURLParam(req, "id", jobID.String())
 	w := httptest.NewRecorder()
 
-	h.Request.GetJob(w, req)
+	h.EKG.SubmitECGAnalyze(w, req)
 
-	if w.Code != http.StatusNotFound {
-		t.Fatalf("expected 404, got %d", w.Code)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected Retry-After header")
 	}
 }
 
-func TestGetJob_BadID(t *testing.T) {
+// --- EKG batch tests ---
+
+func TestSubmitECGBatch_ReportsItemsInInputOrder(t *testing.T) {
 	d := newTestDeps(t)
+	batchID, jobID := uuid.New(), uuid.New()
+
+	d.submissionSvc.EXPECT().
+		SubmitECGBatch(mock.Anything, mock.Anything,
+			mock.MatchedBy(func(items []service.ECGBatchItem) bool {
+				return len(items) == 2 && items[0].ImageURL == "https://8.8.8.8/a.jpg" && items[1].ImageURL == "https://8.8.4.4/c.jpg"
+			}), mock.Anything).
+		Return(&service.ECGBatchResult{
+			BatchID: batchID,
+			Items: []service.ECGBatchItemResult{
+				{Job: &service.SubmittedJob{JobID: jobID, RequestID: uuid.New(), Status: "queued"}},
+				{Err: fmt.Errorf("free limit exceeded: %w", apperr.ErrPaymentRequired)},
+			},
+		}, nil)
+
 	h := d.handler()
-	userID := uuid.New()
 
-	req := httptest.NewRequest("GET", "/v1/jobs/not-a-uuid", http.NoBody)
-	req = withAuthContext(req, userID, []string{"user"})
-	req = addChiURLParam(req, "id", "not-a-uuid")
+	body, _ := json.Marshal(map[string][]string{
+		"image_temp_urls": {"https://8.8.8.8/a.jpg", "https://10.0.0.1/b.jpg", "https://8.8.4.4/c.jpg"},
+	})
+	req := httptest.NewRequest("POST", "/v1/ecg/batch", bytes.NewReader(body))
+	req = withAuthContext(req, uuid.New(), []string{"user"})
 	w := httptest.NewRecorder()
 
-	h.Request.GetJob(w, req)
+	h.EKG.SubmitECGBatch(w, req)
 
-	if w.Code != http.StatusBadRequest {
-		t.Fatalf("expected 400, got %d", w.Code)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp SubmitECGBatchResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.BatchID != batchID || len(resp.Items) != 3 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if resp.Items[0].JobID == nil || *resp.Items[0].JobID != jobID {
+		t.Fatalf("expected item 0 to carry job %s, got %+v", jobID, resp.Items[0])
+	}
+	if resp.Items[1].Status != batchItemRejected || resp.Items[1].Error == nil || resp.Items[1].Error.Code != codeValidation {
+		t.Fatalf("expected item 1 rejected as invalid URL, got %+v", resp.Items[1])
+	}
+	if resp.Items[2].Error == nil || resp.Items[2].Error.Code != codePaymentRequired {
+		t.Fatalf("expected item 2 rejected for payment, got %+v", resp.Items[2])
 	}
 }
 
-func TestGetJob_Success(t *testing.T) {
+func TestSubmitECGBatch_TooManyImages(t *testing.T) {
 	d := newTestDeps(t)
-	userID := uuid.New()
-	jobID := uuid.New()
+	h := d.handler()
 
-	testJob := &job.Job{
-		ID:     jobID,
-		Type:   job.TypeECGAnalyze,
-		Status: job.StatusRunning,
+	urls := make([]string, service.MaxECGBatchSize+1)
+	for i := range urls {
+		urls[i] = "https://8.8.8.8/ekg.jpg"
+	}
+	body, _ := json.Marshal(map[string][]string{"image_temp_urls": urls})
+	req := httptest.NewRequest("POST", "/v1/ecg/batch", bytes.NewReader(body))
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	w := httptest.NewRecorder()
+
+	h.EKG.SubmitECGBatch(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
 	}
+}
+
+func TestGetECGBatch_Success(t *testing.T) {
+	d := newTestDeps(t)
+	batchID := uuid.New()
 
 	d.requestSvc.EXPECT().
-		GetJobStatus(mock.Anything, jobID, mock.Anything).
-		Return(testJob, nil)
+		GetECGBatch(mock.Anything, batchID, mock.Anything).
+		Return(&models.ECGBatch{ID: batchID, Status: models.BatchStatusProcessing, Requests: []models.Request{}}, nil)
 
 	h := d.handler()
 
-	req := httptest.NewRequest("GET", "/v1/jobs/"+jobID.String(), http.NoBody)
-	req = withAuthContext(req, userID, []string{"user"})
-	req = addChiURLParam(req, "id", jobID.String())
+	req := httptest.NewRequest("GET", "/v1/ecg/batch/"+batchID.String(), nil)
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", batchID.String())
 	w := httptest.NewRecorder()
 
-	h.Request.GetJob(w, req)
+	h.Request.GetECGBatch(w, req)
 
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
 	}
+	var batch models.ECGBatch
+	if err := json.NewDecoder(w.Body).Decode(&batch); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if batch.ID != batchID || batch.Status != models.BatchStatusProcessing {
+		t.Fatalf("unexpected batch: %+v", batch)
+	}
 }
 
-// --- Serialization tests ---
+func TestGetECGBatch_NotFound(t *testing.T) {
+	d := newTestDeps(t)
+	batchID := uuid.New()
 
-func TestEKGPayload_Roundtrip(t *testing.T) {
-	original := job.ECGJobPayload{
-		ImageTempURL: "http://example.com/test.jpg",
-		Notes:        "Test EKG analysis",
-		UserID:       uuid.New(),
-		RequestID:    uuid.New(),
-	}
+	d.requestSvc.EXPECT().
+		GetECGBatch(mock.Anything, batchID, mock.Anything).
+		Return(nil, apperr.ErrBatchNotFound)
 
-	data, err := json.Marshal(original)
-	if err != nil {
-		t.Fatalf("marshal: %v", err)
-	}
+	h := d.handler()
 
-	var decoded job.ECGJobPayload
-	if err := json.Unmarshal(data, &decoded); err != nil {
-		t.Fatalf("unmarshal: %v", err)
-	}
+	req := httptest.NewRequest("GET", "/v1/ecg/batch/"+batchID.String(), nil)
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", batchID.String())
+	w := httptest.NewRecorder()
 
-	if decoded.ImageTempURL != original.ImageTempURL {
-		t.Errorf("ImageTempURL: got %s, want %s", decoded.ImageTempURL, original.ImageTempURL)
-	}
-	if decoded.UserID != original.UserID {
-		t.Errorf("UserID: got %s, want %s", decoded.UserID, original.UserID)
-	}
-	if decoded.RequestID != original.RequestID {
-		t.Errorf("RequestID: got %s, want %s", decoded.RequestID, original.RequestID)
+	h.Request.GetECGBatch(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
 	}
 }
 
-func TestSubmitECGResponse_Roundtrip(t *testing.T) {
-	resp := SubmitECGResponse{
+// --- GPT upload tests ---
+
+func TestSubmitGPTRequest_OversizedBody(t *testing.T) {
+	d := newTestDeps(t)
+	d.config.Storage.MaxImageBytes = 1 << 10
+	h := d.handler()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, _ := mw.CreateFormFile("files", "big.pdf")
+	_, _ = part.Write(bytes.Repeat([]byte("a"), 2<<20))
+	_ = mw.Close()
+
+	req := httptest.NewRequest("POST", "/v1/gpt/process", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	w := httptest.NewRecorder()
+
+	h.GPT.SubmitGPTRequest(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp APIError
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Error.Code != codePayloadTooLarge {
+		t.Fatalf("expected code %q, got %q", codePayloadTooLarge, resp.Error.Code)
+	}
+}
+
+// gptUploadRequest builds a GPT submission with one small PNG and the given
+// extra form fields.
+func gptUploadRequest(t *testing.T, fields map[string]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for k, v := range fields {
+		_ = mw.WriteField(k, v)
+	}
+	part, _ := mw.CreateFormFile("files", "ekg.png")
+	_, _ = part.Write([]byte("\x89PNG\r\n\x1a\n0000"))
+	_ = mw.Close()
+
+	req := httptest.NewRequest("POST", "/v1/gpt/process", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return withAuthContext(req, uuid.New(), []string{"user"})
+}
+
+func TestSubmitGPTRequest_TimeoutOverride(t *testing.T) {
+	d := newTestDeps(t)
+	d.config.GPT.MaxTimeout = 5 * time.Minute
+	d.submissionSvc.EXPECT().
+		SubmitGPT(mock.Anything, mock.Anything, "", mock.Anything, service.GPTOptions{Timeout: 90 * time.Second}).
+		Return(&service.GPTSubmitResult{SubmittedJob: service.SubmittedJob{RequestID: uuid.New(), Status: models.StatusPending}}, nil)
+	h := d.handler()
+
+	w := httptest.NewRecorder()
+	h.GPT.SubmitGPTRequest(w, gptUploadRequest(t, map[string]string{"timeout_ms": "90000"}))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSubmitGPTRequest_RejectsInvalidTimeout(t *testing.T) {
+	for _, v := range []string{"0", "-5", "soon", "300001"} {
+		t.Run(v, func(t *testing.T) {
+			d := newTestDeps(t)
+			d.config.GPT.MaxTimeout = 5 * time.Minute
+			h := d.handler()
+
+			w := httptest.NewRecorder()
+			h.GPT.SubmitGPTRequest(w, gptUploadRequest(t, map[string]string{"timeout_ms": v}))
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSubmitGPTRequest_ImageDetailAndMaxTokensOverride(t *testing.T) {
+	d := newTestDeps(t)
+	d.submissionSvc.EXPECT().
+		SubmitGPT(mock.Anything, mock.Anything, "", mock.Anything,
+			service.GPTOptions{ImageDetail: openai.ImageURLDetailHigh, MaxTokens: 4000}).
+		Return(&service.GPTSubmitResult{SubmittedJob: service.SubmittedJob{RequestID: uuid.New(), Status: models.StatusPending}}, nil)
+	h := d.handler()
+
+	w := httptest.NewRecorder()
+	h.GPT.SubmitGPTRequest(w, gptUploadRequest(t, map[string]string{"image_detail": "high", "max_tokens": "4000"}))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSubmitGPTRequest_RejectsInvalidImageDetailAndMaxTokens(t *testing.T) {
+	tests := []struct {
+		field string
+		value string
+	}{
+		{"image_detail", "ultra"},
+		{"max_tokens", "0"},
+		{"max_tokens", "many"},
+		{"max_tokens", "16385"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field+"="+tt.value, func(t *testing.T) {
+			d := newTestDeps(t)
+			h := d.handler()
+
+			w := httptest.NewRecorder()
+			h.GPT.SubmitGPTRequest(w, gptUploadRequest(t, map[string]string{tt.field: tt.value}))
+
+			if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), tt.field) {
+				t.Fatalf("expected 400 naming %s, got %d: %s", tt.field, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestValidateGPTRequest_ValidFormSubmitsNothing(t *testing.T) {
+	d := newTestDeps(t) // no service expectations: any submission call fails the test
+	h := d.handler()
+
+	w := httptest.NewRecorder()
+	h.GPT.ValidateGPTRequest(w, gptUploadRequest(t, map[string]string{"text_query": "check the rhythm"}))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ValidateGPTResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !resp.Valid || resp.Files != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestValidateGPTRequest_ReportsValidationErrors(t *testing.T) {
+	d := newTestDeps(t)
+	h := d.handler()
+	req := gptUploadRequest(t, map[string]string{"text_query": strings.Repeat("a", validation.MaxTextLength+1)})
+
+	// The dry run and the real submission report the same errors.
+	validate, submit := httptest.NewRecorder(), httptest.NewRecorder()
+	h.GPT.ValidateGPTRequest(validate, req)
+	h.GPT.SubmitGPTRequest(submit, gptUploadRequest(t, map[string]string{"text_query": strings.Repeat("a", validation.MaxTextLength+1)}))
+
+	if validate.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", validate.Code, validate.Body.String())
+	}
+	var resp APIError
+	if err := json.Unmarshal(validate.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Error.Code != codeValidation || resp.Error.Details == nil {
+		t.Fatalf("expected validation details, got %+v", resp.Error)
+	}
+	if validate.Body.String() != submit.Body.String() {
+		t.Fatalf("dry run and submission disagree:\n validate: %s\n submit:   %s", validate.Body.String(), submit.Body.String())
+	}
+}
+
+func TestValidateGPTRequest_RejectsInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		value string
+	}{
+		{"timeout above max", "timeout_ms", "120000"},
+		{"timeout not a number", "timeout_ms", "soon"},
+		{"unknown image detail", "image_detail", "ultra"},
+		{"max tokens above limit", "max_tokens", "16385"},
+		{"private callback", "callback_url", "http://127.0.0.1/hook"},
+		{"bad tags", "tags", `{"a b":"c"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			d.config.GPT.MaxTimeout = time.Minute
+			h := d.handler()
+			form := map[string]string{"text_query": "check the rhythm", tt.field: tt.value}
+
+			// The dry run rejects what the submission rejects, with the same body.
+			validate, submit := httptest.NewRecorder(), httptest.NewRecorder()
+			h.GPT.ValidateGPTRequest(validate, gptUploadRequest(t, form))
+			h.GPT.SubmitGPTRequest(submit, gptUploadRequest(t, form))
+
+			if validate.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", validate.Code, validate.Body.String())
+			}
+			if validate.Body.String() != submit.Body.String() {
+				t.Fatalf("dry run and submission disagree:\n validate: %s\n submit:   %s", validate.Body.String(), submit.Body.String())
+			}
+		})
+	}
+}
+
+// --- GetJob tests ---
+
+func TestGetJob_NotFound(t *testing.T) {
+	d := newTestDeps(t)
+	jobID := uuid.New()
+
+	d.requestSvc.EXPECT().
+		GetJobStatus(mock.Anything, jobID, mock.Anything).
+		Return(nil, apperr.ErrJobNotFound)
+
+	h := d.handler()
+	userID := uuid.New()
+
+	req := httptest.NewRequest("GET", "/v1/jobs/"+jobID.String(), http.NoBody)
+	req = withAuthContext(req, userID, []string{"user"})
+	req = addChiURLParam(req, "id", jobID.String())
+	w := httptest.NewRecorder()
+
+	h.Request.GetJob(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestGetJob_OtherUsersJobForbidden(t *testing.T) {
+	d := newTestDeps(t)
+	jobID := uuid.New()
+
+	d.requestSvc.EXPECT().
+		GetJobStatus(mock.Anything, jobID, mock.Anything).
+		Return(nil, apperr.ErrForbidden)
+
+	h := d.handler()
+
+	req := httptest.NewRequest("GET", "/v1/jobs/"+jobID.String(), http.NoBody)
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", jobID.String())
+	w := httptest.NewRecorder()
+
+	h.Request.GetJob(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", w.Code)
+	}
+}
+
+func TestGetJob_BadID(t *testing.T) {
+	d := newTestDeps(t)
+	h := d.handler()
+	userID := uuid.New()
+
+	req := httptest.NewRequest("GET", "/v1/jobs/not-a-uuid", http.NoBody)
+	req = withAuthContext(req, userID, []string{"user"})
+	req = addChiURLParam(req, "id", "not-a-uuid")
+	w := httptest.NewRecorder()
+
+	h.Request.GetJob(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestGetJob_Success(t *testing.T) {
+	d := newTestDeps(t)
+	userID := uuid.New()
+	jobID := uuid.New()
+
+	testJob := &job.Job{
+		ID:     jobID,
+		Type:   job.TypeECGAnalyze,
+		Status: job.StatusRunning,
+	}
+
+	d.requestSvc.EXPECT().
+		GetJobStatus(mock.Anything, jobID, mock.Anything).
+		Return(testJob, nil)
+
+	h := d.handler()
+
+	req := httptest.NewRequest("GET", "/v1/jobs/"+jobID.String(), http.NoBody)
+	req = withAuthContext(req, userID, []string{"user"})
+	req = addChiURLParam(req, "id", jobID.String())
+	w := httptest.NewRecorder()
+
+	h.Request.GetJob(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// --- DownloadFile tests ---
+
+func TestDownloadFile_Success(t *testing.T) {
+	d := newTestDeps(t)
+	userID := uuid.New()
+	fileID := uuid.New()
+
+	d.requestSvc.EXPECT().
+		GetFile(mock.Anything, fileID, mock.Anything).
+		Return(&models.File{ID: fileID, OriginalFilename: "ecg.png", FileType: "image/png", S3Key: "ecg/abc.png"}, nil)
+	d.storage.EXPECT().
+		GetFile(mock.Anything, "ecg/abc.png").
+		Return(io.NopCloser(strings.NewReader("png-bytes")), "application/octet-stream", nil)
+
+	h := d.handler()
+
+	req := httptest.NewRequest("GET", "/v1/files/"+fileID.String(), http.NoBody)
+	req = withAuthContext(req, userID, []string{"user"})
+	req = addChiURLParam(req, "id", fileID.String())
+	w := httptest.NewRecorder()
+
+	h.Request.DownloadFile(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "image/png" {
+		t.Fatalf("expected Content-Type image/png, got %q", ct)
+	}
+	if w.Body.String() != "png-bytes" {
+		t.Fatalf("unexpected body %q", w.Body.String())
+	}
+}
+
+func TestDownloadFile_NotFound(t *testing.T) {
+	d := newTestDeps(t)
+	fileID := uuid.New()
+
+	d.requestSvc.EXPECT().
+		GetFile(mock.Anything, fileID, mock.Anything).
+		Return(nil, apperr.ErrFileNotFound)
+
+	h := d.handler()
+
+	req := httptest.NewRequest("GET", "/v1/files/"+fileID.String(), http.NoBody)
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", fileID.String())
+	w := httptest.NewRecorder()
+
+	h.Request.DownloadFile(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestDownloadFile_Forbidden(t *testing.T) {
+	d := newTestDeps(t)
+	fileID := uuid.New()
+
+	d.requestSvc.EXPECT().
+		GetFile(mock.Anything, fileID, mock.Anything).
+		Return(nil, apperr.ErrForbidden)
+
+	h := d.handler()
+
+	req := httptest.NewRequest("GET", "/v1/files/"+fileID.String(), http.NoBody)
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", fileID.String())
+	w := httptest.NewRecorder()
+
+	h.Request.DownloadFile(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", w.Code)
+	}
+}
+
+func TestDownloadThumbnail_Success(t *testing.T) {
+	d := newTestDeps(t)
+	userID := uuid.New()
+	fileID := uuid.New()
+
+	d.requestSvc.EXPECT().
+		GetFile(mock.Anything, fileID, mock.Anything).
+		Return(&models.File{ID: fileID, FileType: "image/png", S3Key: "uploads/a.png", ThumbnailKey: "thumbnails/a.jpg"}, nil)
+	d.storage.EXPECT().
+		GetFile(mock.Anything, "thumbnails/a.jpg").
+		Return(io.NopCloser(strings.NewReader("jpeg-bytes")), "image/jpeg", nil)
+
+	h := d.handler()
+
+	req := httptest.NewRequest("GET", "/v1/files/"+fileID.String()+"/thumbnail", http.NoBody)
+	req = withAuthContext(req, userID, []string{"user"})
+	req = addChiURLParam(req, "id", fileID.String())
+	w := httptest.NewRecorder()
+
+	h.Request.DownloadThumbnail(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "image/jpeg" {
+		t.Fatalf("expected Content-Type image/jpeg, got %q", ct)
+	}
+	if w.Body.String() != "jpeg-bytes" {
+		t.Fatalf("unexpected body %q", w.Body.String())
+	}
+}
+
+func TestDownloadThumbnail_NoThumbnail(t *testing.T) {
+	d := newTestDeps(t)
+	fileID := uuid.New()
+
+	d.requestSvc.EXPECT().
+		GetFile(mock.Anything, fileID, mock.Anything).
+		Return(&models.File{ID: fileID, FileType: "application/pdf", S3Key: "uploads/a.pdf"}, nil)
+
+	h := d.handler()
+
+	req := httptest.NewRequest("GET", "/v1/files/"+fileID.String()+"/thumbnail", http.NoBody)
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", fileID.String())
+	w := httptest.NewRecorder()
+
+	h.Request.DownloadThumbnail(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+// --- Error response tests ---
+
+func TestGetRequest_NotFoundReturnsJSONError(t *testing.T) {
+	d := newTestDeps(t)
+	requestID := uuid.New()
+
+	d.requestSvc.EXPECT().
+		GetRequest(mock.Anything, requestID, mock.Anything).
+		Return(nil, apperr.ErrRequestNotFound)
+
+	h := d.handler()
+
+	req := httptest.NewRequest("GET", "/v1/requests/"+requestID.String(), http.NoBody)
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", requestID.String())
+	w := httptest.NewRecorder()
+
+	h.Request.GetRequest(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected JSON content type, got %q", ct)
+	}
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON body %q: %v", w.Body.String(), err)
+	}
+	if body.Error.Code != "not_found" || body.Error.Message == "" {
+		t.Fatalf("unexpected error body: %s", w.Body.String())
+	}
+}
+
+func TestWriteError_DefaultCodes(t *testing.T) {
+	tests := []struct {
+		status int
+		code   string
+	}{
+		{http.StatusBadRequest, "bad_request"},
+		{http.StatusUnauthorized, "unauthorized"},
+		{http.StatusTooManyRequests, "rate_limited"},
+		{http.StatusUnsupportedMediaType, "unsupported_media_type"},
+		{http.StatusBadGateway, "upstream_error"},
+		{http.StatusInternalServerError, "internal"},
+		{http.StatusTeapot, "internal"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		writeError(w, tt.status, "msg")
+
+		var body APIError
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != tt.status || body.Error.Code != tt.code || body.Error.Message != "msg" {
+			t.Errorf("writeError(%d) = %d %+v, want code %q", tt.status, w.Code, body.Error, tt.code)
+		}
+	}
+}
+
+// --- requireJSON tests ---
+
+func TestRequireJSON_RejectsWrongContentType(t *testing.T) {
+	d := newTestDeps(t)
+	h := d.handler()
+
+	req := httptest.NewRequest("POST", "/v1/auth/register", strings.NewReader("email=alice%40example.com"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	requireJSON(http.HandlerFunc(h.Auth.Register)).ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected 415, got %d: %s", w.Code, w.Body.String())
+	}
+	var body APIError
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Error.Code != "unsupported_media_type" {
+		t.Fatalf("expected unsupported_media_type, got %+v", body.Error)
+	}
+}
+
+func TestRequireJSON_RejectsOversizedBody(t *testing.T) {
+	d := newTestDeps(t)
+	h := d.handler()
+	payload, _ := json.Marshal(map[string]string{
+		"email":    "alice@example.com",
+		"username": strings.Repeat("a", maxBodySize),
+		"password": "Secret123",
+	})
+
+	for _, chunked := range []bool{false, true} {
+		req := httptest.NewRequest("POST", "/v1/auth/register", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		if chunked {
+			// Unknown length: the limit is hit while decoding.
+			req.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+
+		requireJSON(http.HandlerFunc(h.Auth.Register)).ServeHTTP(w, req)
+
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("chunked=%v: expected 413, got %d: %s", chunked, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestRequireJSON_PassesJSONAndEmptyBodies(t *testing.T) {
+	var calls int
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	jsonReq := httptest.NewRequest("POST", "/v1/rag/query", strings.NewReader(`{"question":"qt"}`))
+	jsonReq.Header.Set("Content-Type", "application/json; charset=utf-8")
+	emptyReq := httptest.NewRequest("POST", "/v1/requests/x/reanalyze", http.NoBody)
+
+	for _, req := range []*http.Request{jsonReq, emptyReq} {
+		w := httptest.NewRecorder()
+		requireJSON(next).ServeHTTP(w, req)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("%s: expected 204, got %d", req.URL.Path, w.Code)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected next to run twice, ran %d times", calls)
+	}
+}
+
+// --- GetUserRequests tests ---
+
+func TestGetUserRequests_AppliesFilters(t *testing.T) {
+	from := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 12, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		query string
+		want  repository.RequestFilter
+	}{
+		{"", repository.RequestFilter{}},
+		{"status=failed", repository.RequestFilter{Status: models.StatusFailed}},
+		{"from=2026-10-05T00:00:00Z", repository.RequestFilter{From: &from}},
+		{"to=2026-10-12T09:30:00Z", repository.RequestFilter{To: &to}},
+		{"from=2026-10-05T00:00:00Z&to=2026-10-12T09:30:00Z", repository.RequestFilter{From: &from, To: &to}},
+		{"status=failed&from=2026-10-05T00:00:00Z", repository.RequestFilter{Status: models.StatusFailed, From: &from}},
+		{"status=completed&to=2026-10-12T09:30:00Z", repository.RequestFilter{Status: models.StatusCompleted, To: &to}},
+		{
+			"status=failed&from=2026-10-05T00:00:00Z&to=2026-10-12T09:30:00Z&tag.site=kazan",
+			repository.RequestFilter{Status: models.StatusFailed, From: &from, To: &to, Tags: map[string]string{"site": "kazan"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			d := newTestDeps(t)
+			userID := uuid.New()
+			want := tt.want
+			want.Limit, want.Offset = 10, 20
+
+			d.requestSvc.EXPECT().
+				GetUserRequests(mock.Anything, userID, want).
+				Return(&service.RequestPage{Data: []models.Request{}, Limit: 10, Offset: 20}, nil)
+
+			h := d.handler()
+			req := httptest.NewRequest("GET", "/v1/requests?limit=10&offset=20&"+tt.query, http.NoBody)
+			req = withAuthContext(req, userID, []string{"user"})
+			w := httptest.NewRecorder()
+
+			h.Request.GetUserRequests(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetUserRequests_InvalidFilters(t *testing.T) {
+	for _, query := range []string{"status=running", "from=last-week", "to=2026-10-32", "tag.a%2Fb=x"} {
+		t.Run(query, func(t *testing.T) {
+			h := newTestDeps(t).handler()
+			req := httptest.NewRequest("GET", "/v1/requests?"+query, http.NoBody)
+			req = withAuthContext(req, uuid.New(), []string{"user"})
+			w := httptest.NewRecorder()
+
+			h.Request.GetUserRequests(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// --- RequestStats tests ---
+
+func TestGetRequestStats_Success(t *testing.T) {
+	d := newTestDeps(t)
+	userID := uuid.New()
+
+	d.requestSvc.EXPECT().
+		GetRequestStats(mock.Anything, userID).
+		Return(&service.RequestStats{Total: 45, ByStatus: map[string]int{"pending": 3, "processing": 0, "completed": 40, "failed": 2}}, nil)
+
+	h := d.handler()
+
+	req := httptest.NewRequest("GET", "/v1/requests/stats", http.NoBody)
+	req = withAuthContext(req, userID, []string{"user"})
+	w := httptest.NewRecorder()
+
+	h.Request.GetRequestStats(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp RequestStatsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Total != 45 || resp.ByStatus["pending"] != 3 || len(resp.ByStatus) != 4 {
+		t.Errorf("unexpected stats: %+v", resp)
+	}
+}
+
+func TestGetRequestStats_NoAuth(t *testing.T) {
+	h := newTestDeps(t).handler()
+
+	w := httptest.NewRecorder()
+	h.Request.GetRequestStats(w, httptest.NewRequest("GET", "/v1/requests/stats", http.NoBody))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", w.Code)
+	}
+}
+
+// --- DeleteRequest tests ---
+
+func TestDeleteRequest_Success(t *testing.T) {
+	d := newTestDeps(t)
+	requestID := uuid.New()
+
+	d.requestSvc.EXPECT().
+		DeleteRequest(mock.Anything, requestID, mock.Anything).
+		Return(nil)
+
+	h := d.handler()
+
+	req := httptest.NewRequest("DELETE", "/v1/requests/"+requestID.String(), http.NoBody)
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", requestID.String())
+	w := httptest.NewRecorder()
+
+	h.Request.DeleteRequest(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestDeleteRequest_NotFound(t *testing.T) {
+	d := newTestDeps(t)
+
+	d.requestSvc.EXPECT().
+		DeleteRequest(mock.Anything, mock.Anything, mock.Anything).
+		Return(apperr.ErrRequestNotFound)
+
+	h := d.handler()
+
+	id := uuid.New().String()
+	req := httptest.NewRequest("DELETE", "/v1/requests/"+id, http.NoBody)
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", id)
+	w := httptest.NewRecorder()
+
+	h.Request.DeleteRequest(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+// --- RetryRequest tests ---
+
+func TestRetryRequest_Success(t *testing.T) {
+	d := newTestDeps(t)
+	requestID := uuid.New()
+	jobID := uuid.New()
+
+	d.submissionSvc.EXPECT().
+		RetryGPT(mock.Anything, requestID, mock.Anything).
+		Return(&service.GPTSubmitResult{
+			SubmittedJob:   service.SubmittedJob{JobID: jobID, RequestID: requestID, Status: "pending"},
+			FilesProcessed: 1,
+		}, nil)
+
+	h := d.handler()
+
+	req := httptest.NewRequest("POST", "/v1/requests/"+requestID.String()+"/retry", http.NoBody)
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", requestID.String())
+	w := httptest.NewRecorder()
+
+	h.GPT.RetryRequest(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp SubmitGPTResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.JobID != jobID || resp.Status != "pending" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestRetryRequest_NotFailed(t *testing.T) {
+	d := newTestDeps(t)
+
+	d.submissionSvc.EXPECT().
+		RetryGPT(mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, service.ErrNotRetryable)
+
+	h := d.handler()
+
+	id := uuid.New().String()
+	req := httptest.NewRequest("POST", "/v1/requests/"+id+"/retry", http.NoBody)
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", id)
+	w := httptest.NewRecorder()
+
+	h.GPT.RetryRequest(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+}
+
+// --- ReanalyzeRequest tests ---
+
+func TestReanalyzeRequest_PassesAllowedModelAndPrompt(t *testing.T) {
+	d := newTestDeps(t)
+	d.config.GPT.ReanalyzeModels = []string{"gpt-4.1"}
+	requestID := uuid.New()
+	jobID := uuid.New()
+
+	d.submissionSvc.EXPECT().
+		ReanalyzeGPT(mock.Anything, requestID, mock.Anything, service.ReanalyzeOptions{Model: "gpt-4.1", TextQuery: "second opinion"}).
+		Return(&service.GPTSubmitResult{
+			SubmittedJob:   service.SubmittedJob{JobID: jobID, RequestID: requestID, Status: "pending"},
+			FilesProcessed: 1,
+		}, nil)
+
+	h := d.handler()
+
+	body := `{"model":"gpt-4.1","prompt":"second opinion"}`
+	req := httptest.NewRequest("POST", "/v1/requests/"+requestID.String()+"/reanalyze", strings.NewReader(body))
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", requestID.String())
+	w := httptest.NewRecorder()
+
+	h.GPT.ReanalyzeRequest(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp SubmitGPTResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.JobID != jobID {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestReanalyzeRequest_ChunkedBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want service.ReanalyzeOptions
+	}{
+		{"overrides", `{"model":"gpt-4.1","prompt":"second opinion"}`, service.ReanalyzeOptions{Model: "gpt-4.1", TextQuery: "second opinion"}},
+		{"empty", "", service.ReanalyzeOptions{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			d.config.GPT.ReanalyzeModels = []string{"gpt-4.1"}
+			requestID := uuid.New()
+
+			d.submissionSvc.EXPECT().
+				ReanalyzeGPT(mock.Anything, requestID, mock.Anything, tt.want).
+				Return(&service.GPTSubmitResult{SubmittedJob: service.SubmittedJob{JobID: uuid.New(), RequestID: requestID}}, nil)
+
+			h := d.handler()
+
+			req := httptest.NewRequest("POST", "/v1/requests/"+requestID.String()+"/reanalyze", strings.NewReader(tt.body))
+			req.ContentLength = -1 // Transfer-Encoding: chunked
+			req = withAuthContext(req, uuid.New(), []string{"user"})
+			req = addChiURLParam(req, "id", requestID.String())
+			w := httptest.NewRecorder()
+
+			h.GPT.ReanalyzeRequest(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestReanalyzeRequest_RejectsModelOutsideAllowlist(t *testing.T) {
+	d := newTestDeps(t)
+	d.config.GPT.ReanalyzeModels = []string{"gpt-4.1"}
+	h := d.handler()
+
+	id := uuid.New().String()
+	req := httptest.NewRequest("POST", "/v1/requests/"+id+"/reanalyze", strings.NewReader(`{"model":"o1-pro"}`))
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", id)
+	w := httptest.NewRecorder()
+
+	h.GPT.ReanalyzeRequest(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestReanalyzeRequest_FreeQuotaExhausted(t *testing.T) {
+	d := newTestDeps(t)
+
+	d.submissionSvc.EXPECT().
+		ReanalyzeGPT(mock.Anything, mock.Anything, mock.Anything, service.ReanalyzeOptions{}).
+		Return(nil, fmt.Errorf("free limit (3) exceeded, subscribe for unlimited: %w", apperr.ErrPaymentRequired))
+
+	h := d.handler()
+
+	id := uuid.New().String()
+	req := httptest.NewRequest("POST", "/v1/requests/"+id+"/reanalyze", http.NoBody)
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", id)
+	w := httptest.NewRecorder()
+
+	h.GPT.ReanalyzeRequest(w, req)
+
+	if w.Code != http.StatusPaymentRequired || !strings.Contains(w.Body.String(), codePaymentRequired) {
+		t.Fatalf("expected 402 %s, got %d: %s", codePaymentRequired, w.Code, w.Body.String())
+	}
+}
+
+func TestReanalyzeRequest_InProgress(t *testing.T) {
+	d := newTestDeps(t)
+
+	d.submissionSvc.EXPECT().
+		ReanalyzeGPT(mock.Anything, mock.Anything, mock.Anything, service.ReanalyzeOptions{}).
+		Return(nil, service.ErrNotReanalyzable)
+
+	h := d.handler()
+
+	id := uuid.New().String()
+	req := httptest.NewRequest("POST", "/v1/requests/"+id+"/reanalyze", http.NoBody)
+	req = withAuthContext(req, uuid.New(), []string{"user"})
+	req = addChiURLParam(req, "id", id)
+	w := httptest.NewRecorder()
+
+	h.GPT.ReanalyzeRequest(w, req)
+
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), codeNotReanalyzable) {
+		t.Fatalf("expected 409 %s, got %d: %s", codeNotReanalyzable, w.Code, w.Body.String())
+	}
+}
+
+// --- ServeFiles tests ---
+
+func newServeFilesDeps(t *testing.T) (*testDeps, string) {
+	t.Helper()
+	root := t.TempDir()
+	base := filepath.Join(root, "uploads")
+	if err := os.Mkdir(base, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "ok.txt"), []byte("ok"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "secret.txt"), []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	d := newTestDeps(t)
+	d.config.Storage.LocalDir = base
+	return d, root
+}
+
+func TestServeFiles_ServesFileInsideBase(t *testing.T) {
+	d, _ := newServeFilesDeps(t)
+	h := d.handler()
+
+	w := httptest.NewRecorder()
+	h.Request.ServeFiles(w, httptest.NewRequest("GET", "/files/ok.txt", http.NoBody))
+
+	if w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Fatalf("expected 200 ok, got %d: %q", w.Code, w.Body.String())
+	}
+}
+
+func TestServeFiles_RejectsTraversal(t *testing.T) {
+	d, root := newServeFilesDeps(t)
+	if err := os.Symlink(filepath.Join(root, "secret.txt"), filepath.Join(d.config.Storage.LocalDir, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+	h := d.handler()
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"encoded dot-dot", "/files/%2e%2e/secret.txt"},
+		{"encoded slash", "/files/..%2fsecret.txt"},
+		{"absolute path", "/files/" + filepath.Join(root, "secret.txt")},
+		{"symlink escape", "/files/link.txt"},
+		{"base directory", "/files/."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.Request.ServeFiles(w, httptest.NewRequest("GET", tt.url, http.NoBody))
+
+			if w.Code == http.StatusOK {
+				t.Fatalf("expected rejection, got 200: %q", w.Body.String())
+			}
+			if strings.Contains(w.Body.String(), "secret") {
+				t.Fatalf("secret leaked: %q", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestFileAccess_SignedLinks(t *testing.T) {
+	d, _ := newServeFilesDeps(t)
+	h := d.handler()
+
+	denyJWT := func(http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+	}
+	handler := h.Request.FileAccess(denyJWT)(http.HandlerFunc(h.Request.ServeFiles))
+
+	expires := time.Now().Add(time.Minute).Unix()
+	sig := h.Request.FileSigner.Sign("ok.txt", expires)
+
+	tests := []struct {
+		name string
+		url  string
+		want int
+	}{
+		{"valid signature", fmt.Sprintf("/files/ok.txt?expires=%d&sig=%s", expires, sig), http.StatusOK},
+		{"signature for another file", fmt.Sprintf("/files/other.txt?expires=%d&sig=%s", expires, sig), http.StatusForbidden},
+		{"expired", fmt.Sprintf("/files/ok.txt?expires=1&sig=%s", h.Request.FileSigner.Sign("ok.txt", 1)), http.StatusForbidden},
+		{"unsigned falls back to JWT", "/files/ok.txt", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", tt.url, http.NoBody))
+			if w.Code != tt.want {
+				t.Fatalf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// --- Serialization tests ---
+
+func TestEKGPayload_Roundtrip(t *testing.T) {
+	original := job.ECGJobPayload{
+		ImageTempURL: "http://example.com/test.jpg",
+		Notes:        "Test EKG analysis",
+		UserID:       uuid.New(),
+		RequestID:    uuid.New(),
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var decoded job.ECGJobPayload
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if decoded.ImageTempURL != original.ImageTempURL {
+		t.Errorf("ImageTempURL: got %s, want %s", decoded.ImageTempURL, original.ImageTempURL)
+	}
+	if decoded.UserID != original.UserID {
+		t.Errorf("UserID: got %s, want %s", decoded.UserID, original.UserID)
+	}
+	if decoded.RequestID != original.RequestID {
+		t.Errorf("RequestID: got %s, want %s", decoded.RequestID, original.RequestID)
+	}
+}
+
+func TestSubmitECGResponse_Roundtrip(t *testing.T) {
+	resp := SubmitECGResponse{
 		JobID:     uuid.New(),
 		RequestID: uuid.New(),
 		Status:    "queued",
@@ -514,80 +1967,265 @@ func TestLogin_UserNotFound(t *testing.T) {
 	}
 }
 
-func TestLogin_WrongPassword(t *testing.T) {
+func TestLogin_WrongPassword(t *testing.T) {
+	d := newTestDeps(t)
+
+	d.authSvc.EXPECT().
+		Login(mock.Anything, "alice@example.com", "wrongpassword").
+		Return(nil, apperr.ErrInvalidCredentials)
+
+	h := d.handler()
+
+	body, _ := json.Marshal(map[string]string{
+		"email":    "alice@example.com",
+		"password": "wrongpassword",
+	})
+	req := httptest.NewRequest("POST", "/v1/auth/login", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.Auth.Login(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", w.Code)
+	}
+}
+
+func TestLogin_Success(t *testing.T) {
+	d := newTestDeps(t)
+
+	d.authSvc.EXPECT().
+		Login(mock.Anything, "alice@example.com", "securepassword123").
+		Return(&service.LoginResult{Tokens: &auth.TokenPair{AccessToken: "access-token", RefreshToken: "refresh-token"}}, nil)
+
+	h := d.handler()
+
+	body, _ := json.Marshal(map[string]string{
+		"email":    "alice@example.com",
+		"password": "securepassword123",
+	})
+	req := httptest.NewRequest("POST", "/v1/auth/login", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.Auth.Login(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Access token returned in JSON body
+	var resp map[string]string
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp["access_token"] == "" {
+		t.Error("expected non-empty access_token in body")
+	}
+	if _, hasRefresh := resp["refresh_token"]; hasRefresh {
+		t.Error("refresh_token must NOT be in JSON body — it should be in a cookie")
+	}
+
+	// Refresh token set as httpOnly cookie
+	cookies := w.Result().Cookies()
+	var refreshCookie *http.Cookie
+	for _, c := range cookies {
+		if c.Name == "refresh_token" {
+			refreshCookie = c
+			break
+		}
+	}
+	if refreshCookie == nil {
+		t.Fatal("expected refresh_token cookie to be set")
+	}
+	if refreshCookie.Value != "refresh-token" {
+		t.Errorf("expected cookie value 'refresh-token', got %q", refreshCookie.Value)
+	}
+	if !refreshCookie.HttpOnly {
+		t.Error("refresh_token cookie must be HttpOnly")
+	}
+}
+
+func TestLogin_TwoFactorReturnsChallenge(t *testing.T) {
+	d := newTestDeps(t)
+
+	d.authSvc.EXPECT().
+		Login(mock.Anything, "alice@example.com", "securepassword123").
+		Return(&service.LoginResult{ChallengeToken: "challenge"}, nil)
+
+	h := d.handler()
+
+	body, _ := json.Marshal(map[string]string{
+		"email":    "alice@example.com",
+		"password": "securepassword123",
+	})
+	req := httptest.NewRequest("POST", "/v1/auth/login", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.Auth.Login(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp["two_factor_required"] != true || resp["challenge_token"] != "challenge" {
+		t.Fatalf("expected two-factor challenge, got %v", resp)
+	}
+	if _, hasAccess := resp["access_token"]; hasAccess {
+		t.Error("access_token must not be issued before the second factor")
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("refresh_token cookie must not be set before the second factor")
+	}
+}
+
+func TestLoginTwoFactor_Success(t *testing.T) {
+	d := newTestDeps(t)
+
+	d.authSvc.EXPECT().
+		LoginTwoFactor(mock.Anything, "challenge", "123456").
+		Return(&service.LoginResult{UserID: uuid.New(), Tokens: &auth.TokenPair{AccessToken: "access-token", RefreshToken: "refresh-token"}}, nil)
+
+	h := d.handler()
+
+	body, _ := json.Marshal(map[string]string{"challenge_token": "challenge", "code": "123456"})
+	req := httptest.NewRequest("POST", "/v1/auth/2fa/login", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.Auth.LoginTwoFactor(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "access-token") {
+		t.Errorf("expected access token in body, got %s", w.Body.String())
+	}
+}
+
+func TestLoginTwoFactor_InvalidCode(t *testing.T) {
+	d := newTestDeps(t)
+
+	d.authSvc.EXPECT().
+		LoginTwoFactor(mock.Anything, "challenge", "000000").
+		Return(nil, service.ErrInvalidTwoFactorCode)
+
+	h := d.handler()
+
+	body, _ := json.Marshal(map[string]string{"challenge_token": "challenge", "code": "000000"})
+	req := httptest.NewRequest("POST", "/v1/auth/2fa/login", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.Auth.LoginTwoFactor(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"invalid_2fa_code"`) {
+		t.Errorf("expected invalid_2fa_code, got %s", w.Body.String())
+	}
+}
+
+func TestEnrollTwoFactor_ReturnsSecret(t *testing.T) {
+	d := newTestDeps(t)
+	userID := uuid.New()
+
+	d.authSvc.EXPECT().
+		EnrollTwoFactor(mock.Anything, userID).
+		Return(&auth.TOTPEnrollment{Secret: "JBSWY3DPEHPK3PXP", URL: "otpauth://totp/SmartHeart:alice"}, nil)
+
+	h := d.handler()
+
+	req := httptest.NewRequest("POST", "/v1/auth/2fa/enroll", http.NoBody)
+	req = withAuthContext(req, userID, []string{"user"})
+	w := httptest.NewRecorder()
+
+	h.Auth.EnrollTwoFactor(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp TwoFactorEnrollResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Secret == "" || resp.OTPAuthURL == "" {
+		t.Fatalf("expected secret and otpauth_url, got %+v", resp)
+	}
+}
+
+func TestLogin_EmailNotVerified(t *testing.T) {
 	d := newTestDeps(t)
 
 	d.authSvc.EXPECT().
-		Login(mock.Anything, "alice@example.com", "wrongpassword").
-		Return(nil, apperr.ErrInvalidCredentials)
+		Login(mock.Anything, "alice@example.com", "securepassword123").
+		Return(nil, apperr.ErrEmailNotVerified)
 
 	h := d.handler()
 
 	body, _ := json.Marshal(map[string]string{
 		"email":    "alice@example.com",
-		"password": "wrongpassword",
+		"password": "securepassword123",
 	})
 	req := httptest.NewRequest("POST", "/v1/auth/login", bytes.NewReader(body))
 	w := httptest.NewRecorder()
 
 	h.Auth.Login(w, req)
 
-	if w.Code != http.StatusUnauthorized {
-		t.Fatalf("expected 401, got %d", w.Code)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "email_not_verified") {
+		t.Errorf("expected email_not_verified code, got %s", w.Body.String())
 	}
 }
 
-func TestLogin_Success(t *testing.T) {
+func TestVerifyEmail_Success(t *testing.T) {
 	d := newTestDeps(t)
 
-	d.authSvc.EXPECT().
-		Login(mock.Anything, "alice@example.com", "securepassword123").
-		Return(&auth.TokenPair{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
+	d.authSvc.EXPECT().VerifyEmail(mock.Anything, "raw-token").Return(nil)
 
 	h := d.handler()
 
-	body, _ := json.Marshal(map[string]string{
-		"email":    "alice@example.com",
-		"password": "securepassword123",
-	})
-	req := httptest.NewRequest("POST", "/v1/auth/login", bytes.NewReader(body))
+	req := httptest.NewRequest("GET", "/v1/auth/verify?token=raw-token", http.NoBody)
 	w := httptest.NewRecorder()
 
-	h.Auth.Login(w, req)
+	h.Auth.VerifyEmail(w, req)
 
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
 	}
+}
 
-	// Access token returned in JSON body
-	var resp map[string]string
-	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
-		t.Fatalf("decode: %v", err)
-	}
-	if resp["access_token"] == "" {
-		t.Error("expected non-empty access_token in body")
-	}
-	if _, hasRefresh := resp["refresh_token"]; hasRefresh {
-		t.Error("refresh_token must NOT be in JSON body — it should be in a cookie")
-	}
+func TestVerifyEmail_MissingToken(t *testing.T) {
+	d := newTestDeps(t)
+	h := d.handler()
 
-	// Refresh token set as httpOnly cookie
-	cookies := w.Result().Cookies()
-	var refreshCookie *http.Cookie
-	for _, c := range cookies {
-		if c.Name == "refresh_token" {
-			refreshCookie = c
-			break
-		}
-	}
-	if refreshCookie == nil {
-		t.Fatal("expected refresh_token cookie to be set")
-	}
-	if refreshCookie.Value != "refresh-token" {
-		t.Errorf("expected cookie value 'refresh-token', got %q", refreshCookie.Value)
+	req := httptest.NewRequest("GET", "/v1/auth/verify", http.NoBody)
+	w := httptest.NewRecorder()
+
+	h.Auth.VerifyEmail(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
 	}
-	if !refreshCookie.HttpOnly {
-		t.Error("refresh_token cookie must be HttpOnly")
+}
+
+func TestResendVerification(t *testing.T) {
+	d := newTestDeps(t)
+
+	d.authSvc.EXPECT().ResendVerification(mock.Anything, "alice@example.com").Return(nil)
+
+	h := d.handler()
+
+	body := strings.NewReader(`{"email":"alice@example.com"}`)
+	req := httptest.NewRequest("POST", "/v1/auth/verify/resend", body)
+	w := httptest.NewRecorder()
+
+	h.Auth.ResendVerification(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
 	}
 }
 
@@ -639,7 +2277,7 @@ func TestLogout_Success(t *testing.T) {
 	req := httptest.NewRequest("POST", "/v1/auth/logout", http.NoBody)
 	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "some-refresh-token"})
 	req = withAuthContext(req, userID, []string{"user"})
-	accessToken, _ := auth.NewToken("test-secret", "test", userID.String(), []string{"user"}, 15*time.Minute)
+	accessToken, _ := auth.NewToken(auth.NewHS256Keys("test-secret"), "test", userID.String(), []string{"user"}, 15*time.Minute)
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	w := httptest.NewRecorder()
 
@@ -687,6 +2325,556 @@ func TestLogout_NoCookie(t *testing.T) {
 	}
 }
 
+// --- Admin ListRequests tests ---
+
+func TestAdminListRequests_AppliesFilters(t *testing.T) {
+	d := newTestDeps(t)
+	userID := uuid.New()
+
+	d.repo.EXPECT().
+		ListRequests(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, f repository.RequestFilter) {
+			if f.UserID == nil || *f.UserID != userID {
+				t.Errorf("expected user_id filter %s, got %v", userID, f.UserID)
+			}
+			if f.Status != models.StatusCompleted {
+				t.Errorf("expected status filter, got %q", f.Status)
+			}
+			if f.From == nil || !f.From.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("unexpected from: %v", f.From)
+			}
+			// Date-only "to" covers the whole day.
+			if f.To == nil || !f.To.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("unexpected to: %v", f.To)
+			}
+			if f.Tags["site"] != "kazan" || len(f.Tags) != 1 {
+				t.Errorf("unexpected tag filter: %v", f.Tags)
+			}
+			if f.Limit != 10 || f.Offset != 20 {
+				t.Errorf("unexpected pagination: %d/%d", f.Limit, f.Offset)
+			}
+		}).
+		Return([]models.Request{{ID: uuid.New(), UserID: userID}}, 1, nil)
+
+	h := d.handler()
+	url := "/v1/admin/requests?user_id=" + userID.String() +
+		"&status=completed&from=2026-01-01&to=2026-01-31&tag.site=kazan&limit=10&offset=20"
+	req := withAuthContext(httptest.NewRequest("GET", url, nil), uuid.New(), []string{"admin"})
+	w := httptest.NewRecorder()
+
+	h.Admin.ListRequests(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var resp PaginatedResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Total != 1 {
+		t.Fatalf("expected total 1, got %d", resp.Total)
+	}
+}
+
+func TestAdminListRequests_InvalidParams(t *testing.T) {
+	for _, query := range []string{"user_id=nope", "status=bogus", "from=yesterday", "to=2026-13-01", "tag.bad%20key=x"} {
+		t.Run(query, func(t *testing.T) {
+			d := newTestDeps(t)
+			h := d.handler()
+			req := httptest.NewRequest("GET", "/v1/admin/requests?"+query, nil)
+			w := httptest.NewRecorder()
+
+			h.Admin.ListRequests(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+// stubDeadLetters records the filter it was called with.
+type stubDeadLetters struct {
+	filter queue.DeadLetterFilter
+	n      int
+	err    error
+}
+
+func (s *stubDeadLetters) RetryAllDeadLetter(_ context.Context, f queue.DeadLetterFilter) (int, error) {
+	s.filter = f
+	return s.n, s.err
+}
+
+func TestAdminRetryDeadLetters_PassesFilter(t *testing.T) {
+	d := newTestDeps(t)
+	h := d.handler()
+	dl := &stubDeadLetters{n: 7}
+	h.Admin.DeadLetters = dl
+
+	req := httptest.NewRequest("POST", "/v1/admin/deadletter/retry?type=gpt_process&reason=max+retries&limit=50", nil)
+	w := httptest.NewRecorder()
+	h.Admin.RetryDeadLetters(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	want := queue.DeadLetterFilter{Type: job.TypeGPTProcess, Reason: "max retries", Limit: 50}
+	if dl.filter != want {
+		t.Errorf("expected filter %+v, got %+v", want, dl.filter)
+	}
+	var resp map[string]int
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp["retried"] != 7 {
+		t.Errorf("unexpected response %s (err: %v)", w.Body.String(), err)
+	}
+}
+
+func TestAdminRetryDeadLetters_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		dl     DeadLetterReplayer
+		status int
+	}{
+		{"memory queue", "", nil, http.StatusConflict},
+		{"unknown type", "type=bogus", &stubDeadLetters{}, http.StatusBadRequest},
+		{"bad limit", "limit=0", &stubDeadLetters{}, http.StatusBadRequest},
+		{"replay fails", "", &stubDeadLetters{n: 2, err: errors.New("redis down")}, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			h := d.handler()
+			h.Admin.DeadLetters = tt.dl
+			w := httptest.NewRecorder()
+
+			h.Admin.RetryDeadLetters(w, httptest.NewRequest("POST", "/v1/admin/deadletter/retry?"+tt.query, nil))
+
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
+
+// --- Audit tests ---
+
+func TestLogin_RecordsAudit(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name       string
+		result     *service.LoginResult
+		err        error
+		wantAction string
+		wantActor  bool
+	}{
+		{"success", &service.LoginResult{UserID: userID, Tokens: &auth.TokenPair{AccessToken: "a", RefreshToken: "r"}}, nil, models.AuditLogin, true},
+		{"2fa challenge", &service.LoginResult{UserID: userID, ChallengeToken: "challenge"}, nil, models.AuditLoginChallenge, true},
+		{"bad credentials", nil, apperr.ErrInvalidCredentials, models.AuditLoginFailed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			d.authSvc.EXPECT().Login(mock.Anything, "alice@example.com", "securepassword123").Return(tt.result, tt.err)
+			h := d.handler()
+
+			body, _ := json.Marshal(map[string]string{"email": "alice@example.com", "password": "securepassword123"})
+			req := httptest.NewRequest("POST", "/v1/auth/login", bytes.NewReader(body))
+			req.RemoteAddr = "203.0.113.7:51234"
+			h.Auth.Login(httptest.NewRecorder(), req)
+			d.flushAudit(h)
+
+			if len(d.audits) != 1 {
+				t.Fatalf("expected 1 audit entry, got %v", d.audits)
+			}
+			e := d.audits[0]
+			if e.Action != tt.wantAction || e.IP != "203.0.113.7" {
+				t.Errorf("unexpected entry: %+v", e)
+			}
+			if tt.wantActor && (e.ActorID == nil || *e.ActorID != userID) {
+				t.Errorf("expected actor %s, got %v", userID, e.ActorID)
+			}
+			if !tt.wantActor && (e.ActorID != nil || e.Target != "email:alice@example.com") {
+				t.Errorf("expected failed login recorded by email without actor, got %+v", e)
+			}
+		})
+	}
+}
+
+func TestLoginTwoFactor_RecordsAudit(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name       string
+		result     *service.LoginResult
+		err        error
+		wantAction string
+	}{
+		{"success", &service.LoginResult{UserID: userID, Tokens: &auth.TokenPair{AccessToken: "a", RefreshToken: "r"}}, nil, models.AuditLogin},
+		{"bad code", nil, service.ErrInvalidTwoFactorCode, models.AuditLoginFailed},
+		{"bad challenge", nil, apperr.ErrInvalidToken, models.AuditLoginFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			d.authSvc.EXPECT().LoginTwoFactor(mock.Anything, "challenge", "123456").Return(tt.result, tt.err)
+			h := d.handler()
+
+			body, _ := json.Marshal(map[string]string{"challenge_token": "challenge", "code": "123456"})
+			h.Auth.LoginTwoFactor(httptest.NewRecorder(), httptest.NewRequest("POST", "/v1/auth/2fa/login", bytes.NewReader(body)))
+			d.flushAudit(h)
+
+			if len(d.audits) != 1 || d.audits[0].Action != tt.wantAction {
+				t.Fatalf("expected one %s entry, got %+v", tt.wantAction, d.audits)
+			}
+			if actor := d.audits[0].ActorID; tt.err == nil && (actor == nil || *actor != userID) {
+				t.Errorf("expected actor %s, got %v", userID, actor)
+			}
+		})
+	}
+}
+
+func TestGetRequest_RecordsAudit(t *testing.T) {
+	d := newTestDeps(t)
+	userID, requestID := uuid.New(), uuid.New()
+	d.requestSvc.EXPECT().
+		GetRequest(mock.Anything, requestID, mock.Anything).
+		Return(&models.Request{ID: requestID, UserID: userID}, nil)
+	h := d.handler()
+
+	req := withAuthContext(httptest.NewRequest("GET", "/v1/requests/"+requestID.String(), nil), userID, []string{"user"})
+	req = addChiURLParam(req, "id", requestID.String())
+	w := httptest.NewRecorder()
+	h.Request.GetRequest(w, req)
+	d.flushAudit(h)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if len(d.audits) != 1 {
+		t.Fatalf("expected 1 audit entry, got %v", d.audits)
+	}
+	e := d.audits[0]
+	if e.Action != models.AuditRequestRead || e.Target != "request:"+requestID.String() || e.ActorID == nil || *e.ActorID != userID {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+}
+
+func TestAuditMiddleware_RecordsAdminCalls(t *testing.T) {
+	d := newTestDeps(t)
+	h := d.handler()
+	adminID := uuid.New()
+
+	var called bool
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true })
+	req := withAuthContext(httptest.NewRequest("GET", "/v1/admin/users?search=bob", nil), adminID, []string{"admin"})
+	h.Audit.Middleware(next).ServeHTTP(httptest.NewRecorder(), req)
+	d.flushAudit(h)
+
+	if !called {
+		t.Fatal("expected the admin handler to run")
+	}
+	if len(d.audits) != 1 {
+		t.Fatalf("expected 1 audit entry, got %v", d.audits)
+	}
+	e := d.audits[0]
+	if e.Action != models.AuditAdminAccess || e.Target != "GET /v1/admin/users" || e.ActorID == nil || *e.ActorID != adminID {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+}
+
+func TestAuditor_WriteFailureDoesNotBlockRequest(t *testing.T) {
+	repo := repomocks.NewMockStore(t)
+	repo.EXPECT().WriteAudit(mock.Anything, mock.Anything).Return(errors.New("db down"))
+	a := &Auditor{Repo: repo}
+
+	w := httptest.NewRecorder()
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	a.Middleware(next).ServeHTTP(w, httptest.NewRequest("GET", "/v1/admin/stats", nil))
+	a.Close()
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected the request to proceed, got %d", w.Code)
+	}
+}
+
+func TestAuditor_DropsWhenQueueFull(t *testing.T) {
+	repo := repomocks.NewMockStore(t)
+	release := make(chan struct{})
+	var writes int
+	repo.EXPECT().WriteAudit(mock.Anything, mock.Anything).
+		RunAndReturn(func(context.Context, *models.AuditEntry) error {
+			<-release
+			writes++
+			return nil
+		})
+	a := &Auditor{Repo: repo}
+
+	// The writer holds one entry, the queue the next auditQueueSize; the
+	// rest must be dropped without blocking the caller.
+	done := make(chan struct{})
+	go func() {
+		for range auditQueueSize + 10 {
+			a.Record(httptest.NewRequest("GET", "/v1/admin/stats", nil), nil, models.AuditAdminAccess, "")
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Record blocked on a full queue")
+	}
+
+	close(release)
+	a.Close()
+	if writes < auditQueueSize || writes > auditQueueSize+1 {
+		t.Fatalf("expected about %d writes, got %d", auditQueueSize, writes)
+	}
+}
+
+func TestAdminRoutes_AuditOnlyPermittedCalls(t *testing.T) {
+	tests := []struct {
+		role   string
+		status int
+		audits int
+	}{
+		{"user", http.StatusForbidden, 0},
+		{"admin", http.StatusOK, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			d := newTestDeps(t)
+			d.sessions.EXPECT().IsTokenBlacklisted(mock.Anything, mock.Anything).Return(false, nil)
+			if tt.status == http.StatusOK {
+				d.repo.EXPECT().GetAdminStats(mock.Anything).Return(&repository.AdminStats{}, nil)
+			}
+			h := d.handler()
+			r := chi.NewRouter()
+			h.RegisterRoutes(r)
+
+			token, err := auth.NewToken(auth.NewHS256Keys(d.config.JWT.Secret), d.config.JWT.Issuer, uuid.NewString(), []string{tt.role}, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest("GET", "/v1/admin/stats", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			d.flushAudit(h)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if len(d.audits) != tt.audits {
+				t.Fatalf("expected %d audit entries, got %+v", tt.audits, d.audits)
+			}
+		})
+	}
+}
+
+func TestAdminListAudit_AppliesFilters(t *testing.T) {
+	d := newTestDeps(t)
+	actorID := uuid.New()
+	d.repo.EXPECT().
+		ListAudit(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, f repository.AuditFilter) {
+			if f.ActorID == nil || *f.ActorID != actorID {
+				t.Errorf("expected actor_id filter %s, got %v", actorID, f.ActorID)
+			}
+			if f.Action != models.AuditLoginFailed {
+				t.Errorf("expected action filter, got %q", f.Action)
+			}
+			if f.To == nil || !f.To.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("unexpected to: %v", f.To)
+			}
+		}).
+		Return([]models.AuditEntry{{ID: 1, Action: models.AuditLoginFailed}}, 1, nil)
+	h := d.handler()
+
+	url := "/v1/admin/audit?actor_id=" + actorID.String() + "&action=auth.login_failed&to=2026-01-31"
+	w := httptest.NewRecorder()
+	h.Admin.ListAudit(w, httptest.NewRequest("GET", url, nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var resp PaginatedResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Total != 1 {
+		t.Fatalf("expected total 1, got %d", resp.Total)
+	}
+}
+
+func TestAdminListAudit_InvalidParams(t *testing.T) {
+	for _, query := range []string{"actor_id=nope", "from=yesterday"} {
+		t.Run(query, func(t *testing.T) {
+			h := newTestDeps(t).handler()
+			w := httptest.NewRecorder()
+
+			h.Admin.ListAudit(w, httptest.NewRequest("GET", "/v1/admin/audit?"+query, nil))
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+// --- Profile tests ---
+
+func TestUpdateMe_Success(t *testing.T) {
+	d := newTestDeps(t)
+	userID := uuid.New()
+	d.authSvc.EXPECT().
+		UpdateProfile(mock.Anything, userID, mock.MatchedBy(func(u service.ProfileUpdate) bool {
+			return u.Username == nil && u.Email != nil && *u.Email == "new@example.com"
+		})).
+		Return(&models.User{ID: userID, Username: "alice", Email: "new@example.com"}, nil)
+
+	h := d.handler()
+	req := withAuthContext(httptest.NewRequest("PATCH", "/v1/users/me",
+		strings.NewReader(`{"email":"new@example.com"}`)), userID, []string{"user"})
+	w := httptest.NewRecorder()
+
+	h.Profile.UpdateMe(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp["email"] != "new@example.com" || resp["email_verified"] != false {
+		t.Fatalf("unexpected profile: %v", resp)
+	}
+	if _, ok := resp["password_hash"]; ok {
+		t.Fatal("profile must not expose the password hash")
+	}
+}
+
+func TestUpdateMe_EmptyBody(t *testing.T) {
+	d := newTestDeps(t)
+	h := d.handler()
+	req := withAuthContext(httptest.NewRequest("PATCH", "/v1/users/me", strings.NewReader(`{}`)), uuid.New(), []string{"user"})
+	w := httptest.NewRecorder()
+
+	h.Profile.UpdateMe(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestUpdateMe_Conflict(t *testing.T) {
+	d := newTestDeps(t)
+	d.authSvc.EXPECT().
+		UpdateProfile(mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("username or email is already taken: %w", apperr.ErrConflict))
+
+	h := d.handler()
+	req := withAuthContext(httptest.NewRequest("PATCH", "/v1/users/me",
+		strings.NewReader(`{"username":"bob"}`)), uuid.New(), []string{"user"})
+	w := httptest.NewRecorder()
+
+	h.Profile.UpdateMe(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+}
+
+// --- Admin role management tests ---
+
+func TestAdminCreatePermission_DerivesName(t *testing.T) {
+	d := newTestDeps(t)
+	d.repo.EXPECT().
+		CreatePermission(mock.Anything, mock.MatchedBy(func(p *models.Permission) bool {
+			return p.Name == "report:export" && p.Resource == "report" && p.Action == "export"
+		})).
+		Return(nil)
+
+	h := d.handler()
+	req := httptest.NewRequest("POST", "/v1/admin/permissions",
+		strings.NewReader(`{"resource":"report","action":"export"}`))
+	w := httptest.NewRecorder()
+
+	h.Admin.CreatePermission(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestAdminCreateRole_Conflict(t *testing.T) {
+	d := newTestDeps(t)
+	d.repo.EXPECT().
+		CreateRole(mock.Anything, mock.Anything).
+		Return(fmt.Errorf("role exists: %w", apperr.ErrConflict))
+
+	h := d.handler()
+	req := httptest.NewRequest("POST", "/v1/admin/roles", strings.NewReader(`{"name":"doctor"}`))
+	w := httptest.NewRecorder()
+
+	h.Admin.CreateRole(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+}
+
+func TestAdminAttachPermission_ReloadsPermissionCache(t *testing.T) {
+	t.Cleanup(func() {
+		auth.InitPermsFromDB(map[string][]string{
+			auth.RoleUser:  {auth.PermECGSubmit, auth.PermJobReadOwn},
+			auth.RoleAdmin: {auth.PermECGSubmit, auth.PermJobReadAll, auth.PermAdminAll},
+		})
+	})
+
+	d := newTestDeps(t)
+	d.repo.EXPECT().AttachPermission(mock.Anything, "doctor", "report:export").Return(nil)
+	d.repo.EXPECT().
+		LoadRolePermissions(mock.Anything).
+		Return(map[string][]string{"doctor": {"report:export"}}, nil)
+
+	h := d.handler()
+	req := httptest.NewRequest("POST", "/v1/admin/roles/doctor/permissions",
+		strings.NewReader(`{"permission":"report:export"}`))
+	req = addChiURLParam(req, "name", "doctor")
+	w := httptest.NewRecorder()
+
+	h.Admin.AttachPermission(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, ok := auth.PermsForRoles([]string{"doctor"})["report:export"]; !ok {
+		t.Fatal("expected permission cache to include the attached permission")
+	}
+}
+
+func TestAdminUnassignRole_NotFound(t *testing.T) {
+	d := newTestDeps(t)
+	userID := uuid.New()
+	d.repo.EXPECT().
+		UnassignRoleFromUser(mock.Anything, userID, "doctor").
+		Return(fmt.Errorf("user has no role: %w", apperr.ErrNotFound))
+
+	h := d.handler()
+	req := httptest.NewRequest("DELETE", "/v1/admin/users/"+userID.String()+"/roles/doctor", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", userID.String())
+	rctx.URLParams.Add("role", "doctor")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	h.Admin.UnassignRole(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
 // --- Benchmarks ---
 
 func BenchmarkHandlers_RequestMarshaling(b *testing.B) {
@@ -2,26 +2,98 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"runtime"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/config"
+	"github.com/fedutinova/smartheart/back-api/job"
+	"github.com/fedutinova/smartheart/back-api/repository"
+	"github.com/fedutinova/smartheart/back-api/storage"
 )
 
+// ModelLister is the OpenAI call used to probe API connectivity.
+type ModelLister interface {
+	ListModels(ctx context.Context) (openai.ModelsList, error)
+}
+
+func newHealthHandler(queue job.Queue, repo repository.Store, sessions auth.SessionService, storageService storage.Storage, cfg config.Config) *HealthHandler {
+	h := &HealthHandler{
+		Queue:           queue,
+		Repo:            repo,
+		Sessions:        sessions,
+		Storage:         storageService,
+		StorageCritical: cfg.Storage.HealthCritical,
+		CheckOpenAI:     cfg.GPT.HealthCheck,
+		AIProvider:      cfg.GPT.Provider,
+	}
+	if cfg.GPT.HealthCheck && cfg.GPT.Provider != config.AIProviderAnthropic && cfg.GPT.APIKey != "" {
+		h.OpenAI = newOpenAIClient(cfg.GPT.APIKey, cfg.GPT.BaseURL)
+	}
+	return h
+}
+
+// newOpenAIClient returns an OpenAI client for the official API, or for
+// baseURL when it is set.
+func newOpenAIClient(apiKey, baseURL string) *openai.Client {
+	cfg := openai.DefaultConfig(apiKey)
+	if baseURL != "" {
+		cfg.BaseURL = baseURL
+	}
+	return openai.NewClientWithConfig(cfg)
+}
+
+// PoolStatter reports database connection pool statistics. *pgxpool.Pool
+// implements it.
+type PoolStatter interface {
+	Stat() *pgxpool.Stat
+}
+
 // HealthStatus represents the health check response.
 type HealthStatus struct {
 	Status    string           `json:"status"`
 	Timestamp string           `json:"timestamp"`
 	Version   string           `json:"version,omitempty"`
+	Commit    string           `json:"commit,omitempty"`
+	BuildTime string           `json:"build_time,omitempty"`
 	Checks    map[string]Check `json:"checks,omitempty"`
 	System    *SystemInfo      `json:"system,omitempty"`
 }
 
+// BuildInfo describes the running binary. Version, Commit and BuildTime are
+// injected into cmd/main.go with -ldflags "-X main.Version=... -X
+// main.Commit=... -X main.BuildTime=...".
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
 // Check represents a single health check result.
 type Check struct {
-	Status   string `json:"status"`
-	Message  string `json:"message,omitempty"`
-	Duration string `json:"duration,omitempty"`
+	Status   string       `json:"status"`
+	Message  string       `json:"message,omitempty"`
+	Duration string       `json:"duration,omitempty"`
+	Pool     *DBPoolStats `json:"pool,omitempty"`
+}
+
+// DBPoolStats is a snapshot of the database connection pool.
+type DBPoolStats struct {
+	MaxConns          int32  `json:"max_conns"`
+	TotalConns        int32  `json:"total_conns"`
+	AcquiredConns     int32  `json:"acquired_conns"`
+	IdleConns         int32  `json:"idle_conns"`
+	ConstructingConns int32  `json:"constructing_conns"`
+	AcquireCount      int64  `json:"acquire_count"`
+	EmptyAcquireCount int64  `json:"empty_acquire_count"` // acquires that had to wait for a connection
+	AcquireDuration   string `json:"acquire_duration"`    // total time spent acquiring
 }
 
 // SystemInfo contains system information.
@@ -36,18 +108,30 @@ const (
 	StatusHealthy   = "healthy"
 	StatusUnhealthy = "unhealthy"
 	StatusDegraded  = "degraded"
+	StatusSkipped   = "skipped"
 
-	queueBacklogThreshold = 500 // warn when queue has more pending jobs
+	queueBacklogThreshold = 500 // warn when more jobs than this wait for a worker
+	openAICheckTimeout    = 3 * time.Second
 )
 
 // Health returns basic health status (for load balancer).
-func (*HealthHandler) Health(w http.ResponseWriter, _ *http.Request) {
+func (h *HealthHandler) Health(w http.ResponseWriter, _ *http.Request) {
 	writeJSON(w, http.StatusOK, HealthStatus{
 		Status:    StatusHealthy,
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Version:   h.Build.Version,
+		Commit:    h.Build.Commit,
+		BuildTime: h.Build.BuildTime,
 	})
 }
 
+// Version returns build information of the running binary.
+func (h *HealthHandler) Version(w http.ResponseWriter, _ *http.Request) {
+	info := h.Build
+	info.GoVersion = runtime.Version()
+	writeJSON(w, http.StatusOK, info)
+}
+
 // Ready performs full readiness check including dependencies.
 func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
@@ -76,14 +160,28 @@ func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 	storageCheck := h.checkStorage(ctx)
 	checks["storage"] = storageCheck
 	if storageCheck.Status != StatusHealthy {
-		if overallStatus == StatusHealthy {
+		if h.StorageCritical {
+			overallStatus = StatusUnhealthy
+		} else if overallStatus == StatusHealthy {
+			overallStatus = StatusDegraded
+		}
+	}
+
+	// Check OpenAI (opt-in: costs a round trip)
+	if h.CheckOpenAI {
+		openAICheck := h.checkOpenAI(ctx)
+		checks["openai"] = openAICheck
+		if openAICheck.Status == StatusDegraded && overallStatus == StatusHealthy {
 			overallStatus = StatusDegraded
 		}
 	}
 
 	// Check queue
-	queueCheck := h.checkQueue()
+	queueCheck := h.checkQueue(ctx)
 	checks["queue"] = queueCheck
+	if queueCheck.Status == StatusUnhealthy && overallStatus == StatusHealthy {
+		overallStatus = StatusDegraded
+	}
 
 	// System info
 	var memStats runtime.MemStats
@@ -98,6 +196,9 @@ func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 	status := HealthStatus{
 		Status:    overallStatus,
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Version:   h.Build.Version,
+		Commit:    h.Build.Commit,
+		BuildTime: h.Build.BuildTime,
 		Checks:    checks,
 		System:    sysInfo,
 	}
@@ -113,19 +214,48 @@ func (h *HealthHandler) checkDatabase(ctx context.Context) Check {
 	start := time.Now()
 	err := h.Repo.Ping(ctx)
 	duration := time.Since(start)
+	pool := h.poolStats()
 
 	if err != nil {
 		return Check{
 			Status:   StatusUnhealthy,
-			Message:  err.Error(),
+			Message:  pool.describe(err.Error()),
 			Duration: duration.String(),
+			Pool:     pool,
 		}
 	}
 
 	return Check{
 		Status:   StatusHealthy,
-		Message:  "connection successful",
+		Message:  pool.describe("connection successful"),
 		Duration: duration.String(),
+		Pool:     pool,
+	}
+}
+
+// describe appends a pool summary to msg; a nil snapshot leaves msg as is.
+func (s *DBPoolStats) describe(msg string) string {
+	if s == nil {
+		return msg
+	}
+	return fmt.Sprintf("%s (pool: total %d/%d, acquired %d, idle %d, waited acquires %d)",
+		msg, s.TotalConns, s.MaxConns, s.AcquiredConns, s.IdleConns, s.EmptyAcquireCount)
+}
+
+func (h *HealthHandler) poolStats() *DBPoolStats {
+	if h.DBPool == nil {
+		return nil
+	}
+	s := h.DBPool.Stat()
+	return &DBPoolStats{
+		MaxConns:          s.MaxConns(),
+		TotalConns:        s.TotalConns(),
+		AcquiredConns:     s.AcquiredConns(),
+		IdleConns:         s.IdleConns(),
+		ConstructingConns: s.ConstructingConns(),
+		AcquireCount:      s.AcquireCount(),
+		EmptyAcquireCount: s.EmptyAcquireCount(),
+		AcquireDuration:   s.AcquireDuration().String(),
 	}
 }
 
@@ -151,12 +281,16 @@ func (h *HealthHandler) checkRedis(ctx context.Context) Check {
 
 func (h *HealthHandler) checkStorage(ctx context.Context) Check {
 	start := time.Now()
-	_, err := h.Storage.GetPresignedURL(ctx, "healthcheck", 1*time.Minute)
+	err := h.Storage.HealthCheck(ctx)
 	duration := time.Since(start)
 
 	if err != nil {
+		status := StatusDegraded
+		if h.StorageCritical {
+			status = StatusUnhealthy
+		}
 		return Check{
-			Status:   StatusUnhealthy,
+			Status:   status,
 			Message:  err.Error(),
 			Duration: duration.String(),
 		}
@@ -169,19 +303,68 @@ func (h *HealthHandler) checkStorage(ctx context.Context) Check {
 	}
 }
 
-func (h *HealthHandler) checkQueue() Check {
-	queueLen := h.Queue.Len()
+// checkOpenAI lists models to verify the API key and reachability. Failure is
+// reported as degraded: uploads and reads keep working without OpenAI. It is
+// skipped when another provider serves the models.
+func (h *HealthHandler) checkOpenAI(ctx context.Context) Check {
+	if h.AIProvider == config.AIProviderAnthropic {
+		return Check{Status: StatusSkipped, Message: "AI provider is anthropic"}
+	}
+	if h.OpenAI == nil {
+		return Check{Status: StatusSkipped, Message: "not configured"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, openAICheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	_, err := h.OpenAI.ListModels(ctx)
+	duration := time.Since(start)
+
+	if err != nil {
+		return Check{
+			Status:   StatusDegraded,
+			Message:  err.Error(),
+			Duration: duration.String(),
+		}
+	}
+
+	return Check{
+		Status:   StatusHealthy,
+		Message:  "api key valid",
+		Duration: duration.String(),
+	}
+}
+
+func (h *HealthHandler) checkQueue(ctx context.Context) Check {
+	stats, err := h.Queue.Stats(ctx)
+	if errors.Is(err, job.ErrQueueUnavailable) {
+		return Check{
+			Status:  StatusUnhealthy,
+			Message: err.Error(),
+		}
+	}
+	if err != nil {
+		return Check{
+			Status:  StatusDegraded,
+			Message: fmt.Sprintf("queue stats unavailable: %v", err),
+		}
+	}
 
 	status := StatusHealthy
 	message := "queue operational"
 
-	if queueLen > queueBacklogThreshold {
+	if stats.Queued > queueBacklogThreshold {
 		status = StatusDegraded
 		message = "queue backlog detected"
 	}
 
+	counts := fmt.Sprintf("queued: %d, running: %d, scheduled: %d", stats.Queued, stats.Running, stats.Scheduled)
+	if stats.Length > 0 {
+		counts += fmt.Sprintf(", stream length: %d", stats.Length)
+	}
 	return Check{
 		Status:  status,
-		Message: fmt.Sprintf("%s (pending: %d)", message, queueLen),
+		Message: fmt.Sprintf("%s (%s)", message, counts),
 	}
 }
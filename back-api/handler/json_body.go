@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+// requireJSON guards routes that read a JSON body. A request carrying a body
+// must declare Content-Type application/json, or it is rejected with 415;
+// bodies over maxBodySize are rejected with 413, up front when the length is
+// declared and otherwise by decodeAndValidate. Requests without a body pass,
+// so routes with an optional body or none keep working.
+//
+// Multipart routes must not use it; they bound their bodies in
+// parseMultipart.
+func requireJSON(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+			writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+			return
+		}
+		if r.ContentLength > maxBodySize {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", maxBodySize))
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,13 +1,29 @@
 package handler
 
 import (
+	"crypto/sha256"
 	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"sync"
+
+	"gopkg.in/yaml.v3"
 )
 
 //go:embed openapi.yaml
 var openapiSpec []byte
 
+// openapiJSON converts the embedded YAML spec to JSON once, on first use.
+var openapiJSON = sync.OnceValues(func() ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(openapiSpec, &doc); err != nil {
+		return nil, fmt.Errorf("parse openapi.yaml: %w", err)
+	}
+	return json.Marshal(doc)
+})
+
 // OpenAPISpec serves the OpenAPI 3.0 specification.
 func OpenAPISpec(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "application/yaml")
@@ -15,3 +31,56 @@ func OpenAPISpec(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write(openapiSpec)
 }
+
+// OpenAPISpecJSON serves the OpenAPI 3.0 specification as JSON.
+func OpenAPISpecJSON(w http.ResponseWriter, _ *http.Request) {
+	spec, err := openapiJSON()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to render spec")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(spec)
+}
+
+const swaggerUIBase = "https://unpkg.com/swagger-ui-dist@5.17.14"
+
+const swaggerInitScript = `window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });`
+
+var swaggerUIPage = []byte(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>SmartHeart API</title>
+<link rel="stylesheet" href="` + swaggerUIBase + `/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="` + swaggerUIBase + `/swagger-ui-bundle.js"></script>
+<script>` + swaggerInitScript + `</script>
+</body>
+</html>
+`)
+
+// swaggerUICSP relaxes the API-wide CSP just enough for the docs page: the
+// Swagger UI assets from the CDN and the inline init script, pinned by hash.
+var swaggerUICSP = func() string {
+	sum := sha256.Sum256([]byte(swaggerInitScript))
+	hash := base64.StdEncoding.EncodeToString(sum[:])
+	return "default-src 'none'; " +
+		"script-src " + swaggerUIBase + "/ 'sha256-" + hash + "'; " +
+		"style-src " + swaggerUIBase + "/; " +
+		"img-src 'self' data:; " +
+		"connect-src 'self'; " +
+		"frame-ancestors 'none'"
+}()
+
+// SwaggerUI serves an interactive API explorer backed by /openapi.json.
+func SwaggerUI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", swaggerUICSP)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(swaggerUIPage)
+}
@@ -25,8 +25,6 @@ type changePasswordRequest struct {
 }
 
 func (h *PasswordHandler) RequestReset(w http.ResponseWriter, r *http.Request) {
-	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
-
 	var req requestResetRequest
 	if !decodeAndValidate(w, r, &req) {
 		return
@@ -43,8 +41,6 @@ func (h *PasswordHandler) RequestReset(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *PasswordHandler) ConfirmReset(w http.ResponseWriter, r *http.Request) {
-	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
-
 	var req confirmResetRequest
 	if !decodeAndValidate(w, r, &req) {
 		return
@@ -61,8 +57,6 @@ func (h *PasswordHandler) ConfirmReset(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *PasswordHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
-	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
-
 	userID, _, ok := extractUserID(r)
 	if !ok {
 		writeError(w, http.StatusUnauthorized, "unauthorized")
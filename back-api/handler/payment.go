@@ -89,8 +89,6 @@ func (h *PaymentHandler) GetQuota(w http.ResponseWriter, r *http.Request) {
 
 // ApplyPromoCode validates and returns discount info for a promo code.
 func (h *PaymentHandler) ApplyPromoCode(w http.ResponseWriter, r *http.Request) {
-	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
-
 	var req applyPromoCodeRequest
 	if !decodeAndValidate(w, r, &req) {
 		return
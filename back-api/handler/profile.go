@@ -3,12 +3,20 @@ package handler
 import (
 	"net/http"
 
+	"github.com/fedutinova/smartheart/back-api/models"
 	"github.com/fedutinova/smartheart/back-api/repository"
+	"github.com/fedutinova/smartheart/back-api/service"
 )
 
 // ProfileHandler handles user profile endpoints.
 type ProfileHandler struct {
-	Repo repository.Store
+	Repo    repository.Store
+	Service service.AuthService
+}
+
+type updateProfileRequest struct {
+	Username *string `json:"username" validate:"omitempty,min=1,max=100"`
+	Email    *string `json:"email"    validate:"omitempty,email"`
 }
 
 // GetMe returns the current user's profile.
@@ -25,16 +33,51 @@ func (h *ProfileHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	writeJSON(w, http.StatusOK, profileResponse(user))
+}
+
+// UpdateMe changes the current user's username and/or email.
+func (h *ProfileHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := extractUserID(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req updateProfileRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	if req.Username == nil && req.Email == nil {
+		writeError(w, http.StatusBadRequest, "nothing to update")
+		return
+	}
+
+	user, err := h.Service.UpdateProfile(r.Context(), userID, service.ProfileUpdate{
+		Username: req.Username,
+		Email:    req.Email,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, profileResponse(user))
+}
+
+func profileResponse(user *models.User) map[string]any {
 	roles := make([]string, len(user.Roles))
 	for i, r := range user.Roles {
 		roles[i] = r.Name
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"id":         user.ID,
-		"username":   user.Username,
-		"email":      user.Email,
-		"roles":      roles,
-		"created_at": user.CreatedAt,
-	})
+	return map[string]any{
+		"id":                 user.ID,
+		"username":           user.Username,
+		"email":              user.Email,
+		"roles":              roles,
+		"created_at":         user.CreatedAt,
+		"two_factor_enabled": user.TwoFactorEnabled,
+		"email_verified":     user.EmailVerified,
+	}
 }
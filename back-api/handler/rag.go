@@ -15,6 +15,7 @@ import (
 	"github.com/sashabaranov/go-openai"
 
 	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/logging"
 	"github.com/fedutinova/smartheart/back-api/models"
 	"github.com/fedutinova/smartheart/back-api/repository"
 )
@@ -28,14 +29,14 @@ type RAGHandler struct {
 }
 
 // NewRAGHandler creates a handler that forwards requests to the RAG service.
-func NewRAGHandler(ragURL string, repo repository.Store, apiKey string) *RAGHandler {
+func NewRAGHandler(ragURL string, repo repository.Store, apiKey, baseURL string) *RAGHandler {
 	h := &RAGHandler{
 		ragURL: ragURL,
 		client: &http.Client{Timeout: 120 * time.Second},
 		repo:   repo,
 	}
 	if apiKey != "" {
-		h.judgeClient = openai.NewClient(apiKey)
+		h.judgeClient = newOpenAIClient(apiKey, baseURL)
 	}
 	return h
 }
@@ -58,8 +59,6 @@ type ragEmbedResponse struct {
 // Query handles POST /v1/rag/query — validates input, proxies to RAG service,
 // and records the request/response in the database for performance tracking.
 func (h *RAGHandler) Query(w http.ResponseWriter, r *http.Request) {
-	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
-
 	if h.ragURL == "" {
 		writeError(w, http.StatusServiceUnavailable, "RAG service not configured")
 		return
@@ -94,6 +93,9 @@ func (h *RAGHandler) Query(w http.ResponseWriter, r *http.Request) {
 		TextQuery: &question,
 		Status:    models.StatusProcessing,
 	}
+	if id := logging.RequestID(r.Context()); id != "" {
+		dbReq.CorrelationID = &id
+	}
 	if err := h.repo.CreateRequest(r.Context(), dbReq); err != nil {
 		slog.Error("Failed to create RAG request record", "error", err)
 		// Non-fatal: continue serving the query even if tracking fails.
@@ -336,8 +338,6 @@ type ragFeedbackRequest struct {
 
 // Feedback handles POST /v1/rag/feedback — stores user feedback on RAG answers.
 func (h *RAGHandler) Feedback(w http.ResponseWriter, r *http.Request) {
-	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
-
 	var req ragFeedbackRequest
 	if !decodeAndValidate(w, r, &req) {
 		return
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -11,6 +12,9 @@ import (
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+
+	"github.com/fedutinova/smartheart/back-api/models"
+	"github.com/fedutinova/smartheart/back-api/repository"
 )
 
 type fileURLResponse struct {
@@ -18,7 +22,9 @@ type fileURLResponse struct {
 }
 
 // GetUserRequests returns requests for the authenticated user with pagination.
-// Query params: ?limit=N&offset=N (defaults: limit=50, offset=0).
+// Query params: ?limit=N&offset=N (defaults: limit=50, offset=0), and the
+// filters status, from and to (as for the admin ListRequests) and
+// ?tag.<key>=<value> to keep only requests carrying that tag.
 func (h *RequestHandler) GetUserRequests(w http.ResponseWriter, r *http.Request) {
 	userID, _, ok := extractUserID(r)
 	if !ok {
@@ -39,7 +45,23 @@ func (h *RequestHandler) GetUserRequests(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	page, err := h.Service.GetUserRequests(r.Context(), userID, limit, offset)
+	filter := repository.RequestFilter{Limit: limit, Offset: offset}
+	q := r.URL.Query()
+	if v := q.Get("status"); v != "" {
+		if !models.ValidRequestStatus(v) {
+			writeError(w, http.StatusBadRequest, "invalid status")
+			return
+		}
+		filter.Status = v
+	}
+	if !parseDateRange(w, q, &filter.From, &filter.To) {
+		return
+	}
+	if filter.Tags, ok = tagFilterFromQuery(w, q); !ok {
+		return
+	}
+
+	page, err := h.Service.GetUserRequests(r.Context(), userID, filter)
 	if err != nil {
 		handleServiceError(w, err)
 		return
@@ -53,6 +75,29 @@ func (h *RequestHandler) GetUserRequests(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// RequestStatsResponse is the body of GET /v1/requests/stats.
+type RequestStatsResponse struct {
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"by_status"`
+}
+
+// GetRequestStats returns the caller's request counts by status.
+func (h *RequestHandler) GetRequestStats(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := extractUserID(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "no auth context")
+		return
+	}
+
+	stats, err := h.Service.GetRequestStats(r.Context(), userID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, RequestStatsResponse{Total: stats.Total, ByStatus: stats.ByStatus})
+}
+
 // GetRequest returns a specific request by ID.
 func (h *RequestHandler) GetRequest(w http.ResponseWriter, r *http.Request) {
 	raw := chi.URLParam(r, "id")
@@ -62,7 +107,7 @@ func (h *RequestHandler) GetRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, claims, ok := extractUserID(r)
+	userID, claims, ok := extractUserID(r)
 	if !ok {
 		writeError(w, http.StatusUnauthorized, "no auth context")
 		return
@@ -73,6 +118,7 @@ func (h *RequestHandler) GetRequest(w http.ResponseWriter, r *http.Request) {
 		handleServiceError(w, err)
 		return
 	}
+	h.Audit.Record(r, &userID, models.AuditRequestRead, "request:"+id.String())
 
 	// Fill in missing S3URL from storage config
 	for i := range request.Files {
@@ -84,6 +130,29 @@ func (h *RequestHandler) GetRequest(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, request)
 }
 
+// DeleteRequest soft-deletes a request owned by the caller and removes its files.
+func (h *RequestHandler) DeleteRequest(w http.ResponseWriter, r *http.Request) {
+	raw := chi.URLParam(r, "id")
+	id, err := parseUUID(raw)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request ID")
+		return
+	}
+
+	_, claims, ok := extractUserID(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "no auth context")
+		return
+	}
+
+	if err := h.Service.DeleteRequest(r.Context(), id, claims); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // GetJob returns the status of a job by ID.
 func (h *RequestHandler) GetJob(w http.ResponseWriter, r *http.Request) {
 	raw := chi.URLParam(r, "id")
@@ -129,6 +198,85 @@ func (h *RequestHandler) GetRequestFile(w http.ResponseWriter, r *http.Request)
 	_, _ = io.Copy(w, rc)
 }
 
+// DownloadFile streams a stored file by its ID.
+// The caller must own the parent request or have admin access.
+func (h *RequestHandler) DownloadFile(w http.ResponseWriter, r *http.Request) {
+	fileID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid file ID")
+		return
+	}
+
+	_, claims, ok := extractUserID(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "no auth context")
+		return
+	}
+
+	file, err := h.Service.GetFile(r.Context(), fileID, claims)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	rc, contentType, err := h.Storage.GetFile(r.Context(), file.S3Key)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "file not found")
+		return
+	}
+	defer func() { _ = rc.Close() }()
+
+	if file.FileType != "" {
+		contentType = file.FileType
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	if file.OriginalFilename != "" {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": file.OriginalFilename}))
+	}
+	_, _ = io.Copy(w, rc)
+}
+
+// DownloadThumbnail streams the preview generated for an image file.
+// It returns 404 when the file has no thumbnail.
+func (h *RequestHandler) DownloadThumbnail(w http.ResponseWriter, r *http.Request) {
+	fileID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid file ID")
+		return
+	}
+
+	_, claims, ok := extractUserID(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "no auth context")
+		return
+	}
+
+	file, err := h.Service.GetFile(r.Context(), fileID, claims)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	if file.ThumbnailKey == "" {
+		writeError(w, http.StatusNotFound, "thumbnail not found")
+		return
+	}
+
+	rc, _, err := h.Storage.GetFile(r.Context(), file.ThumbnailKey)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "thumbnail not found")
+		return
+	}
+	defer func() { _ = rc.Close() }()
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	_, _ = io.Copy(w, rc)
+}
+
 // GetRequestFileURL returns a direct file URL when the storage backend supports it.
 // This avoids proxying image bytes through the API and lets the browser load the file directly.
 func (h *RequestHandler) GetRequestFileURL(w http.ResponseWriter, r *http.Request) {
@@ -138,7 +286,7 @@ func (h *RequestHandler) GetRequestFileURL(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	url, err := h.Storage.GetPresignedURL(r.Context(), s3Key, h.Config.JWT.TTLAccess)
+	url, err := h.Storage.GetPresignedURL(r.Context(), s3Key, h.Config.Storage.PresignTTL)
 	if err == nil && url != "" {
 		writeJSON(w, http.StatusOK, fileURLResponse{URL: url})
 		return
@@ -235,6 +383,28 @@ func writeFileLookupError(w http.ResponseWriter, err error) {
 	handleServiceError(w, err)
 }
 
+// FileAccess authorizes /files/* requests: a link signed by local storage
+// (expires and sig query parameters) is checked against FileSigner, and any
+// other request must pass jwt.
+func (h *RequestHandler) FileAccess(jwt Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		authenticated := jwt(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			if q.Get("sig") == "" {
+				authenticated.ServeHTTP(w, r)
+				return
+			}
+			key := strings.TrimPrefix(r.URL.Path, "/files/")
+			if err := h.FileSigner.Verify(key, q.Get("expires"), q.Get("sig")); err != nil {
+				writeError(w, http.StatusForbidden, err.Error())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // ServeFiles serves static files from local storage.
 func (h *RequestHandler) ServeFiles(w http.ResponseWriter, r *http.Request) {
 	filePath := strings.TrimPrefix(r.URL.Path, "/files/")
@@ -243,8 +413,12 @@ func (h *RequestHandler) ServeFiles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Resolve baseDir to absolute path to prevent bypass via symlinks
+	// Resolve baseDir to an absolute, symlink-free path so the prefix check
+	// below compares like with like.
 	baseDir, err := filepath.Abs(filepath.Clean(h.Config.Storage.LocalDir))
+	if err == nil {
+		baseDir, err = filepath.EvalSymlinks(baseDir)
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "invalid storage config")
 		return
@@ -267,5 +441,11 @@ func (h *RequestHandler) ServeFiles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Never list directories.
+	if info, err := os.Stat(realPath); err != nil || info.IsDir() {
+		writeError(w, http.StatusNotFound, "file not found")
+		return
+	}
+
 	http.ServeFile(w, r, realPath)
 }
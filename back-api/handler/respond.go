@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strings"
@@ -12,11 +13,16 @@ import (
 
 	"github.com/fedutinova/smartheart/back-api/apperr"
 	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/job"
 	"github.com/fedutinova/smartheart/back-api/service"
 )
 
 var validate = validator.New(validator.WithRequiredStructEnabled())
 
+// queueFullRetryAfter is the Retry-After hint (seconds) sent with 503
+// responses when the job queue is saturated.
+const queueFullRetryAfter = "5"
+
 // writeJSON writes a JSON response with the given status code.
 func writeJSON(w http.ResponseWriter, code int, v any) {
 	w.Header().Set("Content-Type", "application/json")
@@ -26,9 +32,59 @@ func writeJSON(w http.ResponseWriter, code int, v any) {
 	}
 }
 
-// writeError writes a structured JSON error response.
-func writeError(w http.ResponseWriter, code int, msg string) {
-	writeJSON(w, code, APIError{Error: msg})
+// Error codes returned in APIError. Clients branch on these, so they must stay
+// stable; messages may change.
+const (
+	codeBadRequest         = "bad_request"
+	codeValidation         = "validation_failed"
+	codeUnauthorized       = "unauthorized"
+	codeInvalidCredentials = "invalid_credentials"
+	codeInvalidToken       = "invalid_token"
+	codeInvalid2FACode     = "invalid_2fa_code"
+	codePaymentRequired    = "payment_required"
+	codeForbidden          = "forbidden"
+	codeEmailNotVerified   = "email_not_verified"
+	codeNotFound           = "not_found"
+	codeConflict           = "conflict"
+	codeNotRetryable       = "not_retryable"
+	codeNotReanalyzable    = "not_reanalyzable"
+	codePayloadTooLarge    = "payload_too_large"
+	codeUnsupportedMedia   = "unsupported_media_type"
+	codeRateLimited        = "rate_limited"
+	codeQuotaExceeded      = "quota_exceeded"
+	codeInternal           = "internal"
+	codeUpstream           = "upstream_error"
+	codeUnavailable        = "unavailable"
+)
+
+// statusCodes is the default error code for each HTTP status.
+var statusCodes = map[int]string{
+	http.StatusBadRequest:            codeBadRequest,
+	http.StatusUnauthorized:          codeUnauthorized,
+	http.StatusPaymentRequired:       codePaymentRequired,
+	http.StatusForbidden:             codeForbidden,
+	http.StatusNotFound:              codeNotFound,
+	http.StatusConflict:              codeConflict,
+	http.StatusRequestEntityTooLarge: codePayloadTooLarge,
+	http.StatusUnsupportedMediaType:  codeUnsupportedMedia,
+	http.StatusTooManyRequests:       codeRateLimited,
+	http.StatusBadGateway:            codeUpstream,
+	http.StatusGatewayTimeout:        codeUpstream,
+	http.StatusServiceUnavailable:    codeUnavailable,
+}
+
+// writeJSONError writes an error response with an explicit error code.
+func writeJSONError(w http.ResponseWriter, status int, code, msg string) {
+	writeJSON(w, status, APIError{Error: ErrorBody{Code: code, Message: msg}})
+}
+
+// writeError writes an error response using the default code for status.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	code, ok := statusCodes[status]
+	if !ok {
+		code = codeInternal
+	}
+	writeJSONError(w, status, code, msg)
 }
 
 // decodeJSON decodes the request body into v.
@@ -47,19 +103,48 @@ func decodeJSON(r *http.Request, v any) error {
 }
 
 // decodeAndValidate decodes JSON body and runs struct tag validation.
-// Returns true on success. On failure it writes an error response and returns false.
+// Returns true on success. On failure it writes an error response and returns
+// false: 413 for a body over the limit set by requireJSON, otherwise 400.
 func decodeAndValidate(w http.ResponseWriter, r *http.Request, v any) bool {
 	if err := decodeJSON(r, v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+			return false
+		}
 		writeError(w, http.StatusBadRequest, "invalid request body")
 		return false
 	}
 	if err := validate.Struct(v); err != nil {
 		var ve validator.ValidationErrors
 		if errors.As(err, &ve) {
-			writeError(w, http.StatusBadRequest, formatValidationErrors(ve))
+			writeJSONError(w, http.StatusBadRequest, codeValidation, formatValidationErrors(ve))
 			return false
 		}
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeJSONError(w, http.StatusBadRequest, codeValidation, err.Error())
+		return false
+	}
+	return true
+}
+
+// multipartOverhead is the body allowance for form fields and part headers
+// on top of the file content in a multipart upload.
+const multipartOverhead = 1 << 20
+
+// parseMultipart bounds the request body to maxFiles uploads of maxFileBytes
+// and parses it as a multipart form, spilling file parts beyond memory bytes
+// to disk. It writes 413 for an oversized body and 400 for a malformed one,
+// returning false in both cases.
+func parseMultipart(w http.ResponseWriter, r *http.Request, maxFiles int, maxFileBytes, memory int64) bool {
+	limit := int64(maxFiles)*maxFileBytes + multipartOverhead
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	if err := r.ParseMultipartForm(memory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", limit))
+			return false
+		}
+		writeError(w, http.StatusBadRequest, "failed to parse form")
 		return false
 	}
 	return true
@@ -113,25 +198,47 @@ func parseUUID(s string) (uuid.UUID, error) {
 
 // handleServiceError maps service-layer errors to HTTP responses.
 func handleServiceError(w http.ResponseWriter, err error) {
+	if errors.Is(err, job.ErrQueueFull) {
+		w.Header().Set("Retry-After", queueFullRetryAfter)
+	}
+	status, body := serviceErrorBody(err)
+	writeJSON(w, status, APIError{Error: body})
+}
+
+// serviceErrorBody returns the HTTP status and error body for a service-layer
+// error. Unrecognized errors are logged and reported as internal.
+func serviceErrorBody(err error) (int, ErrorBody) {
 	switch {
+	case errors.Is(err, job.ErrQueueFull):
+		return http.StatusServiceUnavailable, ErrorBody{Code: codeUnavailable, Message: "service busy, try again later"}
 	case errors.Is(err, service.ErrTooManyAttempts):
-		writeError(w, http.StatusTooManyRequests, "too many attempts, try again later")
+		return http.StatusTooManyRequests, ErrorBody{Code: codeRateLimited, Message: "too many attempts, try again later"}
+	case errors.Is(err, service.ErrNotRetryable):
+		return http.StatusConflict, ErrorBody{Code: codeNotRetryable, Message: err.Error()}
+	case errors.Is(err, service.ErrNotReanalyzable):
+		return http.StatusConflict, ErrorBody{Code: codeNotReanalyzable, Message: err.Error()}
+	case errors.Is(err, apperr.ErrQuotaExceeded):
+		return http.StatusTooManyRequests, ErrorBody{Code: codeQuotaExceeded, Message: err.Error()}
 	case errors.Is(err, apperr.ErrPaymentRequired):
-		writeError(w, http.StatusPaymentRequired, err.Error())
+		return http.StatusPaymentRequired, ErrorBody{Code: codePaymentRequired, Message: err.Error()}
 	case apperr.IsValidation(err):
-		writeError(w, http.StatusBadRequest, err.Error())
+		return http.StatusBadRequest, ErrorBody{Code: codeValidation, Message: err.Error()}
 	case errors.Is(err, apperr.ErrInvalidCredentials):
-		writeError(w, http.StatusUnauthorized, "invalid email or password")
+		return http.StatusUnauthorized, ErrorBody{Code: codeInvalidCredentials, Message: "invalid email or password"}
+	case errors.Is(err, service.ErrInvalidTwoFactorCode):
+		return http.StatusUnauthorized, ErrorBody{Code: codeInvalid2FACode, Message: "invalid two-factor code"}
 	case errors.Is(err, apperr.ErrInvalidToken):
-		writeError(w, http.StatusUnauthorized, "invalid token")
+		return http.StatusUnauthorized, ErrorBody{Code: codeInvalidToken, Message: "invalid token"}
 	case apperr.IsConflict(err):
-		writeError(w, http.StatusConflict, "already exists")
+		return http.StatusConflict, ErrorBody{Code: codeConflict, Message: "already exists"}
 	case apperr.IsNotFound(err):
-		writeError(w, http.StatusNotFound, "not found")
+		return http.StatusNotFound, ErrorBody{Code: codeNotFound, Message: "not found"}
+	case errors.Is(err, apperr.ErrEmailNotVerified):
+		return http.StatusForbidden, ErrorBody{Code: codeEmailNotVerified, Message: "email address is not verified"}
 	case apperr.IsForbidden(err):
-		writeError(w, http.StatusForbidden, "forbidden")
+		return http.StatusForbidden, ErrorBody{Code: codeForbidden, Message: "forbidden"}
 	default:
 		slog.Error("Unhandled service error", "error", err)
-		writeError(w, http.StatusInternalServerError, "internal error")
+		return http.StatusInternalServerError, ErrorBody{Code: codeInternal, Message: "internal error"}
 	}
 }
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+// tagQueryPrefix marks the list query params that filter by tag:
+// ?tag.<key>=<value>.
+const tagQueryPrefix = "tag."
+
+// validTags checks submitted tags, writing a 400 and returning false if they
+// are invalid.
+func validTags(w http.ResponseWriter, tags map[string]string) bool {
+	if err := models.ValidateTags(tags); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid tags: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// tagsFromForm reads the optional "tags" multipart field, a JSON object of
+// string values. An invalid field writes a 400 and returns false.
+func tagsFromForm(w http.ResponseWriter, r *http.Request) (map[string]string, bool) {
+	raw := r.FormValue("tags")
+	if raw == "" {
+		return nil, true
+	}
+	var tags map[string]string
+	if err := json.Unmarshal([]byte(raw), &tags); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid tags: must be a JSON object of strings")
+		return nil, false
+	}
+	if !validTags(w, tags) {
+		return nil, false
+	}
+	return tags, true
+}
+
+// tagFilterFromQuery collects the ?tag.<key>=<value> params of a list query.
+// Repeated params use the first value. Invalid keys or values write a 400 and
+// return false.
+func tagFilterFromQuery(w http.ResponseWriter, q url.Values) (map[string]string, bool) {
+	var tags map[string]string
+	for name, values := range q {
+		key, ok := strings.CutPrefix(name, tagQueryPrefix)
+		if !ok || len(values) == 0 {
+			continue
+		}
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		tags[key] = values[0]
+	}
+	if !validTags(w, tags) {
+		return nil, false
+	}
+	return tags, true
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/models"
+	"github.com/fedutinova/smartheart/back-api/service"
+)
+
+type twoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type twoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code"            validate:"required,len=6,numeric"`
+}
+
+// twoFactorChallengeResponse is returned by login instead of an access token
+// when the user has 2FA enabled.
+type twoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+}
+
+// TwoFactorEnrollResponse carries a new TOTP secret for the authenticator app.
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+}
+
+// EnrollTwoFactor starts TOTP enrollment for the current user. 2FA is not
+// enforced until VerifyTwoFactor confirms a code.
+func (h *AuthHandler) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := extractUserID(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "no auth context")
+		return
+	}
+
+	enrollment, err := h.Service.EnrollTwoFactor(r.Context(), userID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TwoFactorEnrollResponse{Secret: enrollment.Secret, OTPAuthURL: enrollment.URL})
+}
+
+// VerifyTwoFactor enables 2FA after checking a code from the enrolled secret.
+func (h *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req twoFactorCodeRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	userID, _, ok := extractUserID(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "no auth context")
+		return
+	}
+
+	if err := h.Service.VerifyTwoFactor(r.Context(), userID, req.Code); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "two-factor authentication enabled"})
+}
+
+// LoginTwoFactor exchanges a login challenge token and a TOTP code for the
+// token pair.
+func (h *AuthHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req twoFactorLoginRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	result, err := h.Service.LoginTwoFactor(r.Context(), req.ChallengeToken, req.Code)
+	if err != nil {
+		// The user behind a challenge is only known to the service, so a
+		// failed second factor is recorded without an actor.
+		if errors.Is(err, service.ErrInvalidTwoFactorCode) || errors.Is(err, apperr.ErrInvalidToken) {
+			h.Audit.Record(r, nil, models.AuditLoginFailed, "2fa")
+		}
+		handleServiceError(w, err)
+		return
+	}
+
+	h.Audit.Record(r, &result.UserID, models.AuditLogin, "")
+	tokens := result.Tokens
+	auth.SetRefreshTokenCookie(w, tokens.RefreshToken, h.Config.JWT.TTLRefresh, h.Config.Cookie)
+	writeJSON(w, http.StatusOK, accessTokenResponse{AccessToken: tokens.AccessToken})
+}
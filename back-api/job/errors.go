@@ -0,0 +1,35 @@
+package job
+
+import (
+	"errors"
+
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+// codedError attaches a client-facing error code to a handler error.
+type codedError struct {
+	code models.ErrorCode
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+// WithCode tags err with the code reported to clients when the job fails.
+// A nil err stays nil.
+func WithCode(code models.ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// CodeOf returns the code err was tagged with by WithCode, or
+// models.ErrorCodeInternal when it has none.
+func CodeOf(err error) models.ErrorCode {
+	var ce *codedError
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+	return models.ErrorCodeInternal
+}
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	job "github.com/fedutinova/smartheart/back-api/job"
 	uuid "github.com/google/uuid"
@@ -127,6 +128,66 @@ func (_c *MockQueue_Enqueue_Call) RunAndReturn(run func(context.Context, *job.Jo
 	return _c
 }
 
+// EnqueueAt provides a mock function with given fields: ctx, j, when
+func (_m *MockQueue) EnqueueAt(ctx context.Context, j *job.Job, when time.Time) (uuid.UUID, error) {
+	ret := _m.Called(ctx, j, when)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnqueueAt")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *job.Job, time.Time) (uuid.UUID, error)); ok {
+		return rf(ctx, j, when)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *job.Job, time.Time) uuid.UUID); ok {
+		r0 = rf(ctx, j, when)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *job.Job, time.Time) error); ok {
+		r1 = rf(ctx, j, when)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQueue_EnqueueAt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnqueueAt'
+type MockQueue_EnqueueAt_Call struct {
+	*mock.Call
+}
+
+// EnqueueAt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - j *job.Job
+//   - when time.Time
+func (_e *MockQueue_Expecter) EnqueueAt(ctx interface{}, j interface{}, when interface{}) *MockQueue_EnqueueAt_Call {
+	return &MockQueue_EnqueueAt_Call{Call: _e.mock.On("EnqueueAt", ctx, j, when)}
+}
+
+func (_c *MockQueue_EnqueueAt_Call) Run(run func(ctx context.Context, j *job.Job, when time.Time)) *MockQueue_EnqueueAt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*job.Job), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockQueue_EnqueueAt_Call) Return(_a0 uuid.UUID, _a1 error) *MockQueue_EnqueueAt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQueue_EnqueueAt_Call) RunAndReturn(run func(context.Context, *job.Job, time.Time) (uuid.UUID, error)) *MockQueue_EnqueueAt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Len provides a mock function with no fields
 func (_m *MockQueue) Len() int {
 	ret := _m.Called()
@@ -207,6 +268,62 @@ func (_c *MockQueue_StartConsumers_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// Stats provides a mock function with given fields: ctx
+func (_m *MockQueue) Stats(ctx context.Context) (job.QueueStats, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 job.QueueStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (job.QueueStats, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) job.QueueStats); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(job.QueueStats)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQueue_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type MockQueue_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockQueue_Expecter) Stats(ctx interface{}) *MockQueue_Stats_Call {
+	return &MockQueue_Stats_Call{Call: _e.mock.On("Stats", ctx)}
+}
+
+func (_c *MockQueue_Stats_Call) Run(run func(ctx context.Context)) *MockQueue_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockQueue_Stats_Call) Return(_a0 job.QueueStats, _a1 error) *MockQueue_Stats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQueue_Stats_Call) RunAndReturn(run func(context.Context) (job.QueueStats, error)) *MockQueue_Stats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Status provides a mock function with given fields: ctx, id
 func (_m *MockQueue) Status(ctx context.Context, id uuid.UUID) (*job.Job, bool) {
 	ret := _m.Called(ctx, id)
@@ -266,6 +383,65 @@ func (_c *MockQueue_Status_Call) RunAndReturn(run func(context.Context, uuid.UUI
 	return _c
 }
 
+// TryEnqueue provides a mock function with given fields: ctx, j
+func (_m *MockQueue) TryEnqueue(ctx context.Context, j *job.Job) (uuid.UUID, error) {
+	ret := _m.Called(ctx, j)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TryEnqueue")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *job.Job) (uuid.UUID, error)); ok {
+		return rf(ctx, j)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *job.Job) uuid.UUID); ok {
+		r0 = rf(ctx, j)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *job.Job) error); ok {
+		r1 = rf(ctx, j)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQueue_TryEnqueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TryEnqueue'
+type MockQueue_TryEnqueue_Call struct {
+	*mock.Call
+}
+
+// TryEnqueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - j *job.Job
+func (_e *MockQueue_Expecter) TryEnqueue(ctx interface{}, j interface{}) *MockQueue_TryEnqueue_Call {
+	return &MockQueue_TryEnqueue_Call{Call: _e.mock.On("TryEnqueue", ctx, j)}
+}
+
+func (_c *MockQueue_TryEnqueue_Call) Run(run func(ctx context.Context, j *job.Job)) *MockQueue_TryEnqueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*job.Job))
+	})
+	return _c
+}
+
+func (_c *MockQueue_TryEnqueue_Call) Return(_a0 uuid.UUID, _a1 error) *MockQueue_TryEnqueue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQueue_TryEnqueue_Call) RunAndReturn(run func(context.Context, *job.Job) (uuid.UUID, error)) *MockQueue_TryEnqueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockQueue creates a new instance of MockQueue. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockQueue(t interface {
@@ -0,0 +1,74 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Payload is the typed body of a job. Job.Payload carries it as JSON, so the
+// stream format does not depend on the Go type.
+type Payload interface {
+	// JobType is the type of the jobs that carry this payload.
+	JobType() Type
+}
+
+// JobType implements Payload.
+func (ECGJobPayload) JobType() Type { return TypeECGAnalyze }
+
+var (
+	payloadsMu sync.RWMutex
+	payloads   = map[Type]func() Payload{
+		TypeECGAnalyze: func() Payload { return new(ECGJobPayload) },
+	}
+)
+
+// RegisterPayload records the payload type of jobs of type t for
+// UnmarshalPayload. newPayload returns a pointer to an empty payload.
+// Packages defining a payload register it from init.
+func RegisterPayload(t Type, newPayload func() Payload) {
+	payloadsMu.Lock()
+	defer payloadsMu.Unlock()
+	payloads[t] = newPayload
+}
+
+// New returns a job of p's type owned by userID, with p encoded as its
+// payload.
+func New(userID uuid.UUID, p Payload) (*Job, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s payload: %w", p.JobType(), err)
+	}
+	return &Job{Type: p.JobType(), UserID: userID, Payload: data}, nil
+}
+
+// DecodePayload decodes the payload of j as T, failing if j is not of T's
+// job type.
+func DecodePayload[T Payload](j *Job) (T, error) {
+	var p T
+	if j.Type != p.JobType() {
+		return p, fmt.Errorf("unexpected job type: %s", j.Type)
+	}
+	if err := json.Unmarshal(j.Payload, &p); err != nil {
+		return p, fmt.Errorf("failed to unmarshal %s payload: %w", j.Type, err)
+	}
+	return p, nil
+}
+
+// UnmarshalPayload decodes the payload of j into the type registered for
+// j.Type. The result is a pointer, e.g. *ECGJobPayload.
+func UnmarshalPayload(j *Job) (Payload, error) {
+	payloadsMu.RLock()
+	newPayload, ok := payloads[j.Type]
+	payloadsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no payload registered for job type: %s", j.Type)
+	}
+	p := newPayload()
+	if err := json.Unmarshal(j.Payload, p); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s payload: %w", j.Type, err)
+	}
+	return p, nil
+}
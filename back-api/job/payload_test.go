@@ -0,0 +1,94 @@
+package job_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/fedutinova/smartheart/back-api/gpt"
+	"github.com/fedutinova/smartheart/back-api/job"
+)
+
+func TestPayload_RoundTripsThroughRegistry(t *testing.T) {
+	age := 54
+	payloads := []job.Payload{
+		&job.ECGJobPayload{
+			ImageFileKey:  "uploads/ekg.png",
+			UserID:        uuid.New(),
+			RequestID:     uuid.New(),
+			Age:           &age,
+			Sex:           "female",
+			PaperSpeedMMS: 50,
+			CallbackURL:   "https://example.com/hook",
+		},
+		&gpt.JobPayload{
+			RequestID:  uuid.New(),
+			TextQuery:  "check the QT interval",
+			FileKeys:   []string{"uploads/a.png", "uploads/b.png"},
+			UserID:     uuid.New(),
+			Structured: true,
+			Model:      "gpt-4.1",
+			TimeoutMs:  90000,
+		},
+	}
+
+	for _, want := range payloads {
+		t.Run(string(want.JobType()), func(t *testing.T) {
+			userID := uuid.New()
+			j, err := job.New(userID, want)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if j.Type != want.JobType() || j.UserID != userID {
+				t.Fatalf("expected a %s job of %s, got %s of %s", want.JobType(), userID, j.Type, j.UserID)
+			}
+
+			// Through the stream: the job itself is JSON-encoded.
+			data, err := json.Marshal(j)
+			if err != nil {
+				t.Fatalf("marshal job: %v", err)
+			}
+			var decoded job.Job
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("unmarshal job: %v", err)
+			}
+
+			got, err := job.UnmarshalPayload(&decoded)
+			if err != nil {
+				t.Fatalf("UnmarshalPayload: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("payload changed in the round trip:\n got %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodePayload(t *testing.T) {
+	want := gpt.JobPayload{RequestID: uuid.New(), TextQuery: "hello", FileKeys: []string{"k"}}
+	j, err := job.New(uuid.New(), want)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	got, err := job.DecodePayload[gpt.JobPayload](j)
+	if err != nil {
+		t.Fatalf("DecodePayload: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	if _, err := job.DecodePayload[job.ECGJobPayload](j); err == nil || err.Error() != "unexpected job type: gpt_process" {
+		t.Fatalf("expected a job type mismatch, got %v", err)
+	}
+}
+
+func TestUnmarshalPayload_UnknownType(t *testing.T) {
+	_, err := job.UnmarshalPayload(&job.Job{Type: "thumbnail", Payload: []byte(`{}`)})
+	if err == nil {
+		t.Fatal("expected an error for a job type without a registered payload")
+	}
+}
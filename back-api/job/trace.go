@@ -0,0 +1,59 @@
+package job
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/fedutinova/smartheart/back-api/logging"
+)
+
+const tracerName = "github.com/fedutinova/smartheart/back-api/job"
+
+// InjectTraceContext stores the span context and request ID carried by ctx on
+// the job so that the consumer can continue the trace, even across the Redis
+// stream.
+func (j *Job) InjectTraceContext(ctx context.Context) {
+	if j.CorrelationID == "" {
+		j.CorrelationID = logging.RequestID(ctx)
+	}
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) > 0 {
+		j.TraceContext = carrier
+	}
+}
+
+// ExtractTraceContext returns ctx enriched with the remote span context that
+// was stored on the job at enqueue time.
+func (j *Job) ExtractTraceContext(ctx context.Context) context.Context {
+	if len(j.TraceContext) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(j.TraceContext))
+}
+
+// startSpan opens a consumer span for j, parented to the producer's span.
+func (j *Job) startSpan(ctx context.Context) (context.Context, trace.Span) {
+	ctx = j.ExtractTraceContext(ctx)
+	return otel.Tracer(tracerName).Start(ctx, "job "+string(j.Type),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("job.id", j.ID.String()),
+			attribute.String("job.type", string(j.Type)),
+			attribute.String("job.correlation_id", j.CorrelationID),
+		),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
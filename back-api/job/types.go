@@ -2,11 +2,15 @@ package job
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/fedutinova/smartheart/back-api/logging"
+	"github.com/fedutinova/smartheart/back-api/models"
 )
 
 // Handler processes a single job.
@@ -39,18 +43,70 @@ func (r *Registry) Dispatch(ctx context.Context, j *Job) error {
 	if !ok {
 		return fmt.Errorf("unknown job type: %s", j.Type)
 	}
-	return h(ctx, j)
+
+	ctx, span := j.startSpan(logging.WithRequestID(ctx, j.CorrelationID))
+	err := h(ctx, j)
+	endSpan(span, err)
+	return err
 }
 
+// ErrQueueFull is returned by TryEnqueue when the queue has no free capacity.
+var ErrQueueFull = errors.New("queue is full")
+
+// ErrQueueUnavailable is returned while a queue cannot reach its backend.
+var ErrQueueUnavailable = errors.New("queue backend unavailable")
+
 // Queue is the interface for job queue implementations.
 type Queue interface {
+	// Enqueue adds a job, blocking until there is room or ctx is done.
 	Enqueue(ctx context.Context, j *Job) (uuid.UUID, error)
+	// TryEnqueue adds a job without blocking, returning ErrQueueFull
+	// when the queue cannot accept it right now.
+	TryEnqueue(ctx context.Context, j *Job) (uuid.UUID, error)
+	// EnqueueAt schedules a job to become available to consumers at when.
+	// Until then Status reports StatusScheduled. Delivery is at-least-once,
+	// like Enqueue.
+	EnqueueAt(ctx context.Context, j *Job, when time.Time) (uuid.UUID, error)
 	Status(ctx context.Context, id uuid.UUID) (*Job, bool)
 	StartConsumers(ctx context.Context, n int, handler Handler)
+	// Len is a backend-specific depth hint kept for compatibility: the
+	// in-memory queue counts buffered jobs, RedisQueue counts delivered but
+	// unacknowledged ones, or -1 while Redis is unreachable. Prefer Stats.
 	Len() int
+	// Stats reports queue depth with the same meaning on every backend.
+	Stats(ctx context.Context) (QueueStats, error)
 	Close() error
 }
 
+// QueueStats is a point-in-time snapshot of queue depth.
+//
+// Queued jobs wait for a consumer, Running jobs have been handed to one and
+// are not finished yet (for Redis: delivered but not acknowledged, including
+// jobs of crashed consumers awaiting reclaim), Scheduled jobs wait for their
+// EnqueueAt time. Pending is Queued + Running. Length is only reported by
+// RedisQueue: the number of stream entries, including acknowledged ones
+// not trimmed yet.
+//
+// ByType breaks the counts down per job type. The in-memory queue always
+// fills it; RedisQueue only for types with a dedicated stream (see
+// RedisQueueConfig.TypeWorkers), since the shared stream mixes types.
+type QueueStats struct {
+	Queued    int                `json:"queued"`
+	Running   int                `json:"running"`
+	Pending   int                `json:"pending"`
+	Scheduled int                `json:"scheduled"`
+	Length    int                `json:"length,omitempty"`
+	ByType    map[Type]TypeStats `json:"by_type,omitempty"`
+}
+
+// TypeStats holds the QueueStats counts for a single job type.
+type TypeStats struct {
+	Queued    int `json:"queued"`
+	Running   int `json:"running"`
+	Scheduled int `json:"scheduled"`
+	Length    int `json:"length,omitempty"`
+}
+
 type Type string
 
 const (
@@ -73,11 +129,14 @@ type ECGJobPayload struct {
 	PaperSpeedMMS float64   `json:"paper_speed_mms,omitempty"`
 	MmPerMvLimb   float64   `json:"mm_per_mv_limb,omitempty"`
 	MmPerMvChest  float64   `json:"mm_per_mv_chest,omitempty"`
+	// CallbackURL receives a signed POST when the request finishes.
+	CallbackURL string `json:"callback_url,omitempty"`
 }
 
 type Status string
 
 const (
+	StatusScheduled Status = "scheduled"
 	StatusQueued    Status = "queued"
 	StatusRunning   Status = "running"
 	StatusSucceeded Status = "succeeded"
@@ -85,15 +144,30 @@ const (
 )
 
 type Job struct {
-	mu       sync.Mutex
-	ID       uuid.UUID  `json:"id"`
-	Type     Type       `json:"type"`
-	Payload  []byte     `json:"payload"`
-	Status   Status     `json:"status"`
-	Error    string     `json:"error,omitempty"`
-	Enqueued time.Time  `json:"enqueued_at"`
-	Started  *time.Time `json:"started_at,omitempty"`
-	Finished *time.Time `json:"finished_at,omitempty"`
+	mu   sync.Mutex
+	ID   uuid.UUID `json:"id"`
+	Type Type      `json:"type"`
+	// UserID is the owner of the job, set at enqueue time. Jobs enqueued
+	// before it existed carry the owner only in Payload.
+	UserID uuid.UUID `json:"user_id"`
+	// Payload is the JSON encoding of the job's typed Payload. Build jobs
+	// with New and read it back with DecodePayload.
+	Payload  []byte    `json:"payload"`
+	Status   Status    `json:"status"`
+	Error    string    `json:"error,omitempty"`
+	Enqueued time.Time `json:"enqueued_at"`
+	// ErrorCode categorizes a failure for clients. Error then holds its
+	// user-safe message; the handler's own error is only logged.
+	ErrorCode models.ErrorCode `json:"error_code,omitempty"`
+	// ScheduledAt is when a job added with EnqueueAt becomes due.
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	Started     *time.Time `json:"started_at,omitempty"`
+	Finished    *time.Time `json:"finished_at,omitempty"`
+	// TraceContext carries the W3C trace headers of the enqueuing request.
+	TraceContext map[string]string `json:"trace_context,omitempty"`
+	// CorrelationID is the X-Request-ID of the HTTP call that enqueued the
+	// job. Handlers run with it as their request ID, so worker logs match.
+	CorrelationID string `json:"correlation_id,omitempty"`
 }
 
 // snapshot returns a copy of the job without the mutex, safe to return to callers.
@@ -101,18 +175,31 @@ func (j *Job) snapshot() *Job {
 	j.mu.Lock()
 	defer j.mu.Unlock()
 	cp := &Job{
-		ID:       j.ID,
-		Type:     j.Type,
-		Payload:  j.Payload,
-		Status:   j.Status,
-		Error:    j.Error,
-		Enqueued: j.Enqueued,
-		Started:  j.Started,
-		Finished: j.Finished,
+		ID:            j.ID,
+		Type:          j.Type,
+		UserID:        j.UserID,
+		Payload:       j.Payload,
+		Status:        j.Status,
+		Error:         j.Error,
+		ErrorCode:     j.ErrorCode,
+		Enqueued:      j.Enqueued,
+		ScheduledAt:   j.ScheduledAt,
+		Started:       j.Started,
+		Finished:      j.Finished,
+		TraceContext:  j.TraceContext,
+		CorrelationID: j.CorrelationID,
 	}
 	return cp
 }
 
+// SetQueued marks a scheduled job as due and waiting for a consumer
+// (goroutine-safe).
+func (j *Job) SetQueued() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Status = StatusQueued
+}
+
 // SetRunning marks the job as running (goroutine-safe).
 func (j *Job) SetRunning() {
 	j.mu.Lock()
@@ -122,7 +209,9 @@ func (j *Job) SetRunning() {
 	j.Started = &now
 }
 
-// SetFinished marks the job as succeeded or failed (goroutine-safe).
+// SetFinished marks the job as succeeded or failed (goroutine-safe). A
+// failed job records the code of err (see WithCode) and its user-safe
+// message, not err itself.
 func (j *Job) SetFinished(err error) {
 	j.mu.Lock()
 	defer j.mu.Unlock()
@@ -130,7 +219,8 @@ func (j *Job) SetFinished(err error) {
 	j.Finished = &now
 	if err != nil {
 		j.Status = StatusFailed
-		j.Error = err.Error()
+		j.ErrorCode = CodeOf(err)
+		j.Error = models.ErrorMessage(j.ErrorCode)
 	} else {
 		j.Status = StatusSucceeded
 	}
@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/fedutinova/smartheart/back-api/config"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"", slog.LevelInfo, false},
+		{"debug", slog.LevelDebug, false},
+		{"WARN", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", slog.LevelInfo, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v, err=%v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNew_TextFormatAndLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(config.LogConfig{Level: "warn", Format: "text"}, &buf)
+
+	logger.Info("hidden")
+	logger.Warn("shown")
+
+	out := buf.String()
+	if strings.Contains(out, "hidden") {
+		t.Fatalf("info record should be filtered at warn level: %q", out)
+	}
+	if !strings.Contains(out, "level=WARN msg=shown") {
+		t.Fatalf("expected text output, got %q", out)
+	}
+}
+
+func TestMiddleware_LogsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(New(config.LogConfig{Format: "json"}, &buf))
+	defer slog.SetDefault(prev)
+
+	h := middleware.RequestID(Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slog.InfoContext(r.Context(), "in handler")
+		w.WriteHeader(http.StatusTeapot)
+	})))
+	req := httptest.NewRequest("GET", "/v1/ping", http.NoBody)
+	req.Header.Set(middleware.RequestIDHeader, "req-123")
+	h.ServeHTTP(httptest.NewRecorder(), req.WithContext(context.Background()))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records, got %d: %q", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid JSON record %q: %v", line, err)
+		}
+		if rec["request_id"] != "req-123" {
+			t.Errorf("record missing request_id: %q", line)
+		}
+	}
+	if !strings.Contains(lines[1], `"status":418`) {
+		t.Errorf("access record should carry the status: %q", lines[1])
+	}
+}
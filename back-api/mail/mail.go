@@ -1,6 +1,7 @@
 package mail
 
 import (
+	"context"
 	"fmt"
 	"mime"
 	"net/smtp"
@@ -9,6 +10,7 @@ import (
 	"github.com/fedutinova/smartheart/back-api/config"
 )
 
+// Sender is the SMTP Notifier.
 type Sender struct {
 	host    string
 	port    int
@@ -34,7 +36,9 @@ func NewSender(cfg config.SMTPConfig) *Sender {
 	}
 }
 
-func (s *Sender) Send(to, subject, htmlBody string) error {
+// SendEmail delivers an HTML email over SMTP. net/smtp has no context
+// support, so ctx is not honoured once the connection is made.
+func (s *Sender) SendEmail(_ context.Context, to, subject, htmlBody string) error {
 	addr := fmt.Sprintf("%s:%d", s.host, s.port)
 
 	var msg strings.Builder
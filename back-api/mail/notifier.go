@@ -0,0 +1,41 @@
+package mail
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/fedutinova/smartheart/back-api/config"
+)
+
+// Notifier delivers emails to users. Services depend on it rather than on
+// SMTP so delivery can be swapped per environment and stubbed in tests.
+type Notifier interface {
+	SendEmail(ctx context.Context, to, subject, htmlBody string) error
+}
+
+// NewNotifier returns the Notifier selected by cfg.Mode: SMTP delivery
+// (the default), log-only for development, or none.
+func NewNotifier(cfg config.SMTPConfig) Notifier {
+	switch cfg.Mode {
+	case config.MailModeLog:
+		return LogNotifier{}
+	case config.MailModeNone:
+		return NopNotifier{}
+	default:
+		return NewSender(cfg)
+	}
+}
+
+// LogNotifier writes emails to the log instead of sending them, so links in
+// verification and reset emails can be followed in development.
+type LogNotifier struct{}
+
+func (LogNotifier) SendEmail(ctx context.Context, to, subject, htmlBody string) error {
+	slog.InfoContext(ctx, "Email (not sent, MAIL_MODE=log)", "to", to, "subject", subject, "body", htmlBody)
+	return nil
+}
+
+// NopNotifier discards emails.
+type NopNotifier struct{}
+
+func (NopNotifier) SendEmail(context.Context, string, string, string) error { return nil }
@@ -0,0 +1,28 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/fedutinova/smartheart/back-api/config"
+)
+
+func TestNewNotifier_SelectsByMode(t *testing.T) {
+	tests := []struct {
+		mode string
+		want Notifier
+	}{
+		{config.MailModeLog, LogNotifier{}},
+		{config.MailModeNone, NopNotifier{}},
+	}
+	for _, tt := range tests {
+		if got := NewNotifier(config.SMTPConfig{Mode: tt.mode}); got != tt.want {
+			t.Errorf("mode %q: got %T, want %T", tt.mode, got, tt.want)
+		}
+	}
+
+	for _, mode := range []string{"", config.MailModeSMTP} {
+		if _, ok := NewNotifier(config.SMTPConfig{Mode: mode}).(*Sender); !ok {
+			t.Errorf("mode %q: expected SMTP sender", mode)
+		}
+	}
+}
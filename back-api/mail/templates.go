@@ -26,3 +26,25 @@ func PasswordResetEmail(resetLink string) string {
 </body>
 </html>`, safeLink)
 }
+
+func EmailVerificationEmail(verifyLink string) string {
+	safeLink := html.EscapeString(verifyLink)
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="ru">
+<head><meta charset="UTF-8"></head>
+<body style="font-family:Arial,sans-serif;max-width:600px;margin:0 auto;padding:20px;color:#333">
+  <h2 style="color:#2563eb">Умное сердце — Подтверждение email</h2>
+  <p>Спасибо за регистрацию! Подтвердите, что этот адрес принадлежит вам.</p>
+  <p>Нажмите на кнопку ниже. Ссылка действительна <strong>24 часа</strong>.</p>
+  <p style="text-align:center;margin:30px 0">
+    <a href="%s"
+       style="background:#2563eb;color:#fff;padding:12px 32px;text-decoration:none;border-radius:6px;font-size:16px;display:inline-block">
+       Подтвердить email
+    </a>
+  </p>
+  <p style="font-size:13px;color:#666">Если вы не регистрировались, просто проигнорируйте это письмо.</p>
+  <hr style="border:none;border-top:1px solid #eee;margin:30px 0">
+  <p style="font-size:12px;color:#999">Умное сердце — Анализ ЭКГ с помощью ИИ</p>
+</body>
+</html>`, safeLink)
+}
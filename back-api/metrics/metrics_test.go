@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestPoolCollector_ServesPoolMetrics(t *testing.T) {
+	// The pool connects lazily, so Stat works without a server.
+	pool, err := pgxpool.New(context.Background(), "postgres://app@127.0.0.1:1/smartheart?pool_max_conns=7")
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	defer pool.Close()
+
+	reg := NewRegistry()
+	reg.MustRegister(NewPoolCollector(pool))
+
+	w := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", http.NoBody))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"smartheart_db_pool_max_conns 7",
+		"smartheart_db_pool_acquired_conns 0",
+		"smartheart_db_pool_empty_acquires_total 0",
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+}
+
+type fixedWorkers int
+
+func (n fixedWorkers) Workers() int { return int(n) }
+
+func TestQueueWorkersCollector(t *testing.T) {
+	reg := NewRegistry()
+	reg.MustRegister(NewQueueWorkersCollector(fixedWorkers(6)))
+
+	w := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", http.NoBody))
+
+	if !strings.Contains(w.Body.String(), "smartheart_queue_workers 6") {
+		t.Errorf("metrics output missing worker gauge:\n%s", w.Body.String())
+	}
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit log actions.
+const (
+	AuditLogin          = "auth.login"
+	AuditLoginChallenge = "auth.login_challenge" // password accepted, 2FA code pending
+	AuditLoginFailed    = "auth.login_failed"
+	AuditLogout         = "auth.logout"
+	AuditRequestRead    = "request.read"
+	AuditAdminAccess    = "admin.access"
+)
+
+// AuditEntry records one security-relevant action.
+type AuditEntry struct {
+	ID        int64      `json:"id"`
+	ActorID   *uuid.UUID `json:"actor_id,omitempty"` // nil when the actor is unknown (failed login)
+	Action    string     `json:"action"`
+	Target    string     `json:"target,omitempty"` // affected resource, e.g. "request:<id>"
+	IP        string     `json:"ip,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
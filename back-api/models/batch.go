@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Batch status values. A batch is processing while any child is still
+// pending, queued or processing; partial means some children completed and some failed.
+// A batch with no children, because every image was rejected, is failed.
+const (
+	BatchStatusProcessing = "processing"
+	BatchStatusCompleted  = "completed"
+	BatchStatusFailed     = "failed"
+	BatchStatusPartial    = "partial"
+)
+
+// ECGBatch groups EKG requests submitted together, one child request per image.
+type ECGBatch struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Status and Counts aggregate the children; see Summarize.
+	Status   string         `json:"status"`
+	Counts   map[string]int `json:"counts"`
+	Requests []Request      `json:"requests"`
+}
+
+// Summarize fills Status and Counts from the child requests' statuses.
+func (b *ECGBatch) Summarize() {
+	b.Counts = make(map[string]int, 4)
+	for _, r := range b.Requests {
+		b.Counts[r.Status]++
+	}
+
+	completed, failed := b.Counts[StatusCompleted], b.Counts[StatusFailed]
+	switch {
+	case len(b.Requests) == 0:
+		b.Status = BatchStatusFailed
+	case completed+failed < len(b.Requests):
+		b.Status = BatchStatusProcessing
+	case failed == 0:
+		b.Status = BatchStatusCompleted
+	case completed == 0:
+		b.Status = BatchStatusFailed
+	default:
+		b.Status = BatchStatusPartial
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import "testing"
+
+func TestECGBatchSummarize(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []RequestStatus
+		want     string
+	}{
+		{"in flight", []RequestStatus{StatusCompleted, StatusProcessing}, BatchStatusProcessing},
+		{"pending", []RequestStatus{StatusPending}, BatchStatusProcessing},
+		{"all completed", []RequestStatus{StatusCompleted, StatusCompleted}, BatchStatusCompleted},
+		{"all failed", []RequestStatus{StatusFailed}, BatchStatusFailed},
+		{"mixed", []RequestStatus{StatusCompleted, StatusFailed}, BatchStatusPartial},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := ECGBatch{}
+			for _, s := range tt.statuses {
+				b.Requests = append(b.Requests, Request{Status: s})
+			}
+			b.Summarize()
+			if b.Status != tt.want {
+				t.Errorf("Status = %q, want %q", b.Status, tt.want)
+			}
+			if got := b.Counts[tt.statuses[0]]; got == 0 {
+				t.Errorf("Counts[%q] = 0, want > 0", tt.statuses[0])
+			}
+		})
+	}
+}
+
+func TestECGBatchSummarize_NoRequestsIsFailed(t *testing.T) {
+	b := ECGBatch{}
+	b.Summarize()
+	if b.Status != BatchStatusFailed {
+		t.Errorf("Status = %q, want %q", b.Status, BatchStatusFailed)
+	}
+}
@@ -22,6 +22,8 @@ type ECGResponseContent struct {
 	GPTInterpretation       *string              `json:"gpt_interpretation,omitempty"`
 	GPTFullResponse         *string              `json:"gpt_full_response,omitempty"`
 	StructuredResult        *ECGStructuredResult `json:"structured_result,omitempty"`
+	// ImageConfidence is the EKG plausibility score of the input image (0–1).
+	ImageConfidence *float64 `json:"image_confidence,omitempty"`
 }
 
 // Marshal serializes to JSON string suitable for Response.Content.
@@ -43,47 +45,107 @@ func ParseECGContent(content string) (*ECGResponseContent, error) {
 	return &ekg, nil
 }
 
-// ExtractConclusion extracts structured conclusion from GPT response.
-// Returns the full response if it's already structured with bullet points or numbered list.
+// ConclusionKeywords are heading words (lower-case) that open the conclusion
+// section of a free-form GPT response.
+var ConclusionKeywords = []string{
+	"заключение",
+	"выводы",
+	"вывод",
+	"итог",
+	"conclusions",
+	"conclusion",
+}
+
+// DisclaimerPhrases (lower-case) mark trailing disclaimer lines that are cut
+// from an extracted conclusion.
+var DisclaimerPhrases = []string{
+	"интерпретация носит информационный характер",
+	"носит ознакомительный характер",
+	"не является медицинским диагнозом",
+	"не является диагнозом",
+	"не заменяет консультацию",
+	"this is for informational purposes",
+	"for informational purposes only",
+	"not a medical diagnosis",
+	"does not replace",
+}
+
+// headingDecoration is stripped around heading lines: markdown headers,
+// bold/italic markers and trailing colons.
+const headingDecoration = "#*_ \t"
+
+// ExtractConclusion extracts the conclusion section from a free-form GPT
+// response. It looks for a heading line (any markdown level, optionally bold,
+// case-insensitive) matching ConclusionKeywords, either alone ("## Выводы") or
+// followed by inline text ("**Conclusion:** ..."), and drops trailing
+// disclaimers. Without a heading the trimmed response is returned unchanged.
 func ExtractConclusion(gptResponse string) string {
 	response := strings.TrimSpace(gptResponse)
+	lines := strings.Split(response, "\n")
+
+	for i, line := range lines {
+		inline, ok := matchConclusionHeading(line)
+		if !ok {
+			continue
+		}
+		body := lines[i+1:]
+		if inline != "" {
+			body = append([]string{inline}, body...)
+		}
+		conclusion := strings.TrimSpace(strings.Join(stripDisclaimer(body), "\n"))
+		if conclusion != "" {
+			return conclusion
+		}
+	}
+
+	return response
+}
 
-	// Check if response is already structured (starts with number or bullet point)
-	if strings.HasPrefix(response, "1.") || strings.HasPrefix(response, "•") ||
-		strings.HasPrefix(response, "-") || strings.HasPrefix(response, "*") {
-		return response
+// matchConclusionHeading reports whether line is a conclusion heading and
+// returns any text following it on the same line.
+func matchConclusionHeading(line string) (inline string, ok bool) {
+	text := strings.TrimLeft(line, headingDecoration)
+	text = strings.TrimLeft(stripListNumber(text), headingDecoration)
+	lower := strings.ToLower(text)
+	for _, kw := range ConclusionKeywords {
+		if !strings.HasPrefix(lower, kw) {
+			continue
+		}
+		rest := strings.TrimLeft(text[len(kw):], headingDecoration)
+		if rest == "" {
+			return "", true
+		}
+		if strings.HasPrefix(rest, ":") {
+			return strings.TrimSpace(strings.TrimLeft(rest[1:], headingDecoration)), true
+		}
+		// The keyword starts an ordinary sentence ("Заключение было...")
+		// or a longer word ("Итоговый"), not a heading.
 	}
+	return "", false
+}
 
-	// Try to find conclusion section
-	markers := []string{
-		"### Заключение\n",
-		"### Заключение",
-		"## Заключение\n",
-		"## Заключение",
-		"Заключение:\n",
-		"Заключение:",
-		"Заключение\n",
+// stripListNumber removes a leading "5." or "5)" so numbered headings match.
+func stripListNumber(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
 	}
+	if i > 0 && i < len(s) && (s[i] == '.' || s[i] == ')') {
+		return s[i+1:]
+	}
+	return s
+}
 
-	for _, marker := range markers {
-		idx := strings.Index(response, marker)
-		if idx != -1 {
-			conclusion := strings.TrimSpace(response[idx+len(marker):])
-			// Remove disclaimer at the end if present
-			disclaimers := []string{
-				"\n\nИнтерпретация носит информационный характер",
-				"\nИнтерпретация носит информационный характер",
-				"\n\nThis is for informational purposes",
-			}
-			for _, disclaimer := range disclaimers {
-				if discIdx := strings.Index(conclusion, disclaimer); discIdx != -1 {
-					conclusion = strings.TrimSpace(conclusion[:discIdx])
-				}
+// stripDisclaimer cuts lines from the first one containing a disclaimer
+// phrase onward, keeping at least the first line.
+func stripDisclaimer(lines []string) []string {
+	for i := 1; i < len(lines); i++ {
+		lower := strings.ToLower(lines[i])
+		for _, phrase := range DisclaimerPhrases {
+			if strings.Contains(lower, phrase) {
+				return lines[:i]
 			}
-			return strings.TrimSpace(conclusion)
 		}
 	}
-
-	// If no marker found, return full response (it should already be structured)
-	return response
+	return lines
 }
@@ -0,0 +1,35 @@
+package models
+
+// ErrorCode is the category of a failed job or request reported to clients.
+// Clients branch on it, so values must stay stable; the detailed error only
+// goes to the logs.
+type ErrorCode = string
+
+// Error codes of failed jobs and requests.
+const (
+	ErrorCodeRateLimited     ErrorCode = "rate_limited"
+	ErrorCodeContentFiltered ErrorCode = "content_filtered"
+	ErrorCodeAuth            ErrorCode = "auth"
+	ErrorCodeTimeout         ErrorCode = "timeout"
+	ErrorCodeInvalidImage    ErrorCode = "invalid_image"
+	ErrorCodeInternal        ErrorCode = "internal"
+)
+
+// errorMessages are the user-safe messages shown for each ErrorCode.
+var errorMessages = map[ErrorCode]string{
+	ErrorCodeRateLimited:     "The analysis service is busy or out of quota. Please try again later.",
+	ErrorCodeContentFiltered: "The analysis service declined to process this content.",
+	ErrorCodeAuth:            "The analysis service is misconfigured. Please contact support.",
+	ErrorCodeTimeout:         "The analysis took too long. Please try again.",
+	ErrorCodeInvalidImage:    "The image could not be read or is not an EKG.",
+	ErrorCodeInternal:        "The analysis failed due to an internal error.",
+}
+
+// ErrorMessage returns the user-safe message for code. Unknown codes get
+// the ErrorCodeInternal message.
+func ErrorMessage(code ErrorCode) string {
+	if msg, ok := errorMessages[code]; ok {
+		return msg
+	}
+	return errorMessages[ErrorCodeInternal]
+}
@@ -27,3 +27,42 @@ func TestExtractConclusion_NoMarkerReturnsTrimmed(t *testing.T) {
 		t.Fatalf("expected %q, got %q", exp, out)
 	}
 }
+
+func TestExtractConclusion_HeadingFormats(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"bold heading", "Ритм синусовый.\n\n**Заключение**\nНорма", "Норма"},
+		{"bold heading with colon inside", "Текст\n**Заключение:**\nНорма", "Норма"},
+		{"bold inline", "Текст\n**Заключение:** Норма", "Норма"},
+		{"vyvody colon", "Анализ...\nВыводы:\n- Ритм синусовый\n- ЧСС 72", "- Ритм синусовый\n- ЧСС 72"},
+		{"english heading", "Analysis...\n## Conclusion\nSinus rhythm", "Sinus rhythm"},
+		{"english inline lower-case", "Analysis...\nconclusion: sinus rhythm", "sinus rhythm"},
+		{"h4 heading", "#### Заключение\nНорма", "Норма"},
+		{"numbered heading", "1. Ритм: синусовый\n2. ЧСС: 72\n### 3. Итог\nНорма", "Норма"},
+		{"upper-case", "ЗАКЛЮЧЕНИЕ:\nНорма", "Норма"},
+		{"structured list wins when bold heading starts response", "**Заключение**\n1. Норма", "1. Норма"},
+		{"sentence is not a heading", "Заключение было сложным, но ритм синусовый.", "Заключение было сложным, но ритм синусовый."},
+		{"longer word is not a heading", "Итоговый результат: норма", "Итоговый результат: норма"},
+		{
+			"strips broader disclaimers",
+			"## Выводы\nНорма\n\n*Данная интерпретация не является медицинским диагнозом.*",
+			"Норма",
+		},
+		{
+			"strips english disclaimer",
+			"## Conclusion\nSinus rhythm\n\nThis is for informational purposes only.",
+			"Sinus rhythm",
+		},
+		{"empty section falls through", "## Заключение\n", "## Заключение"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractConclusion(tt.in); got != tt.want {
+				t.Fatalf("ExtractConclusion(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
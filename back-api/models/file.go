@@ -16,5 +16,7 @@ type File struct {
 	S3Bucket         string    `json:"s3_bucket,omitempty"`
 	S3Key            string    `json:"s3_key"`
 	S3URL            string    `json:"s3_url,omitempty"`
+	Checksum         string    `json:"checksum,omitempty"` // hex SHA-256 of the stored content
+	ThumbnailKey     string    `json:"thumbnail_key,omitempty"`
 	CreatedAt        time.Time `json:"created_at"`
 }
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// GPTStructuredResult is the JSON object GPT returns when a request is
+// submitted in structured mode. It is stored as-is in Response.Content.
+type GPTStructuredResult struct {
+	ImageQuality string         `json:"image_quality"`
+	Patterns     []string       `json:"patterns"`
+	Measurements map[string]any `json:"measurements"`
+	Conclusion   string         `json:"conclusion"`
+}
+
+// ParseGPTStructuredResult parses content as a GPTStructuredResult.
+// ok is false when content is not JSON or carries no conclusion, in which case
+// callers should fall back to ExtractConclusion.
+func ParseGPTStructuredResult(content string) (result *GPTStructuredResult, ok bool) {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "{") {
+		return nil, false
+	}
+	var r GPTStructuredResult
+	if err := json.Unmarshal([]byte(content), &r); err != nil {
+		return nil, false
+	}
+	if strings.TrimSpace(r.Conclusion) == "" {
+		return nil, false
+	}
+	return &r, true
+}
@@ -0,0 +1,29 @@
+package models
+
+import "testing"
+
+func TestParseGPTStructuredResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         string
+		ok         bool
+		conclusion string
+	}{
+		{"valid", `{"image_quality":"хорошее","patterns":["синусовый ритм"],"measurements":{"ЧСС":72},"conclusion":"Норма"}`, true, "Норма"},
+		{"leading whitespace", "\n {\"conclusion\":\"Итог\"}", true, "Итог"},
+		{"free text", "### Заключение\nНорма", false, ""},
+		{"invalid json", `{"conclusion":`, false, ""},
+		{"empty conclusion", `{"image_quality":"плохое","conclusion":"  "}`, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ok := ParseGPTStructuredResult(tt.in)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && r.Conclusion != tt.conclusion {
+				t.Fatalf("conclusion = %q, want %q", r.Conclusion, tt.conclusion)
+			}
+		})
+	}
+}
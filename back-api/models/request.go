@@ -12,16 +12,26 @@ type RequestStatus = string
 
 // Request status constants.
 const (
-	StatusPending    RequestStatus = "pending"
-	StatusProcessing RequestStatus = "processing"
+	StatusPending    RequestStatus = "pending"    // stored, job not enqueued yet
+	StatusQueued     RequestStatus = "queued"     // job waiting in the queue
+	StatusProcessing RequestStatus = "processing" // a worker picked the job up
 	StatusCompleted  RequestStatus = "completed"
 	StatusFailed     RequestStatus = "failed"
 )
 
+// RequestStatuses lists every request status.
+var RequestStatuses = []RequestStatus{StatusPending, StatusQueued, StatusProcessing, StatusCompleted, StatusFailed}
+
+// Callback delivery status values stored in requests.callback_status.
+const (
+	CallbackDelivered = "delivered"
+	CallbackFailed    = "failed"
+)
+
 // ValidRequestStatus reports whether s is a known request status.
 func ValidRequestStatus(s RequestStatus) bool {
 	switch s {
-	case StatusPending, StatusProcessing, StatusCompleted, StatusFailed:
+	case StatusPending, StatusQueued, StatusProcessing, StatusCompleted, StatusFailed:
 		return true
 	default:
 		return false
@@ -40,6 +50,29 @@ type Request struct {
 	Response   *Response          `json:"response,omitempty"`
 	ClientMeta *RequestClientMeta `json:"client_meta,omitempty"`
 
+	// Tags are client-defined labels (patient pseudonym, department),
+	// filterable in request lists; see ValidateTags.
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// ErrorCode categorizes why a failed request failed and Error is its
+	// user-safe message; both are nil unless Status is failed.
+	ErrorCode *ErrorCode `json:"error_code,omitempty"`
+	Error     *string    `json:"error,omitempty"`
+
+	// Completion webhook (nullable — only set when the client asked for one)
+	CallbackURL    *string `json:"callback_url,omitempty"`
+	CallbackStatus *string `json:"callback_status,omitempty"`
+
+	// BatchID links a request created by a batch submission to its ECGBatch.
+	BatchID *uuid.UUID `json:"batch_id,omitempty"`
+
+	// ParentRequestID links a GPT interpretation to the EKG request it was
+	// created for.
+	ParentRequestID *uuid.UUID `json:"parent_request_id,omitempty"`
+
+	// CorrelationID is the X-Request-ID of the HTTP call that created the request.
+	CorrelationID *string `json:"correlation_id,omitempty"`
+
 	// ECG analysis parameters (nullable — only set for EKG requests)
 	ECGAge           *int     `json:"ecg_age,omitempty"`
 	ECGSex           *string  `json:"ecg_sex,omitempty"`
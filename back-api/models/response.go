@@ -7,6 +7,11 @@ import (
 	"github.com/google/uuid"
 )
 
+// ModelRefused marks a response whose model refused the request even after a
+// rephrased retry. Content then holds a user-facing explanation instead of
+// the refusal text.
+const ModelRefused = "refused"
+
 // Response represents an AI response to a request.
 type Response struct {
 	ID                      uuid.UUID  `json:"id"`
@@ -21,6 +26,8 @@ type Response struct {
 	CacheVectorSimilarity   *float64   `json:"cache_vector_similarity,omitempty"`
 	CacheCombinedSimilarity *float64   `json:"cache_combined_similarity,omitempty"`
 	CacheMatchMethod        string     `json:"cache_match_method,omitempty"`
+	FinishReason            string     `json:"finish_reason,omitempty"` // provider's reason the model stopped, e.g. stop, length
+	IsRefusal               bool       `json:"is_refusal,omitempty"`    // the model refused; Content explains instead of answering
 	CreatedAt               time.Time  `json:"created_at"`
 }
 
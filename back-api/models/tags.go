@@ -0,0 +1,51 @@
+package models
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Request tag limits.
+const (
+	MaxRequestTags = 20  // tags per request
+	MaxTagKeyLen   = 64  // characters in a tag key
+	MaxTagValueLen = 256 // characters in a tag value
+)
+
+// ValidateTags checks request tags against the limits above. Keys are
+// non-empty and use only ASCII letters, digits, '_' and '-', so they can be
+// named in a ?tag.<key>= query parameter.
+func ValidateTags(tags map[string]string) error {
+	if len(tags) > MaxRequestTags {
+		return fmt.Errorf("at most %d tags allowed, got %d", MaxRequestTags, len(tags))
+	}
+	for k, v := range tags {
+		if err := ValidateTagKey(k); err != nil {
+			return err
+		}
+		if utf8.RuneCountInString(v) > MaxTagValueLen {
+			return fmt.Errorf("tag %q: value longer than %d characters", k, MaxTagValueLen)
+		}
+	}
+	return nil
+}
+
+// ValidateTagKey checks a single tag key; see ValidateTags.
+func ValidateTagKey(k string) error {
+	if k == "" {
+		return fmt.Errorf("tag key must not be empty")
+	}
+	if len(k) > MaxTagKeyLen {
+		return fmt.Errorf("tag key %q longer than %d characters", k, MaxTagKeyLen)
+	}
+	for _, c := range k {
+		if !isTagKeyChar(c) {
+			return fmt.Errorf("tag key %q: only letters, digits, '_' and '-' allowed", k)
+		}
+	}
+	return nil
+}
+
+func isTagKeyChar(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidateTags(t *testing.T) {
+	tooMany := make(map[string]string, MaxRequestTags+1)
+	for i := range MaxRequestTags + 1 {
+		tooMany[fmt.Sprintf("k%d", i)] = "v"
+	}
+
+	tests := []struct {
+		name    string
+		tags    map[string]string
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", map[string]string{"study": "AF-2026", "site_id": "кзн-1"}, false},
+		{"empty value", map[string]string{"flag": ""}, false},
+		{"empty key", map[string]string{"": "x"}, true},
+		{"key with space", map[string]string{"my key": "x"}, true},
+		{"key with dot", map[string]string{"a.b": "x"}, true},
+		{"long key", map[string]string{strings.Repeat("k", MaxTagKeyLen+1): "x"}, true},
+		{"long value", map[string]string{"k": strings.Repeat("я", MaxTagValueLen+1)}, true},
+		{"max value", map[string]string{"k": strings.Repeat("я", MaxTagValueLen)}, false},
+		{"too many", tooMany, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTags(tt.tags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateTags(%v) error = %v, wantErr %v", tt.tags, err, tt.wantErr)
+			}
+		})
+	}
+}
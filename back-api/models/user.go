@@ -16,6 +16,9 @@ type User struct {
 	UpdatedAt             time.Time  `json:"updated_at"                        db:"updated_at"`
 	Roles                 []Role     `json:"roles,omitempty"`
 	SubscriptionExpiresAt *time.Time `json:"subscription_expires_at,omitempty" db:"subscription_expires_at"`
+	TwoFactorSecret       string     `json:"-"                                 db:"two_factor_secret"` // encrypted TOTP secret, empty until enrollment
+	TwoFactorEnabled      bool       `json:"two_factor_enabled"                db:"two_factor_enabled"`
+	EmailVerified         bool       `json:"email_verified"                    db:"email_verified"`
 }
 
 // Role represents a user role.
@@ -46,6 +49,16 @@ type PasswordResetToken struct {
 	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
 }
 
+// EmailVerificationToken is a single-use token mailed at registration.
+type EmailVerificationToken struct {
+	ID        uuid.UUID  `json:"id"                db:"id"`
+	UserID    uuid.UUID  `json:"user_id"           db:"user_id"`
+	TokenHash string     `json:"-"                 db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at"        db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"        db:"created_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+}
+
 // RefreshToken represents a refresh token for JWT authentication.
 type RefreshToken struct {
 	ID        uuid.UUID  `json:"id"                   db:"id"`
@@ -0,0 +1,139 @@
+package queue
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fedutinova/smartheart/back-api/job"
+)
+
+const (
+	autoscaleInterval   = 5 * time.Second // how often the shared pool size is reconsidered
+	autoscaleDownChecks = 6               // consecutive low-demand checks before a worker is retired
+)
+
+// workerPool runs the shared workers of a queue and can be resized while
+// running. A retired worker finishes the job it is processing, then exits.
+type workerPool struct {
+	mu sync.Mutex
+	// start launches one worker goroutine that runs until stop is closed.
+	start func(stop <-chan struct{}, name string)
+	stops []chan struct{} // stops[i] belongs to worker i+1
+}
+
+func newWorkerPool(start func(stop <-chan struct{}, name string)) *workerPool {
+	return &workerPool{start: start}
+}
+
+// size returns the number of running workers, not counting retired ones
+// still finishing a job.
+func (p *workerPool) size() int {
+	if p == nil {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.stops)
+}
+
+// resize starts or retires workers until n are running. The newest workers
+// are retired first, so worker names stay within 1..n.
+func (p *workerPool) resize(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.stops) < n {
+		stop := make(chan struct{})
+		p.stops = append(p.stops, stop)
+		p.start(stop, strconv.Itoa(len(p.stops)))
+	}
+	for len(p.stops) > n {
+		last := len(p.stops) - 1
+		close(p.stops[last])
+		p.stops = p.stops[:last]
+	}
+}
+
+// autoscaler sizes a workerPool after the demand on it: the jobs queued for
+// or running on the shared workers. The pool grows to the demand at once,
+// but shrinks by one worker only after demand stayed below its size for
+// downChecks consecutive checks, so short lulls do not cause flapping.
+type autoscaler struct {
+	pool       *workerPool
+	min, max   int
+	demand     func(ctx context.Context) (int, error)
+	interval   time.Duration
+	downChecks int
+
+	low int // consecutive checks with demand below the pool size
+}
+
+func newAutoscaler(pool *workerPool, minWorkers, maxWorkers int, demand func(context.Context) (int, error)) *autoscaler {
+	return &autoscaler{
+		pool:       pool,
+		min:        minWorkers,
+		max:        maxWorkers,
+		demand:     demand,
+		interval:   autoscaleInterval,
+		downChecks: autoscaleDownChecks,
+	}
+}
+
+// run checks the demand every interval until ctx is done or closing is
+// closed. closing may be nil.
+func (a *autoscaler) run(ctx context.Context, closing <-chan struct{}) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-closing:
+			return
+		case <-ticker.C:
+			a.check(ctx)
+		}
+	}
+}
+
+// check resizes the pool once. When the demand cannot be read the pool
+// keeps its size.
+func (a *autoscaler) check(ctx context.Context) {
+	demand, err := a.demand(ctx)
+	if err != nil {
+		slog.DebugContext(ctx, "Skipping worker autoscaling, queue stats unavailable", "error", err)
+		return
+	}
+	want := min(max(demand, a.min), a.max)
+	current := a.pool.size()
+
+	switch {
+	case want > current:
+		a.low = 0
+		a.pool.resize(want)
+		slog.InfoContext(ctx, "Scaled up queue workers", "from", current, "to", want, "demand", demand)
+	case want < current:
+		a.low++
+		if a.low < a.downChecks {
+			return
+		}
+		a.low = 0
+		a.pool.resize(current - 1)
+		slog.InfoContext(ctx, "Scaled down queue workers", "from", current, "to", current-1, "demand", demand)
+	default:
+		a.low = 0
+	}
+}
+
+// sharedDemand returns the jobs in stats that are queued for or running on
+// the shared workers, i.e. all except those of types with a dedicated pool.
+func sharedDemand(stats job.QueueStats, dedicated map[job.Type]int) int {
+	n := stats.Queued + stats.Running
+	for t := range dedicated {
+		s := stats.ByType[t]
+		n -= s.Queued + s.Running
+	}
+	return max(n, 0)
+}
@@ -0,0 +1,160 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fedutinova/smartheart/back-api/job"
+)
+
+// countingPool returns a pool whose workers only record that they started.
+func countingPool(started *[]string) *workerPool {
+	return newWorkerPool(func(_ <-chan struct{}, name string) {
+		*started = append(*started, name)
+	})
+}
+
+func TestWorkerPool_Resize(t *testing.T) {
+	var started []string
+	p := countingPool(&started)
+
+	p.resize(3)
+	if p.size() != 3 || len(started) != 3 {
+		t.Fatalf("expected 3 workers, size %d, started %v", p.size(), started)
+	}
+	last := p.stops[2]
+
+	p.resize(2)
+	if p.size() != 2 {
+		t.Fatalf("expected 2 workers after shrinking, got %d", p.size())
+	}
+	select {
+	case <-last:
+	default:
+		t.Error("expected the newest worker to be stopped")
+	}
+
+	p.resize(4)
+	if got := strings.Join(started, ","); got != "1,2,3,3,4" {
+		t.Errorf("expected freed names to be reused, started %s", got)
+	}
+}
+
+func TestAutoscaler_ScalesUpAtOnceAndDownGradually(t *testing.T) {
+	var started []string
+	p := countingPool(&started)
+	p.resize(2)
+
+	demand := 0
+	a := newAutoscaler(p, 2, 5, func(context.Context) (int, error) { return demand, nil })
+	a.downChecks = 3
+	ctx := context.Background()
+
+	demand = 9
+	a.check(ctx)
+	if p.size() != 5 {
+		t.Fatalf("expected growth capped at max 5, got %d", p.size())
+	}
+
+	demand = 0
+	for i := range a.downChecks - 1 {
+		a.check(ctx)
+		if p.size() != 5 {
+			t.Fatalf("check %d: shrank before %d low checks, size %d", i+1, a.downChecks, p.size())
+		}
+	}
+	a.check(ctx)
+	if p.size() != 4 {
+		t.Fatalf("expected one worker retired after %d low checks, got %d", a.downChecks, p.size())
+	}
+
+	// A busy check resets the countdown.
+	demand = 4
+	a.check(ctx)
+	demand = 0
+	a.check(ctx)
+	a.check(ctx)
+	if p.size() != 4 {
+		t.Errorf("expected the countdown to restart after demand returned, got %d", p.size())
+	}
+
+	for range 20 {
+		a.check(ctx)
+	}
+	if p.size() != 2 {
+		t.Errorf("expected the pool to settle at min 2, got %d", p.size())
+	}
+}
+
+func TestAutoscaler_KeepsSizeWhenStatsFail(t *testing.T) {
+	var started []string
+	p := countingPool(&started)
+	p.resize(3)
+	a := newAutoscaler(p, 1, 5, func(context.Context) (int, error) { return 0, errors.New("redis down") })
+	a.downChecks = 1
+
+	a.check(context.Background())
+	if p.size() != 3 {
+		t.Errorf("expected size unchanged on stats error, got %d", p.size())
+	}
+}
+
+func TestSharedDemand_ExcludesDedicatedTypes(t *testing.T) {
+	stats := job.QueueStats{
+		Queued:  7,
+		Running: 3,
+		ByType: map[job.Type]job.TypeStats{
+			job.TypeGPTProcess: {Queued: 4, Running: 1},
+			job.TypeECGAnalyze: {Queued: 3, Running: 2},
+		},
+	}
+	if got := sharedDemand(stats, map[job.Type]int{job.TypeGPTProcess: 2}); got != 5 {
+		t.Errorf("sharedDemand = %d, want 5", got)
+	}
+	if got := sharedDemand(stats, nil); got != 10 {
+		t.Errorf("sharedDemand without dedicated pools = %d, want 10", got)
+	}
+}
+
+func TestMemoryQueue_RetiredWorkerFinishesItsJob(t *testing.T) {
+	q := NewMemoryQueue(10, 5*time.Second).(*memQueue)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	running := make(chan struct{})
+	release := make(chan struct{})
+	q.StartConsumers(ctx, 1, func(_ context.Context, j *job.Job) error {
+		if string(j.Payload) == `"block"` {
+			close(running)
+			<-release
+		}
+		return nil
+	})
+
+	id, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`"block"`)})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	<-running
+	q.pool.resize(0)
+	if q.Workers() != 0 {
+		t.Fatalf("expected no workers after retiring, got %d", q.Workers())
+	}
+	close(release)
+
+	if j := waitFinished(t, q, id); j.Status != job.StatusSucceeded {
+		t.Fatalf("expected the in-flight job to finish, got %s", j.Status)
+	}
+
+	next, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{}`)})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if j, _ := q.Status(ctx, next); j.Status != job.StatusQueued {
+		t.Errorf("expected the retired worker to take no new jobs, status %s", j.Status)
+	}
+}
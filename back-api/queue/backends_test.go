@@ -0,0 +1,99 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fedutinova/smartheart/back-api/job"
+)
+
+// backends returns one queue of every implementation, so behavior that must
+// not depend on the backend can be asserted for both.
+func backends(t *testing.T) map[string]job.Queue {
+	t.Helper()
+	client := getTestRedisClient(t)
+	t.Cleanup(func() { _ = client.Close() })
+	streamName := "test:jobs:backends:" + uuid.New().String()[:8]
+	t.Cleanup(func() { client.Del(context.Background(), streamName) })
+
+	redisQueue, err := NewRedisQueue(client, RedisQueueConfig{
+		Stream:        streamName,
+		Group:         "test-workers",
+		MaxJobTime:    5 * time.Second,
+		ClaimInterval: time.Hour,
+		ClaimTimeout:  time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create Redis queue: %v", err)
+	}
+	t.Cleanup(func() { _ = redisQueue.Close() })
+
+	return map[string]job.Queue{
+		"memory": NewMemoryQueue(10, 5*time.Second),
+		"redis":  redisQueue,
+	}
+}
+
+// waitFinished polls Status until the job has succeeded or failed.
+func waitFinished(t *testing.T, q job.Queue, id uuid.UUID) *job.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if j, ok := q.Status(context.Background(), id); ok &&
+			(j.Status == job.StatusSucceeded || j.Status == job.StatusFailed) {
+			return j
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return nil
+}
+
+func TestBackends_RecordJobTimestamps(t *testing.T) {
+	for name, q := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			q.StartConsumers(ctx, 1, func(_ context.Context, j *job.Job) error {
+				time.Sleep(5 * time.Millisecond) // make Started and Finished differ
+				if string(j.Payload) == `{"fail":true}` {
+					return errors.New("boom")
+				}
+				return nil
+			})
+
+			before := time.Now()
+			okID, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{}`)})
+			if err != nil {
+				t.Fatalf("Enqueue: %v", err)
+			}
+			failID, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{"fail":true}`)})
+			if err != nil {
+				t.Fatalf("Enqueue: %v", err)
+			}
+
+			for id, want := range map[uuid.UUID]job.Status{okID: job.StatusSucceeded, failID: job.StatusFailed} {
+				j := waitFinished(t, q, id)
+				if j.Status != want {
+					t.Fatalf("job %s: status %s, want %s", id, j.Status, want)
+				}
+				if j.Enqueued.Before(before) {
+					t.Errorf("job %s: enqueued_at %v not set at enqueue", id, j.Enqueued)
+				}
+				if j.Started == nil || j.Finished == nil {
+					t.Fatalf("job %s: expected started and finished timestamps, got %v / %v", id, j.Started, j.Finished)
+				}
+				if j.Started.Before(j.Enqueued) {
+					t.Errorf("job %s: started %v before enqueued %v", id, j.Started, j.Enqueued)
+				}
+				if j.Finished.Before(*j.Started) {
+					t.Errorf("job %s: finished %v before started %v", id, j.Finished, j.Started)
+				}
+			}
+		})
+	}
+}
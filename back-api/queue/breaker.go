@@ -0,0 +1,86 @@
+package queue
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fedutinova/smartheart/back-api/job"
+)
+
+const (
+	breakerThreshold  = 3           // consecutive failures before the connection counts as down
+	breakerBaseDelay  = time.Second // first backoff after a failure
+	breakerMaxBackoff = 10 * time.Second
+)
+
+// breaker tracks the health of the Redis connection shared by the queue's
+// goroutines. Consecutive failures grow a shared backoff so that consumers,
+// the claimer and the scheduler wait instead of looping on errors; after
+// breakerThreshold of them the connection is reported down until the next
+// successful command.
+type breaker struct {
+	mu        sync.Mutex
+	failures  int
+	lastErr   error
+	downSince time.Time
+	now       func() time.Time
+}
+
+func newBreaker() *breaker {
+	return &breaker{now: time.Now}
+}
+
+// success records a successful Redis command and reports whether it ended
+// an outage.
+func (b *breaker) success() (recovered bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	recovered = !b.downSince.IsZero()
+	b.failures = 0
+	b.lastErr = nil
+	b.downSince = time.Time{}
+	return recovered
+}
+
+// failure records a failed Redis command. It returns how long the caller
+// should back off and whether this failure started an outage.
+func (b *breaker) failure(err error) (backoff time.Duration, opened bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.lastErr = err
+	if b.failures >= breakerThreshold && b.downSince.IsZero() {
+		b.downSince = b.now()
+		opened = true
+	}
+	return backoffFor(b.failures), opened
+}
+
+// open reports whether the connection is currently considered down.
+func (b *breaker) open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.downSince.IsZero()
+}
+
+// err describes the outage, or returns nil while the connection is up.
+func (b *breaker) err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.downSince.IsZero() {
+		return nil
+	}
+	return fmt.Errorf("%w: redis down since %s: %w", job.ErrQueueUnavailable,
+		b.downSince.UTC().Format(time.RFC3339), b.lastErr)
+}
+
+// backoffFor doubles breakerBaseDelay per consecutive failure, capped at
+// breakerMaxBackoff.
+func backoffFor(failures int) time.Duration {
+	d := breakerBaseDelay
+	for i := 1; i < failures && d < breakerMaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, breakerMaxBackoff)
+}
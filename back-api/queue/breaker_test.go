@@ -0,0 +1,60 @@
+package queue
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fedutinova/smartheart/back-api/job"
+)
+
+func TestBreaker_OpensAfterThresholdAndRecovers(t *testing.T) {
+	b := newBreaker()
+	refused := errors.New("connection refused")
+
+	for i := 1; i < breakerThreshold; i++ {
+		if _, opened := b.failure(refused); opened {
+			t.Fatalf("breaker opened after %d failures", i)
+		}
+	}
+	if b.open() || b.err() != nil {
+		t.Fatal("breaker should stay closed below the threshold")
+	}
+
+	if _, opened := b.failure(refused); !opened {
+		t.Fatal("expected the threshold failure to open the breaker")
+	}
+	if _, opened := b.failure(refused); opened {
+		t.Fatal("a breaker that is already open should not report opening again")
+	}
+	if err := b.err(); !errors.Is(err, job.ErrQueueUnavailable) || !errors.Is(err, refused) {
+		t.Fatalf("expected ErrQueueUnavailable wrapping the last error, got %v", err)
+	}
+
+	if !b.success() {
+		t.Fatal("expected success to report recovery")
+	}
+	if b.open() || b.err() != nil {
+		t.Fatal("breaker should be closed after a success")
+	}
+	if b.success() {
+		t.Fatal("success without an outage should not report recovery")
+	}
+}
+
+func TestBackoffFor(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, breakerBaseDelay},
+		{2, 2 * breakerBaseDelay},
+		{3, 4 * breakerBaseDelay},
+		{50, breakerMaxBackoff},
+	}
+	for _, tt := range tests {
+		if got := backoffFor(tt.failures); got != tt.want {
+			t.Errorf("backoffFor(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
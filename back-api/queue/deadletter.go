@@ -0,0 +1,130 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/fedutinova/smartheart/back-api/job"
+)
+
+const (
+	deadLetterBatchSize = 100  // dead letters read per XRANGE call
+	maxDeadLetterReplay = 1000 // default and largest DeadLetterFilter.Limit
+)
+
+// DeadLetterFilter selects the dead letters RetryAllDeadLetter requeues.
+// Zero fields match everything.
+type DeadLetterFilter struct {
+	// Type matches jobs of this type only.
+	Type job.Type
+	// Reason matches dead letters whose reason contains it, e.g.
+	// "exceeded max retries".
+	Reason string
+	// Limit caps how many dead letters one call requeues. Zero or values
+	// above 1000 mean 1000.
+	Limit int
+}
+
+func (f DeadLetterFilter) matches(t job.Type, reason string) bool {
+	if f.Type != "" && t != f.Type {
+		return false
+	}
+	return f.Reason == "" || strings.Contains(reason, f.Reason)
+}
+
+// deadLetterStream is the stream failed jobs are moved to.
+func (q *RedisQueue) deadLetterStream() string {
+	return q.stream + ":deadletter"
+}
+
+// RetryAllDeadLetter moves the dead letters matching filter back to their
+// job streams, oldest first, and returns how many it moved. Each entry is
+// deleted only after it was re-added, so an error part way leaves the
+// remaining entries in the dead letter stream; n counts those already moved.
+// Entries that cannot be decoded are skipped and kept.
+func (q *RedisQueue) RetryAllDeadLetter(ctx context.Context, filter DeadLetterFilter) (int, error) {
+	limit := filter.Limit
+	if limit <= 0 || limit > maxDeadLetterReplay {
+		limit = maxDeadLetterReplay
+	}
+	dlStream := q.deadLetterStream()
+
+	n, scanned := 0, 0
+	start := "-"
+	for n < limit {
+		msgs, err := q.client.XRangeN(ctx, dlStream, start, "+", deadLetterBatchSize).Result()
+		if err != nil {
+			return n, fmt.Errorf("failed to read dead letters: %w", err)
+		}
+		for _, msg := range msgs {
+			if n >= limit {
+				break
+			}
+			scanned++
+			j, data, err := decodeDeadLetter(msg)
+			if err != nil {
+				slog.WarnContext(ctx, "Skipping undecodable dead letter", "message_id", msg.ID, "error", err)
+				continue
+			}
+			reason, _ := msg.Values["reason"].(string)
+			if !filter.matches(j.Type, reason) {
+				continue
+			}
+			if err := q.requeueDeadLetter(ctx, msg, j.Type, data); err != nil {
+				return n, err
+			}
+			n++
+		}
+		slog.InfoContext(ctx, "Replaying dead letters", "retried", n, "scanned", scanned, "type", filter.Type)
+		if len(msgs) < deadLetterBatchSize {
+			break
+		}
+		// Exclusive start: skipped entries stay in the stream.
+		start = "(" + msgs[len(msgs)-1].ID
+	}
+
+	slog.InfoContext(ctx, "Dead letter replay finished",
+		"retried", n, "scanned", scanned, "type", filter.Type, "reason", filter.Reason, "limit", limit)
+	return n, nil
+}
+
+// decodeDeadLetter returns the job stored in a dead letter entry along with
+// its raw data.
+func decodeDeadLetter(msg redis.XMessage) (*job.Job, string, error) {
+	data, ok := msg.Values["data"].(string)
+	if !ok {
+		return nil, "", errors.New("invalid message format")
+	}
+	var j job.Job
+	if err := json.Unmarshal([]byte(data), &j); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal dead letter job: %w", err)
+	}
+	return &j, data, nil
+}
+
+// requeueDeadLetter re-adds a dead letter to the stream of its job type and
+// then deletes it. A failed delete is only logged: the job is already back
+// in the queue, and the leftover entry can be replayed again.
+func (q *RedisQueue) requeueDeadLetter(ctx context.Context, msg redis.XMessage, t job.Type, data string) error {
+	_, err := q.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: q.streamFor(t),
+		Values: map[string]any{
+			"id":   msg.Values["original_id"],
+			"data": data,
+		},
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to re-add job: %w", err)
+	}
+
+	if err := q.client.XDel(ctx, q.deadLetterStream(), msg.ID).Err(); err != nil {
+		slog.WarnContext(ctx, "Failed to delete from dead letter", "message_id", msg.ID, "error", err)
+	}
+	return nil
+}
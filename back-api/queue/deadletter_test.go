@@ -0,0 +1,127 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/fedutinova/smartheart/back-api/job"
+)
+
+// addDeadLetter writes a dead letter entry for a job of type t.
+func addDeadLetter(t *testing.T, client *redis.Client, stream string, typ job.Type, reason string) {
+	t.Helper()
+	data, err := json.Marshal(&job.Job{ID: uuid.New(), Type: typ})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := client.XAdd(context.Background(), &redis.XAddArgs{
+		Stream: stream + ":deadletter",
+		Values: map[string]any{"original_id": "0-1", "data": string(data), "reason": reason},
+	}).Err(); err != nil {
+		t.Fatalf("XAdd: %v", err)
+	}
+}
+
+func TestRedisQueue_RetryAllDeadLetterFiltersByType(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+	q, stream := deadLetterQueue(t, client)
+	ctx := context.Background()
+
+	for range 3 {
+		addDeadLetter(t, client, stream, job.TypeGPTProcess, "exceeded max retries: 4")
+		addDeadLetter(t, client, stream, job.TypeECGAnalyze, "exceeded max retries: 4")
+	}
+
+	n, err := q.RetryAllDeadLetter(ctx, DeadLetterFilter{Type: job.TypeGPTProcess})
+	if err != nil {
+		t.Fatalf("RetryAllDeadLetter: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 jobs retried, got %d", n)
+	}
+	if left, _ := q.GetDeadLetterCount(ctx); left != 3 {
+		t.Errorf("expected the 3 EKG dead letters to stay, got %d", left)
+	}
+	msgs, err := client.XRange(ctx, stream, "-", "+").Result()
+	if err != nil {
+		t.Fatalf("XRange: %v", err)
+	}
+	if len(msgs) != 3 {
+		t.Fatalf("expected 3 requeued jobs, got %d", len(msgs))
+	}
+	for _, msg := range msgs {
+		j, _, err := decodeDeadLetter(msg)
+		if err != nil {
+			t.Fatalf("decode requeued job: %v", err)
+		}
+		if j.Type != job.TypeGPTProcess {
+			t.Errorf("expected a %s job requeued, got %s", job.TypeGPTProcess, j.Type)
+		}
+	}
+}
+
+func TestRedisQueue_RetryAllDeadLetterLimitAndReason(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+	q, stream := deadLetterQueue(t, client)
+	ctx := context.Background()
+
+	// More entries than one XRANGE batch, so the scan has to page.
+	for range deadLetterBatchSize + 10 {
+		addDeadLetter(t, client, stream, job.TypeGPTProcess, "manual")
+	}
+	for range 5 {
+		addDeadLetter(t, client, stream, job.TypeGPTProcess, "exceeded max retries: 4")
+	}
+
+	n, err := q.RetryAllDeadLetter(ctx, DeadLetterFilter{Reason: "max retries", Limit: 3})
+	if err != nil {
+		t.Fatalf("RetryAllDeadLetter: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected the limit of 3 to apply, got %d", n)
+	}
+	if left, _ := q.GetDeadLetterCount(ctx); left != int64(deadLetterBatchSize+10+2) {
+		t.Errorf("expected %d dead letters left, got %d", deadLetterBatchSize+12, left)
+	}
+
+	n, err = q.RetryAllDeadLetter(ctx, DeadLetterFilter{Reason: "max retries"})
+	if err != nil {
+		t.Fatalf("RetryAllDeadLetter: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected the remaining 2 matches, got %d", n)
+	}
+}
+
+func TestRedisQueue_RetryAllDeadLetterKeepsEntriesOnFailure(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+	q, stream := deadLetterQueue(t, client)
+	ctx := context.Background()
+
+	for range 3 {
+		addDeadLetter(t, client, stream, job.TypeGPTProcess, "test")
+	}
+	// A non-stream key under the job stream's name makes every XADD fail.
+	client.Del(ctx, stream)
+	if err := client.Set(ctx, stream, "blocked", 0).Err(); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	n, err := q.RetryAllDeadLetter(ctx, DeadLetterFilter{})
+	if err == nil {
+		t.Fatal("expected an error when the job stream cannot be written")
+	}
+	if n != 0 {
+		t.Errorf("expected nothing retried, got %d", n)
+	}
+	if left, _ := q.GetDeadLetterCount(ctx); left != 3 {
+		t.Errorf("expected all 3 dead letters kept, got %d", left)
+	}
+}
@@ -2,7 +2,10 @@ package queue
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,18 +13,83 @@ import (
 	"github.com/fedutinova/smartheart/back-api/job"
 )
 
+// errQueueClosed fails scheduled jobs that come due after Close.
+var errQueueClosed = errors.New("queue closed")
+
 type memQueue struct {
 	buf     chan *job.Job
 	maxWait time.Duration
 	cache   *job.Cache
+
+	// typed holds a separate channel for each job type with its own worker
+	// pool; jobs of other types go to buf.
+	typed       map[job.Type]chan *job.Job
+	typeWorkers map[job.Type]int
+
+	// maxWorkers is the ceiling the shared pool may grow to; 0 keeps it at
+	// the size passed to StartConsumers.
+	maxWorkers int
+	pool       *workerPool
+
+	statsMu sync.Mutex
+	stats   map[job.Type]*job.TypeStats
+
+	// closing is closed by Close so scheduled jobs that come due afterwards
+	// are dropped instead of blocking their timer on a buffer nobody drains.
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+// MemoryOption configures the in-memory queue.
+type MemoryOption func(*memQueue)
+
+// WithTypeWorkers gives each listed job type a dedicated pool of that many
+// workers. Those types never use the shared workers passed to
+// StartConsumers, so a burst of one type cannot starve the others.
+func WithTypeWorkers(limits map[job.Type]int) MemoryOption {
+	return func(q *memQueue) {
+		for t, n := range limits {
+			if n > 0 {
+				q.typeWorkers[t] = n
+			}
+		}
+	}
+}
+
+// WithMaxWorkers lets the shared pool grow from the size passed to
+// StartConsumers up to n workers while jobs are backing up, and shrink back
+// once demand drops. n at or below the starting size disables scaling.
+func WithMaxWorkers(n int) MemoryOption {
+	return func(q *memQueue) {
+		q.maxWorkers = n
+	}
 }
 
-func NewMemoryQueue(buffer int, maxJobDuration time.Duration) job.Queue {
-	return &memQueue{
-		buf:     make(chan *job.Job, buffer),
-		maxWait: maxJobDuration,
-		cache:   job.NewCache(buffer).WithMaxSize(buffer * 10),
+func NewMemoryQueue(buffer int, maxJobDuration time.Duration, opts ...MemoryOption) job.Queue {
+	q := &memQueue{
+		buf:         make(chan *job.Job, buffer),
+		maxWait:     maxJobDuration,
+		cache:       job.NewCache(buffer).WithMaxSize(buffer * 10),
+		typed:       make(map[job.Type]chan *job.Job),
+		typeWorkers: make(map[job.Type]int),
+		stats:       make(map[job.Type]*job.TypeStats),
+		closing:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	for t := range q.typeWorkers {
+		q.typed[t] = make(chan *job.Job, buffer)
 	}
+	return q
+}
+
+// chanFor returns the channel that jobs of type t are queued on.
+func (q *memQueue) chanFor(t job.Type) chan *job.Job {
+	if ch, ok := q.typed[t]; ok {
+		return ch
+	}
+	return q.buf
 }
 
 func (q *memQueue) Enqueue(ctx context.Context, j *job.Job) (uuid.UUID, error) {
@@ -30,44 +98,103 @@ func (q *memQueue) Enqueue(ctx context.Context, j *job.Job) (uuid.UUID, error) {
 	}
 	j.Status = job.StatusQueued
 	j.Enqueued = time.Now()
+	j.InjectTraceContext(ctx)
 
+	// Count before the send so a fast worker never sees a negative queue.
+	q.track(j.Type, func(s *job.TypeStats) { s.Queued++ })
 	select {
-	case q.buf <- j:
+	case q.chanFor(j.Type) <- j:
 		q.cache.Put(j)
 		return j.ID, nil
 	case <-ctx.Done():
+		q.track(j.Type, func(s *job.TypeStats) { s.Queued-- })
 		return uuid.Nil, ctx.Err()
 	}
 }
 
+// TryEnqueue is the non-blocking variant of Enqueue: it fails fast with
+// job.ErrQueueFull instead of waiting for buffer space. A rejected job is
+// left as the caller passed it. The fields are set before the send because
+// a worker may pick the job up as soon as it is in the buffer, so they are
+// restored on failure instead.
+func (q *memQueue) TryEnqueue(ctx context.Context, j *job.Job) (uuid.UUID, error) {
+	id, status, enqueued := j.ID, j.Status, j.Enqueued
+	traceContext, correlationID := j.TraceContext, j.CorrelationID
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	j.Status = job.StatusQueued
+	j.Enqueued = time.Now()
+	j.InjectTraceContext(ctx)
+
+	q.track(j.Type, func(s *job.TypeStats) { s.Queued++ })
+	select {
+	case q.chanFor(j.Type) <- j:
+		q.cache.Put(j)
+		return j.ID, nil
+	default:
+		q.track(j.Type, func(s *job.TypeStats) { s.Queued-- })
+		j.ID, j.Status, j.Enqueued = id, status, enqueued
+		j.TraceContext, j.CorrelationID = traceContext, correlationID
+		return uuid.Nil, job.ErrQueueFull
+	}
+}
+
+// EnqueueAt holds the job in a timer until when. Scheduled jobs live only in
+// process memory and are lost on restart; one that comes due after Close is
+// logged and marked failed.
+func (q *memQueue) EnqueueAt(ctx context.Context, j *job.Job, when time.Time) (uuid.UUID, error) {
+	delay := time.Until(when)
+	if delay <= 0 {
+		return q.Enqueue(ctx, j)
+	}
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	j.Status = job.StatusScheduled
+	j.Enqueued = time.Now()
+	j.ScheduledAt = &when
+	j.InjectTraceContext(ctx)
+	q.cache.Put(j)
+	q.track(j.Type, func(s *job.TypeStats) { s.Scheduled++ })
+
+	time.AfterFunc(delay, func() {
+		j.SetQueued()
+		q.track(j.Type, func(s *job.TypeStats) { s.Scheduled--; s.Queued++ })
+		select {
+		case q.chanFor(j.Type) <- j:
+		case <-q.closing:
+			q.track(j.Type, func(s *job.TypeStats) { s.Queued-- })
+			j.SetFinished(errQueueClosed)
+			slog.Warn("Dropped scheduled job, queue closed", "id", j.ID, "type", j.Type)
+		}
+	})
+	return j.ID, nil
+}
+
 func (q *memQueue) Status(_ context.Context, id uuid.UUID) (*job.Job, bool) {
 	return q.cache.Get(id)
 }
 
+// StartConsumers starts n shared workers plus the dedicated pool of every
+// type configured with WithTypeWorkers. With WithMaxWorkers the shared
+// pool is autoscaled between n and the configured maximum.
 func (q *memQueue) StartConsumers(ctx context.Context, n int, handler job.Handler) {
-	for i := 0; i < n; i++ {
-		go func(workerID int) {
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				case j := <-q.buf:
-					j.SetRunning()
-
-					runCtx, cancel := context.WithTimeout(ctx, q.maxWait)
-					err := handler(runCtx, j)
-					cancel()
-
-					j.SetFinished(err)
-
-					if err != nil {
-						slog.ErrorContext(ctx, "Job failed", "id", j.ID, "type", j.Type, "err", err, "worker", workerID)
-					} else {
-						slog.InfoContext(ctx, "Job done", "id", j.ID, "type", j.Type, "worker", workerID)
-					}
-				}
-			}
-		}(i + 1)
+	q.pool = newWorkerPool(func(stop <-chan struct{}, name string) {
+		go q.worker(ctx, q.buf, stop, name, handler)
+	})
+	q.pool.resize(n)
+	if q.maxWorkers > n {
+		demand := func(ctx context.Context) (int, error) {
+			stats, err := q.Stats(ctx)
+			return sharedDemand(stats, q.typeWorkers), err
+		}
+		go newAutoscaler(q.pool, n, q.maxWorkers, demand).run(ctx, nil)
+	}
+	for t, limit := range q.typeWorkers {
+		for i := 0; i < limit; i++ {
+			go q.worker(ctx, q.typed[t], nil, fmt.Sprintf("%s-%d", t, i+1), handler)
+		}
 	}
 
 	// Periodically clean up finished jobs older than cleanupMaxAge
@@ -85,11 +212,83 @@ func (q *memQueue) StartConsumers(ctx context.Context, n int, handler job.Handle
 	}()
 }
 
+// worker runs jobs until ctx is done or stop is closed; a nil stop never
+// retires the worker.
+func (q *memQueue) worker(ctx context.Context, jobs <-chan *job.Job, stop <-chan struct{}, name string, handler job.Handler) {
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case j := <-jobs:
+			j.SetRunning()
+			q.track(j.Type, func(s *job.TypeStats) { s.Queued--; s.Running++ })
+
+			runCtx, cancel := context.WithTimeout(ctx, q.maxWait)
+			err := handler(runCtx, j)
+			cancel()
+
+			j.SetFinished(err)
+			q.track(j.Type, func(s *job.TypeStats) { s.Running-- })
+
+			if err != nil {
+				slog.ErrorContext(ctx, "Job failed", "id", j.ID, "type", j.Type, "err", err, "worker", name)
+			} else {
+				slog.InfoContext(ctx, "Job done", "id", j.ID, "type", j.Type, "worker", name)
+			}
+		}
+	}
+}
+
+// Workers returns the current size of the shared worker pool.
+func (q *memQueue) Workers() int {
+	return q.pool.size()
+}
+
 func (q *memQueue) Len() int {
-	return len(q.buf)
+	n := len(q.buf)
+	for _, ch := range q.typed {
+		n += len(ch)
+	}
+	return n
+}
+
+// track applies update to the counters of job type t.
+func (q *memQueue) track(t job.Type, update func(*job.TypeStats)) {
+	q.statsMu.Lock()
+	defer q.statsMu.Unlock()
+	s, ok := q.stats[t]
+	if !ok {
+		s = &job.TypeStats{}
+		q.stats[t] = s
+	}
+	update(s)
+}
+
+// Stats counts jobs per type as they move through the buffer and workers.
+func (q *memQueue) Stats(_ context.Context) (job.QueueStats, error) {
+	q.statsMu.Lock()
+	defer q.statsMu.Unlock()
+	stats := job.QueueStats{ByType: make(map[job.Type]job.TypeStats, len(q.stats))}
+	for t, s := range q.stats {
+		stats.ByType[t] = *s
+		stats.Queued += s.Queued
+		stats.Running += s.Running
+		stats.Scheduled += s.Scheduled
+	}
+	stats.Pending = stats.Queued + stats.Running
+	return stats, nil
 }
 
-func (*memQueue) Close() error {
-	// In-memory queue doesn't need cleanup
+// Close releases scheduled jobs still waiting on a full buffer. Jobs already
+// in the buffer are not drained.
+func (q *memQueue) Close() error {
+	q.closeOnce.Do(func() { close(q.closing) })
 	return nil
 }
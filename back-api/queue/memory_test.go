@@ -6,7 +6,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/fedutinova/smartheart/back-api/job"
+	"github.com/fedutinova/smartheart/back-api/logging"
+	"github.com/fedutinova/smartheart/back-api/models"
 )
 
 func TestEnqueue_SetsDefaults(t *testing.T) {
@@ -116,3 +123,306 @@ func TestStartConsumers_TimeoutMarksFailed(t *testing.T) {
 		}
 	}
 }
+
+func TestMemoryQueue_FailedJobReportsErrorCode(t *testing.T) {
+	q := NewMemoryQueue(1, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q.StartConsumers(ctx, 1, func(context.Context, *job.Job) error {
+		return job.WithCode(models.ErrorCodeTimeout, errors.New("openai API request timeout: context deadline exceeded"))
+	})
+
+	id, err := q.Enqueue(context.Background(), &job.Job{Type: job.TypeGPTProcess, Payload: []byte(`{}`)})
+	if err != nil {
+		t.Fatalf("Enqueue error: %v", err)
+	}
+
+	deadline := time.After(time.Second)
+	for {
+		st, ok := q.Status(context.Background(), id)
+		if ok && st.Status == job.StatusFailed {
+			if st.ErrorCode != models.ErrorCodeTimeout {
+				t.Errorf("expected error code %q, got %q", models.ErrorCodeTimeout, st.ErrorCode)
+			}
+			if st.Error != models.ErrorMessage(models.ErrorCodeTimeout) {
+				t.Errorf("expected the user-safe message, got %q", st.Error)
+			}
+			return
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timeout waiting for the job to fail")
+		default:
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestTryEnqueue_ReturnsErrQueueFullWhenBufferFull(t *testing.T) {
+	q := NewMemoryQueue(1, 50*time.Millisecond)
+
+	if _, err := q.TryEnqueue(context.Background(), &job.Job{Type: job.TypeECGAnalyze}); err != nil {
+		t.Fatalf("TryEnqueue error: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := q.TryEnqueue(context.Background(), &job.Job{Type: job.TypeECGAnalyze})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, job.ErrQueueFull) {
+			t.Fatalf("expected ErrQueueFull, got %v", err)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatalf("TryEnqueue blocked on a full buffer")
+	}
+}
+
+func TestTryEnqueue_LeavesRejectedJobUntouched(t *testing.T) {
+	q := NewMemoryQueue(1, 50*time.Millisecond)
+	if _, err := q.TryEnqueue(context.Background(), &job.Job{Type: job.TypeECGAnalyze}); err != nil {
+		t.Fatalf("TryEnqueue error: %v", err)
+	}
+
+	j := &job.Job{Type: job.TypeECGAnalyze}
+	if _, err := q.TryEnqueue(context.Background(), j); !errors.Is(err, job.ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+	if j.ID != uuid.Nil || j.Status != "" || !j.Enqueued.IsZero() {
+		t.Fatalf("rejected job was modified: id=%s status=%q enqueued=%v", j.ID, j.Status, j.Enqueued)
+	}
+}
+
+func TestEnqueue_PropagatesTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	parent := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	q := NewMemoryQueue(1, 200*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	got := make(chan trace.SpanContext, 1)
+	q.StartConsumers(ctx, 1, func(_ context.Context, j *job.Job) error {
+		got <- trace.SpanContextFromContext(j.ExtractTraceContext(context.Background()))
+		return nil
+	})
+
+	if _, err := q.Enqueue(parent, &job.Job{Type: job.TypeECGAnalyze}); err != nil {
+		t.Fatalf("Enqueue error: %v", err)
+	}
+
+	select {
+	case sc := <-got:
+		if sc.TraceID() != traceID {
+			t.Fatalf("expected trace id %s, got %s", traceID, sc.TraceID())
+		}
+		if !sc.IsRemote() {
+			t.Fatalf("expected extracted span context to be remote")
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatalf("timeout waiting for job handler")
+	}
+}
+
+func TestTypeWorkers_GPTCapDoesNotBlockEKG(t *testing.T) {
+	q := NewMemoryQueue(10, time.Second, WithTypeWorkers(map[job.Type]int{job.TypeGPTProcess: 1}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+	gptStarted := make(chan struct{}, 2)
+	ekgDone := make(chan struct{}, 1)
+	q.StartConsumers(ctx, 1, func(ctx context.Context, j *job.Job) error {
+		switch j.Type {
+		case job.TypeGPTProcess:
+			gptStarted <- struct{}{}
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+		case job.TypeECGAnalyze:
+			ekgDone <- struct{}{}
+		}
+		return nil
+	})
+
+	for range 2 {
+		if _, err := q.Enqueue(context.Background(), &job.Job{Type: job.TypeGPTProcess}); err != nil {
+			t.Fatalf("Enqueue error: %v", err)
+		}
+	}
+	select {
+	case <-gptStarted:
+	case <-time.After(time.Second):
+		t.Fatalf("timeout waiting for first GPT job")
+	}
+
+	if _, err := q.Enqueue(context.Background(), &job.Job{Type: job.TypeECGAnalyze}); err != nil {
+		t.Fatalf("Enqueue error: %v", err)
+	}
+	select {
+	case <-ekgDone:
+	case <-time.After(time.Second):
+		t.Fatalf("EKG job starved by GPT jobs")
+	}
+
+	select {
+	case <-gptStarted:
+		t.Fatalf("second GPT job started despite a cap of 1")
+	default:
+	}
+	if n := q.Len(); n != 1 {
+		t.Fatalf("expected 1 GPT job still queued, got %d", n)
+	}
+}
+
+func TestEnqueueAt_HoldsJobUntilDue(t *testing.T) {
+	q := NewMemoryQueue(10, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{}, 1)
+	q.StartConsumers(ctx, 1, func(_ context.Context, _ *job.Job) error {
+		done <- struct{}{}
+		return nil
+	})
+
+	id, err := q.EnqueueAt(context.Background(), &job.Job{Type: job.TypeECGAnalyze}, time.Now().Add(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("EnqueueAt error: %v", err)
+	}
+	st, ok := q.Status(context.Background(), id)
+	if !ok || st.Status != job.StatusScheduled {
+		t.Fatalf("expected scheduled status, got %+v", st)
+	}
+	if st.ScheduledAt == nil {
+		t.Fatalf("expected scheduled_at to be set")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timeout waiting for scheduled job")
+	}
+}
+
+func TestEnqueueAt_DropsDueJobAfterClose(t *testing.T) {
+	q := NewMemoryQueue(1, time.Second)
+	ctx := context.Background()
+
+	// Fill the buffer with no consumers so the scheduled job cannot be sent.
+	if _, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze}); err != nil {
+		t.Fatalf("Enqueue error: %v", err)
+	}
+	id, err := q.EnqueueAt(ctx, &job.Job{Type: job.TypeECGAnalyze}, time.Now().Add(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("EnqueueAt error: %v", err)
+	}
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if st, ok := q.Status(ctx, id); ok && st.Status == job.StatusFailed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("scheduled job was not dropped after Close")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	stats, _ := q.Stats(ctx)
+	if stats.Queued != 1 || stats.Scheduled != 0 {
+		t.Fatalf("expected only the buffered job counted, got %+v", stats)
+	}
+}
+
+func TestEnqueue_PropagatesRequestID(t *testing.T) {
+	q := NewMemoryQueue(10, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reg := job.NewRegistry()
+	got := make(chan string, 1)
+	reg.Register(job.TypeECGAnalyze, func(ctx context.Context, j *job.Job) error {
+		if j.CorrelationID != logging.RequestID(ctx) {
+			t.Errorf("handler ctx request ID %q != job correlation ID %q", logging.RequestID(ctx), j.CorrelationID)
+		}
+		got <- j.CorrelationID
+		return nil
+	})
+	q.StartConsumers(ctx, 1, reg.Dispatch)
+
+	reqCtx := logging.WithRequestID(context.Background(), "req-123")
+	if _, err := q.Enqueue(reqCtx, &job.Job{Type: job.TypeECGAnalyze}); err != nil {
+		t.Fatalf("Enqueue error: %v", err)
+	}
+
+	select {
+	case id := <-got:
+		if id != "req-123" {
+			t.Fatalf("expected correlation ID req-123, got %q", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timeout waiting for job")
+	}
+}
+
+func TestStats_TracksJobsPerType(t *testing.T) {
+	q := NewMemoryQueue(10, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{}, 1)
+	q.StartConsumers(ctx, 1, func(ctx context.Context, _ *job.Job) error {
+		started <- struct{}{}
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return nil
+	})
+
+	for _, typ := range []job.Type{job.TypeGPTProcess, job.TypeECGAnalyze} {
+		if _, err := q.Enqueue(context.Background(), &job.Job{Type: typ}); err != nil {
+			t.Fatalf("Enqueue error: %v", err)
+		}
+	}
+	if _, err := q.EnqueueAt(context.Background(), &job.Job{Type: job.TypeECGAnalyze}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("EnqueueAt error: %v", err)
+	}
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatalf("timeout waiting for first job")
+	}
+
+	stats, err := q.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats error: %v", err)
+	}
+	if stats.Queued != 1 || stats.Running != 1 || stats.Pending != 2 || stats.Scheduled != 1 {
+		t.Fatalf("unexpected totals: %+v", stats)
+	}
+	if got := stats.ByType[job.TypeGPTProcess]; got != (job.TypeStats{Running: 1}) {
+		t.Fatalf("unexpected GPT stats: %+v", got)
+	}
+	if got := stats.ByType[job.TypeECGAnalyze]; got != (job.TypeStats{Queued: 1, Scheduled: 1}) {
+		t.Fatalf("unexpected EKG stats: %+v", got)
+	}
+}
@@ -20,8 +20,25 @@ const (
 	cleanupInterval   = 5 * time.Minute
 	cleanupMaxAge     = 30 * time.Minute
 	consumerBlockTime = 5 * time.Second
+	claimBatchSize    = 100 // messages claimed per XAUTOCLAIM call
+
+	scheduleInterval  = time.Second // how often due scheduled jobs are promoted
+	scheduleBatchSize = 100
 )
 
+// promoteDueScript atomically moves due members of a scheduled sorted set
+// (KEYS[1]) into its stream (KEYS[2]), so a job is promoted exactly once even
+// with several replicas running the mover.
+var promoteDueScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, data in ipairs(due) do
+	local id = cjson.decode(data)['id']
+	redis.call('XADD', KEYS[2], '*', 'id', id, 'data', data)
+	redis.call('ZREM', KEYS[1], data)
+end
+return #due
+`)
+
 // RedisQueue implements JobQueue using Redis Streams.
 type RedisQueue struct {
 	client        *redis.Client
@@ -30,12 +47,32 @@ type RedisQueue struct {
 	maxWait       time.Duration
 	claimInterval time.Duration // how often to check for stuck jobs
 	claimTimeout  time.Duration // consider job stuck after this duration
+	// retryBaseDelay and retryMaxDelay bound the wait before a reclaimed
+	// job is reprocessed (see retryDelay).
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+	// typeWorkers gives job types their own stream and consumer pool.
+	typeWorkers map[job.Type]int
+	// streamMaxLen and deadLetterMaxLen bound the streams (0 = unbounded).
+	streamMaxLen     int64
+	deadLetterMaxLen int64
+	// maxWorkers is the ceiling the shared pool may grow to (0 = static).
+	maxWorkers int
+	pool       *workerPool
 
 	cache   *job.Cache
+	conn    *breaker // health of the Redis connection
 	wg      sync.WaitGroup
 	closing chan struct{}
+
+	// ctx scopes the queue's own Redis calls (Len and the periodic tasks);
+	// Close cancels it so none of them outlive the queue.
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
+var _ job.Queue = (*RedisQueue)(nil)
+
 // RedisQueueConfig holds configuration for RedisQueue.
 type RedisQueueConfig struct {
 	Stream        string
@@ -43,6 +80,27 @@ type RedisQueueConfig struct {
 	MaxJobTime    time.Duration
 	ClaimInterval time.Duration
 	ClaimTimeout  time.Duration
+	// RetryBaseDelay is how long a reclaimed job waits before its first
+	// reprocessing; the wait doubles with every further retry up to
+	// RetryMaxDelay. Zero reprocesses immediately.
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// TypeWorkers routes each listed job type to its own stream
+	// ("<Stream>:<type>") consumed by a dedicated pool of that many workers.
+	// Other types share Stream and the workers passed to StartConsumers.
+	TypeWorkers map[job.Type]int
+	// StreamMaxLen is the number of entries each job stream is trimmed to.
+	// Only acknowledged entries are removed, so a backlog can exceed it.
+	// Zero disables trimming.
+	StreamMaxLen int64
+	// DeadLetterMaxLen is the number of entries the dead letter stream is
+	// trimmed to, oldest first. Zero disables trimming.
+	DeadLetterMaxLen int64
+	// MaxWorkers lets the shared pool grow from the size passed to
+	// StartConsumers up to this many consumers while the shared stream
+	// backs up. Zero or a value at or below the starting size disables
+	// scaling.
+	MaxWorkers int
 }
 
 // DefaultConfig returns default queue configuration.
@@ -53,6 +111,11 @@ func DefaultConfig() RedisQueueConfig {
 		MaxJobTime:    30 * time.Second,
 		ClaimInterval: 10 * time.Second,
 		ClaimTimeout:  60 * time.Second,
+
+		RetryBaseDelay:   time.Second,
+		RetryMaxDelay:    30 * time.Second,
+		StreamMaxLen:     10000,
+		DeadLetterMaxLen: 100000,
 	}
 }
 
@@ -65,26 +128,132 @@ func NewRedisQueue(client *redis.Client, cfg RedisQueueConfig) (*RedisQueue, err
 		maxWait:       cfg.MaxJobTime,
 		claimInterval: cfg.ClaimInterval,
 		claimTimeout:  cfg.ClaimTimeout,
+		typeWorkers:   make(map[job.Type]int),
 		cache:         job.NewCache(0).WithMaxSize(10000),
+		conn:          newBreaker(),
 		closing:       make(chan struct{}),
+
+		retryBaseDelay:   cfg.RetryBaseDelay,
+		retryMaxDelay:    cfg.RetryMaxDelay,
+		streamMaxLen:     cfg.StreamMaxLen,
+		deadLetterMaxLen: cfg.DeadLetterMaxLen,
+
+		maxWorkers: cfg.MaxWorkers,
+	}
+	for t, n := range cfg.TypeWorkers {
+		if n > 0 {
+			q.typeWorkers[t] = n
+		}
 	}
+	q.ctx, q.cancel = context.WithCancel(context.Background())
 
-	// Create consumer group if it doesn't exist
-	ctx := context.Background()
-	err := q.client.XGroupCreateMkStream(ctx, q.stream, q.group, "0").Err()
-	if err != nil && !isGroupExistsError(err) {
-		return nil, fmt.Errorf("failed to create consumer group: %w", err)
+	if err := q.ensureGroups(q.ctx); err != nil {
+		q.cancel()
+		return nil, err
 	}
 
 	slog.Info("Redis queue initialized",
 		"stream", q.stream,
 		"group", q.group,
 		"max_job_time", q.maxWait,
-		"claim_timeout", q.claimTimeout)
+		"claim_timeout", q.claimTimeout,
+		"retry_base_delay", q.retryBaseDelay,
+		"retry_max_delay", q.retryMaxDelay,
+		"type_workers", q.typeWorkers,
+		"stream_max_len", q.streamMaxLen,
+		"dead_letter_max_len", q.deadLetterMaxLen,
+		"max_workers", q.maxWorkers)
 
 	return q, nil
 }
 
+// ensureGroups creates the consumer group on every stream unless it exists.
+// It runs at startup and again when Redis comes back without its data.
+func (q *RedisQueue) ensureGroups(ctx context.Context) error {
+	for _, stream := range q.streams() {
+		err := q.client.XGroupCreateMkStream(ctx, stream, q.group, "0").Err()
+		if err != nil && !isGroupExistsError(err) {
+			return fmt.Errorf("failed to create consumer group on %s: %w", stream, err)
+		}
+	}
+	return nil
+}
+
+// observe feeds the outcome of a Redis command into the connection breaker
+// and logs outage transitions. Any server reply, redis.Nil included, proves
+// the connection works. It returns how long to back off before the next
+// attempt: zero unless err is a connection failure.
+func (q *RedisQueue) observe(ctx context.Context, err error) time.Duration {
+	var reply redis.Error
+	if err == nil || errors.As(err, &reply) {
+		if q.conn.success() {
+			slog.InfoContext(ctx, "Redis connection restored, queue resumed")
+		}
+		return 0
+	}
+	if errors.Is(err, context.Canceled) {
+		return 0 // shutting down, not an outage
+	}
+	backoff, opened := q.conn.failure(err)
+	if opened {
+		slog.ErrorContext(ctx, "Redis unreachable, queue paused", "error", err)
+	}
+	return backoff
+}
+
+// reachable reports whether periodic tasks should talk to Redis. While the
+// connection is down it sends a single PING instead, which ends the outage
+// once Redis answers again.
+func (q *RedisQueue) reachable(ctx context.Context) bool {
+	if !q.conn.open() {
+		return true
+	}
+	return q.observe(ctx, q.client.Ping(ctx).Err()) == 0
+}
+
+// sleep waits for d, returning false early if the queue shuts down.
+func (q *RedisQueue) sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-q.closing:
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// scoped returns ctx cancelled also when the queue closes, for background
+// tasks that only talk to Redis. Consumers keep the caller's context so
+// Close lets the jobs they are running finish.
+func (q *RedisQueue) scoped(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(q.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// streamFor returns the stream that jobs of type t are added to.
+func (q *RedisQueue) streamFor(t job.Type) string {
+	if _, ok := q.typeWorkers[t]; ok {
+		return q.stream + ":" + string(t)
+	}
+	return q.stream
+}
+
+// streams returns the shared stream followed by every per-type stream.
+func (q *RedisQueue) streams() []string {
+	streams := []string{q.stream}
+	for t := range q.typeWorkers {
+		streams = append(streams, q.streamFor(t))
+	}
+	return streams
+}
+
 // Enqueue adds a job to the queue.
 func (q *RedisQueue) Enqueue(ctx context.Context, j *job.Job) (uuid.UUID, error) {
 	if j.ID == uuid.Nil {
@@ -92,6 +261,7 @@ func (q *RedisQueue) Enqueue(ctx context.Context, j *job.Job) (uuid.UUID, error)
 	}
 	j.Status = job.StatusQueued
 	j.Enqueued = time.Now()
+	j.InjectTraceContext(ctx)
 
 	q.cache.Put(j)
 
@@ -104,12 +274,13 @@ func (q *RedisQueue) Enqueue(ctx context.Context, j *job.Job) (uuid.UUID, error)
 
 	// Add to stream
 	_, err = q.client.XAdd(ctx, &redis.XAddArgs{
-		Stream: q.stream,
+		Stream: q.streamFor(j.Type),
 		Values: map[string]any{
 			"id":   j.ID.String(),
 			"data": string(data),
 		},
 	}).Result()
+	q.observe(ctx, err)
 	if err != nil {
 		q.cache.Delete(j.ID)
 		return uuid.Nil, fmt.Errorf("failed to add job to stream: %w", err)
@@ -119,39 +290,249 @@ func (q *RedisQueue) Enqueue(ctx context.Context, j *job.Job) (uuid.UUID, error)
 	return j.ID, nil
 }
 
+// TryEnqueue adds a job to the queue. Redis streams are unbounded, so this
+// never reports job.ErrQueueFull and behaves exactly like Enqueue.
+func (q *RedisQueue) TryEnqueue(ctx context.Context, j *job.Job) (uuid.UUID, error) {
+	return q.Enqueue(ctx, j)
+}
+
+// EnqueueAt stores the job in a per-stream sorted set scored by when. A mover
+// started by StartConsumers promotes due jobs into the stream, so scheduled
+// jobs survive restarts. Delivery after promotion is at-least-once.
+func (q *RedisQueue) EnqueueAt(ctx context.Context, j *job.Job, when time.Time) (uuid.UUID, error) {
+	if !when.After(time.Now()) {
+		return q.Enqueue(ctx, j)
+	}
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	j.Status = job.StatusScheduled
+	j.Enqueued = time.Now()
+	j.ScheduledAt = &when
+	j.InjectTraceContext(ctx)
+
+	q.cache.Put(j)
+
+	data, err := json.Marshal(j)
+	if err != nil {
+		q.cache.Delete(j.ID)
+		return uuid.Nil, fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	err = q.client.ZAdd(ctx, scheduledKey(q.streamFor(j.Type)), redis.Z{
+		Score:  float64(when.UnixMilli()),
+		Member: string(data),
+	}).Err()
+	q.observe(ctx, err)
+	if err != nil {
+		q.cache.Delete(j.ID)
+		return uuid.Nil, fmt.Errorf("failed to schedule job: %w", err)
+	}
+
+	slog.DebugContext(ctx, "Job scheduled", "job_id", j.ID, "type", j.Type, "at", when)
+	return j.ID, nil
+}
+
+// scheduledKey is the sorted set holding jobs scheduled for stream.
+func scheduledKey(stream string) string {
+	return stream + ":scheduled"
+}
+
+// scheduler periodically promotes due scheduled jobs into their streams.
+func (q *RedisQueue) scheduler(ctx context.Context) {
+	defer q.wg.Done()
+	ctx, cancel := q.scoped(ctx)
+	defer cancel()
+	ticker := time.NewTicker(scheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.closing:
+			return
+		case <-ticker.C:
+			if !q.reachable(ctx) {
+				continue
+			}
+			for _, stream := range q.streams() {
+				q.promoteDue(ctx, stream)
+			}
+		}
+	}
+}
+
+// promoteDue moves scheduled jobs for stream whose time has come into it.
+func (q *RedisQueue) promoteDue(ctx context.Context, stream string) {
+	n, err := promoteDueScript.Run(ctx, q.client,
+		[]string{scheduledKey(stream), stream},
+		time.Now().UnixMilli(), scheduleBatchSize,
+	).Int()
+	q.observe(ctx, err)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) && !q.conn.open() {
+			slog.ErrorContext(ctx, "Failed to promote scheduled jobs", "stream", stream, "error", err)
+		}
+		return
+	}
+	if n > 0 {
+		slog.DebugContext(ctx, "Promoted scheduled jobs", "stream", stream, "count", n)
+	}
+}
+
 // Status returns the current status of a job.
 func (q *RedisQueue) Status(_ context.Context, id uuid.UUID) (*job.Job, bool) {
 	return q.cache.Get(id)
 }
 
-// Len returns approximate number of pending jobs.
+// Len returns approximate number of pending jobs across all streams, or -1
+// while Redis is unreachable.
 func (q *RedisQueue) Len() int {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if q.conn.open() || q.ctx.Err() != nil {
+		return -1
+	}
+	ctx, cancel := context.WithTimeout(q.ctx, 5*time.Second)
 	defer cancel()
-	info, err := q.client.XInfoGroups(ctx, q.stream).Result()
+	total := 0
+	for _, stream := range q.streams() {
+		info, err := q.client.XInfoGroups(ctx, stream).Result()
+		if q.observe(ctx, err) > 0 {
+			return -1
+		}
+		if err != nil {
+			continue
+		}
+		for _, g := range info {
+			if g.Name == q.group {
+				total += int(g.Pending)
+			}
+		}
+	}
+	return total
+}
+
+// Workers returns the current size of the shared consumer pool.
+func (q *RedisQueue) Workers() int {
+	return q.pool.size()
+}
+
+// Stats reads the consumer group of every stream: Running is the group's
+// pending entry count, Queued its lag (or XLEN minus entries read when Redis
+// cannot report lag), Scheduled the size of the stream's scheduled set and
+// Length the stream's XLEN.
+// While Redis is unreachable it fails fast with job.ErrQueueUnavailable.
+func (q *RedisQueue) Stats(ctx context.Context) (job.QueueStats, error) {
+	if err := q.conn.err(); err != nil {
+		return job.QueueStats{}, err
+	}
+	stats, err := q.stats(ctx)
+	if q.observe(ctx, err) > 0 {
+		return job.QueueStats{}, fmt.Errorf("%w: %w", job.ErrQueueUnavailable, err)
+	}
+	return stats, err
+}
+
+func (q *RedisQueue) stats(ctx context.Context) (job.QueueStats, error) {
+	shared, err := q.streamStats(ctx, q.stream)
 	if err != nil {
-		return 0
+		return job.QueueStats{}, err
+	}
+	stats := job.QueueStats{Queued: shared.Queued, Running: shared.Running, Scheduled: shared.Scheduled, Length: shared.Length}
+	for t := range q.typeWorkers {
+		s, err := q.streamStats(ctx, q.streamFor(t))
+		if err != nil {
+			return job.QueueStats{}, err
+		}
+		if stats.ByType == nil {
+			stats.ByType = make(map[job.Type]job.TypeStats, len(q.typeWorkers))
+		}
+		stats.ByType[t] = s
+		stats.Queued += s.Queued
+		stats.Running += s.Running
+		stats.Scheduled += s.Scheduled
+		stats.Length += s.Length
+	}
+	stats.Pending = stats.Queued + stats.Running
+	return stats, nil
+}
+
+// streamStats returns the counts for a single stream and its consumer group.
+func (q *RedisQueue) streamStats(ctx context.Context, stream string) (job.TypeStats, error) {
+	var s job.TypeStats
+	groups, err := q.client.XInfoGroups(ctx, stream).Result()
+	if err != nil {
+		return s, fmt.Errorf("xinfo groups %s: %w", stream, err)
 	}
-	for _, g := range info {
-		if g.Name == q.group {
-			return int(g.Pending)
+	n, err := q.client.XLen(ctx, stream).Result()
+	if err != nil {
+		return s, fmt.Errorf("xlen %s: %w", stream, err)
+	}
+	s.Length = int(n)
+	for _, g := range groups {
+		if g.Name != q.group {
+			continue
 		}
+		s.Running = int(g.Pending)
+		if g.Lag >= 0 {
+			s.Queued = int(g.Lag)
+		} else {
+			s.Queued = max(0, int(n-g.EntriesRead))
+		}
+	}
+	scheduled, err := q.client.ZCard(ctx, scheduledKey(stream)).Result()
+	if err != nil {
+		return s, fmt.Errorf("zcard %s: %w", scheduledKey(stream), err)
 	}
-	return 0
+	s.Scheduled = int(scheduled)
+	return s, nil
 }
 
-// StartConsumers starts n consumer goroutines.
+// StartConsumers starts n consumer goroutines on the shared stream plus the
+// dedicated pool of every type in RedisQueueConfig.TypeWorkers. With
+// RedisQueueConfig.MaxWorkers the shared pool is autoscaled between n and
+// that maximum.
 func (q *RedisQueue) StartConsumers(ctx context.Context, n int, handler job.Handler) {
 	// Start consumers
-	for i := 0; i < n; i++ {
+	q.pool = newWorkerPool(func(stop <-chan struct{}, name string) {
 		q.wg.Add(1)
-		go q.consumer(ctx, i+1, handler)
+		go q.consumer(ctx, q.stream, stop, "worker-"+name, handler)
+	})
+	q.pool.resize(n)
+	if q.maxWorkers > n {
+		demand := func(ctx context.Context) (int, error) {
+			stats, err := q.Stats(ctx)
+			return sharedDemand(stats, q.typeWorkers), err
+		}
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			ctx, cancel := q.scoped(ctx)
+			defer cancel()
+			newAutoscaler(q.pool, n, q.maxWorkers, demand).run(ctx, q.closing)
+		}()
+	}
+	for t, limit := range q.typeWorkers {
+		for i := 0; i < limit; i++ {
+			q.wg.Add(1)
+			go q.consumer(ctx, q.streamFor(t), nil, fmt.Sprintf("%s-worker-%d", t, i+1), handler)
+		}
 	}
 
 	// Start claimer for stuck jobs
 	q.wg.Add(1)
 	go q.claimer(ctx, handler)
 
+	// Start mover for scheduled jobs
+	q.wg.Add(1)
+	go q.scheduler(ctx)
+
+	// Trim acknowledged entries off the streams
+	if q.streamMaxLen > 0 || q.deadLetterMaxLen > 0 {
+		q.wg.Add(1)
+		go q.trimmer(ctx)
+	}
+
 	// Periodically clean up finished jobs older than cleanupMaxAge
 	q.wg.Add(1)
 	go func() {
@@ -173,18 +554,21 @@ func (q *RedisQueue) StartConsumers(ctx context.Context, n int, handler job.Hand
 	slog.InfoContext(ctx, "Started queue consumers", "count", n)
 }
 
-// consumer processes jobs from the stream
-func (q *RedisQueue) consumer(ctx context.Context, workerID int, handler job.Handler) {
+// consumer processes jobs from stream until ctx is done, the queue is
+// closed or stop is closed; a nil stop never retires the consumer.
+func (q *RedisQueue) consumer(ctx context.Context, stream string, stop <-chan struct{}, consumerName string, handler job.Handler) {
 	defer q.wg.Done()
-	consumerName := fmt.Sprintf("worker-%d", workerID)
 
 	for {
 		select {
 		case <-ctx.Done():
-			slog.InfoContext(ctx, "Consumer shutting down", "worker", workerID)
+			slog.InfoContext(ctx, "Consumer shutting down", "worker", consumerName)
 			return
 		case <-q.closing:
-			slog.InfoContext(ctx, "Consumer received close signal", "worker", workerID)
+			slog.InfoContext(ctx, "Consumer received close signal", "worker", consumerName)
+			return
+		case <-stop:
+			slog.InfoContext(ctx, "Consumer retired by autoscaler", "worker", consumerName)
 			return
 		default:
 		}
@@ -193,22 +577,32 @@ func (q *RedisQueue) consumer(ctx context.Context, workerID int, handler job.Han
 		streams, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
 			Group:    q.group,
 			Consumer: consumerName,
-			Streams:  []string{q.stream, ">"},
+			Streams:  []string{stream, ">"},
 			Count:    1,
 			Block:    consumerBlockTime,
 		}).Result()
+		backoff := q.observe(ctx, err)
 		if err != nil {
 			if errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) {
 				continue
 			}
-			slog.ErrorContext(ctx, "Failed to read from stream", "error", err, "worker", workerID)
-			time.Sleep(time.Second) // backoff on error
+			if redis.HasErrorPrefix(err, "NOGROUP") {
+				// Redis came back without its data; recreate the groups.
+				if err := q.ensureGroups(ctx); err != nil {
+					slog.ErrorContext(ctx, "Failed to recreate consumer groups", "error", err, "worker", consumerName)
+				}
+			} else if !q.conn.open() {
+				slog.ErrorContext(ctx, "Failed to read from stream", "error", err, "worker", consumerName)
+			}
+			if !q.sleep(ctx, max(backoff, breakerBaseDelay)) {
+				return
+			}
 			continue
 		}
 
-		for _, stream := range streams {
-			for _, msg := range stream.Messages {
-				q.processMessage(ctx, msg, handler, workerID)
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				q.processMessage(ctx, stream, msg, handler, consumerName)
 			}
 		}
 	}
@@ -217,6 +611,8 @@ func (q *RedisQueue) consumer(ctx context.Context, workerID int, handler job.Han
 // claimer reclaims stuck jobs from dead consumers
 func (q *RedisQueue) claimer(ctx context.Context, handler job.Handler) {
 	defer q.wg.Done()
+	pingCtx, cancel := q.scoped(ctx)
+	defer cancel()
 	ticker := time.NewTicker(q.claimInterval)
 	defer ticker.Stop()
 
@@ -227,89 +623,163 @@ func (q *RedisQueue) claimer(ctx context.Context, handler job.Handler) {
 		case <-q.closing:
 			return
 		case <-ticker.C:
-			q.claimStuckJobs(ctx, handler)
+			if !q.reachable(pingCtx) {
+				continue
+			}
+			for _, stream := range q.streams() {
+				q.claimStuckJobs(ctx, stream, handler)
+			}
 		}
 	}
 }
 
-// claimStuckJobs finds and reclaims jobs that have been pending too long
-func (q *RedisQueue) claimStuckJobs(ctx context.Context, handler job.Handler) {
-	// Get pending entries that are older than claimTimeout
-	pending, err := q.client.XPendingExt(ctx, &redis.XPendingExtArgs{
-		Stream: q.stream,
-		Group:  q.group,
-		Start:  "-",
-		End:    "+",
-		Count:  100,
-	}).Result()
-	if err != nil {
-		if !errors.Is(err, redis.Nil) {
-			slog.ErrorContext(ctx, "Failed to get pending entries", "error", err)
-		}
-		return
-	}
-
-	for _, p := range pending {
-		if p.Idle < q.claimTimeout {
-			continue
-		}
-
-		// Claim the message
-		msgs, err := q.client.XClaim(ctx, &redis.XClaimArgs{
-			Stream:   q.stream,
+// claimStuckJobs reclaims jobs on stream that have been pending longer than
+// claimTimeout. XAUTOCLAIM scans and claims in one atomic step, so with
+// several claimers running each idle message is taken by exactly one.
+// The scan stops as soon as the queue closes; jobs already claimed run with
+// ctx like those of the consumers.
+func (q *RedisQueue) claimStuckJobs(ctx context.Context, stream string, handler job.Handler) {
+	scanCtx, cancel := q.scoped(ctx)
+	defer cancel()
+	start := "0-0"
+	for {
+		msgs, next, err := q.client.XAutoClaim(scanCtx, &redis.XAutoClaimArgs{
+			Stream:   stream,
 			Group:    q.group,
 			Consumer: "claimer",
 			MinIdle:  q.claimTimeout,
-			Messages: []string{p.ID},
+			Start:    start,
+			Count:    claimBatchSize,
 		}).Result()
+		q.observe(ctx, err)
 		if err != nil {
-			slog.ErrorContext(ctx, "Failed to claim stuck job", "message_id", p.ID, "error", err)
-			continue
+			if !errors.Is(err, redis.Nil) && !errors.Is(err, context.Canceled) && !q.conn.open() {
+				slog.ErrorContext(ctx, "Failed to claim stuck jobs", "stream", stream, "error", err)
+			}
+			return
 		}
 
-		for _, msg := range msgs {
-			slog.WarnContext(ctx, "Reclaimed stuck job",
-				"message_id", msg.ID,
-				"idle_time", p.Idle,
-				"retry_count", p.RetryCount)
+		q.handleClaimed(ctx, stream, msgs, handler)
 
-			// Check retry count - if too many retries, move to dead letter
-			if p.RetryCount > maxRetries {
-				q.moveToDeadLetter(ctx, msg, fmt.Sprintf("exceeded max retries: %d", p.RetryCount))
-				continue
+		if next == "0-0" || scanCtx.Err() != nil {
+			return
+		}
+		start = next
+	}
+}
+
+// handleClaimed reprocesses claimed messages, moving those delivered more
+// than maxRetries times before this claim to the dead letter stream.
+func (q *RedisQueue) handleClaimed(ctx context.Context, stream string, msgs []redis.XMessage, handler job.Handler) {
+	if len(msgs) == 0 {
+		return
+	}
+	deliveries := q.deliveryCounts(ctx, stream, msgs)
+
+	for _, msg := range msgs {
+		// The claim itself counted as a delivery.
+		retryCount := max(deliveries[msg.ID]-1, 0)
+		slog.WarnContext(ctx, "Reclaimed stuck job",
+			"message_id", msg.ID,
+			"retry_count", retryCount)
+
+		// Check retry count - if too many retries, move to dead letter
+		if retryCount > maxRetries {
+			q.moveToDeadLetter(ctx, stream, msg, fmt.Sprintf("exceeded max retries: %d", retryCount))
+			continue
+		}
+
+		// Reprocess in a tracked goroutine to avoid leaks on shutdown,
+		// backing off so a failing job doesn't take a worker every claim.
+		delay := q.retryDelay(retryCount)
+		q.wg.Add(1)
+		go func(m redis.XMessage) {
+			defer q.wg.Done()
+			if delay > 0 && !q.sleep(ctx, delay) {
+				return // still pending, reclaimed after restart
 			}
+			q.processMessage(ctx, stream, m, handler, "claimer")
+		}(msg)
+	}
+}
 
-			// Reprocess in a tracked goroutine to avoid leaks on shutdown
-			q.wg.Add(1)
-			go func(m redis.XMessage) {
-				defer q.wg.Done()
-				q.processMessage(ctx, m, handler, 0)
-			}(msg)
+// retryDelay returns how long a job reclaimed after retryCount deliveries
+// waits before it is reprocessed: retryBaseDelay doubled per retry beyond
+// the first, capped at retryMaxDelay. The delay always stays below half of
+// claimTimeout, since the claim's idle time grows while waiting and another
+// claimer would otherwise take the job again.
+func (q *RedisQueue) retryDelay(retryCount int64) time.Duration {
+	if q.retryBaseDelay <= 0 || retryCount <= 0 {
+		return 0
+	}
+	limit := q.retryMaxDelay
+	if limit <= 0 {
+		limit = q.retryBaseDelay
+	}
+	if q.claimTimeout > 0 {
+		limit = min(limit, q.claimTimeout/2)
+	}
+	d := q.retryBaseDelay
+	for i := int64(1); i < retryCount && d < limit; i++ {
+		d *= 2
+	}
+	return min(d, limit)
+}
+
+// deliveryCounts looks up how often each message has been delivered, in a
+// single pipelined round trip. XAUTOCLAIM does not report it. Messages whose
+// count cannot be read are missing from the result and count as first
+// deliveries.
+func (q *RedisQueue) deliveryCounts(ctx context.Context, stream string, msgs []redis.XMessage) map[string]int64 {
+	cmds := make([]*redis.XPendingExtCmd, len(msgs))
+	_, err := q.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for i, msg := range msgs {
+			cmds[i] = p.XPendingExt(ctx, &redis.XPendingExtArgs{
+				Stream: stream,
+				Group:  q.group,
+				Start:  msg.ID,
+				End:    msg.ID,
+				Count:  1,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to read delivery counts", "stream", stream, "error", err)
+	}
+
+	counts := make(map[string]int64, len(msgs))
+	for _, cmd := range cmds {
+		pending, err := cmd.Result()
+		if err != nil || len(pending) == 0 {
+			continue
 		}
+		counts[pending[0].ID] = pending[0].RetryCount
 	}
+	return counts
 }
 
-// processMessage handles a single message from the stream
-func (q *RedisQueue) processMessage(ctx context.Context, msg redis.XMessage, handler job.Handler, workerID int) {
+// processMessage handles a single message from stream
+func (q *RedisQueue) processMessage(ctx context.Context, stream string, msg redis.XMessage, handler job.Handler, worker string) {
 	// Parse job data
 	data, ok := msg.Values["data"].(string)
 	if !ok {
 		slog.ErrorContext(ctx, "Invalid message format", "message_id", msg.ID)
-		q.ackMessage(ctx, msg.ID)
+		q.ackMessage(ctx, stream, msg.ID)
 		return
 	}
 
 	var j job.Job
 	if err := json.Unmarshal([]byte(data), &j); err != nil {
 		slog.ErrorContext(ctx, "Failed to unmarshal job", "message_id", msg.ID, "error", err)
-		q.ackMessage(ctx, msg.ID)
+		q.ackMessage(ctx, stream, msg.ID)
 		return
 	}
 
 	j.SetRunning()
 	q.cache.Put(&j)
 
-	slog.InfoContext(ctx, "Processing job", "job_id", j.ID, "type", j.Type, "worker", workerID)
+	slog.InfoContext(ctx, "Processing job", "job_id", j.ID, "type", j.Type, "worker", worker)
 
 	// Execute with timeout
 	runCtx, cancel := context.WithTimeout(ctx, q.maxWait)
@@ -319,24 +789,22 @@ func (q *RedisQueue) processMessage(ctx context.Context, msg redis.XMessage, han
 	j.SetFinished(err)
 
 	if err != nil {
-		slog.ErrorContext(ctx, "Job failed", "job_id", j.ID, "type", j.Type, "error", err, "worker", workerID)
+		slog.ErrorContext(ctx, "Job failed", "job_id", j.ID, "type", j.Type, "error", err, "worker", worker)
 	} else {
-		slog.InfoContext(ctx, "Job completed", "job_id", j.ID, "type", j.Type, "worker", workerID)
+		slog.InfoContext(ctx, "Job completed", "job_id", j.ID, "type", j.Type, "worker", worker)
 	}
 
 	// Update cache with final status
 	q.cache.Put(&j)
 
 	// Acknowledge the message
-	q.ackMessage(ctx, msg.ID)
+	q.ackMessage(ctx, stream, msg.ID)
 }
 
-// moveToDeadLetter moves a failed job to the dead letter stream
-func (q *RedisQueue) moveToDeadLetter(ctx context.Context, msg redis.XMessage, reason string) {
-	dlStream := q.stream + ":deadletter"
-
+// moveToDeadLetter moves a failed job from stream to the dead letter stream
+func (q *RedisQueue) moveToDeadLetter(ctx context.Context, stream string, msg redis.XMessage, reason string) {
 	_, err := q.client.XAdd(ctx, &redis.XAddArgs{
-		Stream: dlStream,
+		Stream: q.deadLetterStream(),
 		Values: map[string]any{
 			"original_id": msg.ID,
 			"data":        msg.Values["data"],
@@ -353,20 +821,22 @@ func (q *RedisQueue) moveToDeadLetter(ctx context.Context, msg redis.XMessage, r
 	slog.WarnContext(ctx, "Moved job to dead letter queue", "message_id", msg.ID, "reason", reason)
 
 	// Only ACK after successful DL insert to avoid message loss
-	q.ackMessage(ctx, msg.ID)
+	q.ackMessage(ctx, stream, msg.ID)
 }
 
-// ackMessage acknowledges a message
-func (q *RedisQueue) ackMessage(ctx context.Context, messageID string) {
-	err := q.client.XAck(ctx, q.stream, q.group, messageID).Err()
+// ackMessage acknowledges a message on stream
+func (q *RedisQueue) ackMessage(ctx context.Context, stream, messageID string) {
+	err := q.client.XAck(ctx, stream, q.group, messageID).Err()
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to ack message", "message_id", messageID, "error", err)
 	}
 }
 
-// Close gracefully shuts down the queue.
+// Close gracefully shuts down the queue. Background Redis calls are cancelled
+// at once; consumers finish the job they are running.
 func (q *RedisQueue) Close() error {
 	close(q.closing)
+	q.cancel()
 	q.wg.Wait()
 	slog.Info("Queue closed gracefully")
 	return nil
@@ -379,16 +849,12 @@ func isGroupExistsError(err error) bool {
 
 // GetDeadLetterCount returns count of jobs in dead letter queue.
 func (q *RedisQueue) GetDeadLetterCount(ctx context.Context) (int64, error) {
-	dlStream := q.stream + ":deadletter"
-	return q.client.XLen(ctx, dlStream).Result()
+	return q.client.XLen(ctx, q.deadLetterStream()).Result()
 }
 
 // RetryDeadLetterJob moves a job from dead letter back to main queue.
 func (q *RedisQueue) RetryDeadLetterJob(ctx context.Context, messageID string) error {
-	dlStream := q.stream + ":deadletter"
-
-	// Read the message
-	msgs, err := q.client.XRange(ctx, dlStream, messageID, messageID).Result()
+	msgs, err := q.client.XRange(ctx, q.deadLetterStream(), messageID, messageID).Result()
 	if err != nil {
 		return fmt.Errorf("failed to read dead letter message: %w", err)
 	}
@@ -396,29 +862,9 @@ func (q *RedisQueue) RetryDeadLetterJob(ctx context.Context, messageID string) e
 		return fmt.Errorf("message not found: %s", messageID)
 	}
 
-	msg := msgs[0]
-	data, ok := msg.Values["data"].(string)
-	if !ok {
-		return errors.New("invalid message format")
-	}
-
-	// Re-add to main stream
-	_, err = q.client.XAdd(ctx, &redis.XAddArgs{
-		Stream: q.stream,
-		Values: map[string]any{
-			"id":   msg.Values["original_id"],
-			"data": data,
-		},
-	}).Result()
-	if err != nil {
-		return fmt.Errorf("failed to re-add job: %w", err)
-	}
-
-	// Delete from dead letter
-	_, err = q.client.XDel(ctx, dlStream, messageID).Result()
+	j, data, err := decodeDeadLetter(msgs[0])
 	if err != nil {
-		slog.WarnContext(ctx, "Failed to delete from dead letter", "message_id", messageID, "error", err)
+		return err
 	}
-
-	return nil
+	return q.requeueDeadLetter(ctx, msgs[0], j.Type, data)
 }
@@ -3,21 +3,30 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"net"
 	"os"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 
 	"github.com/fedutinova/smartheart/back-api/job"
 )
 
+// getTestRedisClient returns a client for the in-memory miniredis server by
+// default. Set TEST_REDIS_URL to run against a real Redis instead; the test
+// is skipped if that server is unreachable.
 func getTestRedisClient(t *testing.T) *redis.Client {
+	t.Helper()
 	redisURL := os.Getenv("TEST_REDIS_URL")
 	if redisURL == "" {
-		redisURL = "redis://localhost:6379"
+		return redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
 	}
 
 	opts, err := redis.ParseURL(redisURL)
@@ -37,9 +46,6 @@ func getTestRedisClient(t *testing.T) *redis.Client {
 }
 
 func TestRedisQueue_EnqueueAndConsume(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping integration test in short mode")
-	}
 	client := getTestRedisClient(t)
 	defer client.Close()
 
@@ -142,9 +148,6 @@ func TestRedisQueue_EnqueueAndConsume(t *testing.T) {
 }
 
 func TestRedisQueue_JobFailure(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping integration test in short mode")
-	}
 	client := getTestRedisClient(t)
 	defer client.Close()
 
@@ -221,9 +224,6 @@ func TestRedisQueue_JobFailure(t *testing.T) {
 }
 
 func TestRedisQueue_Persistence(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping integration test in short mode")
-	}
 	client := getTestRedisClient(t)
 	defer client.Close()
 
@@ -308,9 +308,6 @@ func TestRedisQueue_Persistence(t *testing.T) {
 }
 
 func TestRedisQueue_Len(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping integration test in short mode")
-	}
 	client := getTestRedisClient(t)
 	defer client.Close()
 
@@ -346,3 +343,673 @@ func TestRedisQueue_Len(t *testing.T) {
 	// Note: Len() returns pending count which is 0 until consumers read messages
 	// This is expected behavior for Redis Streams consumer groups
 }
+
+func TestRedisQueue_TypeWorkersUseSeparateStream(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	streamName := "test:jobs:typed:" + uuid.New().String()[:8]
+	gptStream := streamName + ":" + string(job.TypeGPTProcess)
+
+	defer client.Del(ctx, streamName, gptStream)
+
+	q, err := NewRedisQueue(client, RedisQueueConfig{
+		Stream:        streamName,
+		Group:         "test-workers",
+		MaxJobTime:    5 * time.Second,
+		ClaimInterval: 10 * time.Second,
+		ClaimTimeout:  30 * time.Second,
+		TypeWorkers:   map[job.Type]int{job.TypeGPTProcess: 1},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	defer q.Close()
+
+	if _, err := q.Enqueue(ctx, &job.Job{Type: job.TypeGPTProcess, Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("Failed to enqueue GPT job: %v", err)
+	}
+	if _, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("Failed to enqueue EKG job: %v", err)
+	}
+
+	if n := client.XLen(ctx, gptStream).Val(); n != 1 {
+		t.Errorf("expected 1 job on %s, got %d", gptStream, n)
+	}
+	if n := client.XLen(ctx, streamName).Val(); n != 1 {
+		t.Errorf("expected 1 job on %s, got %d", streamName, n)
+	}
+}
+
+func TestRedisQueue_EnqueueAtPromotesDueJobs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping slow test in short mode")
+	}
+	client := getTestRedisClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	streamName := "test:jobs:scheduled:" + uuid.New().String()[:8]
+
+	defer client.Del(context.Background(), streamName, scheduledKey(streamName))
+
+	q, err := NewRedisQueue(client, RedisQueueConfig{
+		Stream:        streamName,
+		Group:         "test-workers",
+		MaxJobTime:    5 * time.Second,
+		ClaimInterval: 10 * time.Second,
+		ClaimTimeout:  30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	defer q.Close()
+
+	id, err := q.EnqueueAt(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{}`)}, time.Now().Add(500*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to schedule job: %v", err)
+	}
+	if n := client.ZCard(ctx, scheduledKey(streamName)).Val(); n != 1 {
+		t.Fatalf("expected 1 scheduled job, got %d", n)
+	}
+	if st, ok := q.Status(ctx, id); !ok || st.Status != job.StatusScheduled {
+		t.Fatalf("expected scheduled status, got %+v", st)
+	}
+
+	processed := make(chan uuid.UUID, 1)
+	q.StartConsumers(ctx, 1, func(_ context.Context, j *job.Job) error {
+		processed <- j.ID
+		return nil
+	})
+
+	select {
+	case got := <-processed:
+		if got != id {
+			t.Errorf("expected job %s, got %s", id, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for scheduled job")
+	}
+	if n := client.ZCard(ctx, scheduledKey(streamName)).Val(); n != 0 {
+		t.Errorf("expected scheduled set to be empty, got %d", n)
+	}
+}
+
+func TestRedisQueue_Stats(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	streamName := "test:jobs:stats:" + uuid.New().String()[:8]
+	gptStream := streamName + ":" + string(job.TypeGPTProcess)
+
+	defer client.Del(ctx, streamName, gptStream, scheduledKey(streamName))
+
+	q, err := NewRedisQueue(client, RedisQueueConfig{
+		Stream:        streamName,
+		Group:         "test-workers",
+		MaxJobTime:    5 * time.Second,
+		ClaimInterval: 10 * time.Second,
+		ClaimTimeout:  30 * time.Second,
+		TypeWorkers:   map[job.Type]int{job.TypeGPTProcess: 1},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	defer q.Close()
+
+	for _, typ := range []job.Type{job.TypeECGAnalyze, job.TypeECGAnalyze, job.TypeGPTProcess} {
+		if _, err := q.Enqueue(ctx, &job.Job{Type: typ, Payload: []byte(`{}`)}); err != nil {
+			t.Fatalf("Failed to enqueue job: %v", err)
+		}
+	}
+	if _, err := q.EnqueueAt(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{}`)}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Failed to schedule job: %v", err)
+	}
+
+	// No consumers yet, so nothing is running.
+	stats, err := q.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats error: %v", err)
+	}
+	if stats.Queued != 3 || stats.Running != 0 || stats.Pending != 3 || stats.Scheduled != 1 || stats.Length != 3 {
+		t.Errorf("unexpected totals: %+v", stats)
+	}
+	if got := stats.ByType[job.TypeGPTProcess]; got.Queued != 1 {
+		t.Errorf("expected 1 queued GPT job, got %+v", got)
+	}
+}
+
+// flakyProxy forwards TCP connections to a Redis server and can simulate an
+// outage: while cut, open connections are dropped and new ones are closed
+// right after accept.
+type flakyProxy struct {
+	ln     net.Listener
+	target string
+
+	mu    sync.Mutex
+	down  bool
+	conns map[net.Conn]struct{}
+}
+
+func newFlakyProxy(t *testing.T, target string) *flakyProxy {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	p := &flakyProxy{ln: ln, target: target, conns: make(map[net.Conn]struct{})}
+	go p.serve()
+	t.Cleanup(func() {
+		_ = ln.Close()
+		p.cut()
+	})
+	return p
+}
+
+func (p *flakyProxy) addr() string { return p.ln.Addr().String() }
+
+func (p *flakyProxy) serve() {
+	for {
+		client, err := p.ln.Accept()
+		if err != nil {
+			return
+		}
+		p.mu.Lock()
+		down := p.down
+		p.mu.Unlock()
+		if down {
+			_ = client.Close()
+			continue
+		}
+		upstream, err := net.Dial("tcp", p.target)
+		if err != nil {
+			_ = client.Close()
+			continue
+		}
+		p.track(client, upstream)
+		go p.pipe(client, upstream)
+		go p.pipe(upstream, client)
+	}
+}
+
+func (p *flakyProxy) track(conns ...net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, c := range conns {
+		p.conns[c] = struct{}{}
+	}
+}
+
+func (p *flakyProxy) pipe(dst, src net.Conn) {
+	_, _ = io.Copy(dst, src)
+	_ = dst.Close()
+	_ = src.Close()
+}
+
+// cut drops every connection and refuses new ones until restore.
+func (p *flakyProxy) cut() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.down = true
+	for c := range p.conns {
+		_ = c.Close()
+	}
+	clear(p.conns)
+}
+
+func (p *flakyProxy) restore() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.down = false
+}
+
+// waitFor polls cond every 50ms until it holds or timeout passes.
+func waitFor(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// commandCounter is a redis.Hook counting the commands a client sends.
+type commandCounter struct{ n atomic.Int64 }
+
+func (c *commandCounter) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (c *commandCounter) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		c.n.Add(1)
+		return next(ctx, cmd)
+	}
+}
+
+func (c *commandCounter) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		c.n.Add(int64(len(cmds)))
+		return next(ctx, cmds)
+	}
+}
+
+func TestRedisQueue_CloseStopsBackgroundRedisCalls(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+	counter := &commandCounter{}
+	client.AddHook(counter)
+
+	streamName := "test:jobs:close:" + uuid.New().String()[:8]
+	defer client.Del(context.Background(), streamName, scheduledKey(streamName))
+
+	q, err := NewRedisQueue(client, RedisQueueConfig{
+		Stream:        streamName,
+		Group:         "test-workers",
+		MaxJobTime:    5 * time.Second,
+		ClaimInterval: 10 * time.Millisecond,
+		ClaimTimeout:  30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+
+	// No consumers: only the claimer, scheduler and trimmer talk to Redis.
+	// The caller's context stays live, so only Close can stop them.
+	q.StartConsumers(context.Background(), 0, func(context.Context, *job.Job) error { return nil })
+	start := counter.n.Load()
+	waitFor(t, 5*time.Second, "claimer to run", func() bool { return counter.n.Load() > start })
+
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	closed := counter.n.Load()
+
+	if got := q.Len(); got != -1 {
+		t.Fatalf("expected Len to report -1 after Close, got %d", got)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := counter.n.Load(); got != closed {
+		t.Fatalf("expected no Redis commands after Close, got %d", got-closed)
+	}
+}
+
+func TestRedisQueue_RecoversFromRedisBlip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping slow test in short mode")
+	}
+	direct := getTestRedisClient(t)
+	defer direct.Close()
+
+	proxy := newFlakyProxy(t, direct.Options().Addr)
+	opts := *direct.Options()
+	opts.Addr = proxy.addr()
+	opts.MaxRetries = -1
+	opts.DialTimeout = time.Second
+	client := redis.NewClient(&opts)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	streamName := "test:jobs:blip:" + uuid.New().String()[:8]
+	defer direct.Del(context.Background(), streamName, scheduledKey(streamName))
+
+	q, err := NewRedisQueue(client, RedisQueueConfig{
+		Stream:        streamName,
+		Group:         "test-workers",
+		MaxJobTime:    5 * time.Second,
+		ClaimInterval: time.Second,
+		ClaimTimeout:  30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	defer q.Close()
+
+	var processed atomic.Int32
+	q.StartConsumers(ctx, 1, func(context.Context, *job.Job) error {
+		processed.Add(1)
+		return nil
+	})
+
+	if _, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	waitFor(t, 10*time.Second, "first job", func() bool { return processed.Load() == 1 })
+
+	proxy.cut()
+	waitFor(t, 20*time.Second, "outage to be reported", func() bool { return q.Len() == -1 })
+	if _, err := q.Stats(ctx); !errors.Is(err, job.ErrQueueUnavailable) {
+		t.Fatalf("expected Stats to report ErrQueueUnavailable during the outage, got %v", err)
+	}
+
+	proxy.restore()
+	waitFor(t, 20*time.Second, "queue to recover", func() bool {
+		_, err := q.Stats(ctx)
+		return err == nil
+	})
+	if _, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("Enqueue after recovery: %v", err)
+	}
+	waitFor(t, 20*time.Second, "job after recovery", func() bool { return processed.Load() == 2 })
+}
+
+// deadLetterQueue returns a queue that treats any job as stuck after a
+// millisecond, so claim and dead-letter paths run without waiting.
+func deadLetterQueue(t *testing.T, client *redis.Client) (*RedisQueue, string) {
+	t.Helper()
+	streamName := "test:jobs:dl:" + uuid.New().String()[:8]
+	t.Cleanup(func() { client.Del(context.Background(), streamName, streamName+":deadletter") })
+
+	q, err := NewRedisQueue(client, RedisQueueConfig{
+		Stream:        streamName,
+		Group:         "test-workers",
+		MaxJobTime:    5 * time.Second,
+		ClaimInterval: time.Hour, // claims are triggered by the test
+		ClaimTimeout:  time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	t.Cleanup(func() { _ = q.Close() })
+	return q, streamName
+}
+
+// deliverWithoutAck reads the next message as a consumer that crashes before
+// acknowledging it, then redelivers it until it has been delivered times
+// times in total.
+func deliverWithoutAck(t *testing.T, client *redis.Client, stream string, times int) string {
+	t.Helper()
+	ctx := context.Background()
+	res, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group: "test-workers", Consumer: "crashed", Streams: []string{stream, ">"}, Count: 1, Block: -1,
+	}).Result()
+	if err != nil || len(res) == 0 || len(res[0].Messages) == 0 {
+		t.Fatalf("XReadGroup: %v %v", res, err)
+	}
+	for i := 1; i < times; i++ {
+		if err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group: "test-workers", Consumer: "crashed", Streams: []string{stream, "0"}, Count: 1, Block: -1,
+		}).Err(); err != nil {
+			t.Fatalf("XReadGroup redelivery: %v", err)
+		}
+	}
+	time.Sleep(5 * time.Millisecond) // exceed ClaimTimeout
+	return res[0].Messages[0].ID
+}
+
+func pendingCount(t *testing.T, client *redis.Client, stream string) int64 {
+	t.Helper()
+	p, err := client.XPending(context.Background(), stream, "test-workers").Result()
+	if err != nil {
+		t.Fatalf("XPending: %v", err)
+	}
+	return p.Count
+}
+
+func TestRedisQueue_ClaimReprocessesStuckJob(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+	q, stream := deadLetterQueue(t, client)
+	ctx := context.Background()
+
+	id, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{}`)})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	deliverWithoutAck(t, client, stream, 1)
+
+	var processed atomic.Int32
+	q.claimStuckJobs(ctx, stream, func(context.Context, *job.Job) error {
+		processed.Add(1)
+		return nil
+	})
+	q.wg.Wait()
+
+	if processed.Load() != 1 {
+		t.Fatalf("expected the stuck job to be reprocessed once, got %d", processed.Load())
+	}
+	if j, ok := q.Status(ctx, id); !ok || j.Status != job.StatusSucceeded {
+		t.Errorf("expected job to succeed, got %+v", j)
+	}
+	if n := pendingCount(t, client, stream); n != 0 {
+		t.Errorf("expected no pending entries, got %d", n)
+	}
+	if n, _ := q.GetDeadLetterCount(ctx); n != 0 {
+		t.Errorf("expected empty dead letter stream, got %d", n)
+	}
+}
+
+func TestRedisQueue_ConcurrentClaimersReclaimOnce(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+	q, stream := deadLetterQueue(t, client)
+	ctx := context.Background()
+
+	// A second replica claiming from the same stream and group.
+	other, err := NewRedisQueue(client, RedisQueueConfig{
+		Stream:        stream,
+		Group:         "test-workers",
+		MaxJobTime:    5 * time.Second,
+		ClaimInterval: time.Hour,
+		ClaimTimeout:  time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create second queue: %v", err)
+	}
+	defer other.Close()
+
+	if _, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	deliverWithoutAck(t, client, stream, 1)
+
+	var processed atomic.Int32
+	handler := func(context.Context, *job.Job) error {
+		processed.Add(1)
+		return nil
+	}
+	var wg sync.WaitGroup
+	for _, claimer := range []*RedisQueue{q, other} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			claimer.claimStuckJobs(ctx, stream, handler)
+		}()
+	}
+	wg.Wait()
+	q.wg.Wait()
+	other.wg.Wait()
+
+	if n := processed.Load(); n != 1 {
+		t.Fatalf("expected the stuck job to be reclaimed exactly once, got %d", n)
+	}
+	if n := pendingCount(t, client, stream); n != 0 {
+		t.Errorf("expected no pending entries, got %d", n)
+	}
+}
+
+func TestRedisQueue_RetryDelayGrowsWithRetryCount(t *testing.T) {
+	q := &RedisQueue{
+		retryBaseDelay: time.Second,
+		retryMaxDelay:  10 * time.Second,
+		claimTimeout:   time.Minute,
+	}
+	want := map[int64]time.Duration{
+		0: 0,
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 8 * time.Second,
+		5: 10 * time.Second,
+		9: 10 * time.Second,
+	}
+	for retries, d := range want {
+		if got := q.retryDelay(retries); got != d {
+			t.Errorf("retryDelay(%d) = %v, want %v", retries, got, d)
+		}
+	}
+
+	// The wait must end before another claimer could take the job again.
+	q.claimTimeout = 6 * time.Second
+	if got := q.retryDelay(5); got != 3*time.Second {
+		t.Errorf("expected the delay capped at half the claim timeout, got %v", got)
+	}
+
+	q.retryBaseDelay = 0
+	if got := q.retryDelay(3); got != 0 {
+		t.Errorf("expected no delay when disabled, got %v", got)
+	}
+}
+
+func TestRedisQueue_ReclaimedJobWaitsBeforeReprocessing(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+	q, stream := deadLetterQueue(t, client)
+	q.retryBaseDelay = 50 * time.Millisecond
+	q.retryMaxDelay = time.Second
+	q.claimTimeout = time.Minute
+	ctx := context.Background()
+
+	if _, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	deliverWithoutAck(t, client, stream, 3)
+
+	started := time.Now()
+	var processedAfter atomic.Int64
+	handler := func(context.Context, *job.Job) error {
+		processedAfter.Store(int64(time.Since(started)))
+		return nil
+	}
+	q.handleClaimed(ctx, stream, claimAll(t, client, stream), handler)
+	q.wg.Wait()
+
+	// Three earlier deliveries, so the base delay is doubled twice.
+	if got := time.Duration(processedAfter.Load()); got < 200*time.Millisecond {
+		t.Errorf("expected reprocessing after at least 200ms, got %v", got)
+	}
+}
+
+// claimAll claims every pending message of stream for the claimer, counting
+// as one more delivery.
+func claimAll(t *testing.T, client *redis.Client, stream string) []redis.XMessage {
+	t.Helper()
+	msgs, _, err := client.XAutoClaim(context.Background(), &redis.XAutoClaimArgs{
+		Stream: stream, Group: "test-workers", Consumer: "claimer", Start: "0-0", Count: 100,
+	}).Result()
+	if err != nil || len(msgs) == 0 {
+		t.Fatalf("XAutoClaim: %v %v", msgs, err)
+	}
+	return msgs
+}
+
+func TestRedisQueue_DeadLettersAfterMaxRetries(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+	q, stream := deadLetterQueue(t, client)
+	ctx := context.Background()
+
+	if _, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	msgID := deliverWithoutAck(t, client, stream, maxRetries+1)
+
+	q.claimStuckJobs(ctx, stream, func(context.Context, *job.Job) error {
+		t.Error("a job over the retry limit must not be processed")
+		return nil
+	})
+	q.wg.Wait()
+
+	if n, err := q.GetDeadLetterCount(ctx); err != nil || n != 1 {
+		t.Fatalf("expected 1 dead letter, got %d (err: %v)", n, err)
+	}
+	if n := pendingCount(t, client, stream); n != 0 {
+		t.Errorf("expected the original message to be acked, got %d pending", n)
+	}
+	dl, err := client.XRange(ctx, stream+":deadletter", "-", "+").Result()
+	if err != nil || len(dl) != 1 {
+		t.Fatalf("XRange dead letter: %v %v", dl, err)
+	}
+	if dl[0].Values["original_id"] != msgID {
+		t.Errorf("expected original_id %s, got %v", msgID, dl[0].Values["original_id"])
+	}
+	if reason, _ := dl[0].Values["reason"].(string); reason == "" {
+		t.Error("expected a dead letter reason")
+	}
+}
+
+func TestRedisQueue_MoveToDeadLetter(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+	q, stream := deadLetterQueue(t, client)
+	ctx := context.Background()
+
+	if _, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	deliverWithoutAck(t, client, stream, 1)
+	msgs, err := client.XRange(ctx, stream, "-", "+").Result()
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("XRange: %v %v", msgs, err)
+	}
+
+	q.moveToDeadLetter(ctx, stream, msgs[0], "test")
+
+	if n, err := q.GetDeadLetterCount(ctx); err != nil || n != 1 {
+		t.Fatalf("expected 1 dead letter, got %d (err: %v)", n, err)
+	}
+	if n := pendingCount(t, client, stream); n != 0 {
+		t.Errorf("expected message to be acked, got %d pending", n)
+	}
+}
+
+func TestRedisQueue_RetryDeadLetterJob(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+	q, stream := deadLetterQueue(t, client)
+	ctx := context.Background()
+
+	id, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{"retry":true}`)})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	deliverWithoutAck(t, client, stream, maxRetries+1)
+	q.claimStuckJobs(ctx, stream, func(context.Context, *job.Job) error { return nil })
+	q.wg.Wait()
+
+	dl, err := client.XRange(ctx, stream+":deadletter", "-", "+").Result()
+	if err != nil || len(dl) != 1 {
+		t.Fatalf("expected 1 dead letter: %v %v", dl, err)
+	}
+	if err := q.RetryDeadLetterJob(ctx, dl[0].ID); err != nil {
+		t.Fatalf("RetryDeadLetterJob: %v", err)
+	}
+	if n, _ := q.GetDeadLetterCount(ctx); n != 0 {
+		t.Errorf("expected dead letter to be removed, got %d", n)
+	}
+
+	processed := make(chan *job.Job, 1)
+	consumerCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	q.StartConsumers(consumerCtx, 1, func(_ context.Context, j *job.Job) error {
+		processed <- j
+		return nil
+	})
+	select {
+	case j := <-processed:
+		if j.ID != id || string(j.Payload) != `{"retry":true}` {
+			t.Errorf("unexpected retried job: %+v", j)
+		}
+	case <-consumerCtx.Done():
+		t.Fatal("timeout waiting for the retried job")
+	}
+
+	if err := q.RetryDeadLetterJob(ctx, dl[0].ID); err == nil {
+		t.Error("expected an error retrying a dead letter that no longer exists")
+	}
+}
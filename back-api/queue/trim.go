@@ -0,0 +1,162 @@
+package queue
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	trimInterval  = time.Minute // how often streams are trimmed
+	trimBatchSize = 1000        // max entries inspected per stream and run
+)
+
+// trimmer periodically trims acknowledged entries from the job streams down
+// to streamMaxLen and the dead letter stream down to deadLetterMaxLen.
+func (q *RedisQueue) trimmer(ctx context.Context) {
+	defer q.wg.Done()
+	ctx, cancel := q.scoped(ctx)
+	defer cancel()
+	ticker := time.NewTicker(trimInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.closing:
+			return
+		case <-ticker.C:
+			if !q.reachable(ctx) {
+				continue
+			}
+			q.trim(ctx)
+		}
+	}
+}
+
+// trim runs one trimming pass over every stream. Failures are logged; the
+// next pass retries.
+func (q *RedisQueue) trim(ctx context.Context) {
+	if q.streamMaxLen > 0 {
+		for _, stream := range q.streams() {
+			n, err := q.trimStream(ctx, stream, q.streamMaxLen)
+			q.observe(ctx, err)
+			if err != nil {
+				if !errors.Is(err, context.Canceled) && !q.conn.open() {
+					slog.ErrorContext(ctx, "Failed to trim stream", "stream", stream, "error", err)
+				}
+				return
+			}
+			if n > 0 {
+				slog.DebugContext(ctx, "Trimmed stream", "stream", stream, "count", n)
+			}
+		}
+	}
+	if q.deadLetterMaxLen > 0 {
+		dlStream := q.deadLetterStream()
+		n, err := q.client.XTrimMaxLenApprox(ctx, dlStream, q.deadLetterMaxLen, 0).Result()
+		q.observe(ctx, err)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) && !q.conn.open() {
+				slog.ErrorContext(ctx, "Failed to trim dead letter stream", "stream", dlStream, "error", err)
+			}
+			return
+		}
+		if n > 0 {
+			slog.DebugContext(ctx, "Trimmed dead letter stream", "stream", dlStream, "count", n)
+		}
+	}
+}
+
+// trimStream removes the oldest entries of stream beyond maxLen, but never
+// one the consumer group has not acknowledged: the cut stops at the oldest
+// pending entry, or after the last delivered one when nothing is pending.
+// Up to trimBatchSize entries are removed per call.
+func (q *RedisQueue) trimStream(ctx context.Context, stream string, maxLen int64) (int64, error) {
+	n, err := q.client.XLen(ctx, stream).Result()
+	if err != nil {
+		return 0, fmt.Errorf("xlen %s: %w", stream, err)
+	}
+	excess := min(n-maxLen, trimBatchSize)
+	if excess <= 0 {
+		return 0, nil
+	}
+
+	floor, err := q.ackedFloor(ctx, stream)
+	if err != nil || floor == "" {
+		return 0, err
+	}
+
+	// The entry after the excess ones is the oldest one to keep.
+	msgs, err := q.client.XRangeN(ctx, stream, "-", "+", excess+1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("xrange %s: %w", stream, err)
+	}
+	if int64(len(msgs)) <= excess {
+		return 0, nil
+	}
+	minID := msgs[excess].ID
+	if compareStreamIDs(floor, minID) < 0 {
+		minID = floor
+	}
+
+	// Approximate trimming only removes whole nodes below minID, so it can
+	// leave extra entries behind but never removes more.
+	trimmed, err := q.client.XTrimMinIDApprox(ctx, stream, minID, 0).Result()
+	if err != nil {
+		return 0, fmt.Errorf("xtrim %s: %w", stream, err)
+	}
+	return trimmed, nil
+}
+
+// ackedFloor returns the ID below which every entry of stream has been
+// acknowledged by the queue's group, or "" when the group is missing.
+func (q *RedisQueue) ackedFloor(ctx context.Context, stream string) (string, error) {
+	groups, err := q.client.XInfoGroups(ctx, stream).Result()
+	if err != nil {
+		return "", fmt.Errorf("xinfo groups %s: %w", stream, err)
+	}
+	for _, g := range groups {
+		if g.Name != q.group {
+			continue
+		}
+		if g.Pending == 0 {
+			// Everything up to the last delivered entry is acknowledged;
+			// keeping that entry itself is harmless.
+			return g.LastDeliveredID, nil
+		}
+		pending, err := q.client.XPending(ctx, stream, q.group).Result()
+		if err != nil {
+			return "", fmt.Errorf("xpending %s: %w", stream, err)
+		}
+		if pending.Count == 0 {
+			return g.LastDeliveredID, nil
+		}
+		return pending.Lower, nil
+	}
+	return "", nil
+}
+
+// compareStreamIDs orders two stream entry IDs ("<ms>-<seq>"). Parts that
+// do not parse compare as zero.
+func compareStreamIDs(a, b string) int {
+	am, as := parseStreamID(a)
+	bm, bs := parseStreamID(b)
+	if c := cmp.Compare(am, bm); c != 0 {
+		return c
+	}
+	return cmp.Compare(as, bs)
+}
+
+func parseStreamID(id string) (ms, seq uint64) {
+	msPart, seqPart, _ := strings.Cut(id, "-")
+	ms, _ = strconv.ParseUint(msPart, 10, 64)
+	seq, _ = strconv.ParseUint(seqPart, 10, 64)
+	return ms, seq
+}
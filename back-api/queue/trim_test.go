@@ -0,0 +1,199 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/fedutinova/smartheart/back-api/job"
+)
+
+func TestCompareStreamIDs(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1-0", "1-0", 0},
+		{"1-1", "1-0", 1},
+		{"2-0", "10-0", -1},
+		{"1700000000000-5", "1700000000001-0", -1},
+		{"0-0", "1-0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareStreamIDs(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareStreamIDs(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// trimQueue returns a queue on a fresh stream with n enqueued jobs and their
+// stream entry IDs, oldest first.
+func trimQueue(t *testing.T, client *redis.Client, n int) (*RedisQueue, string, []string) {
+	t.Helper()
+	ctx := context.Background()
+	streamName := "test:jobs:trim:" + uuid.New().String()[:8]
+	t.Cleanup(func() { client.Del(context.Background(), streamName, streamName+":deadletter") })
+
+	q, err := NewRedisQueue(client, RedisQueueConfig{
+		Stream:        streamName,
+		Group:         "test-workers",
+		MaxJobTime:    5 * time.Second,
+		ClaimInterval: time.Hour,
+		ClaimTimeout:  time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	t.Cleanup(func() { _ = q.Close() })
+
+	for range n {
+		if _, err := q.Enqueue(ctx, &job.Job{Type: job.TypeECGAnalyze, Payload: []byte(`{}`)}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	msgs, err := client.XRange(ctx, streamName, "-", "+").Result()
+	if err != nil || len(msgs) != n {
+		t.Fatalf("XRange: %v %v", msgs, err)
+	}
+	ids := make([]string, n)
+	for i, m := range msgs {
+		ids[i] = m.ID
+	}
+	return q, streamName, ids
+}
+
+// deliver reads count entries as a consumer and acknowledges the first acked
+// of them.
+func deliver(t *testing.T, client *redis.Client, stream string, count, acked int) {
+	t.Helper()
+	ctx := context.Background()
+	res, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group: "test-workers", Consumer: "worker", Streams: []string{stream, ">"}, Count: int64(count), Block: -1,
+	}).Result()
+	if err != nil || len(res) == 0 || len(res[0].Messages) != count {
+		t.Fatalf("XReadGroup: %v %v", res, err)
+	}
+	for _, m := range res[0].Messages[:acked] {
+		if err := client.XAck(ctx, stream, "test-workers", m.ID).Err(); err != nil {
+			t.Fatalf("XAck: %v", err)
+		}
+	}
+}
+
+func remainingIDs(t *testing.T, client *redis.Client, stream string) map[string]bool {
+	t.Helper()
+	msgs, err := client.XRange(context.Background(), stream, "-", "+").Result()
+	if err != nil {
+		t.Fatalf("XRange: %v", err)
+	}
+	ids := make(map[string]bool, len(msgs))
+	for _, m := range msgs {
+		ids[m.ID] = true
+	}
+	return ids
+}
+
+func TestRedisQueue_TrimKeepsUnackedEntries(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+	q, stream, ids := trimQueue(t, client, 6)
+	ctx := context.Background()
+
+	// Entries 0-2 are acked, 3 is pending, 4-5 were never delivered.
+	deliver(t, client, stream, 4, 3)
+
+	trimmed, err := q.trimStream(ctx, stream, 1)
+	if err != nil {
+		t.Fatalf("trimStream: %v", err)
+	}
+
+	left := remainingIDs(t, client, stream)
+	for _, id := range ids[3:] {
+		if !left[id] {
+			t.Errorf("unacknowledged entry %s was trimmed", id)
+		}
+	}
+	if int(trimmed) != len(ids)-len(left) {
+		t.Errorf("trimStream reported %d, but %d entries are gone", trimmed, len(ids)-len(left))
+	}
+	if trimmed > 3 {
+		t.Errorf("expected at most the 3 acked entries to be trimmed, got %d", trimmed)
+	}
+}
+
+func TestRedisQueue_TrimStopsAtLastDelivered(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+	q, stream, ids := trimQueue(t, client, 5)
+	ctx := context.Background()
+
+	// Everything delivered so far is acked; entries 3-4 are still queued.
+	deliver(t, client, stream, 3, 3)
+
+	if _, err := q.trimStream(ctx, stream, 1); err != nil {
+		t.Fatalf("trimStream: %v", err)
+	}
+
+	left := remainingIDs(t, client, stream)
+	for _, id := range ids[3:] {
+		if !left[id] {
+			t.Errorf("queued entry %s was trimmed", id)
+		}
+	}
+
+	// The queued jobs must still be delivered after trimming.
+	res, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group: "test-workers", Consumer: "worker", Streams: []string{stream, ">"}, Count: 10, Block: -1,
+	}).Result()
+	if err != nil || len(res) == 0 || len(res[0].Messages) != 2 {
+		t.Fatalf("expected the 2 queued entries to be delivered: %v %v", res, err)
+	}
+	if res[0].Messages[0].ID != ids[3] || res[0].Messages[1].ID != ids[4] {
+		t.Errorf("unexpected entries delivered: %v", res[0].Messages)
+	}
+}
+
+func TestRedisQueue_TrimWithinLimitIsNoop(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+	q, stream, _ := trimQueue(t, client, 3)
+	deliver(t, client, stream, 3, 3)
+
+	trimmed, err := q.trimStream(context.Background(), stream, 3)
+	if err != nil {
+		t.Fatalf("trimStream: %v", err)
+	}
+	if trimmed != 0 {
+		t.Errorf("expected nothing trimmed, got %d", trimmed)
+	}
+}
+
+func TestRedisQueue_TrimDeadLetter(t *testing.T) {
+	client := getTestRedisClient(t)
+	defer client.Close()
+	q, stream, _ := trimQueue(t, client, 0)
+	ctx := context.Background()
+	q.deadLetterMaxLen = 2
+
+	for range 5 {
+		if err := client.XAdd(ctx, &redis.XAddArgs{
+			Stream: stream + ":deadletter",
+			Values: map[string]any{"data": "{}", "reason": "test"},
+		}).Err(); err != nil {
+			t.Fatalf("XAdd: %v", err)
+		}
+	}
+
+	q.trim(ctx)
+
+	n, err := q.GetDeadLetterCount(ctx)
+	if err != nil {
+		t.Fatalf("GetDeadLetterCount: %v", err)
+	}
+	if n < 2 || n > 5 {
+		t.Errorf("expected the dead letter stream trimmed towards 2 entries, got %d", n)
+	}
+}
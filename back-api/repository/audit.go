@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+// WriteAudit appends an entry to the audit log. CreatedAt is set by the
+// database.
+func (r *Repository) WriteAudit(ctx context.Context, entry *models.AuditEntry) error {
+	_, err := r.querier.Exec(ctx, `
+		INSERT INTO audit_log (actor_id, action, target, ip, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+	`, entry.ActorID, entry.Action, nullString(entry.Target), nullString(entry.IP))
+	if err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// AuditFilter narrows ListAudit. Zero-valued fields are not applied.
+type AuditFilter struct {
+	ActorID *uuid.UUID
+	Action  string
+	From    *time.Time // inclusive lower bound on created_at
+	To      *time.Time // exclusive upper bound on created_at
+	Limit   int
+	Offset  int
+}
+
+// whereClause renders the filter as a parameterized WHERE clause.
+func (f AuditFilter) whereClause() (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(cond string, v any) {
+		args = append(args, v)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.ActorID != nil {
+		add("actor_id = $%d", *f.ActorID)
+	}
+	if f.Action != "" {
+		add("action = $%d", f.Action)
+	}
+	if f.From != nil {
+		add("created_at >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add("created_at < $%d", *f.To)
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+// ListAudit returns audit entries matching filter, newest first, together
+// with the total number of matches.
+func (r *Repository) ListAudit(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, int, error) {
+	where, args := filter.whereClause()
+
+	var total int
+	if err := r.querier.QueryRow(ctx, `SELECT COUNT(*) FROM audit_log `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count audit entries: %w", err)
+	}
+
+	n := len(args)
+	query := fmt.Sprintf(`
+		SELECT id, actor_id, action, COALESCE(target, ''), COALESCE(ip, ''), created_at
+		FROM audit_log
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, n+1, n+2)
+
+	rows, err := r.querier.Query(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var e models.AuditEntry
+		if err := rows.Scan(&e.ID, &e.ActorID, &e.Action, &e.Target, &e.IP, &e.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("scan audit entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate audit entries: %w", err)
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+func TestAuditFilter_WhereClause(t *testing.T) {
+	actorID := uuid.New()
+
+	where, args := AuditFilter{ActorID: &actorID, Action: models.AuditLogin, Limit: 10}.whereClause()
+
+	want := "WHERE actor_id = $1 AND action = $2"
+	if where != want {
+		t.Fatalf("unexpected where clause:\n got: %s\nwant: %s", where, want)
+	}
+	if len(args) != 2 || args[0] != actorID || args[1] != models.AuditLogin {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
+
+func TestWriteAudit_StoresEmptyFieldsAsNull(t *testing.T) {
+	var got []any
+	q := stubQuerier{
+		execFn: func(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+			got = args
+			return pgconn.NewCommandTag("INSERT 0 1"), nil
+		},
+	}
+
+	err := NewTxScoped(q).WriteAudit(context.Background(), &models.AuditEntry{Action: models.AuditLoginFailed, IP: "203.0.113.7"})
+	if err != nil {
+		t.Fatalf("WriteAudit: %v", err)
+	}
+	if len(got) != 4 || got[0] != (*uuid.UUID)(nil) || got[1] != models.AuditLoginFailed || got[2] != nil || got[3] != "203.0.113.7" {
+		t.Fatalf("unexpected args: %#v", got)
+	}
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+// CreateECGBatch inserts a new batch; its child requests reference it through
+// requests.batch_id.
+func (r *Repository) CreateECGBatch(ctx context.Context, batch *models.ECGBatch) error {
+	if batch.ID == uuid.Nil {
+		batch.ID = uuid.New()
+	}
+	query := `
+		INSERT INTO ecg_batches (id, user_id, created_at)
+		VALUES ($1, $2, NOW())
+		RETURNING created_at
+	`
+	if err := r.querier.QueryRow(ctx, query, batch.ID, batch.UserID).Scan(&batch.CreatedAt); err != nil {
+		return fmt.Errorf("create ecg batch: %w", err)
+	}
+	return nil
+}
+
+// GetECGBatch returns a batch with its non-deleted child requests, oldest
+// first. Only request columns are loaded, not files or responses.
+func (r *Repository) GetECGBatch(ctx context.Context, id uuid.UUID) (*models.ECGBatch, error) {
+	var batch models.ECGBatch
+	err := r.querier.QueryRow(ctx,
+		`SELECT id, user_id, created_at FROM ecg_batches WHERE id = $1`, id,
+	).Scan(&batch.ID, &batch.UserID, &batch.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.ErrBatchNotFound
+		}
+		return nil, fmt.Errorf("get ecg batch: %w", err)
+	}
+
+	rows, err := r.querier.Query(ctx, `
+		SELECT id, user_id, status, created_at, updated_at
+		FROM requests
+		WHERE batch_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at, id
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("query batch requests: %w", err)
+	}
+	defer rows.Close()
+
+	batch.Requests = []models.Request{}
+	for rows.Next() {
+		req := models.Request{BatchID: &batch.ID}
+		if err := rows.Scan(&req.ID, &req.UserID, &req.Status, &req.CreatedAt, &req.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan batch request: %w", err)
+		}
+		batch.Requests = append(batch.Requests, req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate batch requests: %w", err)
+	}
+	return &batch, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -64,6 +65,23 @@ func TestGetRefreshToken_WrapsUnexpectedQueryRowError(t *testing.T) {
 	assert.NotErrorIs(t, err, apperr.ErrInvalidToken)
 }
 
+func TestDeleteExpiredRefreshTokens_DeletesInBatches(t *testing.T) {
+	batches := []int64{refreshTokenPurgeBatch, refreshTokenPurgeBatch, 7}
+	calls := 0
+	repo := NewTxScoped(stubQuerier{
+		execFn: func(context.Context, string, ...any) (pgconn.CommandTag, error) {
+			n := batches[calls]
+			calls++
+			return pgconn.NewCommandTag(fmt.Sprintf("DELETE %d", n)), nil
+		},
+	})
+
+	deleted, err := repo.DeleteExpiredRefreshTokens(context.Background(), time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2*refreshTokenPurgeBatch+7), deleted)
+	assert.Equal(t, 3, calls)
+}
+
 func TestGetFreeAnalysesUsed_ReturnsErrorOnNoRows(t *testing.T) {
 	repo := NewTxScoped(stubQuerier{
 		queryRowFn: func(context.Context, string, ...any) pgx.Row {
@@ -83,7 +101,7 @@ func TestGetFreeAnalysesUsed_ReturnsErrorOnNoRows(t *testing.T) {
 func TestUpdateRequestStatus_RejectsUnknownStatus(t *testing.T) {
 	repo := NewTxScoped(stubQuerier{})
 
-	err := repo.UpdateRequestStatus(context.Background(), uuid.New(), "queued")
+	err := repo.UpdateRequestStatus(context.Background(), uuid.New(), "running")
 	require.Error(t, err)
 	assert.ErrorContains(t, err, "invalid request status")
 }
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+func (r *Repository) CreateEmailVerificationToken(ctx context.Context, token *models.EmailVerificationToken) error {
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO email_verification_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+	`
+
+	_, err := r.querier.Exec(ctx, query, token.ID, token.UserID, token.TokenHash, token.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("create email verification token: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetValidEmailVerificationToken(ctx context.Context, tokenHash string) (*models.EmailVerificationToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, created_at, used_at
+		FROM email_verification_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+	`
+
+	var t models.EmailVerificationToken
+	err := r.querier.QueryRow(ctx, query, tokenHash).Scan(
+		&t.ID, &t.UserID, &t.TokenHash, &t.ExpiresAt, &t.CreatedAt, &t.UsedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("get valid email verification token: %w", err)
+	}
+	return &t, nil
+}
+
+func (r *Repository) MarkEmailVerificationTokenUsed(ctx context.Context, tokenID uuid.UUID) error {
+	query := `UPDATE email_verification_tokens SET used_at = NOW() WHERE id = $1`
+
+	_, err := r.querier.Exec(ctx, query, tokenID)
+	if err != nil {
+		return fmt.Errorf("mark email verification token used: %w", err)
+	}
+	return nil
+}
+
+// InvalidateUserEmailVerificationTokens marks every outstanding verification
+// token of the user as used.
+func (r *Repository) InvalidateUserEmailVerificationTokens(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE email_verification_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`
+
+	_, err := r.querier.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("invalidate user email verification tokens: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) MarkEmailVerified(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE users SET email_verified = TRUE, updated_at = NOW() WHERE id = $1`
+
+	tag, err := r.querier.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("mark email verified: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/fedutinova/smartheart/back-api/auth"
+)
+
+// fieldKeyLabel separates field encryption keys from other keys derived from
+// the same secret.
+const fieldKeyLabel = "smartheart-fields-v1"
+
+// ErrUnknownKeyVersion is returned when a row was encrypted with a key
+// version that is not configured.
+var ErrUnknownKeyVersion = errors.New("unknown encryption key version")
+
+// FieldCipher encrypts sensitive text columns (requests.text_query and
+// responses.content) at rest. Every encrypted row records the version of the
+// key it was written with, so keys can be rotated: new rows use the active
+// version and rows written with older versions stay readable as long as
+// those versions remain configured. Rows without a version are plaintext.
+type FieldCipher struct {
+	active int16
+	boxes  map[int16]*auth.SecretBox
+}
+
+// NewFieldCipher derives one key per configured version. New values are
+// encrypted with the active version; active 0 keeps writing plaintext while
+// still decrypting existing rows.
+func NewFieldCipher(secrets map[int]string, active int) (*FieldCipher, error) {
+	c := &FieldCipher{boxes: make(map[int16]*auth.SecretBox, len(secrets))}
+	for version, secret := range secrets {
+		if version < 1 || version > math.MaxInt16 {
+			return nil, fmt.Errorf("encryption key version %d out of range", version)
+		}
+		c.boxes[int16(version)] = auth.NewLabeledSecretBox(secret, fieldKeyLabel)
+	}
+	if active != 0 {
+		if active < 1 || active > math.MaxInt16 || c.boxes[int16(active)] == nil {
+			return nil, fmt.Errorf("active encryption key %d: %w", active, ErrUnknownKeyVersion)
+		}
+		c.active = int16(active)
+	}
+	return c, nil
+}
+
+// WithFieldCipher makes the Repository encrypt and decrypt sensitive columns
+// with c. Repositories derived through WithTx keep it; without it, or with a
+// nil c, values are stored and read as plaintext only.
+func WithFieldCipher(c *FieldCipher) func(*Repository) {
+	return func(r *Repository) {
+		r.cipher = c
+	}
+}
+
+// encrypt returns the stored form of plain and the key version to record
+// with it, or plain and nil while encryption is off.
+func (c *FieldCipher) encrypt(plain string) (string, *int16, error) {
+	if c == nil || c.active == 0 {
+		return plain, nil, nil
+	}
+	sealed, err := c.boxes[c.active].Seal(plain)
+	if err != nil {
+		return "", nil, err
+	}
+	version := c.active
+	return sealed, &version, nil
+}
+
+// decrypt reverses encrypt. A nil version means the value is plaintext.
+func (c *FieldCipher) decrypt(stored string, version *int16) (string, error) {
+	if version == nil {
+		return stored, nil
+	}
+	var box *auth.SecretBox
+	if c != nil {
+		box = c.boxes[*version]
+	}
+	if box == nil {
+		return "", fmt.Errorf("key version %d: %w", *version, ErrUnknownKeyVersion)
+	}
+	return box.Open(stored)
+}
+
+// encryptOptional is encrypt for nullable columns; nil stays nil.
+func (c *FieldCipher) encryptOptional(plain *string) (*string, *int16, error) {
+	if plain == nil {
+		return nil, nil, nil
+	}
+	stored, version, err := c.encrypt(*plain)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &stored, version, nil
+}
+
+// decryptOptional is decrypt for nullable columns; nil stays nil.
+func (c *FieldCipher) decryptOptional(stored *string, version *int16) (*string, error) {
+	if stored == nil {
+		return nil, nil
+	}
+	plain, err := c.decrypt(*stored, version)
+	if err != nil {
+		return nil, err
+	}
+	return &plain, nil
+}
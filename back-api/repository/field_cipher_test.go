@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+const (
+	testSecretV1 = "first-secret-at-least-32-characters-long"
+	testSecretV2 = "second-secret-at-least-32-characters-long"
+)
+
+func newTestCipher(t *testing.T, secrets map[int]string, active int) *FieldCipher {
+	t.Helper()
+	c, err := NewFieldCipher(secrets, active)
+	if err != nil {
+		t.Fatalf("NewFieldCipher: %v", err)
+	}
+	return c
+}
+
+func TestFieldCipher_RoundTrip(t *testing.T) {
+	c := newTestCipher(t, map[int]string{1: testSecretV1}, 1)
+	plain := "Patient: John Doe, Age: 45"
+
+	stored, version, err := c.encrypt(plain)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if version == nil || *version != 1 {
+		t.Fatalf("expected key version 1, got %v", version)
+	}
+	if strings.Contains(stored, "John") {
+		t.Fatalf("stored value leaks plaintext: %s", stored)
+	}
+	again, _, _ := c.encrypt(plain)
+	if again == stored {
+		t.Errorf("expected a fresh nonce per encryption")
+	}
+
+	got, err := c.decrypt(stored, version)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if got != plain {
+		t.Errorf("decrypt = %q, want %q", got, plain)
+	}
+}
+
+func TestFieldCipher_RotationKeepsOldRowsReadable(t *testing.T) {
+	old := newTestCipher(t, map[int]string{1: testSecretV1}, 1)
+	stored, version, err := old.encrypt("before rotation")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	rotated := newTestCipher(t, map[int]string{1: testSecretV1, 2: testSecretV2}, 2)
+	got, err := rotated.decrypt(stored, version)
+	if err != nil || got != "before rotation" {
+		t.Fatalf("decrypt after rotation = %q, %v", got, err)
+	}
+	if _, v, _ := rotated.encrypt("after rotation"); v == nil || *v != 2 {
+		t.Errorf("expected new rows to use key version 2, got %v", v)
+	}
+
+	retired := newTestCipher(t, map[int]string{2: testSecretV2}, 2)
+	if _, err := retired.decrypt(stored, version); !errors.Is(err, ErrUnknownKeyVersion) {
+		t.Errorf("expected ErrUnknownKeyVersion once version 1 is removed, got %v", err)
+	}
+}
+
+func TestFieldCipher_PlaintextWhenDisabled(t *testing.T) {
+	for name, c := range map[string]*FieldCipher{
+		"nil":       nil,
+		"no active": newTestCipher(t, map[int]string{1: testSecretV1}, 0),
+	} {
+		stored, version, err := c.encrypt("plain")
+		if err != nil || stored != "plain" || version != nil {
+			t.Errorf("%s: encrypt = %q, %v, %v; want plaintext without version", name, stored, version, err)
+		}
+		got, err := c.decrypt("legacy row", nil)
+		if err != nil || got != "legacy row" {
+			t.Errorf("%s: decrypt of plaintext row = %q, %v", name, got, err)
+		}
+	}
+}
+
+func TestFieldCipher_TamperedValueFails(t *testing.T) {
+	c := newTestCipher(t, map[int]string{1: testSecretV1}, 1)
+	stored, version, err := c.encrypt("secret")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	tampered := []byte(stored)
+	tampered[len(tampered)/2] ^= 1
+	if _, err := c.decrypt(string(tampered), version); err == nil {
+		t.Errorf("expected tampered value to fail authentication")
+	}
+}
+
+func TestNewFieldCipher_RejectsUnknownActiveVersion(t *testing.T) {
+	if _, err := NewFieldCipher(map[int]string{1: testSecretV1}, 2); !errors.Is(err, ErrUnknownKeyVersion) {
+		t.Errorf("expected ErrUnknownKeyVersion, got %v", err)
+	}
+}
+
+func TestResponseContent_EncryptedAtRest(t *testing.T) {
+	cipher := newTestCipher(t, map[int]string{1: testSecretV1}, 1)
+
+	var storedContent string
+	var storedVersion *int16
+	q := stubQuerier{
+		execFn: func(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+			storedContent = args[2].(string)
+			storedVersion = args[3].(*int16)
+			return pgconn.NewCommandTag("INSERT 0 1"), nil
+		},
+		queryRowFn: func(_ context.Context, _ string, _ ...any) pgx.Row {
+			return stubRow{scanFn: func(dest ...any) error {
+				*dest[2].(*string) = storedContent
+				*dest[3].(**int16) = storedVersion
+				return nil
+			}}
+		},
+	}
+	repo := NewTxScoped(q, WithFieldCipher(cipher))
+	requestID := uuid.New()
+
+	if err := repo.CreateResponse(context.Background(), &models.Response{RequestID: requestID, Content: "sinus rhythm"}); err != nil {
+		t.Fatalf("CreateResponse: %v", err)
+	}
+	if storedVersion == nil || strings.Contains(storedContent, "sinus") {
+		t.Fatalf("expected encrypted content with a key version, stored %q (version %v)", storedContent, storedVersion)
+	}
+
+	resp, err := repo.GetResponseByRequestID(context.Background(), requestID)
+	if err != nil {
+		t.Fatalf("GetResponseByRequestID: %v", err)
+	}
+	if resp.Content != "sinus rhythm" {
+		t.Errorf("expected decrypted content, got %q", resp.Content)
+	}
+}
+
+func TestWithTx_KeepsFieldCipher(t *testing.T) {
+	cipher := newTestCipher(t, map[int]string{1: testSecretV1}, 1)
+	repo := NewTxScoped(stubQuerier{}, WithFieldCipher(cipher))
+
+	if txRepo := repo.WithTx(nil).(*Repository); txRepo.cipher != cipher {
+		t.Errorf("expected the transaction repository to keep the field cipher")
+	}
+}
@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
+	"github.com/fedutinova/smartheart/back-api/apperr"
 	"github.com/fedutinova/smartheart/back-api/models"
 )
 
@@ -16,8 +20,8 @@ func (r *Repository) CreateFile(ctx context.Context, file *models.File) error {
 	}
 
 	query := `
-		INSERT INTO files (id, request_id, original_filename, file_type, file_size, s3_bucket, s3_key, s3_url, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		INSERT INTO files (id, request_id, original_filename, file_type, file_size, s3_bucket, s3_key, s3_url, checksum, thumbnail_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW())
 	`
 
 	_, err := r.querier.Exec(ctx, query,
@@ -29,6 +33,8 @@ func (r *Repository) CreateFile(ctx context.Context, file *models.File) error {
 		file.S3Bucket,
 		file.S3Key,
 		file.S3URL,
+		file.Checksum,
+		file.ThumbnailKey,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
@@ -36,10 +42,50 @@ func (r *Repository) CreateFile(ctx context.Context, file *models.File) error {
 	return nil
 }
 
+// CreateFiles inserts file records in a single multi-row statement, assigning
+// IDs to files that have none.
+func (r *Repository) CreateFiles(ctx context.Context, files []*models.File) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	rows := make([]string, len(files))
+	args := make([]any, 0, len(files)*10)
+	for i, file := range files {
+		if file.ID == uuid.Nil {
+			file.ID = uuid.New()
+		}
+		n := len(args)
+		rows[i] = fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, NOW())",
+			n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10)
+		args = append(args,
+			file.ID,
+			file.RequestID,
+			file.OriginalFilename,
+			file.FileType,
+			file.FileSize,
+			file.S3Bucket,
+			file.S3Key,
+			file.S3URL,
+			file.Checksum,
+			file.ThumbnailKey,
+		)
+	}
+
+	query := `
+		INSERT INTO files (id, request_id, original_filename, file_type, file_size, s3_bucket, s3_key, s3_url, checksum, thumbnail_key, created_at)
+		VALUES ` + strings.Join(rows, ", ")
+
+	if _, err := r.querier.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to create files: %w", err)
+	}
+	return nil
+}
+
 // GetFilesByRequestID retrieves all files for a request.
 func (r *Repository) GetFilesByRequestID(ctx context.Context, requestID uuid.UUID) ([]models.File, error) {
 	query := `
-		SELECT id, request_id, original_filename, file_type, file_size, s3_bucket, s3_key, s3_url, created_at
+		SELECT id, request_id, original_filename, file_type, file_size, s3_bucket, s3_key, s3_url, checksum, thumbnail_key, created_at
 		FROM files
 		WHERE request_id = $1
 		ORDER BY created_at
@@ -63,6 +109,8 @@ func (r *Repository) GetFilesByRequestID(ctx context.Context, requestID uuid.UUI
 			&file.S3Bucket,
 			&file.S3Key,
 			&file.S3URL,
+			&file.Checksum,
+			&file.ThumbnailKey,
 			&file.CreatedAt,
 		)
 		if err != nil {
@@ -76,3 +124,95 @@ func (r *Repository) GetFilesByRequestID(ctx context.Context, requestID uuid.UUI
 	}
 	return files, nil
 }
+
+// GetFileKeysByRequestID returns the storage keys of a request's files in
+// upload order, skipping files that were never stored.
+func (r *Repository) GetFileKeysByRequestID(ctx context.Context, requestID uuid.UUID) ([]string, error) {
+	rows, err := r.querier.Query(ctx,
+		`SELECT s3_key FROM files WHERE request_id = $1 AND s3_key <> '' ORDER BY created_at`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("scan file key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate file key rows: %w", err)
+	}
+	return keys, nil
+}
+
+// GetFileByID retrieves a file record together with the ID of the user who
+// owns its parent request.
+func (r *Repository) GetFileByID(ctx context.Context, id uuid.UUID) (*models.File, uuid.UUID, error) {
+	query := `
+		SELECT f.id, f.request_id, f.original_filename, f.file_type, f.file_size, f.s3_bucket, f.s3_key, f.s3_url, f.checksum, f.thumbnail_key, f.created_at,
+		       r.user_id
+		FROM files f
+		JOIN requests r ON r.id = f.request_id
+		WHERE f.id = $1 AND r.deleted_at IS NULL
+	`
+
+	var (
+		file    models.File
+		ownerID uuid.UUID
+	)
+	err := r.querier.QueryRow(ctx, query, id).Scan(
+		&file.ID,
+		&file.RequestID,
+		&file.OriginalFilename,
+		&file.FileType,
+		&file.FileSize,
+		&file.S3Bucket,
+		&file.S3Key,
+		&file.S3URL,
+		&file.Checksum,
+		&file.ThumbnailKey,
+		&file.CreatedAt,
+		&ownerID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, uuid.Nil, apperr.ErrFileNotFound
+		}
+		return nil, uuid.Nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	return &file, ownerID, nil
+}
+
+// ExistingFileKeys returns the subset of keys that are referenced by a files
+// row, either as the stored object or as its thumbnail.
+func (r *Repository) ExistingFileKeys(ctx context.Context, keys []string) (map[string]struct{}, error) {
+	existing := make(map[string]struct{}, len(keys))
+	if len(keys) == 0 {
+		return existing, nil
+	}
+
+	rows, err := r.querier.Query(ctx, `
+		SELECT s3_key FROM files WHERE s3_key = ANY($1)
+		UNION
+		SELECT thumbnail_key FROM files WHERE thumbnail_key = ANY($1)`, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file keys: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("scan file key: %w", err)
+		}
+		existing[key] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate file key rows: %w", err)
+	}
+	return existing, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+// filesTable is a stubQuerier backing store for the files table: it records
+// the rows of INSERT statements and serves them to SELECTs by request ID.
+type filesTable struct {
+	rows  [][]any
+	execs int
+}
+
+func (ft *filesTable) querier() stubQuerier {
+	return stubQuerier{
+		execFn: func(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+			ft.execs++
+			if !strings.Contains(sql, "INSERT INTO files") {
+				return pgconn.CommandTag{}, errStubQuery
+			}
+			for i := 0; i < len(args); i += 10 {
+				ft.rows = append(ft.rows, append(append([]any{}, args[i:i+10]...), time.Now()))
+			}
+			return pgconn.NewCommandTag("INSERT 0 1"), nil
+		},
+		queryFn: func(_ context.Context, _ string, args ...any) (pgx.Rows, error) {
+			var matched [][]any
+			for _, row := range ft.rows {
+				if row[1] == args[0] {
+					matched = append(matched, row)
+				}
+			}
+			return &stubRows{rows: matched}, nil
+		},
+	}
+}
+
+func TestCreateFiles_SingleStatement(t *testing.T) {
+	table := &filesTable{}
+	repo := NewTxScoped(table.querier())
+	requestID := uuid.New()
+
+	files := []*models.File{
+		{RequestID: requestID, OriginalFilename: "a.pdf", FileType: "application/pdf", FileSize: 10, S3Key: "uploads/a.pdf"},
+		{RequestID: requestID, OriginalFilename: "b.png", FileType: "image/png", FileSize: 20, S3Key: "uploads/b.png", ThumbnailKey: "thumbs/b.jpg"},
+		{RequestID: requestID, OriginalFilename: "c.txt", FileType: "text/plain", FileSize: 30, S3Key: "uploads/c.txt", Checksum: "abc"},
+	}
+	require.NoError(t, repo.CreateFiles(context.Background(), files))
+	assert.Equal(t, 1, table.execs, "expected a single INSERT for all files")
+
+	got, err := repo.GetFilesByRequestID(context.Background(), requestID)
+	require.NoError(t, err)
+	require.Len(t, got, len(files))
+	for i, f := range files {
+		assert.NotEqual(t, uuid.Nil, f.ID)
+		assert.Equal(t, f.ID, got[i].ID)
+		assert.Equal(t, f.OriginalFilename, got[i].OriginalFilename)
+		assert.Equal(t, f.S3Key, got[i].S3Key)
+		assert.Equal(t, f.ThumbnailKey, got[i].ThumbnailKey)
+		assert.Equal(t, f.Checksum, got[i].Checksum)
+	}
+}
+
+func TestCreateFiles_EmptyIsNoop(t *testing.T) {
+	table := &filesTable{}
+	require.NoError(t, NewTxScoped(table.querier()).CreateFiles(context.Background(), nil))
+	assert.Zero(t, table.execs)
+}
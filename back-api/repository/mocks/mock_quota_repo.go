@@ -23,6 +23,63 @@ func (_m *MockQuotaRepo) EXPECT() *MockQuotaRepo_Expecter {
 	return &MockQuotaRepo_Expecter{mock: &_m.Mock}
 }
 
+// CountActiveRequests provides a mock function with given fields: ctx, userID
+func (_m *MockQuotaRepo) CountActiveRequests(ctx context.Context, userID uuid.UUID) (int, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountActiveRequests")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuotaRepo_CountActiveRequests_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountActiveRequests'
+type MockQuotaRepo_CountActiveRequests_Call struct {
+	*mock.Call
+}
+
+// CountActiveRequests is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockQuotaRepo_Expecter) CountActiveRequests(ctx interface{}, userID interface{}) *MockQuotaRepo_CountActiveRequests_Call {
+	return &MockQuotaRepo_CountActiveRequests_Call{Call: _e.mock.On("CountActiveRequests", ctx, userID)}
+}
+
+func (_c *MockQuotaRepo_CountActiveRequests_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockQuotaRepo_CountActiveRequests_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockQuotaRepo_CountActiveRequests_Call) Return(_a0 int, _a1 error) *MockQuotaRepo_CountActiveRequests_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuotaRepo_CountActiveRequests_Call) RunAndReturn(run func(context.Context, uuid.UUID) (int, error)) *MockQuotaRepo_CountActiveRequests_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DecrementFreeAnalysesUsed provides a mock function with given fields: ctx, userID
 func (_m *MockQuotaRepo) DecrementFreeAnalysesUsed(ctx context.Context, userID uuid.UUID) error {
 	ret := _m.Called(ctx, userID)
@@ -6,9 +6,9 @@ import (
 	context "context"
 
 	models "github.com/fedutinova/smartheart/back-api/models"
-	mock "github.com/stretchr/testify/mock"
-
+	repository "github.com/fedutinova/smartheart/back-api/repository"
 	uuid "github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
 )
 
 // MockRequestRepo is an autogenerated mock type for the RequestRepo type
@@ -24,23 +24,25 @@ func (_m *MockRequestRepo) EXPECT() *MockRequestRepo_Expecter {
 	return &MockRequestRepo_Expecter{mock: &_m.Mock}
 }
 
-// CountRequestsByUserID provides a mock function with given fields: ctx, userID
-func (_m *MockRequestRepo) CountRequestsByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+// CountRequestsByStatus provides a mock function with given fields: ctx, userID
+func (_m *MockRequestRepo) CountRequestsByStatus(ctx context.Context, userID uuid.UUID) (map[string]int, error) {
 	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CountRequestsByUserID")
+		panic("no return value specified for CountRequestsByStatus")
 	}
 
-	var r0 int
+	var r0 map[string]int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (map[string]int, error)); ok {
 		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) map[string]int); ok {
 		r0 = rf(ctx, userID)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
@@ -52,6 +54,63 @@ func (_m *MockRequestRepo) CountRequestsByUserID(ctx context.Context, userID uui
 	return r0, r1
 }
 
+// MockRequestRepo_CountRequestsByStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountRequestsByStatus'
+type MockRequestRepo_CountRequestsByStatus_Call struct {
+	*mock.Call
+}
+
+// CountRequestsByStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockRequestRepo_Expecter) CountRequestsByStatus(ctx interface{}, userID interface{}) *MockRequestRepo_CountRequestsByStatus_Call {
+	return &MockRequestRepo_CountRequestsByStatus_Call{Call: _e.mock.On("CountRequestsByStatus", ctx, userID)}
+}
+
+func (_c *MockRequestRepo_CountRequestsByStatus_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockRequestRepo_CountRequestsByStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockRequestRepo_CountRequestsByStatus_Call) Return(_a0 map[string]int, _a1 error) *MockRequestRepo_CountRequestsByStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRequestRepo_CountRequestsByStatus_Call) RunAndReturn(run func(context.Context, uuid.UUID) (map[string]int, error)) *MockRequestRepo_CountRequestsByStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountRequestsByUserID provides a mock function with given fields: ctx, userID, filter
+func (_m *MockRequestRepo) CountRequestsByUserID(ctx context.Context, userID uuid.UUID, filter repository.RequestFilter) (int, error) {
+	ret := _m.Called(ctx, userID, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountRequestsByUserID")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, repository.RequestFilter) (int, error)); ok {
+		return rf(ctx, userID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, repository.RequestFilter) int); ok {
+		r0 = rf(ctx, userID, filter)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, repository.RequestFilter) error); ok {
+		r1 = rf(ctx, userID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // MockRequestRepo_CountRequestsByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountRequestsByUserID'
 type MockRequestRepo_CountRequestsByUserID_Call struct {
 	*mock.Call
@@ -60,13 +119,14 @@ type MockRequestRepo_CountRequestsByUserID_Call struct {
 // CountRequestsByUserID is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userID uuid.UUID
-func (_e *MockRequestRepo_Expecter) CountRequestsByUserID(ctx interface{}, userID interface{}) *MockRequestRepo_CountRequestsByUserID_Call {
-	return &MockRequestRepo_CountRequestsByUserID_Call{Call: _e.mock.On("CountRequestsByUserID", ctx, userID)}
+//   - filter repository.RequestFilter
+func (_e *MockRequestRepo_Expecter) CountRequestsByUserID(ctx interface{}, userID interface{}, filter interface{}) *MockRequestRepo_CountRequestsByUserID_Call {
+	return &MockRequestRepo_CountRequestsByUserID_Call{Call: _e.mock.On("CountRequestsByUserID", ctx, userID, filter)}
 }
 
-func (_c *MockRequestRepo_CountRequestsByUserID_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockRequestRepo_CountRequestsByUserID_Call {
+func (_c *MockRequestRepo_CountRequestsByUserID_Call) Run(run func(ctx context.Context, userID uuid.UUID, filter repository.RequestFilter)) *MockRequestRepo_CountRequestsByUserID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(repository.RequestFilter))
 	})
 	return _c
 }
@@ -76,7 +136,7 @@ func (_c *MockRequestRepo_CountRequestsByUserID_Call) Return(_a0 int, _a1 error)
 	return _c
 }
 
-func (_c *MockRequestRepo_CountRequestsByUserID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (int, error)) *MockRequestRepo_CountRequestsByUserID_Call {
+func (_c *MockRequestRepo_CountRequestsByUserID_Call) RunAndReturn(run func(context.Context, uuid.UUID, repository.RequestFilter) (int, error)) *MockRequestRepo_CountRequestsByUserID_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -128,6 +188,53 @@ func (_c *MockRequestRepo_CreateFile_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// CreateFiles provides a mock function with given fields: ctx, files
+func (_m *MockRequestRepo) CreateFiles(ctx context.Context, files []*models.File) error {
+	ret := _m.Called(ctx, files)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateFiles")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.File) error); ok {
+		r0 = rf(ctx, files)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRequestRepo_CreateFiles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateFiles'
+type MockRequestRepo_CreateFiles_Call struct {
+	*mock.Call
+}
+
+// CreateFiles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - files []*models.File
+func (_e *MockRequestRepo_Expecter) CreateFiles(ctx interface{}, files interface{}) *MockRequestRepo_CreateFiles_Call {
+	return &MockRequestRepo_CreateFiles_Call{Call: _e.mock.On("CreateFiles", ctx, files)}
+}
+
+func (_c *MockRequestRepo_CreateFiles_Call) Run(run func(ctx context.Context, files []*models.File)) *MockRequestRepo_CreateFiles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*models.File))
+	})
+	return _c
+}
+
+func (_c *MockRequestRepo_CreateFiles_Call) Return(_a0 error) *MockRequestRepo_CreateFiles_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRequestRepo_CreateFiles_Call) RunAndReturn(run func(context.Context, []*models.File) error) *MockRequestRepo_CreateFiles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateRequest provides a mock function with given fields: ctx, req
 func (_m *MockRequestRepo) CreateRequest(ctx context.Context, req *models.Request) error {
 	ret := _m.Called(ctx, req)
@@ -222,6 +329,299 @@ func (_c *MockRequestRepo_CreateResponse_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// ExistingFileKeys provides a mock function with given fields: ctx, keys
+func (_m *MockRequestRepo) ExistingFileKeys(ctx context.Context, keys []string) (map[string]struct{}, error) {
+	ret := _m.Called(ctx, keys)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExistingFileKeys")
+	}
+
+	var r0 map[string]struct{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]struct{}, error)); ok {
+		return rf(ctx, keys)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]struct{}); ok {
+		r0 = rf(ctx, keys)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]struct{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, keys)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRequestRepo_ExistingFileKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExistingFileKeys'
+type MockRequestRepo_ExistingFileKeys_Call struct {
+	*mock.Call
+}
+
+// ExistingFileKeys is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keys []string
+func (_e *MockRequestRepo_Expecter) ExistingFileKeys(ctx interface{}, keys interface{}) *MockRequestRepo_ExistingFileKeys_Call {
+	return &MockRequestRepo_ExistingFileKeys_Call{Call: _e.mock.On("ExistingFileKeys", ctx, keys)}
+}
+
+func (_c *MockRequestRepo_ExistingFileKeys_Call) Run(run func(ctx context.Context, keys []string)) *MockRequestRepo_ExistingFileKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *MockRequestRepo_ExistingFileKeys_Call) Return(_a0 map[string]struct{}, _a1 error) *MockRequestRepo_ExistingFileKeys_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRequestRepo_ExistingFileKeys_Call) RunAndReturn(run func(context.Context, []string) (map[string]struct{}, error)) *MockRequestRepo_ExistingFileKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FailRequest provides a mock function with given fields: ctx, requestID, code
+func (_m *MockRequestRepo) FailRequest(ctx context.Context, requestID uuid.UUID, code string) error {
+	ret := _m.Called(ctx, requestID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FailRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, requestID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRequestRepo_FailRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FailRequest'
+type MockRequestRepo_FailRequest_Call struct {
+	*mock.Call
+}
+
+// FailRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - requestID uuid.UUID
+//   - code string
+func (_e *MockRequestRepo_Expecter) FailRequest(ctx interface{}, requestID interface{}, code interface{}) *MockRequestRepo_FailRequest_Call {
+	return &MockRequestRepo_FailRequest_Call{Call: _e.mock.On("FailRequest", ctx, requestID, code)}
+}
+
+func (_c *MockRequestRepo_FailRequest_Call) Run(run func(ctx context.Context, requestID uuid.UUID, code string)) *MockRequestRepo_FailRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockRequestRepo_FailRequest_Call) Return(_a0 error) *MockRequestRepo_FailRequest_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRequestRepo_FailRequest_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockRequestRepo_FailRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetChildRequests provides a mock function with given fields: ctx, parentID
+func (_m *MockRequestRepo) GetChildRequests(ctx context.Context, parentID uuid.UUID) ([]models.Request, error) {
+	ret := _m.Called(ctx, parentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChildRequests")
+	}
+
+	var r0 []models.Request
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]models.Request, error)); ok {
+		return rf(ctx, parentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []models.Request); ok {
+		r0 = rf(ctx, parentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Request)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, parentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRequestRepo_GetChildRequests_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetChildRequests'
+type MockRequestRepo_GetChildRequests_Call struct {
+	*mock.Call
+}
+
+// GetChildRequests is a helper method to define mock.On call
+//   - ctx context.Context
+//   - parentID uuid.UUID
+func (_e *MockRequestRepo_Expecter) GetChildRequests(ctx interface{}, parentID interface{}) *MockRequestRepo_GetChildRequests_Call {
+	return &MockRequestRepo_GetChildRequests_Call{Call: _e.mock.On("GetChildRequests", ctx, parentID)}
+}
+
+func (_c *MockRequestRepo_GetChildRequests_Call) Run(run func(ctx context.Context, parentID uuid.UUID)) *MockRequestRepo_GetChildRequests_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockRequestRepo_GetChildRequests_Call) Return(_a0 []models.Request, _a1 error) *MockRequestRepo_GetChildRequests_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRequestRepo_GetChildRequests_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]models.Request, error)) *MockRequestRepo_GetChildRequests_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFileByID provides a mock function with given fields: ctx, id
+func (_m *MockRequestRepo) GetFileByID(ctx context.Context, id uuid.UUID) (*models.File, uuid.UUID, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFileByID")
+	}
+
+	var r0 *models.File
+	var r1 uuid.UUID
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.File, uuid.UUID, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.File); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) uuid.UUID); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID) error); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockRequestRepo_GetFileByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFileByID'
+type MockRequestRepo_GetFileByID_Call struct {
+	*mock.Call
+}
+
+// GetFileByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockRequestRepo_Expecter) GetFileByID(ctx interface{}, id interface{}) *MockRequestRepo_GetFileByID_Call {
+	return &MockRequestRepo_GetFileByID_Call{Call: _e.mock.On("GetFileByID", ctx, id)}
+}
+
+func (_c *MockRequestRepo_GetFileByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockRequestRepo_GetFileByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockRequestRepo_GetFileByID_Call) Return(_a0 *models.File, _a1 uuid.UUID, _a2 error) *MockRequestRepo_GetFileByID_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockRequestRepo_GetFileByID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.File, uuid.UUID, error)) *MockRequestRepo_GetFileByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFileKeysByRequestID provides a mock function with given fields: ctx, requestID
+func (_m *MockRequestRepo) GetFileKeysByRequestID(ctx context.Context, requestID uuid.UUID) ([]string, error) {
+	ret := _m.Called(ctx, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFileKeysByRequestID")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]string, error)); ok {
+		return rf(ctx, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []string); ok {
+		r0 = rf(ctx, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRequestRepo_GetFileKeysByRequestID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFileKeysByRequestID'
+type MockRequestRepo_GetFileKeysByRequestID_Call struct {
+	*mock.Call
+}
+
+// GetFileKeysByRequestID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - requestID uuid.UUID
+func (_e *MockRequestRepo_Expecter) GetFileKeysByRequestID(ctx interface{}, requestID interface{}) *MockRequestRepo_GetFileKeysByRequestID_Call {
+	return &MockRequestRepo_GetFileKeysByRequestID_Call{Call: _e.mock.On("GetFileKeysByRequestID", ctx, requestID)}
+}
+
+func (_c *MockRequestRepo_GetFileKeysByRequestID_Call) Run(run func(ctx context.Context, requestID uuid.UUID)) *MockRequestRepo_GetFileKeysByRequestID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockRequestRepo_GetFileKeysByRequestID_Call) Return(_a0 []string, _a1 error) *MockRequestRepo_GetFileKeysByRequestID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRequestRepo_GetFileKeysByRequestID_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]string, error)) *MockRequestRepo_GetFileKeysByRequestID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetFilesByRequestID provides a mock function with given fields: ctx, requestID
 func (_m *MockRequestRepo) GetFilesByRequestID(ctx context.Context, requestID uuid.UUID) ([]models.File, error) {
 	ret := _m.Called(ctx, requestID)
@@ -400,9 +800,9 @@ func (_c *MockRequestRepo_GetRequestByID_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
-// GetRequestsByUserID provides a mock function with given fields: ctx, userID, limit, offset
-func (_m *MockRequestRepo) GetRequestsByUserID(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]models.Request, error) {
-	ret := _m.Called(ctx, userID, limit, offset)
+// GetRequestsByUserID provides a mock function with given fields: ctx, userID, filter
+func (_m *MockRequestRepo) GetRequestsByUserID(ctx context.Context, userID uuid.UUID, filter repository.RequestFilter) ([]models.Request, error) {
+	ret := _m.Called(ctx, userID, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetRequestsByUserID")
@@ -410,19 +810,19 @@ func (_m *MockRequestRepo) GetRequestsByUserID(ctx context.Context, userID uuid.
 
 	var r0 []models.Request
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) ([]models.Request, error)); ok {
-		return rf(ctx, userID, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, repository.RequestFilter) ([]models.Request, error)); ok {
+		return rf(ctx, userID, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) []models.Request); ok {
-		r0 = rf(ctx, userID, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, repository.RequestFilter) []models.Request); ok {
+		r0 = rf(ctx, userID, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Request)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, int) error); ok {
-		r1 = rf(ctx, userID, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, repository.RequestFilter) error); ok {
+		r1 = rf(ctx, userID, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -438,15 +838,14 @@ type MockRequestRepo_GetRequestsByUserID_Call struct {
 // GetRequestsByUserID is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userID uuid.UUID
-//   - limit int
-//   - offset int
-func (_e *MockRequestRepo_Expecter) GetRequestsByUserID(ctx interface{}, userID interface{}, limit interface{}, offset interface{}) *MockRequestRepo_GetRequestsByUserID_Call {
-	return &MockRequestRepo_GetRequestsByUserID_Call{Call: _e.mock.On("GetRequestsByUserID", ctx, userID, limit, offset)}
+//   - filter repository.RequestFilter
+func (_e *MockRequestRepo_Expecter) GetRequestsByUserID(ctx interface{}, userID interface{}, filter interface{}) *MockRequestRepo_GetRequestsByUserID_Call {
+	return &MockRequestRepo_GetRequestsByUserID_Call{Call: _e.mock.On("GetRequestsByUserID", ctx, userID, filter)}
 }
 
-func (_c *MockRequestRepo_GetRequestsByUserID_Call) Run(run func(ctx context.Context, userID uuid.UUID, limit int, offset int)) *MockRequestRepo_GetRequestsByUserID_Call {
+func (_c *MockRequestRepo_GetRequestsByUserID_Call) Run(run func(ctx context.Context, userID uuid.UUID, filter repository.RequestFilter)) *MockRequestRepo_GetRequestsByUserID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(int), args[3].(int))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(repository.RequestFilter))
 	})
 	return _c
 }
@@ -456,7 +855,7 @@ func (_c *MockRequestRepo_GetRequestsByUserID_Call) Return(_a0 []models.Request,
 	return _c
 }
 
-func (_c *MockRequestRepo_GetRequestsByUserID_Call) RunAndReturn(run func(context.Context, uuid.UUID, int, int) ([]models.Request, error)) *MockRequestRepo_GetRequestsByUserID_Call {
+func (_c *MockRequestRepo_GetRequestsByUserID_Call) RunAndReturn(run func(context.Context, uuid.UUID, repository.RequestFilter) ([]models.Request, error)) *MockRequestRepo_GetRequestsByUserID_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -520,6 +919,209 @@ func (_c *MockRequestRepo_GetResponseByRequestID_Call) RunAndReturn(run func(con
 	return _c
 }
 
+// MarkRequestQueued provides a mock function with given fields: ctx, requestID
+func (_m *MockRequestRepo) MarkRequestQueued(ctx context.Context, requestID uuid.UUID) error {
+	ret := _m.Called(ctx, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkRequestQueued")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRequestRepo_MarkRequestQueued_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkRequestQueued'
+type MockRequestRepo_MarkRequestQueued_Call struct {
+	*mock.Call
+}
+
+// MarkRequestQueued is a helper method to define mock.On call
+//   - ctx context.Context
+//   - requestID uuid.UUID
+func (_e *MockRequestRepo_Expecter) MarkRequestQueued(ctx interface{}, requestID interface{}) *MockRequestRepo_MarkRequestQueued_Call {
+	return &MockRequestRepo_MarkRequestQueued_Call{Call: _e.mock.On("MarkRequestQueued", ctx, requestID)}
+}
+
+func (_c *MockRequestRepo_MarkRequestQueued_Call) Run(run func(ctx context.Context, requestID uuid.UUID)) *MockRequestRepo_MarkRequestQueued_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockRequestRepo_MarkRequestQueued_Call) Return(_a0 error) *MockRequestRepo_MarkRequestQueued_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRequestRepo_MarkRequestQueued_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockRequestRepo_MarkRequestQueued_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SoftDeleteRequest provides a mock function with given fields: ctx, requestID
+func (_m *MockRequestRepo) SoftDeleteRequest(ctx context.Context, requestID uuid.UUID) error {
+	ret := _m.Called(ctx, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SoftDeleteRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRequestRepo_SoftDeleteRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SoftDeleteRequest'
+type MockRequestRepo_SoftDeleteRequest_Call struct {
+	*mock.Call
+}
+
+// SoftDeleteRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - requestID uuid.UUID
+func (_e *MockRequestRepo_Expecter) SoftDeleteRequest(ctx interface{}, requestID interface{}) *MockRequestRepo_SoftDeleteRequest_Call {
+	return &MockRequestRepo_SoftDeleteRequest_Call{Call: _e.mock.On("SoftDeleteRequest", ctx, requestID)}
+}
+
+func (_c *MockRequestRepo_SoftDeleteRequest_Call) Run(run func(ctx context.Context, requestID uuid.UUID)) *MockRequestRepo_SoftDeleteRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockRequestRepo_SoftDeleteRequest_Call) Return(_a0 error) *MockRequestRepo_SoftDeleteRequest_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRequestRepo_SoftDeleteRequest_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockRequestRepo_SoftDeleteRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TransitionRequestStatus provides a mock function with given fields: ctx, requestID, from, to
+func (_m *MockRequestRepo) TransitionRequestStatus(ctx context.Context, requestID uuid.UUID, from string, to string) (bool, error) {
+	ret := _m.Called(ctx, requestID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransitionRequestStatus")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) (bool, error)); ok {
+		return rf(ctx, requestID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) bool); ok {
+		r0 = rf(ctx, requestID, from, to)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, string) error); ok {
+		r1 = rf(ctx, requestID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRequestRepo_TransitionRequestStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TransitionRequestStatus'
+type MockRequestRepo_TransitionRequestStatus_Call struct {
+	*mock.Call
+}
+
+// TransitionRequestStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - requestID uuid.UUID
+//   - from string
+//   - to string
+func (_e *MockRequestRepo_Expecter) TransitionRequestStatus(ctx interface{}, requestID interface{}, from interface{}, to interface{}) *MockRequestRepo_TransitionRequestStatus_Call {
+	return &MockRequestRepo_TransitionRequestStatus_Call{Call: _e.mock.On("TransitionRequestStatus", ctx, requestID, from, to)}
+}
+
+func (_c *MockRequestRepo_TransitionRequestStatus_Call) Run(run func(ctx context.Context, requestID uuid.UUID, from string, to string)) *MockRequestRepo_TransitionRequestStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockRequestRepo_TransitionRequestStatus_Call) Return(_a0 bool, _a1 error) *MockRequestRepo_TransitionRequestStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRequestRepo_TransitionRequestStatus_Call) RunAndReturn(run func(context.Context, uuid.UUID, string, string) (bool, error)) *MockRequestRepo_TransitionRequestStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCallbackStatus provides a mock function with given fields: ctx, requestID, status, attempts, lastErr
+func (_m *MockRequestRepo) UpdateCallbackStatus(ctx context.Context, requestID uuid.UUID, status string, attempts int, lastErr string) error {
+	ret := _m.Called(ctx, requestID, status, attempts, lastErr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCallbackStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, int, string) error); ok {
+		r0 = rf(ctx, requestID, status, attempts, lastErr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRequestRepo_UpdateCallbackStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCallbackStatus'
+type MockRequestRepo_UpdateCallbackStatus_Call struct {
+	*mock.Call
+}
+
+// UpdateCallbackStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - requestID uuid.UUID
+//   - status string
+//   - attempts int
+//   - lastErr string
+func (_e *MockRequestRepo_Expecter) UpdateCallbackStatus(ctx interface{}, requestID interface{}, status interface{}, attempts interface{}, lastErr interface{}) *MockRequestRepo_UpdateCallbackStatus_Call {
+	return &MockRequestRepo_UpdateCallbackStatus_Call{Call: _e.mock.On("UpdateCallbackStatus", ctx, requestID, status, attempts, lastErr)}
+}
+
+func (_c *MockRequestRepo_UpdateCallbackStatus_Call) Run(run func(ctx context.Context, requestID uuid.UUID, status string, attempts int, lastErr string)) *MockRequestRepo_UpdateCallbackStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string), args[3].(int), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockRequestRepo_UpdateCallbackStatus_Call) Return(_a0 error) *MockRequestRepo_UpdateCallbackStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRequestRepo_UpdateCallbackStatus_Call) RunAndReturn(run func(context.Context, uuid.UUID, string, int, string) error) *MockRequestRepo_UpdateCallbackStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateRequestStatus provides a mock function with given fields: ctx, requestID, status
 func (_m *MockRequestRepo) UpdateRequestStatus(ctx context.Context, requestID uuid.UUID, status string) error {
 	ret := _m.Called(ctx, requestID, status)
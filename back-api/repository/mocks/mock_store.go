@@ -28,14 +28,19 @@ func (_m *MockStore) EXPECT() *MockStore_Expecter {
 
 // ActivateSubscription provides a mock function with given fields: ctx, userID
 func (_m *MockStore) ActivateSubscription(ctx context.Context, userID uuid.UUID) error {
-	_va := []interface{}{ctx, userID}
-	ret := _m.Called(_va...)
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ActivateSubscription")
+	}
+
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
 		r0 = rf(ctx, userID)
 	} else {
 		r0 = ret.Error(0)
 	}
+
 	return r0
 }
 
@@ -45,6 +50,8 @@ type MockStore_ActivateSubscription_Call struct {
 }
 
 // ActivateSubscription is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
 func (_e *MockStore_Expecter) ActivateSubscription(ctx interface{}, userID interface{}) *MockStore_ActivateSubscription_Call {
 	return &MockStore_ActivateSubscription_Call{Call: _e.mock.On("ActivateSubscription", ctx, userID)}
 }
@@ -114,6 +121,54 @@ func (_c *MockStore_AssignRoleToUser_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// AttachPermission provides a mock function with given fields: ctx, roleName, permName
+func (_m *MockStore) AttachPermission(ctx context.Context, roleName string, permName string) error {
+	ret := _m.Called(ctx, roleName, permName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachPermission")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, roleName, permName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_AttachPermission_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AttachPermission'
+type MockStore_AttachPermission_Call struct {
+	*mock.Call
+}
+
+// AttachPermission is a helper method to define mock.On call
+//   - ctx context.Context
+//   - roleName string
+//   - permName string
+func (_e *MockStore_Expecter) AttachPermission(ctx interface{}, roleName interface{}, permName interface{}) *MockStore_AttachPermission_Call {
+	return &MockStore_AttachPermission_Call{Call: _e.mock.On("AttachPermission", ctx, roleName, permName)}
+}
+
+func (_c *MockStore_AttachPermission_Call) Run(run func(ctx context.Context, roleName string, permName string)) *MockStore_AttachPermission_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_AttachPermission_Call) Return(_a0 error) *MockStore_AttachPermission_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_AttachPermission_Call) RunAndReturn(run func(context.Context, string, string) error) *MockStore_AttachPermission_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CancelPayment provides a mock function with given fields: ctx, yookassaID
 func (_m *MockStore) CancelPayment(ctx context.Context, yookassaID string) error {
 	ret := _m.Called(ctx, yookassaID)
@@ -265,12 +320,12 @@ func (_c *MockStore_ConfirmPayment_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
-// CountRequestsByUserID provides a mock function with given fields: ctx, userID
-func (_m *MockStore) CountRequestsByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+// CountActiveRequests provides a mock function with given fields: ctx, userID
+func (_m *MockStore) CountActiveRequests(ctx context.Context, userID uuid.UUID) (int, error) {
 	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CountRequestsByUserID")
+		panic("no return value specified for CountActiveRequests")
 	}
 
 	var r0 int
@@ -293,6 +348,122 @@ func (_m *MockStore) CountRequestsByUserID(ctx context.Context, userID uuid.UUID
 	return r0, r1
 }
 
+// MockStore_CountActiveRequests_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountActiveRequests'
+type MockStore_CountActiveRequests_Call struct {
+	*mock.Call
+}
+
+// CountActiveRequests is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockStore_Expecter) CountActiveRequests(ctx interface{}, userID interface{}) *MockStore_CountActiveRequests_Call {
+	return &MockStore_CountActiveRequests_Call{Call: _e.mock.On("CountActiveRequests", ctx, userID)}
+}
+
+func (_c *MockStore_CountActiveRequests_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_CountActiveRequests_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockStore_CountActiveRequests_Call) Return(_a0 int, _a1 error) *MockStore_CountActiveRequests_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_CountActiveRequests_Call) RunAndReturn(run func(context.Context, uuid.UUID) (int, error)) *MockStore_CountActiveRequests_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountRequestsByStatus provides a mock function with given fields: ctx, userID
+func (_m *MockStore) CountRequestsByStatus(ctx context.Context, userID uuid.UUID) (map[string]int, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountRequestsByStatus")
+	}
+
+	var r0 map[string]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (map[string]int, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) map[string]int); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_CountRequestsByStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountRequestsByStatus'
+type MockStore_CountRequestsByStatus_Call struct {
+	*mock.Call
+}
+
+// CountRequestsByStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockStore_Expecter) CountRequestsByStatus(ctx interface{}, userID interface{}) *MockStore_CountRequestsByStatus_Call {
+	return &MockStore_CountRequestsByStatus_Call{Call: _e.mock.On("CountRequestsByStatus", ctx, userID)}
+}
+
+func (_c *MockStore_CountRequestsByStatus_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_CountRequestsByStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockStore_CountRequestsByStatus_Call) Return(_a0 map[string]int, _a1 error) *MockStore_CountRequestsByStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_CountRequestsByStatus_Call) RunAndReturn(run func(context.Context, uuid.UUID) (map[string]int, error)) *MockStore_CountRequestsByStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountRequestsByUserID provides a mock function with given fields: ctx, userID, filter
+func (_m *MockStore) CountRequestsByUserID(ctx context.Context, userID uuid.UUID, filter repository.RequestFilter) (int, error) {
+	ret := _m.Called(ctx, userID, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountRequestsByUserID")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, repository.RequestFilter) (int, error)); ok {
+		return rf(ctx, userID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, repository.RequestFilter) int); ok {
+		r0 = rf(ctx, userID, filter)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, repository.RequestFilter) error); ok {
+		r1 = rf(ctx, userID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // MockStore_CountRequestsByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountRequestsByUserID'
 type MockStore_CountRequestsByUserID_Call struct {
 	*mock.Call
@@ -301,13 +472,14 @@ type MockStore_CountRequestsByUserID_Call struct {
 // CountRequestsByUserID is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userID uuid.UUID
-func (_e *MockStore_Expecter) CountRequestsByUserID(ctx interface{}, userID interface{}) *MockStore_CountRequestsByUserID_Call {
-	return &MockStore_CountRequestsByUserID_Call{Call: _e.mock.On("CountRequestsByUserID", ctx, userID)}
+//   - filter repository.RequestFilter
+func (_e *MockStore_Expecter) CountRequestsByUserID(ctx interface{}, userID interface{}, filter interface{}) *MockStore_CountRequestsByUserID_Call {
+	return &MockStore_CountRequestsByUserID_Call{Call: _e.mock.On("CountRequestsByUserID", ctx, userID, filter)}
 }
 
-func (_c *MockStore_CountRequestsByUserID_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_CountRequestsByUserID_Call {
+func (_c *MockStore_CountRequestsByUserID_Call) Run(run func(ctx context.Context, userID uuid.UUID, filter repository.RequestFilter)) *MockStore_CountRequestsByUserID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(repository.RequestFilter))
 	})
 	return _c
 }
@@ -317,7 +489,54 @@ func (_c *MockStore_CountRequestsByUserID_Call) Return(_a0 int, _a1 error) *Mock
 	return _c
 }
 
-func (_c *MockStore_CountRequestsByUserID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (int, error)) *MockStore_CountRequestsByUserID_Call {
+func (_c *MockStore_CountRequestsByUserID_Call) RunAndReturn(run func(context.Context, uuid.UUID, repository.RequestFilter) (int, error)) *MockStore_CountRequestsByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateECGBatch provides a mock function with given fields: ctx, batch
+func (_m *MockStore) CreateECGBatch(ctx context.Context, batch *models.ECGBatch) error {
+	ret := _m.Called(ctx, batch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateECGBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ECGBatch) error); ok {
+		r0 = rf(ctx, batch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_CreateECGBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateECGBatch'
+type MockStore_CreateECGBatch_Call struct {
+	*mock.Call
+}
+
+// CreateECGBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batch *models.ECGBatch
+func (_e *MockStore_Expecter) CreateECGBatch(ctx interface{}, batch interface{}) *MockStore_CreateECGBatch_Call {
+	return &MockStore_CreateECGBatch_Call{Call: _e.mock.On("CreateECGBatch", ctx, batch)}
+}
+
+func (_c *MockStore_CreateECGBatch_Call) Run(run func(ctx context.Context, batch *models.ECGBatch)) *MockStore_CreateECGBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.ECGBatch))
+	})
+	return _c
+}
+
+func (_c *MockStore_CreateECGBatch_Call) Return(_a0 error) *MockStore_CreateECGBatch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_CreateECGBatch_Call) RunAndReturn(run func(context.Context, *models.ECGBatch) error) *MockStore_CreateECGBatch_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -369,6 +588,53 @@ func (_c *MockStore_CreateECGChatMessage_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// CreateEmailVerificationToken provides a mock function with given fields: ctx, token
+func (_m *MockStore) CreateEmailVerificationToken(ctx context.Context, token *models.EmailVerificationToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEmailVerificationToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.EmailVerificationToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_CreateEmailVerificationToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateEmailVerificationToken'
+type MockStore_CreateEmailVerificationToken_Call struct {
+	*mock.Call
+}
+
+// CreateEmailVerificationToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *models.EmailVerificationToken
+func (_e *MockStore_Expecter) CreateEmailVerificationToken(ctx interface{}, token interface{}) *MockStore_CreateEmailVerificationToken_Call {
+	return &MockStore_CreateEmailVerificationToken_Call{Call: _e.mock.On("CreateEmailVerificationToken", ctx, token)}
+}
+
+func (_c *MockStore_CreateEmailVerificationToken_Call) Run(run func(ctx context.Context, token *models.EmailVerificationToken)) *MockStore_CreateEmailVerificationToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.EmailVerificationToken))
+	})
+	return _c
+}
+
+func (_c *MockStore_CreateEmailVerificationToken_Call) Return(_a0 error) *MockStore_CreateEmailVerificationToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_CreateEmailVerificationToken_Call) RunAndReturn(run func(context.Context, *models.EmailVerificationToken) error) *MockStore_CreateEmailVerificationToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateFile provides a mock function with given fields: ctx, file
 func (_m *MockStore) CreateFile(ctx context.Context, file *models.File) error {
 	ret := _m.Called(ctx, file)
@@ -416,6 +682,53 @@ func (_c *MockStore_CreateFile_Call) RunAndReturn(run func(context.Context, *mod
 	return _c
 }
 
+// CreateFiles provides a mock function with given fields: ctx, files
+func (_m *MockStore) CreateFiles(ctx context.Context, files []*models.File) error {
+	ret := _m.Called(ctx, files)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateFiles")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.File) error); ok {
+		r0 = rf(ctx, files)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_CreateFiles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateFiles'
+type MockStore_CreateFiles_Call struct {
+	*mock.Call
+}
+
+// CreateFiles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - files []*models.File
+func (_e *MockStore_Expecter) CreateFiles(ctx interface{}, files interface{}) *MockStore_CreateFiles_Call {
+	return &MockStore_CreateFiles_Call{Call: _e.mock.On("CreateFiles", ctx, files)}
+}
+
+func (_c *MockStore_CreateFiles_Call) Run(run func(ctx context.Context, files []*models.File)) *MockStore_CreateFiles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*models.File))
+	})
+	return _c
+}
+
+func (_c *MockStore_CreateFiles_Call) Return(_a0 error) *MockStore_CreateFiles_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_CreateFiles_Call) RunAndReturn(run func(context.Context, []*models.File) error) *MockStore_CreateFiles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreatePasswordResetToken provides a mock function with given fields: ctx, token
 func (_m *MockStore) CreatePasswordResetToken(ctx context.Context, token *models.PasswordResetToken) error {
 	ret := _m.Called(ctx, token)
@@ -510,6 +823,53 @@ func (_c *MockStore_CreatePayment_Call) RunAndReturn(run func(context.Context, *
 	return _c
 }
 
+// CreatePermission provides a mock function with given fields: ctx, perm
+func (_m *MockStore) CreatePermission(ctx context.Context, perm *models.Permission) error {
+	ret := _m.Called(ctx, perm)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePermission")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Permission) error); ok {
+		r0 = rf(ctx, perm)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_CreatePermission_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePermission'
+type MockStore_CreatePermission_Call struct {
+	*mock.Call
+}
+
+// CreatePermission is a helper method to define mock.On call
+//   - ctx context.Context
+//   - perm *models.Permission
+func (_e *MockStore_Expecter) CreatePermission(ctx interface{}, perm interface{}) *MockStore_CreatePermission_Call {
+	return &MockStore_CreatePermission_Call{Call: _e.mock.On("CreatePermission", ctx, perm)}
+}
+
+func (_c *MockStore_CreatePermission_Call) Run(run func(ctx context.Context, perm *models.Permission)) *MockStore_CreatePermission_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Permission))
+	})
+	return _c
+}
+
+func (_c *MockStore_CreatePermission_Call) Return(_a0 error) *MockStore_CreatePermission_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_CreatePermission_Call) RunAndReturn(run func(context.Context, *models.Permission) error) *MockStore_CreatePermission_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreatePromoCode provides a mock function with given fields: ctx, promo
 func (_m *MockStore) CreatePromoCode(ctx context.Context, promo *models.PromoCode) error {
 	ret := _m.Called(ctx, promo)
@@ -745,12 +1105,59 @@ func (_c *MockStore_CreateResponse_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
-// CreateUser provides a mock function with given fields: ctx, user
-func (_m *MockStore) CreateUser(ctx context.Context, user *models.User) error {
-	ret := _m.Called(ctx, user)
+// CreateRole provides a mock function with given fields: ctx, role
+func (_m *MockStore) CreateRole(ctx context.Context, role *models.Role) error {
+	ret := _m.Called(ctx, role)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateUser")
+		panic("no return value specified for CreateRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Role) error); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_CreateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRole'
+type MockStore_CreateRole_Call struct {
+	*mock.Call
+}
+
+// CreateRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - role *models.Role
+func (_e *MockStore_Expecter) CreateRole(ctx interface{}, role interface{}) *MockStore_CreateRole_Call {
+	return &MockStore_CreateRole_Call{Call: _e.mock.On("CreateRole", ctx, role)}
+}
+
+func (_c *MockStore_CreateRole_Call) Run(run func(ctx context.Context, role *models.Role)) *MockStore_CreateRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Role))
+	})
+	return _c
+}
+
+func (_c *MockStore_CreateRole_Call) Return(_a0 error) *MockStore_CreateRole_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_CreateRole_Call) RunAndReturn(run func(context.Context, *models.Role) error) *MockStore_CreateRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateUser provides a mock function with given fields: ctx, user
+func (_m *MockStore) CreateUser(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
 	}
 
 	var r0 error
@@ -839,29 +1246,27 @@ func (_c *MockStore_DecrementFreeAnalysesUsed_Call) RunAndReturn(run func(contex
 	return _c
 }
 
-// FindCachedAnswer provides a mock function with given fields: ctx, question, embedding, trigramThreshold, vectorThreshold
-func (_m *MockStore) FindCachedAnswer(ctx context.Context, question string, embedding []float64, trigramThreshold float64, vectorThreshold float64) (*models.KBCacheEntry, error) {
-	ret := _m.Called(ctx, question, embedding, trigramThreshold, vectorThreshold)
+// DeleteExpiredRefreshTokens provides a mock function with given fields: ctx, before
+func (_m *MockStore) DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindCachedAnswer")
+		panic("no return value specified for DeleteExpiredRefreshTokens")
 	}
 
-	var r0 *models.KBCacheEntry
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, []float64, float64, float64) (*models.KBCacheEntry, error)); ok {
-		return rf(ctx, question, embedding, trigramThreshold, vectorThreshold)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, []float64, float64, float64) *models.KBCacheEntry); ok {
-		r0 = rf(ctx, question, embedding, trigramThreshold, vectorThreshold)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.KBCacheEntry)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, []float64, float64, float64) error); ok {
-		r1 = rf(ctx, question, embedding, trigramThreshold, vectorThreshold)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -869,179 +1274,153 @@ func (_m *MockStore) FindCachedAnswer(ctx context.Context, question string, embe
 	return r0, r1
 }
 
-// MockStore_FindCachedAnswer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindCachedAnswer'
-type MockStore_FindCachedAnswer_Call struct {
+// MockStore_DeleteExpiredRefreshTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredRefreshTokens'
+type MockStore_DeleteExpiredRefreshTokens_Call struct {
 	*mock.Call
 }
 
-// FindCachedAnswer is a helper method to define mock.On call
+// DeleteExpiredRefreshTokens is a helper method to define mock.On call
 //   - ctx context.Context
-//   - question string
-//   - embedding []float64
-//   - trigramThreshold float64
-//   - vectorThreshold float64
-func (_e *MockStore_Expecter) FindCachedAnswer(ctx interface{}, question interface{}, embedding interface{}, trigramThreshold interface{}, vectorThreshold interface{}) *MockStore_FindCachedAnswer_Call {
-	return &MockStore_FindCachedAnswer_Call{Call: _e.mock.On("FindCachedAnswer", ctx, question, embedding, trigramThreshold, vectorThreshold)}
+//   - before time.Time
+func (_e *MockStore_Expecter) DeleteExpiredRefreshTokens(ctx interface{}, before interface{}) *MockStore_DeleteExpiredRefreshTokens_Call {
+	return &MockStore_DeleteExpiredRefreshTokens_Call{Call: _e.mock.On("DeleteExpiredRefreshTokens", ctx, before)}
 }
 
-func (_c *MockStore_FindCachedAnswer_Call) Run(run func(ctx context.Context, question string, embedding []float64, trigramThreshold float64, vectorThreshold float64)) *MockStore_FindCachedAnswer_Call {
+func (_c *MockStore_DeleteExpiredRefreshTokens_Call) Run(run func(ctx context.Context, before time.Time)) *MockStore_DeleteExpiredRefreshTokens_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].([]float64), args[3].(float64), args[4].(float64))
+		run(args[0].(context.Context), args[1].(time.Time))
 	})
 	return _c
 }
 
-func (_c *MockStore_FindCachedAnswer_Call) Return(_a0 *models.KBCacheEntry, _a1 error) *MockStore_FindCachedAnswer_Call {
+func (_c *MockStore_DeleteExpiredRefreshTokens_Call) Return(_a0 int64, _a1 error) *MockStore_DeleteExpiredRefreshTokens_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_FindCachedAnswer_Call) RunAndReturn(run func(context.Context, string, []float64, float64, float64) (*models.KBCacheEntry, error)) *MockStore_FindCachedAnswer_Call {
+func (_c *MockStore_DeleteExpiredRefreshTokens_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *MockStore_DeleteExpiredRefreshTokens_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAdminStats provides a mock function with given fields: ctx
-func (_m *MockStore) GetAdminStats(ctx context.Context) (*repository.AdminStats, error) {
-	ret := _m.Called(ctx)
+// DetachPermission provides a mock function with given fields: ctx, roleName, permName
+func (_m *MockStore) DetachPermission(ctx context.Context, roleName string, permName string) error {
+	ret := _m.Called(ctx, roleName, permName)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAdminStats")
-	}
-
-	var r0 *repository.AdminStats
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (*repository.AdminStats, error)); ok {
-		return rf(ctx)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context) *repository.AdminStats); ok {
-		r0 = rf(ctx)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*repository.AdminStats)
-		}
+		panic("no return value specified for DetachPermission")
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, roleName, permName)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// MockStore_GetAdminStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAdminStats'
-type MockStore_GetAdminStats_Call struct {
+// MockStore_DetachPermission_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DetachPermission'
+type MockStore_DetachPermission_Call struct {
 	*mock.Call
 }
 
-// GetAdminStats is a helper method to define mock.On call
+// DetachPermission is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *MockStore_Expecter) GetAdminStats(ctx interface{}) *MockStore_GetAdminStats_Call {
-	return &MockStore_GetAdminStats_Call{Call: _e.mock.On("GetAdminStats", ctx)}
+//   - roleName string
+//   - permName string
+func (_e *MockStore_Expecter) DetachPermission(ctx interface{}, roleName interface{}, permName interface{}) *MockStore_DetachPermission_Call {
+	return &MockStore_DetachPermission_Call{Call: _e.mock.On("DetachPermission", ctx, roleName, permName)}
 }
 
-func (_c *MockStore_GetAdminStats_Call) Run(run func(ctx context.Context)) *MockStore_GetAdminStats_Call {
+func (_c *MockStore_DetachPermission_Call) Run(run func(ctx context.Context, roleName string, permName string)) *MockStore_DetachPermission_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetAdminStats_Call) Return(_a0 *repository.AdminStats, _a1 error) *MockStore_GetAdminStats_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockStore_DetachPermission_Call) Return(_a0 error) *MockStore_DetachPermission_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStore_GetAdminStats_Call) RunAndReturn(run func(context.Context) (*repository.AdminStats, error)) *MockStore_GetAdminStats_Call {
+func (_c *MockStore_DetachPermission_Call) RunAndReturn(run func(context.Context, string, string) error) *MockStore_DetachPermission_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetECGChatMessages provides a mock function with given fields: ctx, requestID, userID
-func (_m *MockStore) GetECGChatMessages(ctx context.Context, requestID uuid.UUID, userID uuid.UUID) ([]models.ECGChatMessage, error) {
-	ret := _m.Called(ctx, requestID, userID)
+// EnableTwoFactor provides a mock function with given fields: ctx, userID
+func (_m *MockStore) EnableTwoFactor(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetECGChatMessages")
-	}
-
-	var r0 []models.ECGChatMessage
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) ([]models.ECGChatMessage, error)); ok {
-		return rf(ctx, requestID, userID)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []models.ECGChatMessage); ok {
-		r0 = rf(ctx, requestID, userID)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.ECGChatMessage)
-		}
+		panic("no return value specified for EnableTwoFactor")
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
-		r1 = rf(ctx, requestID, userID)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// MockStore_GetECGChatMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetECGChatMessages'
-type MockStore_GetECGChatMessages_Call struct {
+// MockStore_EnableTwoFactor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnableTwoFactor'
+type MockStore_EnableTwoFactor_Call struct {
 	*mock.Call
 }
 
-// GetECGChatMessages is a helper method to define mock.On call
+// EnableTwoFactor is a helper method to define mock.On call
 //   - ctx context.Context
-//   - requestID uuid.UUID
 //   - userID uuid.UUID
-func (_e *MockStore_Expecter) GetECGChatMessages(ctx interface{}, requestID interface{}, userID interface{}) *MockStore_GetECGChatMessages_Call {
-	return &MockStore_GetECGChatMessages_Call{Call: _e.mock.On("GetECGChatMessages", ctx, requestID, userID)}
+func (_e *MockStore_Expecter) EnableTwoFactor(ctx interface{}, userID interface{}) *MockStore_EnableTwoFactor_Call {
+	return &MockStore_EnableTwoFactor_Call{Call: _e.mock.On("EnableTwoFactor", ctx, userID)}
 }
 
-func (_c *MockStore_GetECGChatMessages_Call) Run(run func(ctx context.Context, requestID uuid.UUID, userID uuid.UUID)) *MockStore_GetECGChatMessages_Call {
+func (_c *MockStore_EnableTwoFactor_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_EnableTwoFactor_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetECGChatMessages_Call) Return(_a0 []models.ECGChatMessage, _a1 error) *MockStore_GetECGChatMessages_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockStore_EnableTwoFactor_Call) Return(_a0 error) *MockStore_EnableTwoFactor_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStore_GetECGChatMessages_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) ([]models.ECGChatMessage, error)) *MockStore_GetECGChatMessages_Call {
+func (_c *MockStore_EnableTwoFactor_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockStore_EnableTwoFactor_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetFilesByRequestID provides a mock function with given fields: ctx, requestID
-func (_m *MockStore) GetFilesByRequestID(ctx context.Context, requestID uuid.UUID) ([]models.File, error) {
-	ret := _m.Called(ctx, requestID)
+// ExistingFileKeys provides a mock function with given fields: ctx, keys
+func (_m *MockStore) ExistingFileKeys(ctx context.Context, keys []string) (map[string]struct{}, error) {
+	ret := _m.Called(ctx, keys)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetFilesByRequestID")
+		panic("no return value specified for ExistingFileKeys")
 	}
 
-	var r0 []models.File
+	var r0 map[string]struct{}
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]models.File, error)); ok {
-		return rf(ctx, requestID)
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]struct{}, error)); ok {
+		return rf(ctx, keys)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []models.File); ok {
-		r0 = rf(ctx, requestID)
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]struct{}); ok {
+		r0 = rf(ctx, keys)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.File)
+			r0 = ret.Get(0).(map[string]struct{})
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, requestID)
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, keys)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1049,115 +1428,106 @@ func (_m *MockStore) GetFilesByRequestID(ctx context.Context, requestID uuid.UUI
 	return r0, r1
 }
 
-// MockStore_GetFilesByRequestID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFilesByRequestID'
-type MockStore_GetFilesByRequestID_Call struct {
+// MockStore_ExistingFileKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExistingFileKeys'
+type MockStore_ExistingFileKeys_Call struct {
 	*mock.Call
 }
 
-// GetFilesByRequestID is a helper method to define mock.On call
+// ExistingFileKeys is a helper method to define mock.On call
 //   - ctx context.Context
-//   - requestID uuid.UUID
-func (_e *MockStore_Expecter) GetFilesByRequestID(ctx interface{}, requestID interface{}) *MockStore_GetFilesByRequestID_Call {
-	return &MockStore_GetFilesByRequestID_Call{Call: _e.mock.On("GetFilesByRequestID", ctx, requestID)}
+//   - keys []string
+func (_e *MockStore_Expecter) ExistingFileKeys(ctx interface{}, keys interface{}) *MockStore_ExistingFileKeys_Call {
+	return &MockStore_ExistingFileKeys_Call{Call: _e.mock.On("ExistingFileKeys", ctx, keys)}
 }
 
-func (_c *MockStore_GetFilesByRequestID_Call) Run(run func(ctx context.Context, requestID uuid.UUID)) *MockStore_GetFilesByRequestID_Call {
+func (_c *MockStore_ExistingFileKeys_Call) Run(run func(ctx context.Context, keys []string)) *MockStore_ExistingFileKeys_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].([]string))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetFilesByRequestID_Call) Return(_a0 []models.File, _a1 error) *MockStore_GetFilesByRequestID_Call {
+func (_c *MockStore_ExistingFileKeys_Call) Return(_a0 map[string]struct{}, _a1 error) *MockStore_ExistingFileKeys_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_GetFilesByRequestID_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]models.File, error)) *MockStore_GetFilesByRequestID_Call {
+func (_c *MockStore_ExistingFileKeys_Call) RunAndReturn(run func(context.Context, []string) (map[string]struct{}, error)) *MockStore_ExistingFileKeys_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetFreeAnalysesUsed provides a mock function with given fields: ctx, userID
-func (_m *MockStore) GetFreeAnalysesUsed(ctx context.Context, userID uuid.UUID) (int, error) {
-	ret := _m.Called(ctx, userID)
+// FailRequest provides a mock function with given fields: ctx, requestID, code
+func (_m *MockStore) FailRequest(ctx context.Context, requestID uuid.UUID, code string) error {
+	ret := _m.Called(ctx, requestID, code)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetFreeAnalysesUsed")
-	}
-
-	var r0 int
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int, error)); ok {
-		return rf(ctx, userID)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int); ok {
-		r0 = rf(ctx, userID)
-	} else {
-		r0 = ret.Get(0).(int)
+		panic("no return value specified for FailRequest")
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, userID)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, requestID, code)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// MockStore_GetFreeAnalysesUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFreeAnalysesUsed'
-type MockStore_GetFreeAnalysesUsed_Call struct {
+// MockStore_FailRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FailRequest'
+type MockStore_FailRequest_Call struct {
 	*mock.Call
 }
 
-// GetFreeAnalysesUsed is a helper method to define mock.On call
+// FailRequest is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID uuid.UUID
-func (_e *MockStore_Expecter) GetFreeAnalysesUsed(ctx interface{}, userID interface{}) *MockStore_GetFreeAnalysesUsed_Call {
-	return &MockStore_GetFreeAnalysesUsed_Call{Call: _e.mock.On("GetFreeAnalysesUsed", ctx, userID)}
+//   - requestID uuid.UUID
+//   - code string
+func (_e *MockStore_Expecter) FailRequest(ctx interface{}, requestID interface{}, code interface{}) *MockStore_FailRequest_Call {
+	return &MockStore_FailRequest_Call{Call: _e.mock.On("FailRequest", ctx, requestID, code)}
 }
 
-func (_c *MockStore_GetFreeAnalysesUsed_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_GetFreeAnalysesUsed_Call {
+func (_c *MockStore_FailRequest_Call) Run(run func(ctx context.Context, requestID uuid.UUID, code string)) *MockStore_FailRequest_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetFreeAnalysesUsed_Call) Return(_a0 int, _a1 error) *MockStore_GetFreeAnalysesUsed_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockStore_FailRequest_Call) Return(_a0 error) *MockStore_FailRequest_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStore_GetFreeAnalysesUsed_Call) RunAndReturn(run func(context.Context, uuid.UUID) (int, error)) *MockStore_GetFreeAnalysesUsed_Call {
+func (_c *MockStore_FailRequest_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockStore_FailRequest_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetPaymentsByUserID provides a mock function with given fields: ctx, userID
-func (_m *MockStore) GetPaymentsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Payment, error) {
-	ret := _m.Called(ctx, userID)
+// FindCachedAnswer provides a mock function with given fields: ctx, question, embedding, trigramThreshold, vectorThreshold
+func (_m *MockStore) FindCachedAnswer(ctx context.Context, question string, embedding []float64, trigramThreshold float64, vectorThreshold float64) (*models.KBCacheEntry, error) {
+	ret := _m.Called(ctx, question, embedding, trigramThreshold, vectorThreshold)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPaymentsByUserID")
+		panic("no return value specified for FindCachedAnswer")
 	}
 
-	var r0 []models.Payment
+	var r0 *models.KBCacheEntry
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]models.Payment, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []float64, float64, float64) (*models.KBCacheEntry, error)); ok {
+		return rf(ctx, question, embedding, trigramThreshold, vectorThreshold)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []models.Payment); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []float64, float64, float64) *models.KBCacheEntry); ok {
+		r0 = rf(ctx, question, embedding, trigramThreshold, vectorThreshold)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Payment)
+			r0 = ret.Get(0).(*models.KBCacheEntry)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, []float64, float64, float64) error); ok {
+		r1 = rf(ctx, question, embedding, trigramThreshold, vectorThreshold)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1165,58 +1535,61 @@ func (_m *MockStore) GetPaymentsByUserID(ctx context.Context, userID uuid.UUID)
 	return r0, r1
 }
 
-// MockStore_GetPaymentsByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPaymentsByUserID'
-type MockStore_GetPaymentsByUserID_Call struct {
+// MockStore_FindCachedAnswer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindCachedAnswer'
+type MockStore_FindCachedAnswer_Call struct {
 	*mock.Call
 }
 
-// GetPaymentsByUserID is a helper method to define mock.On call
+// FindCachedAnswer is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID uuid.UUID
-func (_e *MockStore_Expecter) GetPaymentsByUserID(ctx interface{}, userID interface{}) *MockStore_GetPaymentsByUserID_Call {
-	return &MockStore_GetPaymentsByUserID_Call{Call: _e.mock.On("GetPaymentsByUserID", ctx, userID)}
+//   - question string
+//   - embedding []float64
+//   - trigramThreshold float64
+//   - vectorThreshold float64
+func (_e *MockStore_Expecter) FindCachedAnswer(ctx interface{}, question interface{}, embedding interface{}, trigramThreshold interface{}, vectorThreshold interface{}) *MockStore_FindCachedAnswer_Call {
+	return &MockStore_FindCachedAnswer_Call{Call: _e.mock.On("FindCachedAnswer", ctx, question, embedding, trigramThreshold, vectorThreshold)}
 }
 
-func (_c *MockStore_GetPaymentsByUserID_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_GetPaymentsByUserID_Call {
+func (_c *MockStore_FindCachedAnswer_Call) Run(run func(ctx context.Context, question string, embedding []float64, trigramThreshold float64, vectorThreshold float64)) *MockStore_FindCachedAnswer_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(string), args[2].([]float64), args[3].(float64), args[4].(float64))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetPaymentsByUserID_Call) Return(_a0 []models.Payment, _a1 error) *MockStore_GetPaymentsByUserID_Call {
+func (_c *MockStore_FindCachedAnswer_Call) Return(_a0 *models.KBCacheEntry, _a1 error) *MockStore_FindCachedAnswer_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_GetPaymentsByUserID_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]models.Payment, error)) *MockStore_GetPaymentsByUserID_Call {
+func (_c *MockStore_FindCachedAnswer_Call) RunAndReturn(run func(context.Context, string, []float64, float64, float64) (*models.KBCacheEntry, error)) *MockStore_FindCachedAnswer_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetPromoCodeByCode provides a mock function with given fields: ctx, code
-func (_m *MockStore) GetPromoCodeByCode(ctx context.Context, code string) (*models.PromoCode, error) {
-	ret := _m.Called(ctx, code)
+// GetAdminStats provides a mock function with given fields: ctx
+func (_m *MockStore) GetAdminStats(ctx context.Context) (*repository.AdminStats, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPromoCodeByCode")
+		panic("no return value specified for GetAdminStats")
 	}
 
-	var r0 *models.PromoCode
+	var r0 *repository.AdminStats
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.PromoCode, error)); ok {
-		return rf(ctx, code)
+	if rf, ok := ret.Get(0).(func(context.Context) (*repository.AdminStats, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.PromoCode); ok {
-		r0 = rf(ctx, code)
+	if rf, ok := ret.Get(0).(func(context.Context) *repository.AdminStats); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.PromoCode)
+			r0 = ret.Get(0).(*repository.AdminStats)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, code)
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1224,58 +1597,57 @@ func (_m *MockStore) GetPromoCodeByCode(ctx context.Context, code string) (*mode
 	return r0, r1
 }
 
-// MockStore_GetPromoCodeByCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPromoCodeByCode'
-type MockStore_GetPromoCodeByCode_Call struct {
+// MockStore_GetAdminStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAdminStats'
+type MockStore_GetAdminStats_Call struct {
 	*mock.Call
 }
 
-// GetPromoCodeByCode is a helper method to define mock.On call
+// GetAdminStats is a helper method to define mock.On call
 //   - ctx context.Context
-//   - code string
-func (_e *MockStore_Expecter) GetPromoCodeByCode(ctx interface{}, code interface{}) *MockStore_GetPromoCodeByCode_Call {
-	return &MockStore_GetPromoCodeByCode_Call{Call: _e.mock.On("GetPromoCodeByCode", ctx, code)}
+func (_e *MockStore_Expecter) GetAdminStats(ctx interface{}) *MockStore_GetAdminStats_Call {
+	return &MockStore_GetAdminStats_Call{Call: _e.mock.On("GetAdminStats", ctx)}
 }
 
-func (_c *MockStore_GetPromoCodeByCode_Call) Run(run func(ctx context.Context, code string)) *MockStore_GetPromoCodeByCode_Call {
+func (_c *MockStore_GetAdminStats_Call) Run(run func(ctx context.Context)) *MockStore_GetAdminStats_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetPromoCodeByCode_Call) Return(_a0 *models.PromoCode, _a1 error) *MockStore_GetPromoCodeByCode_Call {
+func (_c *MockStore_GetAdminStats_Call) Return(_a0 *repository.AdminStats, _a1 error) *MockStore_GetAdminStats_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_GetPromoCodeByCode_Call) RunAndReturn(run func(context.Context, string) (*models.PromoCode, error)) *MockStore_GetPromoCodeByCode_Call {
+func (_c *MockStore_GetAdminStats_Call) RunAndReturn(run func(context.Context) (*repository.AdminStats, error)) *MockStore_GetAdminStats_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetRecentRequestsWithResponses provides a mock function with given fields: ctx, userID, limit
-func (_m *MockStore) GetRecentRequestsWithResponses(ctx context.Context, userID uuid.UUID, limit int) ([]models.Request, error) {
-	ret := _m.Called(ctx, userID, limit)
+// GetChildRequests provides a mock function with given fields: ctx, parentID
+func (_m *MockStore) GetChildRequests(ctx context.Context, parentID uuid.UUID) ([]models.Request, error) {
+	ret := _m.Called(ctx, parentID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetRecentRequestsWithResponses")
+		panic("no return value specified for GetChildRequests")
 	}
 
 	var r0 []models.Request
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) ([]models.Request, error)); ok {
-		return rf(ctx, userID, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]models.Request, error)); ok {
+		return rf(ctx, parentID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) []models.Request); ok {
-		r0 = rf(ctx, userID, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []models.Request); ok {
+		r0 = rf(ctx, parentID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Request)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int) error); ok {
-		r1 = rf(ctx, userID, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, parentID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1283,59 +1655,58 @@ func (_m *MockStore) GetRecentRequestsWithResponses(ctx context.Context, userID
 	return r0, r1
 }
 
-// MockStore_GetRecentRequestsWithResponses_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRecentRequestsWithResponses'
-type MockStore_GetRecentRequestsWithResponses_Call struct {
+// MockStore_GetChildRequests_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetChildRequests'
+type MockStore_GetChildRequests_Call struct {
 	*mock.Call
 }
 
-// GetRecentRequestsWithResponses is a helper method to define mock.On call
+// GetChildRequests is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID uuid.UUID
-//   - limit int
-func (_e *MockStore_Expecter) GetRecentRequestsWithResponses(ctx interface{}, userID interface{}, limit interface{}) *MockStore_GetRecentRequestsWithResponses_Call {
-	return &MockStore_GetRecentRequestsWithResponses_Call{Call: _e.mock.On("GetRecentRequestsWithResponses", ctx, userID, limit)}
+//   - parentID uuid.UUID
+func (_e *MockStore_Expecter) GetChildRequests(ctx interface{}, parentID interface{}) *MockStore_GetChildRequests_Call {
+	return &MockStore_GetChildRequests_Call{Call: _e.mock.On("GetChildRequests", ctx, parentID)}
 }
 
-func (_c *MockStore_GetRecentRequestsWithResponses_Call) Run(run func(ctx context.Context, userID uuid.UUID, limit int)) *MockStore_GetRecentRequestsWithResponses_Call {
+func (_c *MockStore_GetChildRequests_Call) Run(run func(ctx context.Context, parentID uuid.UUID)) *MockStore_GetChildRequests_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(int))
+		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetRecentRequestsWithResponses_Call) Return(_a0 []models.Request, _a1 error) *MockStore_GetRecentRequestsWithResponses_Call {
+func (_c *MockStore_GetChildRequests_Call) Return(_a0 []models.Request, _a1 error) *MockStore_GetChildRequests_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_GetRecentRequestsWithResponses_Call) RunAndReturn(run func(context.Context, uuid.UUID, int) ([]models.Request, error)) *MockStore_GetRecentRequestsWithResponses_Call {
+func (_c *MockStore_GetChildRequests_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]models.Request, error)) *MockStore_GetChildRequests_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetRefreshToken provides a mock function with given fields: ctx, tokenHash
-func (_m *MockStore) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
-	ret := _m.Called(ctx, tokenHash)
+// GetECGBatch provides a mock function with given fields: ctx, id
+func (_m *MockStore) GetECGBatch(ctx context.Context, id uuid.UUID) (*models.ECGBatch, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetRefreshToken")
+		panic("no return value specified for GetECGBatch")
 	}
 
-	var r0 *models.RefreshToken
+	var r0 *models.ECGBatch
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.RefreshToken, error)); ok {
-		return rf(ctx, tokenHash)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.ECGBatch, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.RefreshToken); ok {
-		r0 = rf(ctx, tokenHash)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.ECGBatch); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.RefreshToken)
+			r0 = ret.Get(0).(*models.ECGBatch)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, tokenHash)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1343,58 +1714,58 @@ func (_m *MockStore) GetRefreshToken(ctx context.Context, tokenHash string) (*mo
 	return r0, r1
 }
 
-// MockStore_GetRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRefreshToken'
-type MockStore_GetRefreshToken_Call struct {
+// MockStore_GetECGBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetECGBatch'
+type MockStore_GetECGBatch_Call struct {
 	*mock.Call
 }
 
-// GetRefreshToken is a helper method to define mock.On call
+// GetECGBatch is a helper method to define mock.On call
 //   - ctx context.Context
-//   - tokenHash string
-func (_e *MockStore_Expecter) GetRefreshToken(ctx interface{}, tokenHash interface{}) *MockStore_GetRefreshToken_Call {
-	return &MockStore_GetRefreshToken_Call{Call: _e.mock.On("GetRefreshToken", ctx, tokenHash)}
+//   - id uuid.UUID
+func (_e *MockStore_Expecter) GetECGBatch(ctx interface{}, id interface{}) *MockStore_GetECGBatch_Call {
+	return &MockStore_GetECGBatch_Call{Call: _e.mock.On("GetECGBatch", ctx, id)}
 }
 
-func (_c *MockStore_GetRefreshToken_Call) Run(run func(ctx context.Context, tokenHash string)) *MockStore_GetRefreshToken_Call {
+func (_c *MockStore_GetECGBatch_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockStore_GetECGBatch_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetRefreshToken_Call) Return(_a0 *models.RefreshToken, _a1 error) *MockStore_GetRefreshToken_Call {
+func (_c *MockStore_GetECGBatch_Call) Return(_a0 *models.ECGBatch, _a1 error) *MockStore_GetECGBatch_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_GetRefreshToken_Call) RunAndReturn(run func(context.Context, string) (*models.RefreshToken, error)) *MockStore_GetRefreshToken_Call {
+func (_c *MockStore_GetECGBatch_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.ECGBatch, error)) *MockStore_GetECGBatch_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetRequestByID provides a mock function with given fields: ctx, id
-func (_m *MockStore) GetRequestByID(ctx context.Context, id uuid.UUID) (*models.Request, error) {
-	ret := _m.Called(ctx, id)
+// GetECGChatMessages provides a mock function with given fields: ctx, requestID, userID
+func (_m *MockStore) GetECGChatMessages(ctx context.Context, requestID uuid.UUID, userID uuid.UUID) ([]models.ECGChatMessage, error) {
+	ret := _m.Called(ctx, requestID, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetRequestByID")
+		panic("no return value specified for GetECGChatMessages")
 	}
 
-	var r0 *models.Request
+	var r0 []models.ECGChatMessage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.Request, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) ([]models.ECGChatMessage, error)); ok {
+		return rf(ctx, requestID, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.Request); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []models.ECGChatMessage); ok {
+		r0 = rf(ctx, requestID, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.Request)
+			r0 = ret.Get(0).([]models.ECGChatMessage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, requestID, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1402,114 +1773,122 @@ func (_m *MockStore) GetRequestByID(ctx context.Context, id uuid.UUID) (*models.
 	return r0, r1
 }
 
-// MockStore_GetRequestByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRequestByID'
-type MockStore_GetRequestByID_Call struct {
+// MockStore_GetECGChatMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetECGChatMessages'
+type MockStore_GetECGChatMessages_Call struct {
 	*mock.Call
 }
 
-// GetRequestByID is a helper method to define mock.On call
+// GetECGChatMessages is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id uuid.UUID
-func (_e *MockStore_Expecter) GetRequestByID(ctx interface{}, id interface{}) *MockStore_GetRequestByID_Call {
-	return &MockStore_GetRequestByID_Call{Call: _e.mock.On("GetRequestByID", ctx, id)}
+//   - requestID uuid.UUID
+//   - userID uuid.UUID
+func (_e *MockStore_Expecter) GetECGChatMessages(ctx interface{}, requestID interface{}, userID interface{}) *MockStore_GetECGChatMessages_Call {
+	return &MockStore_GetECGChatMessages_Call{Call: _e.mock.On("GetECGChatMessages", ctx, requestID, userID)}
 }
 
-func (_c *MockStore_GetRequestByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockStore_GetRequestByID_Call {
+func (_c *MockStore_GetECGChatMessages_Call) Run(run func(ctx context.Context, requestID uuid.UUID, userID uuid.UUID)) *MockStore_GetECGChatMessages_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetRequestByID_Call) Return(_a0 *models.Request, _a1 error) *MockStore_GetRequestByID_Call {
+func (_c *MockStore_GetECGChatMessages_Call) Return(_a0 []models.ECGChatMessage, _a1 error) *MockStore_GetECGChatMessages_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_GetRequestByID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.Request, error)) *MockStore_GetRequestByID_Call {
+func (_c *MockStore_GetECGChatMessages_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) ([]models.ECGChatMessage, error)) *MockStore_GetECGChatMessages_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetRequestsByUserID provides a mock function with given fields: ctx, userID, limit, offset
-func (_m *MockStore) GetRequestsByUserID(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]models.Request, error) {
-	ret := _m.Called(ctx, userID, limit, offset)
+// GetFileByID provides a mock function with given fields: ctx, id
+func (_m *MockStore) GetFileByID(ctx context.Context, id uuid.UUID) (*models.File, uuid.UUID, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetRequestsByUserID")
+		panic("no return value specified for GetFileByID")
 	}
 
-	var r0 []models.Request
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) ([]models.Request, error)); ok {
-		return rf(ctx, userID, limit, offset)
+	var r0 *models.File
+	var r1 uuid.UUID
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.File, uuid.UUID, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) []models.Request); ok {
-		r0 = rf(ctx, userID, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.File); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Request)
+			r0 = ret.Get(0).(*models.File)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, int) error); ok {
-		r1 = rf(ctx, userID, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) uuid.UUID); ok {
+		r1 = rf(ctx, id)
 	} else {
-		r1 = ret.Error(1)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(uuid.UUID)
+		}
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID) error); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// MockStore_GetRequestsByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRequestsByUserID'
-type MockStore_GetRequestsByUserID_Call struct {
+// MockStore_GetFileByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFileByID'
+type MockStore_GetFileByID_Call struct {
 	*mock.Call
 }
 
-// GetRequestsByUserID is a helper method to define mock.On call
+// GetFileByID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID uuid.UUID
-//   - limit int
-//   - offset int
-func (_e *MockStore_Expecter) GetRequestsByUserID(ctx interface{}, userID interface{}, limit interface{}, offset interface{}) *MockStore_GetRequestsByUserID_Call {
-	return &MockStore_GetRequestsByUserID_Call{Call: _e.mock.On("GetRequestsByUserID", ctx, userID, limit, offset)}
+//   - id uuid.UUID
+func (_e *MockStore_Expecter) GetFileByID(ctx interface{}, id interface{}) *MockStore_GetFileByID_Call {
+	return &MockStore_GetFileByID_Call{Call: _e.mock.On("GetFileByID", ctx, id)}
 }
 
-func (_c *MockStore_GetRequestsByUserID_Call) Run(run func(ctx context.Context, userID uuid.UUID, limit int, offset int)) *MockStore_GetRequestsByUserID_Call {
+func (_c *MockStore_GetFileByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockStore_GetFileByID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(int), args[3].(int))
+		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetRequestsByUserID_Call) Return(_a0 []models.Request, _a1 error) *MockStore_GetRequestsByUserID_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockStore_GetFileByID_Call) Return(_a0 *models.File, _a1 uuid.UUID, _a2 error) *MockStore_GetFileByID_Call {
+	_c.Call.Return(_a0, _a1, _a2)
 	return _c
 }
 
-func (_c *MockStore_GetRequestsByUserID_Call) RunAndReturn(run func(context.Context, uuid.UUID, int, int) ([]models.Request, error)) *MockStore_GetRequestsByUserID_Call {
+func (_c *MockStore_GetFileByID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.File, uuid.UUID, error)) *MockStore_GetFileByID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetResponseByRequestID provides a mock function with given fields: ctx, requestID
-func (_m *MockStore) GetResponseByRequestID(ctx context.Context, requestID uuid.UUID) (*models.Response, error) {
+// GetFileKeysByRequestID provides a mock function with given fields: ctx, requestID
+func (_m *MockStore) GetFileKeysByRequestID(ctx context.Context, requestID uuid.UUID) ([]string, error) {
 	ret := _m.Called(ctx, requestID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetResponseByRequestID")
+		panic("no return value specified for GetFileKeysByRequestID")
 	}
 
-	var r0 *models.Response
+	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.Response, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]string, error)); ok {
 		return rf(ctx, requestID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.Response); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []string); ok {
 		r0 = rf(ctx, requestID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.Response)
+			r0 = ret.Get(0).([]string)
 		}
 	}
 
@@ -1522,58 +1901,58 @@ func (_m *MockStore) GetResponseByRequestID(ctx context.Context, requestID uuid.
 	return r0, r1
 }
 
-// MockStore_GetResponseByRequestID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetResponseByRequestID'
-type MockStore_GetResponseByRequestID_Call struct {
+// MockStore_GetFileKeysByRequestID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFileKeysByRequestID'
+type MockStore_GetFileKeysByRequestID_Call struct {
 	*mock.Call
 }
 
-// GetResponseByRequestID is a helper method to define mock.On call
+// GetFileKeysByRequestID is a helper method to define mock.On call
 //   - ctx context.Context
 //   - requestID uuid.UUID
-func (_e *MockStore_Expecter) GetResponseByRequestID(ctx interface{}, requestID interface{}) *MockStore_GetResponseByRequestID_Call {
-	return &MockStore_GetResponseByRequestID_Call{Call: _e.mock.On("GetResponseByRequestID", ctx, requestID)}
+func (_e *MockStore_Expecter) GetFileKeysByRequestID(ctx interface{}, requestID interface{}) *MockStore_GetFileKeysByRequestID_Call {
+	return &MockStore_GetFileKeysByRequestID_Call{Call: _e.mock.On("GetFileKeysByRequestID", ctx, requestID)}
 }
 
-func (_c *MockStore_GetResponseByRequestID_Call) Run(run func(ctx context.Context, requestID uuid.UUID)) *MockStore_GetResponseByRequestID_Call {
+func (_c *MockStore_GetFileKeysByRequestID_Call) Run(run func(ctx context.Context, requestID uuid.UUID)) *MockStore_GetFileKeysByRequestID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetResponseByRequestID_Call) Return(_a0 *models.Response, _a1 error) *MockStore_GetResponseByRequestID_Call {
+func (_c *MockStore_GetFileKeysByRequestID_Call) Return(_a0 []string, _a1 error) *MockStore_GetFileKeysByRequestID_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_GetResponseByRequestID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.Response, error)) *MockStore_GetResponseByRequestID_Call {
+func (_c *MockStore_GetFileKeysByRequestID_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]string, error)) *MockStore_GetFileKeysByRequestID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetRevokedRefreshTokenOwner provides a mock function with given fields: ctx, tokenHash
-func (_m *MockStore) GetRevokedRefreshTokenOwner(ctx context.Context, tokenHash string) (uuid.UUID, error) {
-	ret := _m.Called(ctx, tokenHash)
+// GetFilesByRequestID provides a mock function with given fields: ctx, requestID
+func (_m *MockStore) GetFilesByRequestID(ctx context.Context, requestID uuid.UUID) ([]models.File, error) {
+	ret := _m.Called(ctx, requestID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetRevokedRefreshTokenOwner")
+		panic("no return value specified for GetFilesByRequestID")
 	}
 
-	var r0 uuid.UUID
+	var r0 []models.File
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (uuid.UUID, error)); ok {
-		return rf(ctx, tokenHash)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]models.File, error)); ok {
+		return rf(ctx, requestID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) uuid.UUID); ok {
-		r0 = rf(ctx, tokenHash)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []models.File); ok {
+		r0 = rf(ctx, requestID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(uuid.UUID)
+			r0 = ret.Get(0).([]models.File)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, tokenHash)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, requestID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1581,54 +1960,52 @@ func (_m *MockStore) GetRevokedRefreshTokenOwner(ctx context.Context, tokenHash
 	return r0, r1
 }
 
-// MockStore_GetRevokedRefreshTokenOwner_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRevokedRefreshTokenOwner'
-type MockStore_GetRevokedRefreshTokenOwner_Call struct {
+// MockStore_GetFilesByRequestID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFilesByRequestID'
+type MockStore_GetFilesByRequestID_Call struct {
 	*mock.Call
 }
 
-// GetRevokedRefreshTokenOwner is a helper method to define mock.On call
+// GetFilesByRequestID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - tokenHash string
-func (_e *MockStore_Expecter) GetRevokedRefreshTokenOwner(ctx interface{}, tokenHash interface{}) *MockStore_GetRevokedRefreshTokenOwner_Call {
-	return &MockStore_GetRevokedRefreshTokenOwner_Call{Call: _e.mock.On("GetRevokedRefreshTokenOwner", ctx, tokenHash)}
+//   - requestID uuid.UUID
+func (_e *MockStore_Expecter) GetFilesByRequestID(ctx interface{}, requestID interface{}) *MockStore_GetFilesByRequestID_Call {
+	return &MockStore_GetFilesByRequestID_Call{Call: _e.mock.On("GetFilesByRequestID", ctx, requestID)}
 }
 
-func (_c *MockStore_GetRevokedRefreshTokenOwner_Call) Run(run func(ctx context.Context, tokenHash string)) *MockStore_GetRevokedRefreshTokenOwner_Call {
+func (_c *MockStore_GetFilesByRequestID_Call) Run(run func(ctx context.Context, requestID uuid.UUID)) *MockStore_GetFilesByRequestID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetRevokedRefreshTokenOwner_Call) Return(_a0 uuid.UUID, _a1 error) *MockStore_GetRevokedRefreshTokenOwner_Call {
+func (_c *MockStore_GetFilesByRequestID_Call) Return(_a0 []models.File, _a1 error) *MockStore_GetFilesByRequestID_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_GetRevokedRefreshTokenOwner_Call) RunAndReturn(run func(context.Context, string) (uuid.UUID, error)) *MockStore_GetRevokedRefreshTokenOwner_Call {
+func (_c *MockStore_GetFilesByRequestID_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]models.File, error)) *MockStore_GetFilesByRequestID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetSubscriptionExpiresAt provides a mock function with given fields: ctx, userID
-func (_m *MockStore) GetSubscriptionExpiresAt(ctx context.Context, userID uuid.UUID) (*time.Time, error) {
+// GetFreeAnalysesUsed provides a mock function with given fields: ctx, userID
+func (_m *MockStore) GetFreeAnalysesUsed(ctx context.Context, userID uuid.UUID) (int, error) {
 	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetSubscriptionExpiresAt")
+		panic("no return value specified for GetFreeAnalysesUsed")
 	}
 
-	var r0 *time.Time
+	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*time.Time, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int, error)); ok {
 		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *time.Time); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int); ok {
 		r0 = rf(ctx, userID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*time.Time)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
@@ -1640,58 +2017,58 @@ func (_m *MockStore) GetSubscriptionExpiresAt(ctx context.Context, userID uuid.U
 	return r0, r1
 }
 
-// MockStore_GetSubscriptionExpiresAt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSubscriptionExpiresAt'
-type MockStore_GetSubscriptionExpiresAt_Call struct {
+// MockStore_GetFreeAnalysesUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFreeAnalysesUsed'
+type MockStore_GetFreeAnalysesUsed_Call struct {
 	*mock.Call
 }
 
-// GetSubscriptionExpiresAt is a helper method to define mock.On call
+// GetFreeAnalysesUsed is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userID uuid.UUID
-func (_e *MockStore_Expecter) GetSubscriptionExpiresAt(ctx interface{}, userID interface{}) *MockStore_GetSubscriptionExpiresAt_Call {
-	return &MockStore_GetSubscriptionExpiresAt_Call{Call: _e.mock.On("GetSubscriptionExpiresAt", ctx, userID)}
+func (_e *MockStore_Expecter) GetFreeAnalysesUsed(ctx interface{}, userID interface{}) *MockStore_GetFreeAnalysesUsed_Call {
+	return &MockStore_GetFreeAnalysesUsed_Call{Call: _e.mock.On("GetFreeAnalysesUsed", ctx, userID)}
 }
 
-func (_c *MockStore_GetSubscriptionExpiresAt_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_GetSubscriptionExpiresAt_Call {
+func (_c *MockStore_GetFreeAnalysesUsed_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_GetFreeAnalysesUsed_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetSubscriptionExpiresAt_Call) Return(_a0 *time.Time, _a1 error) *MockStore_GetSubscriptionExpiresAt_Call {
+func (_c *MockStore_GetFreeAnalysesUsed_Call) Return(_a0 int, _a1 error) *MockStore_GetFreeAnalysesUsed_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_GetSubscriptionExpiresAt_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*time.Time, error)) *MockStore_GetSubscriptionExpiresAt_Call {
+func (_c *MockStore_GetFreeAnalysesUsed_Call) RunAndReturn(run func(context.Context, uuid.UUID) (int, error)) *MockStore_GetFreeAnalysesUsed_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserByEmail provides a mock function with given fields: ctx, email
-func (_m *MockStore) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
-	ret := _m.Called(ctx, email)
+// GetPaymentsByUserID provides a mock function with given fields: ctx, userID
+func (_m *MockStore) GetPaymentsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Payment, error) {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserByEmail")
+		panic("no return value specified for GetPaymentsByUserID")
 	}
 
-	var r0 *models.User
+	var r0 []models.Payment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
-		return rf(ctx, email)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]models.Payment, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
-		r0 = rf(ctx, email)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []models.Payment); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.User)
+			r0 = ret.Get(0).([]models.Payment)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, email)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1699,58 +2076,58 @@ func (_m *MockStore) GetUserByEmail(ctx context.Context, email string) (*models.
 	return r0, r1
 }
 
-// MockStore_GetUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByEmail'
-type MockStore_GetUserByEmail_Call struct {
+// MockStore_GetPaymentsByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPaymentsByUserID'
+type MockStore_GetPaymentsByUserID_Call struct {
 	*mock.Call
 }
 
-// GetUserByEmail is a helper method to define mock.On call
+// GetPaymentsByUserID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - email string
-func (_e *MockStore_Expecter) GetUserByEmail(ctx interface{}, email interface{}) *MockStore_GetUserByEmail_Call {
-	return &MockStore_GetUserByEmail_Call{Call: _e.mock.On("GetUserByEmail", ctx, email)}
+//   - userID uuid.UUID
+func (_e *MockStore_Expecter) GetPaymentsByUserID(ctx interface{}, userID interface{}) *MockStore_GetPaymentsByUserID_Call {
+	return &MockStore_GetPaymentsByUserID_Call{Call: _e.mock.On("GetPaymentsByUserID", ctx, userID)}
 }
 
-func (_c *MockStore_GetUserByEmail_Call) Run(run func(ctx context.Context, email string)) *MockStore_GetUserByEmail_Call {
+func (_c *MockStore_GetPaymentsByUserID_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_GetPaymentsByUserID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetUserByEmail_Call) Return(_a0 *models.User, _a1 error) *MockStore_GetUserByEmail_Call {
+func (_c *MockStore_GetPaymentsByUserID_Call) Return(_a0 []models.Payment, _a1 error) *MockStore_GetPaymentsByUserID_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_GetUserByEmail_Call) RunAndReturn(run func(context.Context, string) (*models.User, error)) *MockStore_GetUserByEmail_Call {
+func (_c *MockStore_GetPaymentsByUserID_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]models.Payment, error)) *MockStore_GetPaymentsByUserID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserByID provides a mock function with given fields: ctx, userID
-func (_m *MockStore) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
-	ret := _m.Called(ctx, userID)
+// GetPromoCodeByCode provides a mock function with given fields: ctx, code
+func (_m *MockStore) GetPromoCodeByCode(ctx context.Context, code string) (*models.PromoCode, error) {
+	ret := _m.Called(ctx, code)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserByID")
+		panic("no return value specified for GetPromoCodeByCode")
 	}
 
-	var r0 *models.User
+	var r0 *models.PromoCode
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.User, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.PromoCode, error)); ok {
+		return rf(ctx, code)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.User); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.PromoCode); ok {
+		r0 = rf(ctx, code)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.User)
+			r0 = ret.Get(0).(*models.PromoCode)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1758,58 +2135,58 @@ func (_m *MockStore) GetUserByID(ctx context.Context, userID uuid.UUID) (*models
 	return r0, r1
 }
 
-// MockStore_GetUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByID'
-type MockStore_GetUserByID_Call struct {
+// MockStore_GetPromoCodeByCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPromoCodeByCode'
+type MockStore_GetPromoCodeByCode_Call struct {
 	*mock.Call
 }
 
-// GetUserByID is a helper method to define mock.On call
+// GetPromoCodeByCode is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID uuid.UUID
-func (_e *MockStore_Expecter) GetUserByID(ctx interface{}, userID interface{}) *MockStore_GetUserByID_Call {
-	return &MockStore_GetUserByID_Call{Call: _e.mock.On("GetUserByID", ctx, userID)}
+//   - code string
+func (_e *MockStore_Expecter) GetPromoCodeByCode(ctx interface{}, code interface{}) *MockStore_GetPromoCodeByCode_Call {
+	return &MockStore_GetPromoCodeByCode_Call{Call: _e.mock.On("GetPromoCodeByCode", ctx, code)}
 }
 
-func (_c *MockStore_GetUserByID_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_GetUserByID_Call {
+func (_c *MockStore_GetPromoCodeByCode_Call) Run(run func(ctx context.Context, code string)) *MockStore_GetPromoCodeByCode_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetUserByID_Call) Return(_a0 *models.User, _a1 error) *MockStore_GetUserByID_Call {
+func (_c *MockStore_GetPromoCodeByCode_Call) Return(_a0 *models.PromoCode, _a1 error) *MockStore_GetPromoCodeByCode_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_GetUserByID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.User, error)) *MockStore_GetUserByID_Call {
+func (_c *MockStore_GetPromoCodeByCode_Call) RunAndReturn(run func(context.Context, string) (*models.PromoCode, error)) *MockStore_GetPromoCodeByCode_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetValidPasswordResetToken provides a mock function with given fields: ctx, tokenHash
-func (_m *MockStore) GetValidPasswordResetToken(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
-	ret := _m.Called(ctx, tokenHash)
+// GetRecentRequestsWithResponses provides a mock function with given fields: ctx, userID, limit
+func (_m *MockStore) GetRecentRequestsWithResponses(ctx context.Context, userID uuid.UUID, limit int) ([]models.Request, error) {
+	ret := _m.Called(ctx, userID, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetValidPasswordResetToken")
+		panic("no return value specified for GetRecentRequestsWithResponses")
 	}
 
-	var r0 *models.PasswordResetToken
+	var r0 []models.Request
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.PasswordResetToken, error)); ok {
-		return rf(ctx, tokenHash)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) ([]models.Request, error)); ok {
+		return rf(ctx, userID, limit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.PasswordResetToken); ok {
-		r0 = rf(ctx, tokenHash)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) []models.Request); ok {
+		r0 = rf(ctx, userID, limit)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.PasswordResetToken)
+			r0 = ret.Get(0).([]models.Request)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, tokenHash)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int) error); ok {
+		r1 = rf(ctx, userID, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1817,56 +2194,59 @@ func (_m *MockStore) GetValidPasswordResetToken(ctx context.Context, tokenHash s
 	return r0, r1
 }
 
-// MockStore_GetValidPasswordResetToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetValidPasswordResetToken'
-type MockStore_GetValidPasswordResetToken_Call struct {
+// MockStore_GetRecentRequestsWithResponses_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRecentRequestsWithResponses'
+type MockStore_GetRecentRequestsWithResponses_Call struct {
 	*mock.Call
 }
 
-// GetValidPasswordResetToken is a helper method to define mock.On call
+// GetRecentRequestsWithResponses is a helper method to define mock.On call
 //   - ctx context.Context
-//   - tokenHash string
-func (_e *MockStore_Expecter) GetValidPasswordResetToken(ctx interface{}, tokenHash interface{}) *MockStore_GetValidPasswordResetToken_Call {
-	return &MockStore_GetValidPasswordResetToken_Call{Call: _e.mock.On("GetValidPasswordResetToken", ctx, tokenHash)}
+//   - userID uuid.UUID
+//   - limit int
+func (_e *MockStore_Expecter) GetRecentRequestsWithResponses(ctx interface{}, userID interface{}, limit interface{}) *MockStore_GetRecentRequestsWithResponses_Call {
+	return &MockStore_GetRecentRequestsWithResponses_Call{Call: _e.mock.On("GetRecentRequestsWithResponses", ctx, userID, limit)}
 }
 
-func (_c *MockStore_GetValidPasswordResetToken_Call) Run(run func(ctx context.Context, tokenHash string)) *MockStore_GetValidPasswordResetToken_Call {
+func (_c *MockStore_GetRecentRequestsWithResponses_Call) Run(run func(ctx context.Context, userID uuid.UUID, limit int)) *MockStore_GetRecentRequestsWithResponses_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(int))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetValidPasswordResetToken_Call) Return(_a0 *models.PasswordResetToken, _a1 error) *MockStore_GetValidPasswordResetToken_Call {
+func (_c *MockStore_GetRecentRequestsWithResponses_Call) Return(_a0 []models.Request, _a1 error) *MockStore_GetRecentRequestsWithResponses_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_GetValidPasswordResetToken_Call) RunAndReturn(run func(context.Context, string) (*models.PasswordResetToken, error)) *MockStore_GetValidPasswordResetToken_Call {
+func (_c *MockStore_GetRecentRequestsWithResponses_Call) RunAndReturn(run func(context.Context, uuid.UUID, int) ([]models.Request, error)) *MockStore_GetRecentRequestsWithResponses_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// HasPendingPayment provides a mock function with given fields: ctx, userID, paymentType
-func (_m *MockStore) HasPendingPayment(ctx context.Context, userID uuid.UUID, paymentType string) (bool, error) {
-	ret := _m.Called(ctx, userID, paymentType)
+// GetRefreshToken provides a mock function with given fields: ctx, tokenHash
+func (_m *MockStore) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	ret := _m.Called(ctx, tokenHash)
 
 	if len(ret) == 0 {
-		panic("no return value specified for HasPendingPayment")
+		panic("no return value specified for GetRefreshToken")
 	}
 
-	var r0 bool
+	var r0 *models.RefreshToken
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (bool, error)); ok {
-		return rf(ctx, userID, paymentType)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.RefreshToken, error)); ok {
+		return rf(ctx, tokenHash)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) bool); ok {
-		r0 = rf(ctx, userID, paymentType)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.RefreshToken); ok {
+		r0 = rf(ctx, tokenHash)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RefreshToken)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
-		r1 = rf(ctx, userID, paymentType)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1874,57 +2254,58 @@ func (_m *MockStore) HasPendingPayment(ctx context.Context, userID uuid.UUID, pa
 	return r0, r1
 }
 
-// MockStore_HasPendingPayment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HasPendingPayment'
-type MockStore_HasPendingPayment_Call struct {
+// MockStore_GetRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRefreshToken'
+type MockStore_GetRefreshToken_Call struct {
 	*mock.Call
 }
 
-// HasPendingPayment is a helper method to define mock.On call
+// GetRefreshToken is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID uuid.UUID
-//   - paymentType string
-func (_e *MockStore_Expecter) HasPendingPayment(ctx interface{}, userID interface{}, paymentType interface{}) *MockStore_HasPendingPayment_Call {
-	return &MockStore_HasPendingPayment_Call{Call: _e.mock.On("HasPendingPayment", ctx, userID, paymentType)}
+//   - tokenHash string
+func (_e *MockStore_Expecter) GetRefreshToken(ctx interface{}, tokenHash interface{}) *MockStore_GetRefreshToken_Call {
+	return &MockStore_GetRefreshToken_Call{Call: _e.mock.On("GetRefreshToken", ctx, tokenHash)}
 }
 
-func (_c *MockStore_HasPendingPayment_Call) Run(run func(ctx context.Context, userID uuid.UUID, paymentType string)) *MockStore_HasPendingPayment_Call {
+func (_c *MockStore_GetRefreshToken_Call) Run(run func(ctx context.Context, tokenHash string)) *MockStore_GetRefreshToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_HasPendingPayment_Call) Return(_a0 bool, _a1 error) *MockStore_HasPendingPayment_Call {
+func (_c *MockStore_GetRefreshToken_Call) Return(_a0 *models.RefreshToken, _a1 error) *MockStore_GetRefreshToken_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_HasPendingPayment_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) (bool, error)) *MockStore_HasPendingPayment_Call {
+func (_c *MockStore_GetRefreshToken_Call) RunAndReturn(run func(context.Context, string) (*models.RefreshToken, error)) *MockStore_GetRefreshToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// IncrementFreeAnalysesUsed provides a mock function with given fields: ctx, userID
-func (_m *MockStore) IncrementFreeAnalysesUsed(ctx context.Context, userID uuid.UUID) (int, error) {
-	ret := _m.Called(ctx, userID)
+// GetRequestByID provides a mock function with given fields: ctx, id
+func (_m *MockStore) GetRequestByID(ctx context.Context, id uuid.UUID) (*models.Request, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for IncrementFreeAnalysesUsed")
+		panic("no return value specified for GetRequestByID")
 	}
 
-	var r0 int
+	var r0 *models.Request
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.Request, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.Request); ok {
+		r0 = rf(ctx, id)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Request)
+		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, userID)
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1932,307 +2313,354 @@ func (_m *MockStore) IncrementFreeAnalysesUsed(ctx context.Context, userID uuid.
 	return r0, r1
 }
 
-// MockStore_IncrementFreeAnalysesUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncrementFreeAnalysesUsed'
-type MockStore_IncrementFreeAnalysesUsed_Call struct {
+// MockStore_GetRequestByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRequestByID'
+type MockStore_GetRequestByID_Call struct {
 	*mock.Call
 }
 
-// IncrementFreeAnalysesUsed is a helper method to define mock.On call
+// GetRequestByID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID uuid.UUID
-func (_e *MockStore_Expecter) IncrementFreeAnalysesUsed(ctx interface{}, userID interface{}) *MockStore_IncrementFreeAnalysesUsed_Call {
-	return &MockStore_IncrementFreeAnalysesUsed_Call{Call: _e.mock.On("IncrementFreeAnalysesUsed", ctx, userID)}
+//   - id uuid.UUID
+func (_e *MockStore_Expecter) GetRequestByID(ctx interface{}, id interface{}) *MockStore_GetRequestByID_Call {
+	return &MockStore_GetRequestByID_Call{Call: _e.mock.On("GetRequestByID", ctx, id)}
 }
 
-func (_c *MockStore_IncrementFreeAnalysesUsed_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_IncrementFreeAnalysesUsed_Call {
+func (_c *MockStore_GetRequestByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockStore_GetRequestByID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *MockStore_IncrementFreeAnalysesUsed_Call) Return(_a0 int, _a1 error) *MockStore_IncrementFreeAnalysesUsed_Call {
+func (_c *MockStore_GetRequestByID_Call) Return(_a0 *models.Request, _a1 error) *MockStore_GetRequestByID_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_IncrementFreeAnalysesUsed_Call) RunAndReturn(run func(context.Context, uuid.UUID) (int, error)) *MockStore_IncrementFreeAnalysesUsed_Call {
+func (_c *MockStore_GetRequestByID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.Request, error)) *MockStore_GetRequestByID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// InvalidateUserPasswordResetTokens provides a mock function with given fields: ctx, userID
-func (_m *MockStore) InvalidateUserPasswordResetTokens(ctx context.Context, userID uuid.UUID) error {
-	ret := _m.Called(ctx, userID)
+// GetRequestsByUserID provides a mock function with given fields: ctx, userID, filter
+func (_m *MockStore) GetRequestsByUserID(ctx context.Context, userID uuid.UUID, filter repository.RequestFilter) ([]models.Request, error) {
+	ret := _m.Called(ctx, userID, filter)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InvalidateUserPasswordResetTokens")
+		panic("no return value specified for GetRequestsByUserID")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, userID)
+	var r0 []models.Request
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, repository.RequestFilter) ([]models.Request, error)); ok {
+		return rf(ctx, userID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, repository.RequestFilter) []models.Request); ok {
+		r0 = rf(ctx, userID, filter)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Request)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, repository.RequestFilter) error); ok {
+		r1 = rf(ctx, userID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// MockStore_InvalidateUserPasswordResetTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InvalidateUserPasswordResetTokens'
-type MockStore_InvalidateUserPasswordResetTokens_Call struct {
+// MockStore_GetRequestsByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRequestsByUserID'
+type MockStore_GetRequestsByUserID_Call struct {
 	*mock.Call
 }
 
-// InvalidateUserPasswordResetTokens is a helper method to define mock.On call
+// GetRequestsByUserID is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userID uuid.UUID
-func (_e *MockStore_Expecter) InvalidateUserPasswordResetTokens(ctx interface{}, userID interface{}) *MockStore_InvalidateUserPasswordResetTokens_Call {
-	return &MockStore_InvalidateUserPasswordResetTokens_Call{Call: _e.mock.On("InvalidateUserPasswordResetTokens", ctx, userID)}
+//   - filter repository.RequestFilter
+func (_e *MockStore_Expecter) GetRequestsByUserID(ctx interface{}, userID interface{}, filter interface{}) *MockStore_GetRequestsByUserID_Call {
+	return &MockStore_GetRequestsByUserID_Call{Call: _e.mock.On("GetRequestsByUserID", ctx, userID, filter)}
 }
 
-func (_c *MockStore_InvalidateUserPasswordResetTokens_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_InvalidateUserPasswordResetTokens_Call {
+func (_c *MockStore_GetRequestsByUserID_Call) Run(run func(ctx context.Context, userID uuid.UUID, filter repository.RequestFilter)) *MockStore_GetRequestsByUserID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(repository.RequestFilter))
 	})
 	return _c
 }
 
-func (_c *MockStore_InvalidateUserPasswordResetTokens_Call) Return(_a0 error) *MockStore_InvalidateUserPasswordResetTokens_Call {
-	_c.Call.Return(_a0)
+func (_c *MockStore_GetRequestsByUserID_Call) Return(_a0 []models.Request, _a1 error) *MockStore_GetRequestsByUserID_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_InvalidateUserPasswordResetTokens_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockStore_InvalidateUserPasswordResetTokens_Call {
+func (_c *MockStore_GetRequestsByUserID_Call) RunAndReturn(run func(context.Context, uuid.UUID, repository.RequestFilter) ([]models.Request, error)) *MockStore_GetRequestsByUserID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListPayments provides a mock function with given fields: ctx, limit, offset
-func (_m *MockStore) ListPayments(ctx context.Context, limit int, offset int) ([]repository.AdminPaymentRow, int, error) {
-	ret := _m.Called(ctx, limit, offset)
+// GetResponseByRequestID provides a mock function with given fields: ctx, requestID
+func (_m *MockStore) GetResponseByRequestID(ctx context.Context, requestID uuid.UUID) (*models.Response, error) {
+	ret := _m.Called(ctx, requestID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListPayments")
+		panic("no return value specified for GetResponseByRequestID")
 	}
 
-	var r0 []repository.AdminPaymentRow
-	var r1 int
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]repository.AdminPaymentRow, int, error)); ok {
-		return rf(ctx, limit, offset)
+	var r0 *models.Response
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.Response, error)); ok {
+		return rf(ctx, requestID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) []repository.AdminPaymentRow); ok {
-		r0 = rf(ctx, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.Response); ok {
+		r0 = rf(ctx, requestID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]repository.AdminPaymentRow)
+			r0 = ret.Get(0).(*models.Response)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
-		r1 = rf(ctx, limit, offset)
-	} else {
-		r1 = ret.Get(1).(int)
-	}
-
-	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
-		r2 = rf(ctx, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, requestID)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// MockStore_ListPayments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPayments'
-type MockStore_ListPayments_Call struct {
+// MockStore_GetResponseByRequestID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetResponseByRequestID'
+type MockStore_GetResponseByRequestID_Call struct {
 	*mock.Call
 }
 
-// ListPayments is a helper method to define mock.On call
+// GetResponseByRequestID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - limit int
-//   - offset int
-func (_e *MockStore_Expecter) ListPayments(ctx interface{}, limit interface{}, offset interface{}) *MockStore_ListPayments_Call {
-	return &MockStore_ListPayments_Call{Call: _e.mock.On("ListPayments", ctx, limit, offset)}
+//   - requestID uuid.UUID
+func (_e *MockStore_Expecter) GetResponseByRequestID(ctx interface{}, requestID interface{}) *MockStore_GetResponseByRequestID_Call {
+	return &MockStore_GetResponseByRequestID_Call{Call: _e.mock.On("GetResponseByRequestID", ctx, requestID)}
 }
 
-func (_c *MockStore_ListPayments_Call) Run(run func(ctx context.Context, limit int, offset int)) *MockStore_ListPayments_Call {
+func (_c *MockStore_GetResponseByRequestID_Call) Run(run func(ctx context.Context, requestID uuid.UUID)) *MockStore_GetResponseByRequestID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(int))
+		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *MockStore_ListPayments_Call) Return(_a0 []repository.AdminPaymentRow, _a1 int, _a2 error) *MockStore_ListPayments_Call {
-	_c.Call.Return(_a0, _a1, _a2)
+func (_c *MockStore_GetResponseByRequestID_Call) Return(_a0 *models.Response, _a1 error) *MockStore_GetResponseByRequestID_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_ListPayments_Call) RunAndReturn(run func(context.Context, int, int) ([]repository.AdminPaymentRow, int, error)) *MockStore_ListPayments_Call {
+func (_c *MockStore_GetResponseByRequestID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.Response, error)) *MockStore_GetResponseByRequestID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListRAGFeedback provides a mock function with given fields: ctx, limit, offset
-func (_m *MockStore) ListRAGFeedback(ctx context.Context, limit int, offset int) ([]repository.AdminFeedbackRow, int, error) {
-	ret := _m.Called(ctx, limit, offset)
+// GetRevokedRefreshTokenOwner provides a mock function with given fields: ctx, tokenHash
+func (_m *MockStore) GetRevokedRefreshTokenOwner(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	ret := _m.Called(ctx, tokenHash)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListRAGFeedback")
+		panic("no return value specified for GetRevokedRefreshTokenOwner")
 	}
 
-	var r0 []repository.AdminFeedbackRow
-	var r1 int
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]repository.AdminFeedbackRow, int, error)); ok {
-		return rf(ctx, limit, offset)
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (uuid.UUID, error)); ok {
+		return rf(ctx, tokenHash)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) []repository.AdminFeedbackRow); ok {
-		r0 = rf(ctx, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string) uuid.UUID); ok {
+		r0 = rf(ctx, tokenHash)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]repository.AdminFeedbackRow)
+			r0 = ret.Get(0).(uuid.UUID)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
-		r1 = rf(ctx, limit, offset)
-	} else {
-		r1 = ret.Get(1).(int)
-	}
-
-	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
-		r2 = rf(ctx, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// MockStore_ListRAGFeedback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRAGFeedback'
-type MockStore_ListRAGFeedback_Call struct {
+// MockStore_GetRevokedRefreshTokenOwner_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRevokedRefreshTokenOwner'
+type MockStore_GetRevokedRefreshTokenOwner_Call struct {
 	*mock.Call
 }
 
-// ListRAGFeedback is a helper method to define mock.On call
+// GetRevokedRefreshTokenOwner is a helper method to define mock.On call
 //   - ctx context.Context
-//   - limit int
-//   - offset int
-func (_e *MockStore_Expecter) ListRAGFeedback(ctx interface{}, limit interface{}, offset interface{}) *MockStore_ListRAGFeedback_Call {
-	return &MockStore_ListRAGFeedback_Call{Call: _e.mock.On("ListRAGFeedback", ctx, limit, offset)}
+//   - tokenHash string
+func (_e *MockStore_Expecter) GetRevokedRefreshTokenOwner(ctx interface{}, tokenHash interface{}) *MockStore_GetRevokedRefreshTokenOwner_Call {
+	return &MockStore_GetRevokedRefreshTokenOwner_Call{Call: _e.mock.On("GetRevokedRefreshTokenOwner", ctx, tokenHash)}
 }
 
-func (_c *MockStore_ListRAGFeedback_Call) Run(run func(ctx context.Context, limit int, offset int)) *MockStore_ListRAGFeedback_Call {
+func (_c *MockStore_GetRevokedRefreshTokenOwner_Call) Run(run func(ctx context.Context, tokenHash string)) *MockStore_GetRevokedRefreshTokenOwner_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(int))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_ListRAGFeedback_Call) Return(_a0 []repository.AdminFeedbackRow, _a1 int, _a2 error) *MockStore_ListRAGFeedback_Call {
-	_c.Call.Return(_a0, _a1, _a2)
+func (_c *MockStore_GetRevokedRefreshTokenOwner_Call) Return(_a0 uuid.UUID, _a1 error) *MockStore_GetRevokedRefreshTokenOwner_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_ListRAGFeedback_Call) RunAndReturn(run func(context.Context, int, int) ([]repository.AdminFeedbackRow, int, error)) *MockStore_ListRAGFeedback_Call {
+func (_c *MockStore_GetRevokedRefreshTokenOwner_Call) RunAndReturn(run func(context.Context, string) (uuid.UUID, error)) *MockStore_GetRevokedRefreshTokenOwner_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListUsers provides a mock function with given fields: ctx, limit, offset, search
-func (_m *MockStore) ListUsers(ctx context.Context, limit int, offset int, search string) ([]repository.AdminUserRow, int, error) {
-	ret := _m.Called(ctx, limit, offset, search)
+// GetSubscriptionExpiresAt provides a mock function with given fields: ctx, userID
+func (_m *MockStore) GetSubscriptionExpiresAt(ctx context.Context, userID uuid.UUID) (*time.Time, error) {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListUsers")
+		panic("no return value specified for GetSubscriptionExpiresAt")
 	}
 
-	var r0 []repository.AdminUserRow
-	var r1 int
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) ([]repository.AdminUserRow, int, error)); ok {
-		return rf(ctx, limit, offset, search)
+	var r0 *time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*time.Time, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) []repository.AdminUserRow); ok {
-		r0 = rf(ctx, limit, offset, search)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *time.Time); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]repository.AdminUserRow)
+			r0 = ret.Get(0).(*time.Time)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, int, string) int); ok {
-		r1 = rf(ctx, limit, offset, search)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
-		r1 = ret.Get(1).(int)
+		r1 = ret.Error(1)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, int, int, string) error); ok {
-		r2 = rf(ctx, limit, offset, search)
+	return r0, r1
+}
+
+// MockStore_GetSubscriptionExpiresAt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSubscriptionExpiresAt'
+type MockStore_GetSubscriptionExpiresAt_Call struct {
+	*mock.Call
+}
+
+// GetSubscriptionExpiresAt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockStore_Expecter) GetSubscriptionExpiresAt(ctx interface{}, userID interface{}) *MockStore_GetSubscriptionExpiresAt_Call {
+	return &MockStore_GetSubscriptionExpiresAt_Call{Call: _e.mock.On("GetSubscriptionExpiresAt", ctx, userID)}
+}
+
+func (_c *MockStore_GetSubscriptionExpiresAt_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_GetSubscriptionExpiresAt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockStore_GetSubscriptionExpiresAt_Call) Return(_a0 *time.Time, _a1 error) *MockStore_GetSubscriptionExpiresAt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_GetSubscriptionExpiresAt_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*time.Time, error)) *MockStore_GetSubscriptionExpiresAt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByEmail provides a mock function with given fields: ctx, email
+func (_m *MockStore) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByEmail")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, email)
 	} else {
-		r2 = ret.Error(2)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
 	}
 
-	return r0, r1, r2
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// MockStore_ListUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUsers'
-type MockStore_ListUsers_Call struct {
+// MockStore_GetUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByEmail'
+type MockStore_GetUserByEmail_Call struct {
 	*mock.Call
 }
 
-// ListUsers is a helper method to define mock.On call
+// GetUserByEmail is a helper method to define mock.On call
 //   - ctx context.Context
-//   - limit int
-//   - offset int
-//   - search string
-func (_e *MockStore_Expecter) ListUsers(ctx interface{}, limit interface{}, offset interface{}, search interface{}) *MockStore_ListUsers_Call {
-	return &MockStore_ListUsers_Call{Call: _e.mock.On("ListUsers", ctx, limit, offset, search)}
+//   - email string
+func (_e *MockStore_Expecter) GetUserByEmail(ctx interface{}, email interface{}) *MockStore_GetUserByEmail_Call {
+	return &MockStore_GetUserByEmail_Call{Call: _e.mock.On("GetUserByEmail", ctx, email)}
 }
 
-func (_c *MockStore_ListUsers_Call) Run(run func(ctx context.Context, limit int, offset int, search string)) *MockStore_ListUsers_Call {
+func (_c *MockStore_GetUserByEmail_Call) Run(run func(ctx context.Context, email string)) *MockStore_GetUserByEmail_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_ListUsers_Call) Return(_a0 []repository.AdminUserRow, _a1 int, _a2 error) *MockStore_ListUsers_Call {
-	_c.Call.Return(_a0, _a1, _a2)
+func (_c *MockStore_GetUserByEmail_Call) Return(_a0 *models.User, _a1 error) *MockStore_GetUserByEmail_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_ListUsers_Call) RunAndReturn(run func(context.Context, int, int, string) ([]repository.AdminUserRow, int, error)) *MockStore_ListUsers_Call {
+func (_c *MockStore_GetUserByEmail_Call) RunAndReturn(run func(context.Context, string) (*models.User, error)) *MockStore_GetUserByEmail_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// LoadRolePermissions provides a mock function with given fields: ctx
-func (_m *MockStore) LoadRolePermissions(ctx context.Context) (map[string][]string, error) {
-	ret := _m.Called(ctx)
+// GetUserByID provides a mock function with given fields: ctx, userID
+func (_m *MockStore) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for LoadRolePermissions")
+		panic("no return value specified for GetUserByID")
 	}
 
-	var r0 map[string][]string
+	var r0 *models.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (map[string][]string, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.User, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) map[string][]string); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.User); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string][]string)
+			r0 = ret.Get(0).(*models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2240,45 +2668,1303 @@ func (_m *MockStore) LoadRolePermissions(ctx context.Context) (map[string][]stri
 	return r0, r1
 }
 
-// MockStore_LoadRolePermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LoadRolePermissions'
-type MockStore_LoadRolePermissions_Call struct {
+// MockStore_GetUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByID'
+type MockStore_GetUserByID_Call struct {
+	*mock.Call
+}
+
+// GetUserByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockStore_Expecter) GetUserByID(ctx interface{}, userID interface{}) *MockStore_GetUserByID_Call {
+	return &MockStore_GetUserByID_Call{Call: _e.mock.On("GetUserByID", ctx, userID)}
+}
+
+func (_c *MockStore_GetUserByID_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_GetUserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockStore_GetUserByID_Call) Return(_a0 *models.User, _a1 error) *MockStore_GetUserByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_GetUserByID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.User, error)) *MockStore_GetUserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetValidEmailVerificationToken provides a mock function with given fields: ctx, tokenHash
+func (_m *MockStore) GetValidEmailVerificationToken(ctx context.Context, tokenHash string) (*models.EmailVerificationToken, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetValidEmailVerificationToken")
+	}
+
+	var r0 *models.EmailVerificationToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.EmailVerificationToken, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.EmailVerificationToken); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmailVerificationToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_GetValidEmailVerificationToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetValidEmailVerificationToken'
+type MockStore_GetValidEmailVerificationToken_Call struct {
+	*mock.Call
+}
+
+// GetValidEmailVerificationToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+func (_e *MockStore_Expecter) GetValidEmailVerificationToken(ctx interface{}, tokenHash interface{}) *MockStore_GetValidEmailVerificationToken_Call {
+	return &MockStore_GetValidEmailVerificationToken_Call{Call: _e.mock.On("GetValidEmailVerificationToken", ctx, tokenHash)}
+}
+
+func (_c *MockStore_GetValidEmailVerificationToken_Call) Run(run func(ctx context.Context, tokenHash string)) *MockStore_GetValidEmailVerificationToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_GetValidEmailVerificationToken_Call) Return(_a0 *models.EmailVerificationToken, _a1 error) *MockStore_GetValidEmailVerificationToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_GetValidEmailVerificationToken_Call) RunAndReturn(run func(context.Context, string) (*models.EmailVerificationToken, error)) *MockStore_GetValidEmailVerificationToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetValidPasswordResetToken provides a mock function with given fields: ctx, tokenHash
+func (_m *MockStore) GetValidPasswordResetToken(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetValidPasswordResetToken")
+	}
+
+	var r0 *models.PasswordResetToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.PasswordResetToken, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.PasswordResetToken); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PasswordResetToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_GetValidPasswordResetToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetValidPasswordResetToken'
+type MockStore_GetValidPasswordResetToken_Call struct {
+	*mock.Call
+}
+
+// GetValidPasswordResetToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+func (_e *MockStore_Expecter) GetValidPasswordResetToken(ctx interface{}, tokenHash interface{}) *MockStore_GetValidPasswordResetToken_Call {
+	return &MockStore_GetValidPasswordResetToken_Call{Call: _e.mock.On("GetValidPasswordResetToken", ctx, tokenHash)}
+}
+
+func (_c *MockStore_GetValidPasswordResetToken_Call) Run(run func(ctx context.Context, tokenHash string)) *MockStore_GetValidPasswordResetToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_GetValidPasswordResetToken_Call) Return(_a0 *models.PasswordResetToken, _a1 error) *MockStore_GetValidPasswordResetToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_GetValidPasswordResetToken_Call) RunAndReturn(run func(context.Context, string) (*models.PasswordResetToken, error)) *MockStore_GetValidPasswordResetToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HasPendingPayment provides a mock function with given fields: ctx, userID, paymentType
+func (_m *MockStore) HasPendingPayment(ctx context.Context, userID uuid.UUID, paymentType string) (bool, error) {
+	ret := _m.Called(ctx, userID, paymentType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasPendingPayment")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (bool, error)); ok {
+		return rf(ctx, userID, paymentType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) bool); ok {
+		r0 = rf(ctx, userID, paymentType)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, userID, paymentType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_HasPendingPayment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HasPendingPayment'
+type MockStore_HasPendingPayment_Call struct {
+	*mock.Call
+}
+
+// HasPendingPayment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - paymentType string
+func (_e *MockStore_Expecter) HasPendingPayment(ctx interface{}, userID interface{}, paymentType interface{}) *MockStore_HasPendingPayment_Call {
+	return &MockStore_HasPendingPayment_Call{Call: _e.mock.On("HasPendingPayment", ctx, userID, paymentType)}
+}
+
+func (_c *MockStore_HasPendingPayment_Call) Run(run func(ctx context.Context, userID uuid.UUID, paymentType string)) *MockStore_HasPendingPayment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_HasPendingPayment_Call) Return(_a0 bool, _a1 error) *MockStore_HasPendingPayment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_HasPendingPayment_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) (bool, error)) *MockStore_HasPendingPayment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IncrementFreeAnalysesUsed provides a mock function with given fields: ctx, userID
+func (_m *MockStore) IncrementFreeAnalysesUsed(ctx context.Context, userID uuid.UUID) (int, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementFreeAnalysesUsed")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_IncrementFreeAnalysesUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncrementFreeAnalysesUsed'
+type MockStore_IncrementFreeAnalysesUsed_Call struct {
+	*mock.Call
+}
+
+// IncrementFreeAnalysesUsed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockStore_Expecter) IncrementFreeAnalysesUsed(ctx interface{}, userID interface{}) *MockStore_IncrementFreeAnalysesUsed_Call {
+	return &MockStore_IncrementFreeAnalysesUsed_Call{Call: _e.mock.On("IncrementFreeAnalysesUsed", ctx, userID)}
+}
+
+func (_c *MockStore_IncrementFreeAnalysesUsed_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_IncrementFreeAnalysesUsed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockStore_IncrementFreeAnalysesUsed_Call) Return(_a0 int, _a1 error) *MockStore_IncrementFreeAnalysesUsed_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_IncrementFreeAnalysesUsed_Call) RunAndReturn(run func(context.Context, uuid.UUID) (int, error)) *MockStore_IncrementFreeAnalysesUsed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InvalidateUserEmailVerificationTokens provides a mock function with given fields: ctx, userID
+func (_m *MockStore) InvalidateUserEmailVerificationTokens(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InvalidateUserEmailVerificationTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_InvalidateUserEmailVerificationTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InvalidateUserEmailVerificationTokens'
+type MockStore_InvalidateUserEmailVerificationTokens_Call struct {
+	*mock.Call
+}
+
+// InvalidateUserEmailVerificationTokens is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockStore_Expecter) InvalidateUserEmailVerificationTokens(ctx interface{}, userID interface{}) *MockStore_InvalidateUserEmailVerificationTokens_Call {
+	return &MockStore_InvalidateUserEmailVerificationTokens_Call{Call: _e.mock.On("InvalidateUserEmailVerificationTokens", ctx, userID)}
+}
+
+func (_c *MockStore_InvalidateUserEmailVerificationTokens_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_InvalidateUserEmailVerificationTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockStore_InvalidateUserEmailVerificationTokens_Call) Return(_a0 error) *MockStore_InvalidateUserEmailVerificationTokens_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_InvalidateUserEmailVerificationTokens_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockStore_InvalidateUserEmailVerificationTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InvalidateUserPasswordResetTokens provides a mock function with given fields: ctx, userID
+func (_m *MockStore) InvalidateUserPasswordResetTokens(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InvalidateUserPasswordResetTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_InvalidateUserPasswordResetTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InvalidateUserPasswordResetTokens'
+type MockStore_InvalidateUserPasswordResetTokens_Call struct {
+	*mock.Call
+}
+
+// InvalidateUserPasswordResetTokens is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockStore_Expecter) InvalidateUserPasswordResetTokens(ctx interface{}, userID interface{}) *MockStore_InvalidateUserPasswordResetTokens_Call {
+	return &MockStore_InvalidateUserPasswordResetTokens_Call{Call: _e.mock.On("InvalidateUserPasswordResetTokens", ctx, userID)}
+}
+
+func (_c *MockStore_InvalidateUserPasswordResetTokens_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_InvalidateUserPasswordResetTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockStore_InvalidateUserPasswordResetTokens_Call) Return(_a0 error) *MockStore_InvalidateUserPasswordResetTokens_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_InvalidateUserPasswordResetTokens_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockStore_InvalidateUserPasswordResetTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAudit provides a mock function with given fields: ctx, filter
+func (_m *MockStore) ListAudit(ctx context.Context, filter repository.AuditFilter) ([]models.AuditEntry, int, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAudit")
+	}
+
+	var r0 []models.AuditEntry
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.AuditFilter) ([]models.AuditEntry, int, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.AuditFilter) []models.AuditEntry); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.AuditFilter) int); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, repository.AuditFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockStore_ListAudit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAudit'
+type MockStore_ListAudit_Call struct {
+	*mock.Call
+}
+
+// ListAudit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.AuditFilter
+func (_e *MockStore_Expecter) ListAudit(ctx interface{}, filter interface{}) *MockStore_ListAudit_Call {
+	return &MockStore_ListAudit_Call{Call: _e.mock.On("ListAudit", ctx, filter)}
+}
+
+func (_c *MockStore_ListAudit_Call) Run(run func(ctx context.Context, filter repository.AuditFilter)) *MockStore_ListAudit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.AuditFilter))
+	})
+	return _c
+}
+
+func (_c *MockStore_ListAudit_Call) Return(_a0 []models.AuditEntry, _a1 int, _a2 error) *MockStore_ListAudit_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockStore_ListAudit_Call) RunAndReturn(run func(context.Context, repository.AuditFilter) ([]models.AuditEntry, int, error)) *MockStore_ListAudit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPayments provides a mock function with given fields: ctx, limit, offset
+func (_m *MockStore) ListPayments(ctx context.Context, limit int, offset int) ([]repository.AdminPaymentRow, int, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPayments")
+	}
+
+	var r0 []repository.AdminPaymentRow
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]repository.AdminPaymentRow, int, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []repository.AdminPaymentRow); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.AdminPaymentRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockStore_ListPayments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPayments'
+type MockStore_ListPayments_Call struct {
+	*mock.Call
+}
+
+// ListPayments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - offset int
+func (_e *MockStore_Expecter) ListPayments(ctx interface{}, limit interface{}, offset interface{}) *MockStore_ListPayments_Call {
+	return &MockStore_ListPayments_Call{Call: _e.mock.On("ListPayments", ctx, limit, offset)}
+}
+
+func (_c *MockStore_ListPayments_Call) Run(run func(ctx context.Context, limit int, offset int)) *MockStore_ListPayments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockStore_ListPayments_Call) Return(_a0 []repository.AdminPaymentRow, _a1 int, _a2 error) *MockStore_ListPayments_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockStore_ListPayments_Call) RunAndReturn(run func(context.Context, int, int) ([]repository.AdminPaymentRow, int, error)) *MockStore_ListPayments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPermissions provides a mock function with given fields: ctx
+func (_m *MockStore) ListPermissions(ctx context.Context) ([]models.Permission, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPermissions")
+	}
+
+	var r0 []models.Permission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Permission, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Permission); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Permission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_ListPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPermissions'
+type MockStore_ListPermissions_Call struct {
+	*mock.Call
+}
+
+// ListPermissions is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockStore_Expecter) ListPermissions(ctx interface{}) *MockStore_ListPermissions_Call {
+	return &MockStore_ListPermissions_Call{Call: _e.mock.On("ListPermissions", ctx)}
+}
+
+func (_c *MockStore_ListPermissions_Call) Run(run func(ctx context.Context)) *MockStore_ListPermissions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockStore_ListPermissions_Call) Return(_a0 []models.Permission, _a1 error) *MockStore_ListPermissions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_ListPermissions_Call) RunAndReturn(run func(context.Context) ([]models.Permission, error)) *MockStore_ListPermissions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRAGFeedback provides a mock function with given fields: ctx, limit, offset
+func (_m *MockStore) ListRAGFeedback(ctx context.Context, limit int, offset int) ([]repository.AdminFeedbackRow, int, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRAGFeedback")
+	}
+
+	var r0 []repository.AdminFeedbackRow
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]repository.AdminFeedbackRow, int, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []repository.AdminFeedbackRow); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.AdminFeedbackRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockStore_ListRAGFeedback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRAGFeedback'
+type MockStore_ListRAGFeedback_Call struct {
+	*mock.Call
+}
+
+// ListRAGFeedback is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - offset int
+func (_e *MockStore_Expecter) ListRAGFeedback(ctx interface{}, limit interface{}, offset interface{}) *MockStore_ListRAGFeedback_Call {
+	return &MockStore_ListRAGFeedback_Call{Call: _e.mock.On("ListRAGFeedback", ctx, limit, offset)}
+}
+
+func (_c *MockStore_ListRAGFeedback_Call) Run(run func(ctx context.Context, limit int, offset int)) *MockStore_ListRAGFeedback_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockStore_ListRAGFeedback_Call) Return(_a0 []repository.AdminFeedbackRow, _a1 int, _a2 error) *MockStore_ListRAGFeedback_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockStore_ListRAGFeedback_Call) RunAndReturn(run func(context.Context, int, int) ([]repository.AdminFeedbackRow, int, error)) *MockStore_ListRAGFeedback_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRequests provides a mock function with given fields: ctx, filter
+func (_m *MockStore) ListRequests(ctx context.Context, filter repository.RequestFilter) ([]models.Request, int, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRequests")
+	}
+
+	var r0 []models.Request
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.RequestFilter) ([]models.Request, int, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.RequestFilter) []models.Request); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Request)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.RequestFilter) int); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, repository.RequestFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockStore_ListRequests_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRequests'
+type MockStore_ListRequests_Call struct {
+	*mock.Call
+}
+
+// ListRequests is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.RequestFilter
+func (_e *MockStore_Expecter) ListRequests(ctx interface{}, filter interface{}) *MockStore_ListRequests_Call {
+	return &MockStore_ListRequests_Call{Call: _e.mock.On("ListRequests", ctx, filter)}
+}
+
+func (_c *MockStore_ListRequests_Call) Run(run func(ctx context.Context, filter repository.RequestFilter)) *MockStore_ListRequests_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.RequestFilter))
+	})
+	return _c
+}
+
+func (_c *MockStore_ListRequests_Call) Return(_a0 []models.Request, _a1 int, _a2 error) *MockStore_ListRequests_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockStore_ListRequests_Call) RunAndReturn(run func(context.Context, repository.RequestFilter) ([]models.Request, int, error)) *MockStore_ListRequests_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRoles provides a mock function with given fields: ctx
+func (_m *MockStore) ListRoles(ctx context.Context) ([]models.Role, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRoles")
+	}
+
+	var r0 []models.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Role, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Role); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_ListRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRoles'
+type MockStore_ListRoles_Call struct {
+	*mock.Call
+}
+
+// ListRoles is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockStore_Expecter) ListRoles(ctx interface{}) *MockStore_ListRoles_Call {
+	return &MockStore_ListRoles_Call{Call: _e.mock.On("ListRoles", ctx)}
+}
+
+func (_c *MockStore_ListRoles_Call) Run(run func(ctx context.Context)) *MockStore_ListRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockStore_ListRoles_Call) Return(_a0 []models.Role, _a1 error) *MockStore_ListRoles_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_ListRoles_Call) RunAndReturn(run func(context.Context) ([]models.Role, error)) *MockStore_ListRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUsers provides a mock function with given fields: ctx, limit, offset, search
+func (_m *MockStore) ListUsers(ctx context.Context, limit int, offset int, search string) ([]repository.AdminUserRow, int, error) {
+	ret := _m.Called(ctx, limit, offset, search)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 []repository.AdminUserRow
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) ([]repository.AdminUserRow, int, error)); ok {
+		return rf(ctx, limit, offset, search)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) []repository.AdminUserRow); ok {
+		r0 = rf(ctx, limit, offset, search)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.AdminUserRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string) int); ok {
+		r1 = rf(ctx, limit, offset, search)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int, string) error); ok {
+		r2 = rf(ctx, limit, offset, search)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockStore_ListUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUsers'
+type MockStore_ListUsers_Call struct {
+	*mock.Call
+}
+
+// ListUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - offset int
+//   - search string
+func (_e *MockStore_Expecter) ListUsers(ctx interface{}, limit interface{}, offset interface{}, search interface{}) *MockStore_ListUsers_Call {
+	return &MockStore_ListUsers_Call{Call: _e.mock.On("ListUsers", ctx, limit, offset, search)}
+}
+
+func (_c *MockStore_ListUsers_Call) Run(run func(ctx context.Context, limit int, offset int, search string)) *MockStore_ListUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_ListUsers_Call) Return(_a0 []repository.AdminUserRow, _a1 int, _a2 error) *MockStore_ListUsers_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockStore_ListUsers_Call) RunAndReturn(run func(context.Context, int, int, string) ([]repository.AdminUserRow, int, error)) *MockStore_ListUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LoadRolePermissions provides a mock function with given fields: ctx
+func (_m *MockStore) LoadRolePermissions(ctx context.Context) (map[string][]string, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoadRolePermissions")
+	}
+
+	var r0 map[string][]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[string][]string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[string][]string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_LoadRolePermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LoadRolePermissions'
+type MockStore_LoadRolePermissions_Call struct {
 	*mock.Call
 }
 
 // LoadRolePermissions is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *MockStore_Expecter) LoadRolePermissions(ctx interface{}) *MockStore_LoadRolePermissions_Call {
-	return &MockStore_LoadRolePermissions_Call{Call: _e.mock.On("LoadRolePermissions", ctx)}
+func (_e *MockStore_Expecter) LoadRolePermissions(ctx interface{}) *MockStore_LoadRolePermissions_Call {
+	return &MockStore_LoadRolePermissions_Call{Call: _e.mock.On("LoadRolePermissions", ctx)}
+}
+
+func (_c *MockStore_LoadRolePermissions_Call) Run(run func(ctx context.Context)) *MockStore_LoadRolePermissions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockStore_LoadRolePermissions_Call) Return(_a0 map[string][]string, _a1 error) *MockStore_LoadRolePermissions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_LoadRolePermissions_Call) RunAndReturn(run func(context.Context) (map[string][]string, error)) *MockStore_LoadRolePermissions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkEmailVerificationTokenUsed provides a mock function with given fields: ctx, tokenID
+func (_m *MockStore) MarkEmailVerificationTokenUsed(ctx context.Context, tokenID uuid.UUID) error {
+	ret := _m.Called(ctx, tokenID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkEmailVerificationTokenUsed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, tokenID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_MarkEmailVerificationTokenUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkEmailVerificationTokenUsed'
+type MockStore_MarkEmailVerificationTokenUsed_Call struct {
+	*mock.Call
+}
+
+// MarkEmailVerificationTokenUsed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenID uuid.UUID
+func (_e *MockStore_Expecter) MarkEmailVerificationTokenUsed(ctx interface{}, tokenID interface{}) *MockStore_MarkEmailVerificationTokenUsed_Call {
+	return &MockStore_MarkEmailVerificationTokenUsed_Call{Call: _e.mock.On("MarkEmailVerificationTokenUsed", ctx, tokenID)}
+}
+
+func (_c *MockStore_MarkEmailVerificationTokenUsed_Call) Run(run func(ctx context.Context, tokenID uuid.UUID)) *MockStore_MarkEmailVerificationTokenUsed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockStore_MarkEmailVerificationTokenUsed_Call) Return(_a0 error) *MockStore_MarkEmailVerificationTokenUsed_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_MarkEmailVerificationTokenUsed_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockStore_MarkEmailVerificationTokenUsed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkEmailVerified provides a mock function with given fields: ctx, userID
+func (_m *MockStore) MarkEmailVerified(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkEmailVerified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_MarkEmailVerified_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkEmailVerified'
+type MockStore_MarkEmailVerified_Call struct {
+	*mock.Call
+}
+
+// MarkEmailVerified is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockStore_Expecter) MarkEmailVerified(ctx interface{}, userID interface{}) *MockStore_MarkEmailVerified_Call {
+	return &MockStore_MarkEmailVerified_Call{Call: _e.mock.On("MarkEmailVerified", ctx, userID)}
+}
+
+func (_c *MockStore_MarkEmailVerified_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_MarkEmailVerified_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockStore_MarkEmailVerified_Call) Return(_a0 error) *MockStore_MarkEmailVerified_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_MarkEmailVerified_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockStore_MarkEmailVerified_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkPasswordResetTokenUsed provides a mock function with given fields: ctx, tokenID
+func (_m *MockStore) MarkPasswordResetTokenUsed(ctx context.Context, tokenID uuid.UUID) error {
+	ret := _m.Called(ctx, tokenID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkPasswordResetTokenUsed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, tokenID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_MarkPasswordResetTokenUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkPasswordResetTokenUsed'
+type MockStore_MarkPasswordResetTokenUsed_Call struct {
+	*mock.Call
+}
+
+// MarkPasswordResetTokenUsed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenID uuid.UUID
+func (_e *MockStore_Expecter) MarkPasswordResetTokenUsed(ctx interface{}, tokenID interface{}) *MockStore_MarkPasswordResetTokenUsed_Call {
+	return &MockStore_MarkPasswordResetTokenUsed_Call{Call: _e.mock.On("MarkPasswordResetTokenUsed", ctx, tokenID)}
+}
+
+func (_c *MockStore_MarkPasswordResetTokenUsed_Call) Run(run func(ctx context.Context, tokenID uuid.UUID)) *MockStore_MarkPasswordResetTokenUsed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockStore_MarkPasswordResetTokenUsed_Call) Return(_a0 error) *MockStore_MarkPasswordResetTokenUsed_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_MarkPasswordResetTokenUsed_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockStore_MarkPasswordResetTokenUsed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkRequestQueued provides a mock function with given fields: ctx, requestID
+func (_m *MockStore) MarkRequestQueued(ctx context.Context, requestID uuid.UUID) error {
+	ret := _m.Called(ctx, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkRequestQueued")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_MarkRequestQueued_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkRequestQueued'
+type MockStore_MarkRequestQueued_Call struct {
+	*mock.Call
+}
+
+// MarkRequestQueued is a helper method to define mock.On call
+//   - ctx context.Context
+//   - requestID uuid.UUID
+func (_e *MockStore_Expecter) MarkRequestQueued(ctx interface{}, requestID interface{}) *MockStore_MarkRequestQueued_Call {
+	return &MockStore_MarkRequestQueued_Call{Call: _e.mock.On("MarkRequestQueued", ctx, requestID)}
+}
+
+func (_c *MockStore_MarkRequestQueued_Call) Run(run func(ctx context.Context, requestID uuid.UUID)) *MockStore_MarkRequestQueued_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockStore_MarkRequestQueued_Call) Return(_a0 error) *MockStore_MarkRequestQueued_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_MarkRequestQueued_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockStore_MarkRequestQueued_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Ping provides a mock function with given fields: ctx
+func (_m *MockStore) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_Ping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ping'
+type MockStore_Ping_Call struct {
+	*mock.Call
+}
+
+// Ping is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockStore_Expecter) Ping(ctx interface{}) *MockStore_Ping_Call {
+	return &MockStore_Ping_Call{Call: _e.mock.On("Ping", ctx)}
+}
+
+func (_c *MockStore_Ping_Call) Run(run func(ctx context.Context)) *MockStore_Ping_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockStore_Ping_Call) Return(_a0 error) *MockStore_Ping_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_Ping_Call) RunAndReturn(run func(context.Context) error) *MockStore_Ping_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordPromoCodeUsage provides a mock function with given fields: ctx, usage
+func (_m *MockStore) RecordPromoCodeUsage(ctx context.Context, usage *models.PromoCodeUsage) error {
+	ret := _m.Called(ctx, usage)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordPromoCodeUsage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PromoCodeUsage) error); ok {
+		r0 = rf(ctx, usage)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_RecordPromoCodeUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordPromoCodeUsage'
+type MockStore_RecordPromoCodeUsage_Call struct {
+	*mock.Call
+}
+
+// RecordPromoCodeUsage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - usage *models.PromoCodeUsage
+func (_e *MockStore_Expecter) RecordPromoCodeUsage(ctx interface{}, usage interface{}) *MockStore_RecordPromoCodeUsage_Call {
+	return &MockStore_RecordPromoCodeUsage_Call{Call: _e.mock.On("RecordPromoCodeUsage", ctx, usage)}
+}
+
+func (_c *MockStore_RecordPromoCodeUsage_Call) Run(run func(ctx context.Context, usage *models.PromoCodeUsage)) *MockStore_RecordPromoCodeUsage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.PromoCodeUsage))
+	})
+	return _c
+}
+
+func (_c *MockStore_RecordPromoCodeUsage_Call) Return(_a0 error) *MockStore_RecordPromoCodeUsage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_RecordPromoCodeUsage_Call) RunAndReturn(run func(context.Context, *models.PromoCodeUsage) error) *MockStore_RecordPromoCodeUsage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeAllUserRefreshTokens provides a mock function with given fields: ctx, userID
+func (_m *MockStore) RevokeAllUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAllUserRefreshTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_RevokeAllUserRefreshTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeAllUserRefreshTokens'
+type MockStore_RevokeAllUserRefreshTokens_Call struct {
+	*mock.Call
+}
+
+// RevokeAllUserRefreshTokens is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockStore_Expecter) RevokeAllUserRefreshTokens(ctx interface{}, userID interface{}) *MockStore_RevokeAllUserRefreshTokens_Call {
+	return &MockStore_RevokeAllUserRefreshTokens_Call{Call: _e.mock.On("RevokeAllUserRefreshTokens", ctx, userID)}
+}
+
+func (_c *MockStore_RevokeAllUserRefreshTokens_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_RevokeAllUserRefreshTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockStore_RevokeAllUserRefreshTokens_Call) Return(_a0 error) *MockStore_RevokeAllUserRefreshTokens_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_RevokeAllUserRefreshTokens_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockStore_RevokeAllUserRefreshTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeRefreshToken provides a mock function with given fields: ctx, tokenHash
+func (_m *MockStore) RevokeRefreshToken(ctx context.Context, tokenHash string) error {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_RevokeRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeRefreshToken'
+type MockStore_RevokeRefreshToken_Call struct {
+	*mock.Call
+}
+
+// RevokeRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+func (_e *MockStore_Expecter) RevokeRefreshToken(ctx interface{}, tokenHash interface{}) *MockStore_RevokeRefreshToken_Call {
+	return &MockStore_RevokeRefreshToken_Call{Call: _e.mock.On("RevokeRefreshToken", ctx, tokenHash)}
+}
+
+func (_c *MockStore_RevokeRefreshToken_Call) Run(run func(ctx context.Context, tokenHash string)) *MockStore_RevokeRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_RevokeRefreshToken_Call) Return(_a0 error) *MockStore_RevokeRefreshToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_RevokeRefreshToken_Call) RunAndReturn(run func(context.Context, string) error) *MockStore_RevokeRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RunTx provides a mock function with given fields: ctx, fn
+func (_m *MockStore) RunTx(ctx context.Context, fn func(pgx.Tx) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(pgx.Tx) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_RunTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunTx'
+type MockStore_RunTx_Call struct {
+	*mock.Call
+}
+
+// RunTx is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fn func(pgx.Tx) error
+func (_e *MockStore_Expecter) RunTx(ctx interface{}, fn interface{}) *MockStore_RunTx_Call {
+	return &MockStore_RunTx_Call{Call: _e.mock.On("RunTx", ctx, fn)}
 }
 
-func (_c *MockStore_LoadRolePermissions_Call) Run(run func(ctx context.Context)) *MockStore_LoadRolePermissions_Call {
+func (_c *MockStore_RunTx_Call) Run(run func(ctx context.Context, fn func(pgx.Tx) error)) *MockStore_RunTx_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(func(pgx.Tx) error))
 	})
 	return _c
 }
 
-func (_c *MockStore_LoadRolePermissions_Call) Return(_a0 map[string][]string, _a1 error) *MockStore_LoadRolePermissions_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockStore_RunTx_Call) Return(_a0 error) *MockStore_RunTx_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStore_LoadRolePermissions_Call) RunAndReturn(run func(context.Context) (map[string][]string, error)) *MockStore_LoadRolePermissions_Call {
+func (_c *MockStore_RunTx_Call) RunAndReturn(run func(context.Context, func(pgx.Tx) error) error) *MockStore_RunTx_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// MarkPasswordResetTokenUsed provides a mock function with given fields: ctx, tokenID
-func (_m *MockStore) MarkPasswordResetTokenUsed(ctx context.Context, tokenID uuid.UUID) error {
-	ret := _m.Called(ctx, tokenID)
+// SaveCacheEntry provides a mock function with given fields: ctx, question, embedding, answer, sourceMeta
+func (_m *MockStore) SaveCacheEntry(ctx context.Context, question string, embedding []float64, answer string, sourceMeta string) error {
+	ret := _m.Called(ctx, question, embedding, answer, sourceMeta)
 
 	if len(ret) == 0 {
-		panic("no return value specified for MarkPasswordResetTokenUsed")
+		panic("no return value specified for SaveCacheEntry")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, tokenID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []float64, string, string) error); ok {
+		r0 = rf(ctx, question, embedding, answer, sourceMeta)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -2286,46 +3972,49 @@ func (_m *MockStore) MarkPasswordResetTokenUsed(ctx context.Context, tokenID uui
 	return r0
 }
 
-// MockStore_MarkPasswordResetTokenUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkPasswordResetTokenUsed'
-type MockStore_MarkPasswordResetTokenUsed_Call struct {
+// MockStore_SaveCacheEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveCacheEntry'
+type MockStore_SaveCacheEntry_Call struct {
 	*mock.Call
 }
 
-// MarkPasswordResetTokenUsed is a helper method to define mock.On call
+// SaveCacheEntry is a helper method to define mock.On call
 //   - ctx context.Context
-//   - tokenID uuid.UUID
-func (_e *MockStore_Expecter) MarkPasswordResetTokenUsed(ctx interface{}, tokenID interface{}) *MockStore_MarkPasswordResetTokenUsed_Call {
-	return &MockStore_MarkPasswordResetTokenUsed_Call{Call: _e.mock.On("MarkPasswordResetTokenUsed", ctx, tokenID)}
+//   - question string
+//   - embedding []float64
+//   - answer string
+//   - sourceMeta string
+func (_e *MockStore_Expecter) SaveCacheEntry(ctx interface{}, question interface{}, embedding interface{}, answer interface{}, sourceMeta interface{}) *MockStore_SaveCacheEntry_Call {
+	return &MockStore_SaveCacheEntry_Call{Call: _e.mock.On("SaveCacheEntry", ctx, question, embedding, answer, sourceMeta)}
 }
 
-func (_c *MockStore_MarkPasswordResetTokenUsed_Call) Run(run func(ctx context.Context, tokenID uuid.UUID)) *MockStore_MarkPasswordResetTokenUsed_Call {
+func (_c *MockStore_SaveCacheEntry_Call) Run(run func(ctx context.Context, question string, embedding []float64, answer string, sourceMeta string)) *MockStore_SaveCacheEntry_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(string), args[2].([]float64), args[3].(string), args[4].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_MarkPasswordResetTokenUsed_Call) Return(_a0 error) *MockStore_MarkPasswordResetTokenUsed_Call {
+func (_c *MockStore_SaveCacheEntry_Call) Return(_a0 error) *MockStore_SaveCacheEntry_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStore_MarkPasswordResetTokenUsed_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockStore_MarkPasswordResetTokenUsed_Call {
+func (_c *MockStore_SaveCacheEntry_Call) RunAndReturn(run func(context.Context, string, []float64, string, string) error) *MockStore_SaveCacheEntry_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Ping provides a mock function with given fields: ctx
-func (_m *MockStore) Ping(ctx context.Context) error {
-	ret := _m.Called(ctx)
+// SetTwoFactorSecret provides a mock function with given fields: ctx, userID, secret
+func (_m *MockStore) SetTwoFactorSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	ret := _m.Called(ctx, userID, secret)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Ping")
+		panic("no return value specified for SetTwoFactorSecret")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, userID, secret)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -2333,45 +4022,47 @@ func (_m *MockStore) Ping(ctx context.Context) error {
 	return r0
 }
 
-// MockStore_Ping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ping'
-type MockStore_Ping_Call struct {
+// MockStore_SetTwoFactorSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetTwoFactorSecret'
+type MockStore_SetTwoFactorSecret_Call struct {
 	*mock.Call
 }
 
-// Ping is a helper method to define mock.On call
+// SetTwoFactorSecret is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *MockStore_Expecter) Ping(ctx interface{}) *MockStore_Ping_Call {
-	return &MockStore_Ping_Call{Call: _e.mock.On("Ping", ctx)}
+//   - userID uuid.UUID
+//   - secret string
+func (_e *MockStore_Expecter) SetTwoFactorSecret(ctx interface{}, userID interface{}, secret interface{}) *MockStore_SetTwoFactorSecret_Call {
+	return &MockStore_SetTwoFactorSecret_Call{Call: _e.mock.On("SetTwoFactorSecret", ctx, userID, secret)}
 }
 
-func (_c *MockStore_Ping_Call) Run(run func(ctx context.Context)) *MockStore_Ping_Call {
+func (_c *MockStore_SetTwoFactorSecret_Call) Run(run func(ctx context.Context, userID uuid.UUID, secret string)) *MockStore_SetTwoFactorSecret_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_Ping_Call) Return(_a0 error) *MockStore_Ping_Call {
+func (_c *MockStore_SetTwoFactorSecret_Call) Return(_a0 error) *MockStore_SetTwoFactorSecret_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStore_Ping_Call) RunAndReturn(run func(context.Context) error) *MockStore_Ping_Call {
+func (_c *MockStore_SetTwoFactorSecret_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockStore_SetTwoFactorSecret_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// RecordPromoCodeUsage provides a mock function with given fields: ctx, usage
-func (_m *MockStore) RecordPromoCodeUsage(ctx context.Context, usage *models.PromoCodeUsage) error {
-	ret := _m.Called(ctx, usage)
+// SoftDeleteRequest provides a mock function with given fields: ctx, requestID
+func (_m *MockStore) SoftDeleteRequest(ctx context.Context, requestID uuid.UUID) error {
+	ret := _m.Called(ctx, requestID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RecordPromoCodeUsage")
+		panic("no return value specified for SoftDeleteRequest")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.PromoCodeUsage) error); ok {
-		r0 = rf(ctx, usage)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, requestID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -2379,140 +4070,163 @@ func (_m *MockStore) RecordPromoCodeUsage(ctx context.Context, usage *models.Pro
 	return r0
 }
 
-// MockStore_RecordPromoCodeUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordPromoCodeUsage'
-type MockStore_RecordPromoCodeUsage_Call struct {
+// MockStore_SoftDeleteRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SoftDeleteRequest'
+type MockStore_SoftDeleteRequest_Call struct {
 	*mock.Call
 }
 
-// RecordPromoCodeUsage is a helper method to define mock.On call
+// SoftDeleteRequest is a helper method to define mock.On call
 //   - ctx context.Context
-//   - usage *models.PromoCodeUsage
-func (_e *MockStore_Expecter) RecordPromoCodeUsage(ctx interface{}, usage interface{}) *MockStore_RecordPromoCodeUsage_Call {
-	return &MockStore_RecordPromoCodeUsage_Call{Call: _e.mock.On("RecordPromoCodeUsage", ctx, usage)}
+//   - requestID uuid.UUID
+func (_e *MockStore_Expecter) SoftDeleteRequest(ctx interface{}, requestID interface{}) *MockStore_SoftDeleteRequest_Call {
+	return &MockStore_SoftDeleteRequest_Call{Call: _e.mock.On("SoftDeleteRequest", ctx, requestID)}
 }
 
-func (_c *MockStore_RecordPromoCodeUsage_Call) Run(run func(ctx context.Context, usage *models.PromoCodeUsage)) *MockStore_RecordPromoCodeUsage_Call {
+func (_c *MockStore_SoftDeleteRequest_Call) Run(run func(ctx context.Context, requestID uuid.UUID)) *MockStore_SoftDeleteRequest_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*models.PromoCodeUsage))
+		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *MockStore_RecordPromoCodeUsage_Call) Return(_a0 error) *MockStore_RecordPromoCodeUsage_Call {
+func (_c *MockStore_SoftDeleteRequest_Call) Return(_a0 error) *MockStore_SoftDeleteRequest_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStore_RecordPromoCodeUsage_Call) RunAndReturn(run func(context.Context, *models.PromoCodeUsage) error) *MockStore_RecordPromoCodeUsage_Call {
+func (_c *MockStore_SoftDeleteRequest_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockStore_SoftDeleteRequest_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// RevokeAllUserRefreshTokens provides a mock function with given fields: ctx, userID
-func (_m *MockStore) RevokeAllUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
-	ret := _m.Called(ctx, userID)
+// SumUserTokens provides a mock function with given fields: ctx, userID, since
+func (_m *MockStore) SumUserTokens(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	ret := _m.Called(ctx, userID, since)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RevokeAllUserRefreshTokens")
+		panic("no return value specified for SumUserTokens")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, userID)
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) (int, error)); ok {
+		return rf(ctx, userID, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) int); ok {
+		r0 = rf(ctx, userID, since)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r1 = rf(ctx, userID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// MockStore_RevokeAllUserRefreshTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeAllUserRefreshTokens'
-type MockStore_RevokeAllUserRefreshTokens_Call struct {
+// MockStore_SumUserTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SumUserTokens'
+type MockStore_SumUserTokens_Call struct {
 	*mock.Call
 }
 
-// RevokeAllUserRefreshTokens is a helper method to define mock.On call
+// SumUserTokens is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userID uuid.UUID
-func (_e *MockStore_Expecter) RevokeAllUserRefreshTokens(ctx interface{}, userID interface{}) *MockStore_RevokeAllUserRefreshTokens_Call {
-	return &MockStore_RevokeAllUserRefreshTokens_Call{Call: _e.mock.On("RevokeAllUserRefreshTokens", ctx, userID)}
+//   - since time.Time
+func (_e *MockStore_Expecter) SumUserTokens(ctx interface{}, userID interface{}, since interface{}) *MockStore_SumUserTokens_Call {
+	return &MockStore_SumUserTokens_Call{Call: _e.mock.On("SumUserTokens", ctx, userID, since)}
 }
 
-func (_c *MockStore_RevokeAllUserRefreshTokens_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockStore_RevokeAllUserRefreshTokens_Call {
+func (_c *MockStore_SumUserTokens_Call) Run(run func(ctx context.Context, userID uuid.UUID, since time.Time)) *MockStore_SumUserTokens_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(time.Time))
 	})
 	return _c
 }
 
-func (_c *MockStore_RevokeAllUserRefreshTokens_Call) Return(_a0 error) *MockStore_RevokeAllUserRefreshTokens_Call {
-	_c.Call.Return(_a0)
+func (_c *MockStore_SumUserTokens_Call) Return(_a0 int, _a1 error) *MockStore_SumUserTokens_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_RevokeAllUserRefreshTokens_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockStore_RevokeAllUserRefreshTokens_Call {
+func (_c *MockStore_SumUserTokens_Call) RunAndReturn(run func(context.Context, uuid.UUID, time.Time) (int, error)) *MockStore_SumUserTokens_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// RevokeRefreshToken provides a mock function with given fields: ctx, tokenHash
-func (_m *MockStore) RevokeRefreshToken(ctx context.Context, tokenHash string) error {
-	ret := _m.Called(ctx, tokenHash)
+// TransitionRequestStatus provides a mock function with given fields: ctx, requestID, from, to
+func (_m *MockStore) TransitionRequestStatus(ctx context.Context, requestID uuid.UUID, from string, to string) (bool, error) {
+	ret := _m.Called(ctx, requestID, from, to)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RevokeRefreshToken")
+		panic("no return value specified for TransitionRequestStatus")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, tokenHash)
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) (bool, error)); ok {
+		return rf(ctx, requestID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) bool); ok {
+		r0 = rf(ctx, requestID, from, to)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(bool)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, string) error); ok {
+		r1 = rf(ctx, requestID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// MockStore_RevokeRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeRefreshToken'
-type MockStore_RevokeRefreshToken_Call struct {
+// MockStore_TransitionRequestStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TransitionRequestStatus'
+type MockStore_TransitionRequestStatus_Call struct {
 	*mock.Call
 }
 
-// RevokeRefreshToken is a helper method to define mock.On call
+// TransitionRequestStatus is a helper method to define mock.On call
 //   - ctx context.Context
-//   - tokenHash string
-func (_e *MockStore_Expecter) RevokeRefreshToken(ctx interface{}, tokenHash interface{}) *MockStore_RevokeRefreshToken_Call {
-	return &MockStore_RevokeRefreshToken_Call{Call: _e.mock.On("RevokeRefreshToken", ctx, tokenHash)}
+//   - requestID uuid.UUID
+//   - from string
+//   - to string
+func (_e *MockStore_Expecter) TransitionRequestStatus(ctx interface{}, requestID interface{}, from interface{}, to interface{}) *MockStore_TransitionRequestStatus_Call {
+	return &MockStore_TransitionRequestStatus_Call{Call: _e.mock.On("TransitionRequestStatus", ctx, requestID, from, to)}
 }
 
-func (_c *MockStore_RevokeRefreshToken_Call) Run(run func(ctx context.Context, tokenHash string)) *MockStore_RevokeRefreshToken_Call {
+func (_c *MockStore_TransitionRequestStatus_Call) Run(run func(ctx context.Context, requestID uuid.UUID, from string, to string)) *MockStore_TransitionRequestStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_RevokeRefreshToken_Call) Return(_a0 error) *MockStore_RevokeRefreshToken_Call {
-	_c.Call.Return(_a0)
+func (_c *MockStore_TransitionRequestStatus_Call) Return(_a0 bool, _a1 error) *MockStore_TransitionRequestStatus_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_RevokeRefreshToken_Call) RunAndReturn(run func(context.Context, string) error) *MockStore_RevokeRefreshToken_Call {
+func (_c *MockStore_TransitionRequestStatus_Call) RunAndReturn(run func(context.Context, uuid.UUID, string, string) (bool, error)) *MockStore_TransitionRequestStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// RunTx provides a mock function with given fields: ctx, fn
-func (_m *MockStore) RunTx(ctx context.Context, fn func(pgx.Tx) error) error {
-	ret := _m.Called(ctx, fn)
+// UnassignRoleFromUser provides a mock function with given fields: ctx, userID, roleName
+func (_m *MockStore) UnassignRoleFromUser(ctx context.Context, userID uuid.UUID, roleName string) error {
+	ret := _m.Called(ctx, userID, roleName)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RunTx")
+		panic("no return value specified for UnassignRoleFromUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, func(pgx.Tx) error) error); ok {
-		r0 = rf(ctx, fn)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, userID, roleName)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -2520,46 +4234,47 @@ func (_m *MockStore) RunTx(ctx context.Context, fn func(pgx.Tx) error) error {
 	return r0
 }
 
-// MockStore_RunTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunTx'
-type MockStore_RunTx_Call struct {
+// MockStore_UnassignRoleFromUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnassignRoleFromUser'
+type MockStore_UnassignRoleFromUser_Call struct {
 	*mock.Call
 }
 
-// RunTx is a helper method to define mock.On call
+// UnassignRoleFromUser is a helper method to define mock.On call
 //   - ctx context.Context
-//   - fn func(pgx.Tx) error
-func (_e *MockStore_Expecter) RunTx(ctx interface{}, fn interface{}) *MockStore_RunTx_Call {
-	return &MockStore_RunTx_Call{Call: _e.mock.On("RunTx", ctx, fn)}
+//   - userID uuid.UUID
+//   - roleName string
+func (_e *MockStore_Expecter) UnassignRoleFromUser(ctx interface{}, userID interface{}, roleName interface{}) *MockStore_UnassignRoleFromUser_Call {
+	return &MockStore_UnassignRoleFromUser_Call{Call: _e.mock.On("UnassignRoleFromUser", ctx, userID, roleName)}
 }
 
-func (_c *MockStore_RunTx_Call) Run(run func(ctx context.Context, fn func(pgx.Tx) error)) *MockStore_RunTx_Call {
+func (_c *MockStore_UnassignRoleFromUser_Call) Run(run func(ctx context.Context, userID uuid.UUID, roleName string)) *MockStore_UnassignRoleFromUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(func(pgx.Tx) error))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_RunTx_Call) Return(_a0 error) *MockStore_RunTx_Call {
+func (_c *MockStore_UnassignRoleFromUser_Call) Return(_a0 error) *MockStore_UnassignRoleFromUser_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStore_RunTx_Call) RunAndReturn(run func(context.Context, func(pgx.Tx) error) error) *MockStore_RunTx_Call {
+func (_c *MockStore_UnassignRoleFromUser_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockStore_UnassignRoleFromUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SaveCacheEntry provides a mock function with given fields: ctx, question, embedding, answer, sourceMeta
-func (_m *MockStore) SaveCacheEntry(ctx context.Context, question string, embedding []float64, answer string, sourceMeta string) error {
-	ret := _m.Called(ctx, question, embedding, answer, sourceMeta)
+// UpdateCallbackStatus provides a mock function with given fields: ctx, requestID, status, attempts, lastErr
+func (_m *MockStore) UpdateCallbackStatus(ctx context.Context, requestID uuid.UUID, status string, attempts int, lastErr string) error {
+	ret := _m.Called(ctx, requestID, status, attempts, lastErr)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveCacheEntry")
+		panic("no return value specified for UpdateCallbackStatus")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, []float64, string, string) error); ok {
-		r0 = rf(ctx, question, embedding, answer, sourceMeta)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, int, string) error); ok {
+		r0 = rf(ctx, requestID, status, attempts, lastErr)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -2567,34 +4282,34 @@ func (_m *MockStore) SaveCacheEntry(ctx context.Context, question string, embedd
 	return r0
 }
 
-// MockStore_SaveCacheEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveCacheEntry'
-type MockStore_SaveCacheEntry_Call struct {
+// MockStore_UpdateCallbackStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCallbackStatus'
+type MockStore_UpdateCallbackStatus_Call struct {
 	*mock.Call
 }
 
-// SaveCacheEntry is a helper method to define mock.On call
+// UpdateCallbackStatus is a helper method to define mock.On call
 //   - ctx context.Context
-//   - question string
-//   - embedding []float64
-//   - answer string
-//   - sourceMeta string
-func (_e *MockStore_Expecter) SaveCacheEntry(ctx interface{}, question interface{}, embedding interface{}, answer interface{}, sourceMeta interface{}) *MockStore_SaveCacheEntry_Call {
-	return &MockStore_SaveCacheEntry_Call{Call: _e.mock.On("SaveCacheEntry", ctx, question, embedding, answer, sourceMeta)}
+//   - requestID uuid.UUID
+//   - status string
+//   - attempts int
+//   - lastErr string
+func (_e *MockStore_Expecter) UpdateCallbackStatus(ctx interface{}, requestID interface{}, status interface{}, attempts interface{}, lastErr interface{}) *MockStore_UpdateCallbackStatus_Call {
+	return &MockStore_UpdateCallbackStatus_Call{Call: _e.mock.On("UpdateCallbackStatus", ctx, requestID, status, attempts, lastErr)}
 }
 
-func (_c *MockStore_SaveCacheEntry_Call) Run(run func(ctx context.Context, question string, embedding []float64, answer string, sourceMeta string)) *MockStore_SaveCacheEntry_Call {
+func (_c *MockStore_UpdateCallbackStatus_Call) Run(run func(ctx context.Context, requestID uuid.UUID, status string, attempts int, lastErr string)) *MockStore_UpdateCallbackStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].([]float64), args[3].(string), args[4].(string))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string), args[3].(int), args[4].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_SaveCacheEntry_Call) Return(_a0 error) *MockStore_SaveCacheEntry_Call {
+func (_c *MockStore_UpdateCallbackStatus_Call) Return(_a0 error) *MockStore_UpdateCallbackStatus_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStore_SaveCacheEntry_Call) RunAndReturn(run func(context.Context, string, []float64, string, string) error) *MockStore_SaveCacheEntry_Call {
+func (_c *MockStore_UpdateCallbackStatus_Call) RunAndReturn(run func(context.Context, uuid.UUID, string, int, string) error) *MockStore_UpdateCallbackStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -2694,6 +4409,53 @@ func (_c *MockStore_UpdateRequestStatus_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
+// UpdateUser provides a mock function with given fields: ctx, user
+func (_m *MockStore) UpdateUser(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_UpdateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUser'
+type MockStore_UpdateUser_Call struct {
+	*mock.Call
+}
+
+// UpdateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *models.User
+func (_e *MockStore_Expecter) UpdateUser(ctx interface{}, user interface{}) *MockStore_UpdateUser_Call {
+	return &MockStore_UpdateUser_Call{Call: _e.mock.On("UpdateUser", ctx, user)}
+}
+
+func (_c *MockStore_UpdateUser_Call) Run(run func(ctx context.Context, user *models.User)) *MockStore_UpdateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.User))
+	})
+	return _c
+}
+
+func (_c *MockStore_UpdateUser_Call) Return(_a0 error) *MockStore_UpdateUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_UpdateUser_Call) RunAndReturn(run func(context.Context, *models.User) error) *MockStore_UpdateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateUserPassword provides a mock function with given fields: ctx, userID, passwordHash
 func (_m *MockStore) UpdateUserPassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
 	ret := _m.Called(ctx, userID, passwordHash)
@@ -2790,6 +4552,53 @@ func (_c *MockStore_WithTx_Call) RunAndReturn(run func(pgx.Tx) repository.Store)
 	return _c
 }
 
+// WriteAudit provides a mock function with given fields: ctx, entry
+func (_m *MockStore) WriteAudit(ctx context.Context, entry *models.AuditEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WriteAudit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.AuditEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_WriteAudit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WriteAudit'
+type MockStore_WriteAudit_Call struct {
+	*mock.Call
+}
+
+// WriteAudit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entry *models.AuditEntry
+func (_e *MockStore_Expecter) WriteAudit(ctx interface{}, entry interface{}) *MockStore_WriteAudit_Call {
+	return &MockStore_WriteAudit_Call{Call: _e.mock.On("WriteAudit", ctx, entry)}
+}
+
+func (_c *MockStore_WriteAudit_Call) Run(run func(ctx context.Context, entry *models.AuditEntry)) *MockStore_WriteAudit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.AuditEntry))
+	})
+	return _c
+}
+
+func (_c *MockStore_WriteAudit_Call) Return(_a0 error) *MockStore_WriteAudit_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_WriteAudit_Call) RunAndReturn(run func(context.Context, *models.AuditEntry) error) *MockStore_WriteAudit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockStore creates a new instance of MockStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockStore(t interface {
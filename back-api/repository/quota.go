@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -46,3 +47,35 @@ func (r *Repository) GetFreeAnalysesUsed(ctx context.Context, userID uuid.UUID)
 	}
 	return count, nil
 }
+
+// SumUserTokens returns the total OpenAI tokens recorded on responses to the
+// user's requests, counting responses written at or after since. A retry or
+// re-analysis of an older request is charged to the period it ran in.
+func (r *Repository) SumUserTokens(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	var total int
+	err := r.querier.QueryRow(ctx, `
+		SELECT COALESCE(SUM(resp.tokens_used), 0)
+		FROM responses resp
+		JOIN requests req ON req.id = resp.request_id
+		WHERE req.user_id = $1 AND resp.created_at >= $2
+	`, userID, since).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("sum user tokens: %w", err)
+	}
+	return total, nil
+}
+
+// CountActiveRequests returns how many of the user's requests are pending,
+// queued or processing. Deleted requests are not counted.
+func (r *Repository) CountActiveRequests(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.querier.QueryRow(ctx, `
+		SELECT COUNT(*) FROM requests
+		WHERE user_id = $1 AND status IN ('pending', 'queued', 'processing')
+		  AND deleted_at IS NULL
+	`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count active requests: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestCountActiveRequests_SkipsDeleted(t *testing.T) {
+	userID := uuid.New()
+	q := stubQuerier{
+		queryRowFn: func(_ context.Context, sql string, args ...any) pgx.Row {
+			if !strings.Contains(sql, "deleted_at IS NULL") {
+				t.Errorf("deleted requests must not count as active: %s", sql)
+			}
+			if len(args) != 1 || args[0] != userID {
+				t.Errorf("unexpected args: %v", args)
+			}
+			return stubRow{scanFn: func(dest ...any) error {
+				*dest[0].(*int) = 2
+				return nil
+			}}
+		},
+	}
+
+	n, err := NewTxScoped(q).CountActiveRequests(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2, got %d", n)
+	}
+}
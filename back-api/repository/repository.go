@@ -18,27 +18,49 @@ type UserRepo interface {
 	GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error)
 	AssignRoleToUser(ctx context.Context, userID uuid.UUID, roleName string) error
 	UpdateUserPassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	UpdateUser(ctx context.Context, user *models.User) error
+	SetTwoFactorSecret(ctx context.Context, userID uuid.UUID, secret string) error
+	EnableTwoFactor(ctx context.Context, userID uuid.UUID) error
 }
 
 // RequestRepo provides request/file/response data access.
 type RequestRepo interface {
 	CreateRequest(ctx context.Context, req *models.Request) error
 	GetRequestByID(ctx context.Context, id uuid.UUID) (*models.Request, error)
-	GetRequestsByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]models.Request, error)
-	CountRequestsByUserID(ctx context.Context, userID uuid.UUID) (int, error)
+	GetRequestsByUserID(ctx context.Context, userID uuid.UUID, filter RequestFilter) ([]models.Request, error)
+	CountRequestsByUserID(ctx context.Context, userID uuid.UUID, filter RequestFilter) (int, error)
+	CountRequestsByStatus(ctx context.Context, userID uuid.UUID) (map[string]int, error)
 	GetRecentRequestsWithResponses(ctx context.Context, userID uuid.UUID, limit int) ([]models.Request, error)
+	GetChildRequests(ctx context.Context, parentID uuid.UUID) ([]models.Request, error)
 	UpdateRequestStatus(ctx context.Context, requestID uuid.UUID, status string) error
+	TransitionRequestStatus(ctx context.Context, requestID uuid.UUID, from, to string) (bool, error)
+	MarkRequestQueued(ctx context.Context, requestID uuid.UUID) error
+	FailRequest(ctx context.Context, requestID uuid.UUID, code models.ErrorCode) error
+	UpdateCallbackStatus(ctx context.Context, requestID uuid.UUID, status string, attempts int, lastErr string) error
+	SoftDeleteRequest(ctx context.Context, requestID uuid.UUID) error
 	CreateFile(ctx context.Context, file *models.File) error
+	CreateFiles(ctx context.Context, files []*models.File) error
 	GetFilesByRequestID(ctx context.Context, requestID uuid.UUID) ([]models.File, error)
+	GetFileKeysByRequestID(ctx context.Context, requestID uuid.UUID) ([]string, error)
+	GetFileByID(ctx context.Context, id uuid.UUID) (*models.File, uuid.UUID, error)
+	ExistingFileKeys(ctx context.Context, keys []string) (map[string]struct{}, error)
 	CreateResponse(ctx context.Context, resp *models.Response) error
 	GetResponseByRequestID(ctx context.Context, requestID uuid.UUID) (*models.Response, error)
 }
 
+// BatchRepo provides EKG batch data access.
+type BatchRepo interface {
+	CreateECGBatch(ctx context.Context, batch *models.ECGBatch) error
+	GetECGBatch(ctx context.Context, id uuid.UUID) (*models.ECGBatch, error)
+}
+
 // QuotaRepo provides lifetime free analyses quota data access.
 type QuotaRepo interface {
 	IncrementFreeAnalysesUsed(ctx context.Context, userID uuid.UUID) (int, error)
 	DecrementFreeAnalysesUsed(ctx context.Context, userID uuid.UUID) error
 	GetFreeAnalysesUsed(ctx context.Context, userID uuid.UUID) (int, error)
+	SumUserTokens(ctx context.Context, userID uuid.UUID, since time.Time) (int, error)
+	CountActiveRequests(ctx context.Context, userID uuid.UUID) (int, error)
 }
 
 // TokenRepo provides refresh-token data access.
@@ -48,11 +70,19 @@ type TokenRepo interface {
 	RevokeRefreshToken(ctx context.Context, tokenHash string) error
 	GetRevokedRefreshTokenOwner(ctx context.Context, tokenHash string) (uuid.UUID, error)
 	RevokeAllUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+	DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) (int64, error)
 }
 
 // RoleRepo provides role/permission data access.
 type RoleRepo interface {
 	LoadRolePermissions(ctx context.Context) (map[string][]string, error)
+	ListRoles(ctx context.Context) ([]models.Role, error)
+	CreateRole(ctx context.Context, role *models.Role) error
+	ListPermissions(ctx context.Context) ([]models.Permission, error)
+	CreatePermission(ctx context.Context, perm *models.Permission) error
+	AttachPermission(ctx context.Context, roleName, permName string) error
+	DetachPermission(ctx context.Context, roleName, permName string) error
+	UnassignRoleFromUser(ctx context.Context, userID uuid.UUID, roleName string) error
 }
 
 // RAGFeedbackRepo provides RAG feedback data access.
@@ -80,6 +110,15 @@ type PasswordResetRepo interface {
 	InvalidateUserPasswordResetTokens(ctx context.Context, userID uuid.UUID) error
 }
 
+// EmailVerificationRepo provides email verification token data access.
+type EmailVerificationRepo interface {
+	CreateEmailVerificationToken(ctx context.Context, token *models.EmailVerificationToken) error
+	GetValidEmailVerificationToken(ctx context.Context, tokenHash string) (*models.EmailVerificationToken, error)
+	MarkEmailVerificationTokenUsed(ctx context.Context, tokenID uuid.UUID) error
+	InvalidateUserEmailVerificationTokens(ctx context.Context, userID uuid.UUID) error
+	MarkEmailVerified(ctx context.Context, userID uuid.UUID) error
+}
+
 // PaymentRepo provides payment data access.
 type PaymentRepo interface {
 	CreatePayment(ctx context.Context, p *models.Payment) error
@@ -98,6 +137,13 @@ type AdminRepo interface {
 	ListUsers(ctx context.Context, limit, offset int, search string) ([]AdminUserRow, int, error)
 	ListPayments(ctx context.Context, limit, offset int) ([]AdminPaymentRow, int, error)
 	ListRAGFeedback(ctx context.Context, limit, offset int) ([]AdminFeedbackRow, int, error)
+	ListRequests(ctx context.Context, filter RequestFilter) ([]models.Request, int, error)
+}
+
+// AuditRepo provides audit log data access.
+type AuditRepo interface {
+	WriteAudit(ctx context.Context, entry *models.AuditEntry) error
+	ListAudit(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, int, error)
 }
 
 // PromoCodeRepo provides promo code data access.
@@ -112,6 +158,7 @@ type PromoCodeRepo interface {
 type Store interface {
 	UserRepo
 	RequestRepo
+	BatchRepo
 	TokenRepo
 	RoleRepo
 	QuotaRepo
@@ -120,7 +167,9 @@ type Store interface {
 	ECGChatRepo
 	PaymentRepo
 	PasswordResetRepo
+	EmailVerificationRepo
 	AdminRepo
+	AuditRepo
 	PromoCodeRepo
 
 	// Transaction support
@@ -135,6 +184,7 @@ type Store interface {
 type Repository struct {
 	db      *database.DB
 	querier database.Querier // can be pool or transaction
+	cipher  *FieldCipher     // nil stores sensitive columns as plaintext
 }
 
 // New creates a new Repository.
@@ -170,9 +220,14 @@ func NewWithQuerier(db *database.DB, q database.Querier) *Repository {
 // NewTxScoped creates a transaction-scoped Repository.
 // Unlike NewWithQuerier, it does not require a *DB reference, making it
 // suitable for use with the TxBeginner interface where *DB is not available.
-// The returned repo must not call DB() or WithTx().
-func NewTxScoped(q database.Querier) *Repository {
-	return &Repository{querier: q}
+// The returned repo must not call DB() or WithTx(). Options such as
+// WithFieldCipher apply as they do for New.
+func NewTxScoped(q database.Querier, opts ...func(*Repository)) *Repository {
+	r := &Repository{querier: q}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
 }
 
 // WithTx creates a new Repository that uses the given transaction.
@@ -180,6 +235,7 @@ func (r *Repository) WithTx(tx pgx.Tx) Store {
 	return &Repository{
 		db:      r.db,
 		querier: tx,
+		cipher:  r.cipher,
 	}
 }
 
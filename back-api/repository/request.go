@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,14 +25,23 @@ func (r *Repository) CreateRequest(ctx context.Context, req *models.Request) err
 	if err != nil {
 		return fmt.Errorf("failed to marshal client meta: %w", err)
 	}
+	tags, err := marshalTags(req.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	textQuery, keyVersion, err := r.cipher.encryptOptional(req.TextQuery)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt text query: %w", err)
+	}
 
 	query := `
-		INSERT INTO requests (id, user_id, text_query, status, client_meta, ecg_age, ecg_sex, ecg_paper_speed_mms, ecg_mm_per_mv_limb, ecg_mm_per_mv_chest, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+		INSERT INTO requests (id, user_id, text_query, text_query_key_version, status, client_meta, ecg_age, ecg_sex, ecg_paper_speed_mms, ecg_mm_per_mv_limb, ecg_mm_per_mv_chest, callback_url, correlation_id, batch_id, parent_request_id, tags, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW(), NOW())
 	`
 
-	_, err = r.querier.Exec(ctx, query, req.ID, req.UserID, req.TextQuery, req.Status, clientMeta,
-		req.ECGAge, req.ECGSex, req.ECGPaperSpeedMMS, req.ECGMmPerMvLimb, req.ECGMmPerMvChest)
+	_, err = r.querier.Exec(ctx, query, req.ID, req.UserID, textQuery, keyVersion, req.Status, clientMeta,
+		req.ECGAge, req.ECGSex, req.ECGPaperSpeedMMS, req.ECGMmPerMvLimb, req.ECGMmPerMvChest, req.CallbackURL, req.CorrelationID, req.BatchID,
+		req.ParentRequestID, tags)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -41,15 +52,16 @@ func (r *Repository) CreateRequest(ctx context.Context, req *models.Request) err
 // for the request + response, and a separate query for files.
 func (r *Repository) GetRequestByID(ctx context.Context, id uuid.UUID) (*models.Request, error) {
 	query := `
-		SELECT r.id, r.user_id, r.text_query, r.status, r.created_at, r.updated_at, r.client_meta,
+		SELECT r.id, r.user_id, r.text_query, r.text_query_key_version, r.status, r.created_at, r.updated_at, r.client_meta,
 		       r.ecg_age, r.ecg_sex, r.ecg_paper_speed_mms, r.ecg_mm_per_mv_limb, r.ecg_mm_per_mv_chest,
-		       resp.id, resp.request_id, resp.content, resp.model,
-		       resp.tokens_used, resp.processing_time_ms, resp.created_at
+		       r.callback_url, r.callback_status, r.correlation_id, r.batch_id, r.parent_request_id, r.error_code, r.tags,
+		       resp.id, resp.request_id, resp.content, resp.content_key_version, resp.model,
+		       resp.tokens_used, resp.processing_time_ms, resp.finish_reason, resp.is_refusal, resp.created_at
 		FROM requests r
 		LEFT JOIN LATERAL (
 			SELECT * FROM responses WHERE request_id = r.id ORDER BY created_at DESC LIMIT 1
 		) resp ON true
-		WHERE r.id = $1
+		WHERE r.id = $1 AND r.deleted_at IS NULL
 	`
 
 	var req models.Request
@@ -58,14 +70,18 @@ func (r *Repository) GetRequestByID(ctx context.Context, id uuid.UUID) (*models.
 	var respID, respReqID *uuid.UUID
 	var respContent, respModel *string
 	var respTokens, respTimeMs *int
+	var respFinishReason *string
+	var respRefusal *bool
 	var respCreatedAt *time.Time
-	var clientMetaBytes []byte
+	var clientMetaBytes, tagsBytes []byte
+	var textQueryKeyVersion, respContentKeyVersion *int16
 
 	err := r.querier.QueryRow(ctx, query, id).Scan(
-		&req.ID, &req.UserID, &req.TextQuery, &req.Status, &req.CreatedAt, &req.UpdatedAt, &clientMetaBytes,
+		&req.ID, &req.UserID, &req.TextQuery, &textQueryKeyVersion, &req.Status, &req.CreatedAt, &req.UpdatedAt, &clientMetaBytes,
 		&req.ECGAge, &req.ECGSex, &req.ECGPaperSpeedMMS, &req.ECGMmPerMvLimb, &req.ECGMmPerMvChest,
-		&respID, &respReqID, &respContent, &respModel,
-		&respTokens, &respTimeMs, &respCreatedAt,
+		&req.CallbackURL, &req.CallbackStatus, &req.CorrelationID, &req.BatchID, &req.ParentRequestID, &req.ErrorCode, &tagsBytes,
+		&respID, &respReqID, &respContent, &respContentKeyVersion, &respModel,
+		&respTokens, &respTimeMs, &respFinishReason, &respRefusal, &respCreatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -76,17 +92,37 @@ func (r *Repository) GetRequestByID(ctx context.Context, id uuid.UUID) (*models.
 	if req.ClientMeta, err = unmarshalClientMeta(clientMetaBytes); err != nil {
 		return nil, fmt.Errorf("failed to decode client meta: %w", err)
 	}
+	if req.Tags, err = unmarshalTags(tagsBytes); err != nil {
+		return nil, fmt.Errorf("failed to decode tags: %w", err)
+	}
+	if req.TextQuery, err = r.cipher.decryptOptional(req.TextQuery, textQueryKeyVersion); err != nil {
+		return nil, fmt.Errorf("failed to decrypt text query: %w", err)
+	}
+	if req.ErrorCode != nil {
+		msg := models.ErrorMessage(*req.ErrorCode)
+		req.Error = &msg
+	}
 
 	// Assemble response if the JOIN returned data
 	if respID != nil {
+		content, err := r.cipher.decrypt(*respContent, respContentKeyVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt response content: %w", err)
+		}
 		resp := &models.Response{
 			ID:               *respID,
 			RequestID:        *respReqID,
-			Content:          *respContent,
+			Content:          content,
 			Model:            *respModel,
 			TokensUsed:       *respTokens,
 			ProcessingTimeMs: *respTimeMs,
 		}
+		if respFinishReason != nil {
+			resp.FinishReason = *respFinishReason
+		}
+		if respRefusal != nil {
+			resp.IsRefusal = *respRefusal
+		}
 		if respCreatedAt != nil {
 			resp.CreatedAt = *respCreatedAt
 		}
@@ -103,18 +139,23 @@ func (r *Repository) GetRequestByID(ctx context.Context, id uuid.UUID) (*models.
 	return &req, nil
 }
 
-// GetRequestsByUserID retrieves requests for a user with pagination.
-func (r *Repository) GetRequestsByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]models.Request, error) {
-	query := `
-		SELECT id, user_id, text_query, status, created_at, updated_at, client_meta,
+// GetRequestsByUserID retrieves a user's EKG requests matching filter, newest
+// first, paginated by filter.Limit and filter.Offset. filter.UserID is
+// ignored.
+func (r *Repository) GetRequestsByUserID(ctx context.Context, userID uuid.UUID, filter RequestFilter) ([]models.Request, error) {
+	filter.UserID = &userID
+	where, args := filter.whereClause(userRequestConds...)
+	n := len(args)
+	query := fmt.Sprintf(`
+		SELECT id, user_id, text_query, text_query_key_version, status, created_at, updated_at, client_meta, tags,
 		       ecg_age, ecg_sex, ecg_paper_speed_mms, ecg_mm_per_mv_limb, ecg_mm_per_mv_chest
 		FROM requests
-		WHERE user_id = $1 AND ecg_paper_speed_mms IS NOT NULL
+		%s
 		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
-	`
+		LIMIT $%d OFFSET $%d
+	`, where, n+1, n+2)
 
-	rows, err := r.querier.Query(ctx, query, userID, limit, offset)
+	rows, err := r.querier.Query(ctx, query, append(args, filter.Limit, filter.Offset)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query requests: %w", err)
 	}
@@ -123,15 +164,18 @@ func (r *Repository) GetRequestsByUserID(ctx context.Context, userID uuid.UUID,
 	var requests []models.Request
 	for rows.Next() {
 		var req models.Request
-		var clientMetaBytes []byte
+		var clientMetaBytes, tagsBytes []byte
+		var textQueryKeyVersion *int16
 		err := rows.Scan(
 			&req.ID,
 			&req.UserID,
 			&req.TextQuery,
+			&textQueryKeyVersion,
 			&req.Status,
 			&req.CreatedAt,
 			&req.UpdatedAt,
 			&clientMetaBytes,
+			&tagsBytes,
 			&req.ECGAge,
 			&req.ECGSex,
 			&req.ECGPaperSpeedMMS,
@@ -145,6 +189,14 @@ func (r *Repository) GetRequestsByUserID(ctx context.Context, userID uuid.UUID,
 		if err != nil {
 			return nil, fmt.Errorf("failed to decode request client meta: %w", err)
 		}
+		req.Tags, err = unmarshalTags(tagsBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode request tags: %w", err)
+		}
+		req.TextQuery, err = r.cipher.decryptOptional(req.TextQuery, textQueryKeyVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt request text query: %w", err)
+		}
 
 		requests = append(requests, req)
 	}
@@ -159,15 +211,15 @@ func (r *Repository) GetRequestsByUserID(ctx context.Context, userID uuid.UUID,
 // latest response eagerly loaded, avoiding N+1 queries in fallback logic.
 func (r *Repository) GetRecentRequestsWithResponses(ctx context.Context, userID uuid.UUID, limit int) ([]models.Request, error) {
 	query := `
-		SELECT r.id, r.user_id, r.text_query, r.status, r.created_at, r.updated_at, r.client_meta,
+		SELECT r.id, r.user_id, r.text_query, r.text_query_key_version, r.status, r.created_at, r.updated_at, r.client_meta,
 		       r.ecg_age, r.ecg_sex, r.ecg_paper_speed_mms, r.ecg_mm_per_mv_limb, r.ecg_mm_per_mv_chest,
-		       resp.id, resp.request_id, resp.content, resp.model,
-		       resp.tokens_used, resp.processing_time_ms, resp.created_at
+		       resp.id, resp.request_id, resp.content, resp.content_key_version, resp.model,
+		       resp.tokens_used, resp.processing_time_ms, resp.finish_reason, resp.is_refusal, resp.created_at
 		FROM requests r
 		LEFT JOIN LATERAL (
 			SELECT * FROM responses WHERE request_id = r.id ORDER BY created_at DESC LIMIT 1
 		) resp ON true
-		WHERE r.user_id = $1
+		WHERE r.user_id = $1 AND r.deleted_at IS NULL
 		ORDER BY r.created_at DESC
 		LIMIT $2
 	`
@@ -176,6 +228,36 @@ func (r *Repository) GetRecentRequestsWithResponses(ctx context.Context, userID
 	if err != nil {
 		return nil, fmt.Errorf("failed to query requests with responses: %w", err)
 	}
+	return scanRequestsWithResponses(rows, r.cipher)
+}
+
+// GetChildRequests returns the requests created for parentID, such as the
+// GPT interpretation of an EKG, newest first with their latest response.
+func (r *Repository) GetChildRequests(ctx context.Context, parentID uuid.UUID) ([]models.Request, error) {
+	query := `
+		SELECT r.id, r.user_id, r.text_query, r.text_query_key_version, r.status, r.created_at, r.updated_at, r.client_meta,
+		       r.ecg_age, r.ecg_sex, r.ecg_paper_speed_mms, r.ecg_mm_per_mv_limb, r.ecg_mm_per_mv_chest,
+		       resp.id, resp.request_id, resp.content, resp.content_key_version, resp.model,
+		       resp.tokens_used, resp.processing_time_ms, resp.finish_reason, resp.is_refusal, resp.created_at
+		FROM requests r
+		LEFT JOIN LATERAL (
+			SELECT * FROM responses WHERE request_id = r.id ORDER BY created_at DESC LIMIT 1
+		) resp ON true
+		WHERE r.parent_request_id = $1 AND r.deleted_at IS NULL
+		ORDER BY r.created_at DESC
+	`
+
+	rows, err := r.querier.Query(ctx, query, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query child requests: %w", err)
+	}
+	return scanRequestsWithResponses(rows, r.cipher)
+}
+
+// scanRequestsWithResponses reads rows of request columns followed by the
+// latest response columns, as selected by GetRecentRequestsWithResponses,
+// decrypting sensitive columns with c.
+func scanRequestsWithResponses(rows pgx.Rows, c *FieldCipher) ([]models.Request, error) {
 	defer rows.Close()
 
 	var requests []models.Request
@@ -184,14 +266,17 @@ func (r *Repository) GetRecentRequestsWithResponses(ctx context.Context, userID
 		var respID, respReqID *uuid.UUID
 		var respContent, respModel *string
 		var respTokens, respTimeMs *int
+		var respFinishReason *string
+		var respRefusal *bool
 		var respCreatedAt *time.Time
 		var clientMetaBytes []byte
+		var textQueryKeyVersion, respContentKeyVersion *int16
 
 		err := rows.Scan(
-			&req.ID, &req.UserID, &req.TextQuery, &req.Status, &req.CreatedAt, &req.UpdatedAt, &clientMetaBytes,
+			&req.ID, &req.UserID, &req.TextQuery, &textQueryKeyVersion, &req.Status, &req.CreatedAt, &req.UpdatedAt, &clientMetaBytes,
 			&req.ECGAge, &req.ECGSex, &req.ECGPaperSpeedMMS, &req.ECGMmPerMvLimb, &req.ECGMmPerMvChest,
-			&respID, &respReqID, &respContent, &respModel,
-			&respTokens, &respTimeMs, &respCreatedAt,
+			&respID, &respReqID, &respContent, &respContentKeyVersion, &respModel,
+			&respTokens, &respTimeMs, &respFinishReason, &respRefusal, &respCreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan request with response: %w", err)
@@ -200,16 +285,30 @@ func (r *Repository) GetRecentRequestsWithResponses(ctx context.Context, userID
 		if err != nil {
 			return nil, fmt.Errorf("failed to decode request client meta: %w", err)
 		}
+		req.TextQuery, err = c.decryptOptional(req.TextQuery, textQueryKeyVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt request text query: %w", err)
+		}
 
 		if respID != nil {
+			content, err := c.decrypt(*respContent, respContentKeyVersion)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt response content: %w", err)
+			}
 			resp := &models.Response{
 				ID:               *respID,
 				RequestID:        *respReqID,
-				Content:          *respContent,
+				Content:          content,
 				Model:            *respModel,
 				TokensUsed:       *respTokens,
 				ProcessingTimeMs: *respTimeMs,
 			}
+			if respFinishReason != nil {
+				resp.FinishReason = *respFinishReason
+			}
+			if respRefusal != nil {
+				resp.IsRefusal = *respRefusal
+			}
 			if respCreatedAt != nil {
 				resp.CreatedAt = *respCreatedAt
 			}
@@ -225,17 +324,56 @@ func (r *Repository) GetRecentRequestsWithResponses(ctx context.Context, userID
 	return requests, nil
 }
 
-// CountRequestsByUserID returns the total number of requests for a user.
-func (r *Repository) CountRequestsByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+// CountRequestsByUserID returns the number of requests GetRequestsByUserID
+// matches, ignoring pagination.
+func (r *Repository) CountRequestsByUserID(ctx context.Context, userID uuid.UUID, filter RequestFilter) (int, error) {
+	filter.UserID = &userID
+	where, args := filter.whereClause(userRequestConds...)
 	var count int
-	err := r.querier.QueryRow(ctx, `SELECT COUNT(*) FROM requests WHERE user_id = $1 AND ecg_paper_speed_mms IS NOT NULL`, userID).Scan(&count)
+	err := r.querier.QueryRow(ctx, `SELECT COUNT(*) FROM requests `+where, args...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count requests: %w", err)
 	}
 	return count, nil
 }
 
-// UpdateRequestStatus updates the status of a request.
+// CountRequestsByStatus returns a user's request counts grouped by status,
+// counting the same requests as CountRequestsByUserID. Every status in
+// models.RequestStatuses is present, with 0 if the user has none.
+func (r *Repository) CountRequestsByStatus(ctx context.Context, userID uuid.UUID) (map[string]int, error) {
+	rows, err := r.querier.Query(ctx, `
+		SELECT status, COUNT(*)
+		FROM requests
+		WHERE user_id = $1 AND ecg_paper_speed_mms IS NOT NULL AND deleted_at IS NULL
+		GROUP BY status
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count requests by status: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int, len(models.RequestStatuses))
+	for _, status := range models.RequestStatuses {
+		counts[status] = 0
+	}
+	for rows.Next() {
+		var (
+			status string
+			count  int
+		)
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("scan request status count: %w", err)
+		}
+		counts[status] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate request status counts: %w", err)
+	}
+	return counts, nil
+}
+
+// UpdateRequestStatus updates the status of a request. Moving a request out
+// of failed clears its error code.
 // Returns an error if status is not a known RequestStatus value.
 func (r *Repository) UpdateRequestStatus(ctx context.Context, requestID uuid.UUID, status string) error {
 	if !models.ValidRequestStatus(status) {
@@ -244,7 +382,9 @@ func (r *Repository) UpdateRequestStatus(ctx context.Context, requestID uuid.UUI
 
 	query := `
 		UPDATE requests
-		SET status = $1, updated_at = NOW()
+		SET status = $1,
+		    error_code = CASE WHEN $1 = 'failed' THEN error_code END,
+		    updated_at = NOW()
 		WHERE id = $2
 	`
 
@@ -258,6 +398,97 @@ func (r *Repository) UpdateRequestStatus(ctx context.Context, requestID uuid.UUI
 	return nil
 }
 
+// TransitionRequestStatus moves a request from status from to status to and
+// reports whether it did. It returns false when the request is no longer in
+// from, so two callers racing on the same transition cannot both win. Like
+// UpdateRequestStatus it clears the error code unless to is failed.
+func (r *Repository) TransitionRequestStatus(ctx context.Context, requestID uuid.UUID, from, to string) (bool, error) {
+	if !models.ValidRequestStatus(to) {
+		return false, fmt.Errorf("invalid request status: %q", to)
+	}
+
+	query := `
+		UPDATE requests
+		SET status = $1,
+		    error_code = CASE WHEN $1 = 'failed' THEN error_code END,
+		    updated_at = NOW()
+		WHERE id = $2 AND status = $3
+	`
+
+	tag, err := r.querier.Exec(ctx, query, to, requestID, from)
+	if err != nil {
+		return false, fmt.Errorf("failed to transition request status: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// MarkRequestQueued moves a pending request to queued once its job is in
+// the queue. A request a worker already picked up is left alone, so a slow
+// caller never moves it back from processing.
+func (r *Repository) MarkRequestQueued(ctx context.Context, requestID uuid.UUID) error {
+	query := `
+		UPDATE requests
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND status = $3
+	`
+
+	if _, err := r.querier.Exec(ctx, query, models.StatusQueued, requestID, models.StatusPending); err != nil {
+		return fmt.Errorf("failed to mark request queued: %w", err)
+	}
+	return nil
+}
+
+// FailRequest marks a request as failed with the given error code.
+func (r *Repository) FailRequest(ctx context.Context, requestID uuid.UUID, code models.ErrorCode) error {
+	query := `
+		UPDATE requests
+		SET status = $1, error_code = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+
+	tag, err := r.querier.Exec(ctx, query, models.StatusFailed, code, requestID)
+	if err != nil {
+		return fmt.Errorf("failed to mark request failed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrRequestNotFound
+	}
+	return nil
+}
+
+// SoftDeleteRequest marks a request as deleted. Deleted requests are no longer
+// returned by GetRequestByID or the per-user listings.
+func (r *Repository) SoftDeleteRequest(ctx context.Context, requestID uuid.UUID) error {
+	query := `
+		UPDATE requests
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	tag, err := r.querier.Exec(ctx, query, requestID)
+	if err != nil {
+		return fmt.Errorf("failed to delete request: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrRequestNotFound
+	}
+	return nil
+}
+
+// UpdateCallbackStatus records the outcome of a completion webhook delivery.
+func (r *Repository) UpdateCallbackStatus(ctx context.Context, requestID uuid.UUID, status string, attempts int, lastErr string) error {
+	query := `
+		UPDATE requests
+		SET callback_status = $1, callback_attempts = $2, callback_error = NULLIF($3, '')
+		WHERE id = $4
+	`
+
+	if _, err := r.querier.Exec(ctx, query, status, attempts, lastErr, requestID); err != nil {
+		return fmt.Errorf("failed to update callback status: %w", err)
+	}
+	return nil
+}
+
 func marshalClientMeta(meta *models.RequestClientMeta) ([]byte, error) {
 	if meta == nil {
 		return nil, nil
@@ -275,3 +506,132 @@ func unmarshalClientMeta(raw []byte) (*models.RequestClientMeta, error) {
 	}
 	return &meta, nil
 }
+
+// marshalTags encodes tags for the tags column; nil becomes an empty object.
+func marshalTags(tags map[string]string) ([]byte, error) {
+	if tags == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(tags)
+}
+
+// unmarshalTags decodes the tags column; an empty object decodes to nil.
+func unmarshalTags(raw []byte) (map[string]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var tags map[string]string
+	if err := json.Unmarshal(raw, &tags); err != nil {
+		return nil, err
+	}
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	return tags, nil
+}
+
+// RequestFilter narrows ListRequests and GetRequestsByUserID. Zero-valued
+// fields are not applied.
+type RequestFilter struct {
+	UserID *uuid.UUID
+	Status string
+	From   *time.Time // inclusive lower bound on created_at
+	To     *time.Time // exclusive upper bound on created_at
+	// Tags matches requests carrying every one of these tags.
+	Tags   map[string]string
+	Limit  int
+	Offset int
+}
+
+// userRequestConds select the requests listed to their owner: EKG requests
+// that were not deleted.
+var userRequestConds = []string{"ecg_paper_speed_mms IS NOT NULL", "deleted_at IS NULL"}
+
+// whereClause renders the filter, after the constant conditions base, as a
+// parameterized WHERE clause. Values are always passed as arguments, never
+// interpolated into the SQL text.
+func (f RequestFilter) whereClause(base ...string) (string, []any) {
+	var (
+		conds = slices.Clone(base)
+		args  []any
+	)
+	add := func(cond string, v any) {
+		args = append(args, v)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.UserID != nil {
+		add("user_id = $%d", *f.UserID)
+	}
+	if f.Status != "" {
+		add("status = $%d", f.Status)
+	}
+	if f.From != nil {
+		add("created_at >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add("created_at < $%d", *f.To)
+	}
+	if len(f.Tags) > 0 {
+		tags, _ := json.Marshal(f.Tags) // a map[string]string always marshals
+		add("tags @> $%d::jsonb", string(tags))
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+// ListRequests returns requests across all users matching filter, newest
+// first, together with the total number of matches. Deleted requests are
+// left out.
+func (r *Repository) ListRequests(ctx context.Context, filter RequestFilter) ([]models.Request, int, error) {
+	where, args := filter.whereClause("deleted_at IS NULL")
+
+	var total int
+	if err := r.querier.QueryRow(ctx, `SELECT COUNT(*) FROM requests `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count requests: %w", err)
+	}
+
+	n := len(args)
+	query := fmt.Sprintf(`
+		SELECT id, user_id, text_query, text_query_key_version, status, created_at, updated_at, client_meta, tags,
+		       ecg_age, ecg_sex, ecg_paper_speed_mms, ecg_mm_per_mv_limb, ecg_mm_per_mv_chest
+		FROM requests
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, n+1, n+2)
+
+	rows, err := r.querier.Query(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []models.Request{}
+	for rows.Next() {
+		var req models.Request
+		var clientMetaBytes, tagsBytes []byte
+		var textQueryKeyVersion *int16
+		if err := rows.Scan(
+			&req.ID, &req.UserID, &req.TextQuery, &textQueryKeyVersion, &req.Status, &req.CreatedAt, &req.UpdatedAt, &clientMetaBytes, &tagsBytes,
+			&req.ECGAge, &req.ECGSex, &req.ECGPaperSpeedMMS, &req.ECGMmPerMvLimb, &req.ECGMmPerMvChest,
+		); err != nil {
+			return nil, 0, fmt.Errorf("scan request: %w", err)
+		}
+		if req.ClientMeta, err = unmarshalClientMeta(clientMetaBytes); err != nil {
+			return nil, 0, fmt.Errorf("decode request client meta: %w", err)
+		}
+		if req.Tags, err = unmarshalTags(tagsBytes); err != nil {
+			return nil, 0, fmt.Errorf("decode request tags: %w", err)
+		}
+		if req.TextQuery, err = r.cipher.decryptOptional(req.TextQuery, textQueryKeyVersion); err != nil {
+			return nil, 0, fmt.Errorf("decrypt request text query: %w", err)
+		}
+		requests = append(requests, req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate request rows: %w", err)
+	}
+	return requests, total, nil
+}
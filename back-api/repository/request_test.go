@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+var errStubQuery = errors.New("stub query error")
+
+func TestRequestFilter_WhereClause(t *testing.T) {
+	userID := uuid.New()
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	where, args := RequestFilter{UserID: &userID, Status: "completed", From: &from, To: &to}.whereClause()
+
+	want := "WHERE user_id = $1 AND status = $2 AND created_at >= $3 AND created_at < $4"
+	if where != want {
+		t.Fatalf("unexpected where clause:\n got: %s\nwant: %s", where, want)
+	}
+	if len(args) != 4 || args[0] != userID || args[1] != "completed" {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
+
+func TestRequestFilter_EmptyWhereClause(t *testing.T) {
+	where, args := RequestFilter{Limit: 10}.whereClause()
+	if where != "" || args != nil {
+		t.Fatalf("expected no filtering, got %q %v", where, args)
+	}
+}
+
+func TestRequestFilter_TagsAfterBaseConditions(t *testing.T) {
+	where, args := RequestFilter{Tags: map[string]string{"study": "af'--"}}.whereClause(userRequestConds...)
+
+	want := "WHERE ecg_paper_speed_mms IS NOT NULL AND deleted_at IS NULL AND tags @> $1::jsonb"
+	if where != want {
+		t.Fatalf("unexpected where clause:\n got: %s\nwant: %s", where, want)
+	}
+	if len(args) != 1 || args[0] != `{"study":"af'--"}` {
+		t.Fatalf("expected tags passed as one JSON argument, got %v", args)
+	}
+}
+
+func TestGetRequestsByUserID_Filters(t *testing.T) {
+	userID, otherID := uuid.New(), uuid.New()
+	from := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	const base = "WHERE ecg_paper_speed_mms IS NOT NULL AND deleted_at IS NULL AND user_id = $1"
+
+	tests := []struct {
+		name      string
+		filter    RequestFilter
+		wantWhere string
+		wantArgs  []any
+	}{
+		{"none", RequestFilter{}, base, []any{userID}},
+		{"status", RequestFilter{Status: "failed"}, base + " AND status = $2", []any{userID, "failed"}},
+		{"from", RequestFilter{From: &from}, base + " AND created_at >= $2", []any{userID, from}},
+		{"to", RequestFilter{To: &to}, base + " AND created_at < $2", []any{userID, to}},
+		{
+			"status and range", RequestFilter{Status: "failed", From: &from, To: &to},
+			base + " AND status = $2 AND created_at >= $3 AND created_at < $4", []any{userID, "failed", from, to},
+		},
+		{
+			// Another user's ID in the filter is overridden.
+			"foreign user", RequestFilter{UserID: &otherID}, base, []any{userID},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSQL string
+			var gotArgs []any
+			q := stubQuerier{
+				queryFn: func(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+					gotSQL, gotArgs = sql, args
+					return nil, errStubQuery
+				},
+			}
+			tt.filter.Limit, tt.filter.Offset = 10, 20
+
+			if _, err := NewTxScoped(q).GetRequestsByUserID(context.Background(), userID, tt.filter); !errors.Is(err, errStubQuery) {
+				t.Fatalf("expected query error to propagate, got %v", err)
+			}
+			if !strings.Contains(gotSQL, tt.wantWhere+"\n") {
+				t.Fatalf("unexpected where clause in:\n%s\nwant: %s", gotSQL, tt.wantWhere)
+			}
+			n := len(tt.wantArgs)
+			if !strings.Contains(gotSQL, fmt.Sprintf("LIMIT $%d OFFSET $%d", n+1, n+2)) {
+				t.Fatalf("expected pagination placeholders after filter args: %s", gotSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, append(tt.wantArgs, 10, 20)) {
+				t.Fatalf("unexpected args: %v", gotArgs)
+			}
+		})
+	}
+}
+
+func TestListRequests_ValuesNeverInterpolated(t *testing.T) {
+	status := "completed' OR '1'='1"
+	var countSQL string
+	q := stubQuerier{
+		queryRowFn: func(_ context.Context, sql string, args ...any) pgx.Row {
+			countSQL = sql
+			if len(args) != 1 || args[0] != status {
+				t.Errorf("expected status passed as argument, got %v", args)
+			}
+			return stubRow{scanFn: func(dest ...any) error {
+				*dest[0].(*int) = 0
+				return nil
+			}}
+		},
+		queryFn: func(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+			if strings.Contains(sql, status) {
+				t.Errorf("status interpolated into SQL: %s", sql)
+			}
+			if !strings.Contains(sql, "LIMIT $2 OFFSET $3") {
+				t.Errorf("expected pagination placeholders after filter args: %s", sql)
+			}
+			return nil, errStubQuery
+		},
+	}
+
+	_, _, err := NewTxScoped(q).ListRequests(context.Background(), RequestFilter{Status: status, Limit: 10})
+	if err == nil {
+		t.Fatalf("expected query error to propagate")
+	}
+	if strings.Contains(countSQL, status) {
+		t.Fatalf("status interpolated into count SQL: %s", countSQL)
+	}
+}
+
+func TestListRequests_SkipsDeleted(t *testing.T) {
+	q := stubQuerier{
+		queryRowFn: func(_ context.Context, sql string, _ ...any) pgx.Row {
+			if !strings.Contains(sql, "WHERE deleted_at IS NULL AND status = $1") {
+				t.Errorf("deleted requests must not be counted: %s", sql)
+			}
+			return stubRow{scanFn: func(dest ...any) error {
+				*dest[0].(*int) = 0
+				return nil
+			}}
+		},
+		queryFn: func(_ context.Context, sql string, _ ...any) (pgx.Rows, error) {
+			if !strings.Contains(sql, "WHERE deleted_at IS NULL AND status = $1\n") {
+				t.Errorf("deleted requests must not be listed: %s", sql)
+			}
+			return &stubRows{}, nil
+		},
+	}
+
+	requests, total, err := NewTxScoped(q).ListRequests(context.Background(), RequestFilter{Status: "completed", Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 || len(requests) != 0 {
+		t.Fatalf("expected no requests, got %d of %d", len(requests), total)
+	}
+}
+
+func TestCountRequestsByStatus_FillsMissingStatuses(t *testing.T) {
+	userID := uuid.New()
+	q := stubQuerier{
+		queryFn: func(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+			if !strings.Contains(sql, "GROUP BY status") {
+				t.Errorf("expected a single GROUP BY query, got %s", sql)
+			}
+			if len(args) != 1 || args[0] != userID {
+				t.Errorf("unexpected args: %v", args)
+			}
+			return &stubRows{rows: [][]any{
+				{models.StatusCompleted, 40},
+				{models.StatusPending, 2},
+			}}, nil
+		},
+	}
+
+	counts, err := NewTxScoped(q).CountRequestsByStatus(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("CountRequestsByStatus: %v", err)
+	}
+	want := map[string]int{
+		models.StatusPending:    2,
+		models.StatusQueued:     0,
+		models.StatusProcessing: 0,
+		models.StatusCompleted:  40,
+		models.StatusFailed:     0,
+	}
+	if len(counts) != len(want) {
+		t.Fatalf("expected %d statuses, got %v", len(want), counts)
+	}
+	for status, n := range want {
+		if got, ok := counts[status]; !ok || got != n {
+			t.Errorf("%s: expected %d, got %d (present: %v)", status, n, got, ok)
+		}
+	}
+}
+
+func TestGetChildRequests_FiltersByParentWithLatestResponse(t *testing.T) {
+	parentID, childID, userID := uuid.New(), uuid.New(), uuid.New()
+	respID, content, model, tokens := uuid.New(), "### Заключение\nok", "gpt-4o", 42
+	finishReason, refused := "length", false
+	q := stubQuerier{
+		queryFn: func(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+			if !strings.Contains(sql, "r.parent_request_id = $1") {
+				t.Errorf("expected a parent_request_id filter, got %s", sql)
+			}
+			if len(args) != 1 || args[0] != parentID {
+				t.Errorf("unexpected args: %v", args)
+			}
+			now := time.Now()
+			return &stubRows{rows: [][]any{{
+				childID, userID, (*string)(nil), (*int16)(nil), models.StatusCompleted, now, now, []byte(nil),
+				(*int)(nil), (*string)(nil), (*float64)(nil), (*float64)(nil), (*float64)(nil),
+				&respID, &childID, &content, (*int16)(nil), &model,
+				&tokens, &tokens, &finishReason, &refused, &now,
+			}}}, nil
+		},
+	}
+
+	children, err := NewTxScoped(q).GetChildRequests(context.Background(), parentID)
+	if err != nil {
+		t.Fatalf("GetChildRequests: %v", err)
+	}
+	if len(children) != 1 || children[0].ID != childID || children[0].Response == nil || children[0].Response.Content != content {
+		t.Fatalf("unexpected children: %+v", children)
+	}
+	if children[0].Response.FinishReason != finishReason {
+		t.Fatalf("expected finish reason %q, got %q", finishReason, children[0].Response.FinishReason)
+	}
+}
+
+func TestTransitionRequestStatus(t *testing.T) {
+	requestID := uuid.New()
+	for _, affected := range []string{"UPDATE 1", "UPDATE 0"} {
+		q := stubQuerier{
+			execFn: func(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				if !strings.Contains(sql, "status = $3") {
+					t.Errorf("transition must be conditional on the current status: %s", sql)
+				}
+				if !reflect.DeepEqual(args, []any{models.StatusPending, requestID, models.StatusFailed}) {
+					t.Errorf("unexpected args: %v", args)
+				}
+				return pgconn.NewCommandTag(affected), nil
+			},
+		}
+
+		moved, err := NewTxScoped(q).TransitionRequestStatus(context.Background(), requestID, models.StatusFailed, models.StatusPending)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := affected == "UPDATE 1"; moved != want {
+			t.Errorf("%s: expected moved=%v, got %v", affected, want, moved)
+		}
+	}
+}
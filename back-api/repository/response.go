@@ -24,21 +24,26 @@ func (r *Repository) CreateResponse(ctx context.Context, resp *models.Response)
 	if resp.ID == uuid.Nil {
 		resp.ID = uuid.New()
 	}
+	content, keyVersion, err := r.cipher.encrypt(resp.Content)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt response content: %w", err)
+	}
 
 	query := `
 		INSERT INTO responses (
-			id, request_id, content, model, tokens_used, processing_time_ms,
+			id, request_id, content, content_key_version, model, tokens_used, processing_time_ms,
 			cache_status, cache_entry_id, cache_trigram_similarity,
 			cache_vector_similarity, cache_combined_similarity, cache_match_method,
-			created_at
+			finish_reason, is_refusal, created_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW())
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW())
 	`
 
-	_, err := r.querier.Exec(ctx, query,
+	_, err = r.querier.Exec(ctx, query,
 		resp.ID,
 		resp.RequestID,
-		resp.Content,
+		content,
+		keyVersion,
 		resp.Model,
 		resp.TokensUsed,
 		resp.ProcessingTimeMs,
@@ -48,6 +53,8 @@ func (r *Repository) CreateResponse(ctx context.Context, resp *models.Response)
 		resp.CacheVectorSimilarity,
 		resp.CacheCombinedSimilarity,
 		nullString(resp.CacheMatchMethod),
+		nullString(resp.FinishReason),
+		resp.IsRefusal,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create response: %w", err)
@@ -58,10 +65,10 @@ func (r *Repository) CreateResponse(ctx context.Context, resp *models.Response)
 // GetResponseByRequestID retrieves the latest response for a request.
 func (r *Repository) GetResponseByRequestID(ctx context.Context, requestID uuid.UUID) (*models.Response, error) {
 	query := `
-		SELECT id, request_id, content, model, tokens_used, processing_time_ms,
+		SELECT id, request_id, content, content_key_version, model, tokens_used, processing_time_ms,
 		       cache_status, cache_entry_id, cache_trigram_similarity,
 		       cache_vector_similarity, cache_combined_similarity, cache_match_method,
-		       created_at
+		       finish_reason, is_refusal, created_at
 		FROM responses
 		WHERE request_id = $1
 		ORDER BY created_at DESC
@@ -71,10 +78,13 @@ func (r *Repository) GetResponseByRequestID(ctx context.Context, requestID uuid.
 	var resp models.Response
 	var cacheStatus sql.NullString
 	var cacheMatchMethod sql.NullString
+	var finishReason sql.NullString
+	var contentKeyVersion *int16
 	err := r.querier.QueryRow(ctx, query, requestID).Scan(
 		&resp.ID,
 		&resp.RequestID,
 		&resp.Content,
+		&contentKeyVersion,
 		&resp.Model,
 		&resp.TokensUsed,
 		&resp.ProcessingTimeMs,
@@ -84,6 +94,8 @@ func (r *Repository) GetResponseByRequestID(ctx context.Context, requestID uuid.
 		&resp.CacheVectorSimilarity,
 		&resp.CacheCombinedSimilarity,
 		&cacheMatchMethod,
+		&finishReason,
+		&resp.IsRefusal,
 		&resp.CreatedAt,
 	)
 	if err != nil {
@@ -92,12 +104,16 @@ func (r *Repository) GetResponseByRequestID(ctx context.Context, requestID uuid.
 		}
 		return nil, fmt.Errorf("failed to get response: %w", err)
 	}
+	if resp.Content, err = r.cipher.decrypt(resp.Content, contentKeyVersion); err != nil {
+		return nil, fmt.Errorf("failed to decrypt response content: %w", err)
+	}
 	if cacheStatus.Valid {
 		resp.CacheStatus = cacheStatus.String
 	}
 	if cacheMatchMethod.Valid {
 		resp.CacheMatchMethod = cacheMatchMethod.String
 	}
+	resp.FinishReason = finishReason.String
 
 	return &resp, nil
 }
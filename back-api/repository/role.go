@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+// ListRoles returns all roles with their permissions, ordered by name.
+func (r *Repository) ListRoles(ctx context.Context) ([]models.Role, error) {
+	query := `
+		SELECT r.id, r.name, COALESCE(r.description, ''), r.created_at,
+		       p.id, p.name, p.resource, p.action, p.description, p.created_at
+		FROM roles r
+		LEFT JOIN role_permissions rp ON r.id = rp.role_id
+		LEFT JOIN permissions p ON rp.permission_id = p.id
+		ORDER BY r.name, p.name
+	`
+
+	rows, err := r.querier.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := []models.Role{}
+	for rows.Next() {
+		var role models.Role
+		var permID *int
+		var permName, permResource, permAction, permDesc *string
+		var permCreated *time.Time
+
+		if err := rows.Scan(
+			&role.ID, &role.Name, &role.Description, &role.CreatedAt,
+			&permID, &permName, &permResource, &permAction, &permDesc, &permCreated,
+		); err != nil {
+			return nil, fmt.Errorf("scan role row: %w", err)
+		}
+
+		if len(roles) == 0 || roles[len(roles)-1].ID != role.ID {
+			roles = append(roles, role)
+		}
+		if permID != nil {
+			cur := &roles[len(roles)-1]
+			perm := models.Permission{
+				ID:        *permID,
+				Name:      *permName,
+				Resource:  *permResource,
+				Action:    *permAction,
+				CreatedAt: *permCreated,
+			}
+			if permDesc != nil {
+				perm.Description = *permDesc
+			}
+			cur.Permissions = append(cur.Permissions, perm)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate role rows: %w", err)
+	}
+	return roles, nil
+}
+
+// CreateRole inserts a role and fills in its ID and creation time.
+func (r *Repository) CreateRole(ctx context.Context, role *models.Role) error {
+	query := `
+		INSERT INTO roles (name, description)
+		VALUES ($1, NULLIF($2, ''))
+		RETURNING id, created_at
+	`
+
+	err := r.querier.QueryRow(ctx, query, role.Name, role.Description).Scan(&role.ID, &role.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("role %q already exists: %w", role.Name, apperr.ErrConflict)
+		}
+		return fmt.Errorf("create role: %w", err)
+	}
+	return nil
+}
+
+// ListPermissions returns all permissions ordered by name.
+func (r *Repository) ListPermissions(ctx context.Context) ([]models.Permission, error) {
+	query := `
+		SELECT id, name, resource, action, COALESCE(description, ''), created_at
+		FROM permissions
+		ORDER BY name
+	`
+
+	rows, err := r.querier.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list permissions: %w", err)
+	}
+	defer rows.Close()
+
+	perms := []models.Permission{}
+	for rows.Next() {
+		var p models.Permission
+		if err := rows.Scan(&p.ID, &p.Name, &p.Resource, &p.Action, &p.Description, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan permission row: %w", err)
+		}
+		perms = append(perms, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate permission rows: %w", err)
+	}
+	return perms, nil
+}
+
+// CreatePermission inserts a permission and fills in its ID and creation time.
+func (r *Repository) CreatePermission(ctx context.Context, perm *models.Permission) error {
+	query := `
+		INSERT INTO permissions (name, resource, action, description)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		RETURNING id, created_at
+	`
+
+	err := r.querier.QueryRow(ctx, query, perm.Name, perm.Resource, perm.Action, perm.Description).
+		Scan(&perm.ID, &perm.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("permission %q already exists: %w", perm.Name, apperr.ErrConflict)
+		}
+		return fmt.Errorf("create permission: %w", err)
+	}
+	return nil
+}
+
+// AttachPermission grants a permission to a role. Attaching twice is a no-op.
+// Returns ErrNotFound if either the role or the permission does not exist.
+func (r *Repository) AttachPermission(ctx context.Context, roleName, permName string) error {
+	query := `
+		WITH role AS (SELECT id FROM roles WHERE name = $1),
+		     perm AS (SELECT id FROM permissions WHERE name = $2),
+		     ins AS (
+		         INSERT INTO role_permissions (role_id, permission_id)
+		         SELECT role.id, perm.id FROM role, perm
+		         ON CONFLICT (role_id, permission_id) DO NOTHING
+		     )
+		SELECT EXISTS (SELECT 1 FROM role), EXISTS (SELECT 1 FROM perm)
+	`
+
+	var roleExists, permExists bool
+	if err := r.querier.QueryRow(ctx, query, roleName, permName).Scan(&roleExists, &permExists); err != nil {
+		return fmt.Errorf("attach permission: %w", err)
+	}
+	if !roleExists {
+		return fmt.Errorf("role %q: %w", roleName, apperr.ErrNotFound)
+	}
+	if !permExists {
+		return fmt.Errorf("permission %q: %w", permName, apperr.ErrNotFound)
+	}
+	return nil
+}
+
+// DetachPermission revokes a permission from a role. Returns ErrNotFound if
+// the role did not have it.
+func (r *Repository) DetachPermission(ctx context.Context, roleName, permName string) error {
+	query := `
+		DELETE FROM role_permissions rp
+		USING roles r, permissions p
+		WHERE rp.role_id = r.id AND rp.permission_id = p.id
+		  AND r.name = $1 AND p.name = $2
+	`
+
+	tag, err := r.querier.Exec(ctx, query, roleName, permName)
+	if err != nil {
+		return fmt.Errorf("detach permission: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("role %q has no permission %q: %w", roleName, permName, apperr.ErrNotFound)
+	}
+	return nil
+}
+
+// UnassignRoleFromUser removes a role from a user. Returns ErrNotFound if the
+// user did not have it.
+func (r *Repository) UnassignRoleFromUser(ctx context.Context, userID uuid.UUID, roleName string) error {
+	query := `
+		DELETE FROM user_roles ur
+		USING roles r
+		WHERE ur.role_id = r.id AND ur.user_id = $1 AND r.name = $2
+	`
+
+	tag, err := r.querier.Exec(ctx, query, userID, roleName)
+	if err != nil {
+		return fmt.Errorf("unassign role %q: %w", roleName, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("user has no role %q: %w", roleName, apperr.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+func TestCreateRole_MapsUniqueViolationToConflict(t *testing.T) {
+	repo := NewTxScoped(stubQuerier{
+		queryRowFn: func(_ context.Context, _ string, _ ...any) pgx.Row {
+			return stubRow{scanFn: func(_ ...any) error {
+				return &pgconn.PgError{Code: "23505"}
+			}}
+		},
+	})
+
+	err := repo.CreateRole(context.Background(), &models.Role{Name: "doctor"})
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+}
+
+func TestAttachPermission_ReportsMissingRoleOrPermission(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		roleExists bool
+		permExists bool
+		wantErr    bool
+	}{
+		{"both exist", true, true, false},
+		{"missing role", false, true, true},
+		{"missing permission", true, false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := NewTxScoped(stubQuerier{
+				queryRowFn: func(_ context.Context, _ string, _ ...any) pgx.Row {
+					return stubRow{scanFn: func(dest ...any) error {
+						*dest[0].(*bool) = tc.roleExists
+						*dest[1].(*bool) = tc.permExists
+						return nil
+					}}
+				},
+			})
+
+			err := repo.AttachPermission(context.Background(), "doctor", "ekg:review")
+			if !tc.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, apperr.ErrNotFound)
+		})
+	}
+}
+
+func TestDetachPermission_NotFoundWhenNothingDeleted(t *testing.T) {
+	repo := NewTxScoped(stubQuerier{
+		execFn: func(_ context.Context, _ string, _ ...any) (pgconn.CommandTag, error) {
+			return pgconn.NewCommandTag("DELETE 0"), nil
+		},
+	})
+
+	err := repo.DetachPermission(context.Background(), "doctor", "ekg:review")
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+}
+
+func TestAssignRoleToUser_MapsMissingUserToNotFound(t *testing.T) {
+	repo := NewTxScoped(stubQuerier{
+		queryRowFn: func(_ context.Context, _ string, _ ...any) pgx.Row {
+			return stubRow{scanFn: func(_ ...any) error {
+				return &pgconn.PgError{Code: "23503"}
+			}}
+		},
+	})
+
+	err := repo.AssignRoleToUser(context.Background(), uuid.New(), "doctor")
+	assert.ErrorIs(t, err, apperr.ErrUserNotFound)
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -44,3 +46,35 @@ func (r stubRow) Scan(dest ...any) error {
 	}
 	return nil
 }
+
+// stubRows is a pgx.Rows over in-memory values. Scan assigns each value to
+// the matching destination, which must point to the value's exact type.
+type stubRows struct {
+	rows [][]any
+	pos  int
+}
+
+func (r *stubRows) Close()                                       {}
+func (r *stubRows) Err() error                                   { return nil }
+func (r *stubRows) CommandTag() pgconn.CommandTag                { return pgconn.NewCommandTag("SELECT") }
+func (r *stubRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *stubRows) RawValues() [][]byte                          { return nil }
+func (r *stubRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *stubRows) Next() bool {
+	r.pos++
+	return r.pos <= len(r.rows)
+}
+
+func (r *stubRows) Values() ([]any, error) { return r.rows[r.pos-1], nil }
+
+func (r *stubRows) Scan(dest ...any) error {
+	row := r.rows[r.pos-1]
+	if len(dest) != len(row) {
+		return fmt.Errorf("scan: %d destinations for %d values", len(dest), len(row))
+	}
+	for i, v := range row {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -106,3 +107,32 @@ func (r *Repository) RevokeAllUserRefreshTokens(ctx context.Context, userID uuid
 	}
 	return nil
 }
+
+// refreshTokenPurgeBatch bounds the rows deleted per statement so a large
+// backlog does not hold locks on refresh_tokens for long.
+const refreshTokenPurgeBatch = 1000
+
+// DeleteExpiredRefreshTokens deletes refresh tokens that have expired or were
+// revoked before the given time, in batches, and returns the number deleted.
+func (r *Repository) DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM refresh_tokens
+		WHERE id IN (
+			SELECT id FROM refresh_tokens
+			WHERE expires_at < NOW() OR revoked_at < $1
+			LIMIT $2
+		)
+	`
+
+	var total int64
+	for {
+		tag, err := r.querier.Exec(ctx, query, before, refreshTokenPurgeBatch)
+		if err != nil {
+			return total, fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+		}
+		total += tag.RowsAffected()
+		if tag.RowsAffected() < refreshTokenPurgeBatch {
+			return total, nil
+		}
+	}
+}
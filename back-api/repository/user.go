@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 
 	"github.com/fedutinova/smartheart/back-api/apperr"
@@ -38,6 +39,7 @@ func (r *Repository) CreateUser(ctx context.Context, user *models.User) error {
 func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
 		SELECT u.id, u.username, u.email, u.password_hash, u.created_at, u.updated_at,
+		       COALESCE(u.two_factor_secret, ''), u.two_factor_enabled, u.email_verified,
 		       r.id, r.name, r.description, r.created_at
 		FROM users u
 		LEFT JOIN user_roles ur ON u.id = ur.user_id
@@ -52,6 +54,7 @@ func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*models.
 func (r *Repository) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
 	query := `
 		SELECT u.id, u.username, u.email, u.password_hash, u.created_at, u.updated_at,
+		       COALESCE(u.two_factor_secret, ''), u.two_factor_enabled, u.email_verified,
 		       r.id, r.name, r.description, r.created_at
 		FROM users u
 		LEFT JOIN user_roles ur ON u.id = ur.user_id
@@ -79,6 +82,7 @@ func (r *Repository) scanUserWithRoles(ctx context.Context, query string, arg an
 
 		if err := rows.Scan(
 			&u.ID, &u.Username, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt,
+			&u.TwoFactorSecret, &u.TwoFactorEnabled, &u.EmailVerified,
 			&roleID, &roleName, &roleDesc, &roleCreated,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan user row: %w", err)
@@ -105,6 +109,38 @@ func (r *Repository) scanUserWithRoles(ctx context.Context, query string, arg an
 	return user, nil
 }
 
+// SetTwoFactorSecret stores a new encrypted TOTP secret for the user and
+// leaves 2FA disabled until EnableTwoFactor confirms it.
+func (r *Repository) SetTwoFactorSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	query := `UPDATE users SET two_factor_secret = $1, two_factor_enabled = FALSE, updated_at = NOW() WHERE id = $2`
+
+	tag, err := r.querier.Exec(ctx, query, secret, userID)
+	if err != nil {
+		return fmt.Errorf("set two-factor secret: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrUserNotFound
+	}
+	return nil
+}
+
+// EnableTwoFactor turns on 2FA for a user who has an enrolled secret.
+func (r *Repository) EnableTwoFactor(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users SET two_factor_enabled = TRUE, updated_at = NOW()
+		WHERE id = $1 AND two_factor_secret IS NOT NULL
+	`
+
+	tag, err := r.querier.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("enable two-factor: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrUserNotFound
+	}
+	return nil
+}
+
 // GetUserRoles retrieves all roles with permissions for a user in a single query.
 func (r *Repository) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
 	query := `
@@ -171,21 +207,52 @@ func (r *Repository) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]mode
 	return roles, nil
 }
 
-// AssignRoleToUser assigns a role to a user.
-// Returns an error if the role does not exist.
-func (r *Repository) AssignRoleToUser(ctx context.Context, userID uuid.UUID, roleName string) error {
+// UpdateUser saves the user's username, email and email verification flag.
+// Returns ErrConflict if the username or email is taken by another user.
+func (r *Repository) UpdateUser(ctx context.Context, user *models.User) error {
 	query := `
-		INSERT INTO user_roles (user_id, role_id)
-		SELECT $1, id FROM roles WHERE name = $2
-		ON CONFLICT (user_id, role_id) DO NOTHING
+		UPDATE users
+		SET username = $2, email = $3, email_verified = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
 	`
 
-	tag, err := r.querier.Exec(ctx, query, userID, roleName)
+	err := r.querier.QueryRow(ctx, query, user.ID, user.Username, user.Email, user.EmailVerified).
+		Scan(&user.UpdatedAt)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.ErrUserNotFound
+		}
+		if isUniqueViolation(err) {
+			return fmt.Errorf("username or email is already taken: %w", apperr.ErrConflict)
+		}
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+// AssignRoleToUser assigns a role to a user. Assigning a role the user
+// already has is a no-op. Returns ErrNotFound if the role or user does not exist.
+func (r *Repository) AssignRoleToUser(ctx context.Context, userID uuid.UUID, roleName string) error {
+	query := `
+		WITH role AS (SELECT id FROM roles WHERE name = $2),
+		     ins AS (
+		         INSERT INTO user_roles (user_id, role_id)
+		         SELECT $1, id FROM role
+		         ON CONFLICT (user_id, role_id) DO NOTHING
+		     )
+		SELECT EXISTS (SELECT 1 FROM role)
+	`
+
+	var roleExists bool
+	if err := r.querier.QueryRow(ctx, query, userID, roleName).Scan(&roleExists); err != nil {
+		if isForeignKeyViolation(err) {
+			return apperr.ErrUserNotFound
+		}
 		return fmt.Errorf("failed to assign role %q: %w", roleName, err)
 	}
-	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("role %q does not exist", roleName)
+	if !roleExists {
+		return fmt.Errorf("role %q does not exist: %w", roleName, apperr.ErrNotFound)
 	}
 	return nil
 }
@@ -226,3 +293,9 @@ func isUniqueViolation(err error) bool {
 	var pgErr *pgconn.PgError
 	return errors.As(err, &pgErr) && pgErr.Code == "23505"
 }
+
+// isForeignKeyViolation checks if the error is a foreign key violation (PostgreSQL code 23503)
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -63,3 +64,27 @@ func TestCreateUser_WrapsUnexpectedExecError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to create user")
 	assert.NotErrorIs(t, err, apperr.ErrConflict)
 }
+
+func TestUpdateUser_MapsUniqueViolationToConflict(t *testing.T) {
+	repo := NewTxScoped(stubQuerier{
+		queryRowFn: func(_ context.Context, _ string, _ ...any) pgx.Row {
+			return stubRow{scanFn: func(_ ...any) error {
+				return &pgconn.PgError{Code: "23505"}
+			}}
+		},
+	})
+
+	err := repo.UpdateUser(context.Background(), &models.User{ID: uuid.New(), Username: "alice"})
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+}
+
+func TestUpdateUser_MissingUser(t *testing.T) {
+	repo := NewTxScoped(stubQuerier{
+		queryRowFn: func(_ context.Context, _ string, _ ...any) pgx.Row {
+			return stubRow{scanFn: func(_ ...any) error { return pgx.ErrNoRows }}
+		},
+	})
+
+	err := repo.UpdateUser(context.Background(), &models.User{ID: uuid.New()})
+	assert.ErrorIs(t, err, apperr.ErrUserNotFound)
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/fedutinova/smartheart/back-api/auth"
 	"github.com/fedutinova/smartheart/back-api/config"
 	"github.com/fedutinova/smartheart/back-api/handler"
+	"github.com/fedutinova/smartheart/back-api/logging"
+	"github.com/fedutinova/smartheart/back-api/telemetry"
 )
 
 func NewRouter(h *handler.Handler, cfg config.Config) http.Handler {
@@ -27,8 +29,10 @@ func NewRouter(h *handler.Handler, cfg config.Config) http.Handler {
 	}))
 	r.Use(apiSecurityHeaders)
 	r.Use(middleware.RequestID)
+	r.Use(requestIDHeader)
+	r.Use(telemetry.Middleware)
 	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
+	r.Use(logging.Middleware)
 	r.Use(middleware.Recoverer)
 	r.Use(timeoutExcept(60*time.Second, "/v1/events"))
 
@@ -50,7 +54,7 @@ func NewRouter(h *handler.Handler, cfg config.Config) http.Handler {
 func rateLimitHandler(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusTooManyRequests)
-	_, _ = w.Write([]byte(`{"error":"rate limit exceeded","retry_after":"60s"}`))
+	_, _ = w.Write([]byte(`{"error":{"code":"rate_limited","message":"rate limit exceeded","details":{"retry_after":"60s"}}}`))
 }
 
 // EndpointRateLimit returns a rate-limiting middleware for a specific endpoint.
@@ -85,6 +89,17 @@ func apiSecurityHeaders(next http.Handler) http.Handler {
 	})
 }
 
+// requestIDHeader echoes the request ID assigned by middleware.RequestID in
+// the X-Request-ID response header, so clients can quote it in bug reports.
+func requestIDHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := logging.RequestID(r.Context()); id != "" {
+			w.Header().Set(middleware.RequestIDHeader, id)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // timeoutExcept wraps chi's Timeout middleware but skips it for the given paths
 // (e.g. long-lived SSE connections that must stay open indefinitely).
 func timeoutExcept(timeout time.Duration, skipPaths ...string) func(http.Handler) http.Handler {
@@ -47,7 +47,7 @@ func WebhookIPWhitelist(shopID string) func(http.Handler) http.Handler {
 			ip := net.ParseIP(ipStr)
 			if ip == nil {
 				slog.Warn("Webhook rejected: invalid IP", "ip", ipStr)
-				http.Error(w, "forbidden", http.StatusForbidden)
+				writeForbidden(w)
 				return
 			}
 
@@ -59,7 +59,7 @@ func WebhookIPWhitelist(shopID string) func(http.Handler) http.Handler {
 			}
 
 			slog.Warn("Webhook rejected: IP not in YooKassa whitelist", "ip", ipStr)
-			http.Error(w, "forbidden", http.StatusForbidden)
+			writeForbidden(w)
 		})
 	}
 }
@@ -83,3 +83,10 @@ func extractIP(r *http.Request) string {
 	}
 	return host
 }
+
+// writeForbidden writes the standard JSON error body for a rejected webhook.
+func writeForbidden(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	_, _ = w.Write([]byte(`{"error":{"code":"forbidden","message":"forbidden"}}`))
+}
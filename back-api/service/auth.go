@@ -5,7 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"net/mail"
+	netmail "net/mail"
 	"regexp"
 	"time"
 
@@ -15,6 +15,7 @@ import (
 	"github.com/fedutinova/smartheart/back-api/apperr"
 	"github.com/fedutinova/smartheart/back-api/auth"
 	"github.com/fedutinova/smartheart/back-api/config"
+	"github.com/fedutinova/smartheart/back-api/mail"
 	"github.com/fedutinova/smartheart/back-api/models"
 	"github.com/fedutinova/smartheart/back-api/repository"
 )
@@ -22,19 +23,42 @@ import (
 // AuthService handles authentication business logic.
 type AuthService interface {
 	Register(ctx context.Context, username, email, password string) (uuid.UUID, error)
-	Login(ctx context.Context, email, password string) (*auth.TokenPair, error)
+	Login(ctx context.Context, email, password string) (*LoginResult, error)
+	LoginTwoFactor(ctx context.Context, challengeToken, code string) (*LoginResult, error)
+	EnrollTwoFactor(ctx context.Context, userID uuid.UUID) (*auth.TOTPEnrollment, error)
+	VerifyTwoFactor(ctx context.Context, userID uuid.UUID, code string) error
 	Refresh(ctx context.Context, refreshToken string) (*auth.TokenPair, error)
 	Logout(ctx context.Context, refreshToken, accessToken string, claims *auth.Claims) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	UpdateProfile(ctx context.Context, userID uuid.UUID, upd ProfileUpdate) (*models.User, error)
 }
 
 type authService struct {
 	repo     repository.Store
 	sessions auth.SessionService
+	keys     *auth.Keys
+	box      *auth.SecretBox
+	notifier mail.Notifier
 	cfg      config.JWTConfig
+
+	frontendURL      string
+	requireVerified  bool
+	reverifyOnChange bool
 }
 
-func NewAuthService(repo repository.Store, sessions auth.SessionService, cfg config.JWTConfig) AuthService {
-	return &authService{repo: repo, sessions: sessions, cfg: cfg}
+func NewAuthService(repo repository.Store, sessions auth.SessionService, keys *auth.Keys, notifier mail.Notifier, cfg config.Config) AuthService {
+	return &authService{
+		repo:             repo,
+		sessions:         sessions,
+		keys:             keys,
+		box:              auth.NewSecretBox(cfg.JWT.Secret),
+		notifier:         notifier,
+		cfg:              cfg.JWT,
+		frontendURL:      cfg.FrontendURL,
+		requireVerified:  cfg.Auth.RequireEmailVerification,
+		reverifyOnChange: cfg.Auth.ReverifyOnEmailChange,
+	}
 }
 
 const (
@@ -58,7 +82,7 @@ func (s *authService) Register(ctx context.Context, username, email, password st
 	if len(username) > maxUsernameLen {
 		return uuid.Nil, fmt.Errorf("username must not exceed %d characters: %w", maxUsernameLen, apperr.ErrValidation)
 	}
-	if _, err := mail.ParseAddress(email); err != nil {
+	if _, err := netmail.ParseAddress(email); err != nil {
 		return uuid.Nil, fmt.Errorf("invalid email format: %w", apperr.ErrValidation)
 	}
 	if err := validatePassword(password); err != nil {
@@ -76,12 +100,24 @@ func (s *authService) Register(ctx context.Context, username, email, password st
 		PasswordHash: passwordHash,
 	}
 
+	rawToken, err := generateToken()
+	if err != nil {
+		return uuid.Nil, apperr.WrapInternal("generate verification token", err)
+	}
+
 	if err := s.repo.RunTx(ctx, func(tx pgx.Tx) error {
 		txRepo := s.repo.WithTx(tx)
 		if err := txRepo.CreateUser(ctx, user); err != nil {
 			return err
 		}
-		return txRepo.AssignRoleToUser(ctx, user.ID, auth.RoleUser)
+		if err := txRepo.AssignRoleToUser(ctx, user.ID, auth.RoleUser); err != nil {
+			return err
+		}
+		return txRepo.CreateEmailVerificationToken(ctx, &models.EmailVerificationToken{
+			UserID:    user.ID,
+			TokenHash: auth.HashToken(rawToken),
+			ExpiresAt: time.Now().Add(verificationTokenTTL),
+		})
 	}); err != nil {
 		if apperr.IsConflict(err) || apperr.IsValidation(err) {
 			return uuid.Nil, err
@@ -89,10 +125,20 @@ func (s *authService) Register(ctx context.Context, username, email, password st
 		return uuid.Nil, apperr.WrapInternal("register user", err)
 	}
 
+	s.sendVerificationEmail(ctx, email, rawToken)
+
 	return user.ID, nil
 }
 
-func (s *authService) Login(ctx context.Context, email, password string) (*auth.TokenPair, error) {
+// LoginResult is the outcome of a password login: a token pair, or for users
+// with 2FA enabled a challenge token to complete via LoginTwoFactor.
+type LoginResult struct {
+	UserID         uuid.UUID
+	Tokens         *auth.TokenPair
+	ChallengeToken string
+}
+
+func (s *authService) Login(ctx context.Context, email, password string) (*LoginResult, error) {
 	if email == "" || password == "" {
 		return nil, fmt.Errorf("email and password are required: %w", apperr.ErrValidation)
 	}
@@ -117,12 +163,28 @@ func (s *authService) Login(ctx context.Context, email, password string) (*auth.
 		return nil, fmt.Errorf("invalid email or password: %w", apperr.ErrInvalidCredentials)
 	}
 
+	if s.requireVerified && !user.EmailVerified {
+		return nil, apperr.ErrEmailNotVerified
+	}
+
 	// Successful login — reset counter
 	if err := s.sessions.ResetLoginAttempts(ctx, email); err != nil {
 		slog.WarnContext(ctx, "Failed to reset login attempts", "email", email, "error", err)
 	}
 
-	return s.issueTokenPair(ctx, user)
+	if user.TwoFactorEnabled {
+		challenge, err := s.issueTwoFactorChallenge(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{UserID: user.ID, ChallengeToken: challenge}, nil
+	}
+
+	tokens, err := s.issueTokenPair(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{UserID: user.ID, Tokens: tokens}, nil
 }
 
 func (s *authService) Refresh(ctx context.Context, refreshToken string) (*auth.TokenPair, error) {
@@ -193,10 +255,9 @@ func (s *authService) Logout(ctx context.Context, refreshToken, accessToken stri
 	}
 
 	if claims != nil && accessToken != "" {
-		tokenHash := auth.HashToken(accessToken)
 		ttl := time.Until(claims.ExpiresAt.Time)
 		if ttl > 0 {
-			if err := s.sessions.StoreBlacklistedToken(ctx, tokenHash, ttl); err != nil {
+			if err := s.sessions.StoreBlacklistedToken(ctx, auth.RevocationID(accessToken, claims), ttl); err != nil {
 				slog.ErrorContext(ctx, "Failed to blacklist access token", "error", err)
 				errs = append(errs, err)
 			}
@@ -216,7 +277,7 @@ func (s *authService) issueTokenPair(ctx context.Context, user *models.User) (*a
 	}
 
 	tokens, err := auth.NewTokenPair(
-		s.cfg.Secret,
+		s.keys,
 		s.cfg.Issuer,
 		user.ID,
 		roleNames,
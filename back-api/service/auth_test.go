@@ -34,10 +34,33 @@ func newAuthService(t *testing.T) (*authService, *repomocks.MockStore, *authmock
 		TTLAccess:  15 * time.Minute,
 		TTLRefresh: 24 * time.Hour,
 	}
-	svc := NewAuthService(repo, sessions, cfg).(*authService)
+	svc := NewAuthService(repo, sessions, auth.NewHS256Keys(cfg.Secret), &stubNotifier{}, config.Config{JWT: cfg}).(*authService)
 	return svc, repo, sessions
 }
 
+// stubNotifier records sent emails instead of delivering them.
+type stubNotifier struct {
+	sent []string // recipients
+	body string   // last HTML body
+}
+
+func (n *stubNotifier) SendEmail(_ context.Context, to, _, htmlBody string) error {
+	n.sent = append(n.sent, to)
+	n.body = htmlBody
+	return nil
+}
+
+// expectTx runs RunTx callbacks immediately against repo itself, so
+// expectations on repo also match calls made inside the transaction.
+func expectTx(repo *repomocks.MockStore) {
+	repo.EXPECT().WithTx(mock.Anything).Return(repo)
+	repo.EXPECT().
+		RunTx(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, fn func(pgx.Tx) error) error {
+			return fn(nil)
+		})
+}
+
 // --- Register ---
 
 func TestRegister_Success(t *testing.T) {
@@ -70,9 +93,21 @@ func TestRegister_Success(t *testing.T) {
 		AssignRoleToUser(mock.Anything, mock.Anything, auth.RoleUser).
 		Return(nil)
 
+	repo.EXPECT().
+		CreateEmailVerificationToken(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, token *models.EmailVerificationToken) {
+			assert.NotEmpty(t, token.TokenHash)
+			assert.True(t, token.ExpiresAt.After(time.Now()))
+		}).
+		Return(nil)
+
 	id, err := svc.Register(ctx, "testuser", "test@example.com", "strongpassword123")
 	require.NoError(t, err)
 	assert.NotEqual(t, uuid.Nil, id)
+
+	notifier := svc.notifier.(*stubNotifier)
+	assert.Equal(t, []string{"test@example.com"}, notifier.sent)
+	assert.Contains(t, notifier.body, "/verify-email?token=")
 }
 
 func TestRegister_EmptyFields(t *testing.T) {
@@ -217,10 +252,13 @@ func TestLogin_Success(t *testing.T) {
 		CreateRefreshToken(mock.Anything, mock.Anything).
 		Return(nil)
 
-	tokens, err := svc.Login(ctx, "test@example.com", password)
+	result, err := svc.Login(ctx, "test@example.com", password)
 	require.NoError(t, err)
-	assert.NotEmpty(t, tokens.AccessToken)
-	assert.NotEmpty(t, tokens.RefreshToken)
+	require.NotNil(t, result.Tokens)
+	assert.NotEmpty(t, result.Tokens.AccessToken)
+	assert.NotEmpty(t, result.Tokens.RefreshToken)
+	assert.Empty(t, result.ChallengeToken)
+	assert.Equal(t, userID, result.UserID)
 }
 
 func TestLogin_EmptyFields(t *testing.T) {
@@ -332,9 +370,9 @@ func TestLogin_RedisFailAllowsLogin(t *testing.T) {
 		CreateRefreshToken(mock.Anything, mock.Anything).
 		Return(nil)
 
-	tokens, err := svc.Login(ctx, "test@example.com", password)
+	result, err := svc.Login(ctx, "test@example.com", password)
 	require.NoError(t, err)
-	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, result.Tokens.AccessToken)
 }
 
 // --- Refresh ---
@@ -527,6 +565,22 @@ func TestLogout_WithRefreshAndAccess(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestLogout_BlacklistsByJTI(t *testing.T) {
+	svc, _, sessions := newAuthService(t)
+	ctx := context.Background()
+
+	claims := &auth.Claims{UserID: uuid.New().String()}
+	claims.ID = uuid.NewString()
+	claims.ExpiresAt = jwt5ExpiresAt(time.Now().Add(10 * time.Minute))
+
+	sessions.EXPECT().
+		StoreBlacklistedToken(mock.Anything, "jti:"+claims.ID, mock.Anything).
+		Return(nil)
+
+	err := svc.Logout(ctx, "", "access-token", claims)
+	require.NoError(t, err)
+}
+
 func TestLogout_EmptyTokens(t *testing.T) {
 	svc, _, _ := newAuthService(t)
 	ctx := context.Background()
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/mail"
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+const verificationTokenTTL = 24 * time.Hour
+
+// VerifyEmail marks the address of the token's owner as verified. Tokens are
+// single-use and expire after verificationTokenTTL.
+func (s *authService) VerifyEmail(ctx context.Context, rawToken string) error {
+	if rawToken == "" {
+		return fmt.Errorf("token is required: %w", apperr.ErrValidation)
+	}
+
+	token, err := s.repo.GetValidEmailVerificationToken(ctx, auth.HashToken(rawToken))
+	if err != nil {
+		if errors.Is(err, apperr.ErrInvalidToken) {
+			return fmt.Errorf("invalid or expired verification token: %w", apperr.ErrInvalidToken)
+		}
+		return apperr.WrapInternal("get verification token", err)
+	}
+
+	if err := s.repo.RunTx(ctx, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		if err := txRepo.MarkEmailVerified(ctx, token.UserID); err != nil {
+			return err
+		}
+		return txRepo.MarkEmailVerificationTokenUsed(ctx, token.ID)
+	}); err != nil {
+		return apperr.WrapInternal("verify email", err)
+	}
+	return nil
+}
+
+// ResendVerification mails a fresh verification link to an unverified
+// address, replacing any link sent before. Unknown and already verified
+// addresses are ignored so the endpoint does not reveal which emails exist.
+func (s *authService) ResendVerification(ctx context.Context, email string) error {
+	if email == "" {
+		return fmt.Errorf("email is required: %w", apperr.ErrValidation)
+	}
+
+	user, err := s.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		slog.InfoContext(ctx, "Verification resend requested for unknown email")
+		return nil //nolint:nilerr // intentional: prevent email enumeration
+	}
+	if user.EmailVerified {
+		return nil
+	}
+
+	rawToken, err := generateToken()
+	if err != nil {
+		return apperr.WrapInternal("generate verification token", err)
+	}
+
+	if err := s.repo.RunTx(ctx, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		if err := txRepo.InvalidateUserEmailVerificationTokens(ctx, user.ID); err != nil {
+			return err
+		}
+		return txRepo.CreateEmailVerificationToken(ctx, &models.EmailVerificationToken{
+			UserID:    user.ID,
+			TokenHash: auth.HashToken(rawToken),
+			ExpiresAt: time.Now().Add(verificationTokenTTL),
+		})
+	}); err != nil {
+		return apperr.WrapInternal("resend verification", err)
+	}
+
+	s.sendVerificationEmail(ctx, user.Email, rawToken)
+	return nil
+}
+
+// sendVerificationEmail mails the verification link. Failures are logged and
+// do not fail registration.
+func (s *authService) sendVerificationEmail(ctx context.Context, email, rawToken string) {
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.frontendURL, rawToken)
+	if err := s.notifier.SendEmail(ctx, email, "Умное сердце — Подтверждение email", mail.EmailVerificationEmail(link)); err != nil {
+		slog.ErrorContext(ctx, "Failed to send verification email", "email", email, "error", err)
+	}
+}
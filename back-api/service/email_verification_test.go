@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+func TestVerifyEmail_Success(t *testing.T) {
+	svc, repo, _ := newAuthService(t)
+	ctx := context.Background()
+	token := &models.EmailVerificationToken{ID: uuid.New(), UserID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)}
+
+	repo.EXPECT().GetValidEmailVerificationToken(mock.Anything, auth.HashToken("raw")).Return(token, nil)
+	repo.EXPECT().WithTx(mock.Anything).Return(repo)
+	repo.EXPECT().
+		RunTx(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, fn func(pgx.Tx) error) error {
+			return fn(nil)
+		})
+	repo.EXPECT().MarkEmailVerified(mock.Anything, token.UserID).Return(nil)
+	repo.EXPECT().MarkEmailVerificationTokenUsed(mock.Anything, token.ID).Return(nil)
+
+	require.NoError(t, svc.VerifyEmail(ctx, "raw"))
+}
+
+func TestVerifyEmail_InvalidToken(t *testing.T) {
+	svc, repo, _ := newAuthService(t)
+
+	repo.EXPECT().
+		GetValidEmailVerificationToken(mock.Anything, auth.HashToken("raw")).
+		Return(nil, apperr.ErrInvalidToken)
+
+	err := svc.VerifyEmail(context.Background(), "raw")
+	assert.ErrorIs(t, err, apperr.ErrInvalidToken)
+}
+
+func TestLogin_BlocksUnverifiedEmailWhenRequired(t *testing.T) {
+	svc, repo, sessions := newAuthService(t)
+	svc.requireVerified = true
+	ctx := context.Background()
+
+	hash, err := auth.HashPassword("strongpassword123")
+	require.NoError(t, err)
+
+	sessions.EXPECT().IncrLoginAttempts(mock.Anything, "test@example.com", loginLockoutWindow).Return(int64(1), nil)
+	repo.EXPECT().
+		GetUserByEmail(mock.Anything, "test@example.com").
+		Return(&models.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: hash}, nil)
+
+	_, err = svc.Login(ctx, "test@example.com", "strongpassword123")
+	assert.ErrorIs(t, err, apperr.ErrEmailNotVerified)
+	assert.ErrorIs(t, err, apperr.ErrForbidden)
+}
+
+func TestResendVerification_ReplacesTokenAndMails(t *testing.T) {
+	svc, repo, _ := newAuthService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	repo.EXPECT().
+		GetUserByEmail(mock.Anything, "alice@example.com").
+		Return(&models.User{ID: userID, Email: "alice@example.com"}, nil)
+	repo.EXPECT().WithTx(mock.Anything).Return(repo)
+	repo.EXPECT().
+		RunTx(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, fn func(pgx.Tx) error) error {
+			return fn(nil)
+		})
+	repo.EXPECT().InvalidateUserEmailVerificationTokens(mock.Anything, userID).Return(nil)
+	repo.EXPECT().
+		CreateEmailVerificationToken(mock.Anything, mock.MatchedBy(func(tok *models.EmailVerificationToken) bool {
+			return tok.UserID == userID && tok.TokenHash != ""
+		})).
+		Return(nil)
+
+	require.NoError(t, svc.ResendVerification(ctx, "alice@example.com"))
+
+	notifier := svc.notifier.(*stubNotifier)
+	assert.Equal(t, []string{"alice@example.com"}, notifier.sent)
+	assert.Contains(t, notifier.body, "/verify-email?token=")
+}
+
+func TestResendVerification_SkipsUnknownAndVerified(t *testing.T) {
+	svc, repo, _ := newAuthService(t)
+	ctx := context.Background()
+
+	repo.EXPECT().
+		GetUserByEmail(mock.Anything, "unknown@example.com").
+		Return(nil, apperr.ErrUserNotFound)
+	repo.EXPECT().
+		GetUserByEmail(mock.Anything, "done@example.com").
+		Return(&models.User{ID: uuid.New(), Email: "done@example.com", EmailVerified: true}, nil)
+
+	require.NoError(t, svc.ResendVerification(ctx, "unknown@example.com"), "must not reveal unknown emails")
+	require.NoError(t, svc.ResendVerification(ctx, "done@example.com"))
+	assert.Empty(t, svc.notifier.(*stubNotifier).sent)
+}
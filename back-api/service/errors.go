@@ -4,3 +4,15 @@ import "errors"
 
 // ErrTooManyAttempts signals that the caller has been rate-limited.
 var ErrTooManyAttempts = errors.New("too many attempts")
+
+// ErrInvalidTwoFactorCode signals a wrong or expired TOTP code. It is kept
+// apart from apperr.ErrInvalidCredentials so clients can ask for the code
+// again instead of the password.
+var ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+
+// ErrNotRetryable signals that a request is not in a state that allows a retry.
+var ErrNotRetryable = errors.New("only failed GPT requests can be retried")
+
+// ErrNotReanalyzable signals that a request cannot be analyzed again, because
+// it is still in progress or is not a GPT request.
+var ErrNotReanalyzable = errors.New("only completed or failed GPT requests can be reanalyzed")
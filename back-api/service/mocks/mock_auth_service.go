@@ -6,9 +6,10 @@ import (
 	context "context"
 
 	auth "github.com/fedutinova/smartheart/back-api/auth"
-	mock "github.com/stretchr/testify/mock"
-
+	models "github.com/fedutinova/smartheart/back-api/models"
+	service "github.com/fedutinova/smartheart/back-api/service"
 	uuid "github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
 )
 
 // MockAuthService is an autogenerated mock type for the AuthService type
@@ -24,24 +25,83 @@ func (_m *MockAuthService) EXPECT() *MockAuthService_Expecter {
 	return &MockAuthService_Expecter{mock: &_m.Mock}
 }
 
+// EnrollTwoFactor provides a mock function with given fields: ctx, userID
+func (_m *MockAuthService) EnrollTwoFactor(ctx context.Context, userID uuid.UUID) (*auth.TOTPEnrollment, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrollTwoFactor")
+	}
+
+	var r0 *auth.TOTPEnrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*auth.TOTPEnrollment, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *auth.TOTPEnrollment); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.TOTPEnrollment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAuthService_EnrollTwoFactor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnrollTwoFactor'
+type MockAuthService_EnrollTwoFactor_Call struct {
+	*mock.Call
+}
+
+// EnrollTwoFactor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockAuthService_Expecter) EnrollTwoFactor(ctx interface{}, userID interface{}) *MockAuthService_EnrollTwoFactor_Call {
+	return &MockAuthService_EnrollTwoFactor_Call{Call: _e.mock.On("EnrollTwoFactor", ctx, userID)}
+}
+
+func (_c *MockAuthService_EnrollTwoFactor_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockAuthService_EnrollTwoFactor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockAuthService_EnrollTwoFactor_Call) Return(_a0 *auth.TOTPEnrollment, _a1 error) *MockAuthService_EnrollTwoFactor_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAuthService_EnrollTwoFactor_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*auth.TOTPEnrollment, error)) *MockAuthService_EnrollTwoFactor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Login provides a mock function with given fields: ctx, email, password
-func (_m *MockAuthService) Login(ctx context.Context, email string, password string) (*auth.TokenPair, error) {
+func (_m *MockAuthService) Login(ctx context.Context, email string, password string) (*service.LoginResult, error) {
 	ret := _m.Called(ctx, email, password)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
 	}
 
-	var r0 *auth.TokenPair
+	var r0 *service.LoginResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*auth.TokenPair, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*service.LoginResult, error)); ok {
 		return rf(ctx, email, password)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *auth.TokenPair); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *service.LoginResult); ok {
 		r0 = rf(ctx, email, password)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*auth.TokenPair)
+			r0 = ret.Get(0).(*service.LoginResult)
 		}
 	}
 
@@ -74,12 +134,72 @@ func (_c *MockAuthService_Login_Call) Run(run func(ctx context.Context, email st
 	return _c
 }
 
-func (_c *MockAuthService_Login_Call) Return(_a0 *auth.TokenPair, _a1 error) *MockAuthService_Login_Call {
+func (_c *MockAuthService_Login_Call) Return(_a0 *service.LoginResult, _a1 error) *MockAuthService_Login_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockAuthService_Login_Call) RunAndReturn(run func(context.Context, string, string) (*auth.TokenPair, error)) *MockAuthService_Login_Call {
+func (_c *MockAuthService_Login_Call) RunAndReturn(run func(context.Context, string, string) (*service.LoginResult, error)) *MockAuthService_Login_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LoginTwoFactor provides a mock function with given fields: ctx, challengeToken, code
+func (_m *MockAuthService) LoginTwoFactor(ctx context.Context, challengeToken string, code string) (*service.LoginResult, error) {
+	ret := _m.Called(ctx, challengeToken, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoginTwoFactor")
+	}
+
+	var r0 *service.LoginResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*service.LoginResult, error)); ok {
+		return rf(ctx, challengeToken, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *service.LoginResult); ok {
+		r0 = rf(ctx, challengeToken, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.LoginResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, challengeToken, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAuthService_LoginTwoFactor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LoginTwoFactor'
+type MockAuthService_LoginTwoFactor_Call struct {
+	*mock.Call
+}
+
+// LoginTwoFactor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - challengeToken string
+//   - code string
+func (_e *MockAuthService_Expecter) LoginTwoFactor(ctx interface{}, challengeToken interface{}, code interface{}) *MockAuthService_LoginTwoFactor_Call {
+	return &MockAuthService_LoginTwoFactor_Call{Call: _e.mock.On("LoginTwoFactor", ctx, challengeToken, code)}
+}
+
+func (_c *MockAuthService_LoginTwoFactor_Call) Run(run func(ctx context.Context, challengeToken string, code string)) *MockAuthService_LoginTwoFactor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAuthService_LoginTwoFactor_Call) Return(_a0 *service.LoginResult, _a1 error) *MockAuthService_LoginTwoFactor_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAuthService_LoginTwoFactor_Call) RunAndReturn(run func(context.Context, string, string) (*service.LoginResult, error)) *MockAuthService_LoginTwoFactor_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -253,6 +373,208 @@ func (_c *MockAuthService_Register_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// ResendVerification provides a mock function with given fields: ctx, email
+func (_m *MockAuthService) ResendVerification(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResendVerification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAuthService_ResendVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResendVerification'
+type MockAuthService_ResendVerification_Call struct {
+	*mock.Call
+}
+
+// ResendVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *MockAuthService_Expecter) ResendVerification(ctx interface{}, email interface{}) *MockAuthService_ResendVerification_Call {
+	return &MockAuthService_ResendVerification_Call{Call: _e.mock.On("ResendVerification", ctx, email)}
+}
+
+func (_c *MockAuthService_ResendVerification_Call) Run(run func(ctx context.Context, email string)) *MockAuthService_ResendVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockAuthService_ResendVerification_Call) Return(_a0 error) *MockAuthService_ResendVerification_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAuthService_ResendVerification_Call) RunAndReturn(run func(context.Context, string) error) *MockAuthService_ResendVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateProfile provides a mock function with given fields: ctx, userID, upd
+func (_m *MockAuthService) UpdateProfile(ctx context.Context, userID uuid.UUID, upd service.ProfileUpdate) (*models.User, error) {
+	ret := _m.Called(ctx, userID, upd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, service.ProfileUpdate) (*models.User, error)); ok {
+		return rf(ctx, userID, upd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, service.ProfileUpdate) *models.User); ok {
+		r0 = rf(ctx, userID, upd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, service.ProfileUpdate) error); ok {
+		r1 = rf(ctx, userID, upd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAuthService_UpdateProfile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateProfile'
+type MockAuthService_UpdateProfile_Call struct {
+	*mock.Call
+}
+
+// UpdateProfile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - upd service.ProfileUpdate
+func (_e *MockAuthService_Expecter) UpdateProfile(ctx interface{}, userID interface{}, upd interface{}) *MockAuthService_UpdateProfile_Call {
+	return &MockAuthService_UpdateProfile_Call{Call: _e.mock.On("UpdateProfile", ctx, userID, upd)}
+}
+
+func (_c *MockAuthService_UpdateProfile_Call) Run(run func(ctx context.Context, userID uuid.UUID, upd service.ProfileUpdate)) *MockAuthService_UpdateProfile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(service.ProfileUpdate))
+	})
+	return _c
+}
+
+func (_c *MockAuthService_UpdateProfile_Call) Return(_a0 *models.User, _a1 error) *MockAuthService_UpdateProfile_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAuthService_UpdateProfile_Call) RunAndReturn(run func(context.Context, uuid.UUID, service.ProfileUpdate) (*models.User, error)) *MockAuthService_UpdateProfile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyEmail provides a mock function with given fields: ctx, token
+func (_m *MockAuthService) VerifyEmail(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAuthService_VerifyEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyEmail'
+type MockAuthService_VerifyEmail_Call struct {
+	*mock.Call
+}
+
+// VerifyEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *MockAuthService_Expecter) VerifyEmail(ctx interface{}, token interface{}) *MockAuthService_VerifyEmail_Call {
+	return &MockAuthService_VerifyEmail_Call{Call: _e.mock.On("VerifyEmail", ctx, token)}
+}
+
+func (_c *MockAuthService_VerifyEmail_Call) Run(run func(ctx context.Context, token string)) *MockAuthService_VerifyEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockAuthService_VerifyEmail_Call) Return(_a0 error) *MockAuthService_VerifyEmail_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAuthService_VerifyEmail_Call) RunAndReturn(run func(context.Context, string) error) *MockAuthService_VerifyEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyTwoFactor provides a mock function with given fields: ctx, userID, code
+func (_m *MockAuthService) VerifyTwoFactor(ctx context.Context, userID uuid.UUID, code string) error {
+	ret := _m.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyTwoFactor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAuthService_VerifyTwoFactor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyTwoFactor'
+type MockAuthService_VerifyTwoFactor_Call struct {
+	*mock.Call
+}
+
+// VerifyTwoFactor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - code string
+func (_e *MockAuthService_Expecter) VerifyTwoFactor(ctx interface{}, userID interface{}, code interface{}) *MockAuthService_VerifyTwoFactor_Call {
+	return &MockAuthService_VerifyTwoFactor_Call{Call: _e.mock.On("VerifyTwoFactor", ctx, userID, code)}
+}
+
+func (_c *MockAuthService_VerifyTwoFactor_Call) Run(run func(ctx context.Context, userID uuid.UUID, code string)) *MockAuthService_VerifyTwoFactor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAuthService_VerifyTwoFactor_Call) Return(_a0 error) *MockAuthService_VerifyTwoFactor_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAuthService_VerifyTwoFactor_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockAuthService_VerifyTwoFactor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockAuthService creates a new instance of MockAuthService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockAuthService(t interface {
@@ -8,6 +8,7 @@ import (
 	auth "github.com/fedutinova/smartheart/back-api/auth"
 	job "github.com/fedutinova/smartheart/back-api/job"
 	models "github.com/fedutinova/smartheart/back-api/models"
+	repository "github.com/fedutinova/smartheart/back-api/repository"
 	service "github.com/fedutinova/smartheart/back-api/service"
 	uuid "github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
@@ -26,6 +27,174 @@ func (_m *MockRequestService) EXPECT() *MockRequestService_Expecter {
 	return &MockRequestService_Expecter{mock: &_m.Mock}
 }
 
+// DeleteRequest provides a mock function with given fields: ctx, requestID, claims
+func (_m *MockRequestService) DeleteRequest(ctx context.Context, requestID uuid.UUID, claims *auth.Claims) error {
+	ret := _m.Called(ctx, requestID, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *auth.Claims) error); ok {
+		r0 = rf(ctx, requestID, claims)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRequestService_DeleteRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRequest'
+type MockRequestService_DeleteRequest_Call struct {
+	*mock.Call
+}
+
+// DeleteRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - requestID uuid.UUID
+//   - claims *auth.Claims
+func (_e *MockRequestService_Expecter) DeleteRequest(ctx interface{}, requestID interface{}, claims interface{}) *MockRequestService_DeleteRequest_Call {
+	return &MockRequestService_DeleteRequest_Call{Call: _e.mock.On("DeleteRequest", ctx, requestID, claims)}
+}
+
+func (_c *MockRequestService_DeleteRequest_Call) Run(run func(ctx context.Context, requestID uuid.UUID, claims *auth.Claims)) *MockRequestService_DeleteRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(*auth.Claims))
+	})
+	return _c
+}
+
+func (_c *MockRequestService_DeleteRequest_Call) Return(_a0 error) *MockRequestService_DeleteRequest_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRequestService_DeleteRequest_Call) RunAndReturn(run func(context.Context, uuid.UUID, *auth.Claims) error) *MockRequestService_DeleteRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetECGBatch provides a mock function with given fields: ctx, batchID, claims
+func (_m *MockRequestService) GetECGBatch(ctx context.Context, batchID uuid.UUID, claims *auth.Claims) (*models.ECGBatch, error) {
+	ret := _m.Called(ctx, batchID, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetECGBatch")
+	}
+
+	var r0 *models.ECGBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *auth.Claims) (*models.ECGBatch, error)); ok {
+		return rf(ctx, batchID, claims)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *auth.Claims) *models.ECGBatch); ok {
+		r0 = rf(ctx, batchID, claims)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ECGBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *auth.Claims) error); ok {
+		r1 = rf(ctx, batchID, claims)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRequestService_GetECGBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetECGBatch'
+type MockRequestService_GetECGBatch_Call struct {
+	*mock.Call
+}
+
+// GetECGBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batchID uuid.UUID
+//   - claims *auth.Claims
+func (_e *MockRequestService_Expecter) GetECGBatch(ctx interface{}, batchID interface{}, claims interface{}) *MockRequestService_GetECGBatch_Call {
+	return &MockRequestService_GetECGBatch_Call{Call: _e.mock.On("GetECGBatch", ctx, batchID, claims)}
+}
+
+func (_c *MockRequestService_GetECGBatch_Call) Run(run func(ctx context.Context, batchID uuid.UUID, claims *auth.Claims)) *MockRequestService_GetECGBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(*auth.Claims))
+	})
+	return _c
+}
+
+func (_c *MockRequestService_GetECGBatch_Call) Return(_a0 *models.ECGBatch, _a1 error) *MockRequestService_GetECGBatch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRequestService_GetECGBatch_Call) RunAndReturn(run func(context.Context, uuid.UUID, *auth.Claims) (*models.ECGBatch, error)) *MockRequestService_GetECGBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFile provides a mock function with given fields: ctx, fileID, claims
+func (_m *MockRequestService) GetFile(ctx context.Context, fileID uuid.UUID, claims *auth.Claims) (*models.File, error) {
+	ret := _m.Called(ctx, fileID, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFile")
+	}
+
+	var r0 *models.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *auth.Claims) (*models.File, error)); ok {
+		return rf(ctx, fileID, claims)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *auth.Claims) *models.File); ok {
+		r0 = rf(ctx, fileID, claims)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *auth.Claims) error); ok {
+		r1 = rf(ctx, fileID, claims)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRequestService_GetFile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFile'
+type MockRequestService_GetFile_Call struct {
+	*mock.Call
+}
+
+// GetFile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fileID uuid.UUID
+//   - claims *auth.Claims
+func (_e *MockRequestService_Expecter) GetFile(ctx interface{}, fileID interface{}, claims interface{}) *MockRequestService_GetFile_Call {
+	return &MockRequestService_GetFile_Call{Call: _e.mock.On("GetFile", ctx, fileID, claims)}
+}
+
+func (_c *MockRequestService_GetFile_Call) Run(run func(ctx context.Context, fileID uuid.UUID, claims *auth.Claims)) *MockRequestService_GetFile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(*auth.Claims))
+	})
+	return _c
+}
+
+func (_c *MockRequestService_GetFile_Call) Return(_a0 *models.File, _a1 error) *MockRequestService_GetFile_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRequestService_GetFile_Call) RunAndReturn(run func(context.Context, uuid.UUID, *auth.Claims) (*models.File, error)) *MockRequestService_GetFile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetJobStatus provides a mock function with given fields: ctx, jobID, claims
 func (_m *MockRequestService) GetJobStatus(ctx context.Context, jobID uuid.UUID, claims *auth.Claims) (*job.Job, error) {
 	ret := _m.Called(ctx, jobID, claims)
@@ -146,9 +315,68 @@ func (_c *MockRequestService_GetRequest_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
-// GetUserRequests provides a mock function with given fields: ctx, userID, limit, offset
-func (_m *MockRequestService) GetUserRequests(ctx context.Context, userID uuid.UUID, limit int, offset int) (*service.RequestPage, error) {
-	ret := _m.Called(ctx, userID, limit, offset)
+// GetRequestStats provides a mock function with given fields: ctx, userID
+func (_m *MockRequestService) GetRequestStats(ctx context.Context, userID uuid.UUID) (*service.RequestStats, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRequestStats")
+	}
+
+	var r0 *service.RequestStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*service.RequestStats, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *service.RequestStats); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.RequestStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRequestService_GetRequestStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRequestStats'
+type MockRequestService_GetRequestStats_Call struct {
+	*mock.Call
+}
+
+// GetRequestStats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockRequestService_Expecter) GetRequestStats(ctx interface{}, userID interface{}) *MockRequestService_GetRequestStats_Call {
+	return &MockRequestService_GetRequestStats_Call{Call: _e.mock.On("GetRequestStats", ctx, userID)}
+}
+
+func (_c *MockRequestService_GetRequestStats_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockRequestService_GetRequestStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockRequestService_GetRequestStats_Call) Return(_a0 *service.RequestStats, _a1 error) *MockRequestService_GetRequestStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRequestService_GetRequestStats_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*service.RequestStats, error)) *MockRequestService_GetRequestStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserRequests provides a mock function with given fields: ctx, userID, filter
+func (_m *MockRequestService) GetUserRequests(ctx context.Context, userID uuid.UUID, filter repository.RequestFilter) (*service.RequestPage, error) {
+	ret := _m.Called(ctx, userID, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserRequests")
@@ -156,19 +384,19 @@ func (_m *MockRequestService) GetUserRequests(ctx context.Context, userID uuid.U
 
 	var r0 *service.RequestPage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) (*service.RequestPage, error)); ok {
-		return rf(ctx, userID, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, repository.RequestFilter) (*service.RequestPage, error)); ok {
+		return rf(ctx, userID, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) *service.RequestPage); ok {
-		r0 = rf(ctx, userID, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, repository.RequestFilter) *service.RequestPage); ok {
+		r0 = rf(ctx, userID, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*service.RequestPage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, int) error); ok {
-		r1 = rf(ctx, userID, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, repository.RequestFilter) error); ok {
+		r1 = rf(ctx, userID, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -184,15 +412,14 @@ type MockRequestService_GetUserRequests_Call struct {
 // GetUserRequests is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userID uuid.UUID
-//   - limit int
-//   - offset int
-func (_e *MockRequestService_Expecter) GetUserRequests(ctx interface{}, userID interface{}, limit interface{}, offset interface{}) *MockRequestService_GetUserRequests_Call {
-	return &MockRequestService_GetUserRequests_Call{Call: _e.mock.On("GetUserRequests", ctx, userID, limit, offset)}
+//   - filter repository.RequestFilter
+func (_e *MockRequestService_Expecter) GetUserRequests(ctx interface{}, userID interface{}, filter interface{}) *MockRequestService_GetUserRequests_Call {
+	return &MockRequestService_GetUserRequests_Call{Call: _e.mock.On("GetUserRequests", ctx, userID, filter)}
 }
 
-func (_c *MockRequestService_GetUserRequests_Call) Run(run func(ctx context.Context, userID uuid.UUID, limit int, offset int)) *MockRequestService_GetUserRequests_Call {
+func (_c *MockRequestService_GetUserRequests_Call) Run(run func(ctx context.Context, userID uuid.UUID, filter repository.RequestFilter)) *MockRequestService_GetUserRequests_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(int), args[3].(int))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(repository.RequestFilter))
 	})
 	return _c
 }
@@ -202,7 +429,7 @@ func (_c *MockRequestService_GetUserRequests_Call) Return(_a0 *service.RequestPa
 	return _c
 }
 
-func (_c *MockRequestService_GetUserRequests_Call) RunAndReturn(run func(context.Context, uuid.UUID, int, int) (*service.RequestPage, error)) *MockRequestService_GetUserRequests_Call {
+func (_c *MockRequestService_GetUserRequests_Call) RunAndReturn(run func(context.Context, uuid.UUID, repository.RequestFilter) (*service.RequestPage, error)) *MockRequestService_GetUserRequests_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 
+	auth "github.com/fedutinova/smartheart/back-api/auth"
 	service "github.com/fedutinova/smartheart/back-api/service"
 	uuid "github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
@@ -82,6 +83,127 @@ func (_c *MockSubmissionService_CompareH2Redaction_Call) RunAndReturn(run func(c
 	return _c
 }
 
+// ReanalyzeGPT provides a mock function with given fields: ctx, requestID, claims, opts
+func (_m *MockSubmissionService) ReanalyzeGPT(ctx context.Context, requestID uuid.UUID, claims *auth.Claims, opts service.ReanalyzeOptions) (*service.GPTSubmitResult, error) {
+	ret := _m.Called(ctx, requestID, claims, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReanalyzeGPT")
+	}
+
+	var r0 *service.GPTSubmitResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *auth.Claims, service.ReanalyzeOptions) (*service.GPTSubmitResult, error)); ok {
+		return rf(ctx, requestID, claims, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *auth.Claims, service.ReanalyzeOptions) *service.GPTSubmitResult); ok {
+		r0 = rf(ctx, requestID, claims, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.GPTSubmitResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *auth.Claims, service.ReanalyzeOptions) error); ok {
+		r1 = rf(ctx, requestID, claims, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSubmissionService_ReanalyzeGPT_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReanalyzeGPT'
+type MockSubmissionService_ReanalyzeGPT_Call struct {
+	*mock.Call
+}
+
+// ReanalyzeGPT is a helper method to define mock.On call
+//   - ctx context.Context
+//   - requestID uuid.UUID
+//   - claims *auth.Claims
+//   - opts service.ReanalyzeOptions
+func (_e *MockSubmissionService_Expecter) ReanalyzeGPT(ctx interface{}, requestID interface{}, claims interface{}, opts interface{}) *MockSubmissionService_ReanalyzeGPT_Call {
+	return &MockSubmissionService_ReanalyzeGPT_Call{Call: _e.mock.On("ReanalyzeGPT", ctx, requestID, claims, opts)}
+}
+
+func (_c *MockSubmissionService_ReanalyzeGPT_Call) Run(run func(ctx context.Context, requestID uuid.UUID, claims *auth.Claims, opts service.ReanalyzeOptions)) *MockSubmissionService_ReanalyzeGPT_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(*auth.Claims), args[3].(service.ReanalyzeOptions))
+	})
+	return _c
+}
+
+func (_c *MockSubmissionService_ReanalyzeGPT_Call) Return(_a0 *service.GPTSubmitResult, _a1 error) *MockSubmissionService_ReanalyzeGPT_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSubmissionService_ReanalyzeGPT_Call) RunAndReturn(run func(context.Context, uuid.UUID, *auth.Claims, service.ReanalyzeOptions) (*service.GPTSubmitResult, error)) *MockSubmissionService_ReanalyzeGPT_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RetryGPT provides a mock function with given fields: ctx, requestID, claims
+func (_m *MockSubmissionService) RetryGPT(ctx context.Context, requestID uuid.UUID, claims *auth.Claims) (*service.GPTSubmitResult, error) {
+	ret := _m.Called(ctx, requestID, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetryGPT")
+	}
+
+	var r0 *service.GPTSubmitResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *auth.Claims) (*service.GPTSubmitResult, error)); ok {
+		return rf(ctx, requestID, claims)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *auth.Claims) *service.GPTSubmitResult); ok {
+		r0 = rf(ctx, requestID, claims)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.GPTSubmitResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *auth.Claims) error); ok {
+		r1 = rf(ctx, requestID, claims)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSubmissionService_RetryGPT_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RetryGPT'
+type MockSubmissionService_RetryGPT_Call struct {
+	*mock.Call
+}
+
+// RetryGPT is a helper method to define mock.On call
+//   - ctx context.Context
+//   - requestID uuid.UUID
+//   - claims *auth.Claims
+func (_e *MockSubmissionService_Expecter) RetryGPT(ctx interface{}, requestID interface{}, claims interface{}) *MockSubmissionService_RetryGPT_Call {
+	return &MockSubmissionService_RetryGPT_Call{Call: _e.mock.On("RetryGPT", ctx, requestID, claims)}
+}
+
+func (_c *MockSubmissionService_RetryGPT_Call) Run(run func(ctx context.Context, requestID uuid.UUID, claims *auth.Claims)) *MockSubmissionService_RetryGPT_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(*auth.Claims))
+	})
+	return _c
+}
+
+func (_c *MockSubmissionService_RetryGPT_Call) Return(_a0 *service.GPTSubmitResult, _a1 error) *MockSubmissionService_RetryGPT_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSubmissionService_RetryGPT_Call) RunAndReturn(run func(context.Context, uuid.UUID, *auth.Claims) (*service.GPTSubmitResult, error)) *MockSubmissionService_RetryGPT_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SubmitECG provides a mock function with given fields: ctx, userID, imageURL, params
 func (_m *MockSubmissionService) SubmitECG(ctx context.Context, userID uuid.UUID, imageURL string, params service.ECGParams) (*service.SubmittedJob, error) {
 	ret := _m.Called(ctx, userID, imageURL, params)
@@ -143,6 +265,67 @@ func (_c *MockSubmissionService_SubmitECG_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// SubmitECGBatch provides a mock function with given fields: ctx, userID, items, params
+func (_m *MockSubmissionService) SubmitECGBatch(ctx context.Context, userID uuid.UUID, items []service.ECGBatchItem, params service.ECGParams) (*service.ECGBatchResult, error) {
+	ret := _m.Called(ctx, userID, items, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubmitECGBatch")
+	}
+
+	var r0 *service.ECGBatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []service.ECGBatchItem, service.ECGParams) (*service.ECGBatchResult, error)); ok {
+		return rf(ctx, userID, items, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []service.ECGBatchItem, service.ECGParams) *service.ECGBatchResult); ok {
+		r0 = rf(ctx, userID, items, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ECGBatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, []service.ECGBatchItem, service.ECGParams) error); ok {
+		r1 = rf(ctx, userID, items, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSubmissionService_SubmitECGBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SubmitECGBatch'
+type MockSubmissionService_SubmitECGBatch_Call struct {
+	*mock.Call
+}
+
+// SubmitECGBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - items []service.ECGBatchItem
+//   - params service.ECGParams
+func (_e *MockSubmissionService_Expecter) SubmitECGBatch(ctx interface{}, userID interface{}, items interface{}, params interface{}) *MockSubmissionService_SubmitECGBatch_Call {
+	return &MockSubmissionService_SubmitECGBatch_Call{Call: _e.mock.On("SubmitECGBatch", ctx, userID, items, params)}
+}
+
+func (_c *MockSubmissionService_SubmitECGBatch_Call) Run(run func(ctx context.Context, userID uuid.UUID, items []service.ECGBatchItem, params service.ECGParams)) *MockSubmissionService_SubmitECGBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].([]service.ECGBatchItem), args[3].(service.ECGParams))
+	})
+	return _c
+}
+
+func (_c *MockSubmissionService_SubmitECGBatch_Call) Return(_a0 *service.ECGBatchResult, _a1 error) *MockSubmissionService_SubmitECGBatch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSubmissionService_SubmitECGBatch_Call) RunAndReturn(run func(context.Context, uuid.UUID, []service.ECGBatchItem, service.ECGParams) (*service.ECGBatchResult, error)) *MockSubmissionService_SubmitECGBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SubmitECGFile provides a mock function with given fields: ctx, userID, file, params
 func (_m *MockSubmissionService) SubmitECGFile(ctx context.Context, userID uuid.UUID, file service.UploadedFile, params service.ECGParams) (*service.SubmittedJob, error) {
 	ret := _m.Called(ctx, userID, file, params)
@@ -204,9 +387,9 @@ func (_c *MockSubmissionService_SubmitECGFile_Call) RunAndReturn(run func(contex
 	return _c
 }
 
-// SubmitGPT provides a mock function with given fields: ctx, userID, textQuery, files
-func (_m *MockSubmissionService) SubmitGPT(ctx context.Context, userID uuid.UUID, textQuery string, files []service.UploadedFile) (*service.GPTSubmitResult, error) {
-	ret := _m.Called(ctx, userID, textQuery, files)
+// SubmitGPT provides a mock function with given fields: ctx, userID, textQuery, files, opts
+func (_m *MockSubmissionService) SubmitGPT(ctx context.Context, userID uuid.UUID, textQuery string, files []service.UploadedFile, opts service.GPTOptions) (*service.GPTSubmitResult, error) {
+	ret := _m.Called(ctx, userID, textQuery, files, opts)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SubmitGPT")
@@ -214,19 +397,19 @@ func (_m *MockSubmissionService) SubmitGPT(ctx context.Context, userID uuid.UUID
 
 	var r0 *service.GPTSubmitResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, []service.UploadedFile) (*service.GPTSubmitResult, error)); ok {
-		return rf(ctx, userID, textQuery, files)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, []service.UploadedFile, service.GPTOptions) (*service.GPTSubmitResult, error)); ok {
+		return rf(ctx, userID, textQuery, files, opts)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, []service.UploadedFile) *service.GPTSubmitResult); ok {
-		r0 = rf(ctx, userID, textQuery, files)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, []service.UploadedFile, service.GPTOptions) *service.GPTSubmitResult); ok {
+		r0 = rf(ctx, userID, textQuery, files, opts)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*service.GPTSubmitResult)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, []service.UploadedFile) error); ok {
-		r1 = rf(ctx, userID, textQuery, files)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, []service.UploadedFile, service.GPTOptions) error); ok {
+		r1 = rf(ctx, userID, textQuery, files, opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -244,13 +427,14 @@ type MockSubmissionService_SubmitGPT_Call struct {
 //   - userID uuid.UUID
 //   - textQuery string
 //   - files []service.UploadedFile
-func (_e *MockSubmissionService_Expecter) SubmitGPT(ctx interface{}, userID interface{}, textQuery interface{}, files interface{}) *MockSubmissionService_SubmitGPT_Call {
-	return &MockSubmissionService_SubmitGPT_Call{Call: _e.mock.On("SubmitGPT", ctx, userID, textQuery, files)}
+//   - opts service.GPTOptions
+func (_e *MockSubmissionService_Expecter) SubmitGPT(ctx interface{}, userID interface{}, textQuery interface{}, files interface{}, opts interface{}) *MockSubmissionService_SubmitGPT_Call {
+	return &MockSubmissionService_SubmitGPT_Call{Call: _e.mock.On("SubmitGPT", ctx, userID, textQuery, files, opts)}
 }
 
-func (_c *MockSubmissionService_SubmitGPT_Call) Run(run func(ctx context.Context, userID uuid.UUID, textQuery string, files []service.UploadedFile)) *MockSubmissionService_SubmitGPT_Call {
+func (_c *MockSubmissionService_SubmitGPT_Call) Run(run func(ctx context.Context, userID uuid.UUID, textQuery string, files []service.UploadedFile, opts service.GPTOptions)) *MockSubmissionService_SubmitGPT_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string), args[3].([]service.UploadedFile))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string), args[3].([]service.UploadedFile), args[4].(service.GPTOptions))
 	})
 	return _c
 }
@@ -260,7 +444,7 @@ func (_c *MockSubmissionService_SubmitGPT_Call) Return(_a0 *service.GPTSubmitRes
 	return _c
 }
 
-func (_c *MockSubmissionService_SubmitGPT_Call) RunAndReturn(run func(context.Context, uuid.UUID, string, []service.UploadedFile) (*service.GPTSubmitResult, error)) *MockSubmissionService_SubmitGPT_Call {
+func (_c *MockSubmissionService_SubmitGPT_Call) RunAndReturn(run func(context.Context, uuid.UUID, string, []service.UploadedFile, service.GPTOptions) (*service.GPTSubmitResult, error)) *MockSubmissionService_SubmitGPT_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/fedutinova/smartheart/back-api/repository"
+	"github.com/fedutinova/smartheart/back-api/storage"
+)
+
+const (
+	// orphanSweepPrefix covers every key generated by the storage backends.
+	// Thumbnails under thumbnailPrefix are swept as well.
+	orphanSweepPrefix = "uploads/"
+	// orphanKeyBatch bounds the number of keys checked per database query.
+	orphanKeyBatch = 500
+)
+
+// StartOrphanFileSweeper launches a background goroutine that periodically
+// deletes stored objects with no files row that are older than grace. Such
+// objects are left behind when a submission fails after uploading. It stops
+// when ctx is canceled; a non-positive interval disables the sweeper.
+func StartOrphanFileSweeper(ctx context.Context, repo repository.RequestRepo, store storage.Storage, interval, grace time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				deleted, err := SweepOrphanFiles(ctx, repo, store, grace)
+				if err != nil {
+					slog.WarnContext(ctx, "Orphan file sweep failed", "deleted", deleted, "error", err)
+				} else if deleted > 0 {
+					slog.InfoContext(ctx, "Deleted orphaned files", "count", deleted)
+				}
+			}
+		}
+	}()
+}
+
+// SweepOrphanFiles runs a single sweep and returns the number of objects
+// deleted. Objects modified within grace are skipped so uploads whose files
+// row has not been written yet are left alone.
+func SweepOrphanFiles(ctx context.Context, repo repository.RequestRepo, store storage.Storage, grace time.Duration) (int, error) {
+	var objects []storage.ObjectInfo
+	for _, prefix := range []string{orphanSweepPrefix, thumbnailPrefix} {
+		listed, err := store.List(ctx, prefix)
+		if err != nil {
+			return 0, fmt.Errorf("list storage: %w", err)
+		}
+		objects = append(objects, listed...)
+	}
+
+	cutoff := time.Now().Add(-grace)
+	var candidates []string
+	for _, obj := range objects {
+		if obj.LastModified.Before(cutoff) {
+			candidates = append(candidates, obj.Key)
+		}
+	}
+
+	deleted := 0
+	for start := 0; start < len(candidates); start += orphanKeyBatch {
+		batch := candidates[start:min(start+orphanKeyBatch, len(candidates))]
+		// Legacy rows may store keys without the uploads/ prefix (see
+		// LocalStorage.resolveExistingPath), so look up both forms.
+		lookup := make([]string, 0, 2*len(batch))
+		for _, key := range batch {
+			lookup = append(lookup, key, strings.TrimPrefix(key, orphanSweepPrefix))
+		}
+		existing, err := repo.ExistingFileKeys(ctx, lookup)
+		if err != nil {
+			return deleted, fmt.Errorf("check file keys: %w", err)
+		}
+		for _, key := range batch {
+			if _, ok := existing[key]; ok {
+				continue
+			}
+			if _, ok := existing[strings.TrimPrefix(key, orphanSweepPrefix)]; ok {
+				continue
+			}
+			if err := store.DeleteFile(ctx, key); err != nil {
+				slog.WarnContext(ctx, "Failed to delete orphaned file", "key", key, "error", err)
+				continue
+			}
+			deleted++
+		}
+	}
+	return deleted, nil
+}
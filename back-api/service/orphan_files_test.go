@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	repomocks "github.com/fedutinova/smartheart/back-api/repository/mocks"
+	"github.com/fedutinova/smartheart/back-api/storage"
+	storagemocks "github.com/fedutinova/smartheart/back-api/storage/mocks"
+)
+
+func TestSweepOrphanFiles_DeletesOnlyOldUnreferencedKeys(t *testing.T) {
+	repo := repomocks.NewMockRequestRepo(t)
+	store := storagemocks.NewMockStorage(t)
+	old := time.Now().Add(-48 * time.Hour)
+
+	store.EXPECT().List(mock.Anything, "uploads/").Return([]storage.ObjectInfo{
+		{Key: "uploads/2026/01/01/referenced.jpg", LastModified: old},
+		{Key: "uploads/2026/01/01/legacy.jpg", LastModified: old},
+		{Key: "uploads/2026/01/01/orphan.jpg", LastModified: old},
+		{Key: "uploads/2026/01/01/broken.jpg", LastModified: old},
+		{Key: "uploads/2026/01/01/fresh.jpg", LastModified: time.Now()},
+	}, nil)
+	store.EXPECT().List(mock.Anything, "thumbnails/").Return(nil, nil)
+	repo.EXPECT().ExistingFileKeys(mock.Anything, mock.MatchedBy(func(keys []string) bool {
+		return len(keys) == 8 // four candidates, each with and without the prefix
+	})).Return(map[string]struct{}{
+		"uploads/2026/01/01/referenced.jpg": {},
+		"2026/01/01/legacy.jpg":             {},
+	}, nil)
+	store.EXPECT().DeleteFile(mock.Anything, "uploads/2026/01/01/orphan.jpg").Return(nil)
+	store.EXPECT().DeleteFile(mock.Anything, "uploads/2026/01/01/broken.jpg").Return(errors.New("access denied"))
+
+	deleted, err := SweepOrphanFiles(context.Background(), repo, store, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+}
+
+func TestSweepOrphanFiles_ListError(t *testing.T) {
+	repo := repomocks.NewMockRequestRepo(t)
+	store := storagemocks.NewMockStorage(t)
+
+	store.EXPECT().List(mock.Anything, mock.Anything).Return(nil, errors.New("boom"))
+
+	_, err := SweepOrphanFiles(context.Background(), repo, store, time.Hour)
+	require.Error(t, err)
+}
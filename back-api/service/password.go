@@ -34,15 +34,15 @@ type PasswordService interface {
 type passwordService struct {
 	repo        repository.Store
 	sessions    auth.SessionService
-	mailer      *mail.Sender
+	notifier    mail.Notifier
 	frontendURL string
 }
 
-func NewPasswordService(repo repository.Store, sessions auth.SessionService, mailer *mail.Sender, cfg config.Config) PasswordService {
+func NewPasswordService(repo repository.Store, sessions auth.SessionService, notifier mail.Notifier, cfg config.Config) PasswordService {
 	return &passwordService{
 		repo:        repo,
 		sessions:    sessions,
-		mailer:      mailer,
+		notifier:    notifier,
 		frontendURL: cfg.FrontendURL,
 	}
 }
@@ -85,7 +85,7 @@ func (s *passwordService) RequestReset(ctx context.Context, email string) error
 	resetLink := fmt.Sprintf("%s/reset-password?token=%s", s.frontendURL, rawToken)
 	body := mail.PasswordResetEmail(resetLink)
 
-	if err := s.mailer.Send(email, "Умное сердце — Сброс пароля", body); err != nil {
+	if err := s.notifier.SendEmail(ctx, email, "Умное сердце — Сброс пароля", body); err != nil {
 		slog.ErrorContext(ctx, "Failed to send password reset email", "email", email, "error", err)
 		return nil
 	}
@@ -15,7 +15,6 @@ import (
 	"github.com/fedutinova/smartheart/back-api/auth"
 	authmocks "github.com/fedutinova/smartheart/back-api/auth/mocks"
 	"github.com/fedutinova/smartheart/back-api/config"
-	"github.com/fedutinova/smartheart/back-api/mail"
 	"github.com/fedutinova/smartheart/back-api/models"
 	repomocks "github.com/fedutinova/smartheart/back-api/repository/mocks"
 )
@@ -23,9 +22,8 @@ import (
 func newPasswordService(t *testing.T) (*passwordService, *repomocks.MockStore, *authmocks.MockSessionService) {
 	repo := repomocks.NewMockStore(t)
 	sessions := authmocks.NewMockSessionService(t)
-	mailer := mail.NewSender(config.SMTPConfig{})
 	cfg := config.Config{FrontendURL: "http://localhost:3000"}
-	svc := NewPasswordService(repo, sessions, mailer, cfg).(*passwordService)
+	svc := NewPasswordService(repo, sessions, &stubNotifier{}, cfg).(*passwordService)
 	return svc, repo, sessions
 }
 
@@ -52,6 +50,23 @@ func TestRequestReset_UnknownEmail_ReturnsNil(t *testing.T) {
 	require.NoError(t, err, "must return nil to prevent email enumeration")
 }
 
+func TestRequestReset_SendsResetLink(t *testing.T) {
+	svc, repo, _ := newPasswordService(t)
+	userID := uuid.New()
+
+	repo.EXPECT().
+		GetUserByEmail(mock.Anything, "alice@example.com").
+		Return(&models.User{ID: userID, Email: "alice@example.com"}, nil)
+	repo.EXPECT().InvalidateUserPasswordResetTokens(mock.Anything, userID).Return(nil)
+	repo.EXPECT().CreatePasswordResetToken(mock.Anything, mock.Anything).Return(nil)
+
+	require.NoError(t, svc.RequestReset(context.Background(), "alice@example.com"))
+
+	notifier := svc.notifier.(*stubNotifier)
+	assert.Equal(t, []string{"alice@example.com"}, notifier.sent)
+	assert.Contains(t, notifier.body, "http://localhost:3000/reset-password?token=")
+}
+
 // ---------------------------------------------------------------------------
 // ConfirmReset
 // ---------------------------------------------------------------------------
@@ -215,8 +215,9 @@ func TestCheckQuota_ActiveSubscription_Unlimited(t *testing.T) {
 
 	repo.EXPECT().GetSubscriptionExpiresAt(mock.Anything, userID).Return(&expires, nil)
 
-	err := svc.checkQuota(ctx, userID)
+	charged, err := svc.checkQuota(ctx, userID)
 	require.NoError(t, err)
+	assert.False(t, charged)
 }
 
 func TestCheckQuota_ExpiredSubscription_FallsToFreeQuota(t *testing.T) {
@@ -229,8 +230,9 @@ func TestCheckQuota_ExpiredSubscription_FallsToFreeQuota(t *testing.T) {
 	repo.EXPECT().GetSubscriptionExpiresAt(mock.Anything, userID).Return(&expired, nil)
 	repo.EXPECT().IncrementFreeAnalysesUsed(mock.Anything, userID).Return(1, nil)
 
-	err := svc.checkQuota(ctx, userID)
+	charged, err := svc.checkQuota(ctx, userID)
 	require.NoError(t, err)
+	assert.True(t, charged)
 }
 
 func TestCheckQuota_NoSubscription_QuotaExceeded_NoPaid(t *testing.T) {
@@ -243,7 +245,7 @@ func TestCheckQuota_NoSubscription_QuotaExceeded_NoPaid(t *testing.T) {
 	repo.EXPECT().IncrementFreeAnalysesUsed(mock.Anything, userID).Return(4, nil)
 	repo.EXPECT().DecrementFreeAnalysesUsed(mock.Anything, userID).Return(nil)
 
-	err := svc.checkQuota(ctx, userID)
+	_, err := svc.checkQuota(ctx, userID)
 	require.Error(t, err)
 	assert.ErrorIs(t, err, apperr.ErrPaymentRequired)
 }
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	netmail "net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+// ProfileUpdate holds the profile fields to change; nil fields are kept.
+type ProfileUpdate struct {
+	Username *string
+	Email    *string
+}
+
+// UpdateProfile changes the user's username and/or email. A new email is
+// marked unverified and sent a verification link unless re-verification is
+// disabled. Links mailed before the change stop working either way, so they
+// cannot verify the new address.
+func (s *authService) UpdateProfile(ctx context.Context, userID uuid.UUID, upd ProfileUpdate) (*models.User, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if apperr.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, apperr.WrapInternal("get user", err)
+	}
+
+	if upd.Username != nil {
+		username := strings.TrimSpace(*upd.Username)
+		if username == "" || len(username) > maxUsernameLen {
+			return nil, fmt.Errorf("username must be 1 to %d characters: %w", maxUsernameLen, apperr.ErrValidation)
+		}
+		user.Username = username
+	}
+
+	emailChanged := false
+	if upd.Email != nil && *upd.Email != user.Email {
+		if _, err := netmail.ParseAddress(*upd.Email); err != nil {
+			return nil, fmt.Errorf("invalid email format: %w", apperr.ErrValidation)
+		}
+		user.Email = *upd.Email
+		emailChanged = true
+	}
+
+	var rawToken string
+	reverify := emailChanged && s.reverifyOnChange
+	if reverify {
+		user.EmailVerified = false
+		if rawToken, err = generateToken(); err != nil {
+			return nil, apperr.WrapInternal("generate verification token", err)
+		}
+	}
+
+	if err := s.repo.RunTx(ctx, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		if err := txRepo.UpdateUser(ctx, user); err != nil {
+			return err
+		}
+		if emailChanged {
+			if err := txRepo.InvalidateUserEmailVerificationTokens(ctx, user.ID); err != nil {
+				return err
+			}
+		}
+		if !reverify {
+			return nil
+		}
+		return txRepo.CreateEmailVerificationToken(ctx, &models.EmailVerificationToken{
+			UserID:    user.ID,
+			TokenHash: auth.HashToken(rawToken),
+			ExpiresAt: time.Now().Add(verificationTokenTTL),
+		})
+	}); err != nil {
+		if apperr.IsConflict(err) || apperr.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, apperr.WrapInternal("update profile", err)
+	}
+
+	if reverify {
+		s.sendVerificationEmail(ctx, user.Email, rawToken)
+	}
+	return user, nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+func TestUpdateProfile_EmailChangeRequiresReverification(t *testing.T) {
+	svc, repo, _ := newAuthService(t)
+	svc.reverifyOnChange = true
+	userID := uuid.New()
+	newEmail := "new@example.com"
+
+	repo.EXPECT().GetUserByID(mock.Anything, userID).
+		Return(&models.User{ID: userID, Username: "alice", Email: "old@example.com", EmailVerified: true}, nil)
+	expectTx(repo)
+	repo.EXPECT().
+		UpdateUser(mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+			return u.Email == newEmail && !u.EmailVerified
+		})).
+		Return(nil)
+	// Links sent to the old address must die before the new one is issued.
+	invalidated := false
+	repo.EXPECT().InvalidateUserEmailVerificationTokens(mock.Anything, userID).
+		RunAndReturn(func(context.Context, uuid.UUID) error {
+			invalidated = true
+			return nil
+		})
+	repo.EXPECT().CreateEmailVerificationToken(mock.Anything, mock.Anything).
+		RunAndReturn(func(context.Context, *models.EmailVerificationToken) error {
+			assert.True(t, invalidated, "old tokens must be invalidated first")
+			return nil
+		})
+
+	user, err := svc.UpdateProfile(context.Background(), userID, ProfileUpdate{Email: &newEmail})
+	require.NoError(t, err)
+	assert.False(t, user.EmailVerified)
+	assert.Equal(t, []string{newEmail}, svc.notifier.(*stubNotifier).sent)
+}
+
+func TestUpdateProfile_UsernameOnlyKeepsVerification(t *testing.T) {
+	svc, repo, _ := newAuthService(t)
+	svc.reverifyOnChange = true
+	userID := uuid.New()
+	username := "bob"
+
+	repo.EXPECT().GetUserByID(mock.Anything, userID).
+		Return(&models.User{ID: userID, Username: "alice", Email: "a@example.com", EmailVerified: true}, nil)
+	expectTx(repo)
+	repo.EXPECT().UpdateUser(mock.Anything, mock.Anything).Return(nil)
+
+	user, err := svc.UpdateProfile(context.Background(), userID, ProfileUpdate{Username: &username})
+	require.NoError(t, err)
+	assert.Equal(t, "bob", user.Username)
+	assert.True(t, user.EmailVerified)
+	assert.Empty(t, svc.notifier.(*stubNotifier).sent)
+}
+
+func TestUpdateProfile_EmailChangeWithoutReverificationInvalidatesTokens(t *testing.T) {
+	svc, repo, _ := newAuthService(t)
+	userID := uuid.New()
+	newEmail := "new@example.com"
+
+	repo.EXPECT().GetUserByID(mock.Anything, userID).
+		Return(&models.User{ID: userID, Username: "alice", Email: "old@example.com"}, nil)
+	expectTx(repo)
+	repo.EXPECT().UpdateUser(mock.Anything, mock.Anything).Return(nil)
+	repo.EXPECT().InvalidateUserEmailVerificationTokens(mock.Anything, userID).Return(nil)
+
+	_, err := svc.UpdateProfile(context.Background(), userID, ProfileUpdate{Email: &newEmail})
+	require.NoError(t, err)
+	assert.Empty(t, svc.notifier.(*stubNotifier).sent)
+}
+
+func TestUpdateProfile_TakenEmailIsConflict(t *testing.T) {
+	svc, repo, _ := newAuthService(t)
+	userID := uuid.New()
+	email := "taken@example.com"
+
+	repo.EXPECT().GetUserByID(mock.Anything, userID).
+		Return(&models.User{ID: userID, Username: "alice", Email: "a@example.com"}, nil)
+	expectTx(repo)
+	repo.EXPECT().UpdateUser(mock.Anything, mock.Anything).Return(apperr.ErrConflict)
+
+	_, err := svc.UpdateProfile(context.Background(), userID, ProfileUpdate{Email: &email})
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+}
+
+func TestUpdateProfile_InvalidEmail(t *testing.T) {
+	svc, repo, _ := newAuthService(t)
+	userID := uuid.New()
+	email := "not-an-email"
+
+	repo.EXPECT().GetUserByID(mock.Anything, userID).
+		Return(&models.User{ID: userID, Email: "a@example.com"}, nil)
+
+	_, err := svc.UpdateProfile(context.Background(), userID, ProfileUpdate{Email: &email})
+	assert.ErrorIs(t, err, apperr.ErrValidation)
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/fedutinova/smartheart/back-api/repository"
+)
+
+// StartRefreshTokenCleaner launches a background goroutine that periodically
+// deletes expired refresh tokens and those revoked longer than retention ago.
+// Revoked tokens are kept for retention so reuse of a rotated token is still
+// detected. It stops when ctx is canceled; a non-positive interval disables
+// the cleaner.
+func StartRefreshTokenCleaner(ctx context.Context, repo repository.TokenRepo, interval, retention time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				deleted, err := repo.DeleteExpiredRefreshTokens(ctx, time.Now().Add(-retention))
+				if err != nil {
+					slog.WarnContext(ctx, "Refresh token cleanup failed", "deleted", deleted, "error", err)
+				} else {
+					slog.InfoContext(ctx, "Purged expired refresh tokens", "count", deleted)
+				}
+			}
+		}
+	}()
+}
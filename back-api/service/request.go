@@ -12,6 +12,7 @@ import (
 	"github.com/fedutinova/smartheart/back-api/job"
 	"github.com/fedutinova/smartheart/back-api/models"
 	"github.com/fedutinova/smartheart/back-api/repository"
+	"github.com/fedutinova/smartheart/back-api/storage"
 )
 
 // RequestPage is a paginated list of requests.
@@ -22,34 +23,47 @@ type RequestPage struct {
 	Offset int
 }
 
+// RequestStats summarizes a user's requests.
+type RequestStats struct {
+	Total    int
+	ByStatus map[string]int // every known status, including those with 0
+}
+
 // RequestService handles request retrieval and enrichment.
 type RequestService interface {
-	GetUserRequests(ctx context.Context, userID uuid.UUID, limit, offset int) (*RequestPage, error)
+	GetUserRequests(ctx context.Context, userID uuid.UUID, filter repository.RequestFilter) (*RequestPage, error)
+	GetRequestStats(ctx context.Context, userID uuid.UUID) (*RequestStats, error)
 	GetRequest(ctx context.Context, requestID uuid.UUID, claims *auth.Claims) (*models.Request, error)
 	GetJobStatus(ctx context.Context, jobID uuid.UUID, claims *auth.Claims) (*job.Job, error)
+	GetFile(ctx context.Context, fileID uuid.UUID, claims *auth.Claims) (*models.File, error)
+	DeleteRequest(ctx context.Context, requestID uuid.UUID, claims *auth.Claims) error
+	GetECGBatch(ctx context.Context, batchID uuid.UUID, claims *auth.Claims) (*models.ECGBatch, error)
 }
 
 type requestService struct {
-	repo  repository.Store
-	queue job.Queue
+	repo    repository.Store
+	queue   job.Queue
+	storage storage.Storage
 }
 
-func NewRequestService(repo repository.Store, queue job.Queue) RequestService {
-	return &requestService{repo: repo, queue: queue}
+func NewRequestService(repo repository.Store, queue job.Queue, storage storage.Storage) RequestService {
+	return &requestService{repo: repo, queue: queue, storage: storage}
 }
 
-func (s *requestService) GetUserRequests(ctx context.Context, userID uuid.UUID, limit, offset int) (*RequestPage, error) {
-	if limit <= 0 || limit > 200 {
-		limit = 50
+// GetUserRequests returns a page of userID's requests matching filter;
+// filter.UserID is ignored.
+func (s *requestService) GetUserRequests(ctx context.Context, userID uuid.UUID, filter repository.RequestFilter) (*RequestPage, error) {
+	if filter.Limit <= 0 || filter.Limit > 200 {
+		filter.Limit = 50
 	}
-	offset = max(offset, 0)
+	filter.Offset = max(filter.Offset, 0)
 
-	requests, err := s.repo.GetRequestsByUserID(ctx, userID, limit, offset)
+	requests, err := s.repo.GetRequestsByUserID(ctx, userID, filter)
 	if err != nil {
 		return nil, apperr.WrapInternal("get user requests", err)
 	}
 
-	total, err := s.repo.CountRequestsByUserID(ctx, userID)
+	total, err := s.repo.CountRequestsByUserID(ctx, userID, filter)
 	if err != nil {
 		return nil, apperr.WrapInternal("count user requests", err)
 	}
@@ -61,11 +75,24 @@ func (s *requestService) GetUserRequests(ctx context.Context, userID uuid.UUID,
 	return &RequestPage{
 		Data:   requests,
 		Total:  total,
-		Limit:  limit,
-		Offset: offset,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
 	}, nil
 }
 
+func (s *requestService) GetRequestStats(ctx context.Context, userID uuid.UUID) (*RequestStats, error) {
+	counts, err := s.repo.CountRequestsByStatus(ctx, userID)
+	if err != nil {
+		return nil, apperr.WrapInternal("count user requests by status", err)
+	}
+
+	stats := &RequestStats{ByStatus: counts}
+	for _, n := range counts {
+		stats.Total += n
+	}
+	return stats, nil
+}
+
 func (s *requestService) GetRequest(ctx context.Context, requestID uuid.UUID, claims *auth.Claims) (*models.Request, error) {
 	request, err := s.repo.GetRequestByID(ctx, requestID)
 	if err != nil {
@@ -81,71 +108,188 @@ func (s *requestService) GetRequest(ctx context.Context, requestID uuid.UUID, cl
 
 	// Enrich old EKG responses with GPT interpretation (not needed for structured)
 	if request.Response != nil && request.Response.Model == models.ECGModelDirect {
-		enrichECGResponse(ctx, s.repo, request, claims)
+		request.Response = enrichECGResponse(ctx, s.repo, request, claims)
 	}
 
 	return request, nil
 }
 
+// GetECGBatch returns a batch with its child requests and aggregated status,
+// after checking that the caller owns it (or has admin access).
+func (s *requestService) GetECGBatch(ctx context.Context, batchID uuid.UUID, claims *auth.Claims) (*models.ECGBatch, error) {
+	batch, err := s.repo.GetECGBatch(ctx, batchID)
+	if err != nil {
+		if apperr.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, apperr.WrapInternal("get batch", err)
+	}
+
+	if !auth.CanAccessResource(claims, batch.UserID) {
+		return nil, apperr.ErrForbidden
+	}
+
+	batch.Summarize()
+	return batch, nil
+}
+
 func (s *requestService) GetJobStatus(ctx context.Context, jobID uuid.UUID, claims *auth.Claims) (*job.Job, error) {
 	j, ok := s.queue.Status(ctx, jobID)
 	if !ok {
 		return nil, apperr.ErrJobNotFound
 	}
 
-	var payload struct {
-		UserID uuid.UUID `json:"user_id"`
-	}
-	if err := json.Unmarshal(j.Payload, &payload); err != nil {
-		return nil, apperr.ErrForbidden
+	ownerID := j.UserID
+	if ownerID == uuid.Nil {
+		// Jobs enqueued before Job.UserID existed: fall back to the payload.
+		var payload struct {
+			UserID uuid.UUID `json:"user_id"`
+		}
+		if err := json.Unmarshal(j.Payload, &payload); err != nil {
+			return nil, apperr.ErrForbidden
+		}
+		ownerID = payload.UserID
 	}
-	if !auth.CanAccessResource(claims, payload.UserID) {
+	if !auth.CanAccessResource(claims, ownerID) {
 		return nil, apperr.ErrForbidden
 	}
 
 	return j, nil
 }
 
-// enrichECGResponse adds GPT interpretation to an EKG response.
-// Moved from handler/enrich.go to the service layer.
-func enrichECGResponse(ctx context.Context, repo repository.RequestRepo, request *models.Request, claims *auth.Claims) {
-	ekg, err := models.ParseECGContent(request.Response.Content)
+// GetFile returns a stored file's metadata after checking that the caller
+// owns the parent request (or has admin access).
+func (s *requestService) GetFile(ctx context.Context, fileID uuid.UUID, claims *auth.Claims) (*models.File, error) {
+	file, ownerID, err := s.repo.GetFileByID(ctx, fileID)
 	if err != nil {
-		slog.DebugContext(ctx, "Failed to parse EKG content for enrichment", "request_id", request.ID, "error", err)
-		return
+		if apperr.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, apperr.WrapInternal("get file", err)
+	}
+
+	if !auth.CanAccessResource(claims, ownerID) {
+		return nil, apperr.ErrForbidden
 	}
-	if ekg == nil || ekg.GPTRequestID == "" {
-		return
+	if file.S3Key == "" {
+		return nil, apperr.ErrFileNotFound
 	}
 
-	gptRequestID, err := uuid.Parse(ekg.GPTRequestID)
+	return file, nil
+}
+
+// DeleteRequest soft-deletes a request owned by the caller (or any request for
+// admins) and removes its stored files. File removal is best-effort: the
+// request is already hidden, so storage failures are only logged.
+func (s *requestService) DeleteRequest(ctx context.Context, requestID uuid.UUID, claims *auth.Claims) error {
+	request, err := s.repo.GetRequestByID(ctx, requestID)
 	if err != nil {
-		slog.WarnContext(ctx, "Invalid GPT request ID in EKG content", "request_id", request.ID, "gpt_request_id", ekg.GPTRequestID, "error", err)
-		return
+		if apperr.IsNotFound(err) {
+			return err
+		}
+		return apperr.WrapInternal("get request", err)
 	}
 
-	gptRequest, err := repo.GetRequestByID(ctx, gptRequestID)
+	if !auth.CanAccessResource(claims, request.UserID) {
+		return apperr.ErrForbidden
+	}
+
+	if err := s.repo.SoftDeleteRequest(ctx, requestID); err != nil {
+		if apperr.IsNotFound(err) {
+			return err
+		}
+		return apperr.WrapInternal("delete request", err)
+	}
+
+	for _, f := range request.Files {
+		for _, key := range []string{f.S3Key, f.ThumbnailKey} {
+			if key == "" {
+				continue
+			}
+			if err := s.storage.DeleteFile(ctx, key); err != nil {
+				slog.WarnContext(ctx, "Failed to delete file of deleted request",
+					"request_id", requestID, "file_id", f.ID, "key", key, "error", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// enrichECGResponse returns request.Response with the status and conclusion
+// of the linked GPT request merged into its EKG content. The linked request
+// is fetched once; the stored response is returned unchanged when there is
+// nothing to merge or the caller may not see the GPT request.
+func enrichECGResponse(ctx context.Context, repo repository.RequestRepo, request *models.Request, claims *auth.Claims) *models.Response {
+	resp := request.Response
+	ekg, err := models.ParseECGContent(resp.Content)
 	if err != nil {
-		slog.WarnContext(ctx, "Failed to get GPT request for EKG enrichment", "request_id", request.ID, "gpt_request_id", gptRequestID, "error", err)
-		return
+		slog.DebugContext(ctx, "Failed to parse EKG content for enrichment", "request_id", request.ID, "error", err)
+		return resp
+	}
+	if ekg == nil {
+		return resp
 	}
 
-	if !auth.CanAccessResource(claims, gptRequest.UserID) {
-		return
+	gptRequest := linkedGPTRequest(ctx, repo, request.ID, ekg)
+	if gptRequest == nil || !auth.CanAccessResource(claims, gptRequest.UserID) {
+		return resp
 	}
 
 	ekg.GPTInterpretationStatus = gptRequest.Status
-	if gptRequest.Status == models.StatusCompleted && gptRequest.Response != nil {
+	switch {
+	case gptRequest.Status == models.StatusCompleted && gptRequest.Response != nil && gptRequest.Response.IsRefusal:
+		refused := "Analysis refused by model"
+		ekg.GPTInterpretation = &refused
+	case gptRequest.Status == models.StatusCompleted && gptRequest.Response != nil:
 		gptContent := gptRequest.Response.Content
-		conclusion := models.ExtractConclusion(gptContent)
+		var conclusion string
+		if structured, ok := models.ParseGPTStructuredResult(gptContent); ok {
+			conclusion = structured.Conclusion
+		} else {
+			conclusion = models.ExtractConclusion(gptContent)
+		}
 		ekg.GPTInterpretation = &conclusion
 		ekg.GPTFullResponse = &gptContent
-	} else if gptRequest.Status == models.StatusFailed {
+	case gptRequest.Status == models.StatusFailed:
 		failed := "GPT analysis failed"
 		ekg.GPTInterpretation = &failed
 	}
 
-	if updatedContent, err := ekg.Marshal(); err == nil {
-		request.Response.Content = updatedContent
+	content, err := ekg.Marshal()
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to marshal enriched EKG content", "request_id", request.ID, "error", err)
+		return resp
+	}
+	enriched := *resp
+	enriched.Content = content
+	return &enriched
+}
+
+// linkedGPTRequest returns the GPT request created for the EKG request
+// ekgRequestID: the newest child by parent_request_id, or for legacy rows
+// the one named by gpt_request_id in the EKG content. Nil means none.
+func linkedGPTRequest(ctx context.Context, repo repository.RequestRepo, ekgRequestID uuid.UUID, ekg *models.ECGResponseContent) *models.Request {
+	children, err := repo.GetChildRequests(ctx, ekgRequestID)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to get child requests for EKG enrichment", "request_id", ekgRequestID, "error", err)
+	}
+	if len(children) > 0 {
+		return &children[0]
+	}
+
+	if ekg.GPTRequestID == "" {
+		return nil
+	}
+	gptRequestID, err := uuid.Parse(ekg.GPTRequestID)
+	if err != nil {
+		slog.WarnContext(ctx, "Invalid GPT request ID in EKG content", "request_id", ekgRequestID, "gpt_request_id", ekg.GPTRequestID, "error", err)
+		return nil
+	}
+	gptRequest, err := repo.GetRequestByID(ctx, gptRequestID)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to get GPT request for EKG enrichment", "request_id", ekgRequestID, "gpt_request_id", gptRequestID, "error", err)
+		return nil
 	}
+	return gptRequest
 }
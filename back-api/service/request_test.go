@@ -16,13 +16,15 @@ import (
 	"github.com/fedutinova/smartheart/back-api/job"
 	jobmocks "github.com/fedutinova/smartheart/back-api/job/mocks"
 	"github.com/fedutinova/smartheart/back-api/models"
+	"github.com/fedutinova/smartheart/back-api/repository"
 	repomocks "github.com/fedutinova/smartheart/back-api/repository/mocks"
+	storagemocks "github.com/fedutinova/smartheart/back-api/storage/mocks"
 )
 
 func newRequestService(t *testing.T) (*requestService, *repomocks.MockStore, *jobmocks.MockQueue) {
 	repo := repomocks.NewMockStore(t)
 	queue := jobmocks.NewMockQueue(t)
-	svc := NewRequestService(repo, queue).(*requestService)
+	svc := NewRequestService(repo, queue, nil).(*requestService)
 	return svc, repo, queue
 }
 
@@ -41,14 +43,14 @@ func TestGetUserRequests_Success(t *testing.T) {
 	userID := uuid.New()
 
 	repo.EXPECT().
-		GetRequestsByUserID(mock.Anything, userID, 50, 0).
+		GetRequestsByUserID(mock.Anything, userID, repository.RequestFilter{Limit: 50}).
 		Return([]models.Request{{ID: uuid.New(), UserID: userID}}, nil)
 
 	repo.EXPECT().
-		CountRequestsByUserID(mock.Anything, userID).
+		CountRequestsByUserID(mock.Anything, userID, repository.RequestFilter{Limit: 50}).
 		Return(1, nil)
 
-	page, err := svc.GetUserRequests(ctx, userID, 50, 0)
+	page, err := svc.GetUserRequests(ctx, userID, repository.RequestFilter{Limit: 50})
 	require.NoError(t, err)
 	assert.Len(t, page.Data, 1)
 	assert.Equal(t, 1, page.Total)
@@ -63,14 +65,14 @@ func TestGetUserRequests_DefaultLimit(t *testing.T) {
 
 	// Limit <= 0 should default to 50
 	repo.EXPECT().
-		GetRequestsByUserID(mock.Anything, userID, 50, 0).
+		GetRequestsByUserID(mock.Anything, userID, repository.RequestFilter{Limit: 50}).
 		Return(nil, nil)
 
 	repo.EXPECT().
-		CountRequestsByUserID(mock.Anything, userID).
+		CountRequestsByUserID(mock.Anything, userID, repository.RequestFilter{Limit: 50}).
 		Return(0, nil)
 
-	page, err := svc.GetUserRequests(ctx, userID, 0, 0)
+	page, err := svc.GetUserRequests(ctx, userID, repository.RequestFilter{})
 	require.NoError(t, err)
 	assert.Equal(t, 50, page.Limit)
 	assert.Empty(t, page.Data) // nil is converted to empty slice
@@ -83,14 +85,14 @@ func TestGetUserRequests_LimitTooHigh(t *testing.T) {
 
 	// Limit > 200 should default to 50
 	repo.EXPECT().
-		GetRequestsByUserID(mock.Anything, userID, 50, 0).
+		GetRequestsByUserID(mock.Anything, userID, repository.RequestFilter{Limit: 50}).
 		Return(nil, nil)
 
 	repo.EXPECT().
-		CountRequestsByUserID(mock.Anything, userID).
+		CountRequestsByUserID(mock.Anything, userID, repository.RequestFilter{Limit: 50}).
 		Return(0, nil)
 
-	page, err := svc.GetUserRequests(ctx, userID, 300, 0)
+	page, err := svc.GetUserRequests(ctx, userID, repository.RequestFilter{Limit: 300})
 	require.NoError(t, err)
 	assert.Equal(t, 50, page.Limit)
 }
@@ -102,28 +104,49 @@ func TestGetUserRequests_NegativeOffset(t *testing.T) {
 
 	// Negative offset should default to 0
 	repo.EXPECT().
-		GetRequestsByUserID(mock.Anything, userID, 50, 0).
+		GetRequestsByUserID(mock.Anything, userID, repository.RequestFilter{Limit: 50}).
 		Return(nil, nil)
 
 	repo.EXPECT().
-		CountRequestsByUserID(mock.Anything, userID).
+		CountRequestsByUserID(mock.Anything, userID, repository.RequestFilter{Limit: 50}).
 		Return(0, nil)
 
-	page, err := svc.GetUserRequests(ctx, userID, 50, -5)
+	page, err := svc.GetUserRequests(ctx, userID, repository.RequestFilter{Limit: 50, Offset: -5})
 	require.NoError(t, err)
 	assert.Equal(t, 0, page.Offset)
 }
 
+func TestGetUserRequests_PassesTagFilter(t *testing.T) {
+	svc, repo, _ := newRequestService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	filter := repository.RequestFilter{Tags: map[string]string{"study": "af-2026"}, Limit: 20, Offset: 40}
+
+	repo.EXPECT().
+		GetRequestsByUserID(mock.Anything, userID, filter).
+		Return([]models.Request{{ID: uuid.New(), UserID: userID, Tags: filter.Tags}}, nil)
+
+	repo.EXPECT().
+		CountRequestsByUserID(mock.Anything, userID, filter).
+		Return(41, nil)
+
+	page, err := svc.GetUserRequests(ctx, userID, filter)
+	require.NoError(t, err)
+	assert.Len(t, page.Data, 1)
+	assert.Equal(t, 41, page.Total)
+	assert.Equal(t, 40, page.Offset)
+}
+
 func TestGetUserRequests_RepoError(t *testing.T) {
 	svc, repo, _ := newRequestService(t)
 	ctx := context.Background()
 	userID := uuid.New()
 
 	repo.EXPECT().
-		GetRequestsByUserID(mock.Anything, userID, 50, 0).
+		GetRequestsByUserID(mock.Anything, userID, repository.RequestFilter{Limit: 50}).
 		Return(nil, errors.New("db error"))
 
-	_, err := svc.GetUserRequests(ctx, userID, 50, 0)
+	_, err := svc.GetUserRequests(ctx, userID, repository.RequestFilter{Limit: 50})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "get user requests")
 }
@@ -134,18 +157,46 @@ func TestGetUserRequests_CountError(t *testing.T) {
 	userID := uuid.New()
 
 	repo.EXPECT().
-		GetRequestsByUserID(mock.Anything, userID, 50, 0).
+		GetRequestsByUserID(mock.Anything, userID, repository.RequestFilter{Limit: 50}).
 		Return([]models.Request{}, nil)
 
 	repo.EXPECT().
-		CountRequestsByUserID(mock.Anything, userID).
+		CountRequestsByUserID(mock.Anything, userID, repository.RequestFilter{Limit: 50}).
 		Return(0, errors.New("count error"))
 
-	_, err := svc.GetUserRequests(ctx, userID, 50, 0)
+	_, err := svc.GetUserRequests(ctx, userID, repository.RequestFilter{Limit: 50})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "count user requests")
 }
 
+// --- GetRequestStats ---
+
+func TestGetRequestStats_SumsTotal(t *testing.T) {
+	svc, repo, _ := newRequestService(t)
+	userID := uuid.New()
+
+	repo.EXPECT().
+		CountRequestsByStatus(mock.Anything, userID).
+		Return(map[string]int{models.StatusPending: 3, models.StatusProcessing: 0, models.StatusCompleted: 38, models.StatusFailed: 1}, nil)
+
+	stats, err := svc.GetRequestStats(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, 42, stats.Total)
+	assert.Equal(t, 3, stats.ByStatus[models.StatusPending])
+}
+
+func TestGetRequestStats_RepoError(t *testing.T) {
+	svc, repo, _ := newRequestService(t)
+
+	repo.EXPECT().
+		CountRequestsByStatus(mock.Anything, mock.Anything).
+		Return(nil, errors.New("db down"))
+
+	_, err := svc.GetRequestStats(context.Background(), uuid.New())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, apperr.ErrInternal)
+}
+
 // --- GetRequest ---
 
 func TestGetRequest_Success(t *testing.T) {
@@ -216,6 +267,7 @@ func TestGetRequest_WithEKGEnrichment(t *testing.T) {
 			},
 		}, nil)
 
+	repo.EXPECT().GetChildRequests(mock.Anything, requestID).Return(nil, nil)
 	gptResponse := "### Заключение\nAll good"
 	repo.EXPECT().
 		GetRequestByID(mock.Anything, gptRequestID).
@@ -239,6 +291,120 @@ func TestGetRequest_WithEKGEnrichment(t *testing.T) {
 	assert.Contains(t, *enriched.GPTInterpretation, "All good")
 }
 
+func TestGetRequest_EKGEnrichmentUsesStructuredConclusion(t *testing.T) {
+	svc, repo, _ := newRequestService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	requestID := uuid.New()
+	gptRequestID := uuid.New()
+
+	ecgContent := &models.ECGResponseContent{
+		AnalysisType: models.ECGModelDirect,
+		GPTRequestID: gptRequestID.String(),
+	}
+	ekgJSON, _ := ecgContent.Marshal()
+
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, requestID).
+		Return(&models.Request{
+			ID:       requestID,
+			UserID:   userID,
+			Response: &models.Response{Model: models.ECGModelDirect, Content: ekgJSON},
+		}, nil)
+	repo.EXPECT().GetChildRequests(mock.Anything, requestID).Return(nil, nil)
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, gptRequestID).
+		Return(&models.Request{
+			ID:     gptRequestID,
+			UserID: userID,
+			Status: models.StatusCompleted,
+			Response: &models.Response{
+				Content: `{"image_quality":"хорошее","patterns":[],"conclusion":"Синусовый ритм"}`,
+			},
+		}, nil)
+
+	req, err := svc.GetRequest(ctx, requestID, userClaims(userID))
+	require.NoError(t, err)
+
+	var enriched models.ECGResponseContent
+	require.NoError(t, json.Unmarshal([]byte(req.Response.Content), &enriched))
+	require.NotNil(t, enriched.GPTInterpretation)
+	assert.Equal(t, "Синусовый ритм", *enriched.GPTInterpretation)
+}
+
+func TestEnrichECGResponse_PrefersChildRequest(t *testing.T) {
+	_, repo, _ := newRequestService(t)
+	userID := uuid.New()
+	// The legacy JSON link points elsewhere; the parent_request_id child wins
+	// and GetRequestByID is never called.
+	ekgJSON, _ := (&models.ECGResponseContent{AnalysisType: models.ECGModelDirect, GPTRequestID: uuid.New().String()}).Marshal()
+	request := &models.Request{ID: uuid.New(), UserID: userID, Response: &models.Response{Model: models.ECGModelDirect, Content: ekgJSON}}
+
+	repo.EXPECT().GetChildRequests(mock.Anything, request.ID).Return([]models.Request{
+		{ID: uuid.New(), UserID: userID, ParentRequestID: &request.ID, Status: models.StatusCompleted,
+			Response: &models.Response{Content: "### Заключение\nFrom the child"}},
+	}, nil)
+
+	resp := enrichECGResponse(context.Background(), repo, request, userClaims(userID))
+
+	var enriched models.ECGResponseContent
+	require.NoError(t, json.Unmarshal([]byte(resp.Content), &enriched))
+	require.NotNil(t, enriched.GPTInterpretation)
+	assert.Contains(t, *enriched.GPTInterpretation, "From the child")
+}
+
+func TestEnrichECGResponse_GPTStatus(t *testing.T) {
+	tests := []struct {
+		name               string
+		gpt                *models.Request
+		wantInterpretation string // "" = none
+		wantFullResponse   bool
+	}{
+		{
+			name:               "completed",
+			gpt:                &models.Request{Status: models.StatusCompleted, Response: &models.Response{Content: "### Заключение\nAll good"}},
+			wantInterpretation: "All good",
+			wantFullResponse:   true,
+		},
+		{
+			name: "refused",
+			gpt: &models.Request{Status: models.StatusCompleted, Response: &models.Response{
+				Model: models.ModelRefused, Content: "Модель отказалась", IsRefusal: true,
+			}},
+			wantInterpretation: "Analysis refused by model",
+		},
+		{name: "pending", gpt: &models.Request{Status: models.StatusPending}},
+		{name: "failed", gpt: &models.Request{Status: models.StatusFailed}, wantInterpretation: "GPT analysis failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, repo, _ := newRequestService(t)
+			userID, gptRequestID := uuid.New(), uuid.New()
+			ekgJSON, _ := (&models.ECGResponseContent{AnalysisType: models.ECGModelDirect, GPTRequestID: gptRequestID.String()}).Marshal()
+			stored := &models.Response{Model: models.ECGModelDirect, Content: ekgJSON}
+			request := &models.Request{ID: uuid.New(), UserID: userID, Response: stored}
+
+			tt.gpt.ID, tt.gpt.UserID = gptRequestID, userID
+			repo.EXPECT().GetChildRequests(mock.Anything, request.ID).Return(nil, nil).Once()
+			repo.EXPECT().GetRequestByID(mock.Anything, gptRequestID).Return(tt.gpt, nil).Once()
+
+			resp := enrichECGResponse(context.Background(), repo, request, userClaims(userID))
+
+			assert.Equal(t, ekgJSON, stored.Content, "stored response must not be modified")
+			var enriched models.ECGResponseContent
+			require.NoError(t, json.Unmarshal([]byte(resp.Content), &enriched))
+			assert.Equal(t, tt.gpt.Status, enriched.GPTInterpretationStatus)
+			if tt.wantInterpretation == "" {
+				assert.Nil(t, enriched.GPTInterpretation)
+			} else {
+				require.NotNil(t, enriched.GPTInterpretation)
+				assert.Contains(t, *enriched.GPTInterpretation, tt.wantInterpretation)
+			}
+			assert.Equal(t, tt.wantFullResponse, enriched.GPTFullResponse != nil)
+		})
+	}
+}
+
 // --- GetJobStatus ---
 
 func TestGetJobStatus_Success(t *testing.T) {
@@ -298,6 +464,47 @@ func TestGetJobStatus_Forbidden(t *testing.T) {
 	assert.ErrorIs(t, err, apperr.ErrForbidden)
 }
 
+func TestGetJobStatus_ForbiddenByJobUserID(t *testing.T) {
+	svc, _, queue := newRequestService(t)
+	ctx := context.Background()
+	ownerID := uuid.New()
+	jobID := uuid.New()
+
+	// Job.UserID takes precedence over the payload.
+	payload, _ := json.Marshal(map[string]string{"user_id": uuid.New().String()})
+
+	queue.EXPECT().
+		Status(mock.Anything, jobID).
+		Return(&job.Job{
+			ID:      jobID,
+			UserID:  ownerID,
+			Payload: payload,
+		}, true)
+
+	_, err := svc.GetJobStatus(ctx, jobID, userClaims(uuid.New()))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, apperr.ErrForbidden)
+}
+
+func TestGetJobStatus_OwnerByJobUserID(t *testing.T) {
+	svc, _, queue := newRequestService(t)
+	ctx := context.Background()
+	ownerID := uuid.New()
+	jobID := uuid.New()
+
+	queue.EXPECT().
+		Status(mock.Anything, jobID).
+		Return(&job.Job{
+			ID:      jobID,
+			UserID:  ownerID,
+			Payload: []byte("{}"),
+		}, true)
+
+	j, err := svc.GetJobStatus(ctx, jobID, userClaims(ownerID))
+	require.NoError(t, err)
+	assert.Equal(t, ownerID, j.UserID)
+}
+
 func TestGetJobStatus_InvalidPayload(t *testing.T) {
 	svc, _, queue := newRequestService(t)
 	ctx := context.Background()
@@ -314,3 +521,113 @@ func TestGetJobStatus_InvalidPayload(t *testing.T) {
 	require.Error(t, err)
 	assert.ErrorIs(t, err, apperr.ErrForbidden)
 }
+
+// --- GetFile ---
+
+func TestGetFile_Success(t *testing.T) {
+	svc, repo, _ := newRequestService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	fileID := uuid.New()
+
+	repo.EXPECT().
+		GetFileByID(mock.Anything, fileID).
+		Return(&models.File{ID: fileID, S3Key: "ecg/abc.png"}, userID, nil)
+
+	file, err := svc.GetFile(ctx, fileID, userClaims(userID))
+	require.NoError(t, err)
+	assert.Equal(t, "ecg/abc.png", file.S3Key)
+}
+
+func TestGetFile_AdminCanAccessOtherUsersFile(t *testing.T) {
+	svc, repo, _ := newRequestService(t)
+	ctx := context.Background()
+	fileID := uuid.New()
+
+	repo.EXPECT().
+		GetFileByID(mock.Anything, fileID).
+		Return(&models.File{ID: fileID, S3Key: "ecg/abc.png"}, uuid.New(), nil)
+
+	admin := &auth.Claims{UserID: uuid.New().String(), Roles: []string{auth.RoleAdmin}}
+	_, err := svc.GetFile(ctx, fileID, admin)
+	require.NoError(t, err)
+}
+
+func TestGetFile_Forbidden(t *testing.T) {
+	svc, repo, _ := newRequestService(t)
+	ctx := context.Background()
+	fileID := uuid.New()
+
+	repo.EXPECT().
+		GetFileByID(mock.Anything, fileID).
+		Return(&models.File{ID: fileID, S3Key: "ecg/abc.png"}, uuid.New(), nil)
+
+	_, err := svc.GetFile(ctx, fileID, userClaims(uuid.New()))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, apperr.ErrForbidden)
+}
+
+func TestGetFile_NotFound(t *testing.T) {
+	svc, repo, _ := newRequestService(t)
+	ctx := context.Background()
+
+	repo.EXPECT().
+		GetFileByID(mock.Anything, mock.Anything).
+		Return(nil, uuid.Nil, apperr.ErrFileNotFound)
+
+	_, err := svc.GetFile(ctx, uuid.New(), userClaims(uuid.New()))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+}
+
+func TestDeleteRequest_RemovesFiles(t *testing.T) {
+	svc, repo, _ := newRequestService(t)
+	store := storagemocks.NewMockStorage(t)
+	svc.storage = store
+	ctx := context.Background()
+	userID := uuid.New()
+	requestID := uuid.New()
+
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, requestID).
+		Return(&models.Request{
+			ID:     requestID,
+			UserID: userID,
+			Files: []models.File{
+				{ID: uuid.New(), S3Key: "ecg/a.png", ThumbnailKey: "thumbnails/a.jpg"},
+				{ID: uuid.New()},
+				{ID: uuid.New(), S3Key: "ecg/b.png"},
+			},
+		}, nil)
+	repo.EXPECT().SoftDeleteRequest(mock.Anything, requestID).Return(nil)
+	store.EXPECT().DeleteFile(mock.Anything, "ecg/a.png").Return(errors.New("storage down"))
+	store.EXPECT().DeleteFile(mock.Anything, "thumbnails/a.jpg").Return(nil)
+	store.EXPECT().DeleteFile(mock.Anything, "ecg/b.png").Return(nil)
+
+	require.NoError(t, svc.DeleteRequest(ctx, requestID, userClaims(userID)))
+}
+
+func TestDeleteRequest_Forbidden(t *testing.T) {
+	svc, repo, _ := newRequestService(t)
+	ctx := context.Background()
+	requestID := uuid.New()
+
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, requestID).
+		Return(&models.Request{ID: requestID, UserID: uuid.New()}, nil)
+
+	err := svc.DeleteRequest(ctx, requestID, userClaims(uuid.New()))
+	assert.ErrorIs(t, err, apperr.ErrForbidden)
+}
+
+func TestDeleteRequest_AlreadyDeleted(t *testing.T) {
+	svc, repo, _ := newRequestService(t)
+	ctx := context.Background()
+
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, mock.Anything).
+		Return(nil, apperr.ErrRequestNotFound)
+
+	err := svc.DeleteRequest(ctx, uuid.New(), userClaims(uuid.New()))
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+}
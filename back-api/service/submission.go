@@ -2,19 +2,23 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sashabaranov/go-openai"
 
 	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/auth"
 	"github.com/fedutinova/smartheart/back-api/config"
 	"github.com/fedutinova/smartheart/back-api/gpt"
 	"github.com/fedutinova/smartheart/back-api/job"
+	"github.com/fedutinova/smartheart/back-api/logging"
 	"github.com/fedutinova/smartheart/back-api/models"
 	"github.com/fedutinova/smartheart/back-api/redaction"
 	"github.com/fedutinova/smartheart/back-api/repository"
@@ -33,6 +37,40 @@ type GPTSubmitResult struct {
 	SubmittedJob
 	FilesProcessed int
 	UploadErrors   []string
+	// TokensRemaining is the user's OpenAI token budget left before this job
+	// runs; nil when the user has no budget.
+	TokensRemaining *int
+}
+
+// GPTOptions holds optional settings for a GPT submission.
+type GPTOptions struct {
+	// Structured requests JSON output (models.GPTStructuredResult).
+	Structured bool
+	// CallbackURL is an optional completion webhook, already SSRF-checked.
+	CallbackURL string
+	// NoCache skips the GPT result cache so an identical earlier request
+	// is not reused.
+	NoCache bool
+	// Timeout overrides the GPT client timeout for this request, already
+	// checked against the configured maximum. Zero uses the default.
+	Timeout time.Duration
+	// ImageDetail and MaxTokens override the GPT client defaults for this
+	// request, already checked by gpt.ValidImageDetail and
+	// gpt.ValidMaxTokens. Zero values use the defaults.
+	ImageDetail openai.ImageURLDetail
+	MaxTokens   int
+	// Tags are stored on the request, already checked by models.ValidateTags.
+	Tags map[string]string
+}
+
+// ReanalyzeOptions holds the overrides of a GPT re-analysis.
+type ReanalyzeOptions struct {
+	// Model replaces the configured GPT model, already checked against the
+	// allowlist. Empty uses the default.
+	Model string
+	// TextQuery replaces the request's stored text query for this run.
+	// Empty reuses the stored one.
+	TextQuery string
 }
 
 // UploadedFile represents a file ready for processing.
@@ -51,13 +89,70 @@ type ECGParams struct {
 	MmPerMvLimb   float64
 	MmPerMvChest  float64
 	ClientMeta    *models.RequestClientMeta
+	CallbackURL   string            // optional completion webhook, already SSRF-checked
+	BatchID       *uuid.UUID        // set for requests created by SubmitECGBatch
+	Tags          map[string]string // already checked by models.ValidateTags
+}
+
+// DefaultECGParams returns parameters with the standard calibration:
+// 25 mm/s paper speed and 10 mm/mV gain on limb and chest leads.
+func DefaultECGParams() ECGParams {
+	return ECGParams{PaperSpeedMMS: 25, MmPerMvLimb: 10, MmPerMvChest: 10}
+}
+
+// Validate checks patient and calibration values against the ranges the API
+// accepts. Every transport builds ECGParams its own way, so the submit
+// methods call this rather than relying on each caller's checks.
+func (p ECGParams) Validate() error {
+	if p.Age != nil && (*p.Age < 1 || *p.Age > 150) {
+		return fmt.Errorf("age must be between 1 and 150: %w", apperr.ErrValidation)
+	}
+	if p.Sex != "" && p.Sex != "male" && p.Sex != "female" {
+		return fmt.Errorf("sex must be male or female: %w", apperr.ErrValidation)
+	}
+	if p.PaperSpeedMMS < 10 || p.PaperSpeedMMS > 100 {
+		return fmt.Errorf("paper_speed_mms must be between 10 and 100: %w", apperr.ErrValidation)
+	}
+	if p.MmPerMvLimb < 1 || p.MmPerMvLimb > 40 {
+		return fmt.Errorf("mm_per_mv_limb must be between 1 and 40: %w", apperr.ErrValidation)
+	}
+	if p.MmPerMvChest < 1 || p.MmPerMvChest > 40 {
+		return fmt.Errorf("mm_per_mv_chest must be between 1 and 40: %w", apperr.ErrValidation)
+	}
+	return nil
+}
+
+// MaxECGBatchSize caps the number of images in one batch submission.
+const MaxECGBatchSize = 20
+
+// ECGBatchItem is one image of a batch submission: a URL or an uploaded file.
+type ECGBatchItem struct {
+	ImageURL string
+	File     *UploadedFile
+}
+
+// ECGBatchItemResult is the outcome of one batch item: Job on success, Err
+// when the item was rejected. Items are independent, so one failure does not
+// affect the others.
+type ECGBatchItemResult struct {
+	Job *SubmittedJob
+	Err error
+}
+
+// ECGBatchResult is returned by SubmitECGBatch, with Items in input order.
+type ECGBatchResult struct {
+	BatchID uuid.UUID
+	Items   []ECGBatchItemResult
 }
 
 // SubmissionService handles EKG and GPT job submission business logic.
 type SubmissionService interface {
 	SubmitECG(ctx context.Context, userID uuid.UUID, imageURL string, params ECGParams) (*SubmittedJob, error)
 	SubmitECGFile(ctx context.Context, userID uuid.UUID, file UploadedFile, params ECGParams) (*SubmittedJob, error)
-	SubmitGPT(ctx context.Context, userID uuid.UUID, textQuery string, files []UploadedFile) (*GPTSubmitResult, error)
+	SubmitECGBatch(ctx context.Context, userID uuid.UUID, items []ECGBatchItem, params ECGParams) (*ECGBatchResult, error)
+	SubmitGPT(ctx context.Context, userID uuid.UUID, textQuery string, files []UploadedFile, opts GPTOptions) (*GPTSubmitResult, error)
+	RetryGPT(ctx context.Context, requestID uuid.UUID, claims *auth.Claims) (*GPTSubmitResult, error)
+	ReanalyzeGPT(ctx context.Context, requestID uuid.UUID, claims *auth.Claims, opts ReanalyzeOptions) (*GPTSubmitResult, error)
 	CompareH2Redaction(ctx context.Context, file UploadedFile) (interface{}, error)
 }
 
@@ -66,11 +161,13 @@ type submissionService struct {
 	queue     job.Queue
 	storage   storage.Storage
 	freeLimit int
+	quota     config.QuotaConfig
 }
 
 func NewSubmissionService(repo repository.Store, queue job.Queue, storageService storage.Storage, quota ...config.QuotaConfig) SubmissionService {
 	s := &submissionService{repo: repo, queue: queue, storage: storageService}
 	if len(quota) > 0 {
+		s.quota = quota[0]
 		s.freeLimit = quota[0].FreeLimit
 	}
 	return s
@@ -102,6 +199,7 @@ func ecgRequest(requestID, userID uuid.UUID, p ECGParams) *models.Request {
 		Status:     models.StatusPending,
 		ClientMeta: p.ClientMeta,
 		ECGAge:     p.Age,
+		Tags:       p.Tags,
 	}
 	if p.Sex != "" {
 		req.ECGSex = &p.Sex
@@ -115,9 +213,40 @@ func ecgRequest(requestID, userID uuid.UUID, p ECGParams) *models.Request {
 	if p.MmPerMvChest != 0 {
 		req.ECGMmPerMvChest = &p.MmPerMvChest
 	}
+	if p.CallbackURL != "" {
+		req.CallbackURL = &p.CallbackURL
+	}
+	req.BatchID = p.BatchID
 	return req
 }
 
+// markQueued records that the job of requestID is in the queue. The job is
+// already enqueued, so a failure is only logged: the request stays pending
+// until the worker moves it to processing.
+func (s *submissionService) markQueued(ctx context.Context, requestID uuid.UUID) {
+	if err := s.repo.MarkRequestQueued(ctx, requestID); err != nil {
+		slog.WarnContext(ctx, "Failed to mark request queued", "request_id", requestID, "error", err)
+	}
+}
+
+// markUnqueued fails a request whose job could not be enqueued, so it does
+// not sit pending with no job to process it and count against the user's
+// active request cap.
+func (s *submissionService) markUnqueued(ctx context.Context, requestID uuid.UUID) {
+	if err := s.repo.UpdateRequestStatus(ctx, requestID, models.StatusFailed); err != nil {
+		slog.ErrorContext(ctx, "Failed to mark request as failed", "request_id", requestID, "error", err)
+	}
+}
+
+// correlationID returns the request ID carried by ctx for storing on a new
+// Request, or nil outside an HTTP request.
+func correlationID(ctx context.Context) *string {
+	if id := logging.RequestID(ctx); id != "" {
+		return &id
+	}
+	return nil
+}
+
 // checkQuota enforces the lifetime free analyses model:
 //  1. If freeLimit <= 0, allow unconditionally (unlimited mode).
 //  2. If user has an active subscription → allow.
@@ -125,26 +254,29 @@ func ecgRequest(requestID, userID uuid.UUID, p ECGParams) *models.Request {
 //  4. If new count <= freeLimit → this is a free slot, allow.
 //  5. Otherwise → decrement back and return ErrPaymentRequired.
 //
+// charged reports whether a free slot was claimed; the caller hands it to
+// refundQuota if the submission then fails.
+//
 // NOTE: Quota checks fail open on database errors to prioritize availability.
 // Set alerts on these error logs.
-func (s *submissionService) checkQuota(ctx context.Context, userID uuid.UUID) error {
+func (s *submissionService) checkQuota(ctx context.Context, userID uuid.UUID) (charged bool, err error) {
 	if s.freeLimit <= 0 {
-		return nil // unlimited
+		return false, nil // unlimited
 	}
 
 	// Check subscription first (takes precedence over free quota)
 	subExpires, err := s.repo.GetSubscriptionExpiresAt(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("check subscription: %w", err)
+		return false, fmt.Errorf("check subscription: %w", err)
 	}
 	if subExpires != nil && subExpires.After(time.Now()) {
-		return nil // active subscription = unlimited
+		return false, nil // active subscription = unlimited
 	}
 
 	// Increment usage and check against lifetime limit
 	count, err := s.repo.IncrementFreeAnalysesUsed(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("increment free analyses used: %w", err)
+		return false, fmt.Errorf("increment free analyses used: %w", err)
 	}
 
 	if count > s.freeLimit {
@@ -153,28 +285,77 @@ func (s *submissionService) checkQuota(ctx context.Context, userID uuid.UUID) er
 			slog.WarnContext(ctx, "Failed to decrement free analyses after quota exceeded",
 				"user_id", userID, "error", decErr)
 		}
-		return fmt.Errorf("free limit (%d) exceeded, subscribe for unlimited: %w",
+		return false, fmt.Errorf("free limit (%d) exceeded, subscribe for unlimited: %w",
 			s.freeLimit, apperr.ErrPaymentRequired)
 	}
 
+	return true, nil
+}
+
+// refundQuota gives back the free analysis claimed by checkQuota for a
+// submission that was not stored or whose job was not enqueued, so a failed
+// submission or a full queue does not use up the user's lifetime quota.
+func (s *submissionService) refundQuota(ctx context.Context, userID uuid.UUID, charged bool) {
+	if !charged {
+		return
+	}
+	if err := s.repo.DecrementFreeAnalysesUsed(ctx, userID); err != nil {
+		slog.WarnContext(ctx, "Failed to decrement free analyses after enqueue error",
+			"user_id", userID, "error", err)
+	}
+}
+
+// checkActiveRequests rejects a submission while the user already has
+// quota.MaxActive requests pending or processing, so one user cannot flood the
+// queue. Admins are exempt.
+func (s *submissionService) checkActiveRequests(ctx context.Context, userID uuid.UUID) error {
+	if s.quota.MaxActive <= 0 {
+		return nil // unlimited
+	}
+	if claims, ok := auth.FromContext(ctx); ok && slices.Contains(claims.Roles, auth.RoleAdmin) {
+		return nil
+	}
+
+	active, err := s.repo.CountActiveRequests(ctx, userID)
+	if err != nil {
+		return apperr.WrapInternal("count active requests", err)
+	}
+	if active >= s.quota.MaxActive {
+		return fmt.Errorf("%d requests still in progress, at most %d allowed: %w",
+			active, s.quota.MaxActive, apperr.ErrQuotaExceeded)
+	}
 	return nil
 }
 
-func (s *submissionService) SubmitECG(ctx context.Context, userID uuid.UUID, imageURL string, params ECGParams) (*SubmittedJob, error) {
+func (s *submissionService) SubmitECG(ctx context.Context, userID uuid.UUID, imageURL string, params ECGParams) (_ *SubmittedJob, err error) {
 	if imageURL == "" {
 		return nil, fmt.Errorf("image_temp_url is required: %w", apperr.ErrValidation)
 	}
-	if err := s.checkQuota(ctx, userID); err != nil {
+	if err := params.Validate(); err != nil {
 		return nil, err
 	}
+	if err := s.checkActiveRequests(ctx, userID); err != nil {
+		return nil, err
+	}
+	charged, err := s.checkQuota(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.refundQuota(ctx, userID, charged)
+		}
+	}()
+
 	requestID := uuid.New()
 	request := ecgRequest(requestID, userID, params)
+	request.CorrelationID = correlationID(ctx)
 
 	if err := s.repo.CreateRequest(ctx, request); err != nil {
 		return nil, apperr.WrapInternal("create request", err)
 	}
 
-	payload, err := json.Marshal(job.ECGJobPayload{
+	j, err := job.New(userID, job.ECGJobPayload{
 		ImageTempURL:  imageURL,
 		UserID:        userID,
 		RequestID:     requestID,
@@ -183,16 +364,17 @@ func (s *submissionService) SubmitECG(ctx context.Context, userID uuid.UUID, ima
 		PaperSpeedMMS: params.PaperSpeedMMS,
 		MmPerMvLimb:   params.MmPerMvLimb,
 		MmPerMvChest:  params.MmPerMvChest,
+		CallbackURL:   params.CallbackURL,
 	})
 	if err != nil {
 		return nil, apperr.WrapInternal("marshal EKG payload", err)
 	}
-
-	j := &job.Job{Type: job.TypeECGAnalyze, Payload: payload}
-	jobID, err := s.queue.Enqueue(ctx, j)
+	jobID, err := s.queue.TryEnqueue(ctx, j)
 	if err != nil {
+		s.markUnqueued(ctx, requestID)
 		return nil, apperr.WrapInternal("enqueue EKG job", err)
 	}
+	s.markQueued(ctx, requestID)
 
 	slog.InfoContext(ctx, "EKG analysis job enqueued", "job_id", jobID, "request_id", requestID, "user_id", userID)
 
@@ -203,10 +385,23 @@ func (s *submissionService) SubmitECG(ctx context.Context, userID uuid.UUID, ima
 	}, nil
 }
 
-func (s *submissionService) SubmitECGFile(ctx context.Context, userID uuid.UUID, file UploadedFile, params ECGParams) (*SubmittedJob, error) {
-	if err := s.checkQuota(ctx, userID); err != nil {
+func (s *submissionService) SubmitECGFile(ctx context.Context, userID uuid.UUID, file UploadedFile, params ECGParams) (_ *SubmittedJob, err error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkActiveRequests(ctx, userID); err != nil {
 		return nil, err
 	}
+	charged, err := s.checkQuota(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.refundQuota(ctx, userID, charged)
+		}
+	}()
+
 	contentType, err := detectContentType(&file)
 	if err != nil {
 		return nil, err
@@ -220,10 +415,7 @@ func (s *submissionService) SubmitECGFile(ctx context.Context, userID uuid.UUID,
 
 	requestID := uuid.New()
 	request := ecgRequest(requestID, userID, params)
-
-	if err := s.repo.CreateRequest(ctx, request); err != nil {
-		return nil, apperr.WrapInternal("create request", err)
-	}
+	request.CorrelationID = correlationID(ctx)
 
 	fileModel := &models.File{
 		ID:               uuid.New(),
@@ -233,12 +425,19 @@ func (s *submissionService) SubmitECGFile(ctx context.Context, userID uuid.UUID,
 		FileSize:         file.Size,
 		S3Key:            uploadResult.Key,
 		S3URL:            uploadResult.URL,
+		Checksum:         uploadResult.Checksum,
+		ThumbnailKey:     s.storeThumbnail(ctx, file, contentType, uploadResult.Key),
+	}
+	if err := s.repo.CreateRequest(ctx, request); err != nil {
+		s.deleteUploads(ctx, []*models.File{fileModel})
+		return nil, apperr.WrapInternal("create request", err)
 	}
 	if err := s.repo.CreateFile(ctx, fileModel); err != nil {
+		s.deleteUploads(ctx, []*models.File{fileModel})
 		return nil, apperr.WrapInternal("create file record", err)
 	}
 
-	payload, err := json.Marshal(job.ECGJobPayload{
+	j, err := job.New(userID, job.ECGJobPayload{
 		ImageFileKey:  uploadResult.Key,
 		UserID:        userID,
 		RequestID:     requestID,
@@ -247,16 +446,17 @@ func (s *submissionService) SubmitECGFile(ctx context.Context, userID uuid.UUID,
 		PaperSpeedMMS: params.PaperSpeedMMS,
 		MmPerMvLimb:   params.MmPerMvLimb,
 		MmPerMvChest:  params.MmPerMvChest,
+		CallbackURL:   params.CallbackURL,
 	})
 	if err != nil {
 		return nil, apperr.WrapInternal("marshal EKG payload", err)
 	}
-
-	j := &job.Job{Type: job.TypeECGAnalyze, Payload: payload}
-	jobID, err := s.queue.Enqueue(ctx, j)
+	jobID, err := s.queue.TryEnqueue(ctx, j)
 	if err != nil {
+		s.markUnqueued(ctx, requestID)
 		return nil, apperr.WrapInternal("enqueue EKG job", err)
 	}
+	s.markQueued(ctx, requestID)
 
 	slog.InfoContext(ctx, "EKG file analysis job enqueued", "job_id", jobID, "request_id", requestID, "user_id", userID, "file_key", uploadResult.Key)
 
@@ -267,8 +467,54 @@ func (s *submissionService) SubmitECGFile(ctx context.Context, userID uuid.UUID,
 	}, nil
 }
 
-func (s *submissionService) SubmitGPT(ctx context.Context, userID uuid.UUID, textQuery string, files []UploadedFile) (*GPTSubmitResult, error) {
-	if err := s.checkQuota(ctx, userID); err != nil {
+// SubmitECGBatch creates a batch and submits each item through the
+// single-image path (quota, upload, request row, enqueue) as a child request.
+// Per-item failures are reported in the result rather than failing the batch.
+func (s *submissionService) SubmitECGBatch(ctx context.Context, userID uuid.UUID, items []ECGBatchItem, params ECGParams) (*ECGBatchResult, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("at least one image is required: %w", apperr.ErrValidation)
+	}
+	if len(items) > MaxECGBatchSize {
+		return nil, fmt.Errorf("batch has %d images, at most %d allowed: %w", len(items), MaxECGBatchSize, apperr.ErrValidation)
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	batch := &models.ECGBatch{ID: uuid.New(), UserID: userID}
+	if err := s.repo.CreateECGBatch(ctx, batch); err != nil {
+		return nil, apperr.WrapInternal("create batch", err)
+	}
+	params.BatchID = &batch.ID
+
+	result := &ECGBatchResult{BatchID: batch.ID, Items: make([]ECGBatchItemResult, len(items))}
+	for i, item := range items {
+		var res ECGBatchItemResult
+		if item.File != nil {
+			res.Job, res.Err = s.SubmitECGFile(ctx, userID, *item.File, params)
+		} else {
+			res.Job, res.Err = s.SubmitECG(ctx, userID, item.ImageURL, params)
+		}
+		if res.Err != nil {
+			slog.WarnContext(ctx, "EKG batch item rejected", "batch_id", batch.ID, "index", i, "error", res.Err)
+		}
+		result.Items[i] = res
+	}
+
+	slog.InfoContext(ctx, "EKG batch submitted", "batch_id", batch.ID, "user_id", userID, "items", len(items))
+	return result, nil
+}
+
+func (s *submissionService) SubmitGPT(ctx context.Context, userID uuid.UUID, textQuery string, files []UploadedFile, opts GPTOptions) (*GPTSubmitResult, error) {
+	tokensRemaining, err := s.checkTokenBudget(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkActiveRequests(ctx, userID); err != nil {
+		return nil, err
+	}
+	charged, err := s.checkQuota(ctx, userID)
+	if err != nil {
 		return nil, err
 	}
 
@@ -276,76 +522,235 @@ func (s *submissionService) SubmitGPT(ctx context.Context, userID uuid.UUID, tex
 		ID:     uuid.New(),
 		UserID: userID,
 		Status: models.StatusPending,
+		Tags:   opts.Tags,
 	}
 	if textQuery != "" {
 		request.TextQuery = &textQuery
 	}
-
-	if err := s.repo.CreateRequest(ctx, request); err != nil {
-		return nil, apperr.WrapInternal("create request", err)
+	if opts.CallbackURL != "" {
+		request.CallbackURL = &opts.CallbackURL
 	}
+	request.CorrelationID = correlationID(ctx)
 
-	var fileKeys []string
+	// Files are uploaded first; the request and file rows are then written in
+	// one transaction so a submission is stored whole or not at all.
+	var uploaded []*models.File
 	var uploadErrors []string
 	for _, f := range files {
-		key, err := s.processFile(ctx, request.ID, f)
+		file, err := s.uploadFile(ctx, request.ID, f)
 		if err != nil {
 			slog.ErrorContext(ctx, "Failed to process file", "filename", f.Filename, "error", err)
 			uploadErrors = append(uploadErrors, fmt.Sprintf("%s: %s", f.Filename, err.Error()))
 			continue
 		}
-		fileKeys = append(fileKeys, key)
+		uploaded = append(uploaded, file)
 	}
 
-	if len(fileKeys) == 0 {
-		if err := s.repo.UpdateRequestStatus(ctx, request.ID, models.StatusFailed); err != nil {
-			slog.ErrorContext(ctx, "Failed to mark request as failed", "request_id", request.ID, "error", err)
-		}
+	if len(uploaded) == 0 {
+		s.refundQuota(ctx, userID, charged)
 		return &GPTSubmitResult{ //nolint:nilnil // intentionally returning partial result with upload errors alongside error
 			UploadErrors: uploadErrors,
 		}, fmt.Errorf("no files successfully processed: %w", apperr.ErrValidation)
 	}
 
+	if err := s.repo.RunTx(ctx, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		if err := txRepo.CreateRequest(ctx, request); err != nil {
+			return fmt.Errorf("create request: %w", err)
+		}
+		if err := txRepo.CreateFiles(ctx, uploaded); err != nil {
+			return fmt.Errorf("create file records: %w", err)
+		}
+		return nil
+	}); err != nil {
+		s.deleteUploads(ctx, uploaded)
+		s.refundQuota(ctx, userID, charged)
+		return nil, apperr.WrapInternal("store GPT submission", err)
+	}
+
+	fileKeys := make([]string, len(uploaded))
+	for i, file := range uploaded {
+		fileKeys[i] = file.S3Key
+	}
+
 	payload := gpt.JobPayload{
 		RequestID: request.ID,
 		TextQuery: textQuery,
 		FileKeys:  fileKeys,
 		UserID:    userID,
+
+		ImageDetail: opts.ImageDetail,
+		MaxTokens:   opts.MaxTokens,
+		Structured:  opts.Structured,
+		NoCache:     opts.NoCache,
+		TimeoutMs:   int(opts.Timeout.Milliseconds()),
+		CallbackURL: opts.CallbackURL,
+	}
+	j, err := job.New(userID, payload)
+	if err != nil {
+		return nil, apperr.WrapInternal("marshal GPT payload", err)
+	}
+	jobID, err := s.queue.TryEnqueue(ctx, j)
+	if err != nil {
+		// Keep the files so the request can be retried.
+		s.markUnqueued(ctx, request.ID)
+		s.refundQuota(ctx, userID, charged)
+		return nil, apperr.WrapInternal("enqueue GPT job", err)
+	}
+	s.markQueued(ctx, request.ID)
+
+	return &GPTSubmitResult{
+		SubmittedJob: SubmittedJob{
+			JobID:     jobID,
+			RequestID: request.ID,
+			Status:    models.StatusQueued,
+		},
+		FilesProcessed:  len(fileKeys),
+		UploadErrors:    uploadErrors,
+		TokensRemaining: tokensRemaining,
+	}, nil
+}
+
+// RetryGPT re-enqueues a failed GPT request with its already stored files and
+// original text query. The request goes back to queued and the worker writes
+// a fresh response. The free analyses quota is not charged again.
+func (s *submissionService) RetryGPT(ctx context.Context, requestID uuid.UUID, claims *auth.Claims) (*GPTSubmitResult, error) {
+	request, err := s.ownedRequest(ctx, requestID, claims)
+	if err != nil {
+		return nil, err
+	}
+	// EKG requests carry calibration parameters and are processed by a
+	// different job type.
+	if request.Status != models.StatusFailed || request.ECGPaperSpeedMMS != nil {
+		return nil, ErrNotRetryable
+	}
+	return s.requeueGPT(ctx, request, ReanalyzeOptions{}, false, ErrNotRetryable)
+}
+
+// ReanalyzeGPT runs a finished GPT request again on its stored files, for
+// example with a stronger model for a second opinion. The worker appends a
+// new response tagged with the model that produced it; earlier responses are
+// kept. Re-analysing a completed request is a new model call and is charged
+// to the free analyses quota; a failed one is re-run free, as by RetryGPT.
+func (s *submissionService) ReanalyzeGPT(ctx context.Context, requestID uuid.UUID, claims *auth.Claims, opts ReanalyzeOptions) (*GPTSubmitResult, error) {
+	request, err := s.ownedRequest(ctx, requestID, claims)
+	if err != nil {
+		return nil, err
+	}
+	finished := request.Status == models.StatusCompleted || request.Status == models.StatusFailed
+	if !finished || request.ECGPaperSpeedMMS != nil {
+		return nil, ErrNotReanalyzable
+	}
+	return s.requeueGPT(ctx, request, opts, request.Status == models.StatusCompleted, ErrNotReanalyzable)
+}
+
+// ownedRequest loads a request the caller may act on.
+func (s *submissionService) ownedRequest(ctx context.Context, requestID uuid.UUID, claims *auth.Claims) (*models.Request, error) {
+	request, err := s.repo.GetRequestByID(ctx, requestID)
+	if err != nil {
+		if apperr.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, apperr.WrapInternal("get request", err)
+	}
+	if !auth.CanAccessResource(claims, request.UserID) {
+		return nil, apperr.ErrForbidden
+	}
+	return request, nil
+}
+
+// requeueGPT enqueues a new GPT job for request with its stored files and
+// moves the request back to pending, then queued. The move to pending only
+// succeeds while the request still has the status the caller checked, so
+// concurrent calls enqueue one job; the others get raceErr. If the job cannot
+// be enqueued the request keeps its previous status. With chargeQuota the
+// free analyses quota is checked first and refunded if nothing is enqueued.
+func (s *submissionService) requeueGPT(ctx context.Context, request *models.Request, opts ReanalyzeOptions, chargeQuota bool, raceErr error) (*GPTSubmitResult, error) {
+	tokensRemaining, err := s.checkTokenBudget(ctx, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	fileKeys, err := s.repo.GetFileKeysByRequestID(ctx, request.ID)
+	if err != nil {
+		return nil, apperr.WrapInternal("get file keys", err)
+	}
+	if len(fileKeys) == 0 {
+		return nil, fmt.Errorf("request has no stored files: %w", apperr.ErrValidation)
+	}
+
+	// A retry or re-analysis asks for a fresh answer rather than a cached one.
+	payload := gpt.JobPayload{
+		RequestID: request.ID,
+		FileKeys:  fileKeys,
+		UserID:    request.UserID,
+		NoCache:   true,
+		Model:     opts.Model,
+		TextQuery: opts.TextQuery,
+	}
+	if payload.TextQuery == "" && request.TextQuery != nil {
+		payload.TextQuery = *request.TextQuery
 	}
-	payloadBytes, err := json.Marshal(payload)
+	if request.CallbackURL != nil {
+		payload.CallbackURL = *request.CallbackURL
+	}
+	j, err := job.New(request.UserID, payload)
 	if err != nil {
 		return nil, apperr.WrapInternal("marshal GPT payload", err)
 	}
 
-	j := &job.Job{Type: job.TypeGPTProcess, Payload: payloadBytes}
-	jobID, err := s.queue.Enqueue(ctx, j)
+	var charged bool
+	if chargeQuota {
+		if charged, err = s.checkQuota(ctx, request.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	reset, err := s.repo.TransitionRequestStatus(ctx, request.ID, request.Status, models.StatusPending)
+	if err != nil {
+		s.refundQuota(ctx, request.UserID, charged)
+		return nil, apperr.WrapInternal("reset request status", err)
+	}
+	if !reset {
+		s.refundQuota(ctx, request.UserID, charged)
+		return nil, raceErr
+	}
+
+	jobID, err := s.queue.TryEnqueue(ctx, j)
 	if err != nil {
+		if revertErr := s.repo.UpdateRequestStatus(ctx, request.ID, request.Status); revertErr != nil {
+			slog.ErrorContext(ctx, "Failed to restore request status after enqueue error",
+				"request_id", request.ID, "status", request.Status, "error", revertErr)
+		}
+		s.refundQuota(ctx, request.UserID, charged)
 		return nil, apperr.WrapInternal("enqueue GPT job", err)
 	}
+	s.markQueued(ctx, request.ID)
 
 	return &GPTSubmitResult{
 		SubmittedJob: SubmittedJob{
 			JobID:     jobID,
 			RequestID: request.ID,
-			Status:    request.Status,
+			Status:    models.StatusQueued,
 		},
-		FilesProcessed: len(fileKeys),
-		UploadErrors:   uploadErrors,
+		FilesProcessed:  len(fileKeys),
+		TokensRemaining: tokensRemaining,
 	}, nil
 }
 
-func (s *submissionService) processFile(ctx context.Context, requestID uuid.UUID, f UploadedFile) (string, error) {
+// uploadFile stores f and returns its file row, which the caller persists.
+func (s *submissionService) uploadFile(ctx context.Context, requestID uuid.UUID, f UploadedFile) (*models.File, error) {
 	contentType, err := detectContentType(&f)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	uploadResult, err := s.storage.UploadFile(ctx, f.Filename, f.Reader, contentType)
 	if err != nil {
-		return "", apperr.WrapInternal("upload file", err)
+		return nil, apperr.WrapInternal("upload file", err)
 	}
 
-	fileModel := &models.File{
+	return &models.File{
 		ID:               uuid.New(),
 		RequestID:        requestID,
 		OriginalFilename: f.Filename,
@@ -353,12 +758,24 @@ func (s *submissionService) processFile(ctx context.Context, requestID uuid.UUID
 		FileSize:         f.Size,
 		S3Key:            uploadResult.Key,
 		S3URL:            uploadResult.URL,
-	}
-	if err := s.repo.CreateFile(ctx, fileModel); err != nil {
-		return "", apperr.WrapInternal("create file record", err)
-	}
+		Checksum:         uploadResult.Checksum,
+		ThumbnailKey:     s.storeThumbnail(ctx, f, contentType, uploadResult.Key),
+	}, nil
+}
 
-	return uploadResult.Key, nil
+// deleteUploads removes the stored objects of files whose rows were not
+// written. Failures are logged; the orphan file sweeper catches leftovers.
+func (s *submissionService) deleteUploads(ctx context.Context, files []*models.File) {
+	for _, file := range files {
+		for _, key := range []string{file.S3Key, file.ThumbnailKey} {
+			if key == "" {
+				continue
+			}
+			if err := s.storage.DeleteFile(ctx, key); err != nil {
+				slog.WarnContext(ctx, "Failed to delete upload after rollback", "key", key, "error", err)
+			}
+		}
+	}
 }
 
 // CompareH2Redaction compares band vs OCR redaction for H2 hypothesis testing.
@@ -3,15 +3,23 @@ package service
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/sashabaranov/go-openai"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/config"
+	"github.com/fedutinova/smartheart/back-api/gpt"
+	"github.com/fedutinova/smartheart/back-api/job"
 	jobmocks "github.com/fedutinova/smartheart/back-api/job/mocks"
 	"github.com/fedutinova/smartheart/back-api/models"
 	repomocks "github.com/fedutinova/smartheart/back-api/repository/mocks"
@@ -44,10 +52,14 @@ func TestSubmitEKG_Success(t *testing.T) {
 		Return(nil)
 
 	queue.EXPECT().
-		Enqueue(mock.Anything, mock.Anything).
+		TryEnqueue(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, j *job.Job) {
+			assert.Equal(t, userID, j.UserID)
+		}).
 		Return(jobID, nil)
+	repo.EXPECT().MarkRequestQueued(mock.Anything, mock.Anything).Return(nil)
 
-	result, err := svc.SubmitECG(ctx, userID, "https://example.com/ekg.jpg", ECGParams{})
+	result, err := svc.SubmitECG(ctx, userID, "https://example.com/ekg.jpg", DefaultECGParams())
 	require.NoError(t, err)
 	assert.Equal(t, jobID, result.JobID)
 	assert.NotEqual(t, uuid.Nil, result.RequestID)
@@ -57,7 +69,7 @@ func TestSubmitEKG_EmptyImageURL(t *testing.T) {
 	svc, _, _, _ := newSubmissionService(t)
 	ctx := context.Background()
 
-	_, err := svc.SubmitECG(ctx, uuid.New(), "", ECGParams{})
+	_, err := svc.SubmitECG(ctx, uuid.New(), "", DefaultECGParams())
 	require.Error(t, err)
 	assert.ErrorIs(t, err, apperr.ErrValidation)
 }
@@ -65,12 +77,17 @@ func TestSubmitEKG_EmptyImageURL(t *testing.T) {
 func TestSubmitEKG_CreateRequestFails(t *testing.T) {
 	svc, repo, _, _ := newSubmissionService(t)
 	ctx := context.Background()
+	svc.freeLimit = 3
+	userID := uuid.New()
+	repo.EXPECT().GetSubscriptionExpiresAt(mock.Anything, userID).Return(nil, nil)
+	repo.EXPECT().IncrementFreeAnalysesUsed(mock.Anything, userID).Return(1, nil)
 
 	repo.EXPECT().
 		CreateRequest(mock.Anything, mock.Anything).
 		Return(errors.New("db error"))
+	repo.EXPECT().DecrementFreeAnalysesUsed(mock.Anything, userID).Return(nil).Once()
 
-	_, err := svc.SubmitECG(ctx, uuid.New(), "https://example.com/ekg.jpg", ECGParams{})
+	_, err := svc.SubmitECG(ctx, userID, "https://example.com/ekg.jpg", DefaultECGParams())
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "create request")
 }
@@ -78,16 +95,24 @@ func TestSubmitEKG_CreateRequestFails(t *testing.T) {
 func TestSubmitEKG_EnqueueFails(t *testing.T) {
 	svc, repo, queue, _ := newSubmissionService(t)
 	ctx := context.Background()
+	svc.freeLimit = 3
+	userID := uuid.New()
+	repo.EXPECT().GetSubscriptionExpiresAt(mock.Anything, userID).Return(nil, nil)
+	repo.EXPECT().IncrementFreeAnalysesUsed(mock.Anything, userID).Return(1, nil)
 
 	repo.EXPECT().
 		CreateRequest(mock.Anything, mock.Anything).
 		Return(nil)
 
 	queue.EXPECT().
-		Enqueue(mock.Anything, mock.Anything).
+		TryEnqueue(mock.Anything, mock.Anything).
 		Return(uuid.Nil, errors.New("queue full"))
+	// The request must not stay pending and hold an active-request slot.
+	repo.EXPECT().UpdateRequestStatus(mock.Anything, mock.Anything, models.StatusFailed).Return(nil)
+	// The free analysis is refunded since nothing was queued.
+	repo.EXPECT().DecrementFreeAnalysesUsed(mock.Anything, userID).Return(nil).Once()
 
-	_, err := svc.SubmitECG(ctx, uuid.New(), "https://example.com/ekg.jpg", ECGParams{})
+	_, err := svc.SubmitECG(ctx, userID, "https://example.com/ekg.jpg", DefaultECGParams())
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "enqueue EKG job")
 }
@@ -117,8 +142,9 @@ func TestSubmitECGFile_Success(t *testing.T) {
 		Return(nil)
 
 	queue.EXPECT().
-		Enqueue(mock.Anything, mock.Anything).
+		TryEnqueue(mock.Anything, mock.Anything).
 		Return(jobID, nil)
+	repo.EXPECT().MarkRequestQueued(mock.Anything, mock.Anything).Return(nil)
 
 	file := UploadedFile{
 		Reader:      bytes.NewReader([]byte("image data")),
@@ -127,12 +153,50 @@ func TestSubmitECGFile_Success(t *testing.T) {
 		Size:        10,
 	}
 
-	result, err := svc.SubmitECGFile(ctx, userID, file, ECGParams{})
+	result, err := svc.SubmitECGFile(ctx, userID, file, DefaultECGParams())
 	require.NoError(t, err)
 	assert.Equal(t, jobID, result.JobID)
 	assert.NotEqual(t, uuid.Nil, result.RequestID)
 }
 
+func TestSubmitECGFile_EnqueueFailureMarksRequestFailed(t *testing.T) {
+	svc, repo, queue, store := newSubmissionService(t)
+	ctx := context.Background()
+	svc.freeLimit = 3
+	userID := uuid.New()
+	repo.EXPECT().GetSubscriptionExpiresAt(mock.Anything, userID).Return(nil, nil)
+	repo.EXPECT().IncrementFreeAnalysesUsed(mock.Anything, userID).Return(1, nil)
+
+	store.EXPECT().
+		UploadFile(mock.Anything, "ekg.jpg", mock.Anything, "image/jpeg").
+		Return(&storage.UploadResult{Key: "uploads/ekg.jpg"}, nil)
+	var requestID uuid.UUID
+	repo.EXPECT().
+		CreateRequest(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, r *models.Request) { requestID = r.ID }).
+		Return(nil)
+	repo.EXPECT().CreateFile(mock.Anything, mock.Anything).Return(nil)
+	queue.EXPECT().TryEnqueue(mock.Anything, mock.Anything).Return(uuid.Nil, job.ErrQueueFull)
+	repo.EXPECT().
+		UpdateRequestStatus(mock.Anything, mock.Anything, models.StatusFailed).
+		Run(func(_ context.Context, id uuid.UUID, _ string) {
+			assert.Equal(t, requestID, id)
+		}).
+		Return(nil)
+	// The free analysis is refunded since nothing was queued.
+	repo.EXPECT().DecrementFreeAnalysesUsed(mock.Anything, userID).Return(nil).Once()
+
+	file := UploadedFile{
+		Reader:      bytes.NewReader([]byte("image data")),
+		Filename:    "ekg.jpg",
+		ContentType: "image/jpeg",
+		Size:        10,
+	}
+
+	_, err := svc.SubmitECGFile(ctx, userID, file, DefaultECGParams())
+	require.ErrorIs(t, err, job.ErrQueueFull)
+}
+
 func TestSubmitECGFile_UploadFails(t *testing.T) {
 	svc, _, _, store := newSubmissionService(t)
 	ctx := context.Background()
@@ -148,7 +212,7 @@ func TestSubmitECGFile_UploadFails(t *testing.T) {
 		Size:        4,
 	}
 
-	_, err := svc.SubmitECGFile(ctx, uuid.New(), file, ECGParams{})
+	_, err := svc.SubmitECGFile(ctx, uuid.New(), file, DefaultECGParams())
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "upload EKG image")
 }
@@ -156,6 +220,10 @@ func TestSubmitECGFile_UploadFails(t *testing.T) {
 func TestSubmitECGFile_CreateRequestFails(t *testing.T) {
 	svc, repo, _, store := newSubmissionService(t)
 	ctx := context.Background()
+	svc.freeLimit = 3
+	userID := uuid.New()
+	repo.EXPECT().GetSubscriptionExpiresAt(mock.Anything, userID).Return(nil, nil)
+	repo.EXPECT().IncrementFreeAnalysesUsed(mock.Anything, userID).Return(1, nil)
 
 	store.EXPECT().
 		UploadFile(mock.Anything, "ekg.jpg", mock.Anything, "image/jpeg").
@@ -164,6 +232,9 @@ func TestSubmitECGFile_CreateRequestFails(t *testing.T) {
 	repo.EXPECT().
 		CreateRequest(mock.Anything, mock.Anything).
 		Return(errors.New("db error"))
+	// Nothing was stored: the upload is removed and the free analysis given back.
+	store.EXPECT().DeleteFile(mock.Anything, "uploads/ekg.jpg").Return(nil)
+	repo.EXPECT().DecrementFreeAnalysesUsed(mock.Anything, userID).Return(nil).Once()
 
 	file := UploadedFile{
 		Reader:      bytes.NewReader([]byte("data")),
@@ -172,11 +243,119 @@ func TestSubmitECGFile_CreateRequestFails(t *testing.T) {
 		Size:        4,
 	}
 
-	_, err := svc.SubmitECGFile(ctx, uuid.New(), file, ECGParams{})
+	_, err := svc.SubmitECGFile(ctx, userID, file, DefaultECGParams())
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "create request")
 }
 
+func TestSubmitECGFile_CreateFileFails(t *testing.T) {
+	svc, repo, _, store := newSubmissionService(t)
+	ctx := context.Background()
+	svc.freeLimit = 3
+	userID := uuid.New()
+	repo.EXPECT().GetSubscriptionExpiresAt(mock.Anything, userID).Return(nil, nil)
+	repo.EXPECT().IncrementFreeAnalysesUsed(mock.Anything, userID).Return(1, nil)
+
+	store.EXPECT().
+		UploadFile(mock.Anything, "ekg.jpg", mock.Anything, "image/jpeg").
+		Return(&storage.UploadResult{Key: "uploads/ekg.jpg"}, nil)
+	repo.EXPECT().CreateRequest(mock.Anything, mock.Anything).Return(nil)
+	repo.EXPECT().CreateFile(mock.Anything, mock.Anything).Return(errors.New("db error"))
+	store.EXPECT().DeleteFile(mock.Anything, "uploads/ekg.jpg").Return(nil)
+	repo.EXPECT().DecrementFreeAnalysesUsed(mock.Anything, userID).Return(nil).Once()
+
+	file := UploadedFile{
+		Reader:      bytes.NewReader([]byte("data")),
+		Filename:    "ekg.jpg",
+		ContentType: "image/jpeg",
+		Size:        4,
+	}
+
+	_, err := svc.SubmitECGFile(ctx, userID, file, DefaultECGParams())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "create file record")
+}
+
+// --- SubmitECGBatch ---
+
+func TestSubmitECGBatch_CreatesChildRequestsPerItem(t *testing.T) {
+	svc, repo, queue, _ := newSubmissionService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	var batchID uuid.UUID
+	repo.EXPECT().
+		CreateECGBatch(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, b *models.ECGBatch) {
+			assert.Equal(t, userID, b.UserID)
+			batchID = b.ID
+		}).
+		Return(nil)
+	repo.EXPECT().
+		CreateRequest(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, req *models.Request) {
+			require.NotNil(t, req.BatchID)
+			assert.Equal(t, batchID, *req.BatchID)
+		}).
+		Return(nil).Times(2)
+	queue.EXPECT().
+		TryEnqueue(mock.Anything, mock.Anything).
+		Return(uuid.New(), nil).Times(2)
+	repo.EXPECT().MarkRequestQueued(mock.Anything, mock.Anything).Return(nil).Times(2)
+
+	items := []ECGBatchItem{
+		{ImageURL: "https://example.com/a.jpg"},
+		{ImageURL: ""},
+		{ImageURL: "https://example.com/c.jpg"},
+	}
+	result, err := svc.SubmitECGBatch(ctx, userID, items, DefaultECGParams())
+	require.NoError(t, err)
+	assert.Equal(t, batchID, result.BatchID)
+	require.Len(t, result.Items, 3)
+	assert.NotNil(t, result.Items[0].Job)
+	assert.ErrorIs(t, result.Items[1].Err, apperr.ErrValidation)
+	assert.NotNil(t, result.Items[2].Job)
+}
+
+func TestSubmitECGBatch_RejectsOversizedBatch(t *testing.T) {
+	svc, _, _, _ := newSubmissionService(t)
+
+	items := make([]ECGBatchItem, MaxECGBatchSize+1)
+	_, err := svc.SubmitECGBatch(context.Background(), uuid.New(), items, DefaultECGParams())
+	assert.ErrorIs(t, err, apperr.ErrValidation)
+
+	_, err = svc.SubmitECGBatch(context.Background(), uuid.New(), nil, DefaultECGParams())
+	assert.ErrorIs(t, err, apperr.ErrValidation)
+}
+
+func TestECGParams_Validate(t *testing.T) {
+	age := 200
+	tests := map[string]func(p *ECGParams){
+		"age":         func(p *ECGParams) { p.Age = &age },
+		"sex":         func(p *ECGParams) { p.Sex = "other" },
+		"paper speed": func(p *ECGParams) { p.PaperSpeedMMS = 5 },
+		"limb gain":   func(p *ECGParams) { p.MmPerMvLimb = 41 },
+		"chest gain":  func(p *ECGParams) { p.MmPerMvChest = 0 },
+	}
+	require.NoError(t, DefaultECGParams().Validate())
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := DefaultECGParams()
+			mutate(&p)
+			assert.ErrorIs(t, p.Validate(), apperr.ErrValidation)
+		})
+	}
+}
+
+func TestSubmitECGFile_InvalidParams(t *testing.T) {
+	svc, _, _, _ := newSubmissionService(t)
+
+	params := DefaultECGParams()
+	params.Sex = "other"
+	_, err := svc.SubmitECGFile(context.Background(), uuid.New(), UploadedFile{}, params)
+	assert.ErrorIs(t, err, apperr.ErrValidation)
+}
+
 // --- SubmitGPT ---
 
 func TestSubmitGPT_Success(t *testing.T) {
@@ -185,6 +364,7 @@ func TestSubmitGPT_Success(t *testing.T) {
 	userID := uuid.New()
 	jobID := uuid.New()
 
+	expectTx(repo)
 	repo.EXPECT().
 		CreateRequest(mock.Anything, mock.Anything).
 		Return(nil)
@@ -194,12 +374,13 @@ func TestSubmitGPT_Success(t *testing.T) {
 		Return(&storage.UploadResult{Key: "files/test.pdf", URL: "https://s3/files/test.pdf"}, nil)
 
 	repo.EXPECT().
-		CreateFile(mock.Anything, mock.Anything).
+		CreateFiles(mock.Anything, mock.Anything).
 		Return(nil)
 
 	queue.EXPECT().
-		Enqueue(mock.Anything, mock.Anything).
+		TryEnqueue(mock.Anything, mock.Anything).
 		Return(jobID, nil)
+	repo.EXPECT().MarkRequestQueued(mock.Anything, mock.Anything).Return(nil)
 
 	files := []UploadedFile{
 		{
@@ -210,47 +391,82 @@ func TestSubmitGPT_Success(t *testing.T) {
 		},
 	}
 
-	result, err := svc.SubmitGPT(ctx, userID, "analyze this", files)
+	result, err := svc.SubmitGPT(ctx, userID, "analyze this", files, GPTOptions{})
 	require.NoError(t, err)
 	assert.Equal(t, jobID, result.JobID)
 	assert.Equal(t, 1, result.FilesProcessed)
 	assert.Empty(t, result.UploadErrors)
 }
 
-func TestSubmitGPT_NoFiles(t *testing.T) {
-	svc, repo, _, _ := newSubmissionService(t)
-	ctx := context.Background()
+func TestSubmitGPT_PassesTimeoutOverride(t *testing.T) {
+	svc, repo, queue, store := newSubmissionService(t)
 
-	repo.EXPECT().
-		CreateRequest(mock.Anything, mock.Anything).
-		Return(nil)
+	expectTx(repo)
+	repo.EXPECT().CreateRequest(mock.Anything, mock.Anything).Return(nil)
+	store.EXPECT().
+		UploadFile(mock.Anything, "test.pdf", mock.Anything, "application/pdf").
+		Return(&storage.UploadResult{Key: "files/test.pdf"}, nil)
+	repo.EXPECT().CreateFiles(mock.Anything, mock.Anything).Return(nil)
+	queue.EXPECT().
+		TryEnqueue(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, j *job.Job) {
+			var p gpt.JobPayload
+			require.NoError(t, json.Unmarshal(j.Payload, &p))
+			assert.Equal(t, 90000, p.TimeoutMs)
+		}).
+		Return(uuid.New(), nil)
+	repo.EXPECT().MarkRequestQueued(mock.Anything, mock.Anything).Return(nil)
 
-	repo.EXPECT().
-		UpdateRequestStatus(mock.Anything, mock.Anything, models.StatusFailed).
-		Return(nil)
+	files := []UploadedFile{{Reader: bytes.NewReader([]byte("pdf content")), Filename: "test.pdf", ContentType: "application/pdf", Size: 11}}
+	_, err := svc.SubmitGPT(context.Background(), uuid.New(), "", files, GPTOptions{Timeout: 90 * time.Second})
+	require.NoError(t, err)
+}
+
+func TestSubmitGPT_PassesImageDetailAndMaxTokens(t *testing.T) {
+	svc, repo, queue, store := newSubmissionService(t)
+
+	expectTx(repo)
+	repo.EXPECT().CreateRequest(mock.Anything, mock.Anything).Return(nil)
+	store.EXPECT().
+		UploadFile(mock.Anything, "test.pdf", mock.Anything, "application/pdf").
+		Return(&storage.UploadResult{Key: "files/test.pdf"}, nil)
+	repo.EXPECT().CreateFiles(mock.Anything, mock.Anything).Return(nil)
+	queue.EXPECT().
+		TryEnqueue(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, j *job.Job) {
+			var p gpt.JobPayload
+			require.NoError(t, json.Unmarshal(j.Payload, &p))
+			assert.Equal(t, openai.ImageURLDetailLow, p.ImageDetail)
+			assert.Equal(t, 4000, p.MaxTokens)
+		}).
+		Return(uuid.New(), nil)
+	repo.EXPECT().MarkRequestQueued(mock.Anything, mock.Anything).Return(nil)
+
+	files := []UploadedFile{{Reader: bytes.NewReader([]byte("pdf content")), Filename: "test.pdf", ContentType: "application/pdf", Size: 11}}
+	_, err := svc.SubmitGPT(context.Background(), uuid.New(), "", files,
+		GPTOptions{ImageDetail: openai.ImageURLDetailLow, MaxTokens: 4000})
+	require.NoError(t, err)
+}
+
+func TestSubmitGPT_NoFiles(t *testing.T) {
+	svc, _, _, _ := newSubmissionService(t)
+	ctx := context.Background()
 
-	result, err := svc.SubmitGPT(ctx, uuid.New(), "query", nil)
+	// Nothing is stored when no file can be processed.
+	result, err := svc.SubmitGPT(ctx, uuid.New(), "query", nil, GPTOptions{})
 	require.Error(t, err)
 	require.ErrorIs(t, err, apperr.ErrValidation)
 	assert.NotNil(t, result)
 }
 
 func TestSubmitGPT_AllUploadsFail(t *testing.T) {
-	svc, repo, _, store := newSubmissionService(t)
+	svc, _, _, store := newSubmissionService(t)
 	ctx := context.Background()
 
-	repo.EXPECT().
-		CreateRequest(mock.Anything, mock.Anything).
-		Return(nil)
-
 	store.EXPECT().
 		UploadFile(mock.Anything, "bad.pdf", mock.Anything, "application/pdf").
 		Return(nil, errors.New("storage error"))
 
-	repo.EXPECT().
-		UpdateRequestStatus(mock.Anything, mock.Anything, models.StatusFailed).
-		Return(nil)
-
 	files := []UploadedFile{
 		{
 			Reader:      bytes.NewReader([]byte("content")),
@@ -260,7 +476,7 @@ func TestSubmitGPT_AllUploadsFail(t *testing.T) {
 		},
 	}
 
-	result, err := svc.SubmitGPT(ctx, uuid.New(), "query", files)
+	result, err := svc.SubmitGPT(ctx, uuid.New(), "query", files, GPTOptions{})
 	require.Error(t, err)
 	require.ErrorIs(t, err, apperr.ErrValidation)
 	assert.Len(t, result.UploadErrors, 1)
@@ -271,6 +487,7 @@ func TestSubmitGPT_PartialUploadFailure(t *testing.T) {
 	ctx := context.Background()
 	userID := uuid.New()
 
+	expectTx(repo)
 	repo.EXPECT().
 		CreateRequest(mock.Anything, mock.Anything).
 		Return(nil)
@@ -281,7 +498,7 @@ func TestSubmitGPT_PartialUploadFailure(t *testing.T) {
 		Return(&storage.UploadResult{Key: "files/good.pdf", URL: "https://s3/good.pdf"}, nil)
 
 	repo.EXPECT().
-		CreateFile(mock.Anything, mock.Anything).
+		CreateFiles(mock.Anything, mock.Anything).
 		Return(nil)
 
 	// Second file fails
@@ -290,15 +507,16 @@ func TestSubmitGPT_PartialUploadFailure(t *testing.T) {
 		Return(nil, errors.New("upload failed"))
 
 	queue.EXPECT().
-		Enqueue(mock.Anything, mock.Anything).
+		TryEnqueue(mock.Anything, mock.Anything).
 		Return(uuid.New(), nil)
+	repo.EXPECT().MarkRequestQueued(mock.Anything, mock.Anything).Return(nil)
 
 	files := []UploadedFile{
 		{Reader: bytes.NewReader([]byte("ok")), Filename: "good.pdf", ContentType: "application/pdf", Size: 2},
 		{Reader: bytes.NewReader([]byte("bad")), Filename: "bad.pdf", ContentType: "application/pdf", Size: 3},
 	}
 
-	result, err := svc.SubmitGPT(ctx, userID, "query", files)
+	result, err := svc.SubmitGPT(ctx, userID, "query", files, GPTOptions{})
 	require.NoError(t, err)
 	assert.Equal(t, 1, result.FilesProcessed)
 	assert.Len(t, result.UploadErrors, 1)
@@ -309,6 +527,7 @@ func TestSubmitGPT_ContentTypeDetection(t *testing.T) {
 	svc, repo, queue, store := newSubmissionService(t)
 	ctx := context.Background()
 
+	expectTx(repo)
 	repo.EXPECT().
 		CreateRequest(mock.Anything, mock.Anything).
 		Return(nil)
@@ -319,12 +538,13 @@ func TestSubmitGPT_ContentTypeDetection(t *testing.T) {
 		Return(&storage.UploadResult{Key: "files/image.bin", URL: "https://s3/image.bin"}, nil)
 
 	repo.EXPECT().
-		CreateFile(mock.Anything, mock.Anything).
+		CreateFiles(mock.Anything, mock.Anything).
 		Return(nil)
 
 	queue.EXPECT().
-		Enqueue(mock.Anything, mock.Anything).
+		TryEnqueue(mock.Anything, mock.Anything).
 		Return(uuid.New(), nil)
+	repo.EXPECT().MarkRequestQueued(mock.Anything, mock.Anything).Return(nil)
 
 	// PNG header bytes for content type detection
 	pngHeader := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
@@ -332,24 +552,514 @@ func TestSubmitGPT_ContentTypeDetection(t *testing.T) {
 		{Reader: bytes.NewReader(pngHeader), Filename: "image.bin", ContentType: "", Size: int64(len(pngHeader))},
 	}
 
-	result, err := svc.SubmitGPT(ctx, uuid.New(), "query", files)
+	result, err := svc.SubmitGPT(ctx, uuid.New(), "query", files, GPTOptions{})
 	require.NoError(t, err)
 	assert.Equal(t, 1, result.FilesProcessed)
 }
 
 func TestSubmitGPT_CreateRequestFails(t *testing.T) {
-	svc, repo, _, _ := newSubmissionService(t)
+	svc, repo, _, store := newSubmissionService(t)
 	ctx := context.Background()
 
+	store.EXPECT().
+		UploadFile(mock.Anything, "f.pdf", mock.Anything, "application/pdf").
+		Return(&storage.UploadResult{Key: "files/f.pdf"}, nil)
+	expectTx(repo)
 	repo.EXPECT().
 		CreateRequest(mock.Anything, mock.Anything).
 		Return(errors.New("db error"))
+	// The upload is removed when nothing was stored.
+	store.EXPECT().DeleteFile(mock.Anything, "files/f.pdf").Return(nil)
 
 	files := []UploadedFile{
 		{Reader: bytes.NewReader([]byte("x")), Filename: "f.pdf", ContentType: "application/pdf", Size: 1},
 	}
 
-	_, err := svc.SubmitGPT(ctx, uuid.New(), "query", files)
+	_, err := svc.SubmitGPT(ctx, uuid.New(), "query", files, GPTOptions{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "create request")
 }
+
+func TestSubmitGPT_FileRecordFailureRollsBackAndCleansUploads(t *testing.T) {
+	svc, repo, _, store := newSubmissionService(t)
+	ctx := context.Background()
+
+	store.EXPECT().
+		UploadFile(mock.Anything, mock.Anything, mock.Anything, "application/pdf").
+		RunAndReturn(func(_ context.Context, name string, _ io.Reader, _ string) (*storage.UploadResult, error) {
+			return &storage.UploadResult{Key: "files/" + name}, nil
+		})
+	expectTx(repo)
+	repo.EXPECT().CreateRequest(mock.Anything, mock.Anything).Return(nil)
+	repo.EXPECT().CreateFiles(mock.Anything, mock.MatchedBy(func(f []*models.File) bool { return len(f) == 2 })).
+		Return(errors.New("db error"))
+	store.EXPECT().DeleteFile(mock.Anything, "files/a.pdf").Return(nil)
+	store.EXPECT().DeleteFile(mock.Anything, "files/b.pdf").Return(nil)
+
+	files := []UploadedFile{
+		{Reader: bytes.NewReader([]byte("a")), Filename: "a.pdf", ContentType: "application/pdf", Size: 1},
+		{Reader: bytes.NewReader([]byte("b")), Filename: "b.pdf", ContentType: "application/pdf", Size: 1},
+	}
+
+	_, err := svc.SubmitGPT(ctx, uuid.New(), "query", files, GPTOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "create file records")
+}
+
+func TestSubmitGPT_RolledBackSubmissionRefundsQuota(t *testing.T) {
+	svc, repo, _, store := newSubmissionService(t)
+	svc.freeLimit = 3
+	userID := uuid.New()
+	repo.EXPECT().GetSubscriptionExpiresAt(mock.Anything, userID).Return(nil, nil)
+	repo.EXPECT().IncrementFreeAnalysesUsed(mock.Anything, userID).Return(1, nil)
+
+	store.EXPECT().
+		UploadFile(mock.Anything, "f.pdf", mock.Anything, "application/pdf").
+		Return(&storage.UploadResult{Key: "files/f.pdf"}, nil)
+	repo.EXPECT().RunTx(mock.Anything, mock.Anything).Return(errors.New("db error"))
+	store.EXPECT().DeleteFile(mock.Anything, "files/f.pdf").Return(nil)
+	// Nothing was stored, so the free analysis is given back.
+	repo.EXPECT().DecrementFreeAnalysesUsed(mock.Anything, userID).Return(nil).Once()
+
+	files := []UploadedFile{
+		{Reader: bytes.NewReader([]byte("x")), Filename: "f.pdf", ContentType: "application/pdf", Size: 1},
+	}
+
+	_, err := svc.SubmitGPT(context.Background(), userID, "query", files, GPTOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "store GPT submission")
+}
+
+func TestSubmitGPT_AllUploadsFailRefundsQuota(t *testing.T) {
+	svc, repo, _, store := newSubmissionService(t)
+	svc.freeLimit = 3
+	userID := uuid.New()
+	repo.EXPECT().GetSubscriptionExpiresAt(mock.Anything, userID).Return(nil, nil)
+	repo.EXPECT().IncrementFreeAnalysesUsed(mock.Anything, userID).Return(1, nil)
+
+	store.EXPECT().
+		UploadFile(mock.Anything, "bad.pdf", mock.Anything, "application/pdf").
+		Return(nil, errors.New("storage error"))
+	repo.EXPECT().DecrementFreeAnalysesUsed(mock.Anything, userID).Return(nil).Once()
+
+	files := []UploadedFile{
+		{Reader: bytes.NewReader([]byte("x")), Filename: "bad.pdf", ContentType: "application/pdf", Size: 1},
+	}
+
+	_, err := svc.SubmitGPT(context.Background(), userID, "query", files, GPTOptions{})
+	require.ErrorIs(t, err, apperr.ErrValidation)
+}
+
+func TestSubmitGPT_EnqueueFailureMarksRequestFailed(t *testing.T) {
+	svc, repo, queue, store := newSubmissionService(t)
+	ctx := context.Background()
+	svc.freeLimit = 3
+	userID := uuid.New()
+	repo.EXPECT().GetSubscriptionExpiresAt(mock.Anything, userID).Return(nil, nil)
+	repo.EXPECT().IncrementFreeAnalysesUsed(mock.Anything, userID).Return(1, nil)
+
+	store.EXPECT().
+		UploadFile(mock.Anything, "f.pdf", mock.Anything, "application/pdf").
+		Return(&storage.UploadResult{Key: "files/f.pdf"}, nil)
+	expectTx(repo)
+	repo.EXPECT().CreateRequest(mock.Anything, mock.Anything).Return(nil)
+	repo.EXPECT().CreateFiles(mock.Anything, mock.Anything).Return(nil)
+	queue.EXPECT().TryEnqueue(mock.Anything, mock.Anything).Return(uuid.Nil, job.ErrQueueFull)
+	repo.EXPECT().UpdateRequestStatus(mock.Anything, mock.Anything, models.StatusFailed).Return(nil)
+	// The free analysis is refunded since nothing was queued.
+	repo.EXPECT().DecrementFreeAnalysesUsed(mock.Anything, userID).Return(nil).Once()
+
+	files := []UploadedFile{
+		{Reader: bytes.NewReader([]byte("x")), Filename: "f.pdf", ContentType: "application/pdf", Size: 1},
+	}
+
+	_, err := svc.SubmitGPT(ctx, userID, "query", files, GPTOptions{})
+	require.Error(t, err)
+}
+
+// --- RetryGPT ---
+
+func TestRetryGPT_ReenqueuesStoredFiles(t *testing.T) {
+	svc, repo, queue, _ := newSubmissionService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	requestID := uuid.New()
+	jobID := uuid.New()
+	query := "what rhythm?"
+
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, requestID).
+		Return(&models.Request{ID: requestID, UserID: userID, Status: models.StatusFailed, TextQuery: &query}, nil)
+	repo.EXPECT().
+		GetFileKeysByRequestID(mock.Anything, requestID).
+		Return([]string{"uploads/a.png", "uploads/b.png"}, nil)
+	repo.EXPECT().
+		TransitionRequestStatus(mock.Anything, requestID, models.StatusFailed, models.StatusPending).
+		Return(true, nil)
+	queue.EXPECT().
+		TryEnqueue(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, j *job.Job) {
+			var p gpt.JobPayload
+			require.NoError(t, json.Unmarshal(j.Payload, &p))
+			assert.Equal(t, requestID, p.RequestID)
+			assert.Equal(t, query, p.TextQuery)
+			assert.Equal(t, []string{"uploads/a.png", "uploads/b.png"}, p.FileKeys)
+		}).
+		Return(jobID, nil)
+	repo.EXPECT().MarkRequestQueued(mock.Anything, requestID).Return(nil)
+
+	result, err := svc.RetryGPT(ctx, requestID, userClaims(userID))
+	require.NoError(t, err)
+	assert.Equal(t, jobID, result.JobID)
+	assert.Equal(t, models.StatusQueued, result.Status)
+	assert.Equal(t, 2, result.FilesProcessed)
+}
+
+func TestRetryGPT_RejectsNonFailedRequests(t *testing.T) {
+	speed := 25.0
+	tests := []struct {
+		name string
+		req  models.Request
+	}{
+		{"completed", models.Request{Status: models.StatusCompleted}},
+		{"processing", models.Request{Status: models.StatusProcessing}},
+		{"failed EKG", models.Request{Status: models.StatusFailed, ECGPaperSpeedMMS: &speed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, _, _ := newSubmissionService(t)
+			userID := uuid.New()
+			tt.req.UserID = userID
+
+			repo.EXPECT().GetRequestByID(mock.Anything, mock.Anything).Return(&tt.req, nil)
+
+			_, err := svc.RetryGPT(context.Background(), uuid.New(), userClaims(userID))
+			assert.ErrorIs(t, err, ErrNotRetryable)
+		})
+	}
+}
+
+func TestRetryGPT_Forbidden(t *testing.T) {
+	svc, repo, _, _ := newSubmissionService(t)
+
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, mock.Anything).
+		Return(&models.Request{UserID: uuid.New(), Status: models.StatusFailed}, nil)
+
+	_, err := svc.RetryGPT(context.Background(), uuid.New(), userClaims(uuid.New()))
+	assert.ErrorIs(t, err, apperr.ErrForbidden)
+}
+
+func TestRetryGPT_EnqueueFailureRestoresStatus(t *testing.T) {
+	svc, repo, queue, _ := newSubmissionService(t)
+	userID := uuid.New()
+	requestID := uuid.New()
+
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, requestID).
+		Return(&models.Request{ID: requestID, UserID: userID, Status: models.StatusFailed}, nil)
+	repo.EXPECT().GetFileKeysByRequestID(mock.Anything, requestID).Return([]string{"uploads/a.png"}, nil)
+	repo.EXPECT().TransitionRequestStatus(mock.Anything, requestID, models.StatusFailed, models.StatusPending).Return(true, nil)
+	queue.EXPECT().TryEnqueue(mock.Anything, mock.Anything).Return(uuid.Nil, job.ErrQueueFull)
+	repo.EXPECT().UpdateRequestStatus(mock.Anything, requestID, models.StatusFailed).Return(nil)
+
+	_, err := svc.RetryGPT(context.Background(), requestID, userClaims(userID))
+	assert.ErrorIs(t, err, job.ErrQueueFull)
+}
+
+func TestRetryGPT_ConcurrentRetryLoses(t *testing.T) {
+	svc, repo, _, _ := newSubmissionService(t)
+	userID := uuid.New()
+	requestID := uuid.New()
+
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, requestID).
+		Return(&models.Request{ID: requestID, UserID: userID, Status: models.StatusFailed}, nil)
+	repo.EXPECT().GetFileKeysByRequestID(mock.Anything, requestID).Return([]string{"uploads/a.png"}, nil)
+	// Another retry moved the request to pending after it was loaded.
+	repo.EXPECT().
+		TransitionRequestStatus(mock.Anything, requestID, models.StatusFailed, models.StatusPending).
+		Return(false, nil)
+
+	_, err := svc.RetryGPT(context.Background(), requestID, userClaims(userID))
+	assert.ErrorIs(t, err, ErrNotRetryable)
+}
+
+// --- ReanalyzeGPT ---
+
+func TestReanalyzeGPT_EnqueuesWithOverrides(t *testing.T) {
+	svc, repo, queue, _ := newSubmissionService(t)
+	userID := uuid.New()
+	requestID := uuid.New()
+	jobID := uuid.New()
+	query := "what rhythm?"
+
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, requestID).
+		Return(&models.Request{ID: requestID, UserID: userID, Status: models.StatusCompleted, TextQuery: &query}, nil)
+	repo.EXPECT().GetFileKeysByRequestID(mock.Anything, requestID).Return([]string{"uploads/a.png"}, nil)
+	repo.EXPECT().TransitionRequestStatus(mock.Anything, requestID, models.StatusCompleted, models.StatusPending).Return(true, nil)
+	queue.EXPECT().
+		TryEnqueue(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, j *job.Job) {
+			var p gpt.JobPayload
+			require.NoError(t, json.Unmarshal(j.Payload, &p))
+			assert.Equal(t, job.TypeGPTProcess, j.Type)
+			assert.Equal(t, "gpt-4.1", p.Model)
+			assert.Equal(t, "check the QT interval", p.TextQuery)
+			assert.True(t, p.NoCache)
+		}).
+		Return(jobID, nil)
+	repo.EXPECT().MarkRequestQueued(mock.Anything, mock.Anything).Return(nil)
+
+	result, err := svc.ReanalyzeGPT(context.Background(), requestID, userClaims(userID),
+		ReanalyzeOptions{Model: "gpt-4.1", TextQuery: "check the QT interval"})
+	require.NoError(t, err)
+	assert.Equal(t, jobID, result.JobID)
+}
+
+func TestReanalyzeGPT_KeepsStoredQueryWithoutOverride(t *testing.T) {
+	svc, repo, queue, _ := newSubmissionService(t)
+	userID := uuid.New()
+	requestID := uuid.New()
+	query := "what rhythm?"
+
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, requestID).
+		Return(&models.Request{ID: requestID, UserID: userID, Status: models.StatusFailed, TextQuery: &query}, nil)
+	repo.EXPECT().GetFileKeysByRequestID(mock.Anything, requestID).Return([]string{"uploads/a.png"}, nil)
+	repo.EXPECT().TransitionRequestStatus(mock.Anything, requestID, models.StatusFailed, models.StatusPending).Return(true, nil)
+	queue.EXPECT().
+		TryEnqueue(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, j *job.Job) {
+			var p gpt.JobPayload
+			require.NoError(t, json.Unmarshal(j.Payload, &p))
+			assert.Equal(t, query, p.TextQuery)
+			assert.Empty(t, p.Model)
+		}).
+		Return(uuid.New(), nil)
+	repo.EXPECT().MarkRequestQueued(mock.Anything, mock.Anything).Return(nil)
+
+	_, err := svc.ReanalyzeGPT(context.Background(), requestID, userClaims(userID), ReanalyzeOptions{})
+	require.NoError(t, err)
+}
+
+func TestReanalyzeGPT_RejectsUnfinishedAndEKGRequests(t *testing.T) {
+	speed := 25.0
+	tests := []struct {
+		name string
+		req  models.Request
+	}{
+		{"pending", models.Request{Status: models.StatusPending}},
+		{"processing", models.Request{Status: models.StatusProcessing}},
+		{"completed EKG", models.Request{Status: models.StatusCompleted, ECGPaperSpeedMMS: &speed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, _, _ := newSubmissionService(t)
+			userID := uuid.New()
+			tt.req.UserID = userID
+
+			repo.EXPECT().GetRequestByID(mock.Anything, mock.Anything).Return(&tt.req, nil)
+
+			_, err := svc.ReanalyzeGPT(context.Background(), uuid.New(), userClaims(userID), ReanalyzeOptions{})
+			assert.ErrorIs(t, err, ErrNotReanalyzable)
+		})
+	}
+}
+
+func TestReanalyzeGPT_EnqueueFailureRestoresCompletedStatus(t *testing.T) {
+	svc, repo, queue, _ := newSubmissionService(t)
+	userID := uuid.New()
+	requestID := uuid.New()
+
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, requestID).
+		Return(&models.Request{ID: requestID, UserID: userID, Status: models.StatusCompleted}, nil)
+	repo.EXPECT().GetFileKeysByRequestID(mock.Anything, requestID).Return([]string{"uploads/a.png"}, nil)
+	repo.EXPECT().TransitionRequestStatus(mock.Anything, requestID, models.StatusCompleted, models.StatusPending).Return(true, nil)
+	queue.EXPECT().TryEnqueue(mock.Anything, mock.Anything).Return(uuid.Nil, job.ErrQueueFull)
+	repo.EXPECT().UpdateRequestStatus(mock.Anything, requestID, models.StatusCompleted).Return(nil)
+
+	_, err := svc.ReanalyzeGPT(context.Background(), requestID, userClaims(userID), ReanalyzeOptions{})
+	assert.ErrorIs(t, err, job.ErrQueueFull)
+}
+
+func TestReanalyzeGPT_CompletedRequestRefusedWhenFreeQuotaExhausted(t *testing.T) {
+	svc, repo, _, _ := newSubmissionService(t)
+	svc.freeLimit = 3
+	userID := uuid.New()
+	requestID := uuid.New()
+
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, requestID).
+		Return(&models.Request{ID: requestID, UserID: userID, Status: models.StatusCompleted}, nil)
+	repo.EXPECT().GetFileKeysByRequestID(mock.Anything, requestID).Return([]string{"uploads/a.png"}, nil)
+	repo.EXPECT().GetSubscriptionExpiresAt(mock.Anything, userID).Return(nil, nil)
+	repo.EXPECT().IncrementFreeAnalysesUsed(mock.Anything, userID).Return(4, nil)
+	repo.EXPECT().DecrementFreeAnalysesUsed(mock.Anything, userID).Return(nil).Once()
+
+	_, err := svc.ReanalyzeGPT(context.Background(), requestID, userClaims(userID), ReanalyzeOptions{Model: "gpt-4.1"})
+	assert.ErrorIs(t, err, apperr.ErrPaymentRequired)
+}
+
+func TestReanalyzeGPT_EnqueueFailureRefundsQuota(t *testing.T) {
+	svc, repo, queue, _ := newSubmissionService(t)
+	svc.freeLimit = 3
+	userID := uuid.New()
+	requestID := uuid.New()
+
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, requestID).
+		Return(&models.Request{ID: requestID, UserID: userID, Status: models.StatusCompleted}, nil)
+	repo.EXPECT().GetFileKeysByRequestID(mock.Anything, requestID).Return([]string{"uploads/a.png"}, nil)
+	repo.EXPECT().GetSubscriptionExpiresAt(mock.Anything, userID).Return(nil, nil)
+	repo.EXPECT().IncrementFreeAnalysesUsed(mock.Anything, userID).Return(2, nil)
+	repo.EXPECT().TransitionRequestStatus(mock.Anything, requestID, models.StatusCompleted, models.StatusPending).Return(true, nil)
+	queue.EXPECT().TryEnqueue(mock.Anything, mock.Anything).Return(uuid.Nil, job.ErrQueueFull)
+	repo.EXPECT().UpdateRequestStatus(mock.Anything, requestID, models.StatusCompleted).Return(nil)
+	repo.EXPECT().DecrementFreeAnalysesUsed(mock.Anything, userID).Return(nil).Once()
+
+	_, err := svc.ReanalyzeGPT(context.Background(), requestID, userClaims(userID), ReanalyzeOptions{})
+	assert.ErrorIs(t, err, job.ErrQueueFull)
+}
+
+func TestReanalyzeGPT_FailedRequestNotCharged(t *testing.T) {
+	svc, repo, queue, _ := newSubmissionService(t)
+	svc.freeLimit = 3
+	userID := uuid.New()
+	requestID := uuid.New()
+
+	// No subscription or free-analysis calls are expected.
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, requestID).
+		Return(&models.Request{ID: requestID, UserID: userID, Status: models.StatusFailed}, nil)
+	repo.EXPECT().GetFileKeysByRequestID(mock.Anything, requestID).Return([]string{"uploads/a.png"}, nil)
+	repo.EXPECT().TransitionRequestStatus(mock.Anything, requestID, models.StatusFailed, models.StatusPending).Return(true, nil)
+	queue.EXPECT().TryEnqueue(mock.Anything, mock.Anything).Return(uuid.New(), nil)
+	repo.EXPECT().MarkRequestQueued(mock.Anything, requestID).Return(nil)
+
+	_, err := svc.ReanalyzeGPT(context.Background(), requestID, userClaims(userID), ReanalyzeOptions{})
+	require.NoError(t, err)
+}
+
+// --- Token budget ---
+
+func newBudgetedSubmissionService(t *testing.T, quota config.QuotaConfig) (*submissionService, *repomocks.MockStore) {
+	repo := repomocks.NewMockStore(t)
+	svc := NewSubmissionService(repo, jobmocks.NewMockQueue(t), storagemocks.NewMockStorage(t), quota).(*submissionService)
+	return svc, repo
+}
+
+func TestSubmitGPT_TokenBudgetExceeded(t *testing.T) {
+	svc, repo := newBudgetedSubmissionService(t, config.QuotaConfig{
+		Tokens: config.TokenBudget{Daily: 1000},
+	})
+	userID := uuid.New()
+	ctx := auth.NewContext(context.Background(), userClaims(userID))
+
+	repo.EXPECT().
+		SumUserTokens(mock.Anything, userID, mock.Anything).
+		Return(1000, nil)
+
+	_, err := svc.SubmitGPT(ctx, userID, "query", nil, GPTOptions{})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, apperr.ErrQuotaExceeded)
+}
+
+func TestCheckTokenBudget_ReturnsTightestRemaining(t *testing.T) {
+	svc, repo := newBudgetedSubmissionService(t, config.QuotaConfig{
+		Tokens: config.TokenBudget{Daily: 1000, Monthly: 5000},
+	})
+	userID := uuid.New()
+	ctx := auth.NewContext(context.Background(), userClaims(userID))
+
+	// Daily window is checked first, then monthly.
+	repo.EXPECT().
+		SumUserTokens(mock.Anything, userID, mock.Anything).
+		Return(300, nil).Once()
+	repo.EXPECT().
+		SumUserTokens(mock.Anything, userID, mock.Anything).
+		Return(4800, nil).Once()
+
+	remaining, err := svc.checkTokenBudget(ctx, userID)
+	require.NoError(t, err)
+	require.NotNil(t, remaining)
+	assert.Equal(t, 200, *remaining)
+}
+
+func TestCheckTokenBudget_AdminExempt(t *testing.T) {
+	svc, _ := newBudgetedSubmissionService(t, config.QuotaConfig{
+		Tokens: config.TokenBudget{Daily: 1},
+	})
+	userID := uuid.New()
+	ctx := auth.NewContext(context.Background(), &auth.Claims{
+		UserID: userID.String(),
+		Roles:  []string{auth.RoleAdmin},
+	})
+
+	remaining, err := svc.checkTokenBudget(ctx, userID)
+	require.NoError(t, err)
+	assert.Nil(t, remaining)
+}
+
+func TestCheckTokenBudget_UnlimitedByDefault(t *testing.T) {
+	svc, _, _, _ := newSubmissionService(t)
+
+	remaining, err := svc.checkTokenBudget(context.Background(), uuid.New())
+	require.NoError(t, err)
+	assert.Nil(t, remaining)
+}
+
+func TestEffectiveTokenBudget_RoleOverrides(t *testing.T) {
+	def := config.TokenBudget{Daily: 100, Monthly: 1000}
+	overrides := map[string]config.TokenBudget{
+		"doctor":  {Daily: 500, Monthly: 0},
+		"premium": {Daily: 800, Monthly: 20000},
+	}
+
+	assert.Equal(t, def, effectiveTokenBudget(def, overrides, []string{auth.RoleUser}))
+	assert.Equal(t, config.TokenBudget{Daily: 500}, effectiveTokenBudget(def, overrides, []string{"doctor"}))
+	assert.Equal(t, config.TokenBudget{Daily: 800, Monthly: 0},
+		effectiveTokenBudget(def, overrides, []string{"doctor", "premium"}))
+}
+
+// --- Active request cap ---
+
+func TestSubmitECG_RejectsSubmissionBeyondActiveCap(t *testing.T) {
+	repo := repomocks.NewMockStore(t)
+	queue := jobmocks.NewMockQueue(t)
+	svc := NewSubmissionService(repo, queue, storagemocks.NewMockStorage(t), config.QuotaConfig{MaxActive: 2})
+	userID := uuid.New()
+	ctx := auth.NewContext(context.Background(), userClaims(userID))
+
+	// Every accepted submission stays pending.
+	active := 0
+	repo.EXPECT().CountActiveRequests(mock.Anything, userID).
+		RunAndReturn(func(context.Context, uuid.UUID) (int, error) { return active, nil })
+	repo.EXPECT().CreateRequest(mock.Anything, mock.Anything).
+		RunAndReturn(func(context.Context, *models.Request) error { active++; return nil }).Times(2)
+	queue.EXPECT().TryEnqueue(mock.Anything, mock.Anything).Return(uuid.New(), nil).Times(2)
+	repo.EXPECT().MarkRequestQueued(mock.Anything, mock.Anything).Return(nil).Times(2)
+
+	for range 2 {
+		_, err := svc.SubmitECG(ctx, userID, "https://example.com/ekg.jpg", DefaultECGParams())
+		require.NoError(t, err)
+	}
+
+	_, err := svc.SubmitECG(ctx, userID, "https://example.com/ekg.jpg", DefaultECGParams())
+	require.ErrorIs(t, err, apperr.ErrQuotaExceeded)
+	assert.Contains(t, err.Error(), "at most 2")
+}
+
+func TestCheckActiveRequests_AdminExempt(t *testing.T) {
+	svc, repo := newBudgetedSubmissionService(t, config.QuotaConfig{MaxActive: 1})
+	userID := uuid.New()
+	ctx := auth.NewContext(context.Background(), &auth.Claims{
+		UserID: userID.String(),
+		Roles:  []string{auth.RoleAdmin},
+	})
+
+	require.NoError(t, svc.checkActiveRequests(ctx, userID))
+	repo.AssertNotCalled(t, "CountActiveRequests", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif" // register GIF decoder
+	"image/jpeg"
+	_ "image/png" // register PNG decoder
+	"io"
+	"log/slog"
+	"path"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register WebP decoder
+
+	"github.com/fedutinova/smartheart/back-api/validation"
+)
+
+const (
+	// thumbnailSize is the longest side of a generated preview, in pixels.
+	thumbnailSize        = 256
+	thumbnailJPEGQuality = 80
+	thumbnailPrefix      = "thumbnails/"
+)
+
+// thumbnailKey maps an upload key to its preview key under thumbnails/,
+// keeping the date path and unique suffix so the two stay associated.
+func thumbnailKey(key string) string {
+	key = strings.TrimPrefix(key, orphanSweepPrefix)
+	return thumbnailPrefix + strings.TrimSuffix(key, path.Ext(key)) + ".jpg"
+}
+
+// makeThumbnail decodes an image and returns a JPEG preview whose longest
+// side is at most size pixels. Images already that small are re-encoded as-is.
+// The header is checked first so oversized images are rejected before their
+// pixels are allocated.
+func makeThumbnail(r io.Reader, size int) ([]byte, error) {
+	var head bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(r, &head))
+	if err != nil {
+		return nil, fmt.Errorf("decode image config: %w", err)
+	}
+	if cfg.Width*cfg.Height > validation.MaxImagePixels {
+		return nil, fmt.Errorf("image is %dx%d, over the %d pixel limit", cfg.Width, cfg.Height, validation.MaxImagePixels)
+	}
+
+	src, _, err := image.Decode(io.MultiReader(&head, r))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, max(1, h*size/w)
+		} else {
+			w, h = max(1, w*size/h), size
+		}
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.ApproxBiLinear.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("encode jpeg: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// storeThumbnail generates and uploads a preview for an image upload stored
+// at key, returning the preview's key. Failures are logged and yield "" so
+// they never fail the upload itself.
+func (s *submissionService) storeThumbnail(ctx context.Context, f UploadedFile, contentType, key string) string {
+	if !strings.HasPrefix(contentType, "image/") {
+		return ""
+	}
+	if _, err := f.Reader.Seek(0, io.SeekStart); err != nil {
+		slog.WarnContext(ctx, "Thumbnail skipped: seek upload", "key", key, "error", err)
+		return ""
+	}
+	data, err := makeThumbnail(f.Reader, thumbnailSize)
+	if err != nil {
+		slog.WarnContext(ctx, "Thumbnail generation failed", "key", key, "error", err)
+		return ""
+	}
+	res, err := s.storage.PutFile(ctx, thumbnailKey(key), bytes.NewReader(data), "image/jpeg")
+	if err != nil {
+		slog.WarnContext(ctx, "Thumbnail upload failed", "key", key, "error", err)
+		return ""
+	}
+	return res.Key
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fedutinova/smartheart/back-api/storage"
+)
+
+func pngImage(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
+// oversizedPNG returns a tiny PNG whose header declares w x h pixels.
+func oversizedPNG(t *testing.T, w, h uint32) []byte {
+	t.Helper()
+	data := pngImage(t, 1, 1)
+	// IHDR data starts after the 8-byte signature and the chunk's length and
+	// type; its CRC covers the type and data and follows the 13 data bytes.
+	binary.BigEndian.PutUint32(data[16:], w)
+	binary.BigEndian.PutUint32(data[20:], h)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestThumbnailKey(t *testing.T) {
+	assert.Equal(t, "thumbnails/2026/01/02/ecg_ab12cd34.jpg", thumbnailKey("uploads/2026/01/02/ecg_ab12cd34.png"))
+}
+
+func TestMakeThumbnail_FitsLongestSide(t *testing.T) {
+	data, err := makeThumbnail(bytes.NewReader(pngImage(t, 1024, 512)), thumbnailSize)
+	require.NoError(t, err)
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, 256, cfg.Width)
+	assert.Equal(t, 128, cfg.Height)
+}
+
+func TestMakeThumbnail_RejectsOversizedImage(t *testing.T) {
+	_, err := makeThumbnail(bytes.NewReader(oversizedPNG(t, 100_000, 100_000)), thumbnailSize)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pixel limit")
+}
+
+func TestStoreThumbnail(t *testing.T) {
+	svc, _, _, store := newSubmissionService(t)
+	ctx := context.Background()
+
+	store.EXPECT().
+		PutFile(mock.Anything, "thumbnails/2026/01/02/ecg.jpg", mock.Anything, "image/jpeg").
+		Return(&storage.UploadResult{Key: "thumbnails/2026/01/02/ecg.jpg"}, nil)
+
+	img := UploadedFile{Reader: bytes.NewReader(pngImage(t, 300, 300))}
+	_, _ = io.Copy(io.Discard, img.Reader) // simulate the consumed upload
+	assert.Equal(t, "thumbnails/2026/01/02/ecg.jpg", svc.storeThumbnail(ctx, img, "image/png", "uploads/2026/01/02/ecg.png"))
+
+	// Non-images and undecodable images are skipped without failing.
+	assert.Empty(t, svc.storeThumbnail(ctx, UploadedFile{Reader: strings.NewReader("%PDF")}, "application/pdf", "uploads/a.pdf"))
+	assert.Empty(t, svc.storeThumbnail(ctx, UploadedFile{Reader: strings.NewReader("not a png")}, "image/png", "uploads/b.png"))
+}
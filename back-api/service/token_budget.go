@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/config"
+)
+
+// effectiveTokenBudget resolves the budget for a user with the given roles.
+// Role overrides win over the default; with several overriding roles the most
+// generous limit per period applies (0 = unlimited beats any number).
+func effectiveTokenBudget(def config.TokenBudget, overrides map[string]config.TokenBudget, roles []string) config.TokenBudget {
+	var (
+		budget config.TokenBudget
+		found  bool
+	)
+	for _, role := range roles {
+		b, ok := overrides[role]
+		if !ok {
+			continue
+		}
+		if !found {
+			budget, found = b, true
+			continue
+		}
+		budget.Daily = moreGenerous(budget.Daily, b.Daily)
+		budget.Monthly = moreGenerous(budget.Monthly, b.Monthly)
+	}
+	if !found {
+		return def
+	}
+	return budget
+}
+
+func moreGenerous(a, b int) int {
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+	return max(a, b)
+}
+
+// checkTokenBudget enforces the per-user daily and monthly OpenAI token budget.
+// Roles come from the auth claims on ctx; admins are exempt. It returns the
+// tokens left in the tightest period, or nil when the user is unlimited.
+func (s *submissionService) checkTokenBudget(ctx context.Context, userID uuid.UUID) (*int, error) {
+	var roles []string
+	if claims, ok := auth.FromContext(ctx); ok {
+		roles = claims.Roles
+	}
+	if slices.Contains(roles, auth.RoleAdmin) {
+		return nil, nil //nolint:nilnil // nil budget means unlimited
+	}
+
+	budget := effectiveTokenBudget(s.quota.Tokens, s.quota.RoleTokens, roles)
+	now := time.Now().UTC()
+
+	periods := []struct {
+		name  string
+		limit int
+		since time.Time
+	}{
+		{"daily", budget.Daily, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)},
+		{"monthly", budget.Monthly, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	var remaining *int
+	for _, p := range periods {
+		if p.limit <= 0 {
+			continue
+		}
+		used, err := s.repo.SumUserTokens(ctx, userID, p.since)
+		if err != nil {
+			return nil, apperr.WrapInternal("sum user tokens", err)
+		}
+		if used >= p.limit {
+			return nil, fmt.Errorf("%s token budget (%d) exhausted: %w", p.name, p.limit, apperr.ErrQuotaExceeded)
+		}
+		left := p.limit - used
+		if remaining == nil || left < *remaining {
+			remaining = &left
+		}
+	}
+	return remaining, nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+const (
+	// twoFactorChallengeTTL bounds how long a password-verified login may
+	// wait for its TOTP code.
+	twoFactorChallengeTTL = 5 * time.Minute
+	// maxTwoFactorAttempts limits code guesses per challenge.
+	maxTwoFactorAttempts int64 = 5
+)
+
+// EnrollTwoFactor generates a new TOTP secret for the user and stores it
+// encrypted. 2FA stays off until VerifyTwoFactor confirms a code, so an
+// abandoned enrollment never locks the user out.
+func (s *authService) EnrollTwoFactor(ctx context.Context, userID uuid.UUID) (*auth.TOTPEnrollment, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if apperr.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, apperr.WrapInternal("get user", err)
+	}
+	if user.TwoFactorEnabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled: %w", apperr.ErrConflict)
+	}
+
+	enrollment, err := auth.GenerateTOTP(user.Email)
+	if err != nil {
+		return nil, apperr.WrapInternal("generate totp secret", err)
+	}
+	sealed, err := s.box.Seal(enrollment.Secret)
+	if err != nil {
+		return nil, apperr.WrapInternal("encrypt totp secret", err)
+	}
+	if err := s.repo.SetTwoFactorSecret(ctx, userID, sealed); err != nil {
+		return nil, apperr.WrapInternal("store totp secret", err)
+	}
+	return enrollment, nil
+}
+
+// VerifyTwoFactor enables 2FA once the user proves their authenticator
+// produces valid codes for the enrolled secret.
+func (s *authService) VerifyTwoFactor(ctx context.Context, userID uuid.UUID, code string) error {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if apperr.IsNotFound(err) {
+			return err
+		}
+		return apperr.WrapInternal("get user", err)
+	}
+	if user.TwoFactorSecret == "" {
+		return fmt.Errorf("two-factor enrollment not started: %w", apperr.ErrValidation)
+	}
+	if !s.checkTOTP(ctx, user, code) {
+		return ErrInvalidTwoFactorCode
+	}
+	if user.TwoFactorEnabled {
+		return nil
+	}
+	if err := s.repo.EnableTwoFactor(ctx, userID); err != nil {
+		return apperr.WrapInternal("enable two-factor", err)
+	}
+	return nil
+}
+
+// LoginTwoFactor completes a login started by Login for a 2FA user. The
+// result always carries Tokens.
+func (s *authService) LoginTwoFactor(ctx context.Context, challengeToken, code string) (*LoginResult, error) {
+	if challengeToken == "" || code == "" {
+		return nil, fmt.Errorf("challenge_token and code are required: %w", apperr.ErrValidation)
+	}
+
+	tokenHash := auth.HashToken(challengeToken)
+
+	attempts, err := s.sessions.IncrLoginAttempts(ctx, "2fa:"+tokenHash, twoFactorChallengeTTL)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to check two-factor attempts, allowing request", "error", err)
+	} else if attempts > maxTwoFactorAttempts {
+		return nil, ErrTooManyAttempts
+	}
+
+	userID, err := s.sessions.GetTwoFactorChallengeUserID(ctx, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid two-factor challenge: %w", apperr.ErrInvalidToken)
+	}
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid two-factor challenge: %w", apperr.ErrInvalidToken)
+	}
+	user, err := s.repo.GetUserByID(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid two-factor challenge: %w", apperr.ErrInvalidToken)
+	}
+
+	if !s.checkTOTP(ctx, user, code) {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	// Single use: a replayed challenge must not yield a second token pair.
+	if err := s.sessions.DeleteTwoFactorChallenge(ctx, tokenHash); err != nil {
+		slog.WarnContext(ctx, "Failed to delete two-factor challenge", "error", err)
+	}
+
+	tokens, err := s.issueTokenPair(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{UserID: user.ID, Tokens: tokens}, nil
+}
+
+func (s *authService) issueTwoFactorChallenge(ctx context.Context, user *models.User) (string, error) {
+	challenge, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return "", apperr.WrapInternal("generate two-factor challenge", err)
+	}
+	if err := s.sessions.StoreTwoFactorChallenge(ctx, auth.HashToken(challenge), user.ID.String(), twoFactorChallengeTTL); err != nil {
+		return "", apperr.WrapInternal("store two-factor challenge", err)
+	}
+	return challenge, nil
+}
+
+// checkTOTP validates code against the user's decrypted TOTP secret.
+func (s *authService) checkTOTP(ctx context.Context, user *models.User, code string) bool {
+	secret, err := s.box.Open(user.TwoFactorSecret)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to decrypt two-factor secret", "user_id", user.ID, "error", err)
+		return false
+	}
+	return auth.ValidateTOTP(code, secret)
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fedutinova/smartheart/back-api/apperr"
+	"github.com/fedutinova/smartheart/back-api/auth"
+	"github.com/fedutinova/smartheart/back-api/models"
+)
+
+// twoFactorUser returns a user with 2FA enrolled under svc's key, and the
+// plaintext TOTP secret.
+func twoFactorUser(t *testing.T, svc *authService, enabled bool) (*models.User, string) {
+	t.Helper()
+	enrollment, err := auth.GenerateTOTP("alice@example.com")
+	require.NoError(t, err)
+	sealed, err := svc.box.Seal(enrollment.Secret)
+	require.NoError(t, err)
+	hash, err := auth.HashPassword("strongpassword123")
+	require.NoError(t, err)
+	return &models.User{
+		ID:               uuid.New(),
+		Email:            "alice@example.com",
+		PasswordHash:     hash,
+		Roles:            []models.Role{{Name: auth.RoleUser}},
+		TwoFactorSecret:  sealed,
+		TwoFactorEnabled: enabled,
+	}, enrollment.Secret
+}
+
+func TestLogin_TwoFactorReturnsChallenge(t *testing.T) {
+	svc, repo, sessions := newAuthService(t)
+	ctx := context.Background()
+	user, _ := twoFactorUser(t, svc, true)
+
+	sessions.EXPECT().IncrLoginAttempts(mock.Anything, user.Email, loginLockoutWindow).Return(int64(1), nil)
+	repo.EXPECT().GetUserByEmail(mock.Anything, user.Email).Return(user, nil)
+	sessions.EXPECT().ResetLoginAttempts(mock.Anything, user.Email).Return(nil)
+	sessions.EXPECT().
+		StoreTwoFactorChallenge(mock.Anything, mock.Anything, user.ID.String(), twoFactorChallengeTTL).
+		Return(nil)
+
+	result, err := svc.Login(ctx, user.Email, "strongpassword123")
+	require.NoError(t, err)
+	assert.Nil(t, result.Tokens)
+	assert.NotEmpty(t, result.ChallengeToken)
+}
+
+func TestLoginTwoFactor_Success(t *testing.T) {
+	svc, repo, sessions := newAuthService(t)
+	ctx := context.Background()
+	user, secret := twoFactorUser(t, svc, true)
+	challengeHash := auth.HashToken("challenge")
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+
+	sessions.EXPECT().IncrLoginAttempts(mock.Anything, "2fa:"+challengeHash, twoFactorChallengeTTL).Return(int64(1), nil)
+	sessions.EXPECT().GetTwoFactorChallengeUserID(mock.Anything, challengeHash).Return(user.ID.String(), nil)
+	repo.EXPECT().GetUserByID(mock.Anything, user.ID).Return(user, nil)
+	sessions.EXPECT().DeleteTwoFactorChallenge(mock.Anything, challengeHash).Return(nil)
+	sessions.EXPECT().StoreRefreshToken(mock.Anything, user.ID.String(), mock.Anything, svc.cfg.TTLRefresh).Return(nil)
+	repo.EXPECT().CreateRefreshToken(mock.Anything, mock.Anything).Return(nil)
+
+	result, err := svc.LoginTwoFactor(ctx, "challenge", code)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, result.UserID)
+	assert.NotEmpty(t, result.Tokens.AccessToken)
+}
+
+func TestLoginTwoFactor_InvalidCode(t *testing.T) {
+	svc, repo, sessions := newAuthService(t)
+	ctx := context.Background()
+	user, _ := twoFactorUser(t, svc, true)
+	challengeHash := auth.HashToken("challenge")
+
+	sessions.EXPECT().IncrLoginAttempts(mock.Anything, "2fa:"+challengeHash, twoFactorChallengeTTL).Return(int64(1), nil)
+	sessions.EXPECT().GetTwoFactorChallengeUserID(mock.Anything, challengeHash).Return(user.ID.String(), nil)
+	repo.EXPECT().GetUserByID(mock.Anything, user.ID).Return(user, nil)
+
+	_, err := svc.LoginTwoFactor(ctx, "challenge", "000000")
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+}
+
+func TestLoginTwoFactor_TooManyAttempts(t *testing.T) {
+	svc, _, sessions := newAuthService(t)
+	ctx := context.Background()
+
+	sessions.EXPECT().
+		IncrLoginAttempts(mock.Anything, "2fa:"+auth.HashToken("challenge"), twoFactorChallengeTTL).
+		Return(maxTwoFactorAttempts+1, nil)
+
+	_, err := svc.LoginTwoFactor(ctx, "challenge", "123456")
+	assert.ErrorIs(t, err, ErrTooManyAttempts)
+}
+
+func TestEnrollTwoFactor_StoresEncryptedSecret(t *testing.T) {
+	svc, repo, _ := newAuthService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	repo.EXPECT().GetUserByID(mock.Anything, userID).Return(&models.User{ID: userID, Email: "alice@example.com"}, nil)
+
+	var stored string
+	repo.EXPECT().
+		SetTwoFactorSecret(mock.Anything, userID, mock.Anything).
+		Run(func(_ context.Context, _ uuid.UUID, secret string) { stored = secret }).
+		Return(nil)
+
+	enrollment, err := svc.EnrollTwoFactor(ctx, userID)
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.URL, "otpauth://totp/")
+	assert.NotEqual(t, enrollment.Secret, stored)
+
+	plain, err := svc.box.Open(stored)
+	require.NoError(t, err)
+	assert.Equal(t, enrollment.Secret, plain)
+}
+
+func TestEnrollTwoFactor_AlreadyEnabled(t *testing.T) {
+	svc, repo, _ := newAuthService(t)
+	user, _ := twoFactorUser(t, svc, true)
+
+	repo.EXPECT().GetUserByID(mock.Anything, user.ID).Return(user, nil)
+
+	_, err := svc.EnrollTwoFactor(context.Background(), user.ID)
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+}
+
+func TestVerifyTwoFactor_Enables(t *testing.T) {
+	svc, repo, _ := newAuthService(t)
+	user, secret := twoFactorUser(t, svc, false)
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+
+	repo.EXPECT().GetUserByID(mock.Anything, user.ID).Return(user, nil)
+	repo.EXPECT().EnableTwoFactor(mock.Anything, user.ID).Return(nil)
+
+	require.NoError(t, svc.VerifyTwoFactor(context.Background(), user.ID, code))
+}
@@ -148,13 +148,35 @@ func (s *Service) GetLoginAttempts(ctx context.Context, email string) (int64, er
 	return count, nil
 }
 
-func (s *Service) StoreBlacklistedToken(ctx context.Context, tokenHash string, ttl time.Duration) error {
-	key := fmt.Sprintf("blacklist:%s", tokenHash)
+func (s *Service) StoreTwoFactorChallenge(ctx context.Context, tokenHash, userID string, ttl time.Duration) error {
+	key := fmt.Sprintf("2fa_challenge:%s", tokenHash)
+	return s.client.Set(ctx, key, userID, ttl).Err()
+}
+
+func (s *Service) GetTwoFactorChallengeUserID(ctx context.Context, tokenHash string) (string, error) {
+	key := fmt.Sprintf("2fa_challenge:%s", tokenHash)
+	userID, err := s.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", errors.New("two-factor challenge not found")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get two-factor challenge: %w", err)
+	}
+	return userID, nil
+}
+
+func (s *Service) DeleteTwoFactorChallenge(ctx context.Context, tokenHash string) error {
+	key := fmt.Sprintf("2fa_challenge:%s", tokenHash)
+	return s.client.Del(ctx, key).Err()
+}
+
+func (s *Service) StoreBlacklistedToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	key := fmt.Sprintf("blacklist:%s", tokenID)
 	return s.client.Set(ctx, key, "revoked", ttl).Err()
 }
 
-func (s *Service) IsTokenBlacklisted(ctx context.Context, tokenHash string) (bool, error) {
-	key := fmt.Sprintf("blacklist:%s", tokenHash)
+func (s *Service) IsTokenBlacklisted(ctx context.Context, tokenID string) (bool, error) {
+	key := fmt.Sprintf("blacklist:%s", tokenID)
 	exists, err := s.client.Exists(ctx, key).Result()
 	if err != nil {
 		return false, fmt.Errorf("failed to check blacklist: %w", err)
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+)
+
+// ErrChecksumMismatch is returned from a GetFile reader when the content read
+// does not match the SHA-256 recorded at upload.
+var ErrChecksumMismatch = errors.New("storage: checksum mismatch")
+
+// checksumMetadataKey is the object metadata key holding the hex SHA-256 on
+// backends with user metadata (S3, GCS).
+const checksumMetadataKey = "sha256"
+
+// Checksum returns the hex-encoded SHA-256 of data.
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyingReader hashes everything read through it and fails the final read
+// with ErrChecksumMismatch if the digest differs from want.
+type verifyingReader struct {
+	io.ReadCloser
+	h    hash.Hash
+	want string
+}
+
+// verifyChecksum wraps rc so reading it to EOF checks want. An empty want
+// (object stored before checksums were recorded) disables verification.
+func verifyChecksum(rc io.ReadCloser, want string) io.ReadCloser {
+	if want == "" {
+		return rc
+	}
+	return &verifyingReader{ReadCloser: rc, h: sha256.New(), want: want}
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.h.Write(p[:n])
+	if errors.Is(err, io.EOF) && hex.EncodeToString(r.h.Sum(nil)) != r.want {
+		return n, ErrChecksumMismatch
+	}
+	return n, err
+}
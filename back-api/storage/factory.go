@@ -10,10 +10,14 @@ func NewStorage(ctx context.Context, cfg appconfig.Config) (Storage, error) {
 	switch cfg.Storage.Mode {
 	case appconfig.StorageModeS3, appconfig.StorageModeAWS, appconfig.StorageModeLocalStack:
 		return NewS3Storage(ctx, cfg)
+	case appconfig.StorageModeGCS:
+		return NewGCSStorage(ctx, cfg)
 	case appconfig.StorageModeLocal, appconfig.StorageModeFilesystem:
-		return NewLocalStorage(cfg.Storage.LocalDir, cfg.Storage.LocalURL)
+		fallthrough
 	default:
-		return NewLocalStorage(cfg.Storage.LocalDir, cfg.Storage.LocalURL)
+		return NewLocalStorage(cfg.Storage.LocalDir, cfg.Storage.LocalURL,
+			WithVerifyChecksums(cfg.Storage.VerifyChecksums),
+			WithURLSigner(NewURLSigner(cfg.JWT.Secret)))
 	}
 }
 
@@ -24,6 +28,8 @@ func GetStorageType(cfg appconfig.Config) string {
 			return "LocalStack S3"
 		}
 		return "AWS S3"
+	case appconfig.StorageModeGCS:
+		return "Google Cloud Storage"
 	case appconfig.StorageModeLocal, appconfig.StorageModeFilesystem:
 		return "Local Filesystem"
 	default:
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	gcs "cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+
+	appconfig "github.com/fedutinova/smartheart/back-api/config"
+)
+
+type GCSStorage struct {
+	client *gcs.Client
+	bucket *gcs.BucketHandle
+	name   string
+	verify bool
+}
+
+// NewGCSStorage creates a Google Cloud Storage backend. Credentials come from
+// cfg.GCS.CredentialsFile when set, otherwise from Application Default
+// Credentials. Presigning needs a service account key or a runtime identity
+// allowed to sign blobs.
+func NewGCSStorage(ctx context.Context, cfg appconfig.Config) (*GCSStorage, error) {
+	slog.InfoContext(ctx, "Initializing GCS storage", "bucket", cfg.GCS.Bucket)
+
+	var opts []option.ClientOption
+	if cfg.GCS.CredentialsFile != "" {
+		opts = append(opts, option.WithAuthCredentialsFile(option.ServiceAccount, cfg.GCS.CredentialsFile))
+	}
+
+	client, err := gcs.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client: %w", err)
+	}
+
+	return &GCSStorage{
+		client: client,
+		bucket: client.Bucket(cfg.GCS.Bucket),
+		name:   cfg.GCS.Bucket,
+		verify: cfg.Storage.VerifyChecksums,
+	}, nil
+}
+
+func (s *GCSStorage) UploadFile(ctx context.Context, filename string, content io.Reader, contentType string) (*UploadResult, error) {
+	return s.PutFile(ctx, generateKey(filename), content, contentType)
+}
+
+func (s *GCSStorage) PutFile(ctx context.Context, key string, content io.Reader, contentType string) (*UploadResult, error) {
+	// Buffer to hash first: metadata has to be set before the write starts.
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload content: %w", err)
+	}
+	checksum := Checksum(data)
+
+	w := s.bucket.Object(key).NewWriter(ctx)
+	w.ContentType = contentType
+	w.Metadata = map[string]string{checksumMetadataKey: checksum}
+	if _, err := w.Write(data); err != nil {
+		_ = w.Close()
+		return nil, fmt.Errorf("failed to upload file to GCS: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to upload file to GCS: %w", err)
+	}
+
+	slog.InfoContext(ctx, "File uploaded to GCS", "key", key, "bucket", s.name)
+
+	return &UploadResult{
+		Key:      key,
+		URL:      fmt.Sprintf("https://storage.googleapis.com/%s/%s", s.name, key),
+		Checksum: checksum,
+	}, nil
+}
+
+// GetPresignedURL returns a V4 signed GET URL valid for expiration.
+func (s *GCSStorage) GetPresignedURL(_ context.Context, key string, expiration time.Duration) (string, error) {
+	url, err := s.bucket.SignedURL(key, &gcs.SignedURLOptions{
+		Scheme:  gcs.SigningSchemeV4,
+		Method:  "GET",
+		Expires: time.Now().Add(expiration),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create presigned URL: %w", err)
+	}
+	return url, nil
+}
+
+func (s *GCSStorage) DeleteFile(ctx context.Context, key string) error {
+	if err := s.bucket.Object(key).Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete file from GCS: %w", err)
+	}
+
+	slog.InfoContext(ctx, "File deleted from GCS", "key", key, "bucket", s.name)
+	return nil
+}
+
+func (s *GCSStorage) GetFile(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	obj := s.bucket.Object(key)
+
+	// The reader does not expose user metadata, so fetch attrs first and pin
+	// the read to that generation.
+	var want string
+	if s.verify {
+		attrs, err := obj.Attrs(ctx)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get file from GCS: %w", err)
+		}
+		want = attrs.Metadata[checksumMetadataKey]
+		obj = obj.Generation(attrs.Generation)
+	}
+
+	r, err := obj.NewReader(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get file from GCS: %w", err)
+	}
+
+	contentType := r.Attrs.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return verifyChecksum(r, want), contentType, nil
+}
+
+// List returns all objects whose key starts with prefix.
+func (s *GCSStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	it := s.bucket.Objects(ctx, &gcs.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list GCS objects: %w", err)
+		}
+		objects = append(objects, ObjectInfo{Key: attrs.Name, Size: attrs.Size, LastModified: attrs.Updated})
+	}
+	return objects, nil
+}
+
+// HealthCheck verifies that the bucket exists and is accessible.
+func (s *GCSStorage) HealthCheck(ctx context.Context) error {
+	if _, err := s.bucket.Attrs(ctx); err != nil {
+		return fmt.Errorf("get bucket %s: %w", s.name, err)
+	}
+	return nil
+}
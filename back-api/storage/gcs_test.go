@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	appconfig "github.com/fedutinova/smartheart/back-api/config"
+)
+
+const testGCSBucket = "smartheart-test"
+
+// fakeGCSObject is a stored object in the fake GCS server.
+type fakeGCSObject struct {
+	data        []byte
+	contentType string
+	metadata    map[string]string
+	generation  int64
+	updated     time.Time
+}
+
+// fakeGCS implements the parts of the GCS JSON and XML APIs that GCSStorage
+// uses, for one bucket. The client is pointed at it through
+// STORAGE_EMULATOR_HOST, the same switch the official emulators use.
+type fakeGCS struct {
+	mu      sync.Mutex
+	objects map[string]*fakeGCSObject
+	gen     int64
+}
+
+func newFakeGCS(t *testing.T) (*fakeGCS, *GCSStorage) {
+	t.Helper()
+	f := &fakeGCS{objects: make(map[string]*fakeGCSObject)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	t.Setenv("STORAGE_EMULATOR_HOST", srv.URL)
+
+	var cfg appconfig.Config
+	cfg.GCS.Bucket = testGCSBucket
+	cfg.Storage.VerifyChecksums = true
+	s, err := NewGCSStorage(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewGCSStorage: %v", err)
+	}
+	t.Cleanup(func() { _ = s.client.Close() })
+	return f, s
+}
+
+func (f *fakeGCS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucketPath := "/storage/v1/b/" + testGCSBucket
+	switch path := r.URL.Path; {
+	case r.Method == http.MethodPost && path == "/upload"+bucketPath+"/o":
+		f.insert(w, r)
+	case path == bucketPath:
+		writeFakeJSON(w, map[string]string{"name": testGCSBucket})
+	case path == bucketPath+"/o":
+		f.list(w, r.URL.Query().Get("prefix"))
+	case strings.HasPrefix(path, bucketPath+"/o/"):
+		name := strings.TrimPrefix(path, bucketPath+"/o/")
+		obj, ok := f.objects[name]
+		switch {
+		case !ok:
+			http.Error(w, `{"error":{"code":404,"message":"not found"}}`, http.StatusNotFound)
+		case r.Method == http.MethodDelete:
+			delete(f.objects, name)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeFakeJSON(w, obj.resource(name))
+		}
+	case strings.HasPrefix(path, "/"+testGCSBucket+"/"):
+		// XML API media download.
+		obj, ok := f.objects[strings.TrimPrefix(path, "/"+testGCSBucket+"/")]
+		if !ok {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+		w.Header().Set("X-Goog-Generation", strconv.FormatInt(obj.generation, 10))
+		_, _ = w.Write(obj.data)
+	default:
+		// 404 rather than a 5xx, which the client would retry.
+		http.Error(w, `{"error":{"code":404,"message":"unexpected request"}}`, http.StatusNotFound)
+	}
+}
+
+// insert handles a multipart upload: object metadata as JSON, then the media.
+func (f *fakeGCS) insert(w http.ResponseWriter, r *http.Request) {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	mr := multipart.NewReader(r.Body, params["boundary"])
+	var meta struct {
+		Name        string            `json:"name"`
+		ContentType string            `json:"contentType"`
+		Metadata    map[string]string `json:"metadata"`
+	}
+	part, err := mr.NextPart()
+	if err == nil {
+		err = json.NewDecoder(part).Decode(&meta)
+	}
+	var data []byte
+	if err == nil {
+		if part, err = mr.NextPart(); err == nil {
+			data, err = io.ReadAll(part)
+		}
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.gen++
+	obj := &fakeGCSObject{data: data, contentType: meta.ContentType, metadata: meta.Metadata, generation: f.gen, updated: time.Now()}
+	f.objects[meta.Name] = obj
+	writeFakeJSON(w, obj.resource(meta.Name))
+}
+
+func (f *fakeGCS) list(w http.ResponseWriter, prefix string) {
+	var items []map[string]any
+	for name, obj := range f.objects {
+		if strings.HasPrefix(name, prefix) {
+			items = append(items, obj.resource(name))
+		}
+	}
+	writeFakeJSON(w, map[string]any{"kind": "storage#objects", "items": items})
+}
+
+func (o *fakeGCSObject) resource(name string) map[string]any {
+	return map[string]any{
+		"bucket":      testGCSBucket,
+		"name":        name,
+		"contentType": o.contentType,
+		"metadata":    o.metadata,
+		"generation":  strconv.FormatInt(o.generation, 10),
+		"size":        strconv.Itoa(len(o.data)),
+		"updated":     o.updated.Format(time.RFC3339),
+	}
+}
+
+func writeFakeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestGCSStorage_PutAndGetFile(t *testing.T) {
+	f, s := newFakeGCS(t)
+	ctx := context.Background()
+
+	res, err := s.PutFile(ctx, "uploads/2026/01/02/ecg.png", strings.NewReader("png-bytes"), "image/png")
+	if err != nil {
+		t.Fatalf("PutFile: %v", err)
+	}
+	if res.Checksum != Checksum([]byte("png-bytes")) {
+		t.Errorf("checksum = %q, want the SHA-256 of the content", res.Checksum)
+	}
+	if want := "https://storage.googleapis.com/" + testGCSBucket + "/uploads/2026/01/02/ecg.png"; res.URL != want {
+		t.Errorf("URL = %q, want %q", res.URL, want)
+	}
+	if got := f.objects["uploads/2026/01/02/ecg.png"].metadata[checksumMetadataKey]; got != res.Checksum {
+		t.Errorf("stored checksum metadata = %q, want %q", got, res.Checksum)
+	}
+
+	rc, contentType, err := s.GetFile(ctx, res.Key)
+	if err != nil {
+		t.Fatalf("GetFile: %v", err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(data) != "png-bytes" || contentType != "image/png" {
+		t.Errorf("got %q (%s), want png-bytes (image/png)", data, contentType)
+	}
+}
+
+func TestGCSStorage_VerifiesChecksum(t *testing.T) {
+	f, s := newFakeGCS(t)
+	ctx := context.Background()
+
+	if _, err := s.PutFile(ctx, "uploads/a.png", strings.NewReader("original"), "image/png"); err != nil {
+		t.Fatalf("PutFile: %v", err)
+	}
+	f.objects["uploads/a.png"].data = []byte("tampered")
+
+	rc, _, err := s.GetFile(ctx, "uploads/a.png")
+	if err != nil {
+		t.Fatalf("GetFile: %v", err)
+	}
+	defer rc.Close()
+	if _, err := io.ReadAll(rc); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+}
+
+func TestGCSStorage_ListAndDelete(t *testing.T) {
+	_, s := newFakeGCS(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"uploads/2026/01/01/a.jpg", "uploads/2026/01/02/b.png", "other/c.txt"} {
+		if _, err := s.PutFile(ctx, key, strings.NewReader("x"), "application/octet-stream"); err != nil {
+			t.Fatalf("PutFile %s: %v", key, err)
+		}
+	}
+
+	objects, err := s.List(ctx, "uploads/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var keys []string
+	for _, o := range objects {
+		keys = append(keys, o.Key)
+		if o.Size != 1 || o.LastModified.IsZero() {
+			t.Fatalf("unexpected object info: %+v", o)
+		}
+	}
+	slices.Sort(keys)
+	if want := []string{"uploads/2026/01/01/a.jpg", "uploads/2026/01/02/b.png"}; !slices.Equal(keys, want) {
+		t.Fatalf("keys = %v, want %v", keys, want)
+	}
+
+	if err := s.DeleteFile(ctx, "uploads/2026/01/01/a.jpg"); err != nil {
+		t.Fatalf("DeleteFile: %v", err)
+	}
+	if _, _, err := s.GetFile(ctx, "uploads/2026/01/01/a.jpg"); err == nil {
+		t.Fatal("expected GetFile to fail for a deleted object")
+	}
+}
+
+func TestGCSStorage_HealthCheck(t *testing.T) {
+	_, s := newFakeGCS(t)
+	if err := s.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("HealthCheck: %v", err)
+	}
+
+	s.bucket = s.client.Bucket("missing")
+	s.name = "missing"
+	if err := s.HealthCheck(context.Background()); err == nil {
+		t.Fatal("expected HealthCheck to fail for a missing bucket")
+	}
+}
@@ -2,37 +2,73 @@ package storage
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
-
-	"github.com/google/uuid"
 )
 
+// checksumSuffix names the sidecar file holding an object's hex SHA-256.
+const checksumSuffix = ".sha256"
+
 type LocalStorage struct {
 	baseDir string
 	baseURL string
+	verify  bool
+	signer  *URLSigner
+}
+
+// LocalOption configures a LocalStorage.
+type LocalOption func(*LocalStorage)
+
+// WithVerifyChecksums makes GetFile check content against the SHA-256 sidecar
+// written at upload.
+func WithVerifyChecksums(on bool) LocalOption {
+	return func(s *LocalStorage) { s.verify = on }
+}
+
+// WithURLSigner enables GetPresignedURL, which then returns links signed by
+// signer that expire like S3 presigned URLs.
+func WithURLSigner(signer *URLSigner) LocalOption {
+	return func(s *LocalStorage) { s.signer = signer }
 }
 
-func NewLocalStorage(baseDir, baseURL string) (*LocalStorage, error) {
+func NewLocalStorage(baseDir, baseURL string, opts ...LocalOption) (*LocalStorage, error) {
 	if err := os.MkdirAll(baseDir, 0o755); err != nil {
 		return nil, fmt.Errorf("failed to create storage directory: %w", err)
 	}
 
-	return &LocalStorage{
+	s := &LocalStorage{
 		baseDir: baseDir,
 		baseURL: baseURL,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
-func (s *LocalStorage) UploadFile(_ context.Context, filename string, content io.Reader, _ string) (*UploadResult, error) {
-	key := s.generateKey(filename)
-	filePath := filepath.Join(s.baseDir, key)
+func (s *LocalStorage) UploadFile(ctx context.Context, filename string, content io.Reader, contentType string) (*UploadResult, error) {
+	return s.PutFile(ctx, generateKey(filename), content, contentType)
+}
+
+func (s *LocalStorage) PutFile(_ context.Context, key string, content io.Reader, _ string) (*UploadResult, error) {
+	// Rooting the key before cleaning drops any ".." that would escape baseDir.
+	key = strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(key)), "/")
+	if key == "" {
+		return nil, errors.New("invalid key: empty")
+	}
+	filePath := filepath.Join(s.baseDir, filepath.FromSlash(key))
 
 	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
 		return nil, fmt.Errorf("failed to create directory structure: %w", err)
@@ -43,7 +79,8 @@ func (s *LocalStorage) UploadFile(_ context.Context, filename string, content io
 		return nil, fmt.Errorf("failed to create file: %w", err)
 	}
 
-	written, err := io.Copy(f, content)
+	h := sha256.New()
+	written, err := io.Copy(f, io.TeeReader(content, h))
 	if closeErr := f.Close(); closeErr != nil && err == nil {
 		err = closeErr
 	}
@@ -52,18 +89,34 @@ func (s *LocalStorage) UploadFile(_ context.Context, filename string, content io
 		return nil, fmt.Errorf("failed to write file: %w", err)
 	}
 
+	checksum := hex.EncodeToString(h.Sum(nil))
+	if err := os.WriteFile(filePath+checksumSuffix, []byte(checksum), 0o644); err != nil {
+		_ = os.Remove(filePath)
+		return nil, fmt.Errorf("failed to write checksum: %w", err)
+	}
+
 	url := fmt.Sprintf("%s/%s", s.baseURL, key)
 
 	slog.Info("File uploaded to local storage", "key", key, "path", filePath, "size", written)
 
 	return &UploadResult{
-		Key: key,
-		URL: url,
+		Key:      key,
+		URL:      url,
+		Checksum: checksum,
 	}, nil
 }
 
-func (*LocalStorage) GetPresignedURL(_ context.Context, _ string, _ time.Duration) (string, error) {
-	return "", errors.New("presigned URLs not supported for local storage")
+// GetPresignedURL returns a link under baseURL carrying an HMAC signature
+// that the /files/* handler accepts until expiration has passed.
+func (s *LocalStorage) GetPresignedURL(_ context.Context, key string, expiration time.Duration) (string, error) {
+	if s.signer == nil {
+		return "", errors.New("presigned URLs not supported for local storage")
+	}
+	expires := s.signer.now().Add(expiration).Unix()
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(expires, 10))
+	q.Set("sig", s.signer.Sign(key, expires))
+	return fmt.Sprintf("%s/%s?%s", s.baseURL, (&url.URL{Path: key}).EscapedPath(), q.Encode()), nil
 }
 
 // safePath resolves the key to an absolute path inside baseDir, rejecting
@@ -97,6 +150,7 @@ func (s *LocalStorage) DeleteFile(_ context.Context, key string) error {
 	if err := os.Remove(filePath); err != nil {
 		return fmt.Errorf("failed to delete file: %w", err)
 	}
+	_ = os.Remove(filePath + checksumSuffix)
 
 	slog.Info("File deleted from local storage", "key", key, "path", filePath)
 	return nil
@@ -151,7 +205,80 @@ func (s *LocalStorage) GetFile(_ context.Context, key string) (io.ReadCloser, st
 		"size", fileInfo.Size(),
 		"content_type", contentType)
 
-	return file, contentType, nil
+	if !s.verify {
+		return file, contentType, nil
+	}
+	// A missing sidecar means the file predates checksums.
+	want, err := os.ReadFile(filePath + checksumSuffix)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		_ = file.Close()
+		return nil, "", fmt.Errorf("failed to read checksum: %w", err)
+	}
+	return verifyChecksum(file, strings.TrimSpace(string(want))), contentType, nil
+}
+
+// HealthCheck verifies that the base directory is writable by creating and
+// removing a temporary file.
+func (s *LocalStorage) HealthCheck(_ context.Context) error {
+	f, err := os.CreateTemp(s.baseDir, ".healthcheck-*")
+	if err != nil {
+		return fmt.Errorf("storage directory not writable: %w", err)
+	}
+	name := f.Name()
+	_ = f.Close()
+	if err := os.Remove(name); err != nil {
+		return fmt.Errorf("remove healthcheck file: %w", err)
+	}
+	return nil
+}
+
+// List returns all files whose key starts with prefix. Keys use forward
+// slashes regardless of the host OS.
+func (s *LocalStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	baseDir, err := filepath.Abs(filepath.Clean(s.baseDir))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base dir: %w", err)
+	}
+
+	// Walk the deepest directory covered by prefix instead of the whole tree.
+	root := baseDir
+	if dir := path.Dir(strings.TrimPrefix(prefix, "/")); dir != "." {
+		root = filepath.Join(baseDir, filepath.FromSlash(dir))
+	}
+
+	var objects []ObjectInfo
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if errors.Is(walkErr, fs.ErrNotExist) {
+				return nil
+			}
+			return walkErr
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || strings.HasSuffix(p, checksumSuffix) {
+			return nil
+		}
+		rel, err := filepath.Rel(baseDir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local storage: %w", err)
+	}
+	return objects, nil
 }
 
 func (s *LocalStorage) resolveExistingPath(key string) (string, error) {
@@ -212,30 +339,3 @@ func (s *LocalStorage) resolveExistingPath(key string) (string, error) {
 	// Return the original safePath error shape for consistent caller handling.
 	return s.safePath(key)
 }
-
-func (*LocalStorage) generateKey(filename string) string {
-	// filepath.Base strips directory components including ".." traversal
-	base := filepath.Base(filename)
-	ext := filepath.Ext(base)
-	basename := strings.TrimSuffix(base, ext)
-
-	// Remove dangerous characters: null bytes, backslashes, path separators
-	r := strings.NewReplacer(
-		"\x00", "",
-		"\\", "_",
-		"/", "_",
-		" ", "_",
-		"..", "_",
-	)
-	safeBasename := r.Replace(basename)
-	safeExt := r.Replace(ext)
-
-	if safeBasename == "" || safeBasename == "." {
-		safeBasename = "file"
-	}
-
-	timestamp := time.Now().Format("2006/01/02")
-	uniqueID := uuid.New().String()[:8]
-
-	return fmt.Sprintf("uploads/%s/%s_%s%s", timestamp, safeBasename, uniqueID, safeExt)
-}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStorage_List(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocalStorage(dir, "http://localhost/files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"uploads/2026/01/01/a.jpg", "uploads/2026/01/02/b.png", "other/c.txt"} {
+		p := filepath.Join(dir, filepath.FromSlash(key))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	objects, err := s.List(context.Background(), "uploads/")
+	if err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	var keys []string
+	for _, o := range objects {
+		keys = append(keys, o.Key)
+		if o.Size != 1 || o.LastModified.IsZero() {
+			t.Fatalf("unexpected object info: %+v", o)
+		}
+	}
+	slices.Sort(keys)
+	if want := []string{"uploads/2026/01/01/a.jpg", "uploads/2026/01/02/b.png"}; !slices.Equal(keys, want) {
+		t.Fatalf("keys = %v, want %v", keys, want)
+	}
+
+	objects, err = s.List(context.Background(), "missing/")
+	if err != nil || len(objects) != 0 {
+		t.Fatalf("expected empty result for missing prefix, got %v %v", objects, err)
+	}
+}
+
+func TestLocalStorage_HealthCheck(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocalStorage(dir, "http://localhost/files")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("HealthCheck error: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected probe file to be removed, found %d entries", len(entries))
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.HealthCheck(context.Background()); err == nil {
+		t.Fatal("expected error for missing storage directory")
+	}
+}
+
+func TestLocalStorage_VerifiesChecksum(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocalStorage(dir, "http://localhost/files", WithVerifyChecksums(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	res, err := s.UploadFile(ctx, "ekg.png", strings.NewReader("image data"), "image/png")
+	if err != nil {
+		t.Fatalf("UploadFile error: %v", err)
+	}
+	if res.Checksum != Checksum([]byte("image data")) {
+		t.Fatalf("Checksum = %q, want sha256 of content", res.Checksum)
+	}
+
+	objects, err := s.List(ctx, "uploads/")
+	if err != nil || len(objects) != 1 || objects[0].Key != res.Key {
+		t.Fatalf("List should return only the object, got %v %v", objects, err)
+	}
+
+	rc, _, err := s.GetFile(ctx, res.Key)
+	if err != nil {
+		t.Fatalf("GetFile error: %v", err)
+	}
+	if _, err := io.ReadAll(rc); err != nil {
+		t.Fatalf("read intact file: %v", err)
+	}
+	_ = rc.Close()
+
+	if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(res.Key)), []byte("corrupted!"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rc, _, err = s.GetFile(ctx, res.Key)
+	if err != nil {
+		t.Fatalf("GetFile error: %v", err)
+	}
+	defer rc.Close()
+	if _, err := io.ReadAll(rc); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+
+	if err := s.DeleteFile(ctx, res.Key); err != nil {
+		t.Fatalf("DeleteFile error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(res.Key)) + checksumSuffix); !os.IsNotExist(err) {
+		t.Fatalf("expected checksum sidecar to be removed, got %v", err)
+	}
+}
+
+func TestLocalStorage_PresignedURLExpires(t *testing.T) {
+	signer := NewURLSigner("test-secret")
+	now := time.Unix(1_700_000_000, 0)
+	signer.now = func() time.Time { return now }
+
+	s, err := NewLocalStorage(t.TempDir(), "http://localhost/files", WithURLSigner(signer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := s.GetPresignedURL(context.Background(), "uploads/a b.jpg", time.Minute)
+	if err != nil {
+		t.Fatalf("GetPresignedURL: %v", err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse %q: %v", raw, err)
+	}
+	if u.Path != "/files/uploads/a b.jpg" {
+		t.Fatalf("unexpected path %q", u.Path)
+	}
+	q := u.Query()
+
+	if err := signer.Verify("uploads/a b.jpg", q.Get("expires"), q.Get("sig")); err != nil {
+		t.Fatalf("fresh link rejected: %v", err)
+	}
+	if err := signer.Verify("uploads/other.jpg", q.Get("expires"), q.Get("sig")); !errors.Is(err, ErrURLSignature) {
+		t.Fatalf("expected signature error for another key, got %v", err)
+	}
+	if err := signer.Verify("uploads/a b.jpg", "9999999999", q.Get("sig")); !errors.Is(err, ErrURLSignature) {
+		t.Fatalf("expected signature error for extended expiry, got %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if err := signer.Verify("uploads/a b.jpg", q.Get("expires"), q.Get("sig")); !errors.Is(err, ErrURLExpired) {
+		t.Fatalf("expected expired error, got %v", err)
+	}
+}
+
+func TestLocalStorage_PresignedURLRequiresSigner(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir(), "http://localhost/files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetPresignedURL(context.Background(), "a.jpg", time.Minute); err == nil {
+		t.Fatal("expected error without a signer")
+	}
+}
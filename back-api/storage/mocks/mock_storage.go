@@ -195,6 +195,172 @@ func (_c *MockStorage_GetPresignedURL_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// HealthCheck provides a mock function with given fields: ctx
+func (_m *MockStorage) HealthCheck(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HealthCheck")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_HealthCheck_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HealthCheck'
+type MockStorage_HealthCheck_Call struct {
+	*mock.Call
+}
+
+// HealthCheck is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockStorage_Expecter) HealthCheck(ctx interface{}) *MockStorage_HealthCheck_Call {
+	return &MockStorage_HealthCheck_Call{Call: _e.mock.On("HealthCheck", ctx)}
+}
+
+func (_c *MockStorage_HealthCheck_Call) Run(run func(ctx context.Context)) *MockStorage_HealthCheck_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockStorage_HealthCheck_Call) Return(_a0 error) *MockStorage_HealthCheck_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_HealthCheck_Call) RunAndReturn(run func(context.Context) error) *MockStorage_HealthCheck_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, prefix
+func (_m *MockStorage) List(ctx context.Context, prefix string) ([]storage.ObjectInfo, error) {
+	ret := _m.Called(ctx, prefix)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []storage.ObjectInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]storage.ObjectInfo, error)); ok {
+		return rf(ctx, prefix)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []storage.ObjectInfo); ok {
+		r0 = rf(ctx, prefix)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.ObjectInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockStorage_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefix string
+func (_e *MockStorage_Expecter) List(ctx interface{}, prefix interface{}) *MockStorage_List_Call {
+	return &MockStorage_List_Call{Call: _e.mock.On("List", ctx, prefix)}
+}
+
+func (_c *MockStorage_List_Call) Run(run func(ctx context.Context, prefix string)) *MockStorage_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_List_Call) Return(_a0 []storage.ObjectInfo, _a1 error) *MockStorage_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_List_Call) RunAndReturn(run func(context.Context, string) ([]storage.ObjectInfo, error)) *MockStorage_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutFile provides a mock function with given fields: ctx, key, content, contentType
+func (_m *MockStorage) PutFile(ctx context.Context, key string, content io.Reader, contentType string) (*storage.UploadResult, error) {
+	ret := _m.Called(ctx, key, content, contentType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutFile")
+	}
+
+	var r0 *storage.UploadResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader, string) (*storage.UploadResult, error)); ok {
+		return rf(ctx, key, content, contentType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader, string) *storage.UploadResult); ok {
+		r0 = rf(ctx, key, content, contentType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.UploadResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.Reader, string) error); ok {
+		r1 = rf(ctx, key, content, contentType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_PutFile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutFile'
+type MockStorage_PutFile_Call struct {
+	*mock.Call
+}
+
+// PutFile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - content io.Reader
+//   - contentType string
+func (_e *MockStorage_Expecter) PutFile(ctx interface{}, key interface{}, content interface{}, contentType interface{}) *MockStorage_PutFile_Call {
+	return &MockStorage_PutFile_Call{Call: _e.mock.On("PutFile", ctx, key, content, contentType)}
+}
+
+func (_c *MockStorage_PutFile_Call) Run(run func(ctx context.Context, key string, content io.Reader, contentType string)) *MockStorage_PutFile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(io.Reader), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_PutFile_Call) Return(_a0 *storage.UploadResult, _a1 error) *MockStorage_PutFile_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_PutFile_Call) RunAndReturn(run func(context.Context, string, io.Reader, string) (*storage.UploadResult, error)) *MockStorage_PutFile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UploadFile provides a mock function with given fields: ctx, filename, content, contentType
 func (_m *MockStorage) UploadFile(ctx context.Context, filename string, content io.Reader, contentType string) (*storage.UploadResult, error) {
 	ret := _m.Called(ctx, filename, content, contentType)
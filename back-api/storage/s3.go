@@ -1,11 +1,15 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log/slog"
-	"path/filepath"
+	"net/url"
 	"strings"
 	"time"
 
@@ -13,110 +17,115 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/google/uuid"
 
 	appconfig "github.com/fedutinova/smartheart/back-api/config"
 )
 
 type S3Storage struct {
-	client   *s3.Client
-	bucket   string
-	endpoint string
-	region   string
+	client         *s3.Client
+	bucket         string
+	endpoint       string
+	region         string
+	forcePathStyle bool
+	verify         bool
 }
 
+// NewS3Storage creates an S3 backend. A custom endpoint selects any
+// S3-compatible store (LocalStack, MinIO, Ceph, ...); addressing style is
+// taken from cfg.S3.ForcePathStyle alone.
 func NewS3Storage(ctx context.Context, cfg appconfig.Config) (*S3Storage, error) {
-	var awsCfg aws.Config
-	var err error
-
 	slog.InfoContext(ctx, "Initializing S3 storage",
 		"endpoint", cfg.S3.Endpoint,
 		"bucket", cfg.S3.Bucket,
 		"region", cfg.S3.Region,
 		"force_path_style", cfg.S3.ForcePathStyle)
 
-	if cfg.S3.Endpoint != "" && (strings.Contains(cfg.S3.Endpoint, "localstack") || strings.Contains(cfg.S3.Endpoint, "localhost:4566") || strings.Contains(cfg.S3.Endpoint, "4566")) {
-		slog.InfoContext(ctx, "Using LocalStack configuration")
-		awsCfg, err = config.LoadDefaultConfig(ctx,
-			config.WithRegion(cfg.S3.Region),
-			config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-				cfg.S3.AWSAccessKey,
-				cfg.S3.AWSSecretKey,
-				"",
-			)),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load AWS config for LocalStack: %w", err)
-		}
-
-		client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
-			o.BaseEndpoint = aws.String(cfg.S3.Endpoint)
-			o.UsePathStyle = cfg.S3.ForcePathStyle
-		})
-
-		return &S3Storage{
-			client:   client,
-			bucket:   cfg.S3.Bucket,
-			endpoint: cfg.S3.Endpoint,
-			region:   cfg.S3.Region,
-		}, nil
-	}
-
+	opts := []func(*config.LoadOptions) error{config.WithRegion(cfg.S3.Region)}
 	if cfg.S3.AWSAccessKey != "" && cfg.S3.AWSSecretKey != "" {
-		awsCfg, err = config.LoadDefaultConfig(ctx,
-			config.WithRegion(cfg.S3.Region),
-			config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-				cfg.S3.AWSAccessKey,
-				cfg.S3.AWSSecretKey,
-				"",
-			)),
-		)
-	} else {
-		awsCfg, err = config.LoadDefaultConfig(ctx, config.WithRegion(cfg.S3.Region))
+		opts = append(opts, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+			cfg.S3.AWSAccessKey,
+			cfg.S3.AWSSecretKey,
+			"",
+		)))
 	}
 
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	client := s3.NewFromConfig(awsCfg)
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.S3.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.S3.Endpoint)
+		}
+		o.UsePathStyle = cfg.S3.ForcePathStyle
+	})
 
 	return &S3Storage{
-		client:   client,
-		bucket:   cfg.S3.Bucket,
-		endpoint: cfg.S3.Endpoint,
-		region:   cfg.S3.Region,
+		client:         client,
+		bucket:         cfg.S3.Bucket,
+		endpoint:       cfg.S3.Endpoint,
+		region:         cfg.S3.Region,
+		forcePathStyle: cfg.S3.ForcePathStyle,
+		verify:         cfg.Storage.VerifyChecksums,
 	}, nil
 }
 
 func (s *S3Storage) UploadFile(ctx context.Context, filename string, content io.Reader, contentType string) (*UploadResult, error) {
-	key := s.generateKey(filename)
+	return s.PutFile(ctx, generateKey(filename), content, contentType)
+}
 
-	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(s.bucket),
-		Key:         aws.String(key),
-		Body:        content,
-		ContentType: aws.String(contentType),
+func (s *S3Storage) PutFile(ctx context.Context, key string, content io.Reader, contentType string) (*UploadResult, error) {
+	// Buffer to hash before the PUT: S3 checks ChecksumSHA256 on receipt and
+	// the hex digest is kept in metadata for verification on read.
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload content: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:         aws.String(s.bucket),
+		Key:            aws.String(key),
+		Body:           bytes.NewReader(data),
+		ContentType:    aws.String(contentType),
+		ChecksumSHA256: aws.String(base64.StdEncoding.EncodeToString(sum[:])),
+		Metadata:       map[string]string{checksumMetadataKey: checksum},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload file to S3: %w", err)
 	}
 
-	var url string
-	if s.endpoint != "" {
-		url = fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucket, key)
-	} else {
-		url = fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, key)
-	}
-
 	slog.InfoContext(ctx, "File uploaded to S3", "key", key, "bucket", s.bucket)
 
 	return &UploadResult{
-		Key: key,
-		URL: url,
+		Key:      key,
+		URL:      s.objectURL(key),
+		Checksum: checksum,
 	}, nil
 }
 
+// objectURL returns the public URL of key, path-style (endpoint/bucket/key)
+// or virtual-host-style (bucket.endpoint/key) to match the client.
+func (s *S3Storage) objectURL(key string) string {
+	endpoint := s.endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", s.region)
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+
+	if s.forcePathStyle {
+		return fmt.Sprintf("%s/%s/%s", endpoint, s.bucket, key)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return fmt.Sprintf("%s/%s/%s", endpoint, s.bucket, key)
+	}
+	u.Host = s.bucket + "." + u.Host
+	return u.String() + "/" + key
+}
+
 func (s *S3Storage) GetPresignedURL(ctx context.Context, key string, expiration time.Duration) (string, error) {
 	presignClient := s3.NewPresignClient(s.client)
 
@@ -146,6 +155,37 @@ func (s *S3Storage) DeleteFile(ctx context.Context, key string) error {
 	return nil
 }
 
+// List returns all objects whose key starts with prefix.
+func (s *S3Storage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list S3 objects: %w", err)
+		}
+		for _, obj := range page.Contents {
+			info := ObjectInfo{Key: aws.ToString(obj.Key), Size: aws.ToInt64(obj.Size)}
+			if obj.LastModified != nil {
+				info.LastModified = *obj.LastModified
+			}
+			objects = append(objects, info)
+		}
+	}
+	return objects, nil
+}
+
+// HealthCheck verifies that the bucket exists and is accessible.
+func (s *S3Storage) HealthCheck(ctx context.Context) error {
+	if _, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(s.bucket)}); err != nil {
+		return fmt.Errorf("head bucket %s: %w", s.bucket, err)
+	}
+	return nil
+}
+
 func (s *S3Storage) GetFile(ctx context.Context, key string) (io.ReadCloser, string, error) {
 	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: &s.bucket,
@@ -160,32 +200,9 @@ func (s *S3Storage) GetFile(ctx context.Context, key string) (io.ReadCloser, str
 		contentType = *result.ContentType
 	}
 
-	return result.Body, contentType, nil
-}
-
-func (*S3Storage) generateKey(filename string) string {
-	// filepath.Base strips directory components including ".." traversal
-	base := filepath.Base(filename)
-	ext := filepath.Ext(base)
-	basename := strings.TrimSuffix(base, ext)
-
-	// Remove dangerous characters: null bytes, backslashes, path separators
-	r := strings.NewReplacer(
-		"\x00", "",
-		"\\", "_",
-		"/", "_",
-		" ", "_",
-		"..", "_",
-	)
-	safeBasename := r.Replace(basename)
-	safeExt := r.Replace(ext)
-
-	if safeBasename == "" || safeBasename == "." {
-		safeBasename = "file"
+	body := result.Body
+	if s.verify {
+		body = verifyChecksum(body, result.Metadata[checksumMetadataKey])
 	}
-
-	timestamp := time.Now().Format("2006/01/02")
-	uniqueID := uuid.New().String()[:8]
-
-	return fmt.Sprintf("uploads/%s/%s_%s%s", timestamp, safeBasename, uniqueID, safeExt)
+	return body, contentType, nil
 }
@@ -0,0 +1,24 @@
+package storage
+
+import "testing"
+
+func TestS3Storage_ObjectURL(t *testing.T) {
+	tests := []struct {
+		name string
+		s    S3Storage
+		want string
+	}{
+		{"localstack path style", S3Storage{bucket: "b", endpoint: "http://localhost:4566", forcePathStyle: true}, "http://localhost:4566/b/k.png"},
+		{"minio path style", S3Storage{bucket: "b", endpoint: "https://minio.internal:9000/", forcePathStyle: true}, "https://minio.internal:9000/b/k.png"},
+		{"custom endpoint virtual host", S3Storage{bucket: "b", endpoint: "https://s3.wasabisys.com"}, "https://b.s3.wasabisys.com/k.png"},
+		{"aws virtual host", S3Storage{bucket: "b", region: "eu-west-1"}, "https://b.s3.eu-west-1.amazonaws.com/k.png"},
+		{"aws path style", S3Storage{bucket: "b", region: "eu-west-1", forcePathStyle: true}, "https://s3.eu-west-1.amazonaws.com/b/k.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.objectURL("k.png"); got != tt.want {
+				t.Fatalf("objectURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
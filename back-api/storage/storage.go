@@ -2,18 +2,67 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type Storage interface {
 	UploadFile(ctx context.Context, filename string, content io.Reader, contentType string) (*UploadResult, error)
+	// PutFile stores content under exactly key, replacing any existing object.
+	// UploadFile is PutFile with a generated key.
+	PutFile(ctx context.Context, key string, content io.Reader, contentType string) (*UploadResult, error)
 	GetPresignedURL(ctx context.Context, key string, expiration time.Duration) (string, error)
 	DeleteFile(ctx context.Context, key string) error
 	GetFile(ctx context.Context, key string) (io.ReadCloser, string, error) // Returns reader, contentType, error
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// HealthCheck is a cheap probe that the backend is reachable and writable.
+	HealthCheck(ctx context.Context) error
 }
 
 type UploadResult struct {
 	Key string
 	URL string
+	// Checksum is the hex-encoded SHA-256 of the uploaded content.
+	Checksum string
+}
+
+// ObjectInfo describes a stored object returned by List.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// generateKey builds the object key for an uploaded file. Every backend uses
+// it so keys stay interchangeable: uploads/YYYY/MM/DD/<name>_<id><ext>.
+func generateKey(filename string) string {
+	// filepath.Base strips directory components including ".." traversal
+	base := filepath.Base(filename)
+	ext := filepath.Ext(base)
+	basename := strings.TrimSuffix(base, ext)
+
+	// Remove dangerous characters: null bytes, backslashes, path separators
+	r := strings.NewReplacer(
+		"\x00", "",
+		"\\", "_",
+		"/", "_",
+		" ", "_",
+		"..", "_",
+	)
+	safeBasename := r.Replace(basename)
+	safeExt := r.Replace(ext)
+
+	if safeBasename == "" || safeBasename == "." {
+		safeBasename = "file"
+	}
+
+	timestamp := time.Now().Format("2006/01/02")
+	uniqueID := uuid.New().String()[:8]
+
+	return fmt.Sprintf("uploads/%s/%s_%s%s", timestamp, safeBasename, uniqueID, safeExt)
 }
@@ -9,11 +9,20 @@ import (
 )
 
 const (
-	MaxFileSize   = 10 << 20 // 10mb
-	MaxFiles      = 5
-	MaxTextLength = 4000
+	DefaultMaxFileSize = 10 << 20 // 10mb
+	MaxFiles           = 5
+	MaxTextLength      = 4000
 )
 
+// DefaultMultipartMemory is how much of a multipart form is held in memory
+// by default; file parts beyond it spill to temporary files.
+const DefaultMultipartMemory = 1 << 20 // 1mb
+
+// MaxImagePixels caps the images decoded server-side (previews, the EKG
+// plausibility check, downscaling before GPT): a small compressed file can
+// declare dimensions that need gigabytes of memory once decoded.
+const MaxImagePixels = 50_000_000
+
 var AllowedMimeTypes = map[string]bool{
 	"image/jpeg":       true,
 	"image/jpg":        true,
@@ -63,7 +72,9 @@ func (e ValidationErrors) Error() string {
 	return strings.Join(messages, "; ")
 }
 
-func ValidateGPTRequest(textQuery string, files []*multipart.FileHeader) ValidationErrors {
+// ValidateGPTRequest checks the text query and files of a GPT submission.
+// Files larger than maxFileSize bytes are rejected.
+func ValidateGPTRequest(textQuery string, files []*multipart.FileHeader, maxFileSize int64) ValidationErrors {
 	var errors ValidationErrors
 
 	if len(files) == 0 {
@@ -89,10 +100,10 @@ func ValidateGPTRequest(textQuery string, files []*multipart.FileHeader) Validat
 	}
 
 	for i, file := range files {
-		if file.Size > MaxFileSize {
+		if file.Size > maxFileSize {
 			errors = append(errors, ValidationError{
 				Field:   fmt.Sprintf("files[%d]", i),
-				Message: fmt.Sprintf("file %s exceeds maximum size of %d bytes", file.Filename, MaxFileSize),
+				Message: fmt.Sprintf("file %s exceeds maximum size of %d bytes", file.Filename, maxFileSize),
 			})
 			continue
 		}
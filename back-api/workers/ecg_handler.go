@@ -3,7 +3,6 @@ package workers
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -33,17 +32,43 @@ type ECGWorker struct {
 	repo      repository.RequestRepo
 	quotaRepo repository.QuotaRepo
 	gptClient gpt.Processor
+	prompts   *gpt.PromptSet
 	hub       *notify.Hub
+	webhooks  *WebhookSender
+	// client downloads EKG images by URL through the SSRF-safe transport.
+	client *http.Client
+	// maxImageBytes caps downloaded and stored EKG images.
+	maxImageBytes int64
+	// minImageConfidence is the EKG plausibility score below which an image
+	// is rejected before the GPT call (0 = no check).
+	minImageConfidence float64
+	// txOpts configure the transaction-scoped repositories, e.g. with
+	// repository.WithFieldCipher.
+	txOpts []func(*repository.Repository)
 }
 
+// errImageTooLarge is returned when an EKG image exceeds maxImageBytes.
+var errImageTooLarge = errors.New("image too large")
+
 func NewECGWorker(
 	txb database.TxBeginner,
 	queue job.Queue,
 	storageService storage.Storage,
 	repo repository.Store,
 	gptClient gpt.Processor,
+	prompts *gpt.PromptSet,
 	hub *notify.Hub,
+	webhooks *WebhookSender,
+	maxImageBytes int64,
+	minImageConfidence float64,
+	txOpts ...func(*repository.Repository),
 ) *ECGWorker {
+	if prompts == nil {
+		prompts = gpt.DefaultPrompts()
+	}
+	if maxImageBytes <= 0 {
+		maxImageBytes = validation.DefaultMaxFileSize
+	}
 	return &ECGWorker{
 		txb:       txb,
 		queue:     queue,
@@ -51,28 +76,38 @@ func NewECGWorker(
 		repo:      repo,
 		quotaRepo: repo,
 		gptClient: gptClient,
+		prompts:   prompts,
 		hub:       hub,
+		webhooks:  webhooks,
+		client:    newImageClient(),
+
+		maxImageBytes:      maxImageBytes,
+		minImageConfidence: minImageConfidence,
+		txOpts:             txOpts,
 	}
 }
 
 func (h *ECGWorker) HandleECGJob(ctx context.Context, j *job.Job) error {
-	if j.Type != job.TypeECGAnalyze {
-		return fmt.Errorf("unexpected job type: %s", j.Type)
+	payload, err := job.DecodePayload[job.ECGJobPayload](j)
+	if err != nil {
+		return err
 	}
 
-	var payload job.ECGJobPayload
-	if err := json.Unmarshal(j.Payload, &payload); err != nil {
-		return fmt.Errorf("failed to unmarshal EKG job payload: %w", err)
+	// Synchronous callers pass no request; processEKG creates it completed.
+	if payload.RequestID != uuid.Nil {
+		if err := h.repo.UpdateRequestStatus(ctx, payload.RequestID, models.StatusProcessing); err != nil {
+			slog.WarnContext(ctx, "Failed to mark EKG request processing", "request_id", payload.RequestID, "error", err)
+		}
 	}
 
-	err := h.processEKG(ctx, j, &payload)
+	err = h.processEKG(ctx, j, &payload)
 	if err != nil {
-		h.handleEKGFailure(ctx, &payload)
+		h.handleEKGFailure(ctx, &payload, job.CodeOf(err))
 	}
 	return err
 }
 
-func (h *ECGWorker) handleEKGFailure(ctx context.Context, payload *job.ECGJobPayload) {
+func (h *ECGWorker) handleEKGFailure(ctx context.Context, payload *job.ECGJobPayload, code models.ErrorCode) {
 	// Refund the free analyses counter so failed analyses don't count.
 	if decErr := h.quotaRepo.DecrementFreeAnalysesUsed(ctx, payload.UserID); decErr != nil {
 		slog.WarnContext(ctx, "Failed to decrement free analyses used after EKG failure", "user_id", payload.UserID, "error", decErr)
@@ -81,7 +116,7 @@ func (h *ECGWorker) handleEKGFailure(ctx context.Context, payload *job.ECGJobPay
 	if payload.RequestID == uuid.Nil {
 		return
 	}
-	if updErr := h.repo.UpdateRequestStatus(ctx, payload.RequestID, models.StatusFailed); updErr != nil {
+	if updErr := h.repo.FailRequest(ctx, payload.RequestID, code); updErr != nil {
 		slog.ErrorContext(ctx, "Failed to update request status to failed", "request_id", payload.RequestID, "error", updErr)
 	}
 	h.hub.Notify(payload.UserID, notify.Event{
@@ -89,9 +124,11 @@ func (h *ECGWorker) handleEKGFailure(ctx context.Context, payload *job.ECGJobPay
 		RequestID: payload.RequestID,
 		Status:    models.StatusFailed,
 	})
+	h.webhooks.Notify(ctx, payload.CallbackURL, newWebhookEvent(payload.RequestID, models.StatusFailed, ""))
 }
 
 func (h *ECGWorker) processEKG(ctx context.Context, j *job.Job, payload *job.ECGJobPayload) error {
+	start := time.Now()
 	slog.InfoContext(ctx, "Starting EKG analysis",
 		"job_id", j.ID,
 		"user_id", payload.UserID,
@@ -118,7 +155,12 @@ func (h *ECGWorker) processEKG(ctx context.Context, j *job.Job, payload *job.ECG
 	}
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to get EKG image", "job_id", j.ID, "error", err)
-		return fmt.Errorf("failed to get image: %w", err)
+		return job.WithCode(models.ErrorCodeInvalidImage, fmt.Errorf("failed to get image: %w", err))
+	}
+
+	imageConfidence, err := h.checkImage(ctx, j, imageData)
+	if err != nil {
+		return job.WithCode(models.ErrorCodeInvalidImage, err)
 	}
 
 	// Ensure image is in storage (for file record and GPT access)
@@ -135,11 +177,14 @@ func (h *ECGWorker) processEKG(ctx context.Context, j *job.Job, payload *job.ECG
 	}
 
 	// Build prompt and call GPT.
-	systemPrompt, userPrompt := gpt.BuildECGMeasurementPrompt(payload.PaperSpeedMMS)
+	systemPrompt, userPrompt, err := h.prompts.ECGMeasurementPrompt(payload.PaperSpeedMMS)
+	if err != nil {
+		return fmt.Errorf("build ECG prompt: %w", err)
+	}
 	gptResult, err := h.gptClient.ProcessStructuredECG(ctx, []string{imageKey}, systemPrompt, userPrompt)
 	if err != nil {
 		slog.ErrorContext(ctx, "GPT structured ECG call failed", "job_id", j.ID, "error", err)
-		return fmt.Errorf("gpt analysis failed: %w", err)
+		return job.WithCode(gpt.ErrorCode(err), fmt.Errorf("gpt analysis failed: %w", err))
 	}
 
 	// Parse GPT JSON response
@@ -179,12 +224,17 @@ func (h *ECGWorker) processEKG(ctx context.Context, j *job.Job, payload *job.ECG
 		Timestamp:        timestamp,
 		JobID:            j.ID.String(),
 		StructuredResult: structured,
+		ImageConfidence:  imageConfidence,
 	}
 	responseJSON, err := ecgContent.Marshal()
 	if err != nil {
 		return fmt.Errorf("failed to marshal response: %w", err)
 	}
 
+	// Wall-clock time of the whole pipeline (image fetch, plausibility
+	// check, GPT call and post-processing), not just the GPT request.
+	processingTimeMs := int(time.Since(start).Milliseconds())
+
 	// Persist in transaction
 	needsCreate := payload.RequestID == uuid.Nil
 	requestID := payload.RequestID
@@ -194,7 +244,7 @@ func (h *ECGWorker) processEKG(ctx context.Context, j *job.Job, payload *job.ECG
 	}
 
 	if err := h.txb.WithTx(ctx, func(tx database.Tx) error {
-		txRepo := repository.NewTxScoped(tx)
+		txRepo := repository.NewTxScoped(tx, h.txOpts...)
 
 		if needsCreate {
 			request := &models.Request{
@@ -205,6 +255,9 @@ func (h *ECGWorker) processEKG(ctx context.Context, j *job.Job, payload *job.ECG
 			if payload.Notes != "" {
 				request.TextQuery = &payload.Notes
 			}
+			if j.CorrelationID != "" {
+				request.CorrelationID = &j.CorrelationID
+			}
 			if err := txRepo.CreateRequest(ctx, request); err != nil {
 				return fmt.Errorf("create request: %w", err)
 			}
@@ -216,7 +269,9 @@ func (h *ECGWorker) processEKG(ctx context.Context, j *job.Job, payload *job.ECG
 			Content:          responseJSON,
 			Model:            models.ECGModelStructured,
 			TokensUsed:       gptResult.TokensUsed,
-			ProcessingTimeMs: gptResult.ProcessingTimeMs,
+			ProcessingTimeMs: processingTimeMs,
+			FinishReason:     gptResult.FinishReason,
+			IsRefusal:        gptResult.Refused,
 		}
 		if err := txRepo.CreateResponse(ctx, response); err != nil {
 			return fmt.Errorf("save response: %w", err)
@@ -231,6 +286,7 @@ func (h *ECGWorker) processEKG(ctx context.Context, j *job.Job, payload *job.ECG
 			FileSize:         int64(len(imageData)),
 			S3Key:            imageKey,
 			S3URL:            imageURL,
+			Checksum:         storage.Checksum(imageData),
 		}
 		if err := txRepo.CreateFile(ctx, fileModel); err != nil {
 			return fmt.Errorf("create file record: %w", err)
@@ -255,11 +311,35 @@ func (h *ECGWorker) processEKG(ctx context.Context, j *job.Job, payload *job.ECG
 			Status:    models.StatusCompleted,
 		})
 	}
+	var conclusion string
+	if structured != nil && structured.Interpretation != nil {
+		conclusion = structured.Interpretation.TextSummary
+	}
+	h.webhooks.Notify(ctx, payload.CallbackURL, newWebhookEvent(requestID, models.StatusCompleted, conclusion))
 
-	slog.InfoContext(ctx, "EKG structured analysis completed", "job_id", j.ID)
+	slog.InfoContext(ctx, "EKG structured analysis completed",
+		"job_id", j.ID,
+		"processing_time_ms", processingTimeMs,
+		"gpt_time_ms", gptResult.ProcessingTimeMs)
 	return nil
 }
 
+// checkImage scores the image's EKG plausibility and rejects it with errNotEKG
+// below minImageConfidence, saving the GPT call. Images the heuristic cannot
+// decode are passed through unscored.
+func (h *ECGWorker) checkImage(ctx context.Context, j *job.Job, data []byte) (*float64, error) {
+	check, err := checkEKGImage(data, h.minImageConfidence)
+	if err != nil {
+		slog.WarnContext(ctx, "EKG plausibility check skipped", "job_id", j.ID, "error", err)
+		return nil, nil
+	}
+	if !check.Plausible {
+		slog.InfoContext(ctx, "Rejected non-EKG image", "job_id", j.ID, "confidence", check.Confidence)
+		return nil, fmt.Errorf("%w (confidence %.2f)", errNotEKG, check.Confidence)
+	}
+	return &check.Confidence, nil
+}
+
 // Close cleans up resources used by the EKG worker.
 func (*ECGWorker) Close() {
 	slog.Debug("EKG worker closed")
@@ -315,22 +395,11 @@ func newSSRFSafeTransport() *http.Transport {
 	}
 }
 
-func (*ECGWorker) downloadImage(ctx context.Context, imageURL string) ([]byte, error) {
+func (h *ECGWorker) downloadImage(ctx context.Context, imageURL string) ([]byte, error) {
 	if err := validateImageURL(imageURL); err != nil {
 		return nil, fmt.Errorf("url validation failed: %w", err)
 	}
 
-	client := &http.Client{
-		Timeout:   30 * time.Second,
-		Transport: sharedSSRFTransport,
-		CheckRedirect: func(_ *http.Request, via []*http.Request) error {
-			if len(via) >= 3 {
-				return errors.New("too many redirects")
-			}
-			return nil
-		},
-	}
-
 	req, err := http.NewRequestWithContext(ctx, "GET", imageURL, http.NoBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -338,7 +407,7 @@ func (*ECGWorker) downloadImage(ctx context.Context, imageURL string) ([]byte, e
 	req.Header.Set("User-Agent", "SmartHeart-EKG-Processor/1.0")
 	req.Header.Set("Accept", "image/*")
 
-	resp, err := client.Do(req)
+	resp, err := h.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download image: %w", err)
 	}
@@ -353,19 +422,29 @@ func (*ECGWorker) downloadImage(ctx context.Context, imageURL string) ([]byte, e
 		return nil, fmt.Errorf("invalid content type: %s", contentType)
 	}
 
-	if resp.ContentLength > maxImageSize {
-		return nil, fmt.Errorf("image too large: %d bytes", resp.ContentLength)
+	if resp.ContentLength > h.maxImageBytes {
+		slog.WarnContext(ctx, "Rejected EKG image download",
+			"content_length", resp.ContentLength, "max_bytes", h.maxImageBytes)
+		return nil, fmt.Errorf("%w: %d bytes exceeds limit of %d", errImageTooLarge, resp.ContentLength, h.maxImageBytes)
 	}
 
-	imageData, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
-	if err != nil {
-		return nil, fmt.Errorf("failed to read image data: %w", err)
-	}
-	if len(imageData) > maxImageSize {
-		return nil, fmt.Errorf("image too large: %d bytes", len(imageData))
-	}
+	// Content-Length may be absent or wrong, so cap the body as well: at
+	// most maxImageBytes+1 bytes are ever buffered.
+	return h.readLimited(ctx, resp.Body, "response body")
+}
 
-	return imageData, nil
+// newImageClient returns the client used by downloadImage.
+func newImageClient() *http.Client {
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: sharedSSRFTransport,
+		CheckRedirect: func(_ *http.Request, via []*http.Request) error {
+			if len(via) >= 3 {
+				return errors.New("too many redirects")
+			}
+			return nil
+		},
+	}
 }
 
 func isValidImageContentType(contentType string) bool {
@@ -379,8 +458,6 @@ func ecgJobMode(p *job.ECGJobPayload) string {
 	return "url"
 }
 
-const maxImageSize = 10 * 1024 * 1024
-
 func (h *ECGWorker) readFromStorage(ctx context.Context, key string) ([]byte, error) {
 	reader, _, err := h.storage.GetFile(ctx, key)
 	if err != nil {
@@ -388,12 +465,19 @@ func (h *ECGWorker) readFromStorage(ctx context.Context, key string) ([]byte, er
 	}
 	defer func() { _ = reader.Close() }()
 
-	data, err := io.ReadAll(io.LimitReader(reader, int64(maxImageSize)+1))
+	return h.readLimited(ctx, reader, "storage")
+}
+
+// readLimited reads r, failing with errImageTooLarge once more than
+// maxImageBytes have been read.
+func (h *ECGWorker) readLimited(ctx context.Context, r io.Reader, source string) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, h.maxImageBytes+1))
 	if err != nil {
-		return nil, fmt.Errorf("read file from storage: %w", err)
+		return nil, fmt.Errorf("read image from %s: %w", source, err)
 	}
-	if len(data) > maxImageSize {
-		return nil, fmt.Errorf("image too large: %d bytes", len(data))
+	if int64(len(data)) > h.maxImageBytes {
+		slog.WarnContext(ctx, "EKG image truncated at size limit", "source", source, "max_bytes", h.maxImageBytes)
+		return nil, fmt.Errorf("%w: exceeds limit of %d bytes", errImageTooLarge, h.maxImageBytes)
 	}
 	return data, nil
 }
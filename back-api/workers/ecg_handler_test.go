@@ -4,12 +4,27 @@
 package workers
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/fedutinova/smartheart/back-api/job"
+	"github.com/fedutinova/smartheart/back-api/models"
+	"github.com/fedutinova/smartheart/back-api/notify"
+	repomocks "github.com/fedutinova/smartheart/back-api/repository/mocks"
+	storagemocks "github.com/fedutinova/smartheart/back-api/storage/mocks"
 )
 
 func TestECGJobPayload_MarshalUnmarshal(t *testing.T) {
@@ -46,6 +61,46 @@ func TestECGJobPayload_MarshalUnmarshal(t *testing.T) {
 	}
 }
 
+func TestHandleECGJob_MarksRequestProcessing(t *testing.T) {
+	requestID, userID := uuid.New(), uuid.New()
+	repo := repomocks.NewMockStore(t)
+	store := storagemocks.NewMockStorage(t)
+
+	// The request moves to processing before the image is read, then fails.
+	processing := repo.EXPECT().UpdateRequestStatus(mock.Anything, requestID, models.StatusProcessing).Return(nil).Call
+	store.EXPECT().GetFile(mock.Anything, "uploads/ekg.png").Return(nil, "", errors.New("not found")).NotBefore(processing)
+	repo.EXPECT().DecrementFreeAnalysesUsed(mock.Anything, userID).Return(nil)
+	repo.EXPECT().FailRequest(mock.Anything, requestID, models.ErrorCodeInvalidImage).Return(nil)
+
+	h := NewECGWorker(nil, nil, store, repo, nil, nil, notify.NewHub(), nil, 0, 0)
+	payload, _ := json.Marshal(job.ECGJobPayload{ImageFileKey: "uploads/ekg.png", UserID: userID, RequestID: requestID})
+	if err := h.HandleECGJob(context.Background(), &job.Job{ID: uuid.New(), Type: job.TypeECGAnalyze, Payload: payload}); err == nil {
+		t.Fatal("expected the missing image to fail the job")
+	}
+}
+
+func TestHandleECGJob_ScoresWebPImages(t *testing.T) {
+	data, err := os.ReadFile("testdata/gradient.webp")
+	if err != nil {
+		t.Fatalf("read testdata: %v", err)
+	}
+	requestID, userID := uuid.New(), uuid.New()
+	repo := repomocks.NewMockStore(t)
+	store := storagemocks.NewMockStorage(t)
+
+	// A decoded non-EKG image fails the plausibility check before any GPT call.
+	repo.EXPECT().UpdateRequestStatus(mock.Anything, requestID, models.StatusProcessing).Return(nil)
+	store.EXPECT().GetFile(mock.Anything, "uploads/gradient.webp").Return(io.NopCloser(bytes.NewReader(data)), "image/webp", nil)
+	repo.EXPECT().DecrementFreeAnalysesUsed(mock.Anything, userID).Return(nil)
+	repo.EXPECT().FailRequest(mock.Anything, requestID, models.ErrorCodeInvalidImage).Return(nil)
+
+	h := NewECGWorker(nil, nil, store, repo, nil, nil, notify.NewHub(), nil, 0, 0.35)
+	j, _ := job.New(userID, job.ECGJobPayload{ImageFileKey: "uploads/gradient.webp", UserID: userID, RequestID: requestID})
+	if err := h.HandleECGJob(context.Background(), j); !errors.Is(err, errNotEKG) {
+		t.Fatalf("expected the WebP gradient to be rejected as not an EKG, got %v", err)
+	}
+}
+
 func TestIsValidImageContentType(t *testing.T) {
 	tests := []struct {
 		contentType string
@@ -72,6 +127,93 @@ func TestIsValidImageContentType(t *testing.T) {
 	}
 }
 
+func TestReadLimited_EnforcesMaxImageBytes(t *testing.T) {
+	w := NewECGWorker(nil, nil, nil, nil, nil, nil, nil, nil, 8, 0)
+
+	data, err := w.readLimited(context.Background(), strings.NewReader("12345678"), "test")
+	if err != nil {
+		t.Fatalf("expected body at the limit to pass, got %v", err)
+	}
+	if len(data) != 8 {
+		t.Errorf("expected 8 bytes, got %d", len(data))
+	}
+
+	_, err = w.readLimited(context.Background(), strings.NewReader("123456789"), "test")
+	if !errors.Is(err, errImageTooLarge) {
+		t.Fatalf("expected errImageTooLarge, got %v", err)
+	}
+}
+
+// newDownloadWorker returns a worker capped at maxBytes whose downloads of
+// any host go to srv, which listens on loopback.
+func newDownloadWorker(srv *httptest.Server, maxBytes int64) *ECGWorker {
+	w := NewECGWorker(nil, nil, nil, nil, nil, nil, nil, nil, maxBytes, 0)
+	w.client = &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, srv.Listener.Addr().String())
+			},
+		},
+	}
+	return w
+}
+
+func TestDownloadImage_CapsBodyWithoutContentLength(t *testing.T) {
+	const maxBytes = 1024
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.WriteHeader(http.StatusOK)
+		// Stream in flushed chunks so no Content-Length is sent.
+		chunk := make([]byte, 256)
+		for range 64 {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer srv.Close()
+
+	w := newDownloadWorker(srv, maxBytes)
+	data, err := w.downloadImage(context.Background(), "http://ekg.example/stream.jpg")
+	if !errors.Is(err, errImageTooLarge) {
+		t.Fatalf("expected errImageTooLarge, got %v (%d bytes)", err, len(data))
+	}
+}
+
+func TestDownloadImage_RejectsDeclaredOversize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Header().Set("Content-Length", "4096")
+		_, _ = w.Write(make([]byte, 4096))
+	}))
+	defer srv.Close()
+
+	w := newDownloadWorker(srv, 1024)
+	if _, err := w.downloadImage(context.Background(), "http://ekg.example/big.jpg"); !errors.Is(err, errImageTooLarge) {
+		t.Fatalf("expected errImageTooLarge, got %v", err)
+	}
+}
+
+func TestDownloadImage_WithinLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("png-bytes"))
+	}))
+	defer srv.Close()
+
+	w := newDownloadWorker(srv, 1024)
+	data, err := w.downloadImage(context.Background(), "http://ekg.example/ok.png")
+	if err != nil {
+		t.Fatalf("downloadImage: %v", err)
+	}
+	if string(data) != "png-bytes" {
+		t.Errorf("unexpected body %q", data)
+	}
+}
+
 func TestCreateMockEKGJob(t *testing.T) {
 	userID := uuid.New()
 	imageURL := "http://example.com/test.jpg"
@@ -0,0 +1,197 @@
+package workers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF decoder
+	_ "image/jpeg" // register JPEG decoder
+	_ "image/png"  // register PNG decoder
+	"math"
+
+	_ "golang.org/x/image/bmp" // register BMP decoder
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/tiff" // register TIFF decoder
+	_ "golang.org/x/image/webp" // register WebP decoder
+
+	"github.com/fedutinova/smartheart/back-api/validation"
+)
+
+// errNotEKG fails a job whose image scored below the plausibility threshold.
+var errNotEKG = errors.New("image does not appear to be an EKG")
+
+const (
+	// ekgCheckWidth is the width images are downscaled to before scoring.
+	ekgCheckWidth = 512
+	// ekgBands is the number of horizontal bands searched for a trace.
+	ekgBands = 8
+	// ekgDetrendWindow is the moving-average window removed from intensity
+	// profiles so uneven lighting does not look like periodicity.
+	ekgDetrendWindow = 15
+)
+
+// ekgImageCheck is the outcome of the EKG plausibility heuristic.
+type ekgImageCheck struct {
+	Confidence float64
+	Plausible  bool
+}
+
+// checkEKGImage scores how much an image looks like an EKG strip, from 0 to
+// 1. It combines three cues: a periodic grid along both axes, a dark trace
+// spanning most of the width within one horizontal band, and a mostly light
+// paper background. The image is plausible when the score reaches threshold.
+// It decodes every image type validation.ImageMimeTypes accepts, after
+// checking the header against validation.MaxImagePixels.
+func checkEKGImage(data []byte, threshold float64) (ekgImageCheck, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ekgImageCheck{}, fmt.Errorf("decode image config: %w", err)
+	}
+	if cfg.Width*cfg.Height > validation.MaxImagePixels {
+		return ekgImageCheck{}, fmt.Errorf("image is %dx%d, over the %d pixel limit", cfg.Width, cfg.Height, validation.MaxImagePixels)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ekgImageCheck{}, fmt.Errorf("decode image: %w", err)
+	}
+	g := downscaleGray(src, ekgCheckWidth)
+
+	conf := 0.4*gridScore(g) + 0.35*traceScore(g) + 0.25*paperScore(g)
+	conf = math.Round(conf*1000) / 1000
+	return ekgImageCheck{Confidence: conf, Plausible: conf >= threshold}, nil
+}
+
+// downscaleGray converts src to grayscale no wider than width pixels.
+func downscaleGray(src image.Image, width int) *image.Gray {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > width {
+		w, h = width, max(1, h*width/w)
+	}
+	dst := image.NewGray(image.Rect(0, 0, w, h))
+	draw.ApproxBiLinear.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	return dst
+}
+
+// gridScore is the mean periodicity of the column and row intensity profiles.
+func gridScore(g *image.Gray) float64 {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	cols := make([]float64, w)
+	rows := make([]float64, h)
+	for y := range h {
+		for x := range w {
+			v := float64(g.Pix[y*g.Stride+x])
+			cols[x] += v
+			rows[y] += v
+		}
+	}
+	return (periodicity(cols) + periodicity(rows)) / 2
+}
+
+// periodicity returns the highest normalized autocorrelation at a local peak
+// of the detrended profile, clamped to [0, 1]. Smooth or noisy profiles have
+// no such peak; ruled paper repeats at the grid pitch.
+func periodicity(profile []float64) float64 {
+	n := len(profile)
+	if n < 4*ekgDetrendWindow {
+		return 0
+	}
+	res := make([]float64, n)
+	half := ekgDetrendWindow / 2
+	for i := range profile {
+		lo, hi := max(0, i-half), min(n, i+half+1)
+		var sum float64
+		for _, v := range profile[lo:hi] {
+			sum += v
+		}
+		res[i] = profile[i] - sum/float64(hi-lo)
+	}
+
+	var energy float64
+	for _, v := range res {
+		energy += v * v
+	}
+	if energy == 0 {
+		return 0
+	}
+	corr := func(lag int) float64 {
+		var s float64
+		for i := 0; i+lag < n; i++ {
+			s += res[i] * res[i+lag]
+		}
+		return s / energy * float64(n) / float64(n-lag)
+	}
+
+	best := 0.0
+	prev, cur := corr(2), corr(3)
+	for lag := 3; lag < n/4; lag++ {
+		next := corr(lag + 1)
+		if cur > prev && cur >= next && cur > best {
+			best = cur
+		}
+		prev, cur = cur, next
+	}
+	return clamp01(best)
+}
+
+// traceScore rewards dark pixels that cross most of the width within one
+// horizontal band while covering only a small part of the image, as an EKG
+// trace does. Photos and dense documents have dark areas everywhere.
+func traceScore(g *image.Gray) float64 {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	cutoff := 0.6 * meanGray(g)
+	bandH := max(1, h/ekgBands)
+
+	var dark int
+	best := 0.0
+	for top := 0; top < h; top += bandH {
+		covered := 0
+		for x := range w {
+			hit := false
+			for y := top; y < min(h, top+bandH); y++ {
+				if float64(g.Pix[y*g.Stride+x]) < cutoff {
+					dark++
+					hit = true
+				}
+			}
+			if hit {
+				covered++
+			}
+		}
+		best = max(best, float64(covered)/float64(w))
+	}
+
+	darkFrac := float64(dark) / float64(w*h)
+	if darkFrac == 0 {
+		return 0
+	}
+	// Full credit up to 15% ink, none at 50%.
+	sparsity := clamp01((0.5 - darkFrac) / 0.35)
+	return best * sparsity
+}
+
+// paperScore is 0 when under 30% of pixels are light and 1 from 80% up.
+func paperScore(g *image.Gray) float64 {
+	var light int
+	for _, v := range g.Pix {
+		if v > 160 {
+			light++
+		}
+	}
+	frac := float64(light) / float64(len(g.Pix))
+	return clamp01((frac - 0.3) / 0.5)
+}
+
+func meanGray(g *image.Gray) float64 {
+	var sum float64
+	for _, v := range g.Pix {
+		sum += float64(v)
+	}
+	return sum / float64(len(g.Pix))
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,163 @@
+package workers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// syntheticEKG draws pink grid paper with a dark trace across the middle.
+func syntheticEKG(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	paper := color.RGBA{255, 250, 250, 255}
+	minor := color.RGBA{250, 205, 205, 255}
+	major := color.RGBA{235, 150, 150, 255}
+	for y := range h {
+		for x := range w {
+			c := paper
+			switch {
+			case x%25 == 0 || y%25 == 0:
+				c = major
+			case x%5 == 0 || y%5 == 0:
+				c = minor
+			}
+			img.Set(x, y, c)
+		}
+	}
+	for x := range w {
+		beat := math.Mod(float64(x), 150)
+		y := h / 2
+		if beat > 60 && beat < 70 {
+			y -= int(8 * (5 - math.Abs(beat-65)))
+		}
+		for dy := -1; dy <= 1; dy++ {
+			img.Set(x, y+dy, color.Black)
+		}
+	}
+	return img
+}
+
+// syntheticPhoto is a dark, smoothly shaded image with no grid or trace.
+func syntheticPhoto(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	cx, cy := float64(w)/2, float64(h)/2
+	for y := range h {
+		for x := range w {
+			d := math.Hypot(float64(x)-cx, float64(y)-cy) / math.Hypot(cx, cy)
+			v := uint8(40 + 120*(1-d))
+			img.Set(x, y, color.RGBA{v, uint8(float64(v) * 0.8), uint8(float64(v) * 0.6), 255})
+		}
+	}
+	return img
+}
+
+func TestCheckEKGImage(t *testing.T) {
+	const threshold = 0.35
+
+	ekg, err := checkEKGImage(encodePNG(t, syntheticEKG(1000, 400)), threshold)
+	if err != nil {
+		t.Fatalf("check EKG: %v", err)
+	}
+	photo, err := checkEKGImage(encodePNG(t, syntheticPhoto(800, 600)), threshold)
+	if err != nil {
+		t.Fatalf("check photo: %v", err)
+	}
+	blank, err := checkEKGImage(encodePNG(t, image.NewGray(image.Rect(0, 0, 600, 400))), threshold)
+	if err != nil {
+		t.Fatalf("check blank: %v", err)
+	}
+
+	if !ekg.Plausible {
+		t.Errorf("expected synthetic EKG to be plausible, got %+v", ekg)
+	}
+	if photo.Plausible {
+		t.Errorf("expected photo to be rejected, got %+v", photo)
+	}
+	if blank.Plausible {
+		t.Errorf("expected blank image to be rejected, got %+v", blank)
+	}
+	if ekg.Confidence <= photo.Confidence {
+		t.Errorf("EKG confidence %v should exceed photo confidence %v", ekg.Confidence, photo.Confidence)
+	}
+}
+
+func TestCheckEKGImage_UndecodableImage(t *testing.T) {
+	if _, err := checkEKGImage([]byte("not an image"), 0.5); err == nil {
+		t.Fatal("expected decode error")
+	}
+}
+
+func TestCheckEKGImage_OversizedImage(t *testing.T) {
+	// A 1x1 PNG whose IHDR is rewritten to declare 100000x100000 pixels.
+	data := encodePNG(t, image.NewGray(image.Rect(0, 0, 1, 1)))
+	binary.BigEndian.PutUint32(data[16:], 100_000)
+	binary.BigEndian.PutUint32(data[20:], 100_000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	_, err := checkEKGImage(data, 0.5)
+	if err == nil || !strings.Contains(err.Error(), "pixel limit") {
+		t.Fatalf("expected pixel limit error, got %v", err)
+	}
+}
+
+func TestCheckEKGImage_DecodesAcceptedFormats(t *testing.T) {
+	img := syntheticEKG(1000, 400)
+	encoders := map[string]func(*bytes.Buffer) error{
+		"png":  func(b *bytes.Buffer) error { return png.Encode(b, img) },
+		"jpeg": func(b *bytes.Buffer) error { return jpeg.Encode(b, img, &jpeg.Options{Quality: 90}) },
+		"gif":  func(b *bytes.Buffer) error { return gif.Encode(b, img, nil) },
+		"bmp":  func(b *bytes.Buffer) error { return bmp.Encode(b, img) },
+		"tiff": func(b *bytes.Buffer) error { return tiff.Encode(b, img, nil) },
+	}
+	for format, encode := range encoders {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := encode(&buf); err != nil {
+				t.Fatalf("encode %s: %v", format, err)
+			}
+			check, err := checkEKGImage(buf.Bytes(), 0.35)
+			if err != nil {
+				t.Fatalf("check %s: %v", format, err)
+			}
+			if !check.Plausible {
+				t.Errorf("expected %s EKG to be plausible, got %+v", format, check)
+			}
+		})
+	}
+}
+
+func TestCheckEKGImage_WebP(t *testing.T) {
+	// A lossy WebP colour gradient, from golang.org/x/image/testdata.
+	data, err := os.ReadFile("testdata/gradient.webp")
+	if err != nil {
+		t.Fatalf("read testdata: %v", err)
+	}
+	check, err := checkEKGImage(data, 0.35)
+	if err != nil {
+		t.Fatalf("check WebP: %v", err)
+	}
+	if check.Plausible {
+		t.Errorf("expected the gradient to be rejected, got %+v", check)
+	}
+}
@@ -2,7 +2,6 @@ package workers
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -25,25 +24,27 @@ type GPTWorker struct {
 	gptClient gpt.Processor
 	repo      repository.RequestRepo
 	hub       *notify.Hub
+	webhooks  *WebhookSender
+	// txOpts configure the transaction-scoped repositories, e.g. with
+	// repository.WithFieldCipher.
+	txOpts []func(*repository.Repository)
 }
 
-func NewGPTWorker(txb database.TxBeginner, gptClient gpt.Processor, repo repository.RequestRepo, hub *notify.Hub) *GPTWorker {
+func NewGPTWorker(txb database.TxBeginner, gptClient gpt.Processor, repo repository.RequestRepo, hub *notify.Hub, webhooks *WebhookSender, txOpts ...func(*repository.Repository)) *GPTWorker {
 	return &GPTWorker{
 		txb:       txb,
 		gptClient: gptClient,
 		repo:      repo,
 		hub:       hub,
+		webhooks:  webhooks,
+		txOpts:    txOpts,
 	}
 }
 
 func (h *GPTWorker) HandleGPTJob(ctx context.Context, j *job.Job) error {
-	if j.Type != job.TypeGPTProcess {
-		return fmt.Errorf("unexpected job type: %s", j.Type)
-	}
-
-	var payload gpt.JobPayload
-	if err := json.Unmarshal(j.Payload, &payload); err != nil {
-		return fmt.Errorf("failed to unmarshal job payload: %w", err)
+	payload, err := job.DecodePayload[gpt.JobPayload](j)
+	if err != nil {
+		return err
 	}
 
 	if err := h.repo.UpdateRequestStatus(ctx, payload.RequestID, models.StatusProcessing); err != nil {
@@ -52,30 +53,35 @@ func (h *GPTWorker) HandleGPTJob(ctx context.Context, j *job.Job) error {
 
 	result, err := h.processWithFallback(ctx, payload)
 	if err != nil {
-		if updateErr := h.repo.UpdateRequestStatus(ctx, payload.RequestID, models.StatusFailed); updateErr != nil {
+		code := gpt.ErrorCode(err)
+		if updateErr := h.repo.FailRequest(ctx, payload.RequestID, code); updateErr != nil {
 			slog.ErrorContext(ctx, "Failed to update request status to failed", "request_id", payload.RequestID, "error", updateErr)
 		}
 		h.notifyUser(payload.UserID, payload.RequestID, models.StatusFailed)
-		return fmt.Errorf("gpt processing failed: %w", err)
+		h.webhooks.Notify(ctx, payload.CallbackURL, newWebhookEvent(payload.RequestID, models.StatusFailed, ""))
+		return job.WithCode(code, fmt.Errorf("gpt processing failed: %w", err))
 	}
 
 	if txErr := h.saveGPTResult(ctx, payload, result); txErr != nil {
-		if updateErr := h.repo.UpdateRequestStatus(ctx, payload.RequestID, models.StatusFailed); updateErr != nil {
+		if updateErr := h.repo.FailRequest(ctx, payload.RequestID, models.ErrorCodeInternal); updateErr != nil {
 			slog.ErrorContext(ctx, "Failed to update request status to failed after tx error",
 				"request_id", payload.RequestID, "error", updateErr)
 		}
 		h.notifyUser(payload.UserID, payload.RequestID, models.StatusFailed)
+		h.webhooks.Notify(ctx, payload.CallbackURL, newWebhookEvent(payload.RequestID, models.StatusFailed, ""))
 		return txErr
 	}
 
 	h.notifyUser(payload.UserID, payload.RequestID, models.StatusCompleted)
+	h.webhooks.Notify(ctx, payload.CallbackURL,
+		newWebhookEvent(payload.RequestID, models.StatusCompleted, gptConclusion(result.Content)))
 	return nil
 }
 
 // saveGPTResult persists the GPT response and marks the request as completed in a single transaction.
 func (h *GPTWorker) saveGPTResult(ctx context.Context, payload gpt.JobPayload, result *gpt.ProcessResult) error {
 	return h.txb.WithTx(ctx, func(tx database.Tx) error {
-		txRepo := repository.NewTxScoped(tx)
+		txRepo := repository.NewTxScoped(tx, h.txOpts...)
 
 		response := &models.Response{
 			RequestID:        payload.RequestID,
@@ -83,6 +89,11 @@ func (h *GPTWorker) saveGPTResult(ctx context.Context, payload gpt.JobPayload, r
 			Model:            result.Model,
 			TokensUsed:       result.TokensUsed,
 			ProcessingTimeMs: result.ProcessingTimeMs,
+			FinishReason:     result.FinishReason,
+			IsRefusal:        result.Refused,
+		}
+		if result.Cached {
+			response.CacheStatus = "HIT"
 		}
 		if err := txRepo.CreateResponse(ctx, response); err != nil {
 			return fmt.Errorf("failed to save response: %w", err)
@@ -116,7 +127,7 @@ func (h *GPTWorker) notifyUser(userID, requestID uuid.UUID, status string) {
 
 // processWithFallback calls GPT and falls back to EKG data if GPT fails or refuses.
 func (h *GPTWorker) processWithFallback(ctx context.Context, payload gpt.JobPayload) (*gpt.ProcessResult, error) {
-	result, gptErr := h.gptClient.ProcessRequest(ctx, payload.TextQuery, payload.FileKeys)
+	result, gptErr := h.gptClient.ProcessRequest(ctx, payload.TextQuery, payload.FileKeys, payload.RequestOptions())
 
 	// Happy path: GPT succeeded and didn't refuse
 	if gptErr == nil && result != nil && !gpt.IsRefusal(result.Content) {
@@ -133,7 +144,7 @@ func (h *GPTWorker) processWithFallback(ctx context.Context, payload gpt.JobPayl
 		if gptErr != nil {
 			return nil, gptErr
 		}
-		return result, nil
+		return markRefused(ctx, payload, result), nil
 	}
 
 	// Try fallback
@@ -150,8 +161,8 @@ func (h *GPTWorker) processWithFallback(ctx context.Context, payload gpt.JobPayl
 		if gptErr != nil {
 			return nil, gptErr
 		}
-		// Refusal with no fallback — return original response as-is
-		return result, nil
+		// Refusal with no fallback
+		return markRefused(ctx, payload, result), nil
 	}
 
 	if gptErr != nil {
@@ -168,9 +179,27 @@ func (h *GPTWorker) processWithFallback(ctx context.Context, payload gpt.JobPayl
 		"fallback_length", len(fallbackContent))
 	result.Content = fallbackContent
 	result.Model += "_with_fallback"
+	result.Refused = false
 	return result, nil
 }
 
+// refusedMessage replaces the refusal text in responses marked ModelRefused.
+const refusedMessage = "Модель отказалась обработать этот запрос даже после повторной попытки с нейтральной формулировкой. " +
+	"Попробуйте загрузить другое изображение или переформулировать вопрос."
+
+// markRefused turns a refusal into a response with model ModelRefused and a
+// meaningful message, so clients can tell it apart from a real answer.
+func markRefused(ctx context.Context, payload gpt.JobPayload, result *gpt.ProcessResult) *gpt.ProcessResult {
+	slog.WarnContext(ctx, "Storing GPT refusal as refused response",
+		"request_id", payload.RequestID,
+		"model", result.Model,
+		"response_preview", truncate(result.Content, 200))
+	result.Content = refusedMessage
+	result.Model = models.ModelRefused
+	result.Refused = true
+	return result
+}
+
 // createFallbackResponse creates a response from EKG analysis data when GPT fails or refuses
 func (h *GPTWorker) createFallbackResponse(ctx context.Context, payload gpt.JobPayload) (string, error) {
 	request, err := h.repo.GetRequestByID(ctx, payload.RequestID)
@@ -183,13 +212,27 @@ func (h *GPTWorker) createFallbackResponse(ctx context.Context, payload gpt.JobP
 		textQuery = *request.TextQuery
 	}
 
+	// The EKG request this GPT request was created for, if linked.
+	if request.ParentRequestID != nil {
+		parent, err := h.repo.GetRequestByID(ctx, *request.ParentRequestID)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to get parent EKG request for fallback",
+				"request_id", payload.RequestID, "parent_request_id", *request.ParentRequestID, "error", err)
+		} else if parent.Response != nil {
+			if ekg, _ := models.ParseECGContent(parent.Response.Content); ekg != nil {
+				return formatECGFallback(ekg, textQuery), nil
+			}
+		}
+	}
+
 	// Fetch recent requests with responses in a single query (avoids N+1).
 	userRequests, err := h.repo.GetRecentRequestsWithResponses(ctx, request.UserID, 10)
 	if err != nil {
 		return formatBasicFallback(textQuery), nil //nolint:nilerr // intentionally return fallback on fetch error
 	}
 
-	// First pass: prefer the EKG response that references this exact GPT request
+	// First pass: prefer the legacy EKG response that names this GPT request
+	// in its content
 	for i := range userRequests {
 		if userRequests[i].ID == payload.RequestID || userRequests[i].Response == nil {
 			continue
@@ -83,6 +83,25 @@ func TestHandleGPTJob_UpdateStatusFails(t *testing.T) {
 	}
 }
 
+func TestHandleGPTJob_RecordsErrorCode(t *testing.T) {
+	requestID := uuid.New()
+	repo := repomocks.NewMockRequestRepo(t)
+	repo.EXPECT().UpdateRequestStatus(mock.Anything, requestID, models.StatusProcessing).Return(nil)
+	repo.EXPECT().FailRequest(mock.Anything, requestID, models.ErrorCodeRateLimited).Return(nil)
+
+	gptErr := errors.New("OpenAI API quota exceeded: insufficient_quota")
+	h := &GPTWorker{repo: repo, gptClient: stubProcessor{err: gptErr}}
+
+	payloadBytes, _ := json.Marshal(gpt.JobPayload{RequestID: requestID, TextQuery: "test query", UserID: uuid.New()})
+	err := h.HandleGPTJob(context.Background(), &job.Job{ID: uuid.New(), Type: job.TypeGPTProcess, Payload: payloadBytes})
+	if !errors.Is(err, gptErr) {
+		t.Fatalf("expected the GPT error to be returned, got %v", err)
+	}
+	if code := job.CodeOf(err); code != models.ErrorCodeRateLimited {
+		t.Errorf("expected the job error tagged %q, got %q", models.ErrorCodeRateLimited, code)
+	}
+}
+
 // --- formatECGFallback tests ---
 
 func TestFormatEKGFallback_WithNotesAndQuery(t *testing.T) {
@@ -162,6 +181,54 @@ func TestFormatBasicFallback_EmptyQuery(t *testing.T) {
 	}
 }
 
+// --- processWithFallback tests ---
+
+// stubProcessor returns a fixed ProcessRequest result.
+type stubProcessor struct {
+	result *gpt.ProcessResult
+	err    error
+}
+
+func (s stubProcessor) ProcessRequest(context.Context, string, []string, ...gpt.RequestOptions) (*gpt.ProcessResult, error) {
+	return s.result, s.err
+}
+
+func (s stubProcessor) ProcessStructuredECG(context.Context, []string, string, string) (*gpt.ProcessResult, error) {
+	return s.result, s.err
+}
+
+func TestProcessWithFallback_MarksRefusal(t *testing.T) {
+	refusal := &gpt.ProcessResult{Content: "I'm sorry, I can't assist with that.", Model: "gpt-4o", Refused: true}
+	h := &GPTWorker{gptClient: stubProcessor{result: refusal}}
+
+	result, err := h.processWithFallback(context.Background(), gpt.JobPayload{RequestID: uuid.New(), TextQuery: "what is this?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Model != models.ModelRefused {
+		t.Errorf("expected model %q, got %q", models.ModelRefused, result.Model)
+	}
+	if gpt.IsRefusal(result.Content) {
+		t.Errorf("expected refusal text to be replaced, got %q", result.Content)
+	}
+	if !result.Refused {
+		t.Error("expected the result to stay marked refused, for is_refusal")
+	}
+}
+
+func TestProcessWithFallback_KeepsAnswer(t *testing.T) {
+	answer := &gpt.ProcessResult{Content: "Синусовый ритм", Model: "gpt-4o"}
+	h := &GPTWorker{gptClient: stubProcessor{result: answer}}
+
+	result, err := h.processWithFallback(context.Background(), gpt.JobPayload{RequestID: uuid.New(), TextQuery: "what is this?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Model != "gpt-4o" || result.Content != "Синусовый ритм" {
+		t.Errorf("expected answer unchanged, got %+v", result)
+	}
+}
+
 // --- createFallbackResponse tests ---
 
 func TestCreateFallbackResponse_NoEKGData(t *testing.T) {
@@ -246,6 +313,36 @@ func TestCreateFallbackResponse_WithEKGResponse(t *testing.T) {
 	}
 }
 
+func TestCreateFallbackResponse_UsesParentEKGRequest(t *testing.T) {
+	requestID := uuid.New()
+	userID := uuid.New()
+	parentID := uuid.New()
+
+	ekgJSON, _ := (&models.ECGResponseContent{
+		AnalysisType: models.ECGModelDirect,
+		Notes:        "parent notes",
+		Timestamp:    "2026-01-01T00:00:00Z",
+	}).Marshal()
+
+	// No recent-requests scan: the parent link is enough.
+	repo := repomocks.NewMockRequestRepo(t)
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, requestID).
+		Return(&models.Request{ID: requestID, UserID: userID, ParentRequestID: &parentID}, nil)
+	repo.EXPECT().
+		GetRequestByID(mock.Anything, parentID).
+		Return(&models.Request{ID: parentID, UserID: userID, Response: &models.Response{Content: ekgJSON}}, nil)
+
+	h := &GPTWorker{repo: repo}
+	result, err := h.createFallbackResponse(context.Background(), gpt.JobPayload{RequestID: requestID, UserID: userID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result, "parent notes") {
+		t.Errorf("expected parent EKG notes in fallback, got %q", result)
+	}
+}
+
 func TestCreateFallbackResponse_RequestNotFound(t *testing.T) {
 	requestID := uuid.New()
 
@@ -0,0 +1,196 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fedutinova/smartheart/back-api/models"
+	"github.com/fedutinova/smartheart/back-api/repository"
+)
+
+// Webhook request headers. The signature is hex(HMAC-SHA256(key, timestamp + "." + body))
+// where key = HMAC-SHA256(JWT secret, webhookKeyLabel).
+const (
+	WebhookSignatureHeader = "X-SmartHeart-Signature"
+	WebhookTimestampHeader = "X-SmartHeart-Timestamp"
+
+	webhookKeyLabel = "smartheart-webhook-v1"
+
+	defaultWebhookAttempts  = 4
+	defaultWebhookBaseDelay = time.Second
+	webhookTimeout          = 10 * time.Second
+	// webhookDeliveryBudget bounds a whole delivery including retries.
+	webhookDeliveryBudget = 2 * time.Minute
+)
+
+// WebhookEvent is the JSON body POSTed to a request's callback URL.
+type WebhookEvent struct {
+	RequestID  uuid.UUID `json:"request_id"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+func newWebhookEvent(requestID uuid.UUID, status, conclusion string) WebhookEvent {
+	return WebhookEvent{RequestID: requestID, Status: status, Conclusion: conclusion, Timestamp: time.Now().UTC()}
+}
+
+// gptConclusion picks the conclusion out of a stored GPT response.
+func gptConclusion(content string) string {
+	if structured, ok := models.ParseGPTStructuredResult(content); ok {
+		return structured.Conclusion
+	}
+	return models.ExtractConclusion(content)
+}
+
+// WebhookSender delivers signed completion callbacks with retries and records
+// the outcome on the request. A nil *WebhookSender is a no-op.
+type WebhookSender struct {
+	client      *http.Client
+	repo        repository.RequestRepo
+	key         []byte
+	maxAttempts int
+	baseDelay   time.Duration
+}
+
+// NewWebhookSender creates a sender whose signing key is derived from secret.
+func NewWebhookSender(secret string, repo repository.RequestRepo) *WebhookSender {
+	return &WebhookSender{
+		client: &http.Client{
+			Timeout:   webhookTimeout,
+			Transport: sharedSSRFTransport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		repo:        repo,
+		key:         DeriveWebhookKey(secret),
+		maxAttempts: defaultWebhookAttempts,
+		baseDelay:   defaultWebhookBaseDelay,
+	}
+}
+
+// DeriveWebhookKey returns the HMAC key used to sign webhooks for a JWT secret.
+func DeriveWebhookKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(webhookKeyLabel))
+	return mac.Sum(nil)
+}
+
+// SignWebhook returns the hex signature for a webhook body sent at timestamp.
+func SignWebhook(key []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Notify delivers ev to url in the background. It does nothing when url is
+// empty or the sender is nil.
+func (s *WebhookSender) Notify(ctx context.Context, url string, ev WebhookEvent) {
+	if s == nil || url == "" {
+		return
+	}
+	// Detach from the job context so delivery outlives the job handler.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), webhookDeliveryBudget)
+	go func() {
+		defer cancel()
+		s.deliver(ctx, url, ev)
+	}()
+}
+
+// deliver POSTs ev with retries and records the final status on the request.
+func (s *WebhookSender) deliver(ctx context.Context, url string, ev WebhookEvent) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to marshal webhook event", "request_id", ev.RequestID, "error", err)
+		return
+	}
+
+	var (
+		attempts int
+		lastErr  error
+	)
+	for attempts < s.maxAttempts {
+		if attempts > 0 {
+			if err := sleepCtx(ctx, s.baseDelay<<(attempts-1)); err != nil {
+				lastErr = fmt.Errorf("%w (last error: %w)", err, lastErr)
+				break
+			}
+		}
+		attempts++
+
+		var retry bool
+		retry, lastErr = s.post(ctx, url, body)
+		if lastErr == nil || !retry {
+			break
+		}
+		slog.WarnContext(ctx, "Webhook delivery failed, retrying",
+			"request_id", ev.RequestID, "attempt", attempts, "error", lastErr)
+	}
+
+	status, errMsg := models.CallbackDelivered, ""
+	if lastErr != nil {
+		status, errMsg = models.CallbackFailed, lastErr.Error()
+		slog.ErrorContext(ctx, "Webhook delivery failed", "request_id", ev.RequestID, "attempts", attempts, "error", lastErr)
+	}
+	// Record even if the delivery budget ran out.
+	ctx = context.WithoutCancel(ctx)
+	if err := s.repo.UpdateCallbackStatus(ctx, ev.RequestID, status, attempts, errMsg); err != nil {
+		slog.ErrorContext(ctx, "Failed to record webhook status", "request_id", ev.RequestID, "error", err)
+	}
+}
+
+// post sends one attempt. retry reports whether a failure is worth retrying:
+// network errors, 408, 429 and 5xx are; other 4xx are not.
+func (s *WebhookSender) post(ctx context.Context, url string, body []byte) (retry bool, err error) {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookTimestampHeader, ts)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhook(s.key, ts, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("send webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode >= 500 ||
+		resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
+// sleepCtx waits for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,122 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/fedutinova/smartheart/back-api/models"
+	repomocks "github.com/fedutinova/smartheart/back-api/repository/mocks"
+)
+
+func newTestWebhookSender(t *testing.T) (*WebhookSender, *repomocks.MockRequestRepo) {
+	repo := repomocks.NewMockRequestRepo(t)
+	s := NewWebhookSender("test-secret", repo)
+	s.client = &http.Client{Timeout: time.Second} // test servers listen on loopback
+	s.baseDelay = time.Millisecond
+	return s, repo
+}
+
+func TestWebhookDeliver_SignsAndRetriesServerErrors(t *testing.T) {
+	s, repo := newTestWebhookSender(t)
+	requestID := uuid.New()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		want := "sha256=" + SignWebhook(DeriveWebhookKey("test-secret"), r.Header.Get(WebhookTimestampHeader), body)
+		if got := r.Header.Get(WebhookSignatureHeader); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+		var ev WebhookEvent
+		if err := json.Unmarshal(body, &ev); err != nil || ev.RequestID != requestID || ev.Conclusion != "Норма" {
+			t.Errorf("unexpected event %s (err %v)", body, err)
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	repo.EXPECT().
+		UpdateCallbackStatus(mock.Anything, requestID, models.CallbackDelivered, 3, "").
+		Return(nil)
+
+	s.deliver(context.Background(), srv.URL, newWebhookEvent(requestID, models.StatusCompleted, "Норма"))
+
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestWebhookDeliver_DoesNotRetryClientErrors(t *testing.T) {
+	s, repo := newTestWebhookSender(t)
+	requestID := uuid.New()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer srv.Close()
+
+	repo.EXPECT().
+		UpdateCallbackStatus(mock.Anything, requestID, models.CallbackFailed, 1,
+			mock.MatchedBy(func(msg string) bool { return strings.Contains(msg, "410") })).
+		Return(nil)
+
+	s.deliver(context.Background(), srv.URL, newWebhookEvent(requestID, models.StatusFailed, ""))
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestWebhookDeliver_GivesUpAfterMaxAttempts(t *testing.T) {
+	s, repo := newTestWebhookSender(t)
+	requestID := uuid.New()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	repo.EXPECT().
+		UpdateCallbackStatus(mock.Anything, requestID, models.CallbackFailed, defaultWebhookAttempts, mock.Anything).
+		Return(nil)
+
+	s.deliver(context.Background(), srv.URL, newWebhookEvent(requestID, models.StatusCompleted, ""))
+}
+
+func TestWebhookSender_RejectsPrivateAddresses(t *testing.T) {
+	repo := repomocks.NewMockRequestRepo(t)
+	s := NewWebhookSender("test-secret", repo)
+	s.maxAttempts = 1
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("request to loopback should have been blocked")
+	}))
+	defer srv.Close()
+
+	repo.EXPECT().
+		UpdateCallbackStatus(mock.Anything, mock.Anything, models.CallbackFailed, 1, mock.Anything).
+		Return(nil)
+
+	s.deliver(context.Background(), srv.URL, newWebhookEvent(uuid.New(), models.StatusCompleted, ""))
+}
+
+func TestWebhookSender_NilIsNoop(t *testing.T) {
+	var s *WebhookSender
+	s.Notify(context.Background(), "https://example.com/hook", WebhookEvent{})
+}
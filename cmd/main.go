@@ -3,19 +3,25 @@ package main
 import (
 	"context"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"google.golang.org/grpc"
+
 	"github.com/fedutinova/smartheart/back-api/auth"
 	appconfig "github.com/fedutinova/smartheart/back-api/config"
 	"github.com/fedutinova/smartheart/back-api/database"
 	"github.com/fedutinova/smartheart/back-api/gpt"
+	"github.com/fedutinova/smartheart/back-api/grpcapi"
 	"github.com/fedutinova/smartheart/back-api/handler"
 	"github.com/fedutinova/smartheart/back-api/job"
+	"github.com/fedutinova/smartheart/back-api/logging"
 	"github.com/fedutinova/smartheart/back-api/mail"
+	"github.com/fedutinova/smartheart/back-api/metrics"
 	"github.com/fedutinova/smartheart/back-api/notify"
 	"github.com/fedutinova/smartheart/back-api/queue"
 	"github.com/fedutinova/smartheart/back-api/repository"
@@ -23,40 +29,59 @@ import (
 	"github.com/fedutinova/smartheart/back-api/service"
 	"github.com/fedutinova/smartheart/back-api/session"
 	"github.com/fedutinova/smartheart/back-api/storage"
+	"github.com/fedutinova/smartheart/back-api/telemetry"
 	"github.com/fedutinova/smartheart/back-api/workers"
 )
 
-// Set at build time via -ldflags.
+// Set at build time via -ldflags:
+//
+//	-X main.Version=v1.2.3 -X main.Commit=$(git rev-parse HEAD) -X main.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)
 var (
-	Version = "dev"
-	Commit  = "unknown"
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
 )
 
 func main() {
 	cfg := appconfig.Load()
-	initLogger()
+	slog.SetDefault(logging.New(cfg.Log, os.Stderr))
 	validateConfig(cfg)
 
-	slog.Info("starting smartheart", "addr", cfg.HTTPAddr, "workers", cfg.Queue.Workers, "version", Version, "commit", Commit)
+	slog.Info("starting smartheart", "addr", cfg.HTTPAddr, "workers", cfg.Queue.Workers, "version", Version, "commit", Commit, "build_time", BuildTime)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	shutdownTracing := initTracing(ctx, cfg)
+	defer shutdownTracing()
+
 	db, sessions, storageService := initInfra(ctx, cfg)
 	defer db.Close()
 	defer func() { _ = sessions.Close() }()
 
 	runMigrations(ctx, db)
 
-	repo := repository.New(db, repository.WithQueryTimeout(cfg.DB.QueryTimeout))
+	fieldCipher, err := repository.NewFieldCipher(cfg.Encryption.Keys, cfg.Encryption.ActiveVersion)
+	if err != nil {
+		slog.Error("Failed to set up field encryption", "error", err)
+		os.Exit(1)
+	}
+	repo := repository.New(db,
+		repository.WithQueryTimeout(cfg.DB.QueryTimeout),
+		repository.WithFieldCipher(fieldCipher))
 	loadPermissions(ctx, repo)
 
 	q := initQueue(cfg, sessions)
 	defer func() { _ = q.Close() }()
 
 	hub := notify.NewHub()
+	prompts, err := gpt.LoadPromptSet(cfg.GPT.PromptDir)
+	if err != nil {
+		slog.Error("failed to load prompt templates", "dir", cfg.GPT.PromptDir, "err", err)
+		os.Exit(1)
+	}
 	var gptClient gpt.Processor
-	if os.Getenv("GPT_MOCK") == "true" {
+	if cfg.GPT.Mock {
 		mockDelay, _ := time.ParseDuration(os.Getenv("GPT_MOCK_DELAY"))
 		if mockDelay == 0 {
 			mockDelay = 5 * time.Second
@@ -64,33 +89,62 @@ func main() {
 		slog.Warn("GPT_MOCK enabled — using simulated responses", "delay", mockDelay)
 		gptClient = &gpt.MockProcessor{Delay: mockDelay}
 	} else {
-		gptClient = gpt.NewClient(cfg.GPT.APIKey, storageService, gpt.WithModel(cfg.GPT.Model))
+		apiKey, model := cfg.GPT.APIKey, cfg.GPT.Model
+		var provider gpt.Provider // nil keeps the default OpenAI provider
+		if cfg.GPT.Provider == appconfig.AIProviderAnthropic {
+			model = cfg.GPT.AnthropicModel
+			provider = gpt.NewAnthropicProvider(cfg.GPT.AnthropicAPIKey)
+		}
+		slog.Info("AI provider configured", "provider", cfg.GPT.Provider, "model", model)
+		gptClient = gpt.NewClient(apiKey, storageService,
+			gpt.WithProvider(provider),
+			gpt.WithBaseURL(cfg.GPT.BaseURL),
+			gpt.WithModel(model),
+			gpt.WithMaxTokens(cfg.GPT.MaxTokens),
+			gpt.WithRetry(cfg.GPT.MaxAttempts, cfg.GPT.RetryBaseDelay),
+			gpt.WithMaxImageDimension(cfg.GPT.MaxImageDimension),
+			gpt.WithMaxFileBytes(cfg.Storage.MaxImageBytes),
+			gpt.WithPresignTTL(cfg.Storage.PresignTTL),
+			gpt.WithCache(gpt.NewRedisCache(sessions.Client()), cfg.GPT.CacheTTL),
+			gpt.WithPrompts(prompts),
+		)
+	}
+	startWorkers(ctx, cfg, db, q, storageService, repo, fieldCipher, hub, gptClient, prompts)
+	srv, grpcSrv, audit := startServers(cfg, db, repo, sessions, storageService, q, hub)
+	// Flush queued audit entries after the servers stop, before the DB closes.
+	defer audit.Close()
+	if metricsSrv := startMetricsServer(cfg, db, q); metricsSrv != nil {
+		defer func() { _ = metricsSrv.Close() }()
 	}
-	startWorkers(ctx, cfg, db, q, storageService, repo, hub, gptClient)
-	srv := startHTTPServer(cfg, repo, sessions, storageService, q, hub)
 
 	// Cancel pending payments older than 1 hour, check every 10 minutes.
 	service.StartStalePaymentCleaner(ctx, repo, 10*time.Minute, 1*time.Hour)
 
-	waitForShutdown(srv, cancel)
+	// Delete uploads left behind by failed submissions.
+	service.StartOrphanFileSweeper(ctx, repo, storageService, cfg.Storage.GCInterval, cfg.Storage.GCGrace)
+
+	// Purge expired and long-revoked refresh tokens.
+	service.StartRefreshTokenCleaner(ctx, repo, cfg.JWT.RefreshGCInterval, cfg.JWT.RefreshGCRetention)
+
+	waitForShutdown(srv, grpcSrv, cancel)
 }
 
-func initLogger() {
-	logLevel := slog.LevelInfo
-	if v := os.Getenv("LOG_LEVEL"); v != "" {
-		switch v {
-		case "debug":
-			logLevel = slog.LevelDebug
-		case "warn":
-			logLevel = slog.LevelWarn
-		case "error":
-			logLevel = slog.LevelError
+func initTracing(ctx context.Context, cfg appconfig.Config) func() {
+	shutdown, err := telemetry.Init(ctx, cfg.Telemetry, Version)
+	if err != nil {
+		slog.Error("failed to initialize tracing", "err", err)
+		os.Exit(1)
+	}
+	if cfg.Telemetry.Enabled {
+		slog.Info("OpenTelemetry tracing enabled", "service", cfg.Telemetry.ServiceName)
+	}
+	return func() {
+		shCtx, shCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shCancel()
+		if err := shutdown(shCtx); err != nil {
+			slog.Error("tracing shutdown error", "err", err)
 		}
 	}
-	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
-		Level:     logLevel,
-		AddSource: logLevel == slog.LevelDebug,
-	})))
 }
 
 func runMigrations(ctx context.Context, db *database.DB) {
@@ -124,6 +178,9 @@ func initInfra(ctx context.Context, cfg appconfig.Config) (*database.DB, *sessio
 	db, err := database.NewDB(ctx, cfg.DB.URL, func(pc *database.PoolConfig) {
 		pc.MaxConns = int32(cfg.DB.MaxConns)
 		pc.MinConns = int32(cfg.DB.MinConns)
+		pc.MaxConnLifetime = cfg.DB.MaxConnLifetime
+		pc.MaxConnIdleTime = cfg.DB.MaxConnIdleTime
+		pc.HealthCheckPeriod = cfg.DB.HealthCheckPeriod
 	})
 	if err != nil {
 		slog.Error("failed to connect to database", "err", err)
@@ -167,6 +224,13 @@ func initQueue(cfg appconfig.Config, sessions *session.Service) job.Queue {
 			MaxJobTime:    cfg.Queue.MaxDuration,
 			ClaimInterval: 10 * time.Second,
 			ClaimTimeout:  cfg.Queue.ClaimTimeout,
+			TypeWorkers:   typeWorkers(cfg),
+
+			RetryBaseDelay:   cfg.Queue.RetryBaseDelay,
+			RetryMaxDelay:    cfg.Queue.RetryMaxDelay,
+			StreamMaxLen:     int64(cfg.Queue.StreamMaxLen),
+			DeadLetterMaxLen: int64(cfg.Queue.DeadLetterMaxLen),
+			MaxWorkers:       cfg.Queue.MaxWorkers,
 		})
 		if err != nil {
 			slog.Error("failed to create Redis queue", "err", err)
@@ -176,13 +240,26 @@ func initQueue(cfg appconfig.Config, sessions *session.Service) job.Queue {
 		return redisQueue
 	default:
 		slog.Warn("using in-memory queue (not recommended for production)")
-		return queue.NewMemoryQueue(cfg.Queue.Buffer, cfg.Queue.MaxDuration)
+		return queue.NewMemoryQueue(cfg.Queue.Buffer, cfg.Queue.MaxDuration,
+			queue.WithTypeWorkers(typeWorkers(cfg)),
+			queue.WithMaxWorkers(cfg.Queue.MaxWorkers))
 	}
 }
 
-func startWorkers(ctx context.Context, cfg appconfig.Config, db *database.DB, q job.Queue, storageService storage.Storage, repo repository.Store, hub *notify.Hub, gptClient gpt.Processor) {
-	gptWorker := workers.NewGPTWorker(db, gptClient, repo, hub)
-	ecgWorker := workers.NewECGWorker(db, q, storageService, repo, gptClient, hub)
+func typeWorkers(cfg appconfig.Config) map[job.Type]int {
+	limits := make(map[job.Type]int, len(cfg.Queue.TypeWorkers))
+	for t, n := range cfg.Queue.TypeWorkers {
+		limits[job.Type(t)] = n
+	}
+	return limits
+}
+
+func startWorkers(ctx context.Context, cfg appconfig.Config, db *database.DB, q job.Queue, storageService storage.Storage, repo repository.Store, fieldCipher *repository.FieldCipher, hub *notify.Hub, gptClient gpt.Processor, prompts *gpt.PromptSet) {
+	webhooks := workers.NewWebhookSender(cfg.JWT.Secret, repo)
+	// Worker transactions use their own repositories, which need the cipher too.
+	withCipher := repository.WithFieldCipher(fieldCipher)
+	gptWorker := workers.NewGPTWorker(db, gptClient, repo, hub, webhooks, withCipher)
+	ecgWorker := workers.NewECGWorker(db, q, storageService, repo, gptClient, prompts, hub, webhooks, cfg.Storage.MaxImageBytes, cfg.ECG.MinImageConfidence, withCipher)
 
 	registry := job.NewRegistry()
 	registry.Register(job.TypeECGAnalyze, ecgWorker.HandleECGJob)
@@ -191,19 +268,27 @@ func startWorkers(ctx context.Context, cfg appconfig.Config, db *database.DB, q
 	q.StartConsumers(ctx, cfg.Queue.Workers, registry.Dispatch)
 }
 
-func startHTTPServer(
+// startServers starts the HTTP server and, when GRPC_ADDR is set, the gRPC
+// API. The returned gRPC server is nil when it is disabled.
+func startServers(
 	cfg appconfig.Config,
+	db *database.DB,
 	repo repository.Store,
 	sessions *session.Service,
 	storageService storage.Storage,
 	q job.Queue,
 	hub *notify.Hub,
-) *http.Server {
-	authSvc := service.NewAuthService(repo, sessions, cfg.JWT)
-	mailer := mail.NewSender(cfg.SMTP)
-	passwordSvc := service.NewPasswordService(repo, sessions, mailer, cfg)
+) (*http.Server, *grpc.Server, *handler.Auditor) {
+	keys, err := auth.LoadKeys(cfg.JWT)
+	if err != nil {
+		slog.Error("failed to load JWT keys", "err", err)
+		os.Exit(1)
+	}
+	notifier := mail.NewNotifier(cfg.SMTP)
+	authSvc := service.NewAuthService(repo, sessions, keys, notifier, cfg)
+	passwordSvc := service.NewPasswordService(repo, sessions, notifier, cfg)
 	submissionSvc := service.NewSubmissionService(repo, q, storageService, cfg.Quota)
-	requestSvc := service.NewRequestService(repo, q)
+	requestSvc := service.NewRequestService(repo, q, storageService)
 	paymentSvc := service.NewPaymentService(repo, cfg.YooKassa, cfg.Quota.FreeLimit)
 	ecgChatSvc := service.NewECGChatService(repo, cfg.RAG.URL)
 
@@ -220,16 +305,24 @@ func startHTTPServer(
 		if cfg.RateLimit.PasswordResetRPM > 0 {
 			mw.PasswordResetRateLimit = server.EndpointRateLimit(cfg.RateLimit.PasswordResetRPM)
 		}
+		if cfg.RateLimit.VerifyResendRPM > 0 {
+			mw.VerifyResendRateLimit = server.EndpointRateLimit(cfg.RateLimit.VerifyResendRPM)
+		}
 	}
-	handlers := handler.NewHandler(authSvc, passwordSvc, submissionSvc, requestSvc, paymentSvc, ecgChatSvc, q, repo, sessions, storageService, hub, cfg, mw)
+	handlers := handler.NewHandler(authSvc, passwordSvc, submissionSvc, requestSvc, paymentSvc, ecgChatSvc, q, repo, sessions, keys, storageService, hub, cfg, mw)
+	handlers.Healthz.Build = handler.BuildInfo{Version: Version, Commit: Commit, BuildTime: BuildTime}
+	handlers.Healthz.DBPool = db.Pool()
 	r := server.NewRouter(handlers, cfg)
 
 	srv := &http.Server{
-		Addr:        cfg.HTTPAddr,
-		Handler:     r,
-		ReadTimeout: 30 * time.Second,
-		IdleTimeout: 90 * time.Second,
+		Addr:         cfg.HTTPAddr,
+		Handler:      r,
+		ReadTimeout:  cfg.HTTP.ReadTimeout,
+		WriteTimeout: cfg.HTTP.WriteTimeout,
+		IdleTimeout:  cfg.HTTP.IdleTimeout,
 	}
+	slog.Info("HTTP server timeouts",
+		"read", cfg.HTTP.ReadTimeout, "write", cfg.HTTP.WriteTimeout, "idle", cfg.HTTP.IdleTimeout)
 
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -238,10 +331,67 @@ func startHTTPServer(
 		}
 	}()
 
+	var grpcSrv *grpc.Server
+	if cfg.GRPCAddr != "" {
+		grpcSrv = startGRPCServer(cfg, keys, sessions, submissionSvc, requestSvc)
+	}
+
+	return srv, grpcSrv, handlers.Audit
+}
+
+func startGRPCServer(
+	cfg appconfig.Config,
+	keys *auth.Keys,
+	sessions *session.Service,
+	submissionSvc service.SubmissionService,
+	requestSvc service.RequestService,
+) *grpc.Server {
+	lis, err := net.Listen("tcp", cfg.GRPCAddr)
+	if err != nil {
+		slog.Error("failed to listen for gRPC", "addr", cfg.GRPCAddr, "err", err)
+		os.Exit(1)
+	}
+	srv := grpcapi.NewGRPCServer(&grpcapi.Server{Submission: submissionSvc, Requests: requestSvc, MaxImageBytes: cfg.Storage.MaxImageBytes}, keys, cfg.JWT.Issuer, sessions)
+
+	go func() {
+		if err := srv.Serve(lis); err != nil {
+			slog.Error("gRPC server error", "err", err)
+			os.Exit(1)
+		}
+	}()
+	slog.Info("gRPC server listening", "addr", cfg.GRPCAddr)
+
 	return srv
 }
 
-func waitForShutdown(srv *http.Server, cancel context.CancelFunc) {
+// startMetricsServer serves Prometheus metrics on cfg.MetricsAddr, or
+// returns nil when it is not set.
+func startMetricsServer(cfg appconfig.Config, db *database.DB, q job.Queue) *http.Server {
+	if cfg.MetricsAddr == "" {
+		return nil
+	}
+	reg := metrics.NewRegistry()
+	reg.MustRegister(metrics.NewPoolCollector(db.Pool()))
+	if wc, ok := q.(metrics.WorkerCounter); ok {
+		reg.MustRegister(metrics.NewQueueWorkersCollector(wc))
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler(reg))
+	srv := &http.Server{Addr: cfg.MetricsAddr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("metrics server error", "err", err)
+			os.Exit(1)
+		}
+	}()
+	slog.Info("metrics server listening", "addr", cfg.MetricsAddr)
+
+	return srv
+}
+
+func waitForShutdown(srv *http.Server, grpcSrv *grpc.Server, cancel context.CancelFunc) {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 
@@ -253,5 +403,23 @@ func waitForShutdown(srv *http.Server, cancel context.CancelFunc) {
 	if err := srv.Shutdown(shCtx); err != nil {
 		slog.Error("HTTP server shutdown error", "err", err)
 	}
+	if grpcSrv != nil {
+		stopGRPCServer(shCtx, grpcSrv)
+	}
 	cancel()
 }
+
+// stopGRPCServer drains in-flight RPCs and forces a stop once ctx expires.
+func stopGRPCServer(ctx context.Context, srv *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		slog.Warn("gRPC graceful stop timed out, forcing stop")
+		srv.Stop()
+	}
+}
@@ -10,6 +10,7 @@ import { Register } from '@/pages/Register';
 import { Landing } from '@/pages/Landing';
 import { ForgotPassword } from '@/pages/ForgotPassword';
 import { ResetPassword } from '@/pages/ResetPassword';
+import { VerifyEmail } from '@/pages/VerifyEmail';
 
 /**
  * Retry a dynamic import up to `retries` times, then force-reload the page
@@ -77,6 +78,7 @@ function App() {
           <Route path={ROUTES.REGISTER} element={<Register />} />
           <Route path={ROUTES.FORGOT_PASSWORD} element={<ForgotPassword />} />
           <Route path={ROUTES.RESET_PASSWORD} element={<ResetPassword />} />
+          <Route path={ROUTES.VERIFY_EMAIL} element={<VerifyEmail />} />
           <Route path={ROUTES.PRIVACY} element={<Privacy />} />
           <Route path={ROUTES.TERMS} element={<Terms />} />
           <Route path={ROUTES.CONTACTS} element={<Contacts />} />
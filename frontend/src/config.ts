@@ -16,6 +16,7 @@ export const ROUTES = {
   ACCOUNT: '/account',
   FORGOT_PASSWORD: '/forgot-password',
   RESET_PASSWORD: '/reset-password',
+  VERIFY_EMAIL: '/verify-email',
   PRIVACY: '/privacy',
   TERMS: '/terms',
 };
@@ -42,7 +42,7 @@ export function Results() {
       if (query.state.fetchStatus === 'paused' || query.state.status === 'error') return false;
       const data = query.state.data;
       if (!data) return false;
-      if (data.status === 'pending' || data.status === 'processing') return 2000;
+      if (data.status === 'pending' || data.status === 'queued' || data.status === 'processing') return 2000;
       if (data.response?.content) {
         try {
           const parsed = JSON.parse(data.response.content);
@@ -0,0 +1,124 @@
+import { useEffect, useRef, useState } from 'react';
+import { Link, useSearchParams } from 'react-router-dom';
+import { useMutation } from '@tanstack/react-query';
+import { authAPI } from '@/services/api';
+import { ROUTES } from '@/config';
+import { getApiError, ERR_RATE_LIMIT, ERR_NETWORK } from '@/utils/apiError';
+import { Layout } from '@/components/Layout';
+
+export function VerifyEmail() {
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get('token') || '';
+
+  const [email, setEmail] = useState('');
+  const [resendError, setResendError] = useState('');
+  const [sent, setSent] = useState(false);
+  const started = useRef(false);
+
+  const verify = useMutation({
+    mutationFn: () => authAPI.verifyEmail(token),
+  });
+
+  const resend = useMutation({
+    mutationFn: () => authAPI.resendVerification(email),
+    onSuccess: () => setSent(true),
+    onError: (err: unknown) => {
+      const { status, message } = getApiError(err);
+      if (status === 429) {
+        setResendError(ERR_RATE_LIMIT);
+      } else if (!status) {
+        setResendError(ERR_NETWORK);
+      } else {
+        setResendError(message || 'Ошибка отправки');
+      }
+    },
+  });
+
+  // The token is single-use, so verify it exactly once even under StrictMode.
+  useEffect(() => {
+    if (token && !started.current) {
+      started.current = true;
+      verify.mutate();
+    }
+  }, [token, verify]);
+
+  const handleResend = (e: React.FormEvent) => {
+    e.preventDefault();
+    setResendError('');
+    resend.reset();
+    resend.mutate();
+  };
+
+  let status: React.ReactNode;
+  if (token && (verify.isIdle || verify.isPending)) {
+    status = <p className="text-sm text-gray-600">Проверяем ссылку...</p>;
+  } else if (verify.isSuccess) {
+    status = (
+      <div className="bg-green-50 border border-green-200 text-green-800 px-4 py-3 rounded-xl text-sm">
+        Email подтверждён. Теперь можно войти.
+      </div>
+    );
+  } else if (verify.isError) {
+    const { status: code } = getApiError(verify.error);
+    status = (
+      <div className="bg-red-50 border border-red-200 text-red-800 px-4 py-3 rounded-xl text-sm">
+        {code ? 'Ссылка недействительна или истекла. Запросите новую' : ERR_NETWORK}
+      </div>
+    );
+  }
+
+  const showResend = !token || verify.isError;
+
+  return (
+    <Layout>
+      <div className="min-h-screen flex items-center justify-center bg-gradient-to-br from-rose-50 to-blue-50 py-12 px-4 sm:px-6 lg:px-8">
+        <div className="max-w-md w-full bg-white shadow-xl rounded-2xl p-8 space-y-6 animate-scale-in">
+          <h2 className="text-center text-3xl font-extrabold text-gray-900">Подтверждение email</h2>
+
+          {status}
+
+          {showResend && (sent ? (
+            <div className="bg-green-50 border border-green-200 text-green-800 px-4 py-3 rounded-xl text-sm">
+              Если адрес ожидает подтверждения, мы отправили на него новую ссылку.
+            </div>
+          ) : (
+            <form className="space-y-5" onSubmit={handleResend}>
+              {resendError && (
+                <div className="bg-red-50 border border-red-200 text-red-800 px-4 py-3 rounded-xl">
+                  {resendError}
+                </div>
+              )}
+              <div>
+                <label htmlFor="email" className="sr-only">Email</label>
+                <input
+                  id="email"
+                  name="email"
+                  type="email"
+                  required
+                  className="appearance-none relative block w-full px-4 py-3 border border-gray-300 placeholder-gray-400 text-gray-900 rounded-xl focus:outline-none focus:ring-2 focus:ring-rose-500 focus:border-rose-500 sm:text-sm"
+                  placeholder="Email адрес"
+                  value={email}
+                  onChange={(e) => setEmail(e.target.value)}
+                />
+              </div>
+              <button
+                type="submit"
+                disabled={resend.isPending}
+                className="group relative w-full flex justify-center py-3 px-4 border border-transparent text-sm font-medium rounded-xl text-white bg-rose-600 hover:bg-rose-700 active:scale-95 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-rose-500 disabled:opacity-50 transition-all duration-150"
+              >
+                {resend.isPending ? 'Отправка...' : 'Отправить ссылку ещё раз'}
+              </button>
+            </form>
+          ))}
+
+          <Link
+            to={ROUTES.LOGIN}
+            className="block w-full text-center py-3 px-4 border border-rose-300 text-sm font-medium rounded-xl text-rose-600 bg-white hover:bg-rose-50 active:scale-95 transition-all duration-150"
+          >
+            Перейти ко входу
+          </Link>
+        </div>
+      </div>
+    </Layout>
+  );
+}
@@ -152,6 +152,16 @@ export const authAPI = {
     const response = await api.post('/v1/auth/password-change', { old_password, new_password });
     return response.data;
   },
+
+  verifyEmail: async (token: string) => {
+    const response = await api.get('/v1/auth/verify', { params: { token } });
+    return response.data;
+  },
+
+  resendVerification: async (email: string) => {
+    const response = await api.post('/v1/auth/verify/resend', { email });
+    return response.data;
+  },
 };
 
 export interface UserProfile {
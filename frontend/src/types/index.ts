@@ -33,6 +33,7 @@ export interface ECGAnalysisRequest {
   mm_per_mv_limb?: number;
   mm_per_mv_chest?: number;
   client_meta?: ECGClientMeta;
+  tags?: Record<string, string>;
 }
 
 export interface ECGCalibrationParams {
@@ -86,6 +87,7 @@ export interface Request {
   created_at: string;
   updated_at: string;
   client_meta?: ECGClientMeta;
+  tags?: Record<string, string>;
   files?: File[];
   response?: Response;
   ecg_age?: number;
@@ -95,7 +97,7 @@ export interface Request {
   ecg_mm_per_mv_chest?: number;
 }
 
-export type RequestStatus = 'pending' | 'processing' | 'completed' | 'failed';
+export type RequestStatus = 'pending' | 'queued' | 'processing' | 'completed' | 'failed';
 
 export interface File {
   id: string;
@@ -115,6 +117,8 @@ export interface Response {
   model?: string;
   tokens_used?: number;
   processing_time_ms?: number;
+  finish_reason?: string;
+  is_refusal?: boolean;
   created_at: string;
 }
 
@@ -2,6 +2,8 @@ import { AxiosError } from 'axios';
 
 interface ApiError {
   status?: number;
+  /** Machine-readable error code from the backend, e.g. "not_found". */
+  code?: string;
   message: string;
 }
 
@@ -12,17 +14,28 @@ interface ApiError {
 export function getApiError(err: unknown): ApiError {
   if (err instanceof AxiosError) {
     let message = err.message;
+    let code: string | undefined;
 
-    // Type-safe extraction of error message from response
+    // Type-safe extraction of the {"error": {"code", "message"}} body.
+    // A plain string "error" is still accepted from older backends.
     if (err.response?.data && typeof err.response.data === 'object') {
       const data = err.response.data as Record<string, unknown>;
       if (typeof data.error === 'string') {
         message = data.error;
+      } else if (data.error && typeof data.error === 'object') {
+        const body = data.error as Record<string, unknown>;
+        if (typeof body.message === 'string') {
+          message = body.message;
+        }
+        if (typeof body.code === 'string') {
+          code = body.code;
+        }
       }
     }
 
     return {
       status: err.response?.status,
+      code,
       message,
     };
   }
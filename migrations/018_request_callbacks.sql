@@ -0,0 +1,6 @@
+-- Optional completion webhook per request and its delivery outcome.
+ALTER TABLE requests
+    ADD COLUMN IF NOT EXISTS callback_url TEXT,
+    ADD COLUMN IF NOT EXISTS callback_status TEXT,
+    ADD COLUMN IF NOT EXISTS callback_attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS callback_error TEXT;
@@ -0,0 +1,7 @@
+-- Soft deletion of requests. Deleted rows are hidden from user-facing reads;
+-- their stored files are removed when the request is deleted.
+ALTER TABLE requests ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_requests_user_live
+    ON requests(user_id, created_at DESC)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- SHA-256 of each stored file, recorded at upload. Empty for files uploaded
+-- before checksums were introduced.
+ALTER TABLE files ADD COLUMN IF NOT EXISTS checksum TEXT NOT NULL DEFAULT '';
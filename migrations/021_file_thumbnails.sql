@@ -0,0 +1,3 @@
+-- Storage key of a downscaled preview generated for image uploads. Empty when
+-- no thumbnail exists (non-image file or generation failed).
+ALTER TABLE files ADD COLUMN IF NOT EXISTS thumbnail_key TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- X-Request-ID of the HTTP call that created the request, for matching a
+-- request row to API and worker logs.
+ALTER TABLE requests ADD COLUMN IF NOT EXISTS correlation_id TEXT;
@@ -0,0 +1,12 @@
+-- A batch groups the EKG requests submitted together through /v1/ecg/batch.
+CREATE TABLE IF NOT EXISTS ecg_batches (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ecg_batches_user_id ON ecg_batches(user_id);
+
+ALTER TABLE requests ADD COLUMN IF NOT EXISTS batch_id UUID REFERENCES ecg_batches(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_requests_batch_id ON requests(batch_id) WHERE batch_id IS NOT NULL;
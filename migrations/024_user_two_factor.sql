@@ -0,0 +1,4 @@
+-- Optional TOTP two-factor authentication. The secret is AES-GCM encrypted
+-- by the API; it is set at enrollment and only enforced once enabled.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_secret TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_enabled BOOLEAN NOT NULL DEFAULT FALSE;
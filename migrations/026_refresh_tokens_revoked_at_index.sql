@@ -0,0 +1,3 @@
+-- Lets the refresh token cleanup find revoked tokens past retention without
+-- scanning the table.
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_revoked_at ON refresh_tokens (revoked_at) WHERE revoked_at IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Key version used to encrypt requests.text_query and responses.content.
+-- NULL means the value is stored as plaintext.
+ALTER TABLE requests ADD COLUMN IF NOT EXISTS text_query_key_version SMALLINT;
+ALTER TABLE responses ADD COLUMN IF NOT EXISTS content_key_version SMALLINT;
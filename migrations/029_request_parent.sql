@@ -0,0 +1,27 @@
+-- First-class link from a GPT interpretation to the EKG request it was
+-- created for. It used to exist only as gpt_request_id inside the EKG
+-- response JSON.
+ALTER TABLE requests
+    ADD COLUMN IF NOT EXISTS parent_request_id UUID REFERENCES requests(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_requests_parent_request_id
+    ON requests (parent_request_id)
+    WHERE parent_request_id IS NOT NULL;
+
+-- Backfill from plaintext EKG responses; encrypted ones keep relying on the
+-- JSON field, which the application still reads. The cast sits inside CASE so
+-- content that is not a JSON object is skipped rather than aborting the
+-- migration: Postgres may evaluate plain AND conditions in any order.
+UPDATE requests child
+SET parent_request_id = src.parent_id
+FROM (
+    SELECT resp.request_id AS parent_id,
+           CASE WHEN resp.content IS JSON OBJECT
+                THEN resp.content::jsonb ->> 'gpt_request_id'
+           END AS child_id
+    FROM responses resp
+    WHERE resp.content_key_version IS NULL
+) src
+WHERE child.id::text = src.child_id
+  AND child.id <> src.parent_id
+  AND child.parent_request_id IS NULL;
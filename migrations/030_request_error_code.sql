@@ -0,0 +1,5 @@
+-- Category of a failed request (rate_limited, content_filtered, auth,
+-- timeout, invalid_image, internal), reported to clients as error_code.
+-- NULL unless status is 'failed'; older failures stay NULL.
+ALTER TABLE requests
+    ADD COLUMN IF NOT EXISTS error_code VARCHAR(32);
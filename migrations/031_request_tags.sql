@@ -0,0 +1,7 @@
+-- Client-defined labels such as a patient pseudonym or department, filtered
+-- with containment (tags @> '{"department": "cardiology"}').
+ALTER TABLE requests
+    ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+CREATE INDEX IF NOT EXISTS idx_requests_tags
+    ON requests USING GIN (tags jsonb_path_ops);
@@ -0,0 +1,5 @@
+-- Why the model stopped generating, and whether it refused the request, so
+-- short or empty answers can be explained without scraping the content.
+ALTER TABLE responses
+    ADD COLUMN IF NOT EXISTS finish_reason TEXT,
+    ADD COLUMN IF NOT EXISTS is_refusal BOOLEAN NOT NULL DEFAULT FALSE;